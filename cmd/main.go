@@ -2,11 +2,17 @@ package main
 
 import (
 	"fmt"
+	"github.com/lazylex/watch-store/secure/internal/adapters/grants"
+	grpcServer "github.com/lazylex/watch-store/secure/internal/adapters/grpc/server"
 	"github.com/lazylex/watch-store/secure/internal/adapters/http/server"
 	"github.com/lazylex/watch-store/secure/internal/adapters/message_broker/kafka"
+	"github.com/lazylex/watch-store/secure/internal/adapters/message_broker/kafka/producer/rbac_changes"
+	"github.com/lazylex/watch-store/secure/internal/adapters/purge"
+	"github.com/lazylex/watch-store/secure/internal/adapters/reconcile"
 	"github.com/lazylex/watch-store/secure/internal/config"
 	"github.com/lazylex/watch-store/secure/internal/logger"
 	prometheusMetrics "github.com/lazylex/watch-store/secure/internal/metrics"
+	"github.com/lazylex/watch-store/secure/internal/ports/message_broker"
 	"github.com/lazylex/watch-store/secure/internal/repository/in_memory/redis"
 	"github.com/lazylex/watch-store/secure/internal/repository/joint"
 	"github.com/lazylex/watch-store/secure/internal/repository/persistent/postgresql"
@@ -29,9 +35,33 @@ func main() {
 	repo := joint.MustCreate(inMemoryRepo, persistentRepo)
 	domainService := service.MustCreate(metrics.Service, &repo, cfg.Secure)
 
+	var reconciler *reconcile.Reconciler
+	if len(cfg.Reconcile.RBACFile) > 0 {
+		var events message_broker.RBACChangesInterface
+		if cfg.UseKafka && len(cfg.Kafka.RBACChangesTopic) > 0 {
+			producer := rbac_changes.Create(&cfg.Kafka)
+			defer producer.Close()
+			events = producer
+		}
+		reconciler = reconcile.MustCreate(domainService, &cfg.Reconcile, events)
+		reconciler.MustRun()
+	}
+
+	cleaner := grants.MustCreate(domainService, &cfg.Grants)
+	cleaner.MustRun()
+
+	purger := purge.MustCreate(domainService, &cfg.Purge)
+	purger.MustRun()
+
 	httpServer := server.MustCreate(domainService, &cfg.HttpServer, metrics)
 	httpServer.MustRun()
 
+	var rpcServer *grpcServer.Server
+	if len(cfg.GrpcServer.GrpcAddress) > 0 {
+		rpcServer = grpcServer.MustCreate(domainService, &cfg.GrpcServer, metrics)
+		rpcServer.MustRun()
+	}
+
 	if cfg.UseKafka {
 		kafka.MustRun(&cfg.Kafka)
 	}
@@ -44,7 +74,15 @@ func main() {
 	fmt.Println() // так красивее, если вывод логов производится в стандартный терминал
 	slog.Info(fmt.Sprintf("%s signal received. Shutdown started", sig))
 
+	if reconciler != nil {
+		reconciler.Stop()
+	}
+	cleaner.Stop()
+	purger.Stop()
 	httpServer.Shutdown()
+	if rpcServer != nil {
+		rpcServer.Shutdown()
+	}
 	persistentRepo.Close()
 }
 
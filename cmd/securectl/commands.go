@@ -0,0 +1,971 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/lazylex/watch-store/secure/internal/domain/value_objects/account_type"
+	"github.com/lazylex/watch-store/secure/internal/domain/value_objects/login"
+	"github.com/lazylex/watch-store/secure/internal/domain/value_objects/password"
+	"github.com/lazylex/watch-store/secure/internal/dto"
+	"github.com/lazylex/watch-store/secure/internal/helpers/rbac_document"
+	"github.com/lazylex/watch-store/secure/internal/service"
+	"os"
+	"strings"
+	"time"
+)
+
+// instanceSecretLength длина секрета, создаваемого для экземпляра сервиса.
+const instanceSecretLength = 64
+
+// adminAPI операции, доступные как напрямую через хранилища, так и через HTTP-api администратора.
+type adminAPI interface {
+	ExportRBAC(context.Context) (dto.RBACDocument, error)
+	ImportRBAC(context.Context, *dto.RBACDocument, bool) (dto.RBACImportResult, error)
+	ResetPassword(context.Context, login.Login) (string, error)
+}
+
+// command команда утилиты.
+type command struct {
+	group   string                                                           // Группа команд, например account
+	action  string                                                           // Название команды в группе, например create
+	summary string                                                           // Краткое описание
+	direct  bool                                                             // Требуется прямой доступ к хранилищам
+	run     func(ctx context.Context, env *environment, args []string) error // Выполнение команды с её аргументами
+}
+
+// name возвращает полное название команды.
+func (c *command) name() string {
+	return c.group + " " + c.action
+}
+
+// list значение флага, который можно указать несколько раз.
+type list []string
+
+// String возвращает значения флага через запятую.
+func (l *list) String() string {
+	return strings.Join(*l, ",")
+}
+
+// Set добавляет очередное значение флага.
+func (l *list) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// commands все команды утилиты.
+var commands = []command{
+	{"account", "create", "создать учетную запись с ролями, группами и разрешениями экземпляров", true, accountCreate},
+	{"account", "set-metadata", "заменить тип, отображаемое имя, контакт и команду-владельца учетной записи", true, accountSetMetadata},
+	{"account", "assign-role", "назначить роль учетной записи", true, accountAssignRole},
+	{"account", "assign-group", "добавить учетную запись в группу", true, accountAssignGroup},
+	{"account", "assign-permission", "назначить учетной записи разрешение экземпляра", true, accountAssignPermission},
+	{"account", "assign-instance-role", "назначить учетной записи роль, действующую только в экземпляре", true, accountAssignInstanceRole},
+	{"account", "assign-instance-group", "добавить учетную запись в группу только в экземпляре", true, accountAssignInstanceGroup},
+	{"account", "assign-selector-permission", "назначить учетной записи разрешение во всех экземплярах с атрибутами по селектору", true, accountAssignSelectorPermission},
+	{"account", "assign-global-group", "добавить учетную запись в глобальную группу", true, accountAssignGlobalGroup},
+	{"account", "reset-password", "выдать токен сброса пароля", false, accountResetPassword},
+	{"account", "delete", "пометить учетную запись удаленной", true, accountDelete},
+	{"account", "restore", "восстановить удаленную учетную запись с её назначениями", true, accountRestore},
+	{"service", "register", "зарегистрировать сервис или изменить его описание", true, serviceRegister},
+	{"service", "set-encoding", "выбрать кодирование номеров разрешений в токенах (list или bitmap)", true, serviceSetEncoding},
+	{"service", "delete", "пометить удаленными сервис и все его экземпляры, разрешения, роли и группы", true, serviceDelete},
+	{"service", "restore", "восстановить удаленный сервис с удаленными вместе с ним сущностями", true, serviceRestore},
+	{"instance", "register", "зарегистрировать экземпляр сервиса", true, instanceRegister},
+	{"instance", "rotate-secret", "заменить секрет экземпляра новым случайным", true, instanceRotateSecret},
+	{"instance", "set-attribute", "установить атрибут экземпляра, используемый в условиях назначений", true, instanceSetAttribute},
+	{"instance", "delete-attribute", "удалить атрибут экземпляра", true, instanceDeleteAttribute},
+	{"instance", "delete", "пометить экземпляр удаленным", true, instanceDelete},
+	{"instance", "restore", "восстановить удаленный экземпляр с его назначениями", true, instanceRestore},
+	{"permission", "create", "создать разрешение сервиса", true, permissionCreate},
+	{"permission", "delete", "пометить разрешение сервиса удаленным (номер выводится из употребления при окончательном удалении)", true, permissionDelete},
+	{"permission", "restore", "восстановить удаленное разрешение сервиса с его назначениями", true, permissionRestore},
+	{"permission", "deprecate", "пометить разрешение сервиса устаревшим", true, permissionDeprecate},
+	{"role", "create", "создать роль сервиса", true, roleCreate},
+	{"role", "delete", "пометить роль сервиса удаленной", true, roleDelete},
+	{"role", "restore", "восстановить удаленную роль сервиса с её назначениями", true, roleRestore},
+	{"role", "assign-permission", "назначить разрешение роли", true, roleAssignPermission},
+	{"role", "assign-parent", "назначить роли родительскую роль, разрешения которой она наследует", true, roleAssignParent},
+	{"group", "create", "создать группу сервиса", true, groupCreate},
+	{"group", "delete", "пометить группу сервиса удаленной", true, groupDelete},
+	{"group", "restore", "восстановить удаленную группу сервиса с её назначениями", true, groupRestore},
+	{"group", "assign-role", "назначить роль группе", true, groupAssignRole},
+	{"group", "assign-permission", "назначить разрешение группе", true, groupAssignPermission},
+	{"group", "assign-subgroup", "вложить в группу другую группу, учетные записи которой получат её роли и разрешения", true, groupAssignSubgroup},
+	{"global-group", "create", "создать глобальную группу, объединяющую роли разных сервисов", true, globalGroupCreate},
+	{"global-group", "delete", "удалить глобальную группу", true, globalGroupDelete},
+	{"global-group", "assign-role", "назначить глобальной группе роль сервиса", true, globalGroupAssignRole},
+	{"rbac", "export", "выгрузить конфигурацию управления доступом", false, rbacExport},
+	{"rbac", "import", "загрузить конфигурацию управления доступом", false, rbacImport},
+	{"db", "migrate", "создать отсутствующие схему и таблицы", true, dbMigrate},
+	{"db", "purge", "безвозвратно удалить сущности, помеченные удаленными раньше срока хранения", true, dbPurge},
+	{"cache", "warm", "загрузить кешируемые данные в Redis", true, cacheWarm},
+}
+
+// findCommand возвращает команду по группе и названию.
+func findCommand(group, action string) (command, bool) {
+	for _, cmd := range commands {
+		if cmd.group == group && cmd.action == action {
+			return cmd, true
+		}
+	}
+
+	return command{}, false
+}
+
+// parse разбирает флаги команды name, объявленные функцией define, и проверяет, что флаги required заданы.
+func parse(name string, args []string, define func(*flag.FlagSet), required ...string) error {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	define(fs)
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	for _, flagName := range required {
+		if f := fs.Lookup(flagName); f == nil || len(f.Value.String()) == 0 {
+			return fmt.Errorf("flag -%s is required", flagName)
+		}
+	}
+
+	return nil
+}
+
+// split разделяет значение вида "первое/второе".
+func split(value string) (string, string, error) {
+	first, second, ok := strings.Cut(value, "/")
+	if !ok || len(first) == 0 || len(second) == 0 {
+		return "", "", fmt.Errorf("value %q must have form first/second", value)
+	}
+
+	return first, second, nil
+}
+
+// nameService разбирает значения вида "сервис/название".
+func nameService(values []string) ([]dto.NameService, error) {
+	result := make([]dto.NameService, 0, len(values))
+	for _, value := range values {
+		service, name, err := split(value)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, dto.NameService{Name: name, Service: service})
+	}
+
+	return result, nil
+}
+
+// defineValidity объявляет флаги срока действия назначения в формате RFC 3339.
+func defineValidity(fs *flag.FlagSet, from, until **time.Time) {
+	fs.Func("valid-from", "начало срока действия назначения в формате RFC 3339", timeFlag(from))
+	fs.Func("valid-until", "окончание срока действия назначения в формате RFC 3339", timeFlag(until))
+}
+
+// defineCondition объявляет флаг условия назначения, вычисляемого при выдаче токена.
+func defineCondition(fs *flag.FlagSet, condition *string) {
+	fs.StringVar(condition, "condition", "", `условие назначения, например time_between("09:00", "21:00")`)
+}
+
+// defineMetadata объявляет флаги type, display-name, contact и owner-team метаданных учетной записи.
+func defineMetadata(fs *flag.FlagSet, data *dto.UserIdAccountMetadata) {
+	fs.StringVar(&data.Type, "type", "", "тип учетной записи: human или service")
+	fs.StringVar(&data.DisplayName, "display-name", "", "отображаемое имя")
+	fs.StringVar(&data.Contact, "contact", "", "контакт, например адрес электронной почты")
+	fs.StringVar(&data.OwnerTeam, "owner-team", "", "команда-владелец")
+}
+
+// timeFlag возвращает функцию разбора значения флага со временем в формате RFC 3339.
+func timeFlag(target **time.Time) func(string) error {
+	return func(value string) error {
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return err
+		}
+		*target = &t
+
+		return nil
+	}
+}
+
+// printJSON выводит данные в JSON.
+func printJSON(data any) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(data)
+}
+
+// randomSecret возвращает случайный секрет экземпляра сервиса.
+func randomSecret() (string, error) {
+	buf := make([]byte, instanceSecretLength/2)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(buf), nil
+}
+
+func accountCreate(ctx context.Context, env *environment, args []string) error {
+	var (
+		accountLogin, accountPassword string
+		roles, groups, permissions    list
+		metadata                      dto.UserIdAccountMetadata
+	)
+
+	if err := parse("account create", args, func(fs *flag.FlagSet) {
+		fs.StringVar(&accountLogin, "login", "", "логин")
+		fs.StringVar(&accountPassword, "password", os.Getenv("SECURECTL_PASSWORD"), "пароль (по умолчанию SECURECTL_PASSWORD)")
+		fs.Var(&roles, "role", "роль в виде сервис/роль, можно указать несколько раз")
+		fs.Var(&groups, "group", "группа в виде сервис/группа, можно указать несколько раз")
+		fs.Var(&permissions, "permission", "разрешение в виде экземпляр/разрешение, можно указать несколько раз")
+		defineMetadata(fs, &metadata)
+	}, "login", "password"); err != nil {
+		return err
+	}
+
+	if len(metadata.Type) == 0 {
+		metadata.Type = account_type.Human
+	}
+
+	var (
+		err     error
+		options = service.AccountOptions{Metadata: &metadata}
+	)
+
+	if options.Roles, err = nameService(roles); err != nil {
+		return err
+	}
+	if options.Groups, err = nameService(groups); err != nil {
+		return err
+	}
+	for _, value := range permissions {
+		instance, permission, err := split(value)
+		if err != nil {
+			return err
+		}
+		options.InstancePermissions = append(options.InstancePermissions,
+			dto.InstancePermission{Instance: instance, Permission: permission})
+	}
+
+	id, err := env.service.CreateAccount(ctx,
+		&dto.LoginPassword{Login: login.Login(accountLogin), Password: password.Password(accountPassword)}, options)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(id)
+
+	return nil
+}
+
+func accountSetMetadata(ctx context.Context, env *environment, args []string) error {
+	var data dto.UserIdAccountMetadata
+	var userId string
+
+	if err := parse("account set-metadata", args, func(fs *flag.FlagSet) {
+		fs.StringVar(&userId, "user-id", "", "идентификатор учетной записи")
+		defineMetadata(fs, &data)
+	}, "user-id", "type"); err != nil {
+		return err
+	}
+
+	var err error
+	if data.UserId, err = uuid.Parse(userId); err != nil {
+		return err
+	}
+
+	return env.service.SetAccountMetadata(ctx, &data)
+}
+
+func accountDelete(ctx context.Context, env *environment, args []string) error {
+	id, err := parseUserId("account delete", args)
+	if err != nil {
+		return err
+	}
+
+	return env.service.DeleteAccount(ctx, uuid.Nil, id)
+}
+
+func accountRestore(ctx context.Context, env *environment, args []string) error {
+	id, err := parseUserId("account restore", args)
+	if err != nil {
+		return err
+	}
+
+	return env.service.RestoreAccount(ctx, id)
+}
+
+// parseUserId разбирает обязательный флаг user-id команды name.
+func parseUserId(name string, args []string) (uuid.UUID, error) {
+	var userId string
+
+	if err := parse(name, args, func(fs *flag.FlagSet) {
+		fs.StringVar(&userId, "user-id", "", "идентификатор учетной записи")
+	}, "user-id"); err != nil {
+		return uuid.UUID{}, err
+	}
+
+	return uuid.Parse(userId)
+}
+
+func accountAssignRole(ctx context.Context, env *environment, args []string) error {
+	var data dto.UserIdRoleService
+	var userId string
+
+	if err := parse("account assign-role", args, func(fs *flag.FlagSet) {
+		fs.StringVar(&userId, "user-id", "", "идентификатор учетной записи")
+		fs.StringVar(&data.Service, "service", "", "сервис")
+		fs.StringVar(&data.Role, "role", "", "роль")
+		defineValidity(fs, &data.ValidFrom, &data.ValidUntil)
+		defineCondition(fs, &data.Condition)
+	}, "user-id", "service", "role"); err != nil {
+		return err
+	}
+
+	var err error
+	if data.UserId, err = uuid.Parse(userId); err != nil {
+		return err
+	}
+
+	return env.service.AssignRoleToAccount(ctx, &data)
+}
+
+func accountAssignGroup(ctx context.Context, env *environment, args []string) error {
+	var data dto.UserIdGroupService
+	var userId string
+
+	if err := parse("account assign-group", args, func(fs *flag.FlagSet) {
+		fs.StringVar(&userId, "user-id", "", "идентификатор учетной записи")
+		fs.StringVar(&data.Service, "service", "", "сервис")
+		fs.StringVar(&data.Group, "group", "", "группа")
+		defineValidity(fs, &data.ValidFrom, &data.ValidUntil)
+		defineCondition(fs, &data.Condition)
+	}, "user-id", "service", "group"); err != nil {
+		return err
+	}
+
+	var err error
+	if data.UserId, err = uuid.Parse(userId); err != nil {
+		return err
+	}
+
+	return env.service.AssignGroupToAccount(ctx, &data)
+}
+
+func accountAssignPermission(ctx context.Context, env *environment, args []string) error {
+	var data dto.UserIdInstancePermission
+	var userId string
+
+	if err := parse("account assign-permission", args, func(fs *flag.FlagSet) {
+		fs.StringVar(&userId, "user-id", "", "идентификатор учетной записи")
+		fs.StringVar(&data.Instance, "instance", "", "экземпляр")
+		fs.StringVar(&data.Permission, "permission", "", "разрешение")
+		defineValidity(fs, &data.ValidFrom, &data.ValidUntil)
+		defineCondition(fs, &data.Condition)
+	}, "user-id", "instance", "permission"); err != nil {
+		return err
+	}
+
+	var err error
+	if data.UserId, err = uuid.Parse(userId); err != nil {
+		return err
+	}
+
+	return env.service.AssignInstancePermissionToAccount(ctx, &data)
+}
+
+func accountAssignInstanceRole(ctx context.Context, env *environment, args []string) error {
+	var data dto.UserIdInstanceRole
+	var userId string
+
+	if err := parse("account assign-instance-role", args, func(fs *flag.FlagSet) {
+		fs.StringVar(&userId, "user-id", "", "идентификатор учетной записи")
+		fs.StringVar(&data.Instance, "instance", "", "экземпляр")
+		fs.StringVar(&data.Role, "role", "", "роль")
+		defineValidity(fs, &data.ValidFrom, &data.ValidUntil)
+		defineCondition(fs, &data.Condition)
+	}, "user-id", "instance", "role"); err != nil {
+		return err
+	}
+
+	var err error
+	if data.UserId, err = uuid.Parse(userId); err != nil {
+		return err
+	}
+
+	return env.service.AssignInstanceRoleToAccount(ctx, &data)
+}
+
+func accountAssignInstanceGroup(ctx context.Context, env *environment, args []string) error {
+	var data dto.UserIdInstanceGroup
+	var userId string
+
+	if err := parse("account assign-instance-group", args, func(fs *flag.FlagSet) {
+		fs.StringVar(&userId, "user-id", "", "идентификатор учетной записи")
+		fs.StringVar(&data.Instance, "instance", "", "экземпляр")
+		fs.StringVar(&data.Group, "group", "", "группа")
+		defineValidity(fs, &data.ValidFrom, &data.ValidUntil)
+		defineCondition(fs, &data.Condition)
+	}, "user-id", "instance", "group"); err != nil {
+		return err
+	}
+
+	var err error
+	if data.UserId, err = uuid.Parse(userId); err != nil {
+		return err
+	}
+
+	return env.service.AssignInstanceGroupToAccount(ctx, &data)
+}
+
+func accountAssignSelectorPermission(ctx context.Context, env *environment, args []string) error {
+	var data dto.UserIdSelectorPermission
+	var userId string
+
+	if err := parse("account assign-selector-permission", args, func(fs *flag.FlagSet) {
+		fs.StringVar(&userId, "user-id", "", "идентификатор учетной записи")
+		fs.StringVar(&data.Service, "service", "", "сервис")
+		fs.StringVar(&data.Permission, "permission", "", "разрешение")
+		fs.StringVar(&data.Selector, "selector", "", "селектор атрибутов экземпляров, например city=Донецк,tier=gold")
+	}, "user-id", "service", "permission", "selector"); err != nil {
+		return err
+	}
+
+	var err error
+	if data.UserId, err = uuid.Parse(userId); err != nil {
+		return err
+	}
+
+	return env.service.AssignSelectorPermissionToAccount(ctx, &data)
+}
+
+func accountAssignGlobalGroup(ctx context.Context, env *environment, args []string) error {
+	var data dto.UserIdGlobalGroup
+	var userId string
+
+	if err := parse("account assign-global-group", args, func(fs *flag.FlagSet) {
+		fs.StringVar(&userId, "user-id", "", "идентификатор учетной записи")
+		fs.StringVar(&data.GlobalGroup, "global-group", "", "глобальная группа")
+		defineValidity(fs, &data.ValidFrom, &data.ValidUntil)
+		defineCondition(fs, &data.Condition)
+	}, "user-id", "global-group"); err != nil {
+		return err
+	}
+
+	var err error
+	if data.UserId, err = uuid.Parse(userId); err != nil {
+		return err
+	}
+
+	return env.service.AssignGlobalGroupToAccount(ctx, &data)
+}
+
+func accountResetPassword(ctx context.Context, env *environment, args []string) error {
+	var accountLogin string
+
+	if err := parse("account reset-password", args, func(fs *flag.FlagSet) {
+		fs.StringVar(&accountLogin, "login", "", "логин")
+	}, "login"); err != nil {
+		return err
+	}
+
+	token, err := env.admin.ResetPassword(ctx, login.Login(accountLogin))
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(token)
+
+	return nil
+}
+
+func serviceRegister(ctx context.Context, env *environment, args []string) error {
+	var data dto.NameDescription
+
+	if err := parse("service register", args, func(fs *flag.FlagSet) {
+		fs.StringVar(&data.Name, "name", "", "сервис")
+		fs.StringVar(&data.Description, "description", "", "описание")
+	}, "name"); err != nil {
+		return err
+	}
+
+	return env.service.RegisterService(ctx, &data)
+}
+
+func serviceSetEncoding(ctx context.Context, env *environment, args []string) error {
+	var data dto.NameEncoding
+
+	if err := parse("service set-encoding", args, func(fs *flag.FlagSet) {
+		fs.StringVar(&data.Name, "name", "", "сервис")
+		fs.StringVar(&data.Encoding, "encoding", "", "кодирование номеров разрешений: list или bitmap")
+	}, "name", "encoding"); err != nil {
+		return err
+	}
+
+	return env.service.SetServicePermissionEncoding(ctx, &data)
+}
+
+func serviceDelete(ctx context.Context, env *environment, args []string) error {
+	name, err := parseName("service delete", args, "сервис")
+	if err != nil {
+		return err
+	}
+
+	return env.service.DeleteService(ctx, name)
+}
+
+func serviceRestore(ctx context.Context, env *environment, args []string) error {
+	name, err := parseName("service restore", args, "сервис")
+	if err != nil {
+		return err
+	}
+
+	return env.service.RestoreService(ctx, name)
+}
+
+// parseName разбирает обязательный флаг name команды name с описанием usage.
+func parseName(command string, args []string, usage string) (string, error) {
+	var name string
+
+	if err := parse(command, args, func(fs *flag.FlagSet) {
+		fs.StringVar(&name, "name", "", usage)
+	}, "name"); err != nil {
+		return "", err
+	}
+
+	return name, nil
+}
+
+func instanceRegister(ctx context.Context, env *environment, args []string) error {
+	var data dto.NameServiceSecret
+
+	if err := parse("instance register", args, func(fs *flag.FlagSet) {
+		fs.StringVar(&data.Name, "name", "", "экземпляр")
+		fs.StringVar(&data.Service, "service", "", "сервис")
+		fs.StringVar(&data.Secret, "secret", "", "секрет для подписи токенов (если не задан, создаётся случайный и выводится)")
+	}, "name", "service"); err != nil {
+		return err
+	}
+
+	generated := len(data.Secret) == 0
+	if generated {
+		var err error
+		if data.Secret, err = randomSecret(); err != nil {
+			return err
+		}
+	}
+
+	if err := env.service.RegisterInstance(ctx, &data); err != nil {
+		return err
+	}
+
+	if generated {
+		fmt.Println(data.Secret)
+	}
+
+	return nil
+}
+
+func instanceRotateSecret(ctx context.Context, env *environment, args []string) error {
+	var name string
+
+	if err := parse("instance rotate-secret", args, func(fs *flag.FlagSet) {
+		fs.StringVar(&name, "name", "", "экземпляр")
+	}, "name"); err != nil {
+		return err
+	}
+
+	instance, err := env.service.InstanceDetails(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	data := dto.NameServiceSecret{Name: instance.Name, Service: instance.Service}
+	if data.Secret, err = randomSecret(); err != nil {
+		return err
+	}
+
+	if err = env.service.RegisterInstance(ctx, &data); err != nil {
+		return err
+	}
+
+	fmt.Println(data.Secret)
+
+	return nil
+}
+
+func instanceSetAttribute(ctx context.Context, env *environment, args []string) error {
+	var data dto.InstanceNameValue
+
+	if err := parse("instance set-attribute", args, func(fs *flag.FlagSet) {
+		fs.StringVar(&data.Instance, "instance", "", "экземпляр")
+		fs.StringVar(&data.Name, "name", "", "название атрибута")
+		fs.StringVar(&data.Value, "value", "", "значение атрибута")
+	}, "instance", "name"); err != nil {
+		return err
+	}
+
+	return env.service.SetInstanceAttribute(ctx, &data)
+}
+
+func instanceDeleteAttribute(ctx context.Context, env *environment, args []string) error {
+	var data dto.NameInstance
+
+	if err := parse("instance delete-attribute", args, func(fs *flag.FlagSet) {
+		fs.StringVar(&data.Instance, "instance", "", "экземпляр")
+		fs.StringVar(&data.Name, "name", "", "название атрибута")
+	}, "instance", "name"); err != nil {
+		return err
+	}
+
+	return env.service.DeleteInstanceAttribute(ctx, &data)
+}
+
+func instanceDelete(ctx context.Context, env *environment, args []string) error {
+	name, err := parseName("instance delete", args, "экземпляр")
+	if err != nil {
+		return err
+	}
+
+	return env.service.DeleteInstance(ctx, name)
+}
+
+func instanceRestore(ctx context.Context, env *environment, args []string) error {
+	name, err := parseName("instance restore", args, "экземпляр")
+	if err != nil {
+		return err
+	}
+
+	return env.service.RestoreInstance(ctx, name)
+}
+
+// defineEntity объявляет флаги service, name и description сущности сервиса.
+func defineEntity(data *dto.NameServiceDescription) func(*flag.FlagSet) {
+	return func(fs *flag.FlagSet) {
+		fs.StringVar(&data.Service, "service", "", "сервис")
+		fs.StringVar(&data.Name, "name", "", "название")
+		fs.StringVar(&data.Description, "description", "", "описание")
+	}
+}
+
+// defineName объявляет флаги service и name сущности сервиса.
+func defineName(data *dto.NameService) func(*flag.FlagSet) {
+	return func(fs *flag.FlagSet) {
+		fs.StringVar(&data.Service, "service", "", "сервис")
+		fs.StringVar(&data.Name, "name", "", "название")
+	}
+}
+
+func permissionCreate(ctx context.Context, env *environment, args []string) error {
+	var data dto.NameNumberDescriptionService
+
+	if err := parse("permission create", args, func(fs *flag.FlagSet) {
+		fs.StringVar(&data.Service, "service", "", "сервис")
+		fs.StringVar(&data.Name, "name", "", "название")
+		fs.StringVar(&data.Description, "description", "", "описание")
+		fs.IntVar(&data.Number, "number", 0, "номер разрешения (по умолчанию следующий свободный)")
+	}, "service", "name"); err != nil {
+		return err
+	}
+
+	return env.service.CreatePermission(ctx, &data)
+}
+
+func permissionDelete(ctx context.Context, env *environment, args []string) error {
+	var data dto.NameService
+	if err := parse("permission delete", args, defineName(&data), "service", "name"); err != nil {
+		return err
+	}
+
+	return env.service.DeletePermission(ctx, &data)
+}
+
+func permissionRestore(ctx context.Context, env *environment, args []string) error {
+	var data dto.NameService
+	if err := parse("permission restore", args, defineName(&data), "service", "name"); err != nil {
+		return err
+	}
+
+	return env.service.RestorePermission(ctx, &data)
+}
+
+func permissionDeprecate(ctx context.Context, env *environment, args []string) error {
+	var data dto.NameService
+	if err := parse("permission deprecate", args, defineName(&data), "service", "name"); err != nil {
+		return err
+	}
+
+	return env.service.DeprecatePermission(ctx, &data)
+}
+
+func roleCreate(ctx context.Context, env *environment, args []string) error {
+	var data dto.NameServiceDescription
+	if err := parse("role create", args, defineEntity(&data), "service", "name"); err != nil {
+		return err
+	}
+
+	return env.service.CreateRole(ctx, &data)
+}
+
+func roleDelete(ctx context.Context, env *environment, args []string) error {
+	var data dto.NameService
+	if err := parse("role delete", args, defineName(&data), "service", "name"); err != nil {
+		return err
+	}
+
+	return env.service.DeleteRole(ctx, &data)
+}
+
+func roleRestore(ctx context.Context, env *environment, args []string) error {
+	var data dto.NameService
+	if err := parse("role restore", args, defineName(&data), "service", "name"); err != nil {
+		return err
+	}
+
+	return env.service.RestoreRole(ctx, &data)
+}
+
+func roleAssignPermission(ctx context.Context, env *environment, args []string) error {
+	var data dto.PermissionRoleService
+
+	if err := parse("role assign-permission", args, func(fs *flag.FlagSet) {
+		fs.StringVar(&data.Service, "service", "", "сервис")
+		fs.StringVar(&data.Role, "role", "", "роль")
+		fs.StringVar(&data.Permission, "permission", "", "разрешение")
+	}, "service", "role", "permission"); err != nil {
+		return err
+	}
+
+	return env.service.AssignPermissionToRole(ctx, &data)
+}
+
+func roleAssignParent(ctx context.Context, env *environment, args []string) error {
+	var data dto.RoleParentService
+
+	if err := parse("role assign-parent", args, func(fs *flag.FlagSet) {
+		fs.StringVar(&data.Service, "service", "", "сервис")
+		fs.StringVar(&data.Role, "role", "", "роль")
+		fs.StringVar(&data.Parent, "parent", "", "родительская роль")
+	}, "service", "role", "parent"); err != nil {
+		return err
+	}
+
+	return env.service.AssignParentToRole(ctx, &data)
+}
+
+func groupCreate(ctx context.Context, env *environment, args []string) error {
+	var data dto.NameServiceDescription
+	if err := parse("group create", args, defineEntity(&data), "service", "name"); err != nil {
+		return err
+	}
+
+	return env.service.CreateGroup(ctx, &data)
+}
+
+func groupDelete(ctx context.Context, env *environment, args []string) error {
+	var data dto.NameService
+	if err := parse("group delete", args, defineName(&data), "service", "name"); err != nil {
+		return err
+	}
+
+	return env.service.DeleteGroup(ctx, &data)
+}
+
+func groupRestore(ctx context.Context, env *environment, args []string) error {
+	var data dto.NameService
+	if err := parse("group restore", args, defineName(&data), "service", "name"); err != nil {
+		return err
+	}
+
+	return env.service.RestoreGroup(ctx, &data)
+}
+
+func groupAssignRole(ctx context.Context, env *environment, args []string) error {
+	var data dto.GroupRoleService
+
+	if err := parse("group assign-role", args, func(fs *flag.FlagSet) {
+		fs.StringVar(&data.Service, "service", "", "сервис")
+		fs.StringVar(&data.Group, "group", "", "группа")
+		fs.StringVar(&data.Role, "role", "", "роль")
+	}, "service", "group", "role"); err != nil {
+		return err
+	}
+
+	return env.service.AssignRoleToGroup(ctx, &data)
+}
+
+func groupAssignPermission(ctx context.Context, env *environment, args []string) error {
+	var data dto.GroupPermissionService
+
+	if err := parse("group assign-permission", args, func(fs *flag.FlagSet) {
+		fs.StringVar(&data.Service, "service", "", "сервис")
+		fs.StringVar(&data.Group, "group", "", "группа")
+		fs.StringVar(&data.Permission, "permission", "", "разрешение")
+	}, "service", "group", "permission"); err != nil {
+		return err
+	}
+
+	return env.service.AssignPermissionToGroup(ctx, &data)
+}
+
+func groupAssignSubgroup(ctx context.Context, env *environment, args []string) error {
+	var data dto.GroupSubgroupService
+
+	if err := parse("group assign-subgroup", args, func(fs *flag.FlagSet) {
+		fs.StringVar(&data.Service, "service", "", "сервис")
+		fs.StringVar(&data.Group, "group", "", "группа")
+		fs.StringVar(&data.Subgroup, "subgroup", "", "вложенная группа")
+	}, "service", "group", "subgroup"); err != nil {
+		return err
+	}
+
+	return env.service.AssignSubgroupToGroup(ctx, &data)
+}
+
+func globalGroupCreate(ctx context.Context, env *environment, args []string) error {
+	var data dto.NameDescription
+
+	if err := parse("global-group create", args, func(fs *flag.FlagSet) {
+		fs.StringVar(&data.Name, "name", "", "глобальная группа")
+		fs.StringVar(&data.Description, "description", "", "описание")
+	}, "name"); err != nil {
+		return err
+	}
+
+	return env.service.CreateGlobalGroup(ctx, &data)
+}
+
+func globalGroupDelete(ctx context.Context, env *environment, args []string) error {
+	var name string
+
+	if err := parse("global-group delete", args, func(fs *flag.FlagSet) {
+		fs.StringVar(&name, "name", "", "глобальная группа")
+	}, "name"); err != nil {
+		return err
+	}
+
+	return env.service.DeleteGlobalGroup(ctx, name)
+}
+
+func globalGroupAssignRole(ctx context.Context, env *environment, args []string) error {
+	var data dto.GlobalGroupRoleService
+
+	if err := parse("global-group assign-role", args, func(fs *flag.FlagSet) {
+		fs.StringVar(&data.GlobalGroup, "global-group", "", "глобальная группа")
+		fs.StringVar(&data.Service, "service", "", "сервис")
+		fs.StringVar(&data.Role, "role", "", "роль")
+	}, "global-group", "service", "role"); err != nil {
+		return err
+	}
+
+	return env.service.AssignRoleToGlobalGroup(ctx, &data)
+}
+
+func rbacExport(ctx context.Context, env *environment, args []string) error {
+	var format, output string
+
+	if err := parse("rbac export", args, func(fs *flag.FlagSet) {
+		fs.StringVar(&format, "format", "yaml", "yaml или json")
+		fs.StringVar(&output, "output", "", "файл для записи (по умолчанию стандартный вывод)")
+	}); err != nil {
+		return err
+	}
+
+	document, err := env.admin.ExportRBAC(ctx)
+	if err != nil {
+		return err
+	}
+
+	var data []byte
+	switch format {
+	case "yaml":
+		data, err = rbac_document.ToYAML(&document)
+	case "json":
+		data, err = json.MarshalIndent(document, "", "  ")
+		data = append(data, '\n')
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(output) == 0 {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+
+	return os.WriteFile(output, data, 0600)
+}
+
+func rbacImport(ctx context.Context, env *environment, args []string) error {
+	var (
+		file   string
+		dryRun bool
+	)
+
+	if err := parse("rbac import", args, func(fs *flag.FlagSet) {
+		fs.StringVar(&file, "file", "", "документ в YAML или JSON")
+		fs.BoolVar(&dryRun, "dry-run", false, "только показать изменения")
+	}, "file"); err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	var document dto.RBACDocument
+	if err = rbac_document.FromYAML(data, &document); err != nil {
+		return err
+	}
+
+	result, err := env.admin.ImportRBAC(ctx, &document, dryRun)
+	if printErr := printJSON(result); printErr != nil {
+		return errors.Join(err, printErr)
+	}
+
+	return err
+}
+
+func dbMigrate(_ context.Context, env *environment, args []string) error {
+	if err := parse("db migrate", args, func(*flag.FlagSet) {}); err != nil {
+		return err
+	}
+
+	if err := env.persistent.Migrate(); err != nil {
+		return err
+	}
+
+	fmt.Println("schema and tables are up to date")
+
+	return nil
+}
+
+func dbPurge(ctx context.Context, env *environment, args []string) error {
+	var retention time.Duration
+
+	if err := parse("db purge", args, func(fs *flag.FlagSet) {
+		fs.DurationVar(&retention, "retention", 720*time.Hour, "срок хранения сущностей, помеченных удаленными")
+	}); err != nil {
+		return err
+	}
+
+	return env.service.PurgeDeleted(ctx, time.Now().Add(-retention))
+}
+
+func cacheWarm(_ context.Context, env *environment, args []string) error {
+	if err := parse("cache warm", args, func(*flag.FlagSet) {}); err != nil {
+		return err
+	}
+
+	env.repository.WarmCache()
+	fmt.Println("cache is warmed up")
+
+	return nil
+}
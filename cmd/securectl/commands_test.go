@@ -0,0 +1,86 @@
+package main
+
+import (
+	"flag"
+	"testing"
+)
+
+func TestFindCommand(t *testing.T) {
+	tests := []struct {
+		group, action string
+		found, direct bool
+	}{
+		{"account", "create", true, true},
+		{"account", "reset-password", true, false},
+		{"rbac", "export", true, false},
+		{"rbac", "import", true, false},
+		{"db", "purge", true, true},
+		{"account", "unknown", false, false},
+		{"unknown", "create", false, false},
+	}
+
+	for _, test := range tests {
+		cmd, ok := findCommand(test.group, test.action)
+		if ok != test.found || cmd.direct != test.direct {
+			t.Errorf("%s %s: found %v, direct %v", test.group, test.action, ok, cmd.direct)
+		}
+		if ok && cmd.name() != test.group+" "+test.action {
+			t.Errorf("%s %s: name %q", test.group, test.action, cmd.name())
+		}
+	}
+}
+
+func TestCommandsUnique(t *testing.T) {
+	names := make(map[string]struct{}, len(commands))
+	for _, cmd := range commands {
+		if _, ok := names[cmd.name()]; ok {
+			t.Errorf("duplicate command %q", cmd.name())
+		}
+		if cmd.run == nil {
+			t.Errorf("command %q has no implementation", cmd.name())
+		}
+		names[cmd.name()] = struct{}{}
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr bool
+	}{
+		{"all required flags", []string{"-login", "user", "-password", "Secret_password"}, false},
+		{"missing password", []string{"-login", "user"}, true},
+		{"missing login", []string{"-password", "Secret_password"}, true},
+		{"empty login", []string{"-login", "", "-password", "Secret_password"}, true},
+		{"unknown flag", []string{"-login", "user", "-password", "Secret_password", "-role"}, true},
+	}
+
+	for _, test := range tests {
+		var accountLogin, accountPassword string
+		err := parse("account create", test.args, func(fs *flag.FlagSet) {
+			fs.StringVar(&accountLogin, "login", "", "логин")
+			fs.StringVar(&accountPassword, "password", "", "пароль")
+		}, "login", "password")
+		if (err != nil) != test.wantErr {
+			t.Errorf("%s: error %v", test.name, err)
+		}
+		if err == nil && (accountLogin != "user" || accountPassword != "Secret_password") {
+			t.Errorf("%s: parsed %q and %q", test.name, accountLogin, accountPassword)
+		}
+	}
+}
+
+func TestNameService(t *testing.T) {
+	result, err := nameService([]string{"store/seller", "warehouse/keeper"})
+	if err != nil || len(result) != 2 || result[0].Service != "store" || result[0].Name != "seller" ||
+		result[1].Service != "warehouse" || result[1].Name != "keeper" {
+		t.Fail()
+	}
+
+	for _, value := range []string{"seller", "/seller", "store/"} {
+		if _, err = nameService([]string{value}); err == nil {
+			t.Errorf("value %q accepted", value)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+/*
+securectl - утилита командной строки для администрирования приложения. Команды выполняются либо напрямую через
+хранилища (PostgreSQL и Redis), настройки которых считываются из того же файла конфигурации, что и у сервера (флаг
+config или переменная окружения SECURE_CONFIG_PATH), либо, если задан флаг api, через HTTP-api администратора с токеном
+сессии из флага token или переменной окружения SECURECTL_TOKEN. Через HTTP-api доступны только команды, для которых
+есть соответствующие административные точки доступа: rbac export, rbac import и account reset-password. Остальные
+команды с флагом api отклоняются.
+
+Использование:
+
+	securectl [флаги] <группа> <команда> [флаги команды]
+*/
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/lazylex/watch-store/secure/internal/config"
+	"github.com/lazylex/watch-store/secure/internal/repository/in_memory/redis"
+	"github.com/lazylex/watch-store/secure/internal/repository/joint"
+	"github.com/lazylex/watch-store/secure/internal/repository/persistent/postgresql"
+	"github.com/lazylex/watch-store/secure/internal/service"
+	"log/slog"
+	"os"
+	"time"
+)
+
+// environment окружение, в котором выполняются команды.
+type environment struct {
+	admin      adminAPI               // Операции, доступные в обоих режимах
+	service    *service.Service       // Сервисный слой. Равен nil при работе через HTTP-api
+	repository *joint.Repository      // Объединенное хранилище. Равно nil при работе через HTTP-api
+	persistent *postgresql.PostgreSQL // Постоянное хранилище. Равно nil при работе через HTTP-api
+}
+
+// noMetrics метрики сервисного слоя, которые не подсчитываются.
+type noMetrics struct{}
+
+func (noMetrics) AuthenticationErrorInc() {}
+func (noMetrics) LoginInc()               {}
+func (noMetrics) LogoutInc()              {}
+
+func main() {
+	os.Exit(run())
+}
+
+// run выполняет переданную в командной строке команду и возвращает код завершения.
+func run() int {
+	var (
+		configPath = flag.String("config", "", "путь к файлу конфигурации (по умолчанию SECURE_CONFIG_PATH)")
+		apiURL     = flag.String("api", "", "адрес HTTP-api, например https://localhost:8159 (только для rbac export, rbac import и account reset-password)")
+		token      = flag.String("token", os.Getenv("SECURECTL_TOKEN"), "токен сессии администратора для HTTP-api")
+		timeout    = flag.Duration("timeout", time.Minute, "максимальное время выполнения команды")
+	)
+
+	flag.Usage = printUsage
+	flag.Parse()
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn})))
+
+	if flag.NArg() < 2 {
+		printUsage()
+		return 2
+	}
+
+	cmd, ok := findCommand(flag.Arg(0), flag.Arg(1))
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", flag.Arg(0)+" "+flag.Arg(1))
+		printUsage()
+		return 2
+	}
+
+	var env environment
+	if len(*apiURL) > 0 {
+		if cmd.direct {
+			fmt.Fprintf(os.Stderr, "command %q requires direct access to storages and can't be used with -api\n", cmd.name())
+			return 2
+		}
+		env.admin = newRemote(*apiURL, *token)
+	} else {
+		env = mustConnect(*configPath)
+		defer env.persistent.Close()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	if err := cmd.run(ctx, &env, flag.Args()[2:]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	return 0
+}
+
+// mustConnect подключается к хранилищам, указанным в файле конфигурации. При подключении к PostgreSQL создаются
+// отсутствующие схема и таблицы. Если подключиться не удалось, работа утилиты завершается.
+func mustConnect(configPath string) environment {
+	cfg := config.MustLoadFrom(configPath)
+
+	persistentRepo := postgresql.MustCreate(cfg.PersistentStorage)
+	repo := joint.MustCreateWithoutCaching(redis.MustCreate(cfg.Redis, cfg.TTL), persistentRepo)
+	domainService := service.MustCreate(noMetrics{}, &repo, cfg.Secure)
+
+	return environment{admin: domainService, service: domainService, repository: &repo, persistent: persistentRepo}
+}
+
+// printUsage выводит справку по флагам и командам.
+func printUsage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintln(out, "Использование: securectl [флаги] <группа> <команда> [флаги команды]")
+	fmt.Fprintln(out, "\nФлаги:")
+	flag.PrintDefaults()
+	fmt.Fprintln(out, "\nКоманды (* - только с прямым доступом к хранилищам):")
+	for _, cmd := range commands {
+		mark := " "
+		if cmd.direct {
+			mark = "*"
+		}
+		fmt.Fprintf(out, "  %s %-32s %s\n", mark, cmd.name(), cmd.summary)
+	}
+	fmt.Fprintln(out, "\nФлаги команды: securectl <группа> <команда> -h")
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/lazylex/watch-store/secure/internal/domain/value_objects/login"
+	"github.com/lazylex/watch-store/secure/internal/dto"
+	"github.com/lazylex/watch-store/secure/internal/helpers/prefixes"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// remote выполняет операции администратора через HTTP-api.
+type remote struct {
+	baseURL string
+	token   string
+	client  *http.Client
+}
+
+// newRemote возвращает клиент HTTP-api, расположенного по адресу baseURL. Запросы выполняются с токеном сессии token.
+func newRemote(baseURL, token string) *remote {
+	return &remote{baseURL: strings.TrimSuffix(baseURL, "/"), token: token, client: http.DefaultClient}
+}
+
+// do выполняет запрос к точке доступа администратора path и возвращает тело ответа. Если статус ответа не входит в
+// okStatuses (по умолчанию только 200), возвращается ошибка с тем же телом ответа.
+func (r *remote) do(ctx context.Context, method, path string, query url.Values, contentType string, body io.Reader,
+	okStatuses ...int) ([]byte, int, error) {
+	target := r.baseURL + prefixes.AdminPrefix + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return nil, 0, err
+	}
+	request.Header.Set("Authorization", "Bearer "+r.token)
+	if len(contentType) > 0 {
+		request.Header.Set("Content-Type", contentType)
+	}
+
+	response, err := r.client.Do(request)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() { _ = response.Body.Close() }()
+
+	answer, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, response.StatusCode, err
+	}
+
+	if len(okStatuses) == 0 {
+		okStatuses = []int{http.StatusOK}
+	}
+	for _, status := range okStatuses {
+		if response.StatusCode == status {
+			return answer, response.StatusCode, nil
+		}
+	}
+
+	return answer, response.StatusCode, fmt.Errorf("%s %s: %s", method, path, response.Status)
+}
+
+// ExportRBAC возвращает документ с конфигурацией управления доступом.
+func (r *remote) ExportRBAC(ctx context.Context) (dto.RBACDocument, error) {
+	var document dto.RBACDocument
+
+	answer, _, err := r.do(ctx, http.MethodGet, "rbac/export", nil, "", nil)
+	if err != nil {
+		return document, err
+	}
+
+	err = json.Unmarshal(answer, &document)
+
+	return document, err
+}
+
+// ImportRBAC применяет документ с конфигурацией управления доступом. При конфликтах вместе с ошибкой возвращается
+// список конфликтов.
+func (r *remote) ImportRBAC(ctx context.Context, document *dto.RBACDocument, dryRun bool) (dto.RBACImportResult, error) {
+	var result dto.RBACImportResult
+
+	body, err := json.Marshal(document)
+	if err != nil {
+		return result, err
+	}
+
+	query := url.Values{}
+	if dryRun {
+		query.Set("dry_run", "true")
+	}
+
+	answer, status, err := r.do(ctx, http.MethodPost, "rbac/import", query, "application/json", bytes.NewReader(body),
+		http.StatusOK, http.StatusConflict)
+	if err != nil {
+		return result, err
+	}
+
+	if err = json.Unmarshal(answer, &result); err != nil {
+		return result, err
+	}
+
+	if status == http.StatusConflict {
+		return result, fmt.Errorf("rbac document conflicts with current state")
+	}
+
+	return result, nil
+}
+
+// ResetPassword возвращает одноразовый токен сброса пароля учетной записи с логином accountLogin.
+func (r *remote) ResetPassword(ctx context.Context, accountLogin login.Login) (string, error) {
+	form := url.Values{"login": {string(accountLogin)}}
+
+	answer, _, err := r.do(ctx, http.MethodPost, "reset-password", nil, "application/x-www-form-urlencoded",
+		strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+
+	var result struct {
+		Token string `json:"reset-token"`
+	}
+	err = json.Unmarshal(answer, &result)
+
+	return result.Token, err
+}
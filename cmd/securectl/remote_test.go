@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/lazylex/watch-store/secure/internal/dto"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// server запускает HTTP-api администратора, проверяющий токен сессии, и возвращает клиента этого api.
+func server(t *testing.T, handlers map[string]http.HandlerFunc) *remote {
+	mux := http.NewServeMux()
+	for path, handler := range handlers {
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer session" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			handler(w, r)
+		})
+	}
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	return newRemote(srv.URL+"/", "session")
+}
+
+func TestRemote_ResetPassword(t *testing.T) {
+	r := server(t, map[string]http.HandlerFunc{"/admin/reset-password": func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.FormValue("login") != "user" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"reset-token":"reset"}`))
+	}})
+
+	token, err := r.ResetPassword(context.Background(), "user")
+	if err != nil || token != "reset" {
+		t.Fail()
+	}
+}
+
+func TestRemote_ErrUnauthorized(t *testing.T) {
+	r := server(t, map[string]http.HandlerFunc{"/admin/reset-password": func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"reset-token":"reset"}`))
+	}})
+	r.token = "wrong"
+
+	if _, err := r.ResetPassword(context.Background(), "user"); err == nil {
+		t.Fail()
+	}
+}
+
+func TestRemote_ExportRBAC(t *testing.T) {
+	r := server(t, map[string]http.HandlerFunc{"/admin/rbac/export": func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		_, _ = w.Write([]byte(`{"version":1,"services":[{"name":"store"}]}`))
+	}})
+
+	document, err := r.ExportRBAC(context.Background())
+	if err != nil || document.Version != 1 || len(document.Services) != 1 || document.Services[0].Name != "store" {
+		t.Fail()
+	}
+}
+
+func TestRemote_ImportRBAC(t *testing.T) {
+	r := server(t, map[string]http.HandlerFunc{"/admin/rbac/import": func(w http.ResponseWriter, r *http.Request) {
+		var document dto.RBACDocument
+		if r.Header.Get("Content-Type") != "application/json" || json.NewDecoder(r.Body).Decode(&document) != nil ||
+			document.Version != 1 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if r.URL.Query().Get("dry_run") == "true" {
+			_, _ = w.Write([]byte(`{"applied":false}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"applied":true}`))
+	}})
+
+	result, err := r.ImportRBAC(context.Background(), &dto.RBACDocument{Version: 1}, false)
+	if err != nil || !result.Applied {
+		t.Fail()
+	}
+
+	result, err = r.ImportRBAC(context.Background(), &dto.RBACDocument{Version: 1}, true)
+	if err != nil || result.Applied {
+		t.Fail()
+	}
+}
+
+func TestRemote_ImportRBACConflict(t *testing.T) {
+	r := server(t, map[string]http.HandlerFunc{"/admin/rbac/import": func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		_, _ = w.Write([]byte(`{"applied":false,"conflicts":["role store/seller is deleted"]}`))
+	}})
+
+	result, err := r.ImportRBAC(context.Background(), &dto.RBACDocument{Version: 1}, false)
+	if err == nil || len(result.Conflicts) != 1 {
+		t.Fail()
+	}
+}
+
+func TestRbacImportRemote(t *testing.T) {
+	imported := false
+	r := server(t, map[string]http.HandlerFunc{"/admin/rbac/import": func(w http.ResponseWriter, _ *http.Request) {
+		imported = true
+		_, _ = w.Write([]byte(`{"applied":true}`))
+	}})
+
+	file := filepath.Join(t.TempDir(), "rbac.yaml")
+	if err := os.WriteFile(file, []byte("version: 1\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd, _ := findCommand("rbac", "import")
+	if err := cmd.run(context.Background(), &environment{admin: r}, []string{"-file", file}); err != nil || !imported {
+		t.Fail()
+	}
+
+	if err := cmd.run(context.Background(), &environment{admin: r}, nil); err == nil {
+		t.Fail()
+	}
+}
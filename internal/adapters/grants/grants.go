@@ -0,0 +1,76 @@
+/*
+Package grants: пакет периодического удаления назначений учетным записям с истёкшим сроком действия. Cleaner с заданным
+в настройках интервалом удаляет истёкшие назначения ролей, групп и разрешений экземпляров и обновляет кэш разрешений
+учетных записей, назначения которых истекли или вступили в силу с момента предыдущей очистки.
+*/
+package grants
+
+import (
+	"context"
+	"github.com/lazylex/watch-store/secure/internal/config"
+	"github.com/lazylex/watch-store/secure/internal/ports/service"
+	"log/slog"
+	"os"
+	"time"
+)
+
+// Cleaner структура, периодически удаляющая истёкшие назначения учетным записям.
+type Cleaner struct {
+	service service.Service // Сервисный слой
+	cfg     *config.Grants  // Настройки очистки
+	last    time.Time       // Время предыдущей очистки
+	stop    chan struct{}   // Канал остановки очистки
+}
+
+// MustCreate возвращает структуру для удаления истёкших назначений. Если сервис или настройки равны nil или интервал
+// очистки не положителен, работа приложения завершается.
+func MustCreate(domainService service.Service, cfg *config.Grants) *Cleaner {
+	if domainService == nil || cfg == nil || cfg.CleanupInterval <= 0 {
+		slog.Error("domain service, cfg or cleanup interval is not set")
+		os.Exit(1)
+	}
+
+	return &Cleaner{service: domainService, cfg: cfg, last: time.Now()}
+}
+
+// MustRun запускает очистку истёкших назначений с интервалом из настроек. Ошибки очистки записываются в журнал.
+func (c *Cleaner) MustRun() {
+	stop := make(chan struct{})
+	c.stop = stop
+	ticker := time.NewTicker(c.cfg.CleanupInterval)
+
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), c.cfg.CleanupInterval)
+				_ = c.CleanUp(ctx)
+				cancel()
+			}
+		}
+	}()
+}
+
+// Stop прекращает очистку истёкших назначений.
+func (c *Cleaner) Stop() {
+	if c.stop != nil {
+		close(c.stop)
+		c.stop = nil
+	}
+}
+
+// CleanUp удаляет истёкшие назначения и обновляет кэш разрешений учетных записей, назначения которых истекли или
+// вступили в силу после предыдущей успешной очистки.
+func (c *Cleaner) CleanUp(ctx context.Context) error {
+	now := time.Now()
+	if err := c.service.DeleteExpiredGrants(ctx, c.last); err != nil {
+		slog.Error("unable to delete expired grants: " + err.Error())
+		return err
+	}
+	c.last = now
+
+	return nil
+}
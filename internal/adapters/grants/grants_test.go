@@ -0,0 +1,39 @@
+package grants
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/lazylex/watch-store/secure/internal/config"
+	mockservice "github.com/lazylex/watch-store/secure/internal/ports/service/mocks"
+	"testing"
+	"time"
+)
+
+func TestCleaner_CleanUp(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	service := mockservice.NewMockService(controller)
+	c := MustCreate(service, &config.Grants{CleanupInterval: time.Minute})
+	first := c.last
+
+	service.EXPECT().DeleteExpiredGrants(ctx, first).Times(1).Return(nil)
+
+	if c.CleanUp(ctx) != nil || !c.last.After(first) {
+		t.Fail()
+	}
+}
+
+func TestCleaner_CleanUpErrorKeepsLastTime(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	service := mockservice.NewMockService(controller)
+	c := MustCreate(service, &config.Grants{CleanupInterval: time.Minute})
+	first := c.last
+
+	service.EXPECT().DeleteExpiredGrants(ctx, first).Times(2).Return(errors.New("timeout"))
+
+	if c.CleanUp(ctx) == nil || c.CleanUp(ctx) == nil || c.last != first {
+		t.Fail()
+	}
+}
@@ -0,0 +1,399 @@
+package handlers
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/lazylex/watch-store/secure/internal/adapters/grpc/session"
+	"github.com/lazylex/watch-store/secure/internal/dto"
+	"github.com/lazylex/watch-store/secure/internal/service"
+	"github.com/lazylex/watch-store/secure/pkg/securepb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log/slog"
+	"time"
+)
+
+// Проверка роли администратора содержится в interceptor, поэтому в обработчиках она опускается.
+
+// AdminHandler структура для обработки gRPC-запросов сервиса Admin, управляющего разрешениями, ролями и группами.
+type AdminHandler struct {
+	securepb.UnimplementedAdminServer
+	service      *service.Service // Объект, реализующий логику сервиса
+	queryTimeout time.Duration    // Допустимый таймаут для обработки запроса
+}
+
+// NewAdmin возвращает структуру с обработчиками gRPC-запросов сервиса Admin.
+func NewAdmin(domainService *service.Service, timeout time.Duration) *AdminHandler {
+	return &AdminHandler{service: domainService, queryTimeout: timeout}
+}
+
+// CreatePermission создает разрешение с заданным номером, а если номер равен нулю - со следующим свободным.
+func (h *AdminHandler) CreatePermission(ctx context.Context, req *securepb.CreatePermissionRequest) (*securepb.Empty, error) {
+	return h.execute(ctx, "create permission", func(ctx context.Context) error {
+		return h.service.CreatePermission(ctx, &dto.NameNumberDescriptionService{
+			Name:        req.GetName(),
+			Number:      int(req.GetNumber()),
+			Description: req.GetDescription(),
+			Service:     req.GetService(),
+		})
+	}, req.GetName(), req.GetService())
+}
+
+// CreateRole создает роль.
+func (h *AdminHandler) CreateRole(ctx context.Context, req *securepb.NameServiceDescription) (*securepb.Empty, error) {
+	return h.execute(ctx, "create role", func(ctx context.Context) error {
+		return h.service.CreateRole(ctx, nameServiceDescription(req))
+	}, req.GetName(), req.GetService())
+}
+
+// CreateGroup создает группу.
+func (h *AdminHandler) CreateGroup(ctx context.Context, req *securepb.NameServiceDescription) (*securepb.Empty, error) {
+	return h.execute(ctx, "create group", func(ctx context.Context) error {
+		return h.service.CreateGroup(ctx, nameServiceDescription(req))
+	}, req.GetName(), req.GetService())
+}
+
+// SetAccountMetadata заменяет метаданные учетной записи.
+func (h *AdminHandler) SetAccountMetadata(ctx context.Context, req *securepb.AccountMetadata) (*securepb.Empty, error) {
+	id, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user id")
+	}
+
+	return h.execute(ctx, "set account metadata", func(ctx context.Context) error {
+		return h.service.SetAccountMetadata(ctx, &dto.UserIdAccountMetadata{UserId: id, Type: req.GetType(),
+			DisplayName: req.GetDisplayName(), Contact: req.GetContact(), OwnerTeam: req.GetOwnerTeam()})
+	}, req.GetType())
+}
+
+// AssignRoleToAccount прикрепляет роль к учетной записи.
+func (h *AdminHandler) AssignRoleToAccount(ctx context.Context, req *securepb.AccountRole) (*securepb.Empty, error) {
+	id, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user id")
+	}
+
+	return h.execute(ctx, "assign role to account", func(ctx context.Context) error {
+		return h.service.AssignRoleToAccount(ctx, &dto.UserIdRoleService{UserId: id, Role: req.GetRole(), Service: req.GetService(),
+			ValidFrom: unixTime(req.GetValidFrom()), ValidUntil: unixTime(req.GetValidUntil()), Condition: req.GetCondition()})
+	}, req.GetRole(), req.GetService())
+}
+
+// AssignGroupToAccount прикрепляет учетную запись к группе.
+func (h *AdminHandler) AssignGroupToAccount(ctx context.Context, req *securepb.AccountGroup) (*securepb.Empty, error) {
+	id, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user id")
+	}
+
+	return h.execute(ctx, "assign group to account", func(ctx context.Context) error {
+		return h.service.AssignGroupToAccount(ctx, &dto.UserIdGroupService{UserId: id, Group: req.GetGroup(), Service: req.GetService(),
+			ValidFrom: unixTime(req.GetValidFrom()), ValidUntil: unixTime(req.GetValidUntil()), Condition: req.GetCondition()})
+	}, req.GetGroup(), req.GetService())
+}
+
+// AssignInstancePermissionToAccount прикрепляет к учетной записи разрешение для конкретного экземпляра сервиса.
+func (h *AdminHandler) AssignInstancePermissionToAccount(ctx context.Context, req *securepb.AccountInstancePermission) (*securepb.Empty, error) {
+	id, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user id")
+	}
+
+	return h.execute(ctx, "assign instance permission to account", func(ctx context.Context) error {
+		return h.service.AssignInstancePermissionToAccount(ctx,
+			&dto.UserIdInstancePermission{UserId: id, Instance: req.GetInstance(), Permission: req.GetPermission(),
+				ValidFrom: unixTime(req.GetValidFrom()), ValidUntil: unixTime(req.GetValidUntil()), Condition: req.GetCondition()})
+	}, req.GetInstance(), req.GetPermission())
+}
+
+// AssignInstanceRoleToAccount прикрепляет к учетной записи роль, действующую только в конкретном экземпляре сервиса.
+func (h *AdminHandler) AssignInstanceRoleToAccount(ctx context.Context, req *securepb.AccountInstanceRole) (*securepb.Empty, error) {
+	id, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user id")
+	}
+
+	return h.execute(ctx, "assign instance role to account", func(ctx context.Context) error {
+		return h.service.AssignInstanceRoleToAccount(ctx, &dto.UserIdInstanceRole{UserId: id, Instance: req.GetInstance(),
+			Role: req.GetRole(), ValidFrom: unixTime(req.GetValidFrom()), ValidUntil: unixTime(req.GetValidUntil()),
+			Condition: req.GetCondition()})
+	}, req.GetInstance(), req.GetRole())
+}
+
+// AssignInstanceGroupToAccount прикрепляет учетную запись к группе только в конкретном экземпляре сервиса.
+func (h *AdminHandler) AssignInstanceGroupToAccount(ctx context.Context, req *securepb.AccountInstanceGroup) (*securepb.Empty, error) {
+	id, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user id")
+	}
+
+	return h.execute(ctx, "assign instance group to account", func(ctx context.Context) error {
+		return h.service.AssignInstanceGroupToAccount(ctx, &dto.UserIdInstanceGroup{UserId: id, Instance: req.GetInstance(),
+			Group: req.GetGroup(), ValidFrom: unixTime(req.GetValidFrom()), ValidUntil: unixTime(req.GetValidUntil()),
+			Condition: req.GetCondition()})
+	}, req.GetInstance(), req.GetGroup())
+}
+
+// AssignSelectorPermissionToAccount прикрепляет к учетной записи разрешение сервиса для экземпляров, атрибуты которых
+// соответствуют селектору.
+func (h *AdminHandler) AssignSelectorPermissionToAccount(ctx context.Context, req *securepb.AccountSelectorPermission) (*securepb.Empty, error) {
+	id, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user id")
+	}
+
+	return h.execute(ctx, "assign selector permission to account", func(ctx context.Context) error {
+		return h.service.AssignSelectorPermissionToAccount(ctx, &dto.UserIdSelectorPermission{UserId: id,
+			Service: req.GetService(), Permission: req.GetPermission(), Selector: req.GetSelector()})
+	}, req.GetService(), req.GetPermission(), req.GetSelector())
+}
+
+// AssignRoleToGroup прикрепляет роль к группе.
+func (h *AdminHandler) AssignRoleToGroup(ctx context.Context, req *securepb.GroupRole) (*securepb.Empty, error) {
+	return h.execute(ctx, "assign role to group", func(ctx context.Context) error {
+		return h.service.AssignRoleToGroup(ctx, &dto.GroupRoleService{Group: req.GetGroup(), Role: req.GetRole(), Service: req.GetService()})
+	}, req.GetGroup(), req.GetRole(), req.GetService())
+}
+
+// AssignPermissionToRole прикрепляет разрешение к роли.
+func (h *AdminHandler) AssignPermissionToRole(ctx context.Context, req *securepb.RolePermission) (*securepb.Empty, error) {
+	return h.execute(ctx, "assign permission to role", func(ctx context.Context) error {
+		return h.service.AssignPermissionToRole(ctx,
+			&dto.PermissionRoleService{Permission: req.GetPermission(), Role: req.GetRole(), Service: req.GetService()})
+	}, req.GetPermission(), req.GetRole(), req.GetService())
+}
+
+// AssignParentToRole назначает роли родительскую роль.
+func (h *AdminHandler) AssignParentToRole(ctx context.Context, req *securepb.RoleParent) (*securepb.Empty, error) {
+	return h.execute(ctx, "assign parent to role", func(ctx context.Context) error {
+		return h.service.AssignParentToRole(ctx,
+			&dto.RoleParentService{Role: req.GetRole(), Parent: req.GetParent(), Service: req.GetService()})
+	}, req.GetRole(), req.GetParent(), req.GetService())
+}
+
+// AssignPermissionToGroup прикрепляет разрешение к группе.
+func (h *AdminHandler) AssignPermissionToGroup(ctx context.Context, req *securepb.GroupPermission) (*securepb.Empty, error) {
+	return h.execute(ctx, "assign permission to group", func(ctx context.Context) error {
+		return h.service.AssignPermissionToGroup(ctx,
+			&dto.GroupPermissionService{Group: req.GetGroup(), Permission: req.GetPermission(), Service: req.GetService()})
+	}, req.GetGroup(), req.GetPermission(), req.GetService())
+}
+
+// AssignSubgroupToGroup вкладывает группу в другую группу.
+func (h *AdminHandler) AssignSubgroupToGroup(ctx context.Context, req *securepb.GroupSubgroup) (*securepb.Empty, error) {
+	return h.execute(ctx, "assign subgroup to group", func(ctx context.Context) error {
+		return h.service.AssignSubgroupToGroup(ctx,
+			&dto.GroupSubgroupService{Group: req.GetGroup(), Subgroup: req.GetSubgroup(), Service: req.GetService()})
+	}, req.GetGroup(), req.GetSubgroup(), req.GetService())
+}
+
+// DeleteRole помечает роль удаленной.
+func (h *AdminHandler) DeleteRole(ctx context.Context, req *securepb.NameService) (*securepb.Empty, error) {
+	return h.execute(ctx, "delete role", func(ctx context.Context) error {
+		return h.service.DeleteRole(ctx, &dto.NameService{Name: req.GetName(), Service: req.GetService()})
+	}, req.GetName(), req.GetService())
+}
+
+// DeleteGroup помечает группу удаленной.
+func (h *AdminHandler) DeleteGroup(ctx context.Context, req *securepb.NameService) (*securepb.Empty, error) {
+	return h.execute(ctx, "delete group", func(ctx context.Context) error {
+		return h.service.DeleteGroup(ctx, &dto.NameService{Name: req.GetName(), Service: req.GetService()})
+	}, req.GetName(), req.GetService())
+}
+
+// DeletePermission помечает разрешение удаленным.
+func (h *AdminHandler) DeletePermission(ctx context.Context, req *securepb.NameService) (*securepb.Empty, error) {
+	return h.execute(ctx, "delete permission", func(ctx context.Context) error {
+		return h.service.DeletePermission(ctx, &dto.NameService{Name: req.GetName(), Service: req.GetService()})
+	}, req.GetName(), req.GetService())
+}
+
+// DeprecatePermission помечает разрешение устаревшим.
+func (h *AdminHandler) DeprecatePermission(ctx context.Context, req *securepb.NameService) (*securepb.Empty, error) {
+	return h.execute(ctx, "deprecate permission", func(ctx context.Context) error {
+		return h.service.DeprecatePermission(ctx, &dto.NameService{Name: req.GetName(), Service: req.GetService()})
+	}, req.GetName(), req.GetService())
+}
+
+// DeleteInstance помечает экземпляр сервиса удаленным.
+func (h *AdminHandler) DeleteInstance(ctx context.Context, req *securepb.Name) (*securepb.Empty, error) {
+	return h.execute(ctx, "delete instance", func(ctx context.Context) error {
+		return h.service.DeleteInstance(ctx, req.GetName())
+	}, req.GetName())
+}
+
+// DeleteService помечает удаленными сервис и все его экземпляры, разрешения, роли и группы.
+func (h *AdminHandler) DeleteService(ctx context.Context, req *securepb.Name) (*securepb.Empty, error) {
+	return h.execute(ctx, "delete service", func(ctx context.Context) error {
+		return h.service.DeleteService(ctx, req.GetName())
+	}, req.GetName())
+}
+
+// DeleteAccount помечает учетную запись удаленной.
+func (h *AdminHandler) DeleteAccount(ctx context.Context, req *securepb.AccountId) (*securepb.Empty, error) {
+	id, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user id")
+	}
+
+	callerId, err := h.userId(ctx)
+	if err != nil {
+		return nil, statusError(err, codes.Internal)
+	}
+
+	return h.execute(ctx, "delete account", func(ctx context.Context) error {
+		return h.service.DeleteAccount(ctx, callerId, id)
+	})
+}
+
+// RestoreRole восстанавливает удаленную роль.
+func (h *AdminHandler) RestoreRole(ctx context.Context, req *securepb.NameService) (*securepb.Empty, error) {
+	return h.execute(ctx, "restore role", func(ctx context.Context) error {
+		return h.service.RestoreRole(ctx, &dto.NameService{Name: req.GetName(), Service: req.GetService()})
+	}, req.GetName(), req.GetService())
+}
+
+// RestoreGroup восстанавливает удаленную группу.
+func (h *AdminHandler) RestoreGroup(ctx context.Context, req *securepb.NameService) (*securepb.Empty, error) {
+	return h.execute(ctx, "restore group", func(ctx context.Context) error {
+		return h.service.RestoreGroup(ctx, &dto.NameService{Name: req.GetName(), Service: req.GetService()})
+	}, req.GetName(), req.GetService())
+}
+
+// RestorePermission восстанавливает удаленное разрешение.
+func (h *AdminHandler) RestorePermission(ctx context.Context, req *securepb.NameService) (*securepb.Empty, error) {
+	return h.execute(ctx, "restore permission", func(ctx context.Context) error {
+		return h.service.RestorePermission(ctx, &dto.NameService{Name: req.GetName(), Service: req.GetService()})
+	}, req.GetName(), req.GetService())
+}
+
+// RestoreInstance восстанавливает удаленный экземпляр сервиса.
+func (h *AdminHandler) RestoreInstance(ctx context.Context, req *securepb.Name) (*securepb.Empty, error) {
+	return h.execute(ctx, "restore instance", func(ctx context.Context) error {
+		return h.service.RestoreInstance(ctx, req.GetName())
+	}, req.GetName())
+}
+
+// RestoreService восстанавливает удаленный сервис вместе с удаленными с ним сущностями.
+func (h *AdminHandler) RestoreService(ctx context.Context, req *securepb.Name) (*securepb.Empty, error) {
+	return h.execute(ctx, "restore service", func(ctx context.Context) error {
+		return h.service.RestoreService(ctx, req.GetName())
+	}, req.GetName())
+}
+
+// RestoreAccount восстанавливает удаленную учетную запись.
+func (h *AdminHandler) RestoreAccount(ctx context.Context, req *securepb.AccountId) (*securepb.Empty, error) {
+	id, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user id")
+	}
+
+	return h.execute(ctx, "restore account", func(ctx context.Context) error {
+		return h.service.RestoreAccount(ctx, id)
+	})
+}
+
+// SetPermissionEncoding выбирает кодирование номеров разрешений в токенах сервиса.
+func (h *AdminHandler) SetPermissionEncoding(ctx context.Context, req *securepb.ServicePermissionEncoding) (*securepb.Empty, error) {
+	return h.execute(ctx, "set permission encoding", func(ctx context.Context) error {
+		return h.service.SetServicePermissionEncoding(ctx, &dto.NameEncoding{Name: req.GetService(), Encoding: req.GetEncoding()})
+	}, req.GetService(), req.GetEncoding())
+}
+
+// SetInstanceAttribute устанавливает значение атрибута экземпляра сервиса, используемого в условиях назначений.
+func (h *AdminHandler) SetInstanceAttribute(ctx context.Context, req *securepb.InstanceAttribute) (*securepb.Empty, error) {
+	return h.execute(ctx, "set instance attribute", func(ctx context.Context) error {
+		return h.service.SetInstanceAttribute(ctx,
+			&dto.InstanceNameValue{Instance: req.GetInstance(), Name: req.GetName(), Value: req.GetValue()})
+	}, req.GetInstance(), req.GetName())
+}
+
+// DeleteInstanceAttribute удаляет атрибут экземпляра сервиса.
+func (h *AdminHandler) DeleteInstanceAttribute(ctx context.Context, req *securepb.InstanceAttribute) (*securepb.Empty, error) {
+	return h.execute(ctx, "delete instance attribute", func(ctx context.Context) error {
+		return h.service.DeleteInstanceAttribute(ctx, &dto.NameInstance{Name: req.GetName(), Instance: req.GetInstance()})
+	}, req.GetInstance(), req.GetName())
+}
+
+// CreateGlobalGroup создает глобальную группу.
+func (h *AdminHandler) CreateGlobalGroup(ctx context.Context, req *securepb.NameDescription) (*securepb.Empty, error) {
+	return h.execute(ctx, "create global group", func(ctx context.Context) error {
+		return h.service.CreateGlobalGroup(ctx, &dto.NameDescription{Name: req.GetName(), Description: req.GetDescription()})
+	}, req.GetName())
+}
+
+// AssignRoleToGlobalGroup прикрепляет роль сервиса к глобальной группе.
+func (h *AdminHandler) AssignRoleToGlobalGroup(ctx context.Context, req *securepb.GlobalGroupRole) (*securepb.Empty, error) {
+	return h.execute(ctx, "assign role to global group", func(ctx context.Context) error {
+		return h.service.AssignRoleToGlobalGroup(ctx,
+			&dto.GlobalGroupRoleService{GlobalGroup: req.GetGlobalGroup(), Role: req.GetRole(), Service: req.GetService()})
+	}, req.GetGlobalGroup(), req.GetRole(), req.GetService())
+}
+
+// AssignGlobalGroupToAccount прикрепляет учетную запись к глобальной группе.
+func (h *AdminHandler) AssignGlobalGroupToAccount(ctx context.Context, req *securepb.AccountGlobalGroup) (*securepb.Empty, error) {
+	id, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user id")
+	}
+
+	return h.execute(ctx, "assign global group to account", func(ctx context.Context) error {
+		return h.service.AssignGlobalGroupToAccount(ctx, &dto.UserIdGlobalGroup{UserId: id, GlobalGroup: req.GetGlobalGroup(),
+			ValidFrom: unixTime(req.GetValidFrom()), ValidUntil: unixTime(req.GetValidUntil()), Condition: req.GetCondition()})
+	}, req.GetGlobalGroup())
+}
+
+// DeleteGlobalGroup удаляет глобальную группу.
+func (h *AdminHandler) DeleteGlobalGroup(ctx context.Context, req *securepb.Name) (*securepb.Empty, error) {
+	return h.execute(ctx, "delete global group", func(ctx context.Context) error {
+		return h.service.DeleteGlobalGroup(ctx, req.GetName())
+	}, req.GetName())
+}
+
+// execute проверяет, что обязательные параметры required не пусты, и выполняет операцию action с таймаутом запроса.
+// Результат операции с названием operation заносится в лог.
+func (h *AdminHandler) execute(ctx context.Context, operation string, action func(context.Context) error, required ...string) (*securepb.Empty, error) {
+	log := slog.Default().With("remote address", session.RemoteAddress(ctx))
+
+	for _, value := range required {
+		if len(value) == 0 {
+			log.Warn("empty required parameter: " + operation)
+			return nil, status.Error(codes.InvalidArgument, "empty required parameter")
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, h.queryTimeout)
+	defer cancel()
+
+	if err := action(ctx); err != nil {
+		log.Warn("unable to " + operation)
+		return nil, statusError(err, codes.Internal)
+	}
+
+	log.Info("successfully " + operation)
+
+	return &securepb.Empty{}, nil
+}
+
+// userId возвращает UUID учетной записи, которой принадлежит токен сессии из метаданных запроса.
+func (h *AdminHandler) userId(ctx context.Context) (uuid.UUID, error) {
+	token, _ := session.Token(ctx)
+
+	return h.service.UserUUIDFromSession(ctx, token)
+}
+
+// nameServiceDescription преобразует запрос создания роли или группы в DTO.
+func nameServiceDescription(req *securepb.NameServiceDescription) *dto.NameServiceDescription {
+	return &dto.NameServiceDescription{Name: req.GetName(), Service: req.GetService(), Description: req.GetDescription()}
+}
+
+// unixTime преобразует время в секундах Unix-времени из запроса в DTO. Нулевое значение означает отсутствие
+// ограничения срока действия назначения.
+func unixTime(seconds int64) *time.Time {
+	if seconds == 0 {
+		return nil
+	}
+
+	t := time.Unix(seconds, 0)
+	return &t
+}
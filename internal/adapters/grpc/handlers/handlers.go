@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/lazylex/watch-store/secure/internal/adapters/grpc/session"
+	"github.com/lazylex/watch-store/secure/internal/domain/value_objects/login"
+	"github.com/lazylex/watch-store/secure/internal/domain/value_objects/password"
+	"github.com/lazylex/watch-store/secure/internal/dto"
+	serviceErr "github.com/lazylex/watch-store/secure/internal/errors/service"
+	"github.com/lazylex/watch-store/secure/internal/service"
+	"github.com/lazylex/watch-store/secure/pkg/securepb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log/slog"
+	"time"
+)
+
+// Проверка на существование токена содержится в interceptor, поэтому в обработчиках она опускается.
+
+// Handler структура для обработки gRPC-запросов сервиса Secure.
+type Handler struct {
+	securepb.UnimplementedSecureServer
+	service      *service.Service // Объект, реализующий логику сервиса
+	queryTimeout time.Duration    // Допустимый таймаут для обработки запроса
+}
+
+// New возвращает структуру с обработчиками gRPC-запросов сервиса Secure.
+func New(domainService *service.Service, timeout time.Duration) *Handler {
+	return &Handler{service: domainService, queryTimeout: timeout}
+}
+
+// Login производит вход в учетную запись и возвращает токен сессии. Если для учетной записи включена двухфакторная
+// аутентификация, вместо токена сессии возвращается токен незавершенного входа (challenge).
+func (h *Handler) Login(ctx context.Context, req *securepb.LoginRequest) (*securepb.LoginResponse, error) {
+	log := slog.Default().With("remote address", session.RemoteAddress(ctx))
+
+	userLogin := login.Login(req.GetLogin())
+	userPassword := password.Password(req.GetPassword())
+
+	if userLogin.Validate() != nil || userPassword.Validate() != nil {
+		log.Warn("unable to validate username or password")
+		return nil, status.Error(codes.Unauthenticated, "incorrect login or password")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, h.queryTimeout)
+	defer cancel()
+
+	token, err := h.service.Login(ctx, &dto.LoginPassword{Login: userLogin, Password: userPassword})
+	if err != nil {
+		if errors.Is(err, serviceErr.ErrSecondFactorRequired) {
+			log.Info("second authentication factor required")
+			return &securepb.LoginResponse{Challenge: token}, nil
+		}
+		log.Warn("unable to login")
+		return nil, statusError(err, codes.Unauthenticated)
+	}
+
+	log.Info("successfully logged in")
+
+	return &securepb.LoginResponse{Token: token}, nil
+}
+
+// CompleteLogin завершает вход, начатый методом Login, по токену незавершенного входа и одноразовому паролю или коду
+// восстановления. Возвращает токен сессии.
+func (h *Handler) CompleteLogin(ctx context.Context, req *securepb.CompleteLoginRequest) (*securepb.CompleteLoginResponse, error) {
+	log := slog.Default().With("remote address", session.RemoteAddress(ctx))
+
+	if len(req.GetChallenge()) == 0 || len(req.GetCode()) == 0 {
+		log.Warn("unable to get challenge or code")
+		return nil, status.Error(codes.InvalidArgument, "challenge and code are required")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, h.queryTimeout)
+	defer cancel()
+
+	token, err := h.service.CompleteLogin(ctx, &dto.TokenCode{Token: req.GetChallenge(), Code: req.GetCode()})
+	if err != nil {
+		log.Warn("unable to complete login")
+		return nil, statusError(err, codes.Unauthenticated)
+	}
+
+	log.Info("successfully logged in with second factor")
+
+	return &securepb.CompleteLoginResponse{Token: token}, nil
+}
+
+// Logout производит выход из учетной записи.
+func (h *Handler) Logout(ctx context.Context, _ *securepb.LogoutRequest) (*securepb.LogoutResponse, error) {
+	log := slog.Default().With("remote address", session.RemoteAddress(ctx))
+
+	ctx, cancel := context.WithTimeout(ctx, h.queryTimeout)
+	defer cancel()
+
+	id, err := h.userId(ctx)
+	if err != nil {
+		log.Warn("unable to get user uuid from session")
+		return nil, statusError(err, codes.Internal)
+	}
+
+	if err = h.service.Logout(ctx, id); err != nil {
+		log.Warn("unable to logout")
+		return nil, statusError(err, codes.Internal)
+	}
+
+	log.Info("successfully logout")
+
+	return &securepb.LogoutResponse{}, nil
+}
+
+// GetToken возвращает JWT-токен, содержащий информацию о разрешениях для переданного экземпляра приложения.
+func (h *Handler) GetToken(ctx context.Context, req *securepb.GetTokenRequest) (*securepb.GetTokenResponse, error) {
+	log := slog.Default().With("remote address", session.RemoteAddress(ctx))
+
+	if len(req.GetInstance()) == 0 {
+		log.Warn("unable to get instance")
+		return nil, status.Error(codes.InvalidArgument, "instance is required")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, h.queryTimeout)
+	defer cancel()
+
+	id, err := h.userId(ctx)
+	if err != nil {
+		log.Warn("unable to get user uuid from session")
+		return nil, statusError(err, codes.Internal)
+	}
+
+	token, err := h.service.CreateToken(ctx,
+		&dto.UserIdInstanceAddress{UserId: id, Instance: req.GetInstance(), Address: session.RemoteAddress(ctx)})
+	if err != nil {
+		log.Warn("error create token")
+		return nil, statusError(err, codes.Internal)
+	}
+
+	log.Info("sent jwt-token")
+
+	return &securepb.GetTokenResponse{Token: token}, nil
+}
+
+// GetNumberedPermissions возвращает названия и номера разрешений для переданного сервиса.
+func (h *Handler) GetNumberedPermissions(ctx context.Context, req *securepb.GetNumberedPermissionsRequest) (*securepb.GetNumberedPermissionsResponse, error) {
+	log := slog.Default().With("remote address", session.RemoteAddress(ctx))
+
+	if len(req.GetService()) == 0 {
+		log.Warn("unable to get service")
+		return nil, status.Error(codes.InvalidArgument, "service is required")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, h.queryTimeout)
+	defer cancel()
+
+	numberedPermissions, err := h.service.ServiceNumberedPermissions(ctx, req.GetService())
+	if err != nil {
+		log.Warn("error get numbered permissions")
+		return nil, statusError(err, codes.Internal)
+	}
+
+	response := &securepb.GetNumberedPermissionsResponse{}
+	for _, permission := range *numberedPermissions {
+		response.Permissions = append(response.Permissions,
+			&securepb.NumberedPermission{Name: permission.Name, Number: int32(permission.Number)})
+	}
+
+	log.Info("numbered service permits have been sent")
+
+	return response, nil
+}
+
+// userId возвращает UUID учетной записи, которой принадлежит токен сессии из метаданных запроса.
+func (h *Handler) userId(ctx context.Context) (uuid.UUID, error) {
+	token, _ := session.Token(ctx)
+
+	return h.service.UserUUIDFromSession(ctx, token)
+}
+
+// statusError преобразует ошибку сервисного слоя в ошибку gRPC с соответствующим кодом. Клиенту передаётся только
+// сообщение ошибки без места её возникновения. Для ошибок, не имеющих отдельного кода, используется переданный код.
+func statusError(err error, fallback codes.Code) error {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, "request timed out")
+	case errors.Is(err, serviceErr.ErrAuthenticationData):
+		return status.Error(codes.Unauthenticated, serviceErr.ErrAuthenticationData.Message)
+	case errors.Is(err, serviceErr.ErrNotEnabledAccount):
+		return status.Error(codes.Unauthenticated, serviceErr.ErrNotEnabledAccount.Message)
+	case errors.Is(err, serviceErr.ErrEmptyResult):
+		return status.Error(codes.NotFound, serviceErr.ErrEmptyResult.Message)
+	case errors.Is(err, serviceErr.ErrNothingWasChanged):
+		return status.Error(codes.NotFound, serviceErr.ErrNothingWasChanged.Message)
+	case errors.Is(err, serviceErr.ErrAlreadyExist):
+		return status.Error(codes.AlreadyExists, serviceErr.ErrAlreadyExist.Message)
+	case errors.Is(err, serviceErr.ErrRetiredNumber):
+		return status.Error(codes.FailedPrecondition, serviceErr.ErrRetiredNumber.Message)
+	case errors.Is(err, serviceErr.ErrDeprecatedPermission):
+		return status.Error(codes.FailedPrecondition, serviceErr.ErrDeprecatedPermission.Message)
+	case errors.Is(err, serviceErr.ErrRoleCycle):
+		return status.Error(codes.FailedPrecondition, serviceErr.ErrRoleCycle.Message)
+	case errors.Is(err, serviceErr.ErrGroupCycle):
+		return status.Error(codes.FailedPrecondition, serviceErr.ErrGroupCycle.Message)
+	case errors.Is(err, serviceErr.ErrDeletedEntity):
+		return status.Error(codes.FailedPrecondition, serviceErr.ErrDeletedEntity.Message)
+	case errors.Is(err, serviceErr.ErrAdministratorDeletion):
+		return status.Error(codes.FailedPrecondition, serviceErr.ErrAdministratorDeletion.Message)
+	case errors.Is(err, serviceErr.ErrOwnAccountDeletion):
+		return status.Error(codes.FailedPrecondition, serviceErr.ErrOwnAccountDeletion.Message)
+	case errors.Is(err, serviceErr.ErrInvalidQueryParameters):
+		return status.Error(codes.InvalidArgument, serviceErr.ErrInvalidQueryParameters.Message)
+	default:
+		return status.Error(fallback, "unable to process request")
+	}
+}
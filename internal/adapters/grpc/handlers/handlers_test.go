@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/lazylex/watch-store/secure/internal/adapters/grpc/interceptors/token_checker"
+	"github.com/lazylex/watch-store/secure/internal/config"
+	"github.com/lazylex/watch-store/secure/internal/domain/value_objects/account_state"
+	"github.com/lazylex/watch-store/secure/internal/dto"
+	"github.com/lazylex/watch-store/secure/internal/errors/joint"
+	mockservice "github.com/lazylex/watch-store/secure/internal/ports/metrics/service/mocks"
+	mockjoint "github.com/lazylex/watch-store/secure/internal/ports/repository/joint/mocks"
+	"github.com/lazylex/watch-store/secure/internal/service"
+	"github.com/lazylex/watch-store/secure/pkg/securepb"
+	"github.com/lazylex/watch-store/secure/pkg/totp"
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"net"
+	"testing"
+	"time"
+)
+
+// client запускает gRPC-сервер с сервисом Secure и проверкой токена сессии поверх соединения в памяти и возвращает
+// клиента этого сервера.
+func client(t *testing.T, repo *mockjoint.MockInterface, metrics *mockservice.MockMetricsInterface) securepb.SecureClient {
+	domainService := service.MustCreate(metrics, repo,
+		config.Secure{LoginTokenLength: 24, PasswordCreationCost: bcrypt.MinCost})
+
+	listener := bufconn.Listen(1024 * 1024)
+	srv := grpc.NewServer(grpc.UnaryInterceptor(token_checker.New(domainService).Checker))
+	securepb.RegisterSecureServer(srv, New(domainService, time.Second))
+	go func() { _ = srv.Serve(listener) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return securepb.NewSecureClient(conn)
+}
+
+func TestHandler_LoginWithSecondFactor(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	c := client(t, repo, metrics)
+	id := uuid.New()
+	hash, _ := bcrypt.GenerateFromPassword([]byte("Correct_password"), bcrypt.MinCost)
+	secret, _ := totp.GenerateSecret()
+	var challenge string
+
+	repo.EXPECT().AccountState(gomock.Any(), gomock.Any()).Times(1).Return(account_state.State(account_state.Enabled), nil)
+	repo.EXPECT().UserIdAndPasswordHash(gomock.Any(), gomock.Any()).Times(1).Return(dto.UserIdHash{UserId: id, Hash: string(hash)}, nil)
+	repo.EXPECT().TOTPSecret(gomock.Any(), id).Times(2).Return(dto.SecretEnabled{Secret: secret, Enabled: true}, nil)
+	repo.EXPECT().SaveLoginChallenge(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
+		func(_ context.Context, data *dto.UserIdToken) error {
+			challenge = data.Token
+			return nil
+		})
+	repo.EXPECT().UserIdFromLoginChallenge(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
+		func(_ context.Context, token string) (uuid.UUID, error) {
+			if token != challenge {
+				return uuid.Nil, joint.ErrEmptyResult
+			}
+			return id, nil
+		})
+	repo.EXPECT().MarkTOTPStepUsed(gomock.Any(), id, gomock.Any()).Times(1).Return(true, nil)
+	repo.EXPECT().SessionToken(gomock.Any(), id).Times(1).Return("", joint.ErrEmptyResult)
+	repo.EXPECT().SaveSession(gomock.Any(), gomock.Any()).Times(1).Return(nil)
+	metrics.EXPECT().LoginInc().AnyTimes()
+	repo.EXPECT().UpdateAccountLastLogin(gomock.Any(), gomock.Any()).AnyTimes()
+
+	login, err := c.Login(ctx, &securepb.LoginRequest{Login: "user", Password: "Correct_password"})
+	if err != nil || len(login.GetToken()) != 0 || len(login.GetChallenge()) != 24 {
+		t.Fatal(err)
+	}
+
+	code, _ := totp.Code(secret, time.Now())
+	completed, err := c.CompleteLogin(ctx, &securepb.CompleteLoginRequest{Challenge: login.GetChallenge(), Code: code})
+	if err != nil || len(completed.GetToken()) != 24 {
+		t.Fatal(err)
+	}
+}
+
+func TestHandler_CompleteLoginErrInvalidChallenge(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	c := client(t, repo, metrics)
+
+	repo.EXPECT().UserIdFromLoginChallenge(gomock.Any(), "challenge").Times(1).Return(uuid.Nil, joint.ErrEmptyResult)
+
+	_, err := c.CompleteLogin(ctx, &securepb.CompleteLoginRequest{Challenge: "challenge", Code: "000000"})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Fail()
+	}
+}
+
+func TestHandler_CompleteLoginErrEmptyCode(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	c := client(t, mockjoint.NewMockInterface(controller), mockservice.NewMockMetricsInterface(controller))
+
+	_, err := c.CompleteLogin(ctx, &securepb.CompleteLoginRequest{Challenge: "challenge"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fail()
+	}
+}
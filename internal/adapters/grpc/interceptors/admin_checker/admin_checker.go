@@ -0,0 +1,48 @@
+package admin_checker
+
+import (
+	"context"
+	"github.com/lazylex/watch-store/secure/internal/adapters/grpc/session"
+	"github.com/lazylex/watch-store/secure/internal/helpers/prefixes"
+	"github.com/lazylex/watch-store/secure/internal/service"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log/slog"
+	"strings"
+)
+
+// AdminChecker структура, содержащая доступ к сервисной логике.
+type AdminChecker struct {
+	service *service.Service
+}
+
+// New служит для создания interceptor, предназначенного для отклонения вызовов административных методов от учетных
+// записей, не имеющих роли администратора.
+func New(service *service.Service) *AdminChecker {
+	return &AdminChecker{service: service}
+}
+
+// Checker пропускает вызовы методов с префиксом prefixes.GRPCAdminPrefix только при наличии у владельца сессии роли
+// администратора. Наличие токена сессии в запросе должно быть проверено предыдущим interceptor.
+func (a *AdminChecker) Checker(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if !strings.HasPrefix(info.FullMethod, prefixes.GRPCAdminPrefix) {
+		return handler(ctx, req)
+	}
+
+	log := slog.Default().With("remote address", session.RemoteAddress(ctx))
+	token, _ := session.Token(ctx)
+
+	id, err := a.service.UserUUIDFromSession(ctx, token)
+	if err != nil {
+		log.Warn("admin checker interceptor: invalid token")
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	if isAdmin, errCheck := a.service.IsAdmin(ctx, id); errCheck != nil || !isAdmin {
+		log.Warn("admin checker interceptor: account is not admin")
+		return nil, status.Error(codes.PermissionDenied, "account is not admin")
+	}
+
+	return handler(ctx, req)
+}
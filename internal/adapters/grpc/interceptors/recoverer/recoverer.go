@@ -0,0 +1,37 @@
+package recoverer
+
+import (
+	"context"
+	"fmt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log/slog"
+)
+
+// Recoverer interceptor который восстанавливает после паники и заносит данные о причине в лог. Клиенту возвращается
+// статус codes.Internal.
+func Recoverer(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	defer func() {
+		if rvr := recover(); rvr != nil {
+			writeToLog(rvr)
+			err = status.Error(codes.Internal, "internal error")
+		}
+	}()
+
+	return handler(ctx, req)
+}
+
+// writeToLog записывает в лог причину паники.
+func writeToLog(rvr any) {
+	log := slog.Default().With("origin", "grpc recovery interceptor")
+
+	switch t := rvr.(type) {
+	case error:
+		log.Warn("panic error: " + t.Error())
+	case string:
+		log.Warn("panic string: " + t)
+	default:
+		log.Warn(fmt.Sprint(rvr))
+	}
+}
@@ -0,0 +1,32 @@
+package request_metrics
+
+import (
+	"context"
+	"github.com/lazylex/watch-store/secure/internal/metrics"
+	grpcMetrics "github.com/lazylex/watch-store/secure/internal/ports/metrics/grpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+// InterceptorRequests структура, содержащая доступ к метрикам gRPC-запросов.
+type InterceptorRequests struct {
+	metrics grpcMetrics.MetricsInterface
+}
+
+// New конструктор прослойки для gRPC-запросов.
+func New(metrics grpcMetrics.MetricsInterface) *InterceptorRequests {
+	return &InterceptorRequests{metrics: metrics}
+}
+
+// Handle - interceptor, увеличивающий счетчик gRPC-запросов с метками method (полное название метода) и code (код
+// статуса ответа) и сохраняющий длительность выполнения запроса.
+func (i *InterceptorRequests) Handle(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+
+	i.metrics.RequestsDurationObserve(float64(time.Now().UnixMilli()-start.UnixMilli()) * 0.001)
+	i.metrics.RequestsTotalInc(map[string]string{metrics.METHOD: info.FullMethod, metrics.CODE: status.Code(err).String()})
+
+	return resp, err
+}
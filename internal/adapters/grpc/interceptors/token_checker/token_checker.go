@@ -0,0 +1,52 @@
+package token_checker
+
+import (
+	"context"
+	"github.com/lazylex/watch-store/secure/internal/adapters/grpc/session"
+	"github.com/lazylex/watch-store/secure/internal/service"
+	"github.com/lazylex/watch-store/secure/pkg/securepb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log/slog"
+)
+
+// publicMethods методы, доступные без токена сессии.
+var publicMethods = map[string]struct{}{
+	securepb.Secure_Login_FullMethodName:         {},
+	securepb.Secure_CompleteLogin_FullMethodName: {},
+}
+
+// TokenChecker структура, содержащая доступ к сервисной логике.
+type TokenChecker struct {
+	service *service.Service
+}
+
+// New служит для создания interceptor, предназначенного для отклонения запросов, не содержащих токен или не
+// предназначенных для входа в систему.
+func New(service *service.Service) *TokenChecker {
+	return &TokenChecker{service: service}
+}
+
+// Checker проверяет, что вызывается метод, не требующий входа в систему, либо метаданные запроса содержат токен,
+// который соответствует открытой сессии.
+func (t *TokenChecker) Checker(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if _, ok := publicMethods[info.FullMethod]; ok {
+		return handler(ctx, req)
+	}
+
+	log := slog.Default().With("remote address", session.RemoteAddress(ctx))
+
+	token, ok := session.Token(ctx)
+	if !ok {
+		log.Warn("token checker interceptor: empty or incorrect token")
+		return nil, status.Error(codes.Unauthenticated, "empty or incorrect token")
+	}
+
+	if _, err := t.service.UserUUIDFromSession(ctx, token); err != nil {
+		log.Warn("token checker interceptor: invalid token")
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	return handler(ctx, req)
+}
@@ -0,0 +1,97 @@
+package server
+
+import (
+	"context"
+	"github.com/lazylex/watch-store/secure/internal/adapters/grpc/handlers"
+	"github.com/lazylex/watch-store/secure/internal/adapters/grpc/interceptors/admin_checker"
+	"github.com/lazylex/watch-store/secure/internal/adapters/grpc/interceptors/recoverer"
+	requestMetrics "github.com/lazylex/watch-store/secure/internal/adapters/grpc/interceptors/request_metrics"
+	"github.com/lazylex/watch-store/secure/internal/adapters/grpc/interceptors/token_checker"
+	"github.com/lazylex/watch-store/secure/internal/config"
+	"github.com/lazylex/watch-store/secure/internal/metrics"
+	"github.com/lazylex/watch-store/secure/internal/service"
+	"github.com/lazylex/watch-store/secure/internal/tls_config"
+	"github.com/lazylex/watch-store/secure/pkg/securepb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"log/slog"
+	"net"
+	"os"
+)
+
+// Server структура для обработки gRPC-запросов к приложению.
+type Server struct {
+	cfg *config.GrpcServer // Конфигурация gRPC сервера
+	srv *grpc.Server       // gRPC сервер с зарегистрированными обработчиками
+}
+
+// MustCreate возвращает готовый к запуску gRPC-сервер (запуск осуществляется функцией MustRun). Сервер использует тот
+// же сервисный слой, что и http-сервер. Если какой-либо из переданных параметров равен nil, работа приложения
+// завершается.
+func MustCreate(domainService *service.Service, cfg *config.GrpcServer, m *metrics.Metrics) *Server {
+	if domainService == nil || cfg == nil || m == nil {
+		slog.Error("domain service or cfg is nil")
+		os.Exit(1)
+	}
+
+	tokenInterceptor := token_checker.New(domainService)
+	adminInterceptor := admin_checker.New(domainService)
+	metricsInterceptor := requestMetrics.New(m.GRPC)
+
+	options := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(
+			recoverer.Recoverer,
+			metricsInterceptor.Handle,
+			tokenInterceptor.Checker,
+			adminInterceptor.Checker,
+		),
+	}
+
+	if cfg.TLS.Enabled() {
+		options = append(options, grpc.Creds(credentials.NewTLS(tls_config.MustCreate(&cfg.TLS))))
+	}
+
+	srv := grpc.NewServer(options...)
+	securepb.RegisterSecureServer(srv, handlers.New(domainService, cfg.GrpcRequestTimeout))
+	securepb.RegisterAdminServer(srv, handlers.NewAdmin(domainService, cfg.GrpcRequestTimeout))
+
+	return &Server{cfg: cfg, srv: srv}
+}
+
+// MustRun производит запуск сервера в отдельной go-рутине. В случае ошибки останавливает работу приложения.
+func (s *Server) MustRun() {
+	listener, err := net.Listen("tcp", s.cfg.GrpcAddress)
+	if err != nil {
+		slog.Error("grpc server err: unable to listen. Initial error: " + err.Error())
+		os.Exit(1)
+	}
+
+	go func() {
+		slog.Info("start grpc server on " + s.cfg.GrpcAddress)
+		if err = s.srv.Serve(listener); err != nil {
+			slog.Error("grpc server err: startup error. Initial error: " + err.Error())
+			os.Exit(1)
+		}
+	}()
+}
+
+// Shutdown производит остановку сервера. Если активные запросы не завершились за время GrpcShutdownTimeout, сервер
+// останавливается принудительно.
+func (s *Server) Shutdown() {
+	ctx, cancel := context.WithTimeout(context.Background(), s.cfg.GrpcShutdownTimeout)
+	defer cancel()
+
+	stopped := make(chan struct{})
+	go func() {
+		s.srv.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		slog.Info("gracefully shut down grpc server")
+	case <-ctx.Done():
+		s.srv.Stop()
+		slog.Error("failed to gracefully shutdown grpc server")
+	}
+}
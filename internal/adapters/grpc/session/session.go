@@ -0,0 +1,37 @@
+package session
+
+import (
+	"context"
+	v "github.com/lazylex/watch-store/secure/internal/helpers/constants/various"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"strings"
+)
+
+// authorizationKey ключ метаданных gRPC-запроса, содержащий токен сессии. В метаданных ключи хранятся в нижнем регистре.
+const authorizationKey = "authorization"
+
+// Token возвращает токен сессии из метаданных входящего gRPC-запроса. Токен передаётся так же, как в заголовке
+// Authorization HTTP-запроса - с префиксом "Bearer ". Если токен не передан, второе возвращаемое значение равно false.
+func Token(ctx context.Context) (string, bool) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", false
+	}
+
+	values := md.Get(authorizationKey)
+	if len(values) == 0 || !strings.HasPrefix(values[0], v.BearerTokenPrefix) {
+		return "", false
+	}
+
+	return values[0][len(v.BearerTokenPrefix):], true
+}
+
+// RemoteAddress возвращает адрес клиента gRPC-запроса для записи в лог и проверки условий назначений.
+func RemoteAddress(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		return p.Addr.String()
+	}
+
+	return ""
+}
@@ -0,0 +1,25 @@
+package session
+
+import (
+	"context"
+	"google.golang.org/grpc/metadata"
+	"testing"
+)
+
+func TestToken(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("Authorization", "Bearer session"))
+	if token, ok := Token(ctx); !ok || token != "session" {
+		t.Fail()
+	}
+}
+
+func TestTokenWithoutPrefix(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "session"))
+	if _, ok := Token(ctx); ok {
+		t.Fail()
+	}
+
+	if _, ok := Token(context.Background()); ok {
+		t.Fail()
+	}
+}
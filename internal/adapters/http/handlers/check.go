@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/lazylex/watch-store/secure/internal/dto"
+	serviceErr "github.com/lazylex/watch-store/secure/internal/errors/service"
+	v "github.com/lazylex/watch-store/secure/internal/helpers/constants/various"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+// maxBatchChecks максимальное количество проверок в одном запросе к CheckPermissions.
+const maxBatchChecks = 100
+
+// CheckPermission отвечает, имеет ли учетная запись (параметр user_id) разрешение с номером permission для экземпляра
+// сервиса instance. Возвращает в JSON решение (allowed) и пути, по которым разрешение назначено (reasons): instance,
+// role, group, group_role или condition. Условия назначений вычисляются для адреса клиента, как при выдаче токена.
+// Разрешения другой учетной записи могут проверять только администраторы и учетные записи сервисов.
+func (h *Handler) CheckPermission(w http.ResponseWriter, r *http.Request) {
+	if !allowedOnlyMethod(http.MethodPost, w, r) {
+		return
+	}
+
+	var (
+		err      error
+		caller   uuid.UUID
+		id       uuid.UUID
+		number   int
+		decision dto.PermissionDecision
+		log      = slog.Default().With("remote address", r.RemoteAddr)
+	)
+
+	instance := r.PostFormValue("instance")
+	id, err = uuid.Parse(r.PostFormValue("user_id"))
+	if err == nil {
+		number, err = strconv.Atoi(r.PostFormValue("permission"))
+	}
+	if err != nil || len(instance) == 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		log.Warn("unable to get user id, instance or permission")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.queryTimeout)
+	defer cancel()
+
+	if caller, err = h.service.UserUUIDFromSession(ctx, r.Header.Get("Authorization")[len(v.BearerTokenPrefix):]); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		log.Warn("unable to get user uuid from session")
+		return
+	}
+
+	if decision, err = h.service.CheckPermission(ctx, caller, r.RemoteAddr, &dto.UserIdInstanceNumber{UserId: id, Instance: instance, Number: number}); err != nil {
+		writeServiceError(w, err, log, "unable to check permission")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, decision, log)
+
+	log.Info("permission checked")
+}
+
+// CheckPermissions выполняет пакетную проверку разрешений. Принимает в теле запроса JSON-массив объектов с полями
+// user_id, instance и permission (не более maxBatchChecks) и возвращает массив решений в том же порядке. Разрешения
+// других учетных записей могут проверять только администраторы и учетные записи сервисов.
+func (h *Handler) CheckPermissions(w http.ResponseWriter, r *http.Request) {
+	if !allowedOnlyMethod(http.MethodPost, w, r) {
+		return
+	}
+
+	var (
+		err       error
+		caller    uuid.UUID
+		checks    []dto.UserIdInstanceNumber
+		decisions []dto.PermissionDecision
+		log       = slog.Default().With("remote address", r.RemoteAddr)
+	)
+
+	if err = json.NewDecoder(r.Body).Decode(&checks); err != nil || len(checks) == 0 || len(checks) > maxBatchChecks {
+		w.WriteHeader(http.StatusBadRequest)
+		log.Warn("unable to decode permission checks")
+		return
+	}
+
+	for _, check := range checks {
+		if check.UserId == uuid.Nil || len(check.Instance) == 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			log.Warn("empty user id or instance in permission check")
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.queryTimeout)
+	defer cancel()
+
+	if caller, err = h.service.UserUUIDFromSession(ctx, r.Header.Get("Authorization")[len(v.BearerTokenPrefix):]); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		log.Warn("unable to get user uuid from session")
+		return
+	}
+
+	if decisions, err = h.service.CheckPermissions(ctx, caller, r.RemoteAddr, checks); err != nil {
+		writeServiceError(w, err, log, "unable to check permission")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, decisions, log)
+
+	log.Info("permissions checked")
+}
+
+// ExplainPermissions возвращает в JSON разрешения учетной записи (параметр user_id) для сервиса (параметр service) или
+// экземпляра сервиса (параметр instance) с каждым из путей, по которым разрешение назначено. Условия назначений
+// вычисляются для адреса клиента из необязательного параметра address.
+func (h *Handler) ExplainPermissions(w http.ResponseWriter, r *http.Request) {
+	if !allowedOnlyMethod(http.MethodGet, w, r) {
+		return
+	}
+
+	var (
+		err         error
+		permissions []dto.NumberNamePaths
+		log         = slog.Default().With("remote address", r.RemoteAddr)
+	)
+
+	request := dto.UserIdServiceInstance{Service: r.FormValue("service"), Instance: r.FormValue("instance"),
+		Address: r.FormValue("address")}
+	if request.UserId, err = uuid.Parse(r.FormValue("user_id")); err != nil || len(request.Service)+len(request.Instance) == 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		log.Warn("unable to get user id, service or instance")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.queryTimeout)
+	defer cancel()
+
+	if permissions, err = h.service.ExplainPermissions(ctx, &request); err != nil {
+		if errors.Is(err, serviceErr.ErrEmptyResult) {
+			w.WriteHeader(http.StatusNotFound)
+			log.Warn("instance not found")
+		} else {
+			writeServiceError(w, err, log, "unable to explain permissions")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, permissions, log)
+
+	log.Info("permissions explained")
+}
+
+// writeServiceError записывает в ответ статус, соответствующий ошибке сервиса: 408 при истечении времени запроса, 403 при
+// проверке чужих разрешений без прав на неё и 500 в остальных случаях, и записывает в журнал переданное сообщение.
+func writeServiceError(w http.ResponseWriter, err error, log *slog.Logger, message string) {
+	if errors.Is(err, context.DeadlineExceeded) {
+		w.WriteHeader(http.StatusRequestTimeout)
+		log.Warn("request timed out")
+	} else if errors.Is(err, serviceErr.ErrForeignPermissionCheck) {
+		w.WriteHeader(http.StatusForbidden)
+		log.Warn("permission check of another account is not allowed")
+	} else {
+		w.WriteHeader(http.StatusInternalServerError)
+		log.Warn(message)
+	}
+}
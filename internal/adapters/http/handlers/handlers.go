@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"context"
+	"crypto/x509"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -14,6 +15,8 @@ import (
 	"github.com/lazylex/watch-store/secure/internal/service"
 	"log/slog"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 )
 
@@ -31,6 +34,8 @@ func New(domainService *service.Service, timeout time.Duration) *Handler {
 }
 
 // Login производит вход в учетную запись и возвращает в JSON токен сессии(по ключу token). Тип авторизации - Basic Auth.
+// Если для учетной записи включена двухфакторная аутентификация, вместо токена сессии возвращается токен
+// незавершенного входа (по ключу challenge), который передаётся в LoginSecondFactor вместе с одноразовым паролем.
 func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 	if !allowedOnlyMethod(http.MethodPost, w, r) {
 		return
@@ -61,7 +66,11 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 	defer cancel()
 
 	if token, err = h.service.Login(ctx, &dto.LoginPassword{Login: userLogin, Password: userPassword}); err != nil {
-		if errors.Is(err, context.DeadlineExceeded) {
+		if errors.Is(err, serviceErr.ErrSecondFactorRequired) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(fmt.Sprintf("{\"challenge\":\"%s\"}", token)))
+			log.Info("second authentication factor required")
+		} else if errors.Is(err, context.DeadlineExceeded) {
 			w.WriteHeader(http.StatusRequestTimeout)
 			log.Warn("request timed out")
 		} else {
@@ -78,6 +87,111 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 	log.Info("successfully logged in")
 }
 
+// LoginSecondFactor завершает вход в учетную запись с двухфакторной аутентификацией. Принимает токен незавершенного
+// входа (параметр challenge) и одноразовый пароль или код восстановления (параметр code). Возвращает в JSON токен
+// сессии (по ключу token).
+func (h *Handler) LoginSecondFactor(w http.ResponseWriter, r *http.Request) {
+	if !allowedOnlyMethod(http.MethodPost, w, r) {
+		return
+	}
+
+	var (
+		err   error
+		token string
+		log   = slog.Default().With("remote address", r.RemoteAddr)
+	)
+
+	challenge := r.FormValue("challenge")
+	code := r.FormValue("code")
+
+	if len(challenge) == 0 || len(code) == 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		log.Warn("unable to get challenge or code")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.queryTimeout)
+	defer cancel()
+
+	if token, err = h.service.CompleteLogin(ctx, &dto.TokenCode{Token: challenge, Code: code}); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			w.WriteHeader(http.StatusRequestTimeout)
+			log.Warn("request timed out")
+		} else {
+			w.WriteHeader(http.StatusUnauthorized)
+			log.Warn("unable to complete login")
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte(fmt.Sprintf("{\"token\":\"%s\"}", token)))
+
+	log.Info("successfully logged in with second factor")
+}
+
+// CertificateLogin производит вход в учетную запись по клиентскому сертификату, проверенному при установке
+// TLS-соединения, и возвращает в JSON токен сессии (по ключу token). Логин учетной записи берётся из первого DNS-имени
+// в SAN сертификата, а при его отсутствии - из CommonName.
+func (h *Handler) CertificateLogin(w http.ResponseWriter, r *http.Request) {
+	if !allowedOnlyMethod(http.MethodPost, w, r) {
+		return
+	}
+
+	var (
+		err   error
+		token string
+		log   = slog.Default().With("remote address", r.RemoteAddr)
+	)
+
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		w.WriteHeader(http.StatusUnauthorized)
+		log.Warn("no verified client certificate")
+		return
+	}
+
+	userLogin := certificateLogin(r.TLS.VerifiedChains[0][0])
+	if userLogin.Validate() != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		log.Warn("unable to get login from client certificate")
+		return
+	}
+
+	log = log.With("login", userLogin)
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.queryTimeout)
+	defer cancel()
+
+	if token, err = h.service.LoginWithCertificate(ctx, userLogin); err != nil {
+		if errors.Is(err, serviceErr.ErrSecondFactorRequired) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(fmt.Sprintf("{\"challenge\":\"%s\"}", token)))
+			log.Info("second authentication factor required")
+		} else if errors.Is(err, context.DeadlineExceeded) {
+			w.WriteHeader(http.StatusRequestTimeout)
+			log.Warn("request timed out")
+		} else {
+			w.WriteHeader(http.StatusUnauthorized)
+			log.Warn("unable to login with client certificate")
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte(fmt.Sprintf("{\"token\":\"%s\"}", token)))
+
+	log.Info("successfully logged in with client certificate")
+}
+
+// certificateLogin возвращает логин учетной записи, которой соответствует клиентский сертификат.
+func certificateLogin(cert *x509.Certificate) login.Login {
+	if len(cert.DNSNames) > 0 {
+		return login.Login(cert.DNSNames[0])
+	}
+
+	return login.Login(cert.Subject.CommonName)
+}
+
 // Index обработчик для несуществующих страниц.
 func (h *Handler) Index(w http.ResponseWriter, r *http.Request) {
 	if r.URL.Path != "/" {
@@ -147,7 +261,8 @@ func (h *Handler) TokenWithPermissions(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if token, err = h.service.CreateToken(ctx, &dto.UserIdInstance{UserId: id, Instance: instance}); err != nil {
+	if token, err = h.service.CreateToken(ctx,
+		&dto.UserIdInstanceAddress{UserId: id, Instance: instance, Address: r.RemoteAddr}); err != nil {
 		if errors.Is(err, serviceErr.ErrEmptyResult) {
 			w.WriteHeader(http.StatusNoContent)
 			slog.Warn("no token for return")
@@ -164,6 +279,227 @@ func (h *Handler) TokenWithPermissions(w http.ResponseWriter, r *http.Request) {
 	log.Info("sent jwt-token")
 }
 
+// OAuthToken выдаёт JWT-токен с разрешениями по протоколу OAuth 2.0 (RFC 6749, раздел 4.4) для grant_type
+// client_credentials. Клиентом является учетная запись, аутентификация которой производится через Basic Auth или
+// параметры client_id и client_secret. Экземпляр сервиса, для которого выдаётся токен, передаётся в параметре scope
+// или audience. Токен сессии не требуется и не создаётся.
+func (h *Handler) OAuthToken(w http.ResponseWriter, r *http.Request) {
+	if !allowedOnlyMethod(http.MethodPost, w, r) {
+		return
+	}
+
+	var (
+		err      error
+		answer   []byte
+		token    dto.TokenTTL
+		clientId string
+		secret   string
+		log      = slog.Default().With("remote address", r.RemoteAddr)
+	)
+
+	if r.FormValue("grant_type") != "client_credentials" {
+		writeOAuthError(w, http.StatusBadRequest, "unsupported_grant_type")
+		log.Warn("unsupported grant type")
+		return
+	}
+
+	user, pwd, basic := r.BasicAuth()
+	if basic {
+		// RFC 6749, раздел 2.3.1: перед кодированием в Base64 идентификатор и секрет кодируются как form-urlencoded
+		clientId, err = url.QueryUnescape(user)
+		if err == nil {
+			secret, err = url.QueryUnescape(pwd)
+		}
+		if err != nil || len(r.PostFormValue("client_id")) > 0 || len(r.PostFormValue("client_secret")) > 0 {
+			writeOAuthError(w, http.StatusBadRequest, "invalid_request")
+			log.Warn("invalid client authentication")
+			return
+		}
+	} else {
+		clientId, secret = r.PostFormValue("client_id"), r.PostFormValue("client_secret")
+	}
+
+	clientLogin := login.Login(clientId)
+	clientPassword := password.Password(secret)
+	if clientLogin.Validate() != nil || clientPassword.Validate() != nil {
+		writeOAuthInvalidClient(w, basic)
+		log.Warn("unable to validate client credentials")
+		return
+	}
+
+	instance := r.FormValue("scope")
+	if len(instance) == 0 {
+		instance = r.FormValue("audience")
+	}
+	if len(instance) == 0 || strings.ContainsRune(instance, ' ') {
+		writeOAuthError(w, http.StatusBadRequest, "invalid_scope")
+		log.Warn("scope must contain exactly one instance")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.queryTimeout)
+	defer cancel()
+
+	data := dto.LoginPasswordInstance{Login: clientLogin, Password: clientPassword, Instance: instance,
+		Address: r.RemoteAddr}
+	if token, err = h.service.ClientCredentialsToken(ctx, &data); err != nil {
+		switch {
+		case errors.Is(err, context.DeadlineExceeded):
+			w.WriteHeader(http.StatusRequestTimeout)
+			log.Warn("request timed out")
+		case errors.Is(err, serviceErr.ErrAuthenticationData), errors.Is(err, serviceErr.ErrNotEnabledAccount),
+			errors.Is(err, serviceErr.ErrSecondFactorRequired):
+			writeOAuthInvalidClient(w, basic)
+			log.Warn("invalid client")
+		case errors.Is(err, serviceErr.ErrEmptyResult):
+			writeOAuthError(w, http.StatusBadRequest, "invalid_scope")
+			log.Warn("unknown instance")
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+			log.Warn("error create token")
+		}
+		return
+	}
+
+	if answer, err = json.Marshal(map[string]any{
+		"access_token": token.Token,
+		"token_type":   "Bearer",
+		"expires_in":   int64(token.TTL.Seconds()),
+		"scope":        instance,
+	}); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		log.Warn("unable to marshal token")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+	_, _ = w.Write(answer)
+
+	log.Info("sent oauth access token")
+}
+
+// writeOAuthError записывает ответ с ошибкой в формате RFC 6749, раздел 5.2.
+func writeOAuthError(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte(fmt.Sprintf("{\"error\":\"%s\"}", code)))
+}
+
+// writeOAuthInvalidClient записывает ответ с ошибкой invalid_client. Если клиент использовал Basic Auth, возвращается
+// статус http.StatusUnauthorized с заголовком WWW-Authenticate.
+func writeOAuthInvalidClient(w http.ResponseWriter, basic bool) {
+	if basic {
+		w.Header().Set("WWW-Authenticate", "Basic realm=\"secure\"")
+		writeOAuthError(w, http.StatusUnauthorized, "invalid_client")
+	} else {
+		writeOAuthError(w, http.StatusBadRequest, "invalid_client")
+	}
+}
+
+// IntrospectToken возвращает в JSON результат проверки JWT-токена с разрешениями (параметр token) по RFC 7662. Для
+// действующего токена возвращаются active равный true, UUID учетной записи (sub), экземпляр сервиса (aud), сервис,
+// названия разрешений, время выдачи и окончания действия токена. Для недействительного токена возвращается только
+// active равный false.
+func (h *Handler) IntrospectToken(w http.ResponseWriter, r *http.Request) {
+	if !allowedOnlyMethod(http.MethodPost, w, r) {
+		return
+	}
+
+	var (
+		err    error
+		answer []byte
+		result dto.TokenIntrospection
+		log    = slog.Default().With("remote address", r.RemoteAddr)
+	)
+
+	token := r.PostFormValue("token")
+	if len(token) == 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		log.Warn("unable to get token")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.queryTimeout)
+	defer cancel()
+
+	if result, err = h.service.IntrospectToken(ctx, token); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			w.WriteHeader(http.StatusRequestTimeout)
+			log.Warn("request timed out")
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+			log.Warn("unable to introspect token")
+		}
+		return
+	}
+
+	if result.Active {
+		answer, err = json.Marshal(result)
+	} else {
+		answer, err = json.Marshal(map[string]bool{"active": false})
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		log.Warn("unable to marshal introspection result")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	_, _ = w.Write(answer)
+
+	log.Info("token introspected")
+}
+
+// RevokeToken отзывает JWT-токен с разрешениями (параметр token) по RFC 7009. Отозвать можно только токен, выданный
+// учетной записи, которой принадлежит сессия, если она не является администратором. Недействительный токен не считается
+// ошибкой.
+func (h *Handler) RevokeToken(w http.ResponseWriter, r *http.Request) {
+	if !allowedOnlyMethod(http.MethodPost, w, r) {
+		return
+	}
+
+	var (
+		err error
+		id  uuid.UUID
+		log = slog.Default().With("remote address", r.RemoteAddr)
+	)
+
+	token := r.PostFormValue("token")
+	if len(token) == 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		log.Warn("unable to get token")
+		return
+	}
+
+	session := r.Header.Get("Authorization")[len(v.BearerTokenPrefix):]
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.queryTimeout)
+	defer cancel()
+
+	if id, err = h.service.UserUUIDFromSession(ctx, session); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		log.Warn("unable to get user uuid from session")
+		return
+	}
+
+	if err = h.service.RevokeToken(ctx, id, token); err != nil {
+		if errors.Is(err, serviceErr.ErrNotTokenOwner) {
+			w.WriteHeader(http.StatusForbidden)
+			log.Warn("attempt to revoke token of another account")
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+			log.Warn("unable to revoke token")
+		}
+		return
+	}
+
+	log.Info("token revoked")
+}
+
 // ServiceNumberedPermissions возвращает JSON с названиями и номерами разрешений для переданного в параметре service
 // сервиса. При отсутствии разрешений возвращает статус http.StatusNoContent.
 // Пример возвращаемого функцией JSON:
@@ -225,6 +561,298 @@ func (h *Handler) ServiceNumberedPermissions(w http.ResponseWriter, r *http.Requ
 	}
 }
 
+// ChangePassword меняет пароль учетной записи, которой принадлежит сессия. Текущий и новый пароли передаются в
+// параметрах old_password и new_password. После смены пароля сессия закрывается.
+func (h *Handler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	if !allowedOnlyMethod(http.MethodPost, w, r) {
+		return
+	}
+
+	var (
+		err error
+		id  uuid.UUID
+		log = slog.Default().With("remote address", r.RemoteAddr)
+	)
+
+	oldPassword := password.Password(r.FormValue("old_password"))
+	newPassword := password.Password(r.FormValue("new_password"))
+
+	if len(oldPassword) == 0 || newPassword.Validate() != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		log.Warn("unable to validate passwords")
+		return
+	}
+
+	token := r.Header.Get("Authorization")[len(v.BearerTokenPrefix):]
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.queryTimeout)
+	defer cancel()
+
+	if id, err = h.service.UserUUIDFromSession(ctx, token); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		log.Warn("unable to get user uuid from session")
+		return
+	}
+
+	if err = h.service.ChangePassword(ctx, &dto.UserIdOldNewPassword{
+		UserId:      id,
+		OldPassword: oldPassword,
+		NewPassword: newPassword,
+	}); err != nil {
+		switch {
+		case errors.Is(err, context.DeadlineExceeded):
+			w.WriteHeader(http.StatusRequestTimeout)
+			log.Warn("request timed out")
+		case errors.Is(err, serviceErr.ErrAuthenticationData):
+			w.WriteHeader(http.StatusUnauthorized)
+			log.Warn("incorrect old password")
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+			log.Warn("unable to change password")
+		}
+		return
+	}
+
+	log.Info("password changed")
+}
+
+// ResetPassword создаёт одноразовый токен сброса пароля для учетной записи, логин которой передан в параметре login, и
+// возвращает его в JSON (по ключу reset-token). Открытая сессия учетной записи закрывается. Доступно только
+// администраторам.
+func (h *Handler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	if !allowedOnlyMethod(http.MethodPost, w, r) {
+		return
+	}
+
+	var (
+		err   error
+		token string
+		log   = slog.Default().With("remote address", r.RemoteAddr)
+	)
+
+	accountLogin := login.Login(r.FormValue("login"))
+
+	if accountLogin.Validate() != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		log.Warn("unable to validate login")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.queryTimeout)
+	defer cancel()
+
+	if token, err = h.service.ResetPassword(ctx, accountLogin); err != nil {
+		if errors.Is(err, serviceErr.ErrEmptyResult) {
+			w.WriteHeader(http.StatusNotFound)
+			log.Warn("account not found")
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+			log.Warn("unable to reset password")
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte(fmt.Sprintf("{\"reset-token\":\"%s\"}", token)))
+
+	log.Info("password reset token issued")
+}
+
+// CompletePasswordReset устанавливает новый пароль (параметр password) по одноразовому токену сброса пароля (параметр
+// token). Токен сессии для этого запроса не требуется.
+func (h *Handler) CompletePasswordReset(w http.ResponseWriter, r *http.Request) {
+	if !allowedOnlyMethod(http.MethodPost, w, r) {
+		return
+	}
+
+	log := slog.Default().With("remote address", r.RemoteAddr)
+	token := r.FormValue("token")
+	newPassword := password.Password(r.FormValue("password"))
+
+	if len(token) == 0 || newPassword.Validate() != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		log.Warn("unable to validate reset token or password")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.queryTimeout)
+	defer cancel()
+
+	if err := h.service.CompletePasswordReset(ctx, &dto.TokenPassword{Token: token, Password: newPassword}); err != nil {
+		if errors.Is(err, serviceErr.ErrInvalidResetToken) {
+			w.WriteHeader(http.StatusUnauthorized)
+			log.Warn("invalid reset token")
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+			log.Warn("unable to set new password")
+		}
+		return
+	}
+
+	log.Info("password reset completed")
+}
+
+// EnrollTOTP создаёт секрет одноразовых паролей для учетной записи, которой принадлежит сессия, и возвращает в JSON
+// секрет (по ключу secret) и ссылку otpauth:// для приложения-аутентификатора (по ключу uri).
+func (h *Handler) EnrollTOTP(w http.ResponseWriter, r *http.Request) {
+	if !allowedOnlyMethod(http.MethodPost, w, r) {
+		return
+	}
+
+	var (
+		err    error
+		id     uuid.UUID
+		answer []byte
+		secret dto.SecretURI
+		log    = slog.Default().With("remote address", r.RemoteAddr)
+	)
+
+	token := r.Header.Get("Authorization")[len(v.BearerTokenPrefix):]
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.queryTimeout)
+	defer cancel()
+
+	if id, err = h.service.UserUUIDFromSession(ctx, token); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		log.Warn("unable to get user uuid from session")
+		return
+	}
+
+	if secret, err = h.service.EnrollTOTP(ctx, id); err != nil {
+		if errors.Is(err, serviceErr.ErrTOTPAlreadyEnabled) {
+			w.WriteHeader(http.StatusConflict)
+			log.Warn("two-factor authentication already enabled")
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+			log.Warn("unable to enroll totp")
+		}
+		return
+	}
+
+	if answer, err = json.Marshal(secret); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		log.Warn("unable to marshal totp secret")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(answer)
+
+	log.Info("totp secret created")
+}
+
+// ConfirmTOTP включает двухфакторную аутентификацию после проверки одноразового пароля (параметр code) и возвращает
+// в JSON коды восстановления (по ключу recovery_codes).
+func (h *Handler) ConfirmTOTP(w http.ResponseWriter, r *http.Request) {
+	if !allowedOnlyMethod(http.MethodPost, w, r) {
+		return
+	}
+
+	var (
+		err    error
+		id     uuid.UUID
+		answer []byte
+		codes  []string
+		log    = slog.Default().With("remote address", r.RemoteAddr)
+	)
+
+	code := r.FormValue("code")
+	if len(code) == 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		log.Warn("unable to get code")
+		return
+	}
+
+	token := r.Header.Get("Authorization")[len(v.BearerTokenPrefix):]
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.queryTimeout)
+	defer cancel()
+
+	if id, err = h.service.UserUUIDFromSession(ctx, token); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		log.Warn("unable to get user uuid from session")
+		return
+	}
+
+	if codes, err = h.service.ConfirmTOTP(ctx, &dto.UserIdCode{UserId: id, Code: code}); err != nil {
+		switch {
+		case errors.Is(err, serviceErr.ErrInvalidSecondFactor):
+			w.WriteHeader(http.StatusUnauthorized)
+			log.Warn("invalid one-time password")
+		case errors.Is(err, serviceErr.ErrTOTPNotEnrolled):
+			w.WriteHeader(http.StatusNotFound)
+			log.Warn("two-factor authentication is not enrolled")
+		case errors.Is(err, serviceErr.ErrTOTPAlreadyEnabled):
+			w.WriteHeader(http.StatusConflict)
+			log.Warn("two-factor authentication already enabled")
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+			log.Warn("unable to confirm totp")
+		}
+		return
+	}
+
+	if answer, err = json.Marshal(map[string][]string{"recovery_codes": codes}); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		log.Warn("unable to marshal recovery codes")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(answer)
+
+	log.Info("two-factor authentication enabled")
+}
+
+// DisableTOTP отключает двухфакторную аутентификацию после проверки одноразового пароля или кода восстановления
+// (параметр code).
+func (h *Handler) DisableTOTP(w http.ResponseWriter, r *http.Request) {
+	if !allowedOnlyMethod(http.MethodPost, w, r) {
+		return
+	}
+
+	var (
+		err error
+		id  uuid.UUID
+		log = slog.Default().With("remote address", r.RemoteAddr)
+	)
+
+	code := r.FormValue("code")
+	if len(code) == 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		log.Warn("unable to get code")
+		return
+	}
+
+	token := r.Header.Get("Authorization")[len(v.BearerTokenPrefix):]
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.queryTimeout)
+	defer cancel()
+
+	if id, err = h.service.UserUUIDFromSession(ctx, token); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		log.Warn("unable to get user uuid from session")
+		return
+	}
+
+	if err = h.service.DisableTOTP(ctx, &dto.UserIdCode{UserId: id, Code: code}); err != nil {
+		switch {
+		case errors.Is(err, serviceErr.ErrInvalidSecondFactor):
+			w.WriteHeader(http.StatusUnauthorized)
+			log.Warn("invalid one-time password or recovery code")
+		case errors.Is(err, serviceErr.ErrTOTPNotEnrolled):
+			w.WriteHeader(http.StatusNotFound)
+			log.Warn("two-factor authentication is not enrolled")
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+			log.Warn("unable to disable totp")
+		}
+		return
+	}
+
+	log.Info("two-factor authentication disabled")
+}
+
 // allowedOnlyMethod принимает разрешенный метод и, если запрос ему не соответствует, записывает в заголовок информацию
 // о разрешенном методе, статус http.StatusMethodNotAllowed и возвращает false.
 func allowedOnlyMethod(method string, w http.ResponseWriter, r *http.Request) bool {
@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/lazylex/watch-store/secure/internal/dto"
+	serviceErr "github.com/lazylex/watch-store/secure/internal/errors/service"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+// Services возвращает в JSON страницу сервисов. Параметры постраничной выборки описаны в pageRequest.
+func (h *Handler) Services(w http.ResponseWriter, r *http.Request) {
+	h.listPage(w, r, func(ctx context.Context, request *dto.PageRequest) (any, error) {
+		return h.service.Services(ctx, request)
+	})
+}
+
+// Instances возвращает в JSON страницу экземпляров сервиса (параметр service) или всех сервисов.
+func (h *Handler) Instances(w http.ResponseWriter, r *http.Request) {
+	h.listPage(w, r, func(ctx context.Context, request *dto.PageRequest) (any, error) {
+		return h.service.Instances(ctx, request)
+	})
+}
+
+// Roles возвращает в JSON страницу ролей сервиса (обязательный параметр service).
+func (h *Handler) Roles(w http.ResponseWriter, r *http.Request) {
+	h.listPage(w, r, func(ctx context.Context, request *dto.PageRequest) (any, error) {
+		return h.service.Roles(ctx, request)
+	})
+}
+
+// Groups возвращает в JSON страницу групп сервиса (обязательный параметр service).
+func (h *Handler) Groups(w http.ResponseWriter, r *http.Request) {
+	h.listPage(w, r, func(ctx context.Context, request *dto.PageRequest) (any, error) {
+		return h.service.Groups(ctx, request)
+	})
+}
+
+// Accounts возвращает в JSON страницу учетных записей. Фильтр применяется к логину.
+func (h *Handler) Accounts(w http.ResponseWriter, r *http.Request) {
+	h.listPage(w, r, func(ctx context.Context, request *dto.PageRequest) (any, error) {
+		return h.service.Accounts(ctx, request)
+	})
+}
+
+// ServiceDetails возвращает в JSON описание сервиса (параметр name), его экземпляры, разрешения, роли и группы.
+func (h *Handler) ServiceDetails(w http.ResponseWriter, r *http.Request) {
+	h.details(w, r, func(ctx context.Context) (any, error) {
+		return h.service.ServiceDetails(ctx, r.FormValue("name"))
+	}, "name")
+}
+
+// InstanceDetails возвращает в JSON название экземпляра (параметр name) и его сервиса.
+func (h *Handler) InstanceDetails(w http.ResponseWriter, r *http.Request) {
+	h.details(w, r, func(ctx context.Context) (any, error) {
+		return h.service.InstanceDetails(ctx, r.FormValue("name"))
+	}, "name")
+}
+
+// RoleDetails возвращает в JSON описание роли (параметры name и service) и назначенные ей разрешения.
+func (h *Handler) RoleDetails(w http.ResponseWriter, r *http.Request) {
+	h.details(w, r, func(ctx context.Context) (any, error) {
+		return h.service.RoleDetails(ctx, &dto.NameService{Name: r.FormValue("name"), Service: r.FormValue("service")})
+	}, "name", "service")
+}
+
+// GroupDetails возвращает в JSON описание группы (параметры name и service) и назначенные ей роли и разрешения.
+func (h *Handler) GroupDetails(w http.ResponseWriter, r *http.Request) {
+	h.details(w, r, func(ctx context.Context) (any, error) {
+		return h.service.GroupDetails(ctx, &dto.NameService{Name: r.FormValue("name"), Service: r.FormValue("service")})
+	}, "name", "service")
+}
+
+// AccountDetails возвращает в JSON данные учетной записи (параметр user_id), назначенные ей роли и группы и
+// разрешения для экземпляров сервисов.
+func (h *Handler) AccountDetails(w http.ResponseWriter, r *http.Request) {
+	h.details(w, r, func(ctx context.Context) (any, error) {
+		id, err := uuid.Parse(r.FormValue("user_id"))
+		if err != nil {
+			return nil, serviceErr.ErrInvalidQueryParameters
+		}
+		return h.service.AccountDetails(ctx, id)
+	})
+}
+
+// pageRequest возвращает параметры постраничной выборки из запроса: service, filter (часть названия), cursor (значение
+// next_cursor предыдущей страницы), limit (размер страницы), order (asc или desc), а для списка учетных записей также
+// type (тип учетной записи) и owner_team (команда-владелец).
+func pageRequest(r *http.Request) (dto.PageRequest, bool) {
+	request := dto.PageRequest{
+		Service:   r.FormValue("service"),
+		Filter:    r.FormValue("filter"),
+		Cursor:    r.FormValue("cursor"),
+		Type:      r.FormValue("type"),
+		OwnerTeam: r.FormValue("owner_team"),
+	}
+
+	if limit := r.FormValue("limit"); len(limit) > 0 {
+		var err error
+		if request.Limit, err = strconv.Atoi(limit); err != nil {
+			return dto.PageRequest{}, false
+		}
+	}
+
+	switch r.FormValue("order") {
+	case "", "asc":
+	case "desc":
+		request.Descending = true
+	default:
+		return dto.PageRequest{}, false
+	}
+
+	return request, true
+}
+
+// listPage отвечает на запрос страницы списка, полученной функцией fetch.
+func (h *Handler) listPage(w http.ResponseWriter, r *http.Request, fetch func(context.Context, *dto.PageRequest) (any, error)) {
+	if !allowedOnlyMethod(http.MethodGet, w, r) {
+		return
+	}
+
+	request, ok := pageRequest(r)
+	if !ok {
+		w.WriteHeader(http.StatusBadRequest)
+		slog.Default().With("remote address", r.RemoteAddr).Warn("invalid page parameters")
+		return
+	}
+
+	h.details(w, r, func(ctx context.Context) (any, error) {
+		return fetch(ctx, &request)
+	})
+}
+
+// details отвечает данными, полученными функцией fetch. Перечисленные в required параметры запроса обязательны.
+func (h *Handler) details(w http.ResponseWriter, r *http.Request, fetch func(context.Context) (any, error), required ...string) {
+	if !allowedOnlyMethod(http.MethodGet, w, r) {
+		return
+	}
+
+	log := slog.Default().With("remote address", r.RemoteAddr)
+
+	for _, parameter := range required {
+		if len(r.FormValue(parameter)) == 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			log.Warn("missing required parameter", "parameter", parameter)
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.queryTimeout)
+	defer cancel()
+
+	data, err := fetch(ctx)
+	if err != nil {
+		switch {
+		case errors.Is(err, serviceErr.ErrInvalidQueryParameters):
+			w.WriteHeader(http.StatusBadRequest)
+			log.Warn("invalid query parameters")
+		case errors.Is(err, serviceErr.ErrEmptyResult):
+			w.WriteHeader(http.StatusNotFound)
+			log.Warn("not found")
+		case errors.Is(err, context.DeadlineExceeded):
+			w.WriteHeader(http.StatusRequestTimeout)
+			log.Warn("request timed out")
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+			log.Warn("unable to read data")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, data, log)
+}
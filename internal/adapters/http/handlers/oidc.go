@@ -0,0 +1,323 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/lazylex/watch-store/secure/internal/domain/value_objects/login"
+	"github.com/lazylex/watch-store/secure/internal/domain/value_objects/password"
+	"github.com/lazylex/watch-store/secure/internal/dto"
+	serviceErr "github.com/lazylex/watch-store/secure/internal/errors/service"
+	v "github.com/lazylex/watch-store/secure/internal/helpers/constants/various"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// OpenIDConfiguration возвращает в JSON метаданные провайдера OpenID Connect.
+func (h *Handler) OpenIDConfiguration(w http.ResponseWriter, r *http.Request) {
+	if !allowedOnlyMethod(http.MethodGet, w, r) {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, h.service.OpenIDConfiguration(), slog.Default().With("remote address", r.RemoteAddr))
+}
+
+// JSONWebKeySet возвращает в JSON открытые ключи подписи ID-токенов.
+func (h *Handler) JSONWebKeySet(w http.ResponseWriter, r *http.Request) {
+	if !allowedOnlyMethod(http.MethodGet, w, r) {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, h.service.JSONWebKeySet(), slog.Default().With("remote address", r.RemoteAddr))
+}
+
+// Authorize обрабатывает запрос авторизации OpenID Connect (authorization code flow с обязательным PKCE). Пользователь
+// идентифицируется по токену сессии в заголовке Authorization, а при его отсутствии - через Basic Auth, запрос
+// которой браузер покажет пользователю сам. Учетным записям с двухфакторной аутентификацией необходимо предварительно
+// войти через /login и /login/totp и передать токен сессии. При успехе пользователь перенаправляется на redirect_uri с
+// кодом авторизации (параметр code) и переданным клиентом параметром state.
+func (h *Handler) Authorize(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var (
+		err  error
+		id   uuid.UUID
+		code string
+		log  = slog.Default().With("remote address", r.RemoteAddr)
+	)
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.queryTimeout)
+	defer cancel()
+
+	if id, err = h.authorizingUser(ctx, r); err != nil {
+		if errors.Is(err, serviceErr.ErrSecondFactorRequired) {
+			w.WriteHeader(http.StatusForbidden)
+			log.Warn("second factor required, login with session token")
+		} else {
+			w.Header().Set("WWW-Authenticate", "Basic realm=\"secure\"")
+			w.WriteHeader(http.StatusUnauthorized)
+			log.Warn("unable to authenticate user for authorization")
+		}
+		return
+	}
+
+	request := dto.AuthorizationRequest{
+		UserId:              id,
+		ClientId:            r.FormValue("client_id"),
+		RedirectURI:         r.FormValue("redirect_uri"),
+		ResponseType:        r.FormValue("response_type"),
+		Scope:               r.FormValue("scope"),
+		Nonce:               r.FormValue("nonce"),
+		CodeChallenge:       r.FormValue("code_challenge"),
+		CodeChallengeMethod: r.FormValue("code_challenge_method"),
+	}
+
+	if code, err = h.service.Authorize(ctx, &request); err != nil {
+		switch {
+		case errors.Is(err, serviceErr.ErrInvalidOIDCClient), errors.Is(err, serviceErr.ErrOIDCDisabled):
+			w.WriteHeader(http.StatusBadRequest)
+			log.Warn("invalid client or redirect uri")
+		case errors.Is(err, serviceErr.ErrInvalidAuthorizationRequest):
+			redirectWithParams(w, r, request.RedirectURI, map[string]string{"error": "invalid_request"})
+			log.Warn("invalid authorization request")
+		default:
+			redirectWithParams(w, r, request.RedirectURI, map[string]string{"error": "server_error"})
+			log.Warn("unable to authorize")
+		}
+		return
+	}
+
+	redirectWithParams(w, r, request.RedirectURI, map[string]string{"code": code})
+
+	log.Info("authorization code issued")
+}
+
+// authorizingUser возвращает UUID пользователя, выполняющего авторизацию, по токену сессии или логину и паролю.
+func (h *Handler) authorizingUser(ctx context.Context, r *http.Request) (uuid.UUID, error) {
+	if authHeader := r.Header.Get("Authorization"); strings.HasPrefix(authHeader, v.BearerTokenPrefix) {
+		return h.service.UserUUIDFromSession(ctx, authHeader[len(v.BearerTokenPrefix):])
+	}
+
+	username, pwd, ok := r.BasicAuth()
+	if !ok {
+		return uuid.Nil, serviceErr.ErrAuthenticationData
+	}
+
+	return h.service.AuthenticatePassword(ctx, &dto.LoginPassword{Login: login.Login(username), Password: password.Password(pwd)})
+}
+
+// redirectWithParams перенаправляет пользователя на адрес redirectURI, добавляя к нему переданные параметры и параметр
+// state из запроса.
+func redirectWithParams(w http.ResponseWriter, r *http.Request, redirectURI string, params map[string]string) {
+	u, err := url.Parse(redirectURI)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	query := u.Query()
+	for key, value := range params {
+		query.Set(key, value)
+	}
+	if state := r.FormValue("state"); len(state) > 0 {
+		query.Set("state", state)
+	}
+	u.RawQuery = query.Encode()
+
+	http.Redirect(w, r, u.String(), http.StatusFound)
+}
+
+// OIDCToken обменивает код авторизации OpenID Connect на access token клиента (access_token) и ID-токен (id_token).
+// Конфиденциальный клиент передаёт секрет через Basic Auth или параметр client_secret, публичный - только client_id.
+func (h *Handler) OIDCToken(w http.ResponseWriter, r *http.Request) {
+	if !allowedOnlyMethod(http.MethodPost, w, r) {
+		return
+	}
+
+	var (
+		err    error
+		tokens dto.OIDCTokens
+		log    = slog.Default().With("remote address", r.RemoteAddr)
+	)
+
+	if r.FormValue("grant_type") != "authorization_code" {
+		writeOAuthError(w, http.StatusBadRequest, "unsupported_grant_type")
+		log.Warn("unsupported grant type")
+		return
+	}
+
+	exchange := dto.AuthorizationCodeExchange{
+		Code:         r.PostFormValue("code"),
+		ClientId:     r.PostFormValue("client_id"),
+		ClientSecret: r.PostFormValue("client_secret"),
+		RedirectURI:  r.PostFormValue("redirect_uri"),
+		CodeVerifier: r.PostFormValue("code_verifier"),
+	}
+
+	user, pwd, basic := r.BasicAuth()
+	if basic {
+		if exchange.ClientId, err = url.QueryUnescape(user); err == nil {
+			exchange.ClientSecret, err = url.QueryUnescape(pwd)
+		}
+		if err != nil {
+			writeOAuthError(w, http.StatusBadRequest, "invalid_request")
+			log.Warn("invalid client authentication")
+			return
+		}
+	}
+
+	if len(exchange.Code) == 0 || len(exchange.ClientId) == 0 {
+		writeOAuthError(w, http.StatusBadRequest, "invalid_request")
+		log.Warn("code or client_id is missing")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.queryTimeout)
+	defer cancel()
+
+	if tokens, err = h.service.ExchangeAuthorizationCode(ctx, &exchange); err != nil {
+		switch {
+		case errors.Is(err, context.DeadlineExceeded):
+			w.WriteHeader(http.StatusRequestTimeout)
+			log.Warn("request timed out")
+		case errors.Is(err, serviceErr.ErrInvalidOIDCClient):
+			writeOAuthInvalidClient(w, basic)
+			log.Warn("invalid client")
+		case errors.Is(err, serviceErr.ErrInvalidGrant), errors.Is(err, serviceErr.ErrNotEnabledAccount):
+			writeOAuthError(w, http.StatusBadRequest, "invalid_grant")
+			log.Warn("invalid authorization code")
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+			log.Warn("unable to exchange authorization code")
+		}
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": tokens.AccessToken,
+		"token_type":   "Bearer",
+		"expires_in":   int64(tokens.TTL.Seconds()),
+		"id_token":     tokens.IDToken,
+	}, log)
+
+	log.Info("oidc tokens issued")
+}
+
+// UserInfo возвращает в JSON сведения об учетной записи, для которой выдан access token OpenID Connect: UUID (sub) и
+// логин (preferred_username). Токены сессий здесь не принимаются.
+func (h *Handler) UserInfo(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var (
+		err          error
+		id           uuid.UUID
+		accountLogin login.Login
+		log          = slog.Default().With("remote address", r.RemoteAddr)
+	)
+
+	authHeader := r.Header.Get("Authorization")
+	if !strings.HasPrefix(authHeader, v.BearerTokenPrefix) {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		w.WriteHeader(http.StatusUnauthorized)
+		log.Warn("empty or incorrect access token")
+		return
+	}
+
+	if id, err = h.service.UserIdFromAccessToken(authHeader[len(v.BearerTokenPrefix):]); err != nil {
+		w.Header().Set("WWW-Authenticate", "Bearer error=\"invalid_token\"")
+		w.WriteHeader(http.StatusUnauthorized)
+		log.Warn("invalid access token")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.queryTimeout)
+	defer cancel()
+
+	if accountLogin, err = h.service.UserInfo(ctx, id); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		log.Warn("unable to get user info")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"sub": id.String(), "preferred_username": string(accountLogin)}, log)
+}
+
+// RegisterOIDCClient регистрирует клиента OpenID Connect. Принимает идентификатор (client_id), название (name), один
+// или несколько адресов перенаправления (redirect_uri) и признак конфиденциального клиента (confidential=true).
+// Для конфиденциального клиента возвращает в JSON секрет (по ключу client_secret), который больше не показывается.
+func (h *Handler) RegisterOIDCClient(w http.ResponseWriter, r *http.Request) {
+	if !allowedOnlyMethod(http.MethodPost, w, r) {
+		return
+	}
+
+	var (
+		err    error
+		secret string
+		log    = slog.Default().With("remote address", r.RemoteAddr)
+	)
+
+	if err = r.ParseForm(); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		log.Warn("unable to parse form")
+		return
+	}
+
+	registration := dto.OIDCClientRegistration{
+		ClientId:     r.PostFormValue("client_id"),
+		Name:         r.PostFormValue("name"),
+		RedirectURIs: r.PostForm["redirect_uri"],
+		Confidential: r.PostFormValue("confidential") == "true",
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.queryTimeout)
+	defer cancel()
+
+	if secret, err = h.service.RegisterOIDCClient(ctx, &registration); err != nil {
+		switch {
+		case errors.Is(err, serviceErr.ErrInvalidOIDCClient):
+			w.WriteHeader(http.StatusBadRequest)
+			log.Warn("invalid oidc client data")
+		case errors.Is(err, serviceErr.ErrAlreadyExist):
+			w.WriteHeader(http.StatusConflict)
+			log.Warn("oidc client already exists")
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+			log.Warn("unable to register oidc client")
+		}
+		return
+	}
+
+	answer := map[string]string{"client_id": registration.ClientId}
+	if len(secret) > 0 {
+		answer["client_secret"] = secret
+	}
+	writeJSON(w, http.StatusCreated, answer, log)
+
+	log.Info("oidc client registered")
+}
+
+// writeJSON записывает переданные данные в ответ в формате JSON с переданным кодом статуса. Заголовки отправляются
+// только после успешного преобразования данных, поэтому при ошибке клиент получает код 500.
+func writeJSON(w http.ResponseWriter, code int, data any, log *slog.Logger) {
+	answer, err := json.Marshal(data)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		log.Warn("unable to marshal answer")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_, _ = w.Write(answer)
+}
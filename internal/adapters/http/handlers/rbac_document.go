@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/lazylex/watch-store/secure/internal/dto"
+	serviceErr "github.com/lazylex/watch-store/secure/internal/errors/service"
+	"github.com/lazylex/watch-store/secure/internal/helpers/rbac_document"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// maxRBACDocumentSize максимальный размер импортируемого документа в байтах.
+const maxRBACDocumentSize = 10 << 20
+
+// ExportRBAC возвращает документ с полной конфигурацией управления доступом. По умолчанию документ возвращается в JSON,
+// с параметром format=yaml - в YAML.
+func (h *Handler) ExportRBAC(w http.ResponseWriter, r *http.Request) {
+	if !allowedOnlyMethod(http.MethodGet, w, r) {
+		return
+	}
+
+	var (
+		err      error
+		document dto.RBACDocument
+		answer   []byte
+		log      = slog.Default().With("remote address", r.RemoteAddr)
+	)
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.queryTimeout)
+	defer cancel()
+
+	if document, err = h.service.ExportRBAC(ctx); err != nil {
+		writeServiceError(w, err, log, "unable to export rbac configuration")
+		return
+	}
+
+	if r.FormValue("format") != "yaml" {
+		writeJSON(w, http.StatusOK, document, log)
+		log.Info("rbac configuration exported")
+		return
+	}
+
+	if answer, err = rbac_document.ToYAML(&document); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		log.Warn("unable to marshal rbac document to yaml")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/yaml")
+	_, _ = w.Write(answer)
+
+	log.Info("rbac configuration exported")
+}
+
+// ImportRBAC применяет переданный в теле запроса документ с конфигурацией управления доступом (в YAML, если заголовок
+// Content-Type содержит yaml, иначе в JSON). С параметром dry_run=true документ только сравнивается с текущей
+// конфигурацией. Возвращает в JSON список изменений и конфликтов; при конфликтах документ не применяется и
+// возвращается статус 409.
+func (h *Handler) ImportRBAC(w http.ResponseWriter, r *http.Request) {
+	if !allowedOnlyMethod(http.MethodPost, w, r) {
+		return
+	}
+
+	var (
+		err      error
+		body     []byte
+		document dto.RBACDocument
+		result   dto.RBACImportResult
+		log      = slog.Default().With("remote address", r.RemoteAddr)
+	)
+
+	if body, err = io.ReadAll(http.MaxBytesReader(w, r.Body, maxRBACDocumentSize)); err == nil {
+		if strings.Contains(r.Header.Get("Content-Type"), "yaml") {
+			err = rbac_document.FromYAML(body, &document)
+		} else {
+			err = json.Unmarshal(body, &document)
+		}
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		log.Warn("unable to read rbac document")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.queryTimeout)
+	defer cancel()
+
+	if result, err = h.service.ImportRBAC(ctx, &document, r.FormValue("dry_run") == "true"); err != nil {
+		switch {
+		case errors.Is(err, serviceErr.ErrRBACConflict):
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusConflict)
+			_ = json.NewEncoder(w).Encode(result)
+			log.Warn("rbac document conflicts with current state")
+		case errors.Is(err, serviceErr.ErrInvalidRBACDocument):
+			w.WriteHeader(http.StatusBadRequest)
+			log.Warn("unsupported rbac document version")
+		default:
+			writeServiceError(w, err, log, "unable to import rbac configuration")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result, log)
+
+	if result.Applied {
+		log.Info("rbac configuration imported", "changes", len(result.Changes))
+	}
+}
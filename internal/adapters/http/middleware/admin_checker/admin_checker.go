@@ -0,0 +1,50 @@
+package admin_checker
+
+import (
+	v "github.com/lazylex/watch-store/secure/internal/helpers/constants/various"
+	"github.com/lazylex/watch-store/secure/internal/helpers/prefixes"
+	"github.com/lazylex/watch-store/secure/internal/service"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// AdminChecker структура, содержащая доступ к сервисной логике.
+type AdminChecker struct {
+	service *service.Service
+}
+
+// New служит для создания middleware, предназначенного для отклонения запросов к административным адресам от учетных
+// записей, не имеющих роли администратора.
+func New(service *service.Service) *AdminChecker {
+	return &AdminChecker{service: service}
+}
+
+// Checker пропускает запросы к адресам с префиксом prefixes.AdminPrefix только при наличии у владельца сессии роли
+// администратора. Наличие токена сессии в запросе должно быть проверено предыдущим middleware.
+func (a *AdminChecker) Checker(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if !strings.HasPrefix(req.URL.Path, prefixes.AdminPrefix) {
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		log := slog.Default().With("remote address", req.RemoteAddr)
+		token := req.Header.Get("Authorization")[len(v.BearerTokenPrefix):]
+
+		id, err := a.service.UserUUIDFromSession(req.Context(), token)
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			log.Warn("admin checker middleware: invalid token")
+			return
+		}
+
+		if isAdmin, errCheck := a.service.IsAdmin(req.Context(), id); errCheck != nil || !isAdmin {
+			w.WriteHeader(http.StatusForbidden)
+			log.Warn("admin checker middleware: account is not admin")
+			return
+		}
+
+		next.ServeHTTP(w, req)
+	})
+}
@@ -9,7 +9,18 @@ import (
 	"strings"
 )
 
-const loginURI = "/login"
+// publicPaths адреса, доступные без токена сессии.
+var publicPaths = map[string]struct{}{
+	"/login":                            {},
+	"/login/totp":                       {},
+	"/oauth/token":                      {},
+	"/reset-password":                   {},
+	"/.well-known/openid-configuration": {},
+	"/jwks":                             {},
+	"/authorize":                        {},
+	"/token":                            {},
+	"/userinfo":                         {}, // Проверяет access token OpenID Connect самостоятельно
+}
 
 // TokenChecker структура, содержащая доступ к сервисной логике.
 type TokenChecker struct {
@@ -22,8 +33,8 @@ func New(service *service.Service) *TokenChecker {
 	return &TokenChecker{service: service}
 }
 
-// Checker проверяет, что запрос либо осуществляется по адресу, назначенному для процедуры входа в систему, либо
-// содержит токен, который соответствует открытой сессии.
+// Checker проверяет, что запрос либо осуществляется по адресу, не требующему входа в систему (вход или сброс пароля),
+// либо содержит токен, который соответствует открытой сессии.
 func (t *TokenChecker) Checker(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		uri := req.URL.RequestURI()
@@ -32,7 +43,7 @@ func (t *TokenChecker) Checker(next http.Handler) http.Handler {
 			return
 		}
 
-		if req.RequestURI == loginURI {
+		if _, ok := publicPaths[req.URL.Path]; ok {
 			next.ServeHTTP(w, req)
 			return
 		}
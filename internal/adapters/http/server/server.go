@@ -2,8 +2,11 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"github.com/lazylex/watch-store/secure/internal/adapters/http/handlers"
+	"github.com/lazylex/watch-store/secure/internal/adapters/http/middleware/admin_checker"
 	"github.com/lazylex/watch-store/secure/internal/adapters/http/middleware/recoverer"
 	requestMetrics "github.com/lazylex/watch-store/secure/internal/adapters/http/middleware/request_metrics"
 	"github.com/lazylex/watch-store/secure/internal/adapters/http/middleware/token_checker"
@@ -12,7 +15,9 @@ import (
 	"github.com/lazylex/watch-store/secure/internal/helpers/prefixes"
 	"github.com/lazylex/watch-store/secure/internal/metrics"
 	"github.com/lazylex/watch-store/secure/internal/service"
+	"github.com/lazylex/watch-store/secure/internal/tls_config"
 	"log/slog"
+	"net"
 	"net/http"
 	"net/http/pprof"
 	"os"
@@ -21,10 +26,12 @@ import (
 
 // Server структура для обработки http-запросов к приложению.
 type Server struct {
-	cfg     *config.HttpServer // Конфигурация http сервера
-	srv     *http.Server       // Структура с параметрами сервера
-	mux     *http.ServeMux     // Мультиплексор http запросов
-	service *service.Service   // Структура, реализующая логику приложения
+	cfg         *config.HttpServer // Конфигурация http сервера
+	srv         *http.Server       // Структура с параметрами сервера
+	mtlsSrv     *http.Server       // Сервер для входа по клиентским сертификатам. Равен nil, если его адрес не задан
+	redirectSrv *http.Server       // Сервер перенаправления с HTTP на HTTPS. Равен nil, если он не используется
+	mux         *http.ServeMux     // Мультиплексор http запросов
+	service     *service.Service   // Структура, реализующая логику приложения
 }
 
 // MustCreate возвращает готовый к запуску http-сервер (запуск осуществляется функцией MustRun). Если какой-либо из
@@ -47,9 +54,42 @@ func MustCreate(domainService *service.Service, cfg *config.HttpServer, m *metri
 
 	h := handlers.New(domainService, cfg.RequestTimeout)
 	router.AssignPathToHandler("/login", server.mux, h.Login)
+	router.AssignPathToHandler("/login/totp", server.mux, h.LoginSecondFactor)
 	router.AssignPathToHandler("/logout", server.mux, h.Logout)
 	router.AssignPathToHandler("/get-token", server.mux, h.TokenWithPermissions)
+	router.AssignPathToHandler("/oauth/token", server.mux, h.OAuthToken)
+	router.AssignPathToHandler("/introspect", server.mux, h.IntrospectToken)
+	router.AssignPathToHandler("/revoke", server.mux, h.RevokeToken)
 	router.AssignPathToHandler("/get-numbered-permissions", server.mux, h.ServiceNumberedPermissions)
+	router.AssignPathToHandler("/check", server.mux, h.CheckPermission)
+	router.AssignPathToHandler("/check/batch", server.mux, h.CheckPermissions)
+	router.AssignPathToHandler("/change-password", server.mux, h.ChangePassword)
+	router.AssignPathToHandler("/reset-password", server.mux, h.CompletePasswordReset)
+	router.AssignPathToHandler(prefixes.AdminPrefix+"reset-password", server.mux, h.ResetPassword)
+	router.AssignPathToHandler(prefixes.AdminPrefix+"explain-permissions", server.mux, h.ExplainPermissions)
+	router.AssignPathToHandler(prefixes.AdminPrefix+"services", server.mux, h.Services)
+	router.AssignPathToHandler(prefixes.AdminPrefix+"service", server.mux, h.ServiceDetails)
+	router.AssignPathToHandler(prefixes.AdminPrefix+"instances", server.mux, h.Instances)
+	router.AssignPathToHandler(prefixes.AdminPrefix+"instance", server.mux, h.InstanceDetails)
+	router.AssignPathToHandler(prefixes.AdminPrefix+"roles", server.mux, h.Roles)
+	router.AssignPathToHandler(prefixes.AdminPrefix+"role", server.mux, h.RoleDetails)
+	router.AssignPathToHandler(prefixes.AdminPrefix+"groups", server.mux, h.Groups)
+	router.AssignPathToHandler(prefixes.AdminPrefix+"group", server.mux, h.GroupDetails)
+	router.AssignPathToHandler(prefixes.AdminPrefix+"accounts", server.mux, h.Accounts)
+	router.AssignPathToHandler(prefixes.AdminPrefix+"account", server.mux, h.AccountDetails)
+	router.AssignPathToHandler(prefixes.AdminPrefix+"rbac/export", server.mux, h.ExportRBAC)
+	router.AssignPathToHandler(prefixes.AdminPrefix+"rbac/import", server.mux, h.ImportRBAC)
+	router.AssignPathToHandler("/totp/enroll", server.mux, h.EnrollTOTP)
+	router.AssignPathToHandler("/totp/confirm", server.mux, h.ConfirmTOTP)
+	router.AssignPathToHandler("/totp/disable", server.mux, h.DisableTOTP)
+	if domainService.OIDCEnabled() {
+		router.AssignPathToHandler("/.well-known/openid-configuration", server.mux, h.OpenIDConfiguration)
+		router.AssignPathToHandler("/jwks", server.mux, h.JSONWebKeySet)
+		router.AssignPathToHandler("/authorize", server.mux, h.Authorize)
+		router.AssignPathToHandler("/token", server.mux, h.OIDCToken)
+		router.AssignPathToHandler("/userinfo", server.mux, h.UserInfo)
+		router.AssignPathToHandler(prefixes.AdminPrefix+"oidc-clients", server.mux, h.RegisterOIDCClient)
+	}
 	router.AssignPathToHandler("/", server.mux, h.Index)
 
 	if cfg.EnableProfiler {
@@ -64,26 +104,137 @@ func MustCreate(domainService *service.Service, cfg *config.HttpServer, m *metri
 		router.AssignPathToHandler(prefixes.PPROFPrefix+"trace", server.mux, pprof.Trace)
 	}
 	tokenMiddleware := token_checker.New(domainService)
+	adminMiddleware := admin_checker.New(domainService)
 	metricsMiddleware := requestMetrics.New(m)
 
-	server.srv.Handler = tokenMiddleware.Checker(server.mux)
+	server.srv.Handler = adminMiddleware.Checker(server.mux)
+	server.srv.Handler = tokenMiddleware.Checker(server.srv.Handler)
 	server.srv.Handler = metricsMiddleware.BeforeHandle(server.srv.Handler)
 	server.srv.Handler = metricsMiddleware.AfterHandle(server.srv.Handler)
 	server.srv.Handler = recoverer.Recoverer(server.srv.Handler)
 
+	if cfg.TLS.Enabled() {
+		server.srv.TLSConfig = tls_config.MustCreate(&cfg.TLS)
+
+		if len(cfg.RedirectAddress) > 0 {
+			server.redirectSrv = createRedirectServer(cfg)
+		}
+	}
+
+	if len(cfg.MTLS.Address) > 0 {
+		server.mtlsSrv = mustCreateMTLSServer(cfg, h, m)
+	}
+
 	return server
 }
 
+// mustCreateMTLSServer возвращает TLS-сервер, требующий от клиента сертификат, подписанный центром сертификации из
+// файла cfg.MTLS.ClientCAFile. Сервер обслуживает только вход по сертификату, остальные запросы выполняются с
+// полученным токеном сессии через основной сервер. При ошибке чтения сертификатов работа приложения завершается.
+func mustCreateMTLSServer(cfg *config.HttpServer, h *handlers.Handler, m *metrics.Metrics) *http.Server {
+	caPEM, err := os.ReadFile(cfg.MTLS.ClientCAFile)
+	if err != nil {
+		slog.Error("unable to read client CA file: " + err.Error())
+		os.Exit(1)
+	}
+
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM(caPEM) {
+		slog.Error("no certificates found in client CA file")
+		os.Exit(1)
+	}
+
+	mux := http.NewServeMux()
+	router.AssignPathToHandler("/login/certificate", mux, h.CertificateLogin)
+
+	metricsMiddleware := requestMetrics.New(m)
+	var handler http.Handler = mux
+	handler = metricsMiddleware.BeforeHandle(handler)
+	handler = metricsMiddleware.AfterHandle(handler)
+	handler = recoverer.Recoverer(handler)
+
+	tlsConfig := tls_config.MustCreate(&config.TLS{
+		CertFile:       cfg.MTLS.CertFile,
+		KeyFile:        cfg.MTLS.KeyFile,
+		MinVersion:     cfg.TLS.MinVersion,
+		CipherSuites:   cfg.TLS.CipherSuites,
+		ReloadInterval: cfg.TLS.ReloadInterval,
+	})
+	tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	tlsConfig.ClientCAs = clientCAs
+
+	return &http.Server{
+		Addr:         cfg.MTLS.Address,
+		Handler:      handler,
+		ReadTimeout:  cfg.ReadTimeout,
+		WriteTimeout: cfg.WriteTimeout,
+		IdleTimeout:  cfg.IdleTimeout,
+		TLSConfig:    tlsConfig,
+	}
+}
+
+// createRedirectServer возвращает HTTP-сервер, перенаправляющий все запросы на тот же путь основного HTTPS-сервера.
+// Используется код 308, чтобы клиенты повторяли запрос с тем же методом и телом.
+func createRedirectServer(cfg *config.HttpServer) *http.Server {
+	_, httpsPort, _ := net.SplitHostPort(cfg.Address)
+
+	return &http.Server{
+		Addr:         cfg.RedirectAddress,
+		ReadTimeout:  cfg.ReadTimeout,
+		WriteTimeout: cfg.WriteTimeout,
+		IdleTimeout:  cfg.IdleTimeout,
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			host := r.Host
+			if h, _, err := net.SplitHostPort(r.Host); err == nil {
+				host = h
+			}
+			if len(httpsPort) > 0 && httpsPort != "443" {
+				host = net.JoinHostPort(host, httpsPort)
+			}
+
+			http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusPermanentRedirect)
+		}),
+	}
+}
+
 // MustRun производит запуск сервера в отдельной go-рутине. В случае ошибки останавливает работу приложения.
 func (s *Server) MustRun() {
 	go func() {
-		slog.Info("start http server on " + s.srv.Addr)
-		err := s.srv.ListenAndServe()
+		var err error
+		if s.srv.TLSConfig != nil {
+			slog.Info("start https server on " + s.srv.Addr)
+			err = s.srv.ListenAndServeTLS("", "")
+		} else {
+			slog.Info("start http server on " + s.srv.Addr)
+			err = s.srv.ListenAndServe()
+		}
 		if err != nil && !errors.Is(err, http.ErrServerClosed) {
 			slog.Error("http server err: startup error. Initial error: " + err.Error())
 			os.Exit(1)
 		}
 	}()
+
+	if s.redirectSrv != nil {
+		go func() {
+			slog.Info("start http to https redirect server on " + s.redirectSrv.Addr)
+			err := s.redirectSrv.ListenAndServe()
+			if err != nil && !errors.Is(err, http.ErrServerClosed) {
+				slog.Error("redirect server err: startup error. Initial error: " + err.Error())
+				os.Exit(1)
+			}
+		}()
+	}
+
+	if s.mtlsSrv != nil {
+		go func() {
+			slog.Info("start mtls server on " + s.mtlsSrv.Addr)
+			err := s.mtlsSrv.ListenAndServeTLS("", "")
+			if err != nil && !errors.Is(err, http.ErrServerClosed) {
+				slog.Error("mtls server err: startup error. Initial error: " + err.Error())
+				os.Exit(1)
+			}
+		}()
+	}
 }
 
 // Shutdown производит остановку сервера.
@@ -96,4 +247,20 @@ func (s *Server) Shutdown() {
 	} else {
 		slog.Info("gracefully shut down http server")
 	}
+
+	if s.redirectSrv != nil {
+		if err := s.redirectSrv.Shutdown(ctx); err != nil {
+			slog.Error("failed to gracefully shutdown redirect server")
+		} else {
+			slog.Info("gracefully shut down redirect server")
+		}
+	}
+
+	if s.mtlsSrv != nil {
+		if err := s.mtlsSrv.Shutdown(ctx); err != nil {
+			slog.Error("failed to gracefully shutdown mtls server")
+		} else {
+			slog.Info("gracefully shut down mtls server")
+		}
+	}
 }
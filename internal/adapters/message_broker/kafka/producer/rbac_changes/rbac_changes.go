@@ -0,0 +1,61 @@
+package rbac_changes
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/lazylex/watch-store/secure/internal/config"
+	"github.com/lazylex/watch-store/secure/internal/dto"
+	"github.com/lazylex/watch-store/secure/internal/errors/message_broker"
+	"github.com/segmentio/kafka-go"
+	"log/slog"
+)
+
+// Producer продюсер событий об изменениях конфигурации управления доступом. Каждое изменение отправляется отдельным
+// сообщением в JSON с ключом, равным виду изменяемой сущности.
+type Producer struct {
+	writer *kafka.Writer
+}
+
+// Create возвращает продюсер, отправляющий события в топик RBACChangesTopic.
+func Create(cfg *config.Kafka) *Producer {
+	retries := 3
+	if cfg.NumberOfRetriesToSendMessage > 0 {
+		retries = cfg.NumberOfRetriesToSendMessage
+	}
+
+	return &Producer{writer: &kafka.Writer{
+		Addr:                   kafka.TCP(cfg.Brokers...),
+		Topic:                  cfg.RBACChangesTopic,
+		AllowAutoTopicCreation: true,
+		MaxAttempts:            retries,
+		WriteBackoffMin:        cfg.KafkaTimeBetweenAttempts,
+		WriteTimeout:           cfg.KafkaWriteTimeout,
+	}}
+}
+
+// PublishRBACChanges отправляет события о переданных изменениях.
+func (p *Producer) PublishRBACChanges(ctx context.Context, changes []dto.RBACChange) error {
+	origin := "PublishRBACChanges"
+
+	messages := make([]kafka.Message, 0, len(changes))
+	for _, change := range changes {
+		value, err := json.Marshal(change)
+		if err != nil {
+			return message_broker.FullMessageBrokerError("unable to marshal rbac change", origin, err)
+		}
+		messages = append(messages, kafka.Message{Key: []byte(change.Kind), Value: value})
+	}
+
+	if err := p.writer.WriteMessages(ctx, messages...); err != nil {
+		return message_broker.FullMessageBrokerError(message_broker.ErrCouldNotSendMessage.Message, origin, err)
+	}
+
+	return nil
+}
+
+// Close закрывает соединение с брокером сообщений.
+func (p *Producer) Close() {
+	if err := p.writer.Close(); err != nil {
+		slog.Error(message_broker.ErrFailedToCloseWriter.Error())
+	}
+}
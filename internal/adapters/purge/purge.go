@@ -0,0 +1,72 @@
+/*
+Package purge: пакет периодического окончательного удаления сущностей, помеченных удаленными. Purger с заданным в
+настройках интервалом безвозвратно удаляет учетные записи, сервисы, экземпляры, разрешения, роли и группы, срок
+хранения которых после пометки удаленными истёк, вместе с их назначениями.
+*/
+package purge
+
+import (
+	"context"
+	"github.com/lazylex/watch-store/secure/internal/config"
+	"github.com/lazylex/watch-store/secure/internal/ports/service"
+	"log/slog"
+	"os"
+	"time"
+)
+
+// Purger структура, периодически удаляющая сущности с истёкшим сроком хранения после пометки удаленными.
+type Purger struct {
+	service service.Service // Сервисный слой
+	cfg     *config.Purge   // Настройки удаления
+	stop    chan struct{}   // Канал остановки удаления
+}
+
+// MustCreate возвращает структуру для окончательного удаления сущностей. Если сервис или настройки равны nil либо
+// интервал удаления или срок хранения не положительны, работа приложения завершается.
+func MustCreate(domainService service.Service, cfg *config.Purge) *Purger {
+	if domainService == nil || cfg == nil || cfg.Interval <= 0 || cfg.Retention <= 0 {
+		slog.Error("domain service, cfg, purge interval or retention is not set")
+		os.Exit(1)
+	}
+
+	return &Purger{service: domainService, cfg: cfg}
+}
+
+// MustRun запускает окончательное удаление сущностей с интервалом из настроек. Ошибки удаления записываются в журнал.
+func (p *Purger) MustRun() {
+	stop := make(chan struct{})
+	p.stop = stop
+	ticker := time.NewTicker(p.cfg.Interval)
+
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), p.cfg.Interval)
+				_ = p.Purge(ctx)
+				cancel()
+			}
+		}
+	}()
+}
+
+// Stop прекращает окончательное удаление сущностей.
+func (p *Purger) Stop() {
+	if p.stop != nil {
+		close(p.stop)
+		p.stop = nil
+	}
+}
+
+// Purge безвозвратно удаляет сущности, помеченные удаленными раньше, чем срок хранения из настроек назад.
+func (p *Purger) Purge(ctx context.Context) error {
+	if err := p.service.PurgeDeleted(ctx, time.Now().Add(-p.cfg.Retention)); err != nil {
+		slog.Error("unable to purge deleted entities: " + err.Error())
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package purge
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/lazylex/watch-store/secure/internal/config"
+	mockservice "github.com/lazylex/watch-store/secure/internal/ports/service/mocks"
+	"testing"
+	"time"
+)
+
+func TestPurger_Purge(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	service := mockservice.NewMockService(controller)
+	p := MustCreate(service, &config.Purge{Interval: time.Hour, Retention: 24 * time.Hour})
+	start := time.Now()
+
+	service.EXPECT().PurgeDeleted(ctx, gomock.Any()).Times(1).DoAndReturn(func(_ context.Context, before time.Time) error {
+		if before.After(start.Add(-24*time.Hour)) && before.Before(time.Now().Add(-24*time.Hour)) {
+			return nil
+		}
+		return errors.New("wrong retention")
+	})
+
+	if p.Purge(ctx) != nil {
+		t.Fail()
+	}
+}
+
+func TestPurger_PurgeError(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	service := mockservice.NewMockService(controller)
+	p := MustCreate(service, &config.Purge{Interval: time.Hour, Retention: time.Hour})
+
+	service.EXPECT().PurgeDeleted(ctx, gomock.Any()).Times(1).Return(errors.New("timeout"))
+
+	if p.Purge(ctx) == nil {
+		t.Fail()
+	}
+}
@@ -0,0 +1,134 @@
+/*
+Package reconcile: пакет декларативного управления конфигурацией доступа. Reconciler считывает файл с желаемым
+состоянием (документ в YAML или JSON, хранящийся, например, в git-репозитории) при запуске приложения и при получении
+сигнала SIGHUP, приводит к нему конфигурацию в постоянном хранилище и отправляет события о выполненных изменениях.
+*/
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/lazylex/watch-store/secure/internal/config"
+	"github.com/lazylex/watch-store/secure/internal/dto"
+	serviceErr "github.com/lazylex/watch-store/secure/internal/errors/service"
+	"github.com/lazylex/watch-store/secure/internal/helpers/rbac_document"
+	"github.com/lazylex/watch-store/secure/internal/ports/message_broker"
+	"github.com/lazylex/watch-store/secure/internal/ports/service"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// Reconciler структура, приводящая конфигурацию управления доступом к файлу с желаемым состоянием.
+type Reconciler struct {
+	service service.Service                     // Сервисный слой
+	cfg     *config.Reconcile                   // Настройки согласования
+	events  message_broker.RBACChangesInterface // Отправитель событий об изменениях. Может быть равен nil
+	mu      sync.Mutex                          // Исключает одновременное согласование
+	signals chan os.Signal                      // Канал сигналов SIGHUP
+}
+
+// MustCreate возвращает структуру для согласования конфигурации. Если events равен nil, события об изменениях только
+// записываются в журнал. Если сервис или настройки равны nil или не задан файл с желаемым состоянием, работа
+// приложения завершается.
+func MustCreate(domainService service.Service, cfg *config.Reconcile, events message_broker.RBACChangesInterface) *Reconciler {
+	if domainService == nil || cfg == nil || len(cfg.RBACFile) == 0 {
+		slog.Error("domain service, cfg or rbac file is not set")
+		os.Exit(1)
+	}
+
+	return &Reconciler{service: domainService, cfg: cfg, events: events}
+}
+
+// MustRun согласует конфигурацию и запускает повторное согласование при получении сигнала SIGHUP. Если файл с желаемым
+// состоянием не существует, работа приложения завершается. Ошибки согласования записываются в журнал, при этом
+// конфигурация остаётся прежней.
+func (r *Reconciler) MustRun() {
+	if _, err := os.Stat(r.cfg.RBACFile); err != nil {
+		slog.Error(fmt.Sprintf("rbac file is not available: %s", err.Error()))
+		os.Exit(1)
+	}
+
+	r.reconcileWithTimeout()
+
+	r.signals = make(chan os.Signal, 1)
+	signal.Notify(r.signals, syscall.SIGHUP)
+
+	go func() {
+		for range r.signals {
+			slog.Info("SIGHUP signal received. Rbac reconciliation started")
+			r.reconcileWithTimeout()
+		}
+	}()
+}
+
+// Stop прекращает обработку сигнала SIGHUP.
+func (r *Reconciler) Stop() {
+	if r.signals != nil {
+		signal.Stop(r.signals)
+		close(r.signals)
+	}
+}
+
+// Reconcile считывает файл с желаемым состоянием, приводит к нему конфигурацию (с удалением отсутствующих в файле
+// данных, если это задано в настройках) и отправляет события о выполненных изменениях.
+func (r *Reconciler) Reconcile(ctx context.Context) (dto.RBACImportResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var document dto.RBACDocument
+
+	data, err := os.ReadFile(r.cfg.RBACFile)
+	if err != nil {
+		return dto.RBACImportResult{}, err
+	}
+
+	if err = rbac_document.FromYAML(data, &document); err != nil {
+		return dto.RBACImportResult{}, err
+	}
+
+	result, err := r.service.ReconcileRBAC(ctx, &document, r.cfg.Prune)
+	if err != nil {
+		return result, err
+	}
+
+	if !result.Applied {
+		return result, nil
+	}
+
+	for _, change := range result.Changes {
+		slog.Info("rbac changed", "action", change.Action, "kind", change.Kind, "target", change.Target)
+	}
+
+	if r.events != nil {
+		if err = r.events.PublishRBACChanges(ctx, result.Changes); err != nil {
+			slog.Error(err.Error())
+		}
+	}
+
+	return result, nil
+}
+
+// reconcileWithTimeout выполняет согласование с ограничением времени из настроек и записывает результат в журнал.
+func (r *Reconciler) reconcileWithTimeout() {
+	ctx, cancel := context.WithTimeout(context.Background(), r.cfg.Timeout)
+	defer cancel()
+
+	result, err := r.Reconcile(ctx)
+	switch {
+	case errors.Is(err, serviceErr.ErrRBACConflict):
+		for _, conflict := range result.Conflicts {
+			slog.Error("rbac reconciliation conflict: " + conflict)
+		}
+		slog.Error("rbac file conflicts with current state and was not applied")
+	case err != nil:
+		slog.Error("unable to reconcile rbac: " + err.Error())
+	case result.Applied:
+		slog.Info(fmt.Sprintf("rbac reconciled, %d changes applied", len(result.Changes)))
+	default:
+		slog.Info("rbac is up to date")
+	}
+}
@@ -0,0 +1,64 @@
+package reconcile
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/lazylex/watch-store/secure/internal/config"
+	"github.com/lazylex/watch-store/secure/internal/dto"
+	mockservice "github.com/lazylex/watch-store/secure/internal/ports/service/mocks"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// events запоминает отправленные события.
+type events struct {
+	changes []dto.RBACChange
+}
+
+func (e *events) PublishRBACChanges(_ context.Context, changes []dto.RBACChange) error {
+	e.changes = append(e.changes, changes...)
+	return nil
+}
+
+func TestReconciler_Reconcile(t *testing.T) {
+	ctx := context.Background()
+	file := filepath.Join(t.TempDir(), "rbac.yaml")
+	content := "version: 1\nservices:\n  - name: store\n    instances: [store-1]\n"
+	if os.WriteFile(file, []byte(content), 0600) != nil {
+		t.Fatal()
+	}
+
+	controller := gomock.NewController(t)
+	service := mockservice.NewMockService(controller)
+	published := &events{}
+	r := MustCreate(service, &config.Reconcile{RBACFile: file, Prune: true}, published)
+
+	document := dto.RBACDocument{Version: 1, Services: []dto.RBACService{{Name: "store", Instances: []string{"store-1"}}}}
+	change := dto.RBACChange{Action: "create", Kind: "instance", Target: "store/store-1"}
+	service.EXPECT().ReconcileRBAC(ctx, &document, true).Times(1).Return(
+		dto.RBACImportResult{Applied: true, Changes: []dto.RBACChange{change}}, nil)
+
+	if _, err := r.Reconcile(ctx); err != nil || len(published.changes) != 1 || published.changes[0] != change {
+		t.Fail()
+	}
+}
+
+func TestReconciler_ReconcileNothingToApply(t *testing.T) {
+	ctx := context.Background()
+	file := filepath.Join(t.TempDir(), "rbac.json")
+	if os.WriteFile(file, []byte(`{"version": 1}`), 0600) != nil {
+		t.Fatal()
+	}
+
+	controller := gomock.NewController(t)
+	service := mockservice.NewMockService(controller)
+	published := &events{}
+	r := MustCreate(service, &config.Reconcile{RBACFile: file}, published)
+
+	service.EXPECT().ReconcileRBAC(ctx, &dto.RBACDocument{Version: 1}, false).Times(1).Return(dto.RBACImportResult{}, nil)
+
+	if _, err := r.Reconcile(ctx); err != nil || len(published.changes) != 0 {
+		t.Fail()
+	}
+}
@@ -12,17 +12,26 @@ Package config: пакет содержит объявления всех стр
 
 2. Redis - конфигурация redis-сервера
 
-3. HttpServer - конфигурация http-сервера
+3. HttpServer - конфигурация http-сервера: TLS, перенаправление с HTTP на HTTPS и необязательный TLS-сервер для входа
+по клиентским сертификатам
 
 4. PersistentStorage - настройки реляционной СУБД, используемой в качестве постоянного хранилища
 
 5. Kafka - конфигурация для работы с Apache Kafka
 
-6. Prometheus - конфигурация
+6. Prometheus - конфигурация http-сервера для сбора метрик, в том числе TLS
 
 7. TTL - настройки времени жизни сессий и прочих хранящихся в памяти данных
 
-8. Secure - настройки времени жизни и длины токена, стоимости создания хэша пароля
+8. Secure - настройки времени жизни и длины токена, стоимости создания хэша пароля, названия сервиса и роли, дающей
+права администратора, параметры двухфакторной аутентификации и OpenID Connect
+
+9. Reconcile - настройки декларативного управления конфигурацией доступа: путь к файлу с желаемым состоянием и признак
+удаления отсутствующих в нём данных
+
+10. Grants - настройки удаления назначений учетным записям с истёкшим сроком действия
+
+11. Purge - настройки окончательного удаления помеченных удаленными сущностей по истечении срока хранения
 */
 package config
 
@@ -46,11 +55,15 @@ type Config struct {
 	UseKafka          bool   `yaml:"use_kafka" env:"USE_KAFKA"`
 	Redis             `yaml:"redis"`
 	HttpServer        `yaml:"http_server"`
+	GrpcServer        `yaml:"grpc_server"`
 	PersistentStorage `yaml:"persistent_storage"`
 	Kafka             `yaml:"kafka"`
 	Prometheus        `yaml:"prometheus"`
 	TTL               `yaml:"ttl"`
 	Secure            `yaml:"secure"`
+	Reconcile         `yaml:"reconcile"`
+	Grants            `yaml:"grants"`
+	Purge             `yaml:"purge"`
 }
 
 type HttpServer struct {
@@ -61,6 +74,41 @@ type HttpServer struct {
 	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT" env-required:"true"`
 	RequestTimeout  time.Duration `yaml:"request_timeout" env:"REQUEST_TIMEOUT" env-required:"true"`
 	EnableProfiler  bool          `yaml:"enable_profiler" env:"ENABLE_PROFILER"`
+	RedirectAddress string        `yaml:"redirect_address" env:"REDIRECT_ADDRESS"`
+	TLS             TLS           `yaml:"tls"`
+	MTLS            MTLS          `yaml:"mtls"`
+}
+
+// GrpcServer - настройки gRPC-сервера. Если адрес не задан, gRPC-сервер не запускается.
+type GrpcServer struct {
+	GrpcAddress         string        `yaml:"grpc_address" env:"GRPC_ADDRESS"`
+	GrpcRequestTimeout  time.Duration `yaml:"grpc_request_timeout" env:"GRPC_REQUEST_TIMEOUT" env-default:"50s"`
+	GrpcShutdownTimeout time.Duration `yaml:"grpc_shutdown_timeout" env:"GRPC_SHUTDOWN_TIMEOUT" env-default:"15s"`
+	TLS                 TLS           `yaml:"tls" env-prefix:"GRPC_"`
+}
+
+// TLS - настройки TLS. Если файл сертификата не задан, сервер работает по протоколу HTTP. Сертификат перечитывается
+// при изменении файлов с периодом проверки ReloadInterval.
+type TLS struct {
+	CertFile       string        `yaml:"cert_file" env:"TLS_CERT_FILE"`
+	KeyFile        string        `yaml:"key_file" env:"TLS_KEY_FILE"`
+	MinVersion     string        `yaml:"min_version" env:"TLS_MIN_VERSION" env-default:"1.2"`
+	CipherSuites   []string      `yaml:"cipher_suites" env:"TLS_CIPHER_SUITES"`
+	ReloadInterval time.Duration `yaml:"reload_interval" env:"TLS_RELOAD_INTERVAL" env-default:"1m"`
+}
+
+// Enabled возвращает true, если задан файл сертификата.
+func (t *TLS) Enabled() bool {
+	return len(t.CertFile) > 0
+}
+
+// MTLS - настройки дополнительного TLS-сервера для входа сервисов по клиентским сертификатам. Если адрес не задан,
+// сервер не запускается.
+type MTLS struct {
+	Address      string `yaml:"address" env:"MTLS_ADDRESS"`
+	CertFile     string `yaml:"cert_file" env:"MTLS_CERT_FILE"`
+	KeyFile      string `yaml:"key_file" env:"MTLS_KEY_FILE"`
+	ClientCAFile string `yaml:"client_ca_file" env:"MTLS_CLIENT_CA_FILE"`
 }
 
 type PersistentStorage struct {
@@ -78,6 +126,7 @@ type PersistentStorage struct {
 type Kafka struct {
 	Brokers                      []string      `yaml:"kafka_brokers" env:"KAFKA_BROKERS"`
 	NeedToUpdateTokenTopic       string        `yaml:"kafka_topic_need_update_token" env:"KAFKA_TOPIC_NEED_UPDATE_TOKEN"`
+	RBACChangesTopic             string        `yaml:"kafka_topic_rbac_changes" env:"KAFKA_TOPIC_RBAC_CHANGES"`
 	NumberOfRetriesToSendMessage int           `yaml:"kafka_number_of_retries_to_send_message" env:"KAFKA_NUMBER_OF_RETRIES_TO_SEND_MESSAGE"`
 	KafkaTimeBetweenAttempts     time.Duration `yaml:"kafka_time_between_attempts" env:"KAFKA_TIME_BETWEEN_ATTEMPTS" env-required:"true"`
 	KafkaWriteTimeout            time.Duration `yaml:"kafka_write_timeout" env:"KAFKA_WRITE_TIMEOUT" env-required:"true"`
@@ -86,6 +135,7 @@ type Kafka struct {
 type Prometheus struct {
 	PrometheusPort       string `yaml:"prometheus_port" env:"PROMETHEUS_PORT"`
 	PrometheusMetricsURL string `yaml:"prometheus_metrics_url" env:"PROMETHEUS_METRICS_URL"`
+	TLS                  TLS    `yaml:"tls" env-prefix:"PROMETHEUS_"`
 }
 
 type Redis struct {
@@ -101,12 +151,45 @@ type TTL struct {
 	AccountStateTTL          time.Duration `yaml:"account_state_ttl" env:"TTL_ACCOUNT_STATE_TTL" env-required:"true"`
 	PermissionsNumbersTTL    time.Duration `yaml:"permissions_numbers_ttl" env:"TTL_PERMISSIONS_TTL" env-required:"true"`
 	InstanceDataTTL          time.Duration `yaml:"instance_data_ttl" env:"INSTANCE_DATA_TTL" env-required:"true"`
+	ResetTokenTTL            time.Duration `yaml:"reset_token_ttl" env:"TTL_RESET_TOKEN_TTL" env-required:"true"`
+	LoginChallengeTTL        time.Duration `yaml:"login_challenge_ttl" env:"TTL_LOGIN_CHALLENGE_TTL" env-required:"true"`
+	AuthorizationCodeTTL     time.Duration `yaml:"authorization_code_ttl" env:"TTL_AUTHORIZATION_CODE_TTL" env-required:"true"`
 }
 
 type Secure struct {
 	LoginTokenLength     int           `yaml:"login_token_length" env:"LOGIN_TOKEN_LENGTH" env-required:"true"`
 	PasswordCreationCost int           `yaml:"password_creation_cost" env:"PASSWORD_CREATION_COST" env-required:"true"`
 	TokenTTL             time.Duration `yaml:"token_ttl" env:"TOKEN_TTL" env-required:"true"`
+	AdminService         string        `yaml:"admin_service" env:"ADMIN_SERVICE" env-default:"secure"`
+	AdminRole            string        `yaml:"admin_role" env:"ADMIN_ROLE" env-default:"Администратор"`
+	TOTPIssuer           string        `yaml:"totp_issuer" env:"TOTP_ISSUER" env-default:"watch-store secure"`
+	RecoveryCodesCount   int           `yaml:"recovery_codes_count" env:"RECOVERY_CODES_COUNT" env-default:"10"`
+	OIDCIssuer           string        `yaml:"oidc_issuer" env:"OIDC_ISSUER"`
+	OIDCSigningKeyFile   string        `yaml:"oidc_signing_key_file" env:"OIDC_SIGNING_KEY_FILE"`
+}
+
+// Reconcile - настройки декларативного управления конфигурацией доступа. Если файл не задан, согласование не
+// производится. Файл в YAML или JSON считывается при запуске и при получении сигнала SIGHUP. Если Prune истинно,
+// отсутствующие в файле сервисы, экземпляры, разрешения, роли, группы и назначения удаляются.
+type Reconcile struct {
+	RBACFile string        `yaml:"rbac_file" env:"RECONCILE_RBAC_FILE"`
+	Prune    bool          `yaml:"prune" env:"RECONCILE_PRUNE"`
+	Timeout  time.Duration `yaml:"timeout" env:"RECONCILE_TIMEOUT" env-default:"1m"`
+}
+
+// Grants - настройки удаления назначений учетным записям с истёкшим сроком действия. С интервалом CleanupInterval
+// истёкшие назначения ролей, групп и разрешений экземпляров удаляются из постоянного хранилища, а кэш разрешений
+// затронутых учетных записей обновляется.
+type Grants struct {
+	CleanupInterval time.Duration `yaml:"cleanup_interval" env:"GRANTS_CLEANUP_INTERVAL" env-default:"1m"`
+}
+
+// Purge - настройки окончательного удаления учетных записей, сервисов, экземпляров, разрешений, ролей и групп,
+// помеченных удаленными. С интервалом Interval из постоянного хранилища удаляются сущности, помеченные удаленными
+// раньше, чем Retention назад. До этого их можно восстановить вместе с назначениями.
+type Purge struct {
+	Interval  time.Duration `yaml:"interval" env:"PURGE_INTERVAL" env-default:"1h"`
+	Retention time.Duration `yaml:"retention" env:"PURGE_RETENTION" env-default:"720h"`
 }
 
 // MustLoad возвращает конфигурацию, считанную из файла, путь к которому передан из командной строки по флагу config или
@@ -114,22 +197,30 @@ type Secure struct {
 // использовать переменных окружения (описанные в структурах данных в этом файле).
 func MustLoad() *Config {
 	var configPath = flag.String("config", "", "путь к файлу конфигурации")
-	var cfg Config
 
 	flag.Parse()
 
-	if *configPath == "" {
-		*configPath = os.Getenv("SECURE_CONFIG_PATH")
+	return MustLoadFrom(*configPath)
+}
+
+// MustLoadFrom возвращает конфигурацию, считанную из файла configPath, а если путь пуст - из файла, путь к которому
+// содержится в переменной окружения SECURE_CONFIG_PATH. Если файл не найден или не может быть прочитан, работа
+// приложения завершается.
+func MustLoadFrom(configPath string) *Config {
+	var cfg Config
+
+	if configPath == "" {
+		configPath = os.Getenv("SECURE_CONFIG_PATH")
 	}
-	if *configPath == "" {
+	if configPath == "" {
 		log.Fatal("config path is not set")
 	}
 
-	if _, err := os.Stat(*configPath); os.IsNotExist(err) {
-		log.Fatalf("config file does not exist: %s", *configPath)
+	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		log.Fatalf("config file does not exist: %s", configPath)
 	}
 
-	if err := cleanenv.ReadConfig(*configPath, &cfg); err != nil {
+	if err := cleanenv.ReadConfig(configPath, &cfg); err != nil {
 		log.Fatalf("cannot read config: %s", err)
 	}
 
@@ -0,0 +1,12 @@
+package account_type
+
+// Типы учетных записей.
+const (
+	Human   = "human"   // Учетная запись человека
+	Service = "service" // Учетная запись сервиса
+)
+
+// Valid возвращает true, если accountType является известным типом учетной записи.
+func Valid(accountType string) bool {
+	return accountType == Human || accountType == Service
+}
@@ -0,0 +1,19 @@
+package grant_source
+
+// Пути, по которым учетная запись получает разрешение.
+const (
+	Instance  = "instance"   // Разрешение назначено учетной записи для экземпляра сервиса
+	Role      = "role"       // Разрешение входит в роль учетной записи
+	Group     = "group"      // Разрешение назначено группе учетной записи
+	GroupRole = "group_role" // Разрешение входит в роль, назначенную группе учетной записи
+
+	GlobalGroupRole = "global_group_role" // Разрешение входит в роль, назначенную глобальной группе учетной записи
+
+	InstanceRole      = "instance_role"       // Разрешение входит в роль, назначенную учетной записи для экземпляра
+	InstanceGroup     = "instance_group"      // Разрешение назначено группе учетной записи в экземпляре
+	InstanceGroupRole = "instance_group_role" // Разрешение входит в роль группы учетной записи в экземпляре
+
+	Selector = "selector" // Разрешение назначено для экземпляров, атрибуты которых соответствуют селектору
+
+	Condition = "condition" // Разрешение назначено с условием, выполненным для запроса
+)
@@ -0,0 +1,47 @@
+package label_selector
+
+import (
+	"errors"
+	"github.com/lazylex/watch-store/secure/pkg/condition"
+	"sort"
+	"strings"
+)
+
+// Selector селектор меток экземпляров сервиса: экземпляр подходит, если у него есть все перечисленные метки с
+// указанными значениями.
+type Selector map[string]string
+
+var ErrInvalidSelector = errors.New("invalid label selector")
+
+// Parse разбирает селектор вида city=Донецк,tier=gold. Названия меток должны быть допустимыми названиями атрибутов
+// экземпляра, значения не могут быть пустыми и содержать запятую, каждая метка указывается не более одного раза.
+func Parse(text string) (Selector, error) {
+	selector := make(Selector)
+
+	for _, term := range strings.Split(text, ",") {
+		name, value, found := strings.Cut(term, "=")
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+		if !found || !condition.ValidAttributeName(name) || len(value) == 0 {
+			return nil, ErrInvalidSelector
+		}
+
+		if _, duplicate := selector[name]; duplicate {
+			return nil, ErrInvalidSelector
+		}
+
+		selector[name] = value
+	}
+
+	return selector, nil
+}
+
+// String возвращает каноническую запись селектора: пары название=значение через запятую в порядке возрастания.
+func (s Selector) String() string {
+	terms := make([]string, 0, len(s))
+	for name, value := range s {
+		terms = append(terms, name+"="+value)
+	}
+	sort.Strings(terms)
+
+	return strings.Join(terms, ",")
+}
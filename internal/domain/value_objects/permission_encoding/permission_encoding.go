@@ -0,0 +1,12 @@
+package permission_encoding
+
+// Способы кодирования номеров разрешений в токенах сервиса.
+const (
+	List   = "list"   // JSON-массив номеров
+	Bitmap = "bitmap" // Битовая карта в кодировке base64url
+)
+
+// Valid возвращает true, если encoding является известным способом кодирования.
+func Valid(encoding string) bool {
+	return encoding == List || encoding == Bitmap
+}
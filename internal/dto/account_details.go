@@ -0,0 +1,28 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+	"github.com/lazylex/watch-store/secure/internal/domain/value_objects/account_state"
+	"github.com/lazylex/watch-store/secure/internal/domain/value_objects/login"
+	"time"
+)
+
+type AccountDetails struct {
+	UserId              uuid.UUID            `json:"user_id"`
+	Login               login.Login          `json:"login"`
+	State               account_state.State  `json:"state"`
+	Type                string               `json:"type"`
+	DisplayName         string               `json:"display_name"`
+	Contact             string               `json:"contact"`
+	OwnerTeam           string               `json:"owner_team"`
+	CreatedAt           time.Time            `json:"created_at"`
+	UpdatedAt           time.Time            `json:"updated_at"`
+	LastLoginAt         *time.Time           `json:"last_login_at"`
+	Roles               []NameService        `json:"roles"`
+	Groups              []NameService        `json:"groups"`
+	InstancePermissions []InstancePermission `json:"instance_permissions"`
+	InstanceRoles       []NameInstance       `json:"instance_roles"`
+	InstanceGroups      []NameInstance       `json:"instance_groups"`
+	SelectorPermissions []SelectorPermission `json:"selector_permissions"`
+	GlobalGroups        []string             `json:"global_groups"`
+}
@@ -0,0 +1,13 @@
+package dto
+
+import "github.com/google/uuid"
+
+type AuthorizationCode struct {
+	Code          string    `json:"code"`
+	UserId        uuid.UUID `json:"user_id"`
+	ClientId      string    `json:"client_id"`
+	RedirectURI   string    `json:"redirect_uri"`
+	CodeChallenge string    `json:"code_challenge"`
+	Nonce         string    `json:"nonce"`
+	Scope         string    `json:"scope"`
+}
@@ -0,0 +1,14 @@
+package dto
+
+import "github.com/google/uuid"
+
+type AuthorizationRequest struct {
+	UserId              uuid.UUID `json:"user_id"`
+	ClientId            string    `json:"client_id"`
+	RedirectURI         string    `json:"redirect_uri"`
+	ResponseType        string    `json:"response_type"`
+	Scope               string    `json:"scope"`
+	Nonce               string    `json:"nonce"`
+	CodeChallenge       string    `json:"code_challenge"`
+	CodeChallengeMethod string    `json:"code_challenge_method"`
+}
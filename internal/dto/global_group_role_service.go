@@ -0,0 +1,7 @@
+package dto
+
+type GlobalGroupRoleService struct {
+	GlobalGroup string `json:"global_group"`
+	Role        string `json:"role"`
+	Service     string `json:"service"`
+}
@@ -0,0 +1,15 @@
+package dto
+
+type GrantPath struct {
+	Source        string   `json:"source"`
+	Instance      string   `json:"instance,omitempty"`
+	Group         string   `json:"group,omitempty"`
+	ParentGroups  []string `json:"parent_groups,omitempty"`
+	GlobalGroup   string   `json:"global_group,omitempty"`
+	Role          string   `json:"role,omitempty"`
+	InheritedFrom []string `json:"inherited_from,omitempty"`
+	Selector      string   `json:"selector,omitempty"`
+	Condition     string   `json:"condition,omitempty"`
+	ConditionMet  *bool    `json:"condition_met,omitempty"`
+	Path          string   `json:"path"`
+}
@@ -0,0 +1,10 @@
+package dto
+
+type GroupDetails struct {
+	Name        string       `json:"name"`
+	Service     string       `json:"service"`
+	Description string       `json:"description"`
+	Subgroups   []string     `json:"subgroups"`
+	Roles       []string     `json:"roles"`
+	Permissions []NameNumber `json:"permissions"`
+}
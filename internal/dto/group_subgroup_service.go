@@ -0,0 +1,7 @@
+package dto
+
+type GroupSubgroupService struct {
+	Group    string `json:"group"`
+	Subgroup string `json:"subgroup"`
+	Service  string `json:"service"`
+}
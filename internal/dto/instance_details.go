@@ -0,0 +1,7 @@
+package dto
+
+type InstanceDetails struct {
+	Name       string            `json:"name"`
+	Service    string            `json:"service"`
+	Attributes map[string]string `json:"attributes"`
+}
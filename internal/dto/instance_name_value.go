@@ -0,0 +1,7 @@
+package dto
+
+type InstanceNameValue struct {
+	Instance string `json:"instance"`
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+}
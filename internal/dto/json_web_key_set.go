@@ -0,0 +1,5 @@
+package dto
+
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
@@ -0,0 +1,11 @@
+package dto
+
+type ListQuery struct {
+	Service    string `json:"service"`
+	Filter     string `json:"filter"`
+	After      string `json:"after"`
+	Limit      int    `json:"limit"`
+	Descending bool   `json:"descending"`
+	Type       string `json:"type"`
+	OwnerTeam  string `json:"owner_team"`
+}
@@ -0,0 +1,13 @@
+package dto
+
+import (
+	"github.com/lazylex/watch-store/secure/internal/domain/value_objects/login"
+	"github.com/lazylex/watch-store/secure/internal/domain/value_objects/password"
+)
+
+type LoginPasswordInstance struct {
+	Login    login.Login       `json:"login"`
+	Password password.Password `json:"password"`
+	Instance string            `json:"instance"`
+	Address  string            `json:"address"`
+}
@@ -0,0 +1,6 @@
+package dto
+
+type NameDescriptionPage struct {
+	Items      []NameDescription `json:"items"`
+	NextCursor string            `json:"next_cursor,omitempty"`
+}
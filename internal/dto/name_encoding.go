@@ -0,0 +1,6 @@
+package dto
+
+type NameEncoding struct {
+	Name     string `json:"name"`
+	Encoding string `json:"encoding"`
+}
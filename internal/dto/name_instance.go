@@ -0,0 +1,6 @@
+package dto
+
+type NameInstance struct {
+	Name     string `json:"name"`
+	Instance string `json:"instance"`
+}
@@ -0,0 +1,8 @@
+package dto
+
+type NameNumberDescriptionDeprecated struct {
+	Name        string `json:"name"`
+	Number      int    `json:"number"`
+	Description string `json:"description"`
+	Deprecated  bool   `json:"deprecated"`
+}
@@ -0,0 +1,6 @@
+package dto
+
+type NameServicePage struct {
+	Items      []NameService `json:"items"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+}
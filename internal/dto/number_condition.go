@@ -0,0 +1,6 @@
+package dto
+
+type NumberCondition struct {
+	Number    int    `json:"number"`
+	Condition string `json:"condition"`
+}
@@ -0,0 +1,7 @@
+package dto
+
+type NumberNameGrantPath struct {
+	Number    int       `json:"number"`
+	Name      string    `json:"name"`
+	GrantPath GrantPath `json:"grant_path"`
+}
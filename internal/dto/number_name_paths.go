@@ -0,0 +1,7 @@
+package dto
+
+type NumberNamePaths struct {
+	Number int         `json:"number"`
+	Name   string      `json:"name"`
+	Paths  []GrantPath `json:"paths"`
+}
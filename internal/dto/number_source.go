@@ -0,0 +1,6 @@
+package dto
+
+type NumberSource struct {
+	Number int    `json:"number"`
+	Source string `json:"source"`
+}
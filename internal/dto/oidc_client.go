@@ -0,0 +1,8 @@
+package dto
+
+type OIDCClient struct {
+	ClientId     string   `json:"client_id"`
+	Name         string   `json:"name"`
+	SecretHash   string   `json:"secret_hash"`
+	RedirectURIs []string `json:"redirect_uris"`
+}
@@ -0,0 +1,8 @@
+package dto
+
+type OIDCClientRegistration struct {
+	ClientId     string   `json:"client_id"`
+	Name         string   `json:"name"`
+	RedirectURIs []string `json:"redirect_uris"`
+	Confidential bool     `json:"confidential"`
+}
@@ -0,0 +1,9 @@
+package dto
+
+import "time"
+
+type OIDCTokens struct {
+	AccessToken string        `json:"access_token"`
+	IDToken     string        `json:"id_token"`
+	TTL         time.Duration `json:"ttl"`
+}
@@ -0,0 +1,11 @@
+package dto
+
+type PageRequest struct {
+	Service    string `json:"service"`
+	Filter     string `json:"filter"`
+	Cursor     string `json:"cursor"`
+	Limit      int    `json:"limit"`
+	Descending bool   `json:"descending"`
+	Type       string `json:"type"`
+	OwnerTeam  string `json:"owner_team"`
+}
@@ -0,0 +1,11 @@
+package dto
+
+import "github.com/google/uuid"
+
+type PermissionDecision struct {
+	UserId     uuid.UUID `json:"user_id"`
+	Instance   string    `json:"instance"`
+	Permission int       `json:"permission"`
+	Allowed    bool      `json:"allowed"`
+	Reasons    []string  `json:"reasons"`
+}
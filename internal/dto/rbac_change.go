@@ -0,0 +1,7 @@
+package dto
+
+type RBACChange struct {
+	Action string `json:"action"`
+	Kind   string `json:"kind"`
+	Target string `json:"target"`
+}
@@ -0,0 +1,8 @@
+package dto
+
+type RBACDocument struct {
+	Version      int               `json:"version"`
+	Services     []RBACService     `json:"services"`
+	GlobalGroups []RBACGlobalGroup `json:"global_groups"`
+	Accounts     []AccountDetails  `json:"accounts"`
+}
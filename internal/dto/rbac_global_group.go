@@ -0,0 +1,7 @@
+package dto
+
+type RBACGlobalGroup struct {
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	Roles       []NameService `json:"roles"`
+}
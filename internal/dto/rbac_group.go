@@ -0,0 +1,9 @@
+package dto
+
+type RBACGroup struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Subgroups   []string `json:"subgroups"`
+	Roles       []string `json:"roles"`
+	Permissions []string `json:"permissions"`
+}
@@ -0,0 +1,7 @@
+package dto
+
+type RBACImportResult struct {
+	Applied   bool         `json:"applied"`
+	Changes   []RBACChange `json:"changes"`
+	Conflicts []string     `json:"conflicts"`
+}
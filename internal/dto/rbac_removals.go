@@ -0,0 +1,23 @@
+package dto
+
+type RBACRemovals struct {
+	Services                   []string                   `json:"services"`
+	Instances                  []string                   `json:"instances"`
+	Permissions                []NameService              `json:"permissions"`
+	Roles                      []NameService              `json:"roles"`
+	Groups                     []NameService              `json:"groups"`
+	RolePermissions            []PermissionRoleService    `json:"role_permissions"`
+	RoleParents                []RoleParentService        `json:"role_parents"`
+	GroupRoles                 []GroupRoleService         `json:"group_roles"`
+	GroupPermissions           []GroupPermissionService   `json:"group_permissions"`
+	GroupSubgroups             []GroupSubgroupService     `json:"group_subgroups"`
+	AccountRoles               []UserIdRoleService        `json:"account_roles"`
+	AccountGroups              []UserIdGroupService       `json:"account_groups"`
+	AccountInstancePermissions []UserIdInstancePermission `json:"account_instance_permissions"`
+	AccountInstanceRoles       []UserIdInstanceRole       `json:"account_instance_roles"`
+	AccountInstanceGroups      []UserIdInstanceGroup      `json:"account_instance_groups"`
+	AccountSelectorPermissions []UserIdSelectorPermission `json:"account_selector_permissions"`
+	GlobalGroups               []string                   `json:"global_groups"`
+	GlobalGroupRoles           []GlobalGroupRoleService   `json:"global_group_roles"`
+	AccountGlobalGroups        []UserIdGlobalGroup        `json:"account_global_groups"`
+}
@@ -0,0 +1,8 @@
+package dto
+
+type RBACRole struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Parents     []string `json:"parents"`
+	Permissions []string `json:"permissions"`
+}
@@ -0,0 +1,12 @@
+package dto
+
+type RBACService struct {
+	Name               string                            `json:"name"`
+	Description        string                            `json:"description"`
+	PermissionEncoding string                            `json:"permission_encoding"`
+	Instances          []string                          `json:"instances"`
+	Permissions        []NameNumberDescriptionDeprecated `json:"permissions"`
+	RetiredNumbers     []int                             `json:"retired_numbers"`
+	Roles              []RBACRole                        `json:"roles"`
+	Groups             []RBACGroup                       `json:"groups"`
+}
@@ -0,0 +1,9 @@
+package dto
+
+type RoleDetails struct {
+	Name        string       `json:"name"`
+	Service     string       `json:"service"`
+	Description string       `json:"description"`
+	Parents     []string     `json:"parents"`
+	Permissions []NameNumber `json:"permissions"`
+}
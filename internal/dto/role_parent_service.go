@@ -0,0 +1,7 @@
+package dto
+
+type RoleParentService struct {
+	Role    string `json:"role"`
+	Parent  string `json:"parent"`
+	Service string `json:"service"`
+}
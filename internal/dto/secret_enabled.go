@@ -0,0 +1,6 @@
+package dto
+
+type SecretEnabled struct {
+	Secret  string `json:"secret"`
+	Enabled bool   `json:"enabled"`
+}
@@ -0,0 +1,6 @@
+package dto
+
+type SecretURI struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
@@ -0,0 +1,7 @@
+package dto
+
+type SelectorPermission struct {
+	Permission string `json:"permission"`
+	Service    string `json:"service"`
+	Selector   string `json:"selector"`
+}
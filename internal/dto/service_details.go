@@ -0,0 +1,12 @@
+package dto
+
+type ServiceDetails struct {
+	Name               string                            `json:"name"`
+	Description        string                            `json:"description"`
+	PermissionEncoding string                            `json:"permission_encoding"`
+	Instances          []string                          `json:"instances"`
+	Permissions        []NameNumberDescriptionDeprecated `json:"permissions"`
+	RetiredNumbers     []int                             `json:"retired_numbers"`
+	Roles              []string                          `json:"roles"`
+	Groups             []string                          `json:"groups"`
+}
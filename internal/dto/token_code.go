@@ -0,0 +1,6 @@
+package dto
+
+type TokenCode struct {
+	Token string `json:"token"`
+	Code  string `json:"code"`
+}
@@ -0,0 +1,14 @@
+package dto
+
+import "github.com/google/uuid"
+
+type TokenIntrospection struct {
+	Active      bool      `json:"active"`
+	UserId      uuid.UUID `json:"sub"`
+	Instance    string    `json:"aud"`
+	Service     string    `json:"service"`
+	Permissions []string  `json:"permissions"`
+	ExpiresAt   int64     `json:"exp"`
+	IssuedAt    int64     `json:"iat"`
+	TokenId     string    `json:"jti"`
+}
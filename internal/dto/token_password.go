@@ -0,0 +1,8 @@
+package dto
+
+import "github.com/lazylex/watch-store/secure/internal/domain/value_objects/password"
+
+type TokenPassword struct {
+	Token    string            `json:"token"`
+	Password password.Password `json:"password"`
+}
@@ -0,0 +1,8 @@
+package dto
+
+import "time"
+
+type TokenTTL struct {
+	Token string        `json:"token"`
+	TTL   time.Duration `json:"ttl"`
+}
@@ -0,0 +1,11 @@
+package dto
+
+import "github.com/google/uuid"
+
+type UserIdAccountMetadata struct {
+	UserId      uuid.UUID `json:"user_id"`
+	Type        string    `json:"type"`
+	DisplayName string    `json:"display_name"`
+	Contact     string    `json:"contact"`
+	OwnerTeam   string    `json:"owner_team"`
+}
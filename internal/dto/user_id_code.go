@@ -0,0 +1,8 @@
+package dto
+
+import "github.com/google/uuid"
+
+type UserIdCode struct {
+	UserId uuid.UUID `json:"user_id"`
+	Code   string    `json:"code"`
+}
@@ -0,0 +1,8 @@
+package dto
+
+import "github.com/google/uuid"
+
+type UserIdCodes struct {
+	UserId uuid.UUID `json:"user_id"`
+	Codes  []string  `json:"codes"`
+}
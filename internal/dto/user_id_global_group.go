@@ -0,0 +1,14 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type UserIdGlobalGroup struct {
+	GlobalGroup string     `json:"global_group"`
+	UserId      uuid.UUID  `json:"user_id"`
+	ValidFrom   *time.Time `json:"valid_from,omitempty"`
+	ValidUntil  *time.Time `json:"valid_until,omitempty"`
+	Condition   string     `json:"condition,omitempty"`
+}
@@ -1,9 +1,15 @@
 package dto
 
-import "github.com/google/uuid"
+import (
+	"github.com/google/uuid"
+	"time"
+)
 
 type UserIdGroupService struct {
-	Group   string    `json:"group"`
-	Service string    `json:"service"`
-	UserId  uuid.UUID `json:"user_id"`
+	Group      string     `json:"group"`
+	Service    string     `json:"service"`
+	UserId     uuid.UUID  `json:"user_id"`
+	ValidFrom  *time.Time `json:"valid_from,omitempty"`
+	ValidUntil *time.Time `json:"valid_until,omitempty"`
+	Condition  string     `json:"condition,omitempty"`
 }
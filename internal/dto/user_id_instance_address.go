@@ -0,0 +1,9 @@
+package dto
+
+import "github.com/google/uuid"
+
+type UserIdInstanceAddress struct {
+	UserId   uuid.UUID `json:"user_id"`
+	Instance string    `json:"instance"`
+	Address  string    `json:"address"`
+}
@@ -0,0 +1,15 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type UserIdInstanceGroup struct {
+	Group      string     `json:"group"`
+	Instance   string     `json:"instance"`
+	UserId     uuid.UUID  `json:"user_id"`
+	ValidFrom  *time.Time `json:"valid_from,omitempty"`
+	ValidUntil *time.Time `json:"valid_until,omitempty"`
+	Condition  string     `json:"condition,omitempty"`
+}
@@ -0,0 +1,9 @@
+package dto
+
+import "github.com/google/uuid"
+
+type UserIdInstanceNumber struct {
+	UserId   uuid.UUID `json:"user_id"`
+	Instance string    `json:"instance"`
+	Number   int       `json:"permission"`
+}
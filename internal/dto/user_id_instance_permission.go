@@ -1,9 +1,15 @@
 package dto
 
-import "github.com/google/uuid"
+import (
+	"github.com/google/uuid"
+	"time"
+)
 
 type UserIdInstancePermission struct {
-	Instance   string    `json:"instance"`
-	Permission string    `json:"permission"`
-	UserId     uuid.UUID `json:"user_id"`
+	Instance   string     `json:"instance"`
+	Permission string     `json:"permission"`
+	UserId     uuid.UUID  `json:"user_id"`
+	ValidFrom  *time.Time `json:"valid_from,omitempty"`
+	ValidUntil *time.Time `json:"valid_until,omitempty"`
+	Condition  string     `json:"condition,omitempty"`
 }
@@ -0,0 +1,15 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type UserIdInstanceRole struct {
+	Instance   string     `json:"instance"`
+	Role       string     `json:"role"`
+	UserId     uuid.UUID  `json:"user_id"`
+	ValidFrom  *time.Time `json:"valid_from,omitempty"`
+	ValidUntil *time.Time `json:"valid_until,omitempty"`
+	Condition  string     `json:"condition,omitempty"`
+}
@@ -0,0 +1,15 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+	"github.com/lazylex/watch-store/secure/internal/domain/value_objects/account_state"
+	"github.com/lazylex/watch-store/secure/internal/domain/value_objects/login"
+)
+
+type UserIdLoginState struct {
+	UserId      uuid.UUID           `json:"user_id"`
+	Login       login.Login         `json:"login"`
+	State       account_state.State `json:"state"`
+	Type        string              `json:"type"`
+	DisplayName string              `json:"display_name"`
+}
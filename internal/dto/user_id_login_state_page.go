@@ -0,0 +1,6 @@
+package dto
+
+type UserIdLoginStatePage struct {
+	Items      []UserIdLoginState `json:"items"`
+	NextCursor string             `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,12 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+	"github.com/lazylex/watch-store/secure/internal/domain/value_objects/password"
+)
+
+type UserIdOldNewPassword struct {
+	UserId      uuid.UUID         `json:"user_id"`
+	OldPassword password.Password `json:"old_password"`
+	NewPassword password.Password `json:"new_password"`
+}
@@ -1,9 +1,15 @@
 package dto
 
-import "github.com/google/uuid"
+import (
+	"github.com/google/uuid"
+	"time"
+)
 
 type UserIdRoleService struct {
-	UserId  uuid.UUID `json:"user_id"`
-	Role    string    `json:"role"`
-	Service string    `json:"service"`
+	UserId     uuid.UUID  `json:"user_id"`
+	Role       string     `json:"role"`
+	Service    string     `json:"service"`
+	ValidFrom  *time.Time `json:"valid_from,omitempty"`
+	ValidUntil *time.Time `json:"valid_until,omitempty"`
+	Condition  string     `json:"condition,omitempty"`
 }
@@ -0,0 +1,8 @@
+package dto
+
+import "github.com/google/uuid"
+
+type UserIdSecret struct {
+	UserId uuid.UUID `json:"user_id"`
+	Secret string    `json:"secret"`
+}
@@ -0,0 +1,10 @@
+package dto
+
+import "github.com/google/uuid"
+
+type UserIdSelectorPermission struct {
+	UserId     uuid.UUID `json:"user_id"`
+	Permission string    `json:"permission"`
+	Service    string    `json:"service"`
+	Selector   string    `json:"selector"`
+}
@@ -0,0 +1,10 @@
+package dto
+
+import "github.com/google/uuid"
+
+type UserIdServiceInstance struct {
+	UserId   uuid.UUID `json:"user_id"`
+	Service  string    `json:"service"`
+	Instance string    `json:"instance"`
+	Address  string    `json:"address,omitempty"`
+}
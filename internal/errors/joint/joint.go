@@ -11,6 +11,13 @@ var (
 	ErrDataTypeConversion = NewJointError("data type conversion failed")
 	ErrCacheSavedData     = NewJointError("can't save data to cache")
 	ErrNilRepo            = NewJointError("the repository must be initialized, but it's nil")
+
+	ErrRetiredNumber        = NewJointError("permission number is retired")
+	ErrDeprecatedPermission = NewJointError("permission is deprecated")
+	ErrRoleCycle            = NewJointError("role inheritance cycle")
+	ErrGroupCycle           = NewJointError("group nesting cycle")
+	ErrInvalidSelector      = NewJointError("invalid label selector")
+	ErrDeletedEntity        = NewJointError("entity with the same name is deleted")
 )
 
 // FullJointError возвращает полностью заполненную структуру с типом JointType.
@@ -8,6 +8,13 @@ var (
 	ErrDuplicateKeyValue = NewPersistentError("duplicate key value violates unique constraint violation")
 	ErrZeroRowsAffected  = NewPersistentError("zero rows affected")
 	ErrNoRowsInResultSet = NewPersistentError("no rows in result set")
+
+	ErrRetiredNumber        = NewPersistentError("permission number is retired")
+	ErrDeprecatedPermission = NewPersistentError("permission is deprecated")
+	ErrRoleCycle            = NewPersistentError("role inheritance cycle")
+	ErrGroupCycle           = NewPersistentError("group nesting cycle")
+	ErrInvalidSelector      = NewPersistentError("invalid label selector")
+	ErrDeletedEntity        = NewPersistentError("entity with the same name is deleted")
 )
 
 // FullPersistentError возвращает полностью заполненную структуру с типом PersistentType.
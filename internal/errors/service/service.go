@@ -16,6 +16,36 @@ var (
 	ErrNilRepo            = NewServiceError("repository can't be nil")
 	ErrEmptyConfig        = NewServiceError("empty config")
 	ErrEmptyResult        = NewServiceError("empty result")
+	ErrInvalidResetToken  = NewServiceError("invalid or expired password reset token")
+
+	ErrSecondFactorRequired  = NewServiceError("second authentication factor required")
+	ErrInvalidSecondFactor   = NewServiceError("invalid one-time password or recovery code")
+	ErrInvalidLoginChallenge = NewServiceError("invalid or expired login challenge")
+	ErrTOTPAlreadyEnabled    = NewServiceError("two-factor authentication already enabled")
+	ErrTOTPNotEnrolled       = NewServiceError("two-factor authentication is not enrolled")
+
+	ErrNotTokenOwner          = NewServiceError("token belongs to another account")
+	ErrForeignPermissionCheck = NewServiceError("only admins and services can check permissions of another account")
+
+	ErrOIDCDisabled                = NewServiceError("openid connect is not configured")
+	ErrInvalidOIDCClient           = NewServiceError("unknown openid connect client or redirect uri")
+	ErrInvalidAuthorizationRequest = NewServiceError("invalid authorization request")
+	ErrInvalidGrant                = NewServiceError("invalid or expired authorization code")
+	ErrInvalidAccessToken          = NewServiceError("invalid or expired access token")
+
+	ErrInvalidQueryParameters = NewServiceError("invalid query parameters")
+
+	ErrInvalidRBACDocument = NewServiceError("unsupported access control document version")
+	ErrRBACConflict        = NewServiceError("access control document conflicts with current state")
+
+	ErrRetiredNumber        = NewServiceError("permission number is retired and can't be reused")
+	ErrDeprecatedPermission = NewServiceError("deprecated permission can't be assigned")
+	ErrRoleCycle            = NewServiceError("parent role already inherits permissions of the role")
+	ErrGroupCycle           = NewServiceError("subgroup already contains the group")
+	ErrDeletedEntity        = NewServiceError("entity with the same name is deleted, restore it instead of creating")
+
+	ErrAdministratorDeletion = NewServiceError("administrator service and last administrator account can't be deleted")
+	ErrOwnAccountDeletion    = NewServiceError("own account can't be deleted")
 )
 
 // FullServiceError возвращает полностью заполненную структуру с типом JointType.
@@ -2,4 +2,7 @@ package prefixes
 
 const (
 	PPROFPrefix = "/debug/pprof/"
+	AdminPrefix = "/admin/"
+
+	GRPCAdminPrefix = "/secure.v1.Admin/"
 )
@@ -0,0 +1,51 @@
+/*
+Package rbac_document: пакет содержит функции преобразования документа с конфигурацией управления доступом в YAML и
+обратно. В YAML используются те же названия и порядок полей, что и в JSON.
+*/
+package rbac_document
+
+import (
+	"encoding/json"
+	"github.com/lazylex/watch-store/secure/internal/dto"
+	"gopkg.in/yaml.v3"
+)
+
+// ToYAML возвращает документ в YAML.
+func ToYAML(document *dto.RBACDocument) ([]byte, error) {
+	data, err := json.Marshal(document)
+	if err != nil {
+		return nil, err
+	}
+
+	var node yaml.Node
+	if err = yaml.Unmarshal(data, &node); err != nil {
+		return nil, err
+	}
+	resetStyle(&node)
+
+	return yaml.Marshal(&node)
+}
+
+// FromYAML считывает документ из YAML. Так как JSON является подмножеством YAML, документ может быть передан и в JSON.
+func FromYAML(data []byte, document *dto.RBACDocument) error {
+	var value any
+	if err := yaml.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	converted, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(converted, document)
+}
+
+// resetStyle сбрасывает унаследованный от JSON стиль узлов (кавычки и flow-стиль), чтобы документ выводился в блочном
+// стиле YAML.
+func resetStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		resetStyle(child)
+	}
+}
@@ -1,7 +1,7 @@
 /*
 Package metrics: пакет предназначен для сбора метрик, доступных для хранения в Prometheus. Возврат заполненной
 структуры для работы с метриками производится при вызове функции MustCreate. Функция принимает указатель на
-конфигурацию config.Prometheus и производит запуск http-сервера для сбора метрик. В случае ошибки запуска сервера
-программа останавливается.
+конфигурацию config.Prometheus и производит запуск http-сервера для сбора метрик. Если в конфигурации задан сертификат
+TLS, сервер работает по протоколу HTTPS. В случае ошибки запуска сервера программа останавливается.
 */
 package metrics
@@ -0,0 +1,53 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type GRPC struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+// RequestsTotalInc инкремент счетчика запросов.
+func (g *GRPC) RequestsTotalInc(labels map[string]string) {
+	g.requests.With(labels).Inc()
+}
+
+// RequestsDurationObserve внесение данных о длительности запроса.
+func (g *GRPC) RequestsDurationObserve(duration float64) {
+	g.duration.With(prometheus.Labels{}).Observe(duration)
+}
+
+// createGRPCRequestDurationSecondsBucketMetric создает и регистрирует метрику grpc_request_duration_seconds_bucket.
+func createGRPCRequestDurationSecondsBucketMetric() (*prometheus.HistogramVec, error) {
+	var err error
+	requestDuration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: NAMESPACE,
+		Name:      "grpc_request_duration_seconds_bucket",
+		Help:      "duration of the grpc request",
+	}, []string{})
+	if err = prometheus.Register(requestDuration); err != nil {
+		return nil, err
+	}
+
+	requestDuration.With(prometheus.Labels{})
+
+	return requestDuration, nil
+}
+
+// createGRPCRequestsTotalMetric создает и регистрирует метрику grpc_requests_total, являющуюся счетчиком
+// gRPC-запросов. Метка method содержит полное название вызванного метода, метка code - код статуса ответа.
+func createGRPCRequestsTotalMetric() (*prometheus.CounterVec, error) {
+	var err error
+	requests := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name:      "grpc_requests_total",
+		Namespace: NAMESPACE,
+		Help:      "Count of grpc requests",
+	}, []string{METHOD, CODE})
+	if err = prometheus.Register(requests); err != nil {
+		return nil, err
+	}
+
+	return requests, nil
+}
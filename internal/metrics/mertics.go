@@ -4,8 +4,10 @@ import (
 	"errors"
 	"fmt"
 	"github.com/lazylex/watch-store/secure/internal/config"
+	grpcMetrics "github.com/lazylex/watch-store/secure/internal/ports/metrics/grpc"
 	httpMetrics "github.com/lazylex/watch-store/secure/internal/ports/metrics/http"
 	"github.com/lazylex/watch-store/secure/internal/ports/metrics/service"
+	"github.com/lazylex/watch-store/secure/internal/tls_config"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"log/slog"
@@ -16,11 +18,14 @@ import (
 const (
 	NAMESPACE = "secure"
 	PATH      = "path"
+	METHOD    = "method"
+	CODE      = "code"
 )
 
 // Metrics структура, содержащая объекты, реализующие интерфейсы для сбора метрик.
 type Metrics struct {
 	HTTP    httpMetrics.MetricsInterface
+	GRPC    grpcMetrics.MetricsInterface
 	Service service.MetricsInterface
 }
 
@@ -38,7 +43,7 @@ func MustCreate(cfg *config.Prometheus) *Metrics {
 		url = cfg.PrometheusMetricsURL
 	}
 
-	startHTTP(url, port)
+	startHTTP(url, port, &cfg.TLS)
 
 	metrics, err := registerMetrics()
 	if err != nil {
@@ -52,8 +57,8 @@ func MustCreate(cfg *config.Prometheus) *Metrics {
 // registerMetrics заносит метрики в регистр и возвращает их. При неудаче возвращает ошибку.
 func registerMetrics() (*Metrics, error) {
 	var err error
-	var loginMetric, authErrMetric, logoutMetric, requests *prometheus.CounterVec
-	var requestDuration *prometheus.HistogramVec
+	var loginMetric, authErrMetric, logoutMetric, requests, grpcRequests *prometheus.CounterVec
+	var requestDuration, grpcRequestDuration *prometheus.HistogramVec
 
 	if requests, err = createHTTPRequestsTotalMetric(); err != nil {
 		return nil, err
@@ -63,6 +68,14 @@ func registerMetrics() (*Metrics, error) {
 		return nil, err
 	}
 
+	if grpcRequests, err = createGRPCRequestsTotalMetric(); err != nil {
+		return nil, err
+	}
+
+	if grpcRequestDuration, err = createGRPCRequestDurationSecondsBucketMetric(); err != nil {
+		return nil, err
+	}
+
 	if loginMetric, err = createLoginTotalMetric(); err != nil {
 		return nil, err
 	}
@@ -77,18 +90,30 @@ func registerMetrics() (*Metrics, error) {
 
 	return &Metrics{
 		&HTTP{requests: requests, duration: requestDuration},
+		&GRPC{requests: grpcRequests, duration: grpcRequestDuration},
 		&Service{loginMetric, logoutMetric, authErrMetric},
 	}, nil
 }
 
-// startHTTP запускает http сервер для связи с Prometheus на переданном в функцию порту и url. При неудаче выводит
-// ошибку в лог и останавливает программу.
-func startHTTP(url, port string) {
+// startHTTP запускает сервер для связи с Prometheus на переданном в функцию порту и url. Если в конфигурации TLS задан
+// сертификат, сервер работает по протоколу HTTPS. При неудаче выводит ошибку в лог и останавливает программу.
+func startHTTP(url, port string, tlsCfg *config.TLS) {
+	mux := http.NewServeMux()
+	mux.Handle(url, promhttp.Handler())
+	srv := &http.Server{Addr: ":" + port, Handler: mux}
+
+	if tlsCfg.Enabled() {
+		srv.TLSConfig = tls_config.MustCreate(tlsCfg)
+	}
+
 	go func() {
-		mux := http.NewServeMux()
-		mux.Handle(url, promhttp.Handler())
+		var err error
 		slog.Info(fmt.Sprintf(":%s%s ready for prometheus", port, url))
-		err := http.ListenAndServe(":"+port, mux)
+		if srv.TLSConfig != nil {
+			err = srv.ListenAndServeTLS("", "")
+		} else {
+			err = srv.ListenAndServe()
+		}
 		if err != nil && !errors.Is(err, http.ErrServerClosed) {
 			slog.Error("can't start http server for prometheus")
 			os.Exit(1)
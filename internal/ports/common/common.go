@@ -2,11 +2,12 @@ package common
 
 import (
 	"context"
+	"github.com/google/uuid"
 	"github.com/lazylex/watch-store/secure/internal/dto"
 )
 
 type RBACCreateInterface interface {
-	CreatePermission(context.Context, *dto.NameServiceDescription) error
+	CreatePermission(context.Context, *dto.NameNumberDescriptionService) error
 	CreateRole(context.Context, *dto.NameServiceDescription) error
 	CreateGroup(context.Context, *dto.NameServiceDescription) error
 }
@@ -15,16 +16,59 @@ type RBACAssignToAccountInterface interface {
 	AssignRoleToAccount(context.Context, *dto.UserIdRoleService) error
 	AssignGroupToAccount(context.Context, *dto.UserIdGroupService) error
 	AssignInstancePermissionToAccount(context.Context, *dto.UserIdInstancePermission) error
+	AssignInstanceRoleToAccount(context.Context, *dto.UserIdInstanceRole) error
+	AssignInstanceGroupToAccount(context.Context, *dto.UserIdInstanceGroup) error
+	AssignSelectorPermissionToAccount(context.Context, *dto.UserIdSelectorPermission) error
 }
 
 type RBACAssignInterface interface {
 	AssignRoleToGroup(context.Context, *dto.GroupRoleService) error
 	AssignPermissionToRole(context.Context, *dto.PermissionRoleService) error
+	AssignParentToRole(context.Context, *dto.RoleParentService) error
 	AssignPermissionToGroup(context.Context, *dto.GroupPermissionService) error
+	AssignSubgroupToGroup(context.Context, *dto.GroupSubgroupService) error
+}
+
+type RBACGlobalGroupInterface interface {
+	CreateGlobalGroup(context.Context, *dto.NameDescription) error
+	AssignRoleToGlobalGroup(context.Context, *dto.GlobalGroupRoleService) error
+	AssignGlobalGroupToAccount(context.Context, *dto.UserIdGlobalGroup) error
+	DeleteGlobalGroup(context.Context, string) error
+}
+
+type InstanceAttributesInterface interface {
+	SetInstanceAttribute(context.Context, *dto.InstanceNameValue) error
+	DeleteInstanceAttribute(context.Context, *dto.NameInstance) error
 }
 
 type RBACDeleteInterface interface {
 	DeleteRole(context.Context, *dto.NameService) error
 	DeleteGroup(context.Context, *dto.NameService) error
 	DeletePermission(context.Context, *dto.NameService) error
+	DeprecatePermission(context.Context, *dto.NameService) error
+	DeleteInstance(context.Context, string) error
+	DeleteService(context.Context, string) error
+}
+
+type RBACRestoreInterface interface {
+	RestoreRole(context.Context, *dto.NameService) error
+	RestoreGroup(context.Context, *dto.NameService) error
+	RestorePermission(context.Context, *dto.NameService) error
+	RestoreInstance(context.Context, string) error
+	RestoreService(context.Context, string) error
+	RestoreAccount(context.Context, uuid.UUID) error
+}
+
+type ReadInterface interface {
+	Services(context.Context, *dto.ListQuery) ([]dto.NameDescription, error)
+	Instances(context.Context, *dto.ListQuery) ([]dto.NameService, error)
+	Roles(context.Context, *dto.ListQuery) ([]dto.NameDescription, error)
+	Groups(context.Context, *dto.ListQuery) ([]dto.NameDescription, error)
+	Accounts(context.Context, *dto.ListQuery) ([]dto.UserIdLoginState, error)
+
+	ServiceDetails(context.Context, string) (dto.ServiceDetails, error)
+	RoleDetails(context.Context, *dto.NameService) (dto.RoleDetails, error)
+	GroupDetails(context.Context, *dto.NameService) (dto.GroupDetails, error)
+	AccountDetails(context.Context, uuid.UUID) (dto.AccountDetails, error)
+	GlobalGroups(context.Context) ([]dto.RBACGlobalGroup, error)
 }
@@ -0,0 +1,11 @@
+package message_broker
+
+import (
+	"context"
+	"github.com/lazylex/watch-store/secure/internal/dto"
+)
+
+// RBACChangesInterface интерфейс отправки событий об изменениях конфигурации управления доступом.
+type RBACChangesInterface interface {
+	PublishRBACChanges(context.Context, []dto.RBACChange) error
+}
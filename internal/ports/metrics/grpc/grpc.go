@@ -0,0 +1,7 @@
+package grpc
+
+//go:generate mockgen -source=grpc.go -destination=mocks/grpc.go
+type MetricsInterface interface {
+	RequestsTotalInc(map[string]string)
+	RequestsDurationObserve(float64)
+}
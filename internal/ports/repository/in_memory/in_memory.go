@@ -17,8 +17,18 @@ type LoginInterface interface {
 	DeleteSession(context.Context, uuid.UUID) error
 	SetUserIdAndPasswordHash(context.Context, *dto.UserIdLoginHash)
 	UserIdAndPasswordHash(context.Context, login.Login) (dto.UserIdHash, error)
+	DeleteUserIdAndPasswordHash(context.Context, login.Login) error
 	SetAccountState(ctx context.Context, stateDTO *dto.LoginState) error
 	AccountStateByLogin(context.Context, login.Login) (account_state.State, error)
+	SaveResetToken(context.Context, *dto.UserIdToken) error
+	UserIdFromResetToken(context.Context, string) (uuid.UUID, error)
+	SaveLoginChallenge(context.Context, *dto.UserIdToken) error
+	UserIdFromLoginChallenge(context.Context, string) (uuid.UUID, error)
+	MarkTOTPStepUsed(context.Context, uuid.UUID, uint64) (bool, error)
+	RevokeToken(context.Context, *dto.TokenTTL) error
+	IsTokenRevoked(context.Context, string) (bool, error)
+	SaveAuthorizationCode(context.Context, *dto.AuthorizationCode) error
+	AuthorizationCode(context.Context, string) (dto.AuthorizationCode, error)
 }
 
 type RBACInterface interface {
@@ -33,6 +43,16 @@ type RBACInterface interface {
 
 	SetServiceNumberedPermissions(context.Context, string, *[]dto.NameNumber) error
 	ServiceNumberedPermissions(context.Context, string) (*[]dto.NameNumber, error)
+
+	SetServicePermissionEncoding(context.Context, *dto.NameEncoding) error
+	ServicePermissionEncoding(context.Context, string) (string, error)
+
+	DeleteServiceNumberedPermissions(context.Context, string) error
+	DeleteServicePermissionsNumbers(context.Context, string) error
+	DeleteServicePermissionEncoding(context.Context, string) error
+	DeleteInstancePermissionsNumbers(context.Context, string) error
+	DeleteServicePermissionsNumbersForAccount(context.Context, *dto.UserIdService) error
+	DeleteInstancePermissionsNumbersForAccount(context.Context, *dto.UserIdInstance) error
 }
 
 type InstanceInterface interface {
@@ -41,6 +61,7 @@ type InstanceInterface interface {
 	ServiceName(ctx context.Context, instanceName string) (string, error)
 	SetInstanceSecret(ctx context.Context, data *dto.NameSecret) error
 	InstanceSecret(ctx context.Context, name string) (string, error)
+	DeleteInstance(ctx context.Context, name string) error
 }
 
 type Interface interface {
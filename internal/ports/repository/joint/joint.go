@@ -7,11 +7,13 @@ import (
 	"github.com/lazylex/watch-store/secure/internal/domain/value_objects/login"
 	"github.com/lazylex/watch-store/secure/internal/dto"
 	"github.com/lazylex/watch-store/secure/internal/ports/common"
+	"time"
 )
 
 type ServiceInterface interface {
 	CreateService(context.Context, *dto.NameDescription) error
 	CreateOrUpdateInstance(context.Context, *dto.NameServiceSecret) error
+	SetServicePermissionEncoding(context.Context, *dto.NameEncoding) error
 }
 
 type LoginInterface interface {
@@ -19,11 +21,29 @@ type LoginInterface interface {
 	DeleteSession(context.Context, uuid.UUID) error
 	SessionToken(context.Context, uuid.UUID) (string, error)
 	SetAccountLoginData(context.Context, *dto.UserIdLoginHashState) error
+	CreateAccount(context.Context, *dto.UserIdLoginHashState, *dto.UserIdAccountMetadata) error
 	AccountLoginData(context.Context, login.Login) (dto.UserIdLoginHashState, error)
 	UserIdAndPasswordHash(context.Context, login.Login) (dto.UserIdHash, error)
 	UserUUIDFromSession(ctx context.Context, sessionToken string) (uuid.UUID, error)
 	SetAccountState(context.Context, *dto.LoginState) error
 	AccountState(context.Context, login.Login) (account_state.State, error)
+	AccountLoginDataByUserId(context.Context, uuid.UUID) (dto.UserIdLoginHashState, error)
+	SetAccountPasswordHash(context.Context, *dto.UserIdHash) error
+	SetAccountMetadata(context.Context, *dto.UserIdAccountMetadata) error
+	UpdateAccountLastLogin(context.Context, uuid.UUID) error
+	SaveResetToken(context.Context, *dto.UserIdToken) error
+	UserIdFromResetToken(context.Context, string) (uuid.UUID, error)
+}
+
+type TOTPInterface interface {
+	SaveLoginChallenge(context.Context, *dto.UserIdToken) error
+	UserIdFromLoginChallenge(context.Context, string) (uuid.UUID, error)
+	MarkTOTPStepUsed(context.Context, uuid.UUID, uint64) (bool, error)
+	SetTOTPSecret(context.Context, *dto.UserIdSecret) error
+	TOTPSecret(context.Context, uuid.UUID) (dto.SecretEnabled, error)
+	EnableTOTP(context.Context, *dto.UserIdCodes) error
+	DeleteTOTP(context.Context, uuid.UUID) error
+	UseRecoveryCode(context.Context, *dto.UserIdCode) error
 }
 
 type RBACInterface interface {
@@ -31,20 +51,50 @@ type RBACInterface interface {
 	common.RBACAssignToAccountInterface
 	common.RBACAssignInterface
 	common.RBACDeleteInterface
+	common.RBACRestoreInterface
+	common.RBACGlobalGroupInterface
+	common.InstanceAttributesInterface
+
+	DeleteAccount(context.Context, uuid.UUID) error
+
+	InstanceAttributes(context.Context, string) (map[string]string, error)
+	ConditionalPermissionsForAccount(context.Context, *dto.UserIdInstance) ([]dto.NumberCondition, error)
 
 	ServicePermissionsForAccount(context.Context, *dto.UserIdService) ([]dto.NameNumberDescription, error)
 	ServicePermissionsNumbersForAccount(context.Context, *dto.UserIdService) ([]int, error)
+	ServicePermissionsSourcesForAccount(context.Context, *dto.UserIdService) ([]dto.NumberSource, error)
+	PermissionsGrantPathsForAccount(context.Context, *dto.UserIdServiceInstance) ([]dto.NumberNameGrantPath, error)
 
 	InstancePermissionsNumbersForAccount(context.Context, *dto.UserIdInstance) ([]int, error)
+
+	AccountHasRole(context.Context, *dto.UserIdRoleService) (bool, error)
+	AccountGrantsExpiration(context.Context, *dto.UserIdInstance) (time.Time, error)
+	DeleteExpiredGrants(context.Context, time.Time) error
+	PurgeDeleted(context.Context, time.Time) error
+
+	ImportRBAC(context.Context, *dto.RBACDocument, map[string]string, *dto.RBACRemovals) error
+}
+
+type OIDCInterface interface {
+	CreateOIDCClient(context.Context, *dto.OIDCClient) error
+	OIDCClient(context.Context, string) (dto.OIDCClient, error)
+	SaveAuthorizationCode(context.Context, *dto.AuthorizationCode) error
+	AuthorizationCode(context.Context, string) (dto.AuthorizationCode, error)
 }
 
 //go:generate mockgen -source=joint.go -destination=mocks/joint.go
 type Interface interface {
 	ServiceInterface
 	LoginInterface
+	TOTPInterface
+	OIDCInterface
 	RBACInterface
+	common.ReadInterface
+	RevokeToken(context.Context, *dto.TokenTTL) error
+	IsTokenRevoked(context.Context, string) (bool, error)
 	InstanceSecret(context.Context, string) (string, error)
 	ServiceName(context.Context, string) (string, error)
+	ServicePermissionEncoding(context.Context, string) (string, error)
 	ServiceNumberedPermissions(context.Context, string) (*[]dto.NameNumber, error)
 	ServicesNames(context.Context) ([]string, error)
 }
@@ -7,6 +7,7 @@ package mock_joint
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	uuid "github.com/google/uuid"
@@ -66,6 +67,20 @@ func (mr *MockServiceInterfaceMockRecorder) CreateService(arg0, arg1 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateService", reflect.TypeOf((*MockServiceInterface)(nil).CreateService), arg0, arg1)
 }
 
+// SetServicePermissionEncoding mocks base method.
+func (m *MockServiceInterface) SetServicePermissionEncoding(arg0 context.Context, arg1 *dto.NameEncoding) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetServicePermissionEncoding", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetServicePermissionEncoding indicates an expected call of SetServicePermissionEncoding.
+func (mr *MockServiceInterfaceMockRecorder) SetServicePermissionEncoding(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetServicePermissionEncoding", reflect.TypeOf((*MockServiceInterface)(nil).SetServicePermissionEncoding), arg0, arg1)
+}
+
 // MockLoginInterface is a mock of LoginInterface interface.
 type MockLoginInterface struct {
 	ctrl     *gomock.Controller
@@ -104,6 +119,21 @@ func (mr *MockLoginInterfaceMockRecorder) AccountLoginData(arg0, arg1 interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccountLoginData", reflect.TypeOf((*MockLoginInterface)(nil).AccountLoginData), arg0, arg1)
 }
 
+// AccountLoginDataByUserId mocks base method.
+func (m *MockLoginInterface) AccountLoginDataByUserId(arg0 context.Context, arg1 uuid.UUID) (dto.UserIdLoginHashState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccountLoginDataByUserId", arg0, arg1)
+	ret0, _ := ret[0].(dto.UserIdLoginHashState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AccountLoginDataByUserId indicates an expected call of AccountLoginDataByUserId.
+func (mr *MockLoginInterfaceMockRecorder) AccountLoginDataByUserId(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccountLoginDataByUserId", reflect.TypeOf((*MockLoginInterface)(nil).AccountLoginDataByUserId), arg0, arg1)
+}
+
 // AccountState mocks base method.
 func (m *MockLoginInterface) AccountState(arg0 context.Context, arg1 login.Login) (account_state.State, error) {
 	m.ctrl.T.Helper()
@@ -119,6 +149,20 @@ func (mr *MockLoginInterfaceMockRecorder) AccountState(arg0, arg1 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccountState", reflect.TypeOf((*MockLoginInterface)(nil).AccountState), arg0, arg1)
 }
 
+// CreateAccount mocks base method.
+func (m *MockLoginInterface) CreateAccount(arg0 context.Context, arg1 *dto.UserIdLoginHashState, arg2 *dto.UserIdAccountMetadata) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAccount", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAccount indicates an expected call of CreateAccount.
+func (mr *MockLoginInterfaceMockRecorder) CreateAccount(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccount", reflect.TypeOf((*MockLoginInterface)(nil).CreateAccount), arg0, arg1, arg2)
+}
+
 // DeleteSession mocks base method.
 func (m *MockLoginInterface) DeleteSession(arg0 context.Context, arg1 uuid.UUID) error {
 	m.ctrl.T.Helper()
@@ -133,6 +177,20 @@ func (mr *MockLoginInterfaceMockRecorder) DeleteSession(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSession", reflect.TypeOf((*MockLoginInterface)(nil).DeleteSession), arg0, arg1)
 }
 
+// SaveResetToken mocks base method.
+func (m *MockLoginInterface) SaveResetToken(arg0 context.Context, arg1 *dto.UserIdToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveResetToken", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveResetToken indicates an expected call of SaveResetToken.
+func (mr *MockLoginInterfaceMockRecorder) SaveResetToken(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveResetToken", reflect.TypeOf((*MockLoginInterface)(nil).SaveResetToken), arg0, arg1)
+}
+
 // SaveSession mocks base method.
 func (m *MockLoginInterface) SaveSession(arg0 context.Context, arg1 *dto.UserIdToken) error {
 	m.ctrl.T.Helper()
@@ -176,6 +234,34 @@ func (mr *MockLoginInterfaceMockRecorder) SetAccountLoginData(arg0, arg1 interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAccountLoginData", reflect.TypeOf((*MockLoginInterface)(nil).SetAccountLoginData), arg0, arg1)
 }
 
+// SetAccountMetadata mocks base method.
+func (m *MockLoginInterface) SetAccountMetadata(arg0 context.Context, arg1 *dto.UserIdAccountMetadata) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAccountMetadata", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAccountMetadata indicates an expected call of SetAccountMetadata.
+func (mr *MockLoginInterfaceMockRecorder) SetAccountMetadata(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAccountMetadata", reflect.TypeOf((*MockLoginInterface)(nil).SetAccountMetadata), arg0, arg1)
+}
+
+// SetAccountPasswordHash mocks base method.
+func (m *MockLoginInterface) SetAccountPasswordHash(arg0 context.Context, arg1 *dto.UserIdHash) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAccountPasswordHash", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAccountPasswordHash indicates an expected call of SetAccountPasswordHash.
+func (mr *MockLoginInterfaceMockRecorder) SetAccountPasswordHash(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAccountPasswordHash", reflect.TypeOf((*MockLoginInterface)(nil).SetAccountPasswordHash), arg0, arg1)
+}
+
 // SetAccountState mocks base method.
 func (m *MockLoginInterface) SetAccountState(arg0 context.Context, arg1 *dto.LoginState) error {
 	m.ctrl.T.Helper()
@@ -190,6 +276,20 @@ func (mr *MockLoginInterfaceMockRecorder) SetAccountState(arg0, arg1 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAccountState", reflect.TypeOf((*MockLoginInterface)(nil).SetAccountState), arg0, arg1)
 }
 
+// UpdateAccountLastLogin mocks base method.
+func (m *MockLoginInterface) UpdateAccountLastLogin(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAccountLastLogin", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAccountLastLogin indicates an expected call of UpdateAccountLastLogin.
+func (mr *MockLoginInterfaceMockRecorder) UpdateAccountLastLogin(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccountLastLogin", reflect.TypeOf((*MockLoginInterface)(nil).UpdateAccountLastLogin), arg0, arg1)
+}
+
 // UserIdAndPasswordHash mocks base method.
 func (m *MockLoginInterface) UserIdAndPasswordHash(arg0 context.Context, arg1 login.Login) (dto.UserIdHash, error) {
 	m.ctrl.T.Helper()
@@ -205,6 +305,21 @@ func (mr *MockLoginInterfaceMockRecorder) UserIdAndPasswordHash(arg0, arg1 inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserIdAndPasswordHash", reflect.TypeOf((*MockLoginInterface)(nil).UserIdAndPasswordHash), arg0, arg1)
 }
 
+// UserIdFromResetToken mocks base method.
+func (m *MockLoginInterface) UserIdFromResetToken(arg0 context.Context, arg1 string) (uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserIdFromResetToken", arg0, arg1)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UserIdFromResetToken indicates an expected call of UserIdFromResetToken.
+func (mr *MockLoginInterfaceMockRecorder) UserIdFromResetToken(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserIdFromResetToken", reflect.TypeOf((*MockLoginInterface)(nil).UserIdFromResetToken), arg0, arg1)
+}
+
 // UserUUIDFromSession mocks base method.
 func (m *MockLoginInterface) UserUUIDFromSession(ctx context.Context, sessionToken string) (uuid.UUID, error) {
 	m.ctrl.T.Helper()
@@ -220,6 +335,144 @@ func (mr *MockLoginInterfaceMockRecorder) UserUUIDFromSession(ctx, sessionToken
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserUUIDFromSession", reflect.TypeOf((*MockLoginInterface)(nil).UserUUIDFromSession), ctx, sessionToken)
 }
 
+// MockTOTPInterface is a mock of TOTPInterface interface.
+type MockTOTPInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockTOTPInterfaceMockRecorder
+}
+
+// MockTOTPInterfaceMockRecorder is the mock recorder for MockTOTPInterface.
+type MockTOTPInterfaceMockRecorder struct {
+	mock *MockTOTPInterface
+}
+
+// NewMockTOTPInterface creates a new mock instance.
+func NewMockTOTPInterface(ctrl *gomock.Controller) *MockTOTPInterface {
+	mock := &MockTOTPInterface{ctrl: ctrl}
+	mock.recorder = &MockTOTPInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTOTPInterface) EXPECT() *MockTOTPInterfaceMockRecorder {
+	return m.recorder
+}
+
+// DeleteTOTP mocks base method.
+func (m *MockTOTPInterface) DeleteTOTP(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTOTP", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTOTP indicates an expected call of DeleteTOTP.
+func (mr *MockTOTPInterfaceMockRecorder) DeleteTOTP(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTOTP", reflect.TypeOf((*MockTOTPInterface)(nil).DeleteTOTP), arg0, arg1)
+}
+
+// EnableTOTP mocks base method.
+func (m *MockTOTPInterface) EnableTOTP(arg0 context.Context, arg1 *dto.UserIdCodes) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableTOTP", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnableTOTP indicates an expected call of EnableTOTP.
+func (mr *MockTOTPInterfaceMockRecorder) EnableTOTP(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableTOTP", reflect.TypeOf((*MockTOTPInterface)(nil).EnableTOTP), arg0, arg1)
+}
+
+// MarkTOTPStepUsed mocks base method.
+func (m *MockTOTPInterface) MarkTOTPStepUsed(arg0 context.Context, arg1 uuid.UUID, arg2 uint64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkTOTPStepUsed", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkTOTPStepUsed indicates an expected call of MarkTOTPStepUsed.
+func (mr *MockTOTPInterfaceMockRecorder) MarkTOTPStepUsed(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkTOTPStepUsed", reflect.TypeOf((*MockTOTPInterface)(nil).MarkTOTPStepUsed), arg0, arg1, arg2)
+}
+
+// SaveLoginChallenge mocks base method.
+func (m *MockTOTPInterface) SaveLoginChallenge(arg0 context.Context, arg1 *dto.UserIdToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveLoginChallenge", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveLoginChallenge indicates an expected call of SaveLoginChallenge.
+func (mr *MockTOTPInterfaceMockRecorder) SaveLoginChallenge(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveLoginChallenge", reflect.TypeOf((*MockTOTPInterface)(nil).SaveLoginChallenge), arg0, arg1)
+}
+
+// SetTOTPSecret mocks base method.
+func (m *MockTOTPInterface) SetTOTPSecret(arg0 context.Context, arg1 *dto.UserIdSecret) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTOTPSecret", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetTOTPSecret indicates an expected call of SetTOTPSecret.
+func (mr *MockTOTPInterfaceMockRecorder) SetTOTPSecret(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTOTPSecret", reflect.TypeOf((*MockTOTPInterface)(nil).SetTOTPSecret), arg0, arg1)
+}
+
+// TOTPSecret mocks base method.
+func (m *MockTOTPInterface) TOTPSecret(arg0 context.Context, arg1 uuid.UUID) (dto.SecretEnabled, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TOTPSecret", arg0, arg1)
+	ret0, _ := ret[0].(dto.SecretEnabled)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TOTPSecret indicates an expected call of TOTPSecret.
+func (mr *MockTOTPInterfaceMockRecorder) TOTPSecret(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TOTPSecret", reflect.TypeOf((*MockTOTPInterface)(nil).TOTPSecret), arg0, arg1)
+}
+
+// UseRecoveryCode mocks base method.
+func (m *MockTOTPInterface) UseRecoveryCode(arg0 context.Context, arg1 *dto.UserIdCode) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseRecoveryCode", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UseRecoveryCode indicates an expected call of UseRecoveryCode.
+func (mr *MockTOTPInterfaceMockRecorder) UseRecoveryCode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseRecoveryCode", reflect.TypeOf((*MockTOTPInterface)(nil).UseRecoveryCode), arg0, arg1)
+}
+
+// UserIdFromLoginChallenge mocks base method.
+func (m *MockTOTPInterface) UserIdFromLoginChallenge(arg0 context.Context, arg1 string) (uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserIdFromLoginChallenge", arg0, arg1)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UserIdFromLoginChallenge indicates an expected call of UserIdFromLoginChallenge.
+func (mr *MockTOTPInterfaceMockRecorder) UserIdFromLoginChallenge(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserIdFromLoginChallenge", reflect.TypeOf((*MockTOTPInterface)(nil).UserIdFromLoginChallenge), arg0, arg1)
+}
+
 // MockRBACInterface is a mock of RBACInterface interface.
 type MockRBACInterface struct {
 	ctrl     *gomock.Controller
@@ -243,6 +496,50 @@ func (m *MockRBACInterface) EXPECT() *MockRBACInterfaceMockRecorder {
 	return m.recorder
 }
 
+// AccountGrantsExpiration mocks base method.
+func (m *MockRBACInterface) AccountGrantsExpiration(arg0 context.Context, arg1 *dto.UserIdInstance) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccountGrantsExpiration", arg0, arg1)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AccountGrantsExpiration indicates an expected call of AccountGrantsExpiration.
+func (mr *MockRBACInterfaceMockRecorder) AccountGrantsExpiration(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccountGrantsExpiration", reflect.TypeOf((*MockRBACInterface)(nil).AccountGrantsExpiration), arg0, arg1)
+}
+
+// AccountHasRole mocks base method.
+func (m *MockRBACInterface) AccountHasRole(arg0 context.Context, arg1 *dto.UserIdRoleService) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccountHasRole", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AccountHasRole indicates an expected call of AccountHasRole.
+func (mr *MockRBACInterfaceMockRecorder) AccountHasRole(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccountHasRole", reflect.TypeOf((*MockRBACInterface)(nil).AccountHasRole), arg0, arg1)
+}
+
+// AssignGlobalGroupToAccount mocks base method.
+func (m *MockRBACInterface) AssignGlobalGroupToAccount(arg0 context.Context, arg1 *dto.UserIdGlobalGroup) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignGlobalGroupToAccount", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignGlobalGroupToAccount indicates an expected call of AssignGlobalGroupToAccount.
+func (mr *MockRBACInterfaceMockRecorder) AssignGlobalGroupToAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignGlobalGroupToAccount", reflect.TypeOf((*MockRBACInterface)(nil).AssignGlobalGroupToAccount), arg0, arg1)
+}
+
 // AssignGroupToAccount mocks base method.
 func (m *MockRBACInterface) AssignGroupToAccount(arg0 context.Context, arg1 *dto.UserIdGroupService) error {
 	m.ctrl.T.Helper()
@@ -257,6 +554,20 @@ func (mr *MockRBACInterfaceMockRecorder) AssignGroupToAccount(arg0, arg1 interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignGroupToAccount", reflect.TypeOf((*MockRBACInterface)(nil).AssignGroupToAccount), arg0, arg1)
 }
 
+// AssignInstanceGroupToAccount mocks base method.
+func (m *MockRBACInterface) AssignInstanceGroupToAccount(arg0 context.Context, arg1 *dto.UserIdInstanceGroup) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignInstanceGroupToAccount", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignInstanceGroupToAccount indicates an expected call of AssignInstanceGroupToAccount.
+func (mr *MockRBACInterfaceMockRecorder) AssignInstanceGroupToAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignInstanceGroupToAccount", reflect.TypeOf((*MockRBACInterface)(nil).AssignInstanceGroupToAccount), arg0, arg1)
+}
+
 // AssignInstancePermissionToAccount mocks base method.
 func (m *MockRBACInterface) AssignInstancePermissionToAccount(arg0 context.Context, arg1 *dto.UserIdInstancePermission) error {
 	m.ctrl.T.Helper()
@@ -271,6 +582,34 @@ func (mr *MockRBACInterfaceMockRecorder) AssignInstancePermissionToAccount(arg0,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignInstancePermissionToAccount", reflect.TypeOf((*MockRBACInterface)(nil).AssignInstancePermissionToAccount), arg0, arg1)
 }
 
+// AssignInstanceRoleToAccount mocks base method.
+func (m *MockRBACInterface) AssignInstanceRoleToAccount(arg0 context.Context, arg1 *dto.UserIdInstanceRole) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignInstanceRoleToAccount", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignInstanceRoleToAccount indicates an expected call of AssignInstanceRoleToAccount.
+func (mr *MockRBACInterfaceMockRecorder) AssignInstanceRoleToAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignInstanceRoleToAccount", reflect.TypeOf((*MockRBACInterface)(nil).AssignInstanceRoleToAccount), arg0, arg1)
+}
+
+// AssignParentToRole mocks base method.
+func (m *MockRBACInterface) AssignParentToRole(arg0 context.Context, arg1 *dto.RoleParentService) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignParentToRole", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignParentToRole indicates an expected call of AssignParentToRole.
+func (mr *MockRBACInterfaceMockRecorder) AssignParentToRole(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignParentToRole", reflect.TypeOf((*MockRBACInterface)(nil).AssignParentToRole), arg0, arg1)
+}
+
 // AssignPermissionToGroup mocks base method.
 func (m *MockRBACInterface) AssignPermissionToGroup(arg0 context.Context, arg1 *dto.GroupPermissionService) error {
 	m.ctrl.T.Helper()
@@ -313,6 +652,20 @@ func (mr *MockRBACInterfaceMockRecorder) AssignRoleToAccount(arg0, arg1 interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignRoleToAccount", reflect.TypeOf((*MockRBACInterface)(nil).AssignRoleToAccount), arg0, arg1)
 }
 
+// AssignRoleToGlobalGroup mocks base method.
+func (m *MockRBACInterface) AssignRoleToGlobalGroup(arg0 context.Context, arg1 *dto.GlobalGroupRoleService) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignRoleToGlobalGroup", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignRoleToGlobalGroup indicates an expected call of AssignRoleToGlobalGroup.
+func (mr *MockRBACInterfaceMockRecorder) AssignRoleToGlobalGroup(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignRoleToGlobalGroup", reflect.TypeOf((*MockRBACInterface)(nil).AssignRoleToGlobalGroup), arg0, arg1)
+}
+
 // AssignRoleToGroup mocks base method.
 func (m *MockRBACInterface) AssignRoleToGroup(arg0 context.Context, arg1 *dto.GroupRoleService) error {
 	m.ctrl.T.Helper()
@@ -327,6 +680,63 @@ func (mr *MockRBACInterfaceMockRecorder) AssignRoleToGroup(arg0, arg1 interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignRoleToGroup", reflect.TypeOf((*MockRBACInterface)(nil).AssignRoleToGroup), arg0, arg1)
 }
 
+// AssignSelectorPermissionToAccount mocks base method.
+func (m *MockRBACInterface) AssignSelectorPermissionToAccount(arg0 context.Context, arg1 *dto.UserIdSelectorPermission) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignSelectorPermissionToAccount", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignSelectorPermissionToAccount indicates an expected call of AssignSelectorPermissionToAccount.
+func (mr *MockRBACInterfaceMockRecorder) AssignSelectorPermissionToAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignSelectorPermissionToAccount", reflect.TypeOf((*MockRBACInterface)(nil).AssignSelectorPermissionToAccount), arg0, arg1)
+}
+
+// AssignSubgroupToGroup mocks base method.
+func (m *MockRBACInterface) AssignSubgroupToGroup(arg0 context.Context, arg1 *dto.GroupSubgroupService) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignSubgroupToGroup", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignSubgroupToGroup indicates an expected call of AssignSubgroupToGroup.
+func (mr *MockRBACInterfaceMockRecorder) AssignSubgroupToGroup(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignSubgroupToGroup", reflect.TypeOf((*MockRBACInterface)(nil).AssignSubgroupToGroup), arg0, arg1)
+}
+
+// ConditionalPermissionsForAccount mocks base method.
+func (m *MockRBACInterface) ConditionalPermissionsForAccount(arg0 context.Context, arg1 *dto.UserIdInstance) ([]dto.NumberCondition, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConditionalPermissionsForAccount", arg0, arg1)
+	ret0, _ := ret[0].([]dto.NumberCondition)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConditionalPermissionsForAccount indicates an expected call of ConditionalPermissionsForAccount.
+func (mr *MockRBACInterfaceMockRecorder) ConditionalPermissionsForAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConditionalPermissionsForAccount", reflect.TypeOf((*MockRBACInterface)(nil).ConditionalPermissionsForAccount), arg0, arg1)
+}
+
+// CreateGlobalGroup mocks base method.
+func (m *MockRBACInterface) CreateGlobalGroup(arg0 context.Context, arg1 *dto.NameDescription) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateGlobalGroup", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateGlobalGroup indicates an expected call of CreateGlobalGroup.
+func (mr *MockRBACInterfaceMockRecorder) CreateGlobalGroup(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGlobalGroup", reflect.TypeOf((*MockRBACInterface)(nil).CreateGlobalGroup), arg0, arg1)
+}
+
 // CreateGroup mocks base method.
 func (m *MockRBACInterface) CreateGroup(arg0 context.Context, arg1 *dto.NameServiceDescription) error {
 	m.ctrl.T.Helper()
@@ -342,7 +752,7 @@ func (mr *MockRBACInterfaceMockRecorder) CreateGroup(arg0, arg1 interface{}) *go
 }
 
 // CreatePermission mocks base method.
-func (m *MockRBACInterface) CreatePermission(arg0 context.Context, arg1 *dto.NameServiceDescription) error {
+func (m *MockRBACInterface) CreatePermission(arg0 context.Context, arg1 *dto.NameNumberDescriptionService) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreatePermission", arg0, arg1)
 	ret0, _ := ret[0].(error)
@@ -369,54 +779,181 @@ func (mr *MockRBACInterfaceMockRecorder) CreateRole(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRole", reflect.TypeOf((*MockRBACInterface)(nil).CreateRole), arg0, arg1)
 }
 
-// DeleteGroup mocks base method.
-func (m *MockRBACInterface) DeleteGroup(arg0 context.Context, arg1 *dto.NameService) error {
+// DeleteAccount mocks base method.
+func (m *MockRBACInterface) DeleteAccount(arg0 context.Context, arg1 uuid.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteGroup", arg0, arg1)
+	ret := m.ctrl.Call(m, "DeleteAccount", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// DeleteGroup indicates an expected call of DeleteGroup.
-func (mr *MockRBACInterfaceMockRecorder) DeleteGroup(arg0, arg1 interface{}) *gomock.Call {
+// DeleteAccount indicates an expected call of DeleteAccount.
+func (mr *MockRBACInterfaceMockRecorder) DeleteAccount(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteGroup", reflect.TypeOf((*MockRBACInterface)(nil).DeleteGroup), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccount", reflect.TypeOf((*MockRBACInterface)(nil).DeleteAccount), arg0, arg1)
 }
 
-// DeletePermission mocks base method.
-func (m *MockRBACInterface) DeletePermission(arg0 context.Context, arg1 *dto.NameService) error {
+// DeleteExpiredGrants mocks base method.
+func (m *MockRBACInterface) DeleteExpiredGrants(arg0 context.Context, arg1 time.Time) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeletePermission", arg0, arg1)
+	ret := m.ctrl.Call(m, "DeleteExpiredGrants", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// DeletePermission indicates an expected call of DeletePermission.
-func (mr *MockRBACInterfaceMockRecorder) DeletePermission(arg0, arg1 interface{}) *gomock.Call {
+// DeleteExpiredGrants indicates an expected call of DeleteExpiredGrants.
+func (mr *MockRBACInterfaceMockRecorder) DeleteExpiredGrants(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePermission", reflect.TypeOf((*MockRBACInterface)(nil).DeletePermission), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredGrants", reflect.TypeOf((*MockRBACInterface)(nil).DeleteExpiredGrants), arg0, arg1)
 }
 
-// DeleteRole mocks base method.
-func (m *MockRBACInterface) DeleteRole(arg0 context.Context, arg1 *dto.NameService) error {
+// DeleteGlobalGroup mocks base method.
+func (m *MockRBACInterface) DeleteGlobalGroup(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteRole", arg0, arg1)
+	ret := m.ctrl.Call(m, "DeleteGlobalGroup", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// DeleteRole indicates an expected call of DeleteRole.
-func (mr *MockRBACInterfaceMockRecorder) DeleteRole(arg0, arg1 interface{}) *gomock.Call {
+// DeleteGlobalGroup indicates an expected call of DeleteGlobalGroup.
+func (mr *MockRBACInterfaceMockRecorder) DeleteGlobalGroup(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRole", reflect.TypeOf((*MockRBACInterface)(nil).DeleteRole), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteGlobalGroup", reflect.TypeOf((*MockRBACInterface)(nil).DeleteGlobalGroup), arg0, arg1)
 }
 
-// InstancePermissionsNumbersForAccount mocks base method.
-func (m *MockRBACInterface) InstancePermissionsNumbersForAccount(arg0 context.Context, arg1 *dto.UserIdInstance) ([]int, error) {
+// DeleteGroup mocks base method.
+func (m *MockRBACInterface) DeleteGroup(arg0 context.Context, arg1 *dto.NameService) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "InstancePermissionsNumbersForAccount", arg0, arg1)
-	ret0, _ := ret[0].([]int)
-	ret1, _ := ret[1].(error)
+	ret := m.ctrl.Call(m, "DeleteGroup", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteGroup indicates an expected call of DeleteGroup.
+func (mr *MockRBACInterfaceMockRecorder) DeleteGroup(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteGroup", reflect.TypeOf((*MockRBACInterface)(nil).DeleteGroup), arg0, arg1)
+}
+
+// DeleteInstance mocks base method.
+func (m *MockRBACInterface) DeleteInstance(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteInstance", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteInstance indicates an expected call of DeleteInstance.
+func (mr *MockRBACInterfaceMockRecorder) DeleteInstance(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteInstance", reflect.TypeOf((*MockRBACInterface)(nil).DeleteInstance), arg0, arg1)
+}
+
+// DeleteInstanceAttribute mocks base method.
+func (m *MockRBACInterface) DeleteInstanceAttribute(arg0 context.Context, arg1 *dto.NameInstance) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteInstanceAttribute", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteInstanceAttribute indicates an expected call of DeleteInstanceAttribute.
+func (mr *MockRBACInterfaceMockRecorder) DeleteInstanceAttribute(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteInstanceAttribute", reflect.TypeOf((*MockRBACInterface)(nil).DeleteInstanceAttribute), arg0, arg1)
+}
+
+// DeletePermission mocks base method.
+func (m *MockRBACInterface) DeletePermission(arg0 context.Context, arg1 *dto.NameService) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePermission", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePermission indicates an expected call of DeletePermission.
+func (mr *MockRBACInterfaceMockRecorder) DeletePermission(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePermission", reflect.TypeOf((*MockRBACInterface)(nil).DeletePermission), arg0, arg1)
+}
+
+// DeleteRole mocks base method.
+func (m *MockRBACInterface) DeleteRole(arg0 context.Context, arg1 *dto.NameService) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRole", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRole indicates an expected call of DeleteRole.
+func (mr *MockRBACInterfaceMockRecorder) DeleteRole(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRole", reflect.TypeOf((*MockRBACInterface)(nil).DeleteRole), arg0, arg1)
+}
+
+// DeleteService mocks base method.
+func (m *MockRBACInterface) DeleteService(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteService", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteService indicates an expected call of DeleteService.
+func (mr *MockRBACInterfaceMockRecorder) DeleteService(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteService", reflect.TypeOf((*MockRBACInterface)(nil).DeleteService), arg0, arg1)
+}
+
+// DeprecatePermission mocks base method.
+func (m *MockRBACInterface) DeprecatePermission(arg0 context.Context, arg1 *dto.NameService) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeprecatePermission", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeprecatePermission indicates an expected call of DeprecatePermission.
+func (mr *MockRBACInterfaceMockRecorder) DeprecatePermission(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeprecatePermission", reflect.TypeOf((*MockRBACInterface)(nil).DeprecatePermission), arg0, arg1)
+}
+
+// ImportRBAC mocks base method.
+func (m *MockRBACInterface) ImportRBAC(arg0 context.Context, arg1 *dto.RBACDocument, arg2 map[string]string, arg3 *dto.RBACRemovals) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportRBAC", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ImportRBAC indicates an expected call of ImportRBAC.
+func (mr *MockRBACInterfaceMockRecorder) ImportRBAC(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportRBAC", reflect.TypeOf((*MockRBACInterface)(nil).ImportRBAC), arg0, arg1, arg2, arg3)
+}
+
+// InstanceAttributes mocks base method.
+func (m *MockRBACInterface) InstanceAttributes(arg0 context.Context, arg1 string) (map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InstanceAttributes", arg0, arg1)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InstanceAttributes indicates an expected call of InstanceAttributes.
+func (mr *MockRBACInterfaceMockRecorder) InstanceAttributes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstanceAttributes", reflect.TypeOf((*MockRBACInterface)(nil).InstanceAttributes), arg0, arg1)
+}
+
+// InstancePermissionsNumbersForAccount mocks base method.
+func (m *MockRBACInterface) InstancePermissionsNumbersForAccount(arg0 context.Context, arg1 *dto.UserIdInstance) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InstancePermissionsNumbersForAccount", arg0, arg1)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
@@ -426,6 +963,119 @@ func (mr *MockRBACInterfaceMockRecorder) InstancePermissionsNumbersForAccount(ar
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstancePermissionsNumbersForAccount", reflect.TypeOf((*MockRBACInterface)(nil).InstancePermissionsNumbersForAccount), arg0, arg1)
 }
 
+// PermissionsGrantPathsForAccount mocks base method.
+func (m *MockRBACInterface) PermissionsGrantPathsForAccount(arg0 context.Context, arg1 *dto.UserIdServiceInstance) ([]dto.NumberNameGrantPath, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PermissionsGrantPathsForAccount", arg0, arg1)
+	ret0, _ := ret[0].([]dto.NumberNameGrantPath)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PermissionsGrantPathsForAccount indicates an expected call of PermissionsGrantPathsForAccount.
+func (mr *MockRBACInterfaceMockRecorder) PermissionsGrantPathsForAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PermissionsGrantPathsForAccount", reflect.TypeOf((*MockRBACInterface)(nil).PermissionsGrantPathsForAccount), arg0, arg1)
+}
+
+// PurgeDeleted mocks base method.
+func (m *MockRBACInterface) PurgeDeleted(arg0 context.Context, arg1 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeleted", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgeDeleted indicates an expected call of PurgeDeleted.
+func (mr *MockRBACInterfaceMockRecorder) PurgeDeleted(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeleted", reflect.TypeOf((*MockRBACInterface)(nil).PurgeDeleted), arg0, arg1)
+}
+
+// RestoreAccount mocks base method.
+func (m *MockRBACInterface) RestoreAccount(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreAccount", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreAccount indicates an expected call of RestoreAccount.
+func (mr *MockRBACInterfaceMockRecorder) RestoreAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreAccount", reflect.TypeOf((*MockRBACInterface)(nil).RestoreAccount), arg0, arg1)
+}
+
+// RestoreGroup mocks base method.
+func (m *MockRBACInterface) RestoreGroup(arg0 context.Context, arg1 *dto.NameService) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreGroup", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreGroup indicates an expected call of RestoreGroup.
+func (mr *MockRBACInterfaceMockRecorder) RestoreGroup(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreGroup", reflect.TypeOf((*MockRBACInterface)(nil).RestoreGroup), arg0, arg1)
+}
+
+// RestoreInstance mocks base method.
+func (m *MockRBACInterface) RestoreInstance(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreInstance", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreInstance indicates an expected call of RestoreInstance.
+func (mr *MockRBACInterfaceMockRecorder) RestoreInstance(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreInstance", reflect.TypeOf((*MockRBACInterface)(nil).RestoreInstance), arg0, arg1)
+}
+
+// RestorePermission mocks base method.
+func (m *MockRBACInterface) RestorePermission(arg0 context.Context, arg1 *dto.NameService) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestorePermission", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestorePermission indicates an expected call of RestorePermission.
+func (mr *MockRBACInterfaceMockRecorder) RestorePermission(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestorePermission", reflect.TypeOf((*MockRBACInterface)(nil).RestorePermission), arg0, arg1)
+}
+
+// RestoreRole mocks base method.
+func (m *MockRBACInterface) RestoreRole(arg0 context.Context, arg1 *dto.NameService) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreRole", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreRole indicates an expected call of RestoreRole.
+func (mr *MockRBACInterfaceMockRecorder) RestoreRole(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreRole", reflect.TypeOf((*MockRBACInterface)(nil).RestoreRole), arg0, arg1)
+}
+
+// RestoreService mocks base method.
+func (m *MockRBACInterface) RestoreService(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreService", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreService indicates an expected call of RestoreService.
+func (mr *MockRBACInterfaceMockRecorder) RestoreService(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreService", reflect.TypeOf((*MockRBACInterface)(nil).RestoreService), arg0, arg1)
+}
+
 // ServicePermissionsForAccount mocks base method.
 func (m *MockRBACInterface) ServicePermissionsForAccount(arg0 context.Context, arg1 *dto.UserIdService) ([]dto.NameNumberDescription, error) {
 	m.ctrl.T.Helper()
@@ -456,6 +1106,116 @@ func (mr *MockRBACInterfaceMockRecorder) ServicePermissionsNumbersForAccount(arg
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ServicePermissionsNumbersForAccount", reflect.TypeOf((*MockRBACInterface)(nil).ServicePermissionsNumbersForAccount), arg0, arg1)
 }
 
+// ServicePermissionsSourcesForAccount mocks base method.
+func (m *MockRBACInterface) ServicePermissionsSourcesForAccount(arg0 context.Context, arg1 *dto.UserIdService) ([]dto.NumberSource, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ServicePermissionsSourcesForAccount", arg0, arg1)
+	ret0, _ := ret[0].([]dto.NumberSource)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ServicePermissionsSourcesForAccount indicates an expected call of ServicePermissionsSourcesForAccount.
+func (mr *MockRBACInterfaceMockRecorder) ServicePermissionsSourcesForAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ServicePermissionsSourcesForAccount", reflect.TypeOf((*MockRBACInterface)(nil).ServicePermissionsSourcesForAccount), arg0, arg1)
+}
+
+// SetInstanceAttribute mocks base method.
+func (m *MockRBACInterface) SetInstanceAttribute(arg0 context.Context, arg1 *dto.InstanceNameValue) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetInstanceAttribute", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetInstanceAttribute indicates an expected call of SetInstanceAttribute.
+func (mr *MockRBACInterfaceMockRecorder) SetInstanceAttribute(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetInstanceAttribute", reflect.TypeOf((*MockRBACInterface)(nil).SetInstanceAttribute), arg0, arg1)
+}
+
+// MockOIDCInterface is a mock of OIDCInterface interface.
+type MockOIDCInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockOIDCInterfaceMockRecorder
+}
+
+// MockOIDCInterfaceMockRecorder is the mock recorder for MockOIDCInterface.
+type MockOIDCInterfaceMockRecorder struct {
+	mock *MockOIDCInterface
+}
+
+// NewMockOIDCInterface creates a new mock instance.
+func NewMockOIDCInterface(ctrl *gomock.Controller) *MockOIDCInterface {
+	mock := &MockOIDCInterface{ctrl: ctrl}
+	mock.recorder = &MockOIDCInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOIDCInterface) EXPECT() *MockOIDCInterfaceMockRecorder {
+	return m.recorder
+}
+
+// AuthorizationCode mocks base method.
+func (m *MockOIDCInterface) AuthorizationCode(arg0 context.Context, arg1 string) (dto.AuthorizationCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthorizationCode", arg0, arg1)
+	ret0, _ := ret[0].(dto.AuthorizationCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuthorizationCode indicates an expected call of AuthorizationCode.
+func (mr *MockOIDCInterfaceMockRecorder) AuthorizationCode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthorizationCode", reflect.TypeOf((*MockOIDCInterface)(nil).AuthorizationCode), arg0, arg1)
+}
+
+// CreateOIDCClient mocks base method.
+func (m *MockOIDCInterface) CreateOIDCClient(arg0 context.Context, arg1 *dto.OIDCClient) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOIDCClient", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateOIDCClient indicates an expected call of CreateOIDCClient.
+func (mr *MockOIDCInterfaceMockRecorder) CreateOIDCClient(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOIDCClient", reflect.TypeOf((*MockOIDCInterface)(nil).CreateOIDCClient), arg0, arg1)
+}
+
+// OIDCClient mocks base method.
+func (m *MockOIDCInterface) OIDCClient(arg0 context.Context, arg1 string) (dto.OIDCClient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OIDCClient", arg0, arg1)
+	ret0, _ := ret[0].(dto.OIDCClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OIDCClient indicates an expected call of OIDCClient.
+func (mr *MockOIDCInterfaceMockRecorder) OIDCClient(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OIDCClient", reflect.TypeOf((*MockOIDCInterface)(nil).OIDCClient), arg0, arg1)
+}
+
+// SaveAuthorizationCode mocks base method.
+func (m *MockOIDCInterface) SaveAuthorizationCode(arg0 context.Context, arg1 *dto.AuthorizationCode) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveAuthorizationCode", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveAuthorizationCode indicates an expected call of SaveAuthorizationCode.
+func (mr *MockOIDCInterfaceMockRecorder) SaveAuthorizationCode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveAuthorizationCode", reflect.TypeOf((*MockOIDCInterface)(nil).SaveAuthorizationCode), arg0, arg1)
+}
+
 // MockInterface is a mock of Interface interface.
 type MockInterface struct {
 	ctrl     *gomock.Controller
@@ -479,6 +1239,51 @@ func (m *MockInterface) EXPECT() *MockInterfaceMockRecorder {
 	return m.recorder
 }
 
+// AccountDetails mocks base method.
+func (m *MockInterface) AccountDetails(arg0 context.Context, arg1 uuid.UUID) (dto.AccountDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccountDetails", arg0, arg1)
+	ret0, _ := ret[0].(dto.AccountDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AccountDetails indicates an expected call of AccountDetails.
+func (mr *MockInterfaceMockRecorder) AccountDetails(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccountDetails", reflect.TypeOf((*MockInterface)(nil).AccountDetails), arg0, arg1)
+}
+
+// AccountGrantsExpiration mocks base method.
+func (m *MockInterface) AccountGrantsExpiration(arg0 context.Context, arg1 *dto.UserIdInstance) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccountGrantsExpiration", arg0, arg1)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AccountGrantsExpiration indicates an expected call of AccountGrantsExpiration.
+func (mr *MockInterfaceMockRecorder) AccountGrantsExpiration(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccountGrantsExpiration", reflect.TypeOf((*MockInterface)(nil).AccountGrantsExpiration), arg0, arg1)
+}
+
+// AccountHasRole mocks base method.
+func (m *MockInterface) AccountHasRole(arg0 context.Context, arg1 *dto.UserIdRoleService) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccountHasRole", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AccountHasRole indicates an expected call of AccountHasRole.
+func (mr *MockInterfaceMockRecorder) AccountHasRole(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccountHasRole", reflect.TypeOf((*MockInterface)(nil).AccountHasRole), arg0, arg1)
+}
+
 // AccountLoginData mocks base method.
 func (m *MockInterface) AccountLoginData(arg0 context.Context, arg1 login.Login) (dto.UserIdLoginHashState, error) {
 	m.ctrl.T.Helper()
@@ -494,259 +1299,918 @@ func (mr *MockInterfaceMockRecorder) AccountLoginData(arg0, arg1 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccountLoginData", reflect.TypeOf((*MockInterface)(nil).AccountLoginData), arg0, arg1)
 }
 
+// AccountLoginDataByUserId mocks base method.
+func (m *MockInterface) AccountLoginDataByUserId(arg0 context.Context, arg1 uuid.UUID) (dto.UserIdLoginHashState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccountLoginDataByUserId", arg0, arg1)
+	ret0, _ := ret[0].(dto.UserIdLoginHashState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AccountLoginDataByUserId indicates an expected call of AccountLoginDataByUserId.
+func (mr *MockInterfaceMockRecorder) AccountLoginDataByUserId(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccountLoginDataByUserId", reflect.TypeOf((*MockInterface)(nil).AccountLoginDataByUserId), arg0, arg1)
+}
+
 // AccountState mocks base method.
 func (m *MockInterface) AccountState(arg0 context.Context, arg1 login.Login) (account_state.State, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AccountState", arg0, arg1)
-	ret0, _ := ret[0].(account_state.State)
+	ret := m.ctrl.Call(m, "AccountState", arg0, arg1)
+	ret0, _ := ret[0].(account_state.State)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AccountState indicates an expected call of AccountState.
+func (mr *MockInterfaceMockRecorder) AccountState(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccountState", reflect.TypeOf((*MockInterface)(nil).AccountState), arg0, arg1)
+}
+
+// Accounts mocks base method.
+func (m *MockInterface) Accounts(arg0 context.Context, arg1 *dto.ListQuery) ([]dto.UserIdLoginState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Accounts", arg0, arg1)
+	ret0, _ := ret[0].([]dto.UserIdLoginState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Accounts indicates an expected call of Accounts.
+func (mr *MockInterfaceMockRecorder) Accounts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Accounts", reflect.TypeOf((*MockInterface)(nil).Accounts), arg0, arg1)
+}
+
+// AssignGlobalGroupToAccount mocks base method.
+func (m *MockInterface) AssignGlobalGroupToAccount(arg0 context.Context, arg1 *dto.UserIdGlobalGroup) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignGlobalGroupToAccount", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignGlobalGroupToAccount indicates an expected call of AssignGlobalGroupToAccount.
+func (mr *MockInterfaceMockRecorder) AssignGlobalGroupToAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignGlobalGroupToAccount", reflect.TypeOf((*MockInterface)(nil).AssignGlobalGroupToAccount), arg0, arg1)
+}
+
+// AssignGroupToAccount mocks base method.
+func (m *MockInterface) AssignGroupToAccount(arg0 context.Context, arg1 *dto.UserIdGroupService) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignGroupToAccount", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignGroupToAccount indicates an expected call of AssignGroupToAccount.
+func (mr *MockInterfaceMockRecorder) AssignGroupToAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignGroupToAccount", reflect.TypeOf((*MockInterface)(nil).AssignGroupToAccount), arg0, arg1)
+}
+
+// AssignInstanceGroupToAccount mocks base method.
+func (m *MockInterface) AssignInstanceGroupToAccount(arg0 context.Context, arg1 *dto.UserIdInstanceGroup) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignInstanceGroupToAccount", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignInstanceGroupToAccount indicates an expected call of AssignInstanceGroupToAccount.
+func (mr *MockInterfaceMockRecorder) AssignInstanceGroupToAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignInstanceGroupToAccount", reflect.TypeOf((*MockInterface)(nil).AssignInstanceGroupToAccount), arg0, arg1)
+}
+
+// AssignInstancePermissionToAccount mocks base method.
+func (m *MockInterface) AssignInstancePermissionToAccount(arg0 context.Context, arg1 *dto.UserIdInstancePermission) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignInstancePermissionToAccount", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignInstancePermissionToAccount indicates an expected call of AssignInstancePermissionToAccount.
+func (mr *MockInterfaceMockRecorder) AssignInstancePermissionToAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignInstancePermissionToAccount", reflect.TypeOf((*MockInterface)(nil).AssignInstancePermissionToAccount), arg0, arg1)
+}
+
+// AssignInstanceRoleToAccount mocks base method.
+func (m *MockInterface) AssignInstanceRoleToAccount(arg0 context.Context, arg1 *dto.UserIdInstanceRole) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignInstanceRoleToAccount", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignInstanceRoleToAccount indicates an expected call of AssignInstanceRoleToAccount.
+func (mr *MockInterfaceMockRecorder) AssignInstanceRoleToAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignInstanceRoleToAccount", reflect.TypeOf((*MockInterface)(nil).AssignInstanceRoleToAccount), arg0, arg1)
+}
+
+// AssignParentToRole mocks base method.
+func (m *MockInterface) AssignParentToRole(arg0 context.Context, arg1 *dto.RoleParentService) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignParentToRole", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignParentToRole indicates an expected call of AssignParentToRole.
+func (mr *MockInterfaceMockRecorder) AssignParentToRole(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignParentToRole", reflect.TypeOf((*MockInterface)(nil).AssignParentToRole), arg0, arg1)
+}
+
+// AssignPermissionToGroup mocks base method.
+func (m *MockInterface) AssignPermissionToGroup(arg0 context.Context, arg1 *dto.GroupPermissionService) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignPermissionToGroup", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignPermissionToGroup indicates an expected call of AssignPermissionToGroup.
+func (mr *MockInterfaceMockRecorder) AssignPermissionToGroup(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignPermissionToGroup", reflect.TypeOf((*MockInterface)(nil).AssignPermissionToGroup), arg0, arg1)
+}
+
+// AssignPermissionToRole mocks base method.
+func (m *MockInterface) AssignPermissionToRole(arg0 context.Context, arg1 *dto.PermissionRoleService) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignPermissionToRole", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignPermissionToRole indicates an expected call of AssignPermissionToRole.
+func (mr *MockInterfaceMockRecorder) AssignPermissionToRole(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignPermissionToRole", reflect.TypeOf((*MockInterface)(nil).AssignPermissionToRole), arg0, arg1)
+}
+
+// AssignRoleToAccount mocks base method.
+func (m *MockInterface) AssignRoleToAccount(arg0 context.Context, arg1 *dto.UserIdRoleService) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignRoleToAccount", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignRoleToAccount indicates an expected call of AssignRoleToAccount.
+func (mr *MockInterfaceMockRecorder) AssignRoleToAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignRoleToAccount", reflect.TypeOf((*MockInterface)(nil).AssignRoleToAccount), arg0, arg1)
+}
+
+// AssignRoleToGlobalGroup mocks base method.
+func (m *MockInterface) AssignRoleToGlobalGroup(arg0 context.Context, arg1 *dto.GlobalGroupRoleService) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignRoleToGlobalGroup", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignRoleToGlobalGroup indicates an expected call of AssignRoleToGlobalGroup.
+func (mr *MockInterfaceMockRecorder) AssignRoleToGlobalGroup(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignRoleToGlobalGroup", reflect.TypeOf((*MockInterface)(nil).AssignRoleToGlobalGroup), arg0, arg1)
+}
+
+// AssignRoleToGroup mocks base method.
+func (m *MockInterface) AssignRoleToGroup(arg0 context.Context, arg1 *dto.GroupRoleService) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignRoleToGroup", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignRoleToGroup indicates an expected call of AssignRoleToGroup.
+func (mr *MockInterfaceMockRecorder) AssignRoleToGroup(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignRoleToGroup", reflect.TypeOf((*MockInterface)(nil).AssignRoleToGroup), arg0, arg1)
+}
+
+// AssignSelectorPermissionToAccount mocks base method.
+func (m *MockInterface) AssignSelectorPermissionToAccount(arg0 context.Context, arg1 *dto.UserIdSelectorPermission) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignSelectorPermissionToAccount", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignSelectorPermissionToAccount indicates an expected call of AssignSelectorPermissionToAccount.
+func (mr *MockInterfaceMockRecorder) AssignSelectorPermissionToAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignSelectorPermissionToAccount", reflect.TypeOf((*MockInterface)(nil).AssignSelectorPermissionToAccount), arg0, arg1)
+}
+
+// AssignSubgroupToGroup mocks base method.
+func (m *MockInterface) AssignSubgroupToGroup(arg0 context.Context, arg1 *dto.GroupSubgroupService) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignSubgroupToGroup", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignSubgroupToGroup indicates an expected call of AssignSubgroupToGroup.
+func (mr *MockInterfaceMockRecorder) AssignSubgroupToGroup(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignSubgroupToGroup", reflect.TypeOf((*MockInterface)(nil).AssignSubgroupToGroup), arg0, arg1)
+}
+
+// AuthorizationCode mocks base method.
+func (m *MockInterface) AuthorizationCode(arg0 context.Context, arg1 string) (dto.AuthorizationCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthorizationCode", arg0, arg1)
+	ret0, _ := ret[0].(dto.AuthorizationCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuthorizationCode indicates an expected call of AuthorizationCode.
+func (mr *MockInterfaceMockRecorder) AuthorizationCode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthorizationCode", reflect.TypeOf((*MockInterface)(nil).AuthorizationCode), arg0, arg1)
+}
+
+// ConditionalPermissionsForAccount mocks base method.
+func (m *MockInterface) ConditionalPermissionsForAccount(arg0 context.Context, arg1 *dto.UserIdInstance) ([]dto.NumberCondition, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConditionalPermissionsForAccount", arg0, arg1)
+	ret0, _ := ret[0].([]dto.NumberCondition)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConditionalPermissionsForAccount indicates an expected call of ConditionalPermissionsForAccount.
+func (mr *MockInterfaceMockRecorder) ConditionalPermissionsForAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConditionalPermissionsForAccount", reflect.TypeOf((*MockInterface)(nil).ConditionalPermissionsForAccount), arg0, arg1)
+}
+
+// CreateAccount mocks base method.
+func (m *MockInterface) CreateAccount(arg0 context.Context, arg1 *dto.UserIdLoginHashState, arg2 *dto.UserIdAccountMetadata) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAccount", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAccount indicates an expected call of CreateAccount.
+func (mr *MockInterfaceMockRecorder) CreateAccount(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccount", reflect.TypeOf((*MockInterface)(nil).CreateAccount), arg0, arg1, arg2)
+}
+
+// CreateGlobalGroup mocks base method.
+func (m *MockInterface) CreateGlobalGroup(arg0 context.Context, arg1 *dto.NameDescription) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateGlobalGroup", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateGlobalGroup indicates an expected call of CreateGlobalGroup.
+func (mr *MockInterfaceMockRecorder) CreateGlobalGroup(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGlobalGroup", reflect.TypeOf((*MockInterface)(nil).CreateGlobalGroup), arg0, arg1)
+}
+
+// CreateGroup mocks base method.
+func (m *MockInterface) CreateGroup(arg0 context.Context, arg1 *dto.NameServiceDescription) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateGroup", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateGroup indicates an expected call of CreateGroup.
+func (mr *MockInterfaceMockRecorder) CreateGroup(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGroup", reflect.TypeOf((*MockInterface)(nil).CreateGroup), arg0, arg1)
+}
+
+// CreateOIDCClient mocks base method.
+func (m *MockInterface) CreateOIDCClient(arg0 context.Context, arg1 *dto.OIDCClient) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOIDCClient", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateOIDCClient indicates an expected call of CreateOIDCClient.
+func (mr *MockInterfaceMockRecorder) CreateOIDCClient(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOIDCClient", reflect.TypeOf((*MockInterface)(nil).CreateOIDCClient), arg0, arg1)
+}
+
+// CreateOrUpdateInstance mocks base method.
+func (m *MockInterface) CreateOrUpdateInstance(arg0 context.Context, arg1 *dto.NameServiceSecret) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOrUpdateInstance", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateOrUpdateInstance indicates an expected call of CreateOrUpdateInstance.
+func (mr *MockInterfaceMockRecorder) CreateOrUpdateInstance(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrUpdateInstance", reflect.TypeOf((*MockInterface)(nil).CreateOrUpdateInstance), arg0, arg1)
+}
+
+// CreatePermission mocks base method.
+func (m *MockInterface) CreatePermission(arg0 context.Context, arg1 *dto.NameNumberDescriptionService) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePermission", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePermission indicates an expected call of CreatePermission.
+func (mr *MockInterfaceMockRecorder) CreatePermission(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePermission", reflect.TypeOf((*MockInterface)(nil).CreatePermission), arg0, arg1)
+}
+
+// CreateRole mocks base method.
+func (m *MockInterface) CreateRole(arg0 context.Context, arg1 *dto.NameServiceDescription) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRole", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateRole indicates an expected call of CreateRole.
+func (mr *MockInterfaceMockRecorder) CreateRole(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRole", reflect.TypeOf((*MockInterface)(nil).CreateRole), arg0, arg1)
+}
+
+// CreateService mocks base method.
+func (m *MockInterface) CreateService(arg0 context.Context, arg1 *dto.NameDescription) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateService", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateService indicates an expected call of CreateService.
+func (mr *MockInterfaceMockRecorder) CreateService(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateService", reflect.TypeOf((*MockInterface)(nil).CreateService), arg0, arg1)
+}
+
+// DeleteAccount mocks base method.
+func (m *MockInterface) DeleteAccount(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAccount", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAccount indicates an expected call of DeleteAccount.
+func (mr *MockInterfaceMockRecorder) DeleteAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccount", reflect.TypeOf((*MockInterface)(nil).DeleteAccount), arg0, arg1)
+}
+
+// DeleteExpiredGrants mocks base method.
+func (m *MockInterface) DeleteExpiredGrants(arg0 context.Context, arg1 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredGrants", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExpiredGrants indicates an expected call of DeleteExpiredGrants.
+func (mr *MockInterfaceMockRecorder) DeleteExpiredGrants(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredGrants", reflect.TypeOf((*MockInterface)(nil).DeleteExpiredGrants), arg0, arg1)
+}
+
+// DeleteGlobalGroup mocks base method.
+func (m *MockInterface) DeleteGlobalGroup(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteGlobalGroup", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteGlobalGroup indicates an expected call of DeleteGlobalGroup.
+func (mr *MockInterfaceMockRecorder) DeleteGlobalGroup(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteGlobalGroup", reflect.TypeOf((*MockInterface)(nil).DeleteGlobalGroup), arg0, arg1)
+}
+
+// DeleteGroup mocks base method.
+func (m *MockInterface) DeleteGroup(arg0 context.Context, arg1 *dto.NameService) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteGroup", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteGroup indicates an expected call of DeleteGroup.
+func (mr *MockInterfaceMockRecorder) DeleteGroup(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteGroup", reflect.TypeOf((*MockInterface)(nil).DeleteGroup), arg0, arg1)
+}
+
+// DeleteInstance mocks base method.
+func (m *MockInterface) DeleteInstance(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteInstance", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteInstance indicates an expected call of DeleteInstance.
+func (mr *MockInterfaceMockRecorder) DeleteInstance(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteInstance", reflect.TypeOf((*MockInterface)(nil).DeleteInstance), arg0, arg1)
+}
+
+// DeleteInstanceAttribute mocks base method.
+func (m *MockInterface) DeleteInstanceAttribute(arg0 context.Context, arg1 *dto.NameInstance) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteInstanceAttribute", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteInstanceAttribute indicates an expected call of DeleteInstanceAttribute.
+func (mr *MockInterfaceMockRecorder) DeleteInstanceAttribute(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteInstanceAttribute", reflect.TypeOf((*MockInterface)(nil).DeleteInstanceAttribute), arg0, arg1)
+}
+
+// DeletePermission mocks base method.
+func (m *MockInterface) DeletePermission(arg0 context.Context, arg1 *dto.NameService) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePermission", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePermission indicates an expected call of DeletePermission.
+func (mr *MockInterfaceMockRecorder) DeletePermission(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePermission", reflect.TypeOf((*MockInterface)(nil).DeletePermission), arg0, arg1)
+}
+
+// DeleteRole mocks base method.
+func (m *MockInterface) DeleteRole(arg0 context.Context, arg1 *dto.NameService) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRole", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRole indicates an expected call of DeleteRole.
+func (mr *MockInterfaceMockRecorder) DeleteRole(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRole", reflect.TypeOf((*MockInterface)(nil).DeleteRole), arg0, arg1)
+}
+
+// DeleteService mocks base method.
+func (m *MockInterface) DeleteService(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteService", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteService indicates an expected call of DeleteService.
+func (mr *MockInterfaceMockRecorder) DeleteService(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteService", reflect.TypeOf((*MockInterface)(nil).DeleteService), arg0, arg1)
+}
+
+// DeleteSession mocks base method.
+func (m *MockInterface) DeleteSession(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSession", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSession indicates an expected call of DeleteSession.
+func (mr *MockInterfaceMockRecorder) DeleteSession(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSession", reflect.TypeOf((*MockInterface)(nil).DeleteSession), arg0, arg1)
+}
+
+// DeleteTOTP mocks base method.
+func (m *MockInterface) DeleteTOTP(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTOTP", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTOTP indicates an expected call of DeleteTOTP.
+func (mr *MockInterfaceMockRecorder) DeleteTOTP(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTOTP", reflect.TypeOf((*MockInterface)(nil).DeleteTOTP), arg0, arg1)
+}
+
+// DeprecatePermission mocks base method.
+func (m *MockInterface) DeprecatePermission(arg0 context.Context, arg1 *dto.NameService) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeprecatePermission", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeprecatePermission indicates an expected call of DeprecatePermission.
+func (mr *MockInterfaceMockRecorder) DeprecatePermission(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeprecatePermission", reflect.TypeOf((*MockInterface)(nil).DeprecatePermission), arg0, arg1)
+}
+
+// EnableTOTP mocks base method.
+func (m *MockInterface) EnableTOTP(arg0 context.Context, arg1 *dto.UserIdCodes) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableTOTP", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnableTOTP indicates an expected call of EnableTOTP.
+func (mr *MockInterfaceMockRecorder) EnableTOTP(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableTOTP", reflect.TypeOf((*MockInterface)(nil).EnableTOTP), arg0, arg1)
+}
+
+// GlobalGroups mocks base method.
+func (m *MockInterface) GlobalGroups(arg0 context.Context) ([]dto.RBACGlobalGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GlobalGroups", arg0)
+	ret0, _ := ret[0].([]dto.RBACGlobalGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GlobalGroups indicates an expected call of GlobalGroups.
+func (mr *MockInterfaceMockRecorder) GlobalGroups(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GlobalGroups", reflect.TypeOf((*MockInterface)(nil).GlobalGroups), arg0)
+}
+
+// GroupDetails mocks base method.
+func (m *MockInterface) GroupDetails(arg0 context.Context, arg1 *dto.NameService) (dto.GroupDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GroupDetails", arg0, arg1)
+	ret0, _ := ret[0].(dto.GroupDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GroupDetails indicates an expected call of GroupDetails.
+func (mr *MockInterfaceMockRecorder) GroupDetails(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GroupDetails", reflect.TypeOf((*MockInterface)(nil).GroupDetails), arg0, arg1)
+}
+
+// Groups mocks base method.
+func (m *MockInterface) Groups(arg0 context.Context, arg1 *dto.ListQuery) ([]dto.NameDescription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Groups", arg0, arg1)
+	ret0, _ := ret[0].([]dto.NameDescription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Groups indicates an expected call of Groups.
+func (mr *MockInterfaceMockRecorder) Groups(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Groups", reflect.TypeOf((*MockInterface)(nil).Groups), arg0, arg1)
+}
+
+// ImportRBAC mocks base method.
+func (m *MockInterface) ImportRBAC(arg0 context.Context, arg1 *dto.RBACDocument, arg2 map[string]string, arg3 *dto.RBACRemovals) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportRBAC", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ImportRBAC indicates an expected call of ImportRBAC.
+func (mr *MockInterfaceMockRecorder) ImportRBAC(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportRBAC", reflect.TypeOf((*MockInterface)(nil).ImportRBAC), arg0, arg1, arg2, arg3)
+}
+
+// InstanceAttributes mocks base method.
+func (m *MockInterface) InstanceAttributes(arg0 context.Context, arg1 string) (map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InstanceAttributes", arg0, arg1)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InstanceAttributes indicates an expected call of InstanceAttributes.
+func (mr *MockInterfaceMockRecorder) InstanceAttributes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstanceAttributes", reflect.TypeOf((*MockInterface)(nil).InstanceAttributes), arg0, arg1)
+}
+
+// InstancePermissionsNumbersForAccount mocks base method.
+func (m *MockInterface) InstancePermissionsNumbersForAccount(arg0 context.Context, arg1 *dto.UserIdInstance) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InstancePermissionsNumbersForAccount", arg0, arg1)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InstancePermissionsNumbersForAccount indicates an expected call of InstancePermissionsNumbersForAccount.
+func (mr *MockInterfaceMockRecorder) InstancePermissionsNumbersForAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstancePermissionsNumbersForAccount", reflect.TypeOf((*MockInterface)(nil).InstancePermissionsNumbersForAccount), arg0, arg1)
+}
+
+// InstanceSecret mocks base method.
+func (m *MockInterface) InstanceSecret(arg0 context.Context, arg1 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InstanceSecret", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InstanceSecret indicates an expected call of InstanceSecret.
+func (mr *MockInterfaceMockRecorder) InstanceSecret(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstanceSecret", reflect.TypeOf((*MockInterface)(nil).InstanceSecret), arg0, arg1)
+}
+
+// Instances mocks base method.
+func (m *MockInterface) Instances(arg0 context.Context, arg1 *dto.ListQuery) ([]dto.NameService, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Instances", arg0, arg1)
+	ret0, _ := ret[0].([]dto.NameService)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// AccountState indicates an expected call of AccountState.
-func (mr *MockInterfaceMockRecorder) AccountState(arg0, arg1 interface{}) *gomock.Call {
+// Instances indicates an expected call of Instances.
+func (mr *MockInterfaceMockRecorder) Instances(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccountState", reflect.TypeOf((*MockInterface)(nil).AccountState), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Instances", reflect.TypeOf((*MockInterface)(nil).Instances), arg0, arg1)
 }
 
-// AssignGroupToAccount mocks base method.
-func (m *MockInterface) AssignGroupToAccount(arg0 context.Context, arg1 *dto.UserIdGroupService) error {
+// IsTokenRevoked mocks base method.
+func (m *MockInterface) IsTokenRevoked(arg0 context.Context, arg1 string) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AssignGroupToAccount", arg0, arg1)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "IsTokenRevoked", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// AssignGroupToAccount indicates an expected call of AssignGroupToAccount.
-func (mr *MockInterfaceMockRecorder) AssignGroupToAccount(arg0, arg1 interface{}) *gomock.Call {
+// IsTokenRevoked indicates an expected call of IsTokenRevoked.
+func (mr *MockInterfaceMockRecorder) IsTokenRevoked(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignGroupToAccount", reflect.TypeOf((*MockInterface)(nil).AssignGroupToAccount), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsTokenRevoked", reflect.TypeOf((*MockInterface)(nil).IsTokenRevoked), arg0, arg1)
 }
 
-// AssignInstancePermissionToAccount mocks base method.
-func (m *MockInterface) AssignInstancePermissionToAccount(arg0 context.Context, arg1 *dto.UserIdInstancePermission) error {
+// MarkTOTPStepUsed mocks base method.
+func (m *MockInterface) MarkTOTPStepUsed(arg0 context.Context, arg1 uuid.UUID, arg2 uint64) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AssignInstancePermissionToAccount", arg0, arg1)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "MarkTOTPStepUsed", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// AssignInstancePermissionToAccount indicates an expected call of AssignInstancePermissionToAccount.
-func (mr *MockInterfaceMockRecorder) AssignInstancePermissionToAccount(arg0, arg1 interface{}) *gomock.Call {
+// MarkTOTPStepUsed indicates an expected call of MarkTOTPStepUsed.
+func (mr *MockInterfaceMockRecorder) MarkTOTPStepUsed(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignInstancePermissionToAccount", reflect.TypeOf((*MockInterface)(nil).AssignInstancePermissionToAccount), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkTOTPStepUsed", reflect.TypeOf((*MockInterface)(nil).MarkTOTPStepUsed), arg0, arg1, arg2)
 }
 
-// AssignPermissionToGroup mocks base method.
-func (m *MockInterface) AssignPermissionToGroup(arg0 context.Context, arg1 *dto.GroupPermissionService) error {
+// OIDCClient mocks base method.
+func (m *MockInterface) OIDCClient(arg0 context.Context, arg1 string) (dto.OIDCClient, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AssignPermissionToGroup", arg0, arg1)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "OIDCClient", arg0, arg1)
+	ret0, _ := ret[0].(dto.OIDCClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// AssignPermissionToGroup indicates an expected call of AssignPermissionToGroup.
-func (mr *MockInterfaceMockRecorder) AssignPermissionToGroup(arg0, arg1 interface{}) *gomock.Call {
+// OIDCClient indicates an expected call of OIDCClient.
+func (mr *MockInterfaceMockRecorder) OIDCClient(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignPermissionToGroup", reflect.TypeOf((*MockInterface)(nil).AssignPermissionToGroup), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OIDCClient", reflect.TypeOf((*MockInterface)(nil).OIDCClient), arg0, arg1)
 }
 
-// AssignPermissionToRole mocks base method.
-func (m *MockInterface) AssignPermissionToRole(arg0 context.Context, arg1 *dto.PermissionRoleService) error {
+// PermissionsGrantPathsForAccount mocks base method.
+func (m *MockInterface) PermissionsGrantPathsForAccount(arg0 context.Context, arg1 *dto.UserIdServiceInstance) ([]dto.NumberNameGrantPath, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AssignPermissionToRole", arg0, arg1)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "PermissionsGrantPathsForAccount", arg0, arg1)
+	ret0, _ := ret[0].([]dto.NumberNameGrantPath)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// AssignPermissionToRole indicates an expected call of AssignPermissionToRole.
-func (mr *MockInterfaceMockRecorder) AssignPermissionToRole(arg0, arg1 interface{}) *gomock.Call {
+// PermissionsGrantPathsForAccount indicates an expected call of PermissionsGrantPathsForAccount.
+func (mr *MockInterfaceMockRecorder) PermissionsGrantPathsForAccount(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignPermissionToRole", reflect.TypeOf((*MockInterface)(nil).AssignPermissionToRole), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PermissionsGrantPathsForAccount", reflect.TypeOf((*MockInterface)(nil).PermissionsGrantPathsForAccount), arg0, arg1)
 }
 
-// AssignRoleToAccount mocks base method.
-func (m *MockInterface) AssignRoleToAccount(arg0 context.Context, arg1 *dto.UserIdRoleService) error {
+// PurgeDeleted mocks base method.
+func (m *MockInterface) PurgeDeleted(arg0 context.Context, arg1 time.Time) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AssignRoleToAccount", arg0, arg1)
+	ret := m.ctrl.Call(m, "PurgeDeleted", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// AssignRoleToAccount indicates an expected call of AssignRoleToAccount.
-func (mr *MockInterfaceMockRecorder) AssignRoleToAccount(arg0, arg1 interface{}) *gomock.Call {
+// PurgeDeleted indicates an expected call of PurgeDeleted.
+func (mr *MockInterfaceMockRecorder) PurgeDeleted(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignRoleToAccount", reflect.TypeOf((*MockInterface)(nil).AssignRoleToAccount), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeleted", reflect.TypeOf((*MockInterface)(nil).PurgeDeleted), arg0, arg1)
 }
 
-// AssignRoleToGroup mocks base method.
-func (m *MockInterface) AssignRoleToGroup(arg0 context.Context, arg1 *dto.GroupRoleService) error {
+// RestoreAccount mocks base method.
+func (m *MockInterface) RestoreAccount(arg0 context.Context, arg1 uuid.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AssignRoleToGroup", arg0, arg1)
+	ret := m.ctrl.Call(m, "RestoreAccount", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// AssignRoleToGroup indicates an expected call of AssignRoleToGroup.
-func (mr *MockInterfaceMockRecorder) AssignRoleToGroup(arg0, arg1 interface{}) *gomock.Call {
+// RestoreAccount indicates an expected call of RestoreAccount.
+func (mr *MockInterfaceMockRecorder) RestoreAccount(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignRoleToGroup", reflect.TypeOf((*MockInterface)(nil).AssignRoleToGroup), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreAccount", reflect.TypeOf((*MockInterface)(nil).RestoreAccount), arg0, arg1)
 }
 
-// CreateGroup mocks base method.
-func (m *MockInterface) CreateGroup(arg0 context.Context, arg1 *dto.NameServiceDescription) error {
+// RestoreGroup mocks base method.
+func (m *MockInterface) RestoreGroup(arg0 context.Context, arg1 *dto.NameService) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateGroup", arg0, arg1)
+	ret := m.ctrl.Call(m, "RestoreGroup", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// CreateGroup indicates an expected call of CreateGroup.
-func (mr *MockInterfaceMockRecorder) CreateGroup(arg0, arg1 interface{}) *gomock.Call {
+// RestoreGroup indicates an expected call of RestoreGroup.
+func (mr *MockInterfaceMockRecorder) RestoreGroup(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGroup", reflect.TypeOf((*MockInterface)(nil).CreateGroup), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreGroup", reflect.TypeOf((*MockInterface)(nil).RestoreGroup), arg0, arg1)
 }
 
-// CreateOrUpdateInstance mocks base method.
-func (m *MockInterface) CreateOrUpdateInstance(arg0 context.Context, arg1 *dto.NameServiceSecret) error {
+// RestoreInstance mocks base method.
+func (m *MockInterface) RestoreInstance(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateOrUpdateInstance", arg0, arg1)
+	ret := m.ctrl.Call(m, "RestoreInstance", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// CreateOrUpdateInstance indicates an expected call of CreateOrUpdateInstance.
-func (mr *MockInterfaceMockRecorder) CreateOrUpdateInstance(arg0, arg1 interface{}) *gomock.Call {
+// RestoreInstance indicates an expected call of RestoreInstance.
+func (mr *MockInterfaceMockRecorder) RestoreInstance(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrUpdateInstance", reflect.TypeOf((*MockInterface)(nil).CreateOrUpdateInstance), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreInstance", reflect.TypeOf((*MockInterface)(nil).RestoreInstance), arg0, arg1)
 }
 
-// CreatePermission mocks base method.
-func (m *MockInterface) CreatePermission(arg0 context.Context, arg1 *dto.NameServiceDescription) error {
+// RestorePermission mocks base method.
+func (m *MockInterface) RestorePermission(arg0 context.Context, arg1 *dto.NameService) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreatePermission", arg0, arg1)
+	ret := m.ctrl.Call(m, "RestorePermission", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// CreatePermission indicates an expected call of CreatePermission.
-func (mr *MockInterfaceMockRecorder) CreatePermission(arg0, arg1 interface{}) *gomock.Call {
+// RestorePermission indicates an expected call of RestorePermission.
+func (mr *MockInterfaceMockRecorder) RestorePermission(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePermission", reflect.TypeOf((*MockInterface)(nil).CreatePermission), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestorePermission", reflect.TypeOf((*MockInterface)(nil).RestorePermission), arg0, arg1)
 }
 
-// CreateRole mocks base method.
-func (m *MockInterface) CreateRole(arg0 context.Context, arg1 *dto.NameServiceDescription) error {
+// RestoreRole mocks base method.
+func (m *MockInterface) RestoreRole(arg0 context.Context, arg1 *dto.NameService) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateRole", arg0, arg1)
+	ret := m.ctrl.Call(m, "RestoreRole", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// CreateRole indicates an expected call of CreateRole.
-func (mr *MockInterfaceMockRecorder) CreateRole(arg0, arg1 interface{}) *gomock.Call {
+// RestoreRole indicates an expected call of RestoreRole.
+func (mr *MockInterfaceMockRecorder) RestoreRole(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRole", reflect.TypeOf((*MockInterface)(nil).CreateRole), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreRole", reflect.TypeOf((*MockInterface)(nil).RestoreRole), arg0, arg1)
 }
 
-// CreateService mocks base method.
-func (m *MockInterface) CreateService(arg0 context.Context, arg1 *dto.NameDescription) error {
+// RestoreService mocks base method.
+func (m *MockInterface) RestoreService(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateService", arg0, arg1)
+	ret := m.ctrl.Call(m, "RestoreService", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// CreateService indicates an expected call of CreateService.
-func (mr *MockInterfaceMockRecorder) CreateService(arg0, arg1 interface{}) *gomock.Call {
+// RestoreService indicates an expected call of RestoreService.
+func (mr *MockInterfaceMockRecorder) RestoreService(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateService", reflect.TypeOf((*MockInterface)(nil).CreateService), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreService", reflect.TypeOf((*MockInterface)(nil).RestoreService), arg0, arg1)
 }
 
-// DeleteGroup mocks base method.
-func (m *MockInterface) DeleteGroup(arg0 context.Context, arg1 *dto.NameService) error {
+// RevokeToken mocks base method.
+func (m *MockInterface) RevokeToken(arg0 context.Context, arg1 *dto.TokenTTL) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteGroup", arg0, arg1)
+	ret := m.ctrl.Call(m, "RevokeToken", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// DeleteGroup indicates an expected call of DeleteGroup.
-func (mr *MockInterfaceMockRecorder) DeleteGroup(arg0, arg1 interface{}) *gomock.Call {
+// RevokeToken indicates an expected call of RevokeToken.
+func (mr *MockInterfaceMockRecorder) RevokeToken(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteGroup", reflect.TypeOf((*MockInterface)(nil).DeleteGroup), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeToken", reflect.TypeOf((*MockInterface)(nil).RevokeToken), arg0, arg1)
 }
 
-// DeletePermission mocks base method.
-func (m *MockInterface) DeletePermission(arg0 context.Context, arg1 *dto.NameService) error {
+// RoleDetails mocks base method.
+func (m *MockInterface) RoleDetails(arg0 context.Context, arg1 *dto.NameService) (dto.RoleDetails, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeletePermission", arg0, arg1)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "RoleDetails", arg0, arg1)
+	ret0, _ := ret[0].(dto.RoleDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// DeletePermission indicates an expected call of DeletePermission.
-func (mr *MockInterfaceMockRecorder) DeletePermission(arg0, arg1 interface{}) *gomock.Call {
+// RoleDetails indicates an expected call of RoleDetails.
+func (mr *MockInterfaceMockRecorder) RoleDetails(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePermission", reflect.TypeOf((*MockInterface)(nil).DeletePermission), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RoleDetails", reflect.TypeOf((*MockInterface)(nil).RoleDetails), arg0, arg1)
 }
 
-// DeleteRole mocks base method.
-func (m *MockInterface) DeleteRole(arg0 context.Context, arg1 *dto.NameService) error {
+// Roles mocks base method.
+func (m *MockInterface) Roles(arg0 context.Context, arg1 *dto.ListQuery) ([]dto.NameDescription, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteRole", arg0, arg1)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "Roles", arg0, arg1)
+	ret0, _ := ret[0].([]dto.NameDescription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// DeleteRole indicates an expected call of DeleteRole.
-func (mr *MockInterfaceMockRecorder) DeleteRole(arg0, arg1 interface{}) *gomock.Call {
+// Roles indicates an expected call of Roles.
+func (mr *MockInterfaceMockRecorder) Roles(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRole", reflect.TypeOf((*MockInterface)(nil).DeleteRole), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Roles", reflect.TypeOf((*MockInterface)(nil).Roles), arg0, arg1)
 }
 
-// DeleteSession mocks base method.
-func (m *MockInterface) DeleteSession(arg0 context.Context, arg1 uuid.UUID) error {
+// SaveAuthorizationCode mocks base method.
+func (m *MockInterface) SaveAuthorizationCode(arg0 context.Context, arg1 *dto.AuthorizationCode) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteSession", arg0, arg1)
+	ret := m.ctrl.Call(m, "SaveAuthorizationCode", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// DeleteSession indicates an expected call of DeleteSession.
-func (mr *MockInterfaceMockRecorder) DeleteSession(arg0, arg1 interface{}) *gomock.Call {
+// SaveAuthorizationCode indicates an expected call of SaveAuthorizationCode.
+func (mr *MockInterfaceMockRecorder) SaveAuthorizationCode(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSession", reflect.TypeOf((*MockInterface)(nil).DeleteSession), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveAuthorizationCode", reflect.TypeOf((*MockInterface)(nil).SaveAuthorizationCode), arg0, arg1)
 }
 
-// InstancePermissionsNumbersForAccount mocks base method.
-func (m *MockInterface) InstancePermissionsNumbersForAccount(arg0 context.Context, arg1 *dto.UserIdInstance) ([]int, error) {
+// SaveLoginChallenge mocks base method.
+func (m *MockInterface) SaveLoginChallenge(arg0 context.Context, arg1 *dto.UserIdToken) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "InstancePermissionsNumbersForAccount", arg0, arg1)
-	ret0, _ := ret[0].([]int)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "SaveLoginChallenge", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// InstancePermissionsNumbersForAccount indicates an expected call of InstancePermissionsNumbersForAccount.
-func (mr *MockInterfaceMockRecorder) InstancePermissionsNumbersForAccount(arg0, arg1 interface{}) *gomock.Call {
+// SaveLoginChallenge indicates an expected call of SaveLoginChallenge.
+func (mr *MockInterfaceMockRecorder) SaveLoginChallenge(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstancePermissionsNumbersForAccount", reflect.TypeOf((*MockInterface)(nil).InstancePermissionsNumbersForAccount), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveLoginChallenge", reflect.TypeOf((*MockInterface)(nil).SaveLoginChallenge), arg0, arg1)
 }
 
-// InstanceSecret mocks base method.
-func (m *MockInterface) InstanceSecret(arg0 context.Context, arg1 string) (string, error) {
+// SaveResetToken mocks base method.
+func (m *MockInterface) SaveResetToken(arg0 context.Context, arg1 *dto.UserIdToken) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "InstanceSecret", arg0, arg1)
-	ret0, _ := ret[0].(string)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "SaveResetToken", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// InstanceSecret indicates an expected call of InstanceSecret.
-func (mr *MockInterfaceMockRecorder) InstanceSecret(arg0, arg1 interface{}) *gomock.Call {
+// SaveResetToken indicates an expected call of SaveResetToken.
+func (mr *MockInterfaceMockRecorder) SaveResetToken(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstanceSecret", reflect.TypeOf((*MockInterface)(nil).InstanceSecret), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveResetToken", reflect.TypeOf((*MockInterface)(nil).SaveResetToken), arg0, arg1)
 }
 
 // SaveSession mocks base method.
@@ -763,6 +2227,21 @@ func (mr *MockInterfaceMockRecorder) SaveSession(arg0, arg1 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveSession", reflect.TypeOf((*MockInterface)(nil).SaveSession), arg0, arg1)
 }
 
+// ServiceDetails mocks base method.
+func (m *MockInterface) ServiceDetails(arg0 context.Context, arg1 string) (dto.ServiceDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ServiceDetails", arg0, arg1)
+	ret0, _ := ret[0].(dto.ServiceDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ServiceDetails indicates an expected call of ServiceDetails.
+func (mr *MockInterfaceMockRecorder) ServiceDetails(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ServiceDetails", reflect.TypeOf((*MockInterface)(nil).ServiceDetails), arg0, arg1)
+}
+
 // ServiceName mocks base method.
 func (m *MockInterface) ServiceName(arg0 context.Context, arg1 string) (string, error) {
 	m.ctrl.T.Helper()
@@ -793,6 +2272,21 @@ func (mr *MockInterfaceMockRecorder) ServiceNumberedPermissions(arg0, arg1 inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ServiceNumberedPermissions", reflect.TypeOf((*MockInterface)(nil).ServiceNumberedPermissions), arg0, arg1)
 }
 
+// ServicePermissionEncoding mocks base method.
+func (m *MockInterface) ServicePermissionEncoding(arg0 context.Context, arg1 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ServicePermissionEncoding", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ServicePermissionEncoding indicates an expected call of ServicePermissionEncoding.
+func (mr *MockInterfaceMockRecorder) ServicePermissionEncoding(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ServicePermissionEncoding", reflect.TypeOf((*MockInterface)(nil).ServicePermissionEncoding), arg0, arg1)
+}
+
 // ServicePermissionsForAccount mocks base method.
 func (m *MockInterface) ServicePermissionsForAccount(arg0 context.Context, arg1 *dto.UserIdService) ([]dto.NameNumberDescription, error) {
 	m.ctrl.T.Helper()
@@ -823,6 +2317,36 @@ func (mr *MockInterfaceMockRecorder) ServicePermissionsNumbersForAccount(arg0, a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ServicePermissionsNumbersForAccount", reflect.TypeOf((*MockInterface)(nil).ServicePermissionsNumbersForAccount), arg0, arg1)
 }
 
+// ServicePermissionsSourcesForAccount mocks base method.
+func (m *MockInterface) ServicePermissionsSourcesForAccount(arg0 context.Context, arg1 *dto.UserIdService) ([]dto.NumberSource, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ServicePermissionsSourcesForAccount", arg0, arg1)
+	ret0, _ := ret[0].([]dto.NumberSource)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ServicePermissionsSourcesForAccount indicates an expected call of ServicePermissionsSourcesForAccount.
+func (mr *MockInterfaceMockRecorder) ServicePermissionsSourcesForAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ServicePermissionsSourcesForAccount", reflect.TypeOf((*MockInterface)(nil).ServicePermissionsSourcesForAccount), arg0, arg1)
+}
+
+// Services mocks base method.
+func (m *MockInterface) Services(arg0 context.Context, arg1 *dto.ListQuery) ([]dto.NameDescription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Services", arg0, arg1)
+	ret0, _ := ret[0].([]dto.NameDescription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Services indicates an expected call of Services.
+func (mr *MockInterfaceMockRecorder) Services(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Services", reflect.TypeOf((*MockInterface)(nil).Services), arg0, arg1)
+}
+
 // ServicesNames mocks base method.
 func (m *MockInterface) ServicesNames(arg0 context.Context) ([]string, error) {
 	m.ctrl.T.Helper()
@@ -867,6 +2391,34 @@ func (mr *MockInterfaceMockRecorder) SetAccountLoginData(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAccountLoginData", reflect.TypeOf((*MockInterface)(nil).SetAccountLoginData), arg0, arg1)
 }
 
+// SetAccountMetadata mocks base method.
+func (m *MockInterface) SetAccountMetadata(arg0 context.Context, arg1 *dto.UserIdAccountMetadata) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAccountMetadata", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAccountMetadata indicates an expected call of SetAccountMetadata.
+func (mr *MockInterfaceMockRecorder) SetAccountMetadata(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAccountMetadata", reflect.TypeOf((*MockInterface)(nil).SetAccountMetadata), arg0, arg1)
+}
+
+// SetAccountPasswordHash mocks base method.
+func (m *MockInterface) SetAccountPasswordHash(arg0 context.Context, arg1 *dto.UserIdHash) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAccountPasswordHash", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAccountPasswordHash indicates an expected call of SetAccountPasswordHash.
+func (mr *MockInterfaceMockRecorder) SetAccountPasswordHash(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAccountPasswordHash", reflect.TypeOf((*MockInterface)(nil).SetAccountPasswordHash), arg0, arg1)
+}
+
 // SetAccountState mocks base method.
 func (m *MockInterface) SetAccountState(arg0 context.Context, arg1 *dto.LoginState) error {
 	m.ctrl.T.Helper()
@@ -881,6 +2433,91 @@ func (mr *MockInterfaceMockRecorder) SetAccountState(arg0, arg1 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAccountState", reflect.TypeOf((*MockInterface)(nil).SetAccountState), arg0, arg1)
 }
 
+// SetInstanceAttribute mocks base method.
+func (m *MockInterface) SetInstanceAttribute(arg0 context.Context, arg1 *dto.InstanceNameValue) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetInstanceAttribute", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetInstanceAttribute indicates an expected call of SetInstanceAttribute.
+func (mr *MockInterfaceMockRecorder) SetInstanceAttribute(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetInstanceAttribute", reflect.TypeOf((*MockInterface)(nil).SetInstanceAttribute), arg0, arg1)
+}
+
+// SetServicePermissionEncoding mocks base method.
+func (m *MockInterface) SetServicePermissionEncoding(arg0 context.Context, arg1 *dto.NameEncoding) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetServicePermissionEncoding", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetServicePermissionEncoding indicates an expected call of SetServicePermissionEncoding.
+func (mr *MockInterfaceMockRecorder) SetServicePermissionEncoding(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetServicePermissionEncoding", reflect.TypeOf((*MockInterface)(nil).SetServicePermissionEncoding), arg0, arg1)
+}
+
+// SetTOTPSecret mocks base method.
+func (m *MockInterface) SetTOTPSecret(arg0 context.Context, arg1 *dto.UserIdSecret) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTOTPSecret", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetTOTPSecret indicates an expected call of SetTOTPSecret.
+func (mr *MockInterfaceMockRecorder) SetTOTPSecret(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTOTPSecret", reflect.TypeOf((*MockInterface)(nil).SetTOTPSecret), arg0, arg1)
+}
+
+// TOTPSecret mocks base method.
+func (m *MockInterface) TOTPSecret(arg0 context.Context, arg1 uuid.UUID) (dto.SecretEnabled, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TOTPSecret", arg0, arg1)
+	ret0, _ := ret[0].(dto.SecretEnabled)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TOTPSecret indicates an expected call of TOTPSecret.
+func (mr *MockInterfaceMockRecorder) TOTPSecret(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TOTPSecret", reflect.TypeOf((*MockInterface)(nil).TOTPSecret), arg0, arg1)
+}
+
+// UpdateAccountLastLogin mocks base method.
+func (m *MockInterface) UpdateAccountLastLogin(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAccountLastLogin", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAccountLastLogin indicates an expected call of UpdateAccountLastLogin.
+func (mr *MockInterfaceMockRecorder) UpdateAccountLastLogin(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccountLastLogin", reflect.TypeOf((*MockInterface)(nil).UpdateAccountLastLogin), arg0, arg1)
+}
+
+// UseRecoveryCode mocks base method.
+func (m *MockInterface) UseRecoveryCode(arg0 context.Context, arg1 *dto.UserIdCode) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseRecoveryCode", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UseRecoveryCode indicates an expected call of UseRecoveryCode.
+func (mr *MockInterfaceMockRecorder) UseRecoveryCode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseRecoveryCode", reflect.TypeOf((*MockInterface)(nil).UseRecoveryCode), arg0, arg1)
+}
+
 // UserIdAndPasswordHash mocks base method.
 func (m *MockInterface) UserIdAndPasswordHash(arg0 context.Context, arg1 login.Login) (dto.UserIdHash, error) {
 	m.ctrl.T.Helper()
@@ -896,6 +2533,36 @@ func (mr *MockInterfaceMockRecorder) UserIdAndPasswordHash(arg0, arg1 interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserIdAndPasswordHash", reflect.TypeOf((*MockInterface)(nil).UserIdAndPasswordHash), arg0, arg1)
 }
 
+// UserIdFromLoginChallenge mocks base method.
+func (m *MockInterface) UserIdFromLoginChallenge(arg0 context.Context, arg1 string) (uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserIdFromLoginChallenge", arg0, arg1)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UserIdFromLoginChallenge indicates an expected call of UserIdFromLoginChallenge.
+func (mr *MockInterfaceMockRecorder) UserIdFromLoginChallenge(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserIdFromLoginChallenge", reflect.TypeOf((*MockInterface)(nil).UserIdFromLoginChallenge), arg0, arg1)
+}
+
+// UserIdFromResetToken mocks base method.
+func (m *MockInterface) UserIdFromResetToken(arg0 context.Context, arg1 string) (uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserIdFromResetToken", arg0, arg1)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UserIdFromResetToken indicates an expected call of UserIdFromResetToken.
+func (mr *MockInterfaceMockRecorder) UserIdFromResetToken(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserIdFromResetToken", reflect.TypeOf((*MockInterface)(nil).UserIdFromResetToken), arg0, arg1)
+}
+
 // UserUUIDFromSession mocks base method.
 func (m *MockInterface) UserUUIDFromSession(ctx context.Context, sessionToken string) (uuid.UUID, error) {
 	m.ctrl.T.Helper()
@@ -2,38 +2,67 @@ package persistent
 
 import (
 	"context"
+	"github.com/google/uuid"
 	"github.com/lazylex/watch-store/secure/internal/domain/value_objects/account_state"
 	"github.com/lazylex/watch-store/secure/internal/domain/value_objects/login"
 	"github.com/lazylex/watch-store/secure/internal/dto"
+	"github.com/lazylex/watch-store/secure/internal/ports/common"
 	"github.com/lazylex/watch-store/secure/internal/ports/repository/joint"
+	"time"
 )
 
 type LoginInterface interface {
 	SetAccountState(context.Context, *dto.LoginState) error
 	AccountLoginData(context.Context, login.Login) (dto.UserIdLoginHashState, error)
 	SetAccountLoginData(context.Context, *dto.UserIdLoginHashState) error
+	CreateAccount(context.Context, *dto.UserIdLoginHashState, *dto.UserIdAccountMetadata) error
+	AccountLoginDataByUserId(context.Context, uuid.UUID) (dto.UserIdLoginHashState, error)
+	SetAccountPasswordHash(context.Context, *dto.UserIdHash) error
+	SetAccountMetadata(context.Context, *dto.UserIdAccountMetadata) error
+	UpdateAccountLastLogin(context.Context, uuid.UUID) error
 
 	AccountsLoginsByState(context.Context, account_state.State) ([]login.Login, error)
 }
 
+type TOTPInterface interface {
+	SetTOTPSecret(context.Context, *dto.UserIdSecret) error
+	TOTPSecret(context.Context, uuid.UUID) (dto.SecretEnabled, error)
+	EnableTOTP(context.Context, *dto.UserIdCodes) error
+	DeleteTOTP(context.Context, uuid.UUID) error
+	UseRecoveryCode(context.Context, *dto.UserIdCode) error
+}
+
 type RBACInterface interface {
-	CreatePermission(context.Context, *dto.NameServiceDescription) error
+	CreatePermission(context.Context, *dto.NameNumberDescriptionService) error
 	CreateRole(context.Context, *dto.NameServiceDescription) error
 	CreateGroup(context.Context, *dto.NameServiceDescription) error
 
 	AssignRoleToAccount(context.Context, *dto.UserIdRoleService) error
 	AssignGroupToAccount(context.Context, *dto.UserIdGroupService) error
 	AssignInstancePermissionToAccount(context.Context, *dto.UserIdInstancePermission) error
+	AssignInstanceRoleToAccount(context.Context, *dto.UserIdInstanceRole) error
+	AssignInstanceGroupToAccount(context.Context, *dto.UserIdInstanceGroup) error
+	AssignSelectorPermissionToAccount(context.Context, *dto.UserIdSelectorPermission) error
 
 	AssignRoleToGroup(context.Context, *dto.GroupRoleService) error
 	AssignPermissionToRole(context.Context, *dto.PermissionRoleService) error
+	AssignParentToRole(context.Context, *dto.RoleParentService) error
 	AssignPermissionToGroup(context.Context, *dto.GroupPermissionService) error
+	AssignSubgroupToGroup(context.Context, *dto.GroupSubgroupService) error
+
+	common.RBACGlobalGroupInterface
+	common.InstanceAttributesInterface
+
+	InstanceAttributes(context.Context, string) (map[string]string, error)
+	ConditionalPermissionsForAccount(context.Context, *dto.UserIdInstance) ([]dto.NumberCondition, error)
 
 	InstancePermissionsForAccount(context.Context, *dto.UserIdInstance) ([]dto.NameNumberDescription, error)
 	InstancePermissionsNumbersForAccount(context.Context, *dto.UserIdInstance) ([]int, error)
 
 	ServicePermissionsForAccount(context.Context, *dto.UserIdService) ([]dto.NameNumberDescription, error)
 	ServicePermissionsNumbersForAccount(context.Context, *dto.UserIdService) ([]int, error)
+	ServicePermissionsSourcesForAccount(context.Context, *dto.UserIdService) ([]dto.NumberSource, error)
+	PermissionsGrantPathsForAccount(context.Context, *dto.UserIdServiceInstance) ([]dto.NumberNameGrantPath, error)
 
 	PermissionNumber(ctx context.Context, permission string, instance string) (int, error)
 	ServiceNumberedPermissions(context.Context, string) (*[]dto.NameNumber, error)
@@ -41,13 +70,37 @@ type RBACInterface interface {
 	DeleteRole(context.Context, *dto.NameService) error
 	DeleteGroup(context.Context, *dto.NameService) error
 	DeletePermission(context.Context, *dto.NameService) error
+	DeprecatePermission(context.Context, *dto.NameService) error
+	DeleteInstance(context.Context, string) error
+	DeleteService(context.Context, string) error
+	DeleteAccount(context.Context, uuid.UUID) error
+	common.RBACRestoreInterface
+	PurgeDeleted(context.Context, time.Time) error
+
+	AccountHasRole(context.Context, *dto.UserIdRoleService) (bool, error)
+	GroupAccounts(context.Context, *dto.NameService) ([]uuid.UUID, error)
+	GlobalGroupAccounts(context.Context, string) ([]uuid.UUID, error)
+	GlobalGroupServices(context.Context, string) ([]string, error)
+	AccountGrantsExpiration(context.Context, *dto.UserIdInstance) (time.Time, error)
+	DeleteExpiredGrants(context.Context, time.Time) ([]dto.UserIdServiceInstance, error)
+
+	ImportRBAC(context.Context, *dto.RBACDocument, map[string]string, *dto.RBACRemovals) error
+}
+
+type OIDCInterface interface {
+	CreateOIDCClient(context.Context, *dto.OIDCClient) error
+	OIDCClient(context.Context, string) (dto.OIDCClient, error)
 }
 
 type Interface interface {
 	LoginInterface
+	TOTPInterface
+	OIDCInterface
 	joint.ServiceInterface
 	RBACInterface
+	common.ReadInterface
 	ServiceName(context.Context, string) (string, error)
+	ServicePermissionEncoding(context.Context, string) (string, error)
 	ServicesNames(context.Context) ([]string, error)
 	InstanceSecret(context.Context, string) (string, error)
 	MaxConnections() int
@@ -7,9 +7,11 @@ package mock_service
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	uuid "github.com/google/uuid"
+	login "github.com/lazylex/watch-store/secure/internal/domain/value_objects/login"
 	dto "github.com/lazylex/watch-store/secure/internal/dto"
 	service "github.com/lazylex/watch-store/secure/internal/service"
 )
@@ -37,6 +39,50 @@ func (m *MockService) EXPECT() *MockServiceMockRecorder {
 	return m.recorder
 }
 
+// AccountDetails mocks base method.
+func (m *MockService) AccountDetails(arg0 context.Context, arg1 uuid.UUID) (dto.AccountDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccountDetails", arg0, arg1)
+	ret0, _ := ret[0].(dto.AccountDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AccountDetails indicates an expected call of AccountDetails.
+func (mr *MockServiceMockRecorder) AccountDetails(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccountDetails", reflect.TypeOf((*MockService)(nil).AccountDetails), arg0, arg1)
+}
+
+// Accounts mocks base method.
+func (m *MockService) Accounts(arg0 context.Context, arg1 *dto.PageRequest) (dto.UserIdLoginStatePage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Accounts", arg0, arg1)
+	ret0, _ := ret[0].(dto.UserIdLoginStatePage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Accounts indicates an expected call of Accounts.
+func (mr *MockServiceMockRecorder) Accounts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Accounts", reflect.TypeOf((*MockService)(nil).Accounts), arg0, arg1)
+}
+
+// AssignGlobalGroupToAccount mocks base method.
+func (m *MockService) AssignGlobalGroupToAccount(arg0 context.Context, arg1 *dto.UserIdGlobalGroup) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignGlobalGroupToAccount", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignGlobalGroupToAccount indicates an expected call of AssignGlobalGroupToAccount.
+func (mr *MockServiceMockRecorder) AssignGlobalGroupToAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignGlobalGroupToAccount", reflect.TypeOf((*MockService)(nil).AssignGlobalGroupToAccount), arg0, arg1)
+}
+
 // AssignGroupToAccount mocks base method.
 func (m *MockService) AssignGroupToAccount(arg0 context.Context, arg1 *dto.UserIdGroupService) error {
 	m.ctrl.T.Helper()
@@ -51,6 +97,20 @@ func (mr *MockServiceMockRecorder) AssignGroupToAccount(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignGroupToAccount", reflect.TypeOf((*MockService)(nil).AssignGroupToAccount), arg0, arg1)
 }
 
+// AssignInstanceGroupToAccount mocks base method.
+func (m *MockService) AssignInstanceGroupToAccount(arg0 context.Context, arg1 *dto.UserIdInstanceGroup) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignInstanceGroupToAccount", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignInstanceGroupToAccount indicates an expected call of AssignInstanceGroupToAccount.
+func (mr *MockServiceMockRecorder) AssignInstanceGroupToAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignInstanceGroupToAccount", reflect.TypeOf((*MockService)(nil).AssignInstanceGroupToAccount), arg0, arg1)
+}
+
 // AssignInstancePermissionToAccount mocks base method.
 func (m *MockService) AssignInstancePermissionToAccount(arg0 context.Context, arg1 *dto.UserIdInstancePermission) error {
 	m.ctrl.T.Helper()
@@ -65,6 +125,34 @@ func (mr *MockServiceMockRecorder) AssignInstancePermissionToAccount(arg0, arg1
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignInstancePermissionToAccount", reflect.TypeOf((*MockService)(nil).AssignInstancePermissionToAccount), arg0, arg1)
 }
 
+// AssignInstanceRoleToAccount mocks base method.
+func (m *MockService) AssignInstanceRoleToAccount(arg0 context.Context, arg1 *dto.UserIdInstanceRole) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignInstanceRoleToAccount", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignInstanceRoleToAccount indicates an expected call of AssignInstanceRoleToAccount.
+func (mr *MockServiceMockRecorder) AssignInstanceRoleToAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignInstanceRoleToAccount", reflect.TypeOf((*MockService)(nil).AssignInstanceRoleToAccount), arg0, arg1)
+}
+
+// AssignParentToRole mocks base method.
+func (m *MockService) AssignParentToRole(arg0 context.Context, arg1 *dto.RoleParentService) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignParentToRole", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignParentToRole indicates an expected call of AssignParentToRole.
+func (mr *MockServiceMockRecorder) AssignParentToRole(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignParentToRole", reflect.TypeOf((*MockService)(nil).AssignParentToRole), arg0, arg1)
+}
+
 // AssignPermissionToGroup mocks base method.
 func (m *MockService) AssignPermissionToGroup(arg0 context.Context, arg1 *dto.GroupPermissionService) error {
 	m.ctrl.T.Helper()
@@ -107,6 +195,20 @@ func (mr *MockServiceMockRecorder) AssignRoleToAccount(arg0, arg1 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignRoleToAccount", reflect.TypeOf((*MockService)(nil).AssignRoleToAccount), arg0, arg1)
 }
 
+// AssignRoleToGlobalGroup mocks base method.
+func (m *MockService) AssignRoleToGlobalGroup(arg0 context.Context, arg1 *dto.GlobalGroupRoleService) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignRoleToGlobalGroup", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignRoleToGlobalGroup indicates an expected call of AssignRoleToGlobalGroup.
+func (mr *MockServiceMockRecorder) AssignRoleToGlobalGroup(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignRoleToGlobalGroup", reflect.TypeOf((*MockService)(nil).AssignRoleToGlobalGroup), arg0, arg1)
+}
+
 // AssignRoleToGroup mocks base method.
 func (m *MockService) AssignRoleToGroup(arg0 context.Context, arg1 *dto.GroupRoleService) error {
 	m.ctrl.T.Helper()
@@ -121,6 +223,167 @@ func (mr *MockServiceMockRecorder) AssignRoleToGroup(arg0, arg1 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignRoleToGroup", reflect.TypeOf((*MockService)(nil).AssignRoleToGroup), arg0, arg1)
 }
 
+// AssignSelectorPermissionToAccount mocks base method.
+func (m *MockService) AssignSelectorPermissionToAccount(arg0 context.Context, arg1 *dto.UserIdSelectorPermission) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignSelectorPermissionToAccount", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignSelectorPermissionToAccount indicates an expected call of AssignSelectorPermissionToAccount.
+func (mr *MockServiceMockRecorder) AssignSelectorPermissionToAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignSelectorPermissionToAccount", reflect.TypeOf((*MockService)(nil).AssignSelectorPermissionToAccount), arg0, arg1)
+}
+
+// AssignSubgroupToGroup mocks base method.
+func (m *MockService) AssignSubgroupToGroup(arg0 context.Context, arg1 *dto.GroupSubgroupService) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignSubgroupToGroup", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignSubgroupToGroup indicates an expected call of AssignSubgroupToGroup.
+func (mr *MockServiceMockRecorder) AssignSubgroupToGroup(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignSubgroupToGroup", reflect.TypeOf((*MockService)(nil).AssignSubgroupToGroup), arg0, arg1)
+}
+
+// AuthenticatePassword mocks base method.
+func (m *MockService) AuthenticatePassword(arg0 context.Context, arg1 *dto.LoginPassword) (uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthenticatePassword", arg0, arg1)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuthenticatePassword indicates an expected call of AuthenticatePassword.
+func (mr *MockServiceMockRecorder) AuthenticatePassword(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthenticatePassword", reflect.TypeOf((*MockService)(nil).AuthenticatePassword), arg0, arg1)
+}
+
+// Authorize mocks base method.
+func (m *MockService) Authorize(arg0 context.Context, arg1 *dto.AuthorizationRequest) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authorize", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authorize indicates an expected call of Authorize.
+func (mr *MockServiceMockRecorder) Authorize(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authorize", reflect.TypeOf((*MockService)(nil).Authorize), arg0, arg1)
+}
+
+// ChangePassword mocks base method.
+func (m *MockService) ChangePassword(arg0 context.Context, arg1 *dto.UserIdOldNewPassword) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangePassword", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangePassword indicates an expected call of ChangePassword.
+func (mr *MockServiceMockRecorder) ChangePassword(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePassword", reflect.TypeOf((*MockService)(nil).ChangePassword), arg0, arg1)
+}
+
+// CheckPermission mocks base method.
+func (m *MockService) CheckPermission(arg0 context.Context, arg1 uuid.UUID, arg2 string, arg3 *dto.UserIdInstanceNumber) (dto.PermissionDecision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckPermission", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(dto.PermissionDecision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckPermission indicates an expected call of CheckPermission.
+func (mr *MockServiceMockRecorder) CheckPermission(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckPermission", reflect.TypeOf((*MockService)(nil).CheckPermission), arg0, arg1, arg2, arg3)
+}
+
+// CheckPermissions mocks base method.
+func (m *MockService) CheckPermissions(arg0 context.Context, arg1 uuid.UUID, arg2 string, arg3 []dto.UserIdInstanceNumber) ([]dto.PermissionDecision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckPermissions", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]dto.PermissionDecision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckPermissions indicates an expected call of CheckPermissions.
+func (mr *MockServiceMockRecorder) CheckPermissions(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckPermissions", reflect.TypeOf((*MockService)(nil).CheckPermissions), arg0, arg1, arg2, arg3)
+}
+
+// ClientCredentialsToken mocks base method.
+func (m *MockService) ClientCredentialsToken(arg0 context.Context, arg1 *dto.LoginPasswordInstance) (dto.TokenTTL, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClientCredentialsToken", arg0, arg1)
+	ret0, _ := ret[0].(dto.TokenTTL)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClientCredentialsToken indicates an expected call of ClientCredentialsToken.
+func (mr *MockServiceMockRecorder) ClientCredentialsToken(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClientCredentialsToken", reflect.TypeOf((*MockService)(nil).ClientCredentialsToken), arg0, arg1)
+}
+
+// CompleteLogin mocks base method.
+func (m *MockService) CompleteLogin(arg0 context.Context, arg1 *dto.TokenCode) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteLogin", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompleteLogin indicates an expected call of CompleteLogin.
+func (mr *MockServiceMockRecorder) CompleteLogin(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteLogin", reflect.TypeOf((*MockService)(nil).CompleteLogin), arg0, arg1)
+}
+
+// CompletePasswordReset mocks base method.
+func (m *MockService) CompletePasswordReset(arg0 context.Context, arg1 *dto.TokenPassword) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompletePasswordReset", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompletePasswordReset indicates an expected call of CompletePasswordReset.
+func (mr *MockServiceMockRecorder) CompletePasswordReset(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompletePasswordReset", reflect.TypeOf((*MockService)(nil).CompletePasswordReset), arg0, arg1)
+}
+
+// ConfirmTOTP mocks base method.
+func (m *MockService) ConfirmTOTP(arg0 context.Context, arg1 *dto.UserIdCode) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmTOTP", arg0, arg1)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfirmTOTP indicates an expected call of ConfirmTOTP.
+func (mr *MockServiceMockRecorder) ConfirmTOTP(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmTOTP", reflect.TypeOf((*MockService)(nil).ConfirmTOTP), arg0, arg1)
+}
+
 // CreateAccount mocks base method.
 func (m *MockService) CreateAccount(arg0 context.Context, arg1 *dto.LoginPassword, arg2 service.AccountOptions) (uuid.UUID, error) {
 	m.ctrl.T.Helper()
@@ -136,6 +399,20 @@ func (mr *MockServiceMockRecorder) CreateAccount(arg0, arg1, arg2 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccount", reflect.TypeOf((*MockService)(nil).CreateAccount), arg0, arg1, arg2)
 }
 
+// CreateGlobalGroup mocks base method.
+func (m *MockService) CreateGlobalGroup(arg0 context.Context, arg1 *dto.NameDescription) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateGlobalGroup", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateGlobalGroup indicates an expected call of CreateGlobalGroup.
+func (mr *MockServiceMockRecorder) CreateGlobalGroup(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGlobalGroup", reflect.TypeOf((*MockService)(nil).CreateGlobalGroup), arg0, arg1)
+}
+
 // CreateGroup mocks base method.
 func (m *MockService) CreateGroup(arg0 context.Context, arg1 *dto.NameServiceDescription) error {
 	m.ctrl.T.Helper()
@@ -151,7 +428,7 @@ func (mr *MockServiceMockRecorder) CreateGroup(arg0, arg1 interface{}) *gomock.C
 }
 
 // CreatePermission mocks base method.
-func (m *MockService) CreatePermission(arg0 context.Context, arg1 *dto.NameServiceDescription) error {
+func (m *MockService) CreatePermission(arg0 context.Context, arg1 *dto.NameNumberDescriptionService) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreatePermission", arg0, arg1)
 	ret0, _ := ret[0].(error)
@@ -179,7 +456,7 @@ func (mr *MockServiceMockRecorder) CreateRole(arg0, arg1 interface{}) *gomock.Ca
 }
 
 // CreateToken mocks base method.
-func (m *MockService) CreateToken(arg0 context.Context, arg1 *dto.UserIdInstance) (string, error) {
+func (m *MockService) CreateToken(arg0 context.Context, arg1 *dto.UserIdInstanceAddress) (string, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateToken", arg0, arg1)
 	ret0, _ := ret[0].(string)
@@ -193,6 +470,48 @@ func (mr *MockServiceMockRecorder) CreateToken(arg0, arg1 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateToken", reflect.TypeOf((*MockService)(nil).CreateToken), arg0, arg1)
 }
 
+// DeleteAccount mocks base method.
+func (m *MockService) DeleteAccount(ctx context.Context, callerId, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAccount", ctx, callerId, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAccount indicates an expected call of DeleteAccount.
+func (mr *MockServiceMockRecorder) DeleteAccount(ctx, callerId, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccount", reflect.TypeOf((*MockService)(nil).DeleteAccount), ctx, callerId, id)
+}
+
+// DeleteExpiredGrants mocks base method.
+func (m *MockService) DeleteExpiredGrants(arg0 context.Context, arg1 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredGrants", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExpiredGrants indicates an expected call of DeleteExpiredGrants.
+func (mr *MockServiceMockRecorder) DeleteExpiredGrants(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredGrants", reflect.TypeOf((*MockService)(nil).DeleteExpiredGrants), arg0, arg1)
+}
+
+// DeleteGlobalGroup mocks base method.
+func (m *MockService) DeleteGlobalGroup(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteGlobalGroup", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteGlobalGroup indicates an expected call of DeleteGlobalGroup.
+func (mr *MockServiceMockRecorder) DeleteGlobalGroup(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteGlobalGroup", reflect.TypeOf((*MockService)(nil).DeleteGlobalGroup), arg0, arg1)
+}
+
 // DeleteGroup mocks base method.
 func (m *MockService) DeleteGroup(arg0 context.Context, arg1 *dto.NameService) error {
 	m.ctrl.T.Helper()
@@ -207,6 +526,34 @@ func (mr *MockServiceMockRecorder) DeleteGroup(arg0, arg1 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteGroup", reflect.TypeOf((*MockService)(nil).DeleteGroup), arg0, arg1)
 }
 
+// DeleteInstance mocks base method.
+func (m *MockService) DeleteInstance(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteInstance", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteInstance indicates an expected call of DeleteInstance.
+func (mr *MockServiceMockRecorder) DeleteInstance(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteInstance", reflect.TypeOf((*MockService)(nil).DeleteInstance), arg0, arg1)
+}
+
+// DeleteInstanceAttribute mocks base method.
+func (m *MockService) DeleteInstanceAttribute(arg0 context.Context, arg1 *dto.NameInstance) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteInstanceAttribute", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteInstanceAttribute indicates an expected call of DeleteInstanceAttribute.
+func (mr *MockServiceMockRecorder) DeleteInstanceAttribute(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteInstanceAttribute", reflect.TypeOf((*MockService)(nil).DeleteInstanceAttribute), arg0, arg1)
+}
+
 // DeletePermission mocks base method.
 func (m *MockService) DeletePermission(arg0 context.Context, arg1 *dto.NameService) error {
 	m.ctrl.T.Helper()
@@ -235,6 +582,227 @@ func (mr *MockServiceMockRecorder) DeleteRole(arg0, arg1 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRole", reflect.TypeOf((*MockService)(nil).DeleteRole), arg0, arg1)
 }
 
+// DeleteService mocks base method.
+func (m *MockService) DeleteService(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteService", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteService indicates an expected call of DeleteService.
+func (mr *MockServiceMockRecorder) DeleteService(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteService", reflect.TypeOf((*MockService)(nil).DeleteService), arg0, arg1)
+}
+
+// DeprecatePermission mocks base method.
+func (m *MockService) DeprecatePermission(arg0 context.Context, arg1 *dto.NameService) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeprecatePermission", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeprecatePermission indicates an expected call of DeprecatePermission.
+func (mr *MockServiceMockRecorder) DeprecatePermission(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeprecatePermission", reflect.TypeOf((*MockService)(nil).DeprecatePermission), arg0, arg1)
+}
+
+// DisableTOTP mocks base method.
+func (m *MockService) DisableTOTP(arg0 context.Context, arg1 *dto.UserIdCode) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableTOTP", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisableTOTP indicates an expected call of DisableTOTP.
+func (mr *MockServiceMockRecorder) DisableTOTP(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableTOTP", reflect.TypeOf((*MockService)(nil).DisableTOTP), arg0, arg1)
+}
+
+// EnrollTOTP mocks base method.
+func (m *MockService) EnrollTOTP(arg0 context.Context, arg1 uuid.UUID) (dto.SecretURI, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnrollTOTP", arg0, arg1)
+	ret0, _ := ret[0].(dto.SecretURI)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnrollTOTP indicates an expected call of EnrollTOTP.
+func (mr *MockServiceMockRecorder) EnrollTOTP(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnrollTOTP", reflect.TypeOf((*MockService)(nil).EnrollTOTP), arg0, arg1)
+}
+
+// ExchangeAuthorizationCode mocks base method.
+func (m *MockService) ExchangeAuthorizationCode(arg0 context.Context, arg1 *dto.AuthorizationCodeExchange) (dto.OIDCTokens, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExchangeAuthorizationCode", arg0, arg1)
+	ret0, _ := ret[0].(dto.OIDCTokens)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExchangeAuthorizationCode indicates an expected call of ExchangeAuthorizationCode.
+func (mr *MockServiceMockRecorder) ExchangeAuthorizationCode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExchangeAuthorizationCode", reflect.TypeOf((*MockService)(nil).ExchangeAuthorizationCode), arg0, arg1)
+}
+
+// ExplainPermissions mocks base method.
+func (m *MockService) ExplainPermissions(arg0 context.Context, arg1 *dto.UserIdServiceInstance) ([]dto.NumberNamePaths, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExplainPermissions", arg0, arg1)
+	ret0, _ := ret[0].([]dto.NumberNamePaths)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExplainPermissions indicates an expected call of ExplainPermissions.
+func (mr *MockServiceMockRecorder) ExplainPermissions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExplainPermissions", reflect.TypeOf((*MockService)(nil).ExplainPermissions), arg0, arg1)
+}
+
+// ExportRBAC mocks base method.
+func (m *MockService) ExportRBAC(arg0 context.Context) (dto.RBACDocument, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportRBAC", arg0)
+	ret0, _ := ret[0].(dto.RBACDocument)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportRBAC indicates an expected call of ExportRBAC.
+func (mr *MockServiceMockRecorder) ExportRBAC(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportRBAC", reflect.TypeOf((*MockService)(nil).ExportRBAC), arg0)
+}
+
+// GroupDetails mocks base method.
+func (m *MockService) GroupDetails(arg0 context.Context, arg1 *dto.NameService) (dto.GroupDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GroupDetails", arg0, arg1)
+	ret0, _ := ret[0].(dto.GroupDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GroupDetails indicates an expected call of GroupDetails.
+func (mr *MockServiceMockRecorder) GroupDetails(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GroupDetails", reflect.TypeOf((*MockService)(nil).GroupDetails), arg0, arg1)
+}
+
+// Groups mocks base method.
+func (m *MockService) Groups(arg0 context.Context, arg1 *dto.PageRequest) (dto.NameDescriptionPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Groups", arg0, arg1)
+	ret0, _ := ret[0].(dto.NameDescriptionPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Groups indicates an expected call of Groups.
+func (mr *MockServiceMockRecorder) Groups(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Groups", reflect.TypeOf((*MockService)(nil).Groups), arg0, arg1)
+}
+
+// ImportRBAC mocks base method.
+func (m *MockService) ImportRBAC(arg0 context.Context, arg1 *dto.RBACDocument, arg2 bool) (dto.RBACImportResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportRBAC", arg0, arg1, arg2)
+	ret0, _ := ret[0].(dto.RBACImportResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportRBAC indicates an expected call of ImportRBAC.
+func (mr *MockServiceMockRecorder) ImportRBAC(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportRBAC", reflect.TypeOf((*MockService)(nil).ImportRBAC), arg0, arg1, arg2)
+}
+
+// InstanceDetails mocks base method.
+func (m *MockService) InstanceDetails(arg0 context.Context, arg1 string) (dto.InstanceDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InstanceDetails", arg0, arg1)
+	ret0, _ := ret[0].(dto.InstanceDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InstanceDetails indicates an expected call of InstanceDetails.
+func (mr *MockServiceMockRecorder) InstanceDetails(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstanceDetails", reflect.TypeOf((*MockService)(nil).InstanceDetails), arg0, arg1)
+}
+
+// Instances mocks base method.
+func (m *MockService) Instances(arg0 context.Context, arg1 *dto.PageRequest) (dto.NameServicePage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Instances", arg0, arg1)
+	ret0, _ := ret[0].(dto.NameServicePage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Instances indicates an expected call of Instances.
+func (mr *MockServiceMockRecorder) Instances(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Instances", reflect.TypeOf((*MockService)(nil).Instances), arg0, arg1)
+}
+
+// IntrospectToken mocks base method.
+func (m *MockService) IntrospectToken(arg0 context.Context, arg1 string) (dto.TokenIntrospection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IntrospectToken", arg0, arg1)
+	ret0, _ := ret[0].(dto.TokenIntrospection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IntrospectToken indicates an expected call of IntrospectToken.
+func (mr *MockServiceMockRecorder) IntrospectToken(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IntrospectToken", reflect.TypeOf((*MockService)(nil).IntrospectToken), arg0, arg1)
+}
+
+// IsAdmin mocks base method.
+func (m *MockService) IsAdmin(arg0 context.Context, arg1 uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsAdmin", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsAdmin indicates an expected call of IsAdmin.
+func (mr *MockServiceMockRecorder) IsAdmin(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsAdmin", reflect.TypeOf((*MockService)(nil).IsAdmin), arg0, arg1)
+}
+
+// JSONWebKeySet mocks base method.
+func (m *MockService) JSONWebKeySet() dto.JSONWebKeySet {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "JSONWebKeySet")
+	ret0, _ := ret[0].(dto.JSONWebKeySet)
+	return ret0
+}
+
+// JSONWebKeySet indicates an expected call of JSONWebKeySet.
+func (mr *MockServiceMockRecorder) JSONWebKeySet() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JSONWebKeySet", reflect.TypeOf((*MockService)(nil).JSONWebKeySet))
+}
+
 // Login mocks base method.
 func (m *MockService) Login(arg0 context.Context, arg1 *dto.LoginPassword) (string, error) {
 	m.ctrl.T.Helper()
@@ -250,6 +818,21 @@ func (mr *MockServiceMockRecorder) Login(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockService)(nil).Login), arg0, arg1)
 }
 
+// LoginWithCertificate mocks base method.
+func (m *MockService) LoginWithCertificate(arg0 context.Context, arg1 login.Login) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoginWithCertificate", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoginWithCertificate indicates an expected call of LoginWithCertificate.
+func (mr *MockServiceMockRecorder) LoginWithCertificate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoginWithCertificate", reflect.TypeOf((*MockService)(nil).LoginWithCertificate), arg0, arg1)
+}
+
 // Logout mocks base method.
 func (m *MockService) Logout(arg0 context.Context, arg1 uuid.UUID) error {
 	m.ctrl.T.Helper()
@@ -264,6 +847,63 @@ func (mr *MockServiceMockRecorder) Logout(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockService)(nil).Logout), arg0, arg1)
 }
 
+// OIDCEnabled mocks base method.
+func (m *MockService) OIDCEnabled() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OIDCEnabled")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// OIDCEnabled indicates an expected call of OIDCEnabled.
+func (mr *MockServiceMockRecorder) OIDCEnabled() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OIDCEnabled", reflect.TypeOf((*MockService)(nil).OIDCEnabled))
+}
+
+// OpenIDConfiguration mocks base method.
+func (m *MockService) OpenIDConfiguration() dto.OpenIDConfiguration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenIDConfiguration")
+	ret0, _ := ret[0].(dto.OpenIDConfiguration)
+	return ret0
+}
+
+// OpenIDConfiguration indicates an expected call of OpenIDConfiguration.
+func (mr *MockServiceMockRecorder) OpenIDConfiguration() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenIDConfiguration", reflect.TypeOf((*MockService)(nil).OpenIDConfiguration))
+}
+
+// PurgeDeleted mocks base method.
+func (m *MockService) PurgeDeleted(arg0 context.Context, arg1 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeleted", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgeDeleted indicates an expected call of PurgeDeleted.
+func (mr *MockServiceMockRecorder) PurgeDeleted(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeleted", reflect.TypeOf((*MockService)(nil).PurgeDeleted), arg0, arg1)
+}
+
+// ReconcileRBAC mocks base method.
+func (m *MockService) ReconcileRBAC(arg0 context.Context, arg1 *dto.RBACDocument, arg2 bool) (dto.RBACImportResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReconcileRBAC", arg0, arg1, arg2)
+	ret0, _ := ret[0].(dto.RBACImportResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReconcileRBAC indicates an expected call of ReconcileRBAC.
+func (mr *MockServiceMockRecorder) ReconcileRBAC(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReconcileRBAC", reflect.TypeOf((*MockService)(nil).ReconcileRBAC), arg0, arg1, arg2)
+}
+
 // RegisterInstance mocks base method.
 func (m *MockService) RegisterInstance(arg0 context.Context, arg1 *dto.NameServiceSecret) error {
 	m.ctrl.T.Helper()
@@ -278,6 +918,21 @@ func (mr *MockServiceMockRecorder) RegisterInstance(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterInstance", reflect.TypeOf((*MockService)(nil).RegisterInstance), arg0, arg1)
 }
 
+// RegisterOIDCClient mocks base method.
+func (m *MockService) RegisterOIDCClient(arg0 context.Context, arg1 *dto.OIDCClientRegistration) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterOIDCClient", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegisterOIDCClient indicates an expected call of RegisterOIDCClient.
+func (mr *MockServiceMockRecorder) RegisterOIDCClient(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterOIDCClient", reflect.TypeOf((*MockService)(nil).RegisterOIDCClient), arg0, arg1)
+}
+
 // RegisterService mocks base method.
 func (m *MockService) RegisterService(arg0 context.Context, arg1 *dto.NameDescription) error {
 	m.ctrl.T.Helper()
@@ -292,6 +947,164 @@ func (mr *MockServiceMockRecorder) RegisterService(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterService", reflect.TypeOf((*MockService)(nil).RegisterService), arg0, arg1)
 }
 
+// ResetPassword mocks base method.
+func (m *MockService) ResetPassword(arg0 context.Context, arg1 login.Login) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPassword", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResetPassword indicates an expected call of ResetPassword.
+func (mr *MockServiceMockRecorder) ResetPassword(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockService)(nil).ResetPassword), arg0, arg1)
+}
+
+// RestoreAccount mocks base method.
+func (m *MockService) RestoreAccount(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreAccount", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreAccount indicates an expected call of RestoreAccount.
+func (mr *MockServiceMockRecorder) RestoreAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreAccount", reflect.TypeOf((*MockService)(nil).RestoreAccount), arg0, arg1)
+}
+
+// RestoreGroup mocks base method.
+func (m *MockService) RestoreGroup(arg0 context.Context, arg1 *dto.NameService) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreGroup", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreGroup indicates an expected call of RestoreGroup.
+func (mr *MockServiceMockRecorder) RestoreGroup(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreGroup", reflect.TypeOf((*MockService)(nil).RestoreGroup), arg0, arg1)
+}
+
+// RestoreInstance mocks base method.
+func (m *MockService) RestoreInstance(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreInstance", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreInstance indicates an expected call of RestoreInstance.
+func (mr *MockServiceMockRecorder) RestoreInstance(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreInstance", reflect.TypeOf((*MockService)(nil).RestoreInstance), arg0, arg1)
+}
+
+// RestorePermission mocks base method.
+func (m *MockService) RestorePermission(arg0 context.Context, arg1 *dto.NameService) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestorePermission", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestorePermission indicates an expected call of RestorePermission.
+func (mr *MockServiceMockRecorder) RestorePermission(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestorePermission", reflect.TypeOf((*MockService)(nil).RestorePermission), arg0, arg1)
+}
+
+// RestoreRole mocks base method.
+func (m *MockService) RestoreRole(arg0 context.Context, arg1 *dto.NameService) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreRole", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreRole indicates an expected call of RestoreRole.
+func (mr *MockServiceMockRecorder) RestoreRole(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreRole", reflect.TypeOf((*MockService)(nil).RestoreRole), arg0, arg1)
+}
+
+// RestoreService mocks base method.
+func (m *MockService) RestoreService(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreService", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreService indicates an expected call of RestoreService.
+func (mr *MockServiceMockRecorder) RestoreService(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreService", reflect.TypeOf((*MockService)(nil).RestoreService), arg0, arg1)
+}
+
+// RevokeToken mocks base method.
+func (m *MockService) RevokeToken(arg0 context.Context, arg1 uuid.UUID, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeToken", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeToken indicates an expected call of RevokeToken.
+func (mr *MockServiceMockRecorder) RevokeToken(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeToken", reflect.TypeOf((*MockService)(nil).RevokeToken), arg0, arg1, arg2)
+}
+
+// RoleDetails mocks base method.
+func (m *MockService) RoleDetails(arg0 context.Context, arg1 *dto.NameService) (dto.RoleDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RoleDetails", arg0, arg1)
+	ret0, _ := ret[0].(dto.RoleDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RoleDetails indicates an expected call of RoleDetails.
+func (mr *MockServiceMockRecorder) RoleDetails(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RoleDetails", reflect.TypeOf((*MockService)(nil).RoleDetails), arg0, arg1)
+}
+
+// Roles mocks base method.
+func (m *MockService) Roles(arg0 context.Context, arg1 *dto.PageRequest) (dto.NameDescriptionPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Roles", arg0, arg1)
+	ret0, _ := ret[0].(dto.NameDescriptionPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Roles indicates an expected call of Roles.
+func (mr *MockServiceMockRecorder) Roles(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Roles", reflect.TypeOf((*MockService)(nil).Roles), arg0, arg1)
+}
+
+// ServiceDetails mocks base method.
+func (m *MockService) ServiceDetails(arg0 context.Context, arg1 string) (dto.ServiceDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ServiceDetails", arg0, arg1)
+	ret0, _ := ret[0].(dto.ServiceDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ServiceDetails indicates an expected call of ServiceDetails.
+func (mr *MockServiceMockRecorder) ServiceDetails(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ServiceDetails", reflect.TypeOf((*MockService)(nil).ServiceDetails), arg0, arg1)
+}
+
 // ServiceNumberedPermissions mocks base method.
 func (m *MockService) ServiceNumberedPermissions(arg0 context.Context, arg1 string) (*[]dto.NameNumber, error) {
 	m.ctrl.T.Helper()
@@ -307,6 +1120,93 @@ func (mr *MockServiceMockRecorder) ServiceNumberedPermissions(arg0, arg1 interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ServiceNumberedPermissions", reflect.TypeOf((*MockService)(nil).ServiceNumberedPermissions), arg0, arg1)
 }
 
+// Services mocks base method.
+func (m *MockService) Services(arg0 context.Context, arg1 *dto.PageRequest) (dto.NameDescriptionPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Services", arg0, arg1)
+	ret0, _ := ret[0].(dto.NameDescriptionPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Services indicates an expected call of Services.
+func (mr *MockServiceMockRecorder) Services(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Services", reflect.TypeOf((*MockService)(nil).Services), arg0, arg1)
+}
+
+// SetAccountMetadata mocks base method.
+func (m *MockService) SetAccountMetadata(arg0 context.Context, arg1 *dto.UserIdAccountMetadata) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAccountMetadata", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAccountMetadata indicates an expected call of SetAccountMetadata.
+func (mr *MockServiceMockRecorder) SetAccountMetadata(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAccountMetadata", reflect.TypeOf((*MockService)(nil).SetAccountMetadata), arg0, arg1)
+}
+
+// SetInstanceAttribute mocks base method.
+func (m *MockService) SetInstanceAttribute(arg0 context.Context, arg1 *dto.InstanceNameValue) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetInstanceAttribute", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetInstanceAttribute indicates an expected call of SetInstanceAttribute.
+func (mr *MockServiceMockRecorder) SetInstanceAttribute(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetInstanceAttribute", reflect.TypeOf((*MockService)(nil).SetInstanceAttribute), arg0, arg1)
+}
+
+// SetServicePermissionEncoding mocks base method.
+func (m *MockService) SetServicePermissionEncoding(arg0 context.Context, arg1 *dto.NameEncoding) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetServicePermissionEncoding", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetServicePermissionEncoding indicates an expected call of SetServicePermissionEncoding.
+func (mr *MockServiceMockRecorder) SetServicePermissionEncoding(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetServicePermissionEncoding", reflect.TypeOf((*MockService)(nil).SetServicePermissionEncoding), arg0, arg1)
+}
+
+// UserIdFromAccessToken mocks base method.
+func (m *MockService) UserIdFromAccessToken(arg0 string) (uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserIdFromAccessToken", arg0)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UserIdFromAccessToken indicates an expected call of UserIdFromAccessToken.
+func (mr *MockServiceMockRecorder) UserIdFromAccessToken(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserIdFromAccessToken", reflect.TypeOf((*MockService)(nil).UserIdFromAccessToken), arg0)
+}
+
+// UserInfo mocks base method.
+func (m *MockService) UserInfo(arg0 context.Context, arg1 uuid.UUID) (login.Login, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserInfo", arg0, arg1)
+	ret0, _ := ret[0].(login.Login)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UserInfo indicates an expected call of UserInfo.
+func (mr *MockServiceMockRecorder) UserInfo(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserInfo", reflect.TypeOf((*MockService)(nil).UserInfo), arg0, arg1)
+}
+
 // UserUUIDFromSession mocks base method.
 func (m *MockService) UserUUIDFromSession(arg0 context.Context, arg1 string) (uuid.UUID, error) {
 	m.ctrl.T.Helper()
@@ -3,28 +3,82 @@ package service
 import (
 	"context"
 	"github.com/google/uuid"
+	"github.com/lazylex/watch-store/secure/internal/domain/value_objects/login"
 	"github.com/lazylex/watch-store/secure/internal/dto"
 	"github.com/lazylex/watch-store/secure/internal/ports/common"
 	"github.com/lazylex/watch-store/secure/internal/service"
+	"time"
 )
 
 //go:generate mockgen -source=service.go -destination=mocks/service.go
 type Service interface {
 	Login(context.Context, *dto.LoginPassword) (string, error)
 	Logout(context.Context, uuid.UUID) error
+	CompleteLogin(context.Context, *dto.TokenCode) (string, error)
+	LoginWithCertificate(context.Context, login.Login) (string, error)
+	AuthenticatePassword(context.Context, *dto.LoginPassword) (uuid.UUID, error)
+
+	EnrollTOTP(context.Context, uuid.UUID) (dto.SecretURI, error)
+	ConfirmTOTP(context.Context, *dto.UserIdCode) ([]string, error)
+	DisableTOTP(context.Context, *dto.UserIdCode) error
 
 	UserUUIDFromSession(context.Context, string) (uuid.UUID, error)
+	IsAdmin(context.Context, uuid.UUID) (bool, error)
+
+	ChangePassword(context.Context, *dto.UserIdOldNewPassword) error
+	ResetPassword(context.Context, login.Login) (string, error)
+	CompletePasswordReset(context.Context, *dto.TokenPassword) error
 
 	CreateAccount(context.Context, *dto.LoginPassword, service.AccountOptions) (uuid.UUID, error)
+	SetAccountMetadata(context.Context, *dto.UserIdAccountMetadata) error
+	DeleteAccount(ctx context.Context, callerId, id uuid.UUID) error
 
 	RegisterInstance(context.Context, *dto.NameServiceSecret) error
 	RegisterService(context.Context, *dto.NameDescription) error
+	SetServicePermissionEncoding(context.Context, *dto.NameEncoding) error
 
 	common.RBACCreateInterface
 	common.RBACAssignToAccountInterface
 	common.RBACAssignInterface
 	common.RBACDeleteInterface
+	common.RBACRestoreInterface
+	common.RBACGlobalGroupInterface
+	common.InstanceAttributesInterface
 
-	CreateToken(context.Context, *dto.UserIdInstance) (string, error)
+	OIDCEnabled() bool
+	OpenIDConfiguration() dto.OpenIDConfiguration
+	JSONWebKeySet() dto.JSONWebKeySet
+	RegisterOIDCClient(context.Context, *dto.OIDCClientRegistration) (string, error)
+	Authorize(context.Context, *dto.AuthorizationRequest) (string, error)
+	ExchangeAuthorizationCode(context.Context, *dto.AuthorizationCodeExchange) (dto.OIDCTokens, error)
+	UserIdFromAccessToken(string) (uuid.UUID, error)
+	UserInfo(context.Context, uuid.UUID) (login.Login, error)
+
+	CreateToken(context.Context, *dto.UserIdInstanceAddress) (string, error)
+	ClientCredentialsToken(context.Context, *dto.LoginPasswordInstance) (dto.TokenTTL, error)
+	IntrospectToken(context.Context, string) (dto.TokenIntrospection, error)
+	RevokeToken(context.Context, uuid.UUID, string) error
 	ServiceNumberedPermissions(context.Context, string) (*[]dto.NameNumber, error)
+
+	CheckPermission(context.Context, uuid.UUID, string, *dto.UserIdInstanceNumber) (dto.PermissionDecision, error)
+	CheckPermissions(context.Context, uuid.UUID, string, []dto.UserIdInstanceNumber) ([]dto.PermissionDecision, error)
+	ExplainPermissions(context.Context, *dto.UserIdServiceInstance) ([]dto.NumberNamePaths, error)
+
+	Services(context.Context, *dto.PageRequest) (dto.NameDescriptionPage, error)
+	Instances(context.Context, *dto.PageRequest) (dto.NameServicePage, error)
+	Roles(context.Context, *dto.PageRequest) (dto.NameDescriptionPage, error)
+	Groups(context.Context, *dto.PageRequest) (dto.NameDescriptionPage, error)
+	Accounts(context.Context, *dto.PageRequest) (dto.UserIdLoginStatePage, error)
+	ServiceDetails(context.Context, string) (dto.ServiceDetails, error)
+	InstanceDetails(context.Context, string) (dto.InstanceDetails, error)
+	RoleDetails(context.Context, *dto.NameService) (dto.RoleDetails, error)
+	GroupDetails(context.Context, *dto.NameService) (dto.GroupDetails, error)
+	AccountDetails(context.Context, uuid.UUID) (dto.AccountDetails, error)
+
+	ExportRBAC(context.Context) (dto.RBACDocument, error)
+	ImportRBAC(context.Context, *dto.RBACDocument, bool) (dto.RBACImportResult, error)
+	ReconcileRBAC(context.Context, *dto.RBACDocument, bool) (dto.RBACImportResult, error)
+
+	DeleteExpiredGrants(context.Context, time.Time) error
+	PurgeDeleted(context.Context, time.Time) error
 }
@@ -3,6 +3,7 @@ package redis
 import (
 	"fmt"
 	"github.com/google/uuid"
+	"strings"
 
 	loginVO "github.com/lazylex/watch-store/secure/internal/domain/value_objects/login"
 )
@@ -12,10 +13,16 @@ const (
 	prefixSessionByUUID                    = "si"
 	prefixServicePermissionsNumbers        = "spn"
 	prefixServicePermissionsNumbersForUser = "spn4u"
+	prefixServicePermissionEncoding        = "spe"
 	prefixInstancePermissionsNumbers       = "ipn"
 	prefixUuidHash                         = "uh"
 	prefixAccountState                     = "as"
 	prefixInstance                         = "i"
+	prefixResetToken                       = "rt"
+	prefixLoginChallenge                   = "lc"
+	prefixUsedTOTPStep                     = "tu"
+	prefixRevokedToken                     = "rj"
+	prefixAuthorizationCode                = "ac"
 )
 
 // keySession ключ для получения UUID пользователя сессии.
@@ -39,6 +46,11 @@ func keyServicePermissionsNumbers(service string) string {
 	return fmt.Sprintf("%s:%s", prefixServicePermissionsNumbers, service)
 }
 
+// keyServicePermissionEncoding ключ для получения способа кодирования номеров разрешений в токенах сервиса.
+func keyServicePermissionEncoding(service string) string {
+	return fmt.Sprintf("%s:%s", prefixServicePermissionEncoding, service)
+}
+
 // keyInstancePermissionsNumbers ключ для получения списка разрешений экземпляра для пользователя (сервиса) с UUID
 // равным id.
 func keyInstancePermissionsNumbers(instance string, id uuid.UUID) string {
@@ -59,3 +71,43 @@ func keyAccountStateByLogin(login loginVO.Login) string {
 func keyInstance(instance string) string {
 	return fmt.Sprintf("%s:%s", prefixInstance, instance)
 }
+
+// keyResetToken ключ для получения UUID пользователя по одноразовому токену сброса пароля.
+func keyResetToken(token string) string {
+	return fmt.Sprintf("%s:%s", prefixResetToken, token)
+}
+
+// keyLoginChallenge ключ для получения UUID пользователя по токену незавершенного входа, ожидающего второй фактор.
+func keyLoginChallenge(token string) string {
+	return fmt.Sprintf("%s:%s", prefixLoginChallenge, token)
+}
+
+// keyUsedTOTPStep ключ для отметки использованного шага времени одноразового пароля пользователя с UUID равным id.
+func keyUsedTOTPStep(id uuid.UUID, step uint64) string {
+	return fmt.Sprintf("%s:%s:%d", prefixUsedTOTPStep, id.String(), step)
+}
+
+// keyRevokedToken ключ для отметки отозванного JWT-токена по его идентификатору (jti).
+func keyRevokedToken(tokenId string) string {
+	return fmt.Sprintf("%s:%s", prefixRevokedToken, tokenId)
+}
+
+// keyAuthorizationCode ключ для получения данных запроса авторизации OpenID Connect по коду авторизации.
+func keyAuthorizationCode(code string) string {
+	return fmt.Sprintf("%s:%s", prefixAuthorizationCode, code)
+}
+
+// patternServicePermissionsNumbersForUsers шаблон ключей списков разрешений сервиса service для всех пользователей.
+func patternServicePermissionsNumbersForUsers(service string) string {
+	return fmt.Sprintf("%s:%s:*", prefixServicePermissionsNumbersForUser, escapePattern(service))
+}
+
+// patternInstancePermissionsNumbersForUsers шаблон ключей списков разрешений экземпляра instance для всех пользователей.
+func patternInstancePermissionsNumbersForUsers(instance string) string {
+	return fmt.Sprintf("%s:%s:*", prefixInstancePermissionsNumbers, escapePattern(instance))
+}
+
+// escapePattern экранирует специальные символы шаблона ключей redis.
+func escapePattern(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`, `]`, `\]`).Replace(value)
+}
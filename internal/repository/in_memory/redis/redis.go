@@ -2,12 +2,14 @@
 Package redis: пакет для взаимодействия с in memory хранилищем Redis. Функция MustCreate возвращает структуру,
 содержащую методы, удовлетворяющие интерфейсу in_memory.Interface и содержащую пул соединений с redis-сервером. При
 невозможности установить соединение, работа приложения останавливается. Для работы приложения в настройках redis Access
-Control List должны быть установлены разрешения на выполнение данным приложением операций SET, GET, HSET, HGETALL.
+Control List должны быть установлены разрешения на выполнение данным приложением операций SET, SETNX, GET, GETDEL, DEL,
+HSET, HGETALL, SCAN.
 */
 package redis
 
 import (
 	"context"
+	"encoding/json"
 	"github.com/google/uuid"
 	"github.com/lazylex/watch-store/secure/internal/config"
 	"github.com/lazylex/watch-store/secure/internal/domain/value_objects/account_state"
@@ -17,6 +19,7 @@ import (
 	"log/slog"
 	"os"
 	"strconv"
+	"time"
 )
 
 // Redis структура, содержащая указатель на пул соединений для работы с redis-сервером и конфигурацию времени жизни
@@ -29,6 +32,8 @@ type Redis struct {
 const (
 	userIdField = "user_id"
 	hashField   = "hash"
+
+	usedTOTPStepTTL = 2 * time.Minute // Время, в течение которого одноразовый пароль может быть принят повторно
 )
 
 // MustCreate создание структуры с клиентом для взаимодействия с Redis. При ошибке соединения с сервером Redis выводит
@@ -162,6 +167,100 @@ func (r *Redis) SetUserIdAndPasswordHash(ctx context.Context, data *dto.UserIdLo
 	r.client.Expire(ctx, key, r.ttl.UserIdAndPasswordHashTTL)
 }
 
+// DeleteUserIdAndPasswordHash удаляет из памяти идентификатор пользователя и хеш его пароля.
+func (r *Redis) DeleteUserIdAndPasswordHash(ctx context.Context, login loginVO.Login) error {
+	return adaptErr(r.client.Del(ctx, keyUserIdAndPasswordHash(login)).Err())
+}
+
+// SaveResetToken сохраняет одноразовый токен сброса пароля для пользователя (сервиса). Токен хранится переданное в TTL
+// время.
+func (r *Redis) SaveResetToken(ctx context.Context, data *dto.UserIdToken) error {
+	return adaptErr(r.client.Set(ctx, keyResetToken(data.Token), data.UserId.String(), r.ttl.ResetTokenTTL).Err())
+}
+
+// UserIdFromResetToken возвращает UUID пользователя по токену сброса пароля. Токен при этом удаляется из памяти, так
+// как является одноразовым.
+func (r *Redis) UserIdFromResetToken(ctx context.Context, token string) (uuid.UUID, error) {
+	var val string
+	var err error
+
+	if val, err = r.client.GetDel(ctx, keyResetToken(token)).Result(); err != nil {
+		return uuid.Nil, adaptErr(err)
+	}
+
+	parsedUUID, err := uuid.Parse(val)
+
+	return parsedUUID, adaptErr(err)
+}
+
+// RevokeToken отмечает JWT-токен с переданным идентификатором как отозванный. Отметка хранится до истечения срока
+// действия токена, переданного в TTL.
+func (r *Redis) RevokeToken(ctx context.Context, data *dto.TokenTTL) error {
+	return adaptErr(r.client.Set(ctx, keyRevokedToken(data.Token), 1, data.TTL).Err())
+}
+
+// IsTokenRevoked возвращает true, если JWT-токен с переданным идентификатором отозван.
+func (r *Redis) IsTokenRevoked(ctx context.Context, tokenId string) (bool, error) {
+	result, err := r.client.Exists(ctx, keyRevokedToken(tokenId)).Result()
+	return result > 0, adaptErr(err)
+}
+
+// SaveAuthorizationCode сохраняет код авторизации OpenID Connect вместе с данными запроса авторизации. Код хранится
+// переданное в TTL время.
+func (r *Redis) SaveAuthorizationCode(ctx context.Context, data *dto.AuthorizationCode) error {
+	value, err := json.Marshal(data)
+	if err != nil {
+		return adaptErr(err)
+	}
+
+	return adaptErr(r.client.Set(ctx, keyAuthorizationCode(data.Code), value, r.ttl.AuthorizationCodeTTL).Err())
+}
+
+// AuthorizationCode возвращает данные запроса авторизации по коду авторизации OpenID Connect. Код при этом удаляется
+// из памяти, так как является одноразовым.
+func (r *Redis) AuthorizationCode(ctx context.Context, code string) (dto.AuthorizationCode, error) {
+	var result dto.AuthorizationCode
+
+	value, err := r.client.GetDel(ctx, keyAuthorizationCode(code)).Bytes()
+	if err != nil {
+		return dto.AuthorizationCode{}, adaptErr(err)
+	}
+
+	if err = json.Unmarshal(value, &result); err != nil {
+		return dto.AuthorizationCode{}, adaptErr(err)
+	}
+
+	return result, nil
+}
+
+// SaveLoginChallenge сохраняет токен незавершенного входа, ожидающего подтверждения вторым фактором. Токен хранится
+// переданное в TTL время.
+func (r *Redis) SaveLoginChallenge(ctx context.Context, data *dto.UserIdToken) error {
+	return adaptErr(r.client.Set(ctx, keyLoginChallenge(data.Token), data.UserId.String(), r.ttl.LoginChallengeTTL).Err())
+}
+
+// UserIdFromLoginChallenge возвращает UUID пользователя по токену незавершенного входа. Токен при этом удаляется из
+// памяти, так как является одноразовым.
+func (r *Redis) UserIdFromLoginChallenge(ctx context.Context, token string) (uuid.UUID, error) {
+	var val string
+	var err error
+
+	if val, err = r.client.GetDel(ctx, keyLoginChallenge(token)).Result(); err != nil {
+		return uuid.Nil, adaptErr(err)
+	}
+
+	parsedUUID, err := uuid.Parse(val)
+
+	return parsedUUID, adaptErr(err)
+}
+
+// MarkTOTPStepUsed отмечает шаг времени одноразового пароля пользователя как использованный. Возвращает false, если
+// шаг уже был использован ранее.
+func (r *Redis) MarkTOTPStepUsed(ctx context.Context, id uuid.UUID, step uint64) (bool, error) {
+	result, err := r.client.SetNX(ctx, keyUsedTOTPStep(id, step), 1, usedTOTPStepTTL).Result()
+	return result, adaptErr(err)
+}
+
 // AccountStateByLogin возвращает состояние учетной записи с переданным логином.
 func (r *Redis) AccountStateByLogin(ctx context.Context, login loginVO.Login) (account_state.State, error) {
 	var numericVal int
@@ -332,6 +431,71 @@ func (r *Redis) ServiceNumberedPermissions(ctx context.Context, serviceName stri
 	return &result, nil
 }
 
+// SetServicePermissionEncoding сохраняет в памяти способ кодирования номеров разрешений в токенах сервиса.
+func (r *Redis) SetServicePermissionEncoding(ctx context.Context, data *dto.NameEncoding) error {
+	return adaptErr(r.client.Set(ctx, keyServicePermissionEncoding(data.Name), data.Encoding, r.ttl.InstanceDataTTL).Err())
+}
+
+// ServicePermissionEncoding возвращает из памяти способ кодирования номеров разрешений в токенах сервиса.
+func (r *Redis) ServicePermissionEncoding(ctx context.Context, serviceName string) (string, error) {
+	key := keyServicePermissionEncoding(serviceName)
+	result, err := r.client.Get(ctx, key).Result()
+	if err != nil {
+		return "", adaptErr(err)
+	}
+
+	defer r.client.Expire(ctx, key, r.ttl.InstanceDataTTL)
+
+	return result, nil
+}
+
+// DeleteServiceNumberedPermissions удаляет из памяти разрешения сервиса.
+func (r *Redis) DeleteServiceNumberedPermissions(ctx context.Context, serviceName string) error {
+	return adaptErr(r.client.Del(ctx, keyServicePermissionsNumbers(serviceName)).Err())
+}
+
+// DeleteServicePermissionsNumbers удаляет из памяти номера разрешений сервиса для всех аккаунтов.
+func (r *Redis) DeleteServicePermissionsNumbers(ctx context.Context, serviceName string) error {
+	return r.deleteByPattern(ctx, patternServicePermissionsNumbersForUsers(serviceName))
+}
+
+// DeleteServicePermissionEncoding удаляет из памяти способ кодирования номеров разрешений в токенах сервиса.
+func (r *Redis) DeleteServicePermissionEncoding(ctx context.Context, serviceName string) error {
+	return adaptErr(r.client.Del(ctx, keyServicePermissionEncoding(serviceName)).Err())
+}
+
+// DeleteInstancePermissionsNumbers удаляет из памяти номера разрешений экземпляра сервиса для всех аккаунтов.
+func (r *Redis) DeleteInstancePermissionsNumbers(ctx context.Context, instanceName string) error {
+	return r.deleteByPattern(ctx, patternInstancePermissionsNumbersForUsers(instanceName))
+}
+
+// DeleteServicePermissionsNumbersForAccount удаляет из памяти номера разрешений сервиса для аккаунта.
+func (r *Redis) DeleteServicePermissionsNumbersForAccount(ctx context.Context, data *dto.UserIdService) error {
+	return adaptErr(r.client.Del(ctx, keyServicePermissionsNumbersForUser(data.Service, data.UserId)).Err())
+}
+
+// DeleteInstancePermissionsNumbersForAccount удаляет из памяти номера разрешений экземпляра сервиса для аккаунта.
+func (r *Redis) DeleteInstancePermissionsNumbersForAccount(ctx context.Context, data *dto.UserIdInstance) error {
+	return adaptErr(r.client.Del(ctx, keyInstancePermissionsNumbers(data.Instance, data.UserId)).Err())
+}
+
+// DeleteInstance удаляет из памяти название сервиса и секретный ключ экземпляра.
+func (r *Redis) DeleteInstance(ctx context.Context, name string) error {
+	return adaptErr(r.client.Del(ctx, keyInstance(name)).Err())
+}
+
+// deleteByPattern удаляет все ключи, соответствующие шаблону.
+func (r *Redis) deleteByPattern(ctx context.Context, pattern string) error {
+	iterator := r.client.Scan(ctx, 0, pattern, 0).Iterator()
+	for iterator.Next(ctx) {
+		if err := r.client.Del(ctx, iterator.Val()).Err(); err != nil {
+			return adaptErr(err)
+		}
+	}
+
+	return adaptErr(iterator.Err())
+}
+
 // setPermissionsNumbers сохраняет номера разрешений аккаунта по заданному ключу.
 func (r *Redis) setPermissionsNumbers(ctx context.Context, key string, permissionNumbers []int) error {
 
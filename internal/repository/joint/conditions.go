@@ -0,0 +1,50 @@
+package joint
+
+import (
+	"context"
+	"github.com/lazylex/watch-store/secure/internal/dto"
+	"github.com/lazylex/watch-store/secure/internal/errors/joint"
+)
+
+// SetInstanceAttribute устанавливает значение атрибута экземпляра сервиса. От атрибутов зависят разрешения, назначенные
+// по селектору, поэтому закешированные номера разрешений всех учетных записей для экземпляра удаляются.
+func (r *Repository) SetInstanceAttribute(ctx context.Context, data *dto.InstanceNameValue) error {
+	if err := r.persistent.SetInstanceAttribute(ctx, data); err != nil {
+		return adaptErr(err)
+	}
+
+	return r.invalidateInstancePermissionsNumbers(ctx, data.Instance)
+}
+
+// DeleteInstanceAttribute удаляет атрибут экземпляра сервиса. Закешированные номера разрешений всех учетных записей
+// для экземпляра удаляются.
+func (r *Repository) DeleteInstanceAttribute(ctx context.Context, data *dto.NameInstance) error {
+	if err := r.persistent.DeleteInstanceAttribute(ctx, data); err != nil {
+		return adaptErr(err)
+	}
+
+	return r.invalidateInstancePermissionsNumbers(ctx, data.Instance)
+}
+
+// invalidateInstancePermissionsNumbers удаляет из памяти номера разрешений всех учетных записей для экземпляра сервиса.
+func (r *Repository) invalidateInstancePermissionsNumbers(ctx context.Context, instance string) error {
+	if err := r.memory.DeleteInstancePermissionsNumbers(ctx, instance); err != nil {
+		return adaptErr(joint.ErrCacheSavedData)
+	}
+
+	return nil
+}
+
+// InstanceAttributes возвращает атрибуты экземпляра сервиса. Атрибуты не кешируются и всегда читаются из постоянного
+// хранилища.
+func (r *Repository) InstanceAttributes(ctx context.Context, instance string) (map[string]string, error) {
+	attributes, err := r.persistent.InstanceAttributes(ctx, instance)
+	return attributes, adaptErr(err)
+}
+
+// ConditionalPermissionsForAccount возвращает номера разрешений учетной записи для экземпляра сервиса, полученные по
+// назначениям с условием, вместе с текстом условия. Результат зависит от атрибутов запроса, поэтому не кешируется.
+func (r *Repository) ConditionalPermissionsForAccount(ctx context.Context, data *dto.UserIdInstance) ([]dto.NumberCondition, error) {
+	permissions, err := r.persistent.ConditionalPermissionsForAccount(ctx, data)
+	return permissions, adaptErr(err)
+}
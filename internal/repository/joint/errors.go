@@ -49,6 +49,18 @@ func adaptErrSkipFrames(err interface{}, skip int) error {
 		return joint.ErrDuplicateData.WithOrigin(origin)
 	case message == in_memory.ErrNotNumericValue.Message:
 		return joint.ErrDataTypeConversion.WithOrigin(origin)
+	case message == persistent.ErrRetiredNumber.Message:
+		return joint.ErrRetiredNumber.WithOrigin(origin)
+	case message == persistent.ErrDeprecatedPermission.Message:
+		return joint.ErrDeprecatedPermission.WithOrigin(origin)
+	case message == persistent.ErrRoleCycle.Message:
+		return joint.ErrRoleCycle.WithOrigin(origin)
+	case message == persistent.ErrGroupCycle.Message:
+		return joint.ErrGroupCycle.WithOrigin(origin)
+	case message == persistent.ErrInvalidSelector.Message:
+		return joint.ErrInvalidSelector.WithOrigin(origin)
+	case message == persistent.ErrDeletedEntity.Message:
+		return joint.ErrDeletedEntity.WithOrigin(origin)
 	}
 
 	return joint.FullJointError(message, origin, nil)
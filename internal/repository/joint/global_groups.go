@@ -0,0 +1,79 @@
+package joint
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/lazylex/watch-store/secure/internal/dto"
+)
+
+// CreateGlobalGroup добавляет в БД глобальную группу.
+func (r *Repository) CreateGlobalGroup(ctx context.Context, data *dto.NameDescription) error {
+	return adaptErr(r.persistent.CreateGlobalGroup(ctx, data))
+}
+
+// AssignRoleToGlobalGroup присоединяет к глобальной группе роль сервиса. Закешированные номера разрешений этого сервиса
+// учетных записей глобальной группы обновляются.
+func (r *Repository) AssignRoleToGlobalGroup(ctx context.Context, data *dto.GlobalGroupRoleService) error {
+	var err error
+	if err = r.persistent.AssignRoleToGlobalGroup(ctx, data); err == nil {
+		go func() {
+			ctx := context.Background()
+			if accounts, err := r.persistent.GlobalGroupAccounts(ctx, data.GlobalGroup); err == nil {
+				r.refreshAccountsPermissions(ctx, accounts, []string{data.Service})
+			}
+		}()
+	}
+	return adaptErr(err)
+}
+
+// AssignGlobalGroupToAccount назначает глобальную группу учетной записи. Закешированные номера разрешений учетной
+// записи для всех сервисов, роли которых входят в глобальную группу, обновляются.
+func (r *Repository) AssignGlobalGroupToAccount(ctx context.Context, data *dto.UserIdGlobalGroup) error {
+	var err error
+	if err = r.persistent.AssignGlobalGroupToAccount(ctx, data); err == nil {
+		go func() {
+			ctx := context.Background()
+			if services, err := r.persistent.GlobalGroupServices(ctx, data.GlobalGroup); err == nil {
+				r.refreshAccountsPermissions(ctx, []uuid.UUID{data.UserId}, services)
+			}
+		}()
+	}
+	return adaptErr(err)
+}
+
+// DeleteGlobalGroup удаляет глобальную группу из БД. Учетные записи группы и сервисы её ролей считываются до удаления,
+// после чего закешированные номера разрешений этих учетных записей для этих сервисов обновляются.
+func (r *Repository) DeleteGlobalGroup(ctx context.Context, name string) error {
+	var (
+		err      error
+		accounts []uuid.UUID
+		services []string
+	)
+
+	if accounts, err = r.persistent.GlobalGroupAccounts(ctx, name); err != nil {
+		return adaptErr(err)
+	}
+
+	if services, err = r.persistent.GlobalGroupServices(ctx, name); err != nil {
+		return adaptErr(err)
+	}
+
+	if err = r.persistent.DeleteGlobalGroup(ctx, name); err == nil {
+		go r.refreshAccountsPermissions(context.Background(), accounts, services)
+	}
+
+	return adaptErr(err)
+}
+
+// refreshAccountsPermissions обновляет кеш разрешений учетных записей для каждого из переданных сервисов. Обновляются
+// только закешированные ранее разрешения.
+func (r *Repository) refreshAccountsPermissions(ctx context.Context, accounts []uuid.UUID, services []string) {
+	for _, id := range accounts {
+		for _, service := range services {
+			userIdService := dto.UserIdService{UserId: id, Service: service}
+			if r.memory.ExistServicePermissionsNumbersForAccount(ctx, &userIdService) {
+				r.refreshAccountPermissions(ctx, &userIdService)
+			}
+		}
+	}
+}
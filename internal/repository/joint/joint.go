@@ -8,6 +8,7 @@ package joint
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/google/uuid"
 	"github.com/lazylex/watch-store/secure/internal/domain/value_objects/account_state"
@@ -18,6 +19,7 @@ import (
 	"github.com/lazylex/watch-store/secure/internal/ports/repository/persistent"
 	"log/slog"
 	"os"
+	"sync"
 	"time"
 )
 
@@ -41,6 +43,21 @@ func MustCreate(memoryRepo in_memory.Interface, persistentRepo persistent.Interf
 	return r
 }
 
+// MustCreateWithoutCaching возвращает структуру объединенного хранилища, не кешируя данные в фоне. Предназначена для
+// утилит, выполняющих одну операцию. Если какое-либо из хранилищ равно nil, работа приложения завершается.
+func MustCreateWithoutCaching(memoryRepo in_memory.Interface, persistentRepo persistent.Interface) Repository {
+	if memoryRepo == nil || persistentRepo == nil {
+		slog.Error(adaptErr(joint.ErrNilRepo).Error())
+		os.Exit(1)
+	}
+	return Repository{memory: memoryRepo, persistent: persistentRepo, stateLocker: CreateStateLocker()}
+}
+
+// WarmCache считывает в память все данные, которые возможно кешировать, дожидаясь окончания кеширования.
+func (r *Repository) WarmCache() {
+	r.makeDataCache()
+}
+
 // SaveSession сохраняет в памяти данные сессии.
 func (r *Repository) SaveSession(ctx context.Context, dto *dto.UserIdToken) error {
 	return adaptErr(r.memory.SaveSession(ctx, dto))
@@ -75,6 +92,25 @@ func (r *Repository) SetAccountLoginData(ctx context.Context, data *dto.UserIdLo
 	return nil
 }
 
+// CreateAccount сохраняет в постоянном хранилище данные для входа и метаданные новой учетной записи одним запросом. В
+// памяти по возможности кеширует статус учетной записи, идентификатор пользователя и хеш пароля.
+func (r *Repository) CreateAccount(ctx context.Context, data *dto.UserIdLoginHashState,
+	metadata *dto.UserIdAccountMetadata) error {
+	var err error
+
+	if err = r.persistent.CreateAccount(ctx, data, metadata); err != nil {
+		return adaptErr(err)
+	}
+
+	if err = r.memory.SetAccountState(ctx, &dto.LoginState{Login: data.Login, State: data.State}); err != nil {
+		return adaptErr(err)
+	}
+
+	r.memory.SetUserIdAndPasswordHash(ctx,
+		&dto.UserIdLoginHash{UserId: data.UserId, Hash: data.Hash, Login: data.Login})
+	return nil
+}
+
 // UserIdAndPasswordHash возвращает идентификатор пользователя и хеш его пароля.
 func (r *Repository) UserIdAndPasswordHash(ctx context.Context, login loginVO.Login) (dto.UserIdHash, error) {
 	idAndHash, err := r.memory.UserIdAndPasswordHash(ctx, login)
@@ -155,13 +191,134 @@ func (r *Repository) AccountLoginData(ctx context.Context, login loginVO.Login)
 	return loginData, nil
 }
 
+// AccountLoginDataByUserId возвращает данные учетной записи по идентификатору пользователя.
+func (r *Repository) AccountLoginDataByUserId(ctx context.Context, id uuid.UUID) (dto.UserIdLoginHashState, error) {
+	data, err := r.persistent.AccountLoginDataByUserId(ctx, id)
+	return data, adaptErr(err)
+}
+
+// SetAccountPasswordHash сохраняет в постоянном хранилище новый хеш пароля учетной записи и удаляет из памяти
+// устаревшие идентификатор пользователя и хеш пароля.
+func (r *Repository) SetAccountPasswordHash(ctx context.Context, data *dto.UserIdHash) error {
+	var loginData dto.UserIdLoginHashState
+	var err error
+
+	if loginData, err = r.persistent.AccountLoginDataByUserId(ctx, data.UserId); err != nil {
+		return adaptErr(err)
+	}
+
+	if err = r.persistent.SetAccountPasswordHash(ctx, data); err != nil {
+		return adaptErr(err)
+	}
+
+	return adaptErr(r.memory.DeleteUserIdAndPasswordHash(ctx, loginData.Login))
+}
+
+// SetAccountMetadata сохраняет в постоянном хранилище метаданные учетной записи.
+func (r *Repository) SetAccountMetadata(ctx context.Context, data *dto.UserIdAccountMetadata) error {
+	return adaptErr(r.persistent.SetAccountMetadata(ctx, data))
+}
+
+// UpdateAccountLastLogin сохраняет в постоянном хранилище текущее время как время последнего входа в учетную запись.
+func (r *Repository) UpdateAccountLastLogin(ctx context.Context, id uuid.UUID) error {
+	return adaptErr(r.persistent.UpdateAccountLastLogin(ctx, id))
+}
+
+// SaveResetToken сохраняет в памяти одноразовый токен сброса пароля.
+func (r *Repository) SaveResetToken(ctx context.Context, data *dto.UserIdToken) error {
+	return adaptErr(r.memory.SaveResetToken(ctx, data))
+}
+
+// UserIdFromResetToken возвращает UUID пользователя по одноразовому токену сброса пароля.
+func (r *Repository) UserIdFromResetToken(ctx context.Context, token string) (uuid.UUID, error) {
+	id, err := r.memory.UserIdFromResetToken(ctx, token)
+	return id, adaptErr(err)
+}
+
+// SaveLoginChallenge сохраняет в памяти токен незавершенного входа, ожидающего подтверждения вторым фактором.
+func (r *Repository) SaveLoginChallenge(ctx context.Context, data *dto.UserIdToken) error {
+	return adaptErr(r.memory.SaveLoginChallenge(ctx, data))
+}
+
+// UserIdFromLoginChallenge возвращает UUID пользователя по одноразовому токену незавершенного входа.
+func (r *Repository) UserIdFromLoginChallenge(ctx context.Context, token string) (uuid.UUID, error) {
+	id, err := r.memory.UserIdFromLoginChallenge(ctx, token)
+	return id, adaptErr(err)
+}
+
+// MarkTOTPStepUsed отмечает шаг времени одноразового пароля как использованный. Возвращает false при повторном
+// использовании.
+func (r *Repository) MarkTOTPStepUsed(ctx context.Context, id uuid.UUID, step uint64) (bool, error) {
+	fresh, err := r.memory.MarkTOTPStepUsed(ctx, id, step)
+	return fresh, adaptErr(err)
+}
+
+// RevokeToken отмечает в памяти JWT-токен как отозванный до истечения срока его действия.
+func (r *Repository) RevokeToken(ctx context.Context, data *dto.TokenTTL) error {
+	return adaptErr(r.memory.RevokeToken(ctx, data))
+}
+
+// IsTokenRevoked возвращает true, если JWT-токен с переданным идентификатором отозван.
+func (r *Repository) IsTokenRevoked(ctx context.Context, tokenId string) (bool, error) {
+	revoked, err := r.memory.IsTokenRevoked(ctx, tokenId)
+	return revoked, adaptErr(err)
+}
+
+// CreateOIDCClient сохраняет клиента OpenID Connect в постоянном хранилище.
+func (r *Repository) CreateOIDCClient(ctx context.Context, data *dto.OIDCClient) error {
+	return adaptErr(r.persistent.CreateOIDCClient(ctx, data))
+}
+
+// OIDCClient возвращает клиента OpenID Connect из постоянного хранилища.
+func (r *Repository) OIDCClient(ctx context.Context, clientId string) (dto.OIDCClient, error) {
+	client, err := r.persistent.OIDCClient(ctx, clientId)
+	return client, adaptErr(err)
+}
+
+// SaveAuthorizationCode сохраняет в памяти одноразовый код авторизации OpenID Connect.
+func (r *Repository) SaveAuthorizationCode(ctx context.Context, data *dto.AuthorizationCode) error {
+	return adaptErr(r.memory.SaveAuthorizationCode(ctx, data))
+}
+
+// AuthorizationCode возвращает и удаляет из памяти данные запроса авторизации по одноразовому коду.
+func (r *Repository) AuthorizationCode(ctx context.Context, code string) (dto.AuthorizationCode, error) {
+	data, err := r.memory.AuthorizationCode(ctx, code)
+	return data, adaptErr(err)
+}
+
+// SetTOTPSecret сохраняет неподтвержденный секрет одноразовых паролей учетной записи.
+func (r *Repository) SetTOTPSecret(ctx context.Context, data *dto.UserIdSecret) error {
+	return adaptErr(r.persistent.SetTOTPSecret(ctx, data))
+}
+
+// TOTPSecret возвращает секрет одноразовых паролей учетной записи и признак его подтверждения.
+func (r *Repository) TOTPSecret(ctx context.Context, id uuid.UUID) (dto.SecretEnabled, error) {
+	data, err := r.persistent.TOTPSecret(ctx, id)
+	return data, adaptErr(err)
+}
+
+// EnableTOTP подтверждает секрет одноразовых паролей и сохраняет хеши кодов восстановления.
+func (r *Repository) EnableTOTP(ctx context.Context, data *dto.UserIdCodes) error {
+	return adaptErr(r.persistent.EnableTOTP(ctx, data))
+}
+
+// DeleteTOTP отключает двухфакторную аутентификацию учетной записи.
+func (r *Repository) DeleteTOTP(ctx context.Context, id uuid.UUID) error {
+	return adaptErr(r.persistent.DeleteTOTP(ctx, id))
+}
+
+// UseRecoveryCode удаляет использованный код восстановления.
+func (r *Repository) UseRecoveryCode(ctx context.Context, data *dto.UserIdCode) error {
+	return adaptErr(r.persistent.UseRecoveryCode(ctx, data))
+}
+
 // CreateService добавляет сервис в БД.
 func (r *Repository) CreateService(ctx context.Context, data *dto.NameDescription) error {
 	return adaptErr(r.persistent.CreateService(ctx, data))
 }
 
 // CreatePermission добавляет разрешение в БД.
-func (r *Repository) CreatePermission(ctx context.Context, data *dto.NameServiceDescription) error {
+func (r *Repository) CreatePermission(ctx context.Context, data *dto.NameNumberDescriptionService) error {
 	return adaptErr(r.persistent.CreatePermission(ctx, data))
 }
 
@@ -188,9 +345,16 @@ func (r *Repository) CreateOrUpdateInstance(ctx context.Context, data *dto.NameS
 	return nil
 }
 
-// AssignRoleToGroup присоединяет роль к группе.
+// AssignRoleToGroup присоединяет роль к группе. Закешированные номера разрешений учетных записей группы, в том числе
+// входящих в неё через вложенные группы, обновляются.
 func (r *Repository) AssignRoleToGroup(ctx context.Context, data *dto.GroupRoleService) error {
-	return adaptErr(r.persistent.AssignRoleToGroup(ctx, data))
+	var err error
+	if err = r.persistent.AssignRoleToGroup(ctx, data); err == nil {
+		go func() {
+			r.refreshGroupAccountsPermissions(context.Background(), &dto.NameService{Name: data.Group, Service: data.Service})
+		}()
+	}
+	return adaptErr(err)
 }
 
 // AssignRoleToAccount назначает роль учетной записи.
@@ -231,6 +395,12 @@ func (r *Repository) AssignInstancePermissionToAccount(ctx context.Context, data
 		return adaptErr(err)
 	}
 
+	// Назначение, которое ещё не вступило в силу, попадет в кеш при очистке истекших назначений. Назначение с условием
+	// не кешируется.
+	if data.ValidFrom != nil && data.ValidFrom.After(time.Now()) || len(data.Condition) > 0 {
+		return nil
+	}
+
 	if number, err = r.persistent.PermissionNumber(ctx, data.Permission, data.Instance); err != nil {
 		return adaptErr(joint.ErrCacheSavedData)
 	}
@@ -246,14 +416,72 @@ func (r *Repository) AssignInstancePermissionToAccount(ctx context.Context, data
 	return nil
 }
 
-// AssignPermissionToRole назначает роли разрешение.
+// AssignInstanceRoleToAccount назначает учетной записи роль, действующую только в экземпляре сервиса. Закешированные
+// номера разрешений учетной записи для экземпляра обновляются.
+func (r *Repository) AssignInstanceRoleToAccount(ctx context.Context, data *dto.UserIdInstanceRole) error {
+	if err := r.persistent.AssignInstanceRoleToAccount(ctx, data); err != nil {
+		return adaptErr(err)
+	}
+
+	r.refreshAccountInstancePermissions(ctx, &dto.UserIdInstance{UserId: data.UserId, Instance: data.Instance})
+
+	return nil
+}
+
+// AssignInstanceGroupToAccount включает учетную запись в группу только в экземпляре сервиса. Закешированные номера
+// разрешений учетной записи для экземпляра обновляются.
+func (r *Repository) AssignInstanceGroupToAccount(ctx context.Context, data *dto.UserIdInstanceGroup) error {
+	if err := r.persistent.AssignInstanceGroupToAccount(ctx, data); err != nil {
+		return adaptErr(err)
+	}
+
+	r.refreshAccountInstancePermissions(ctx, &dto.UserIdInstance{UserId: data.UserId, Instance: data.Instance})
+
+	return nil
+}
+
+// AssignPermissionToRole назначает роли разрешение. Разрешение получают и учетные записи с ролями, наследующими эту
+// роль, поэтому закешированные номера разрешений сервиса удаляются для всех учетных записей.
 func (r *Repository) AssignPermissionToRole(ctx context.Context, data *dto.PermissionRoleService) error {
-	return adaptErr(r.persistent.AssignPermissionToRole(ctx, data))
+	if err := r.persistent.AssignPermissionToRole(ctx, data); err != nil {
+		return adaptErr(err)
+	}
+
+	return r.invalidateServicePermissionsNumbers(ctx, data.Service)
+}
+
+// AssignParentToRole назначает роли родительскую роль. Разрешения родительской роли наследуют все роли, наследующие
+// роль, поэтому закешированные номера разрешений сервиса удаляются для всех учетных записей.
+func (r *Repository) AssignParentToRole(ctx context.Context, data *dto.RoleParentService) error {
+	if err := r.persistent.AssignParentToRole(ctx, data); err != nil {
+		return adaptErr(err)
+	}
+
+	return r.invalidateServicePermissionsNumbers(ctx, data.Service)
 }
 
-// AssignPermissionToGroup назначает разрешения группе.
+// AssignPermissionToGroup назначает разрешения группе. Закешированные номера разрешений учетных записей группы, в том
+// числе входящих в неё через вложенные группы, обновляются.
 func (r *Repository) AssignPermissionToGroup(ctx context.Context, data *dto.GroupPermissionService) error {
-	return adaptErr(r.persistent.AssignPermissionToGroup(ctx, data))
+	var err error
+	if err = r.persistent.AssignPermissionToGroup(ctx, data); err == nil {
+		go func() {
+			r.refreshGroupAccountsPermissions(context.Background(), &dto.NameService{Name: data.Group, Service: data.Service})
+		}()
+	}
+	return adaptErr(err)
+}
+
+// AssignSubgroupToGroup вкладывает в группу другую группу. Учетные записи вложенной группы получают роли и разрешения
+// объемлющей группы, поэтому их закешированные номера разрешений обновляются.
+func (r *Repository) AssignSubgroupToGroup(ctx context.Context, data *dto.GroupSubgroupService) error {
+	var err error
+	if err = r.persistent.AssignSubgroupToGroup(ctx, data); err == nil {
+		go func() {
+			r.refreshGroupAccountsPermissions(context.Background(), &dto.NameService{Name: data.Subgroup, Service: data.Service})
+		}()
+	}
+	return adaptErr(err)
 }
 
 // ServicePermissionsForAccount возвращает название, номер и описание разрешений аккаунта для сервиса.
@@ -274,6 +502,20 @@ func (r *Repository) ServicePermissionsNumbersForAccount(ctx context.Context, da
 	return numbers, adaptErr(err)
 }
 
+// ServicePermissionsSourcesForAccount возвращает номера разрешений аккаунта для сервиса вместе с путями, по которым они
+// назначены. Данные не кешируются и всегда читаются из постоянного хранилища.
+func (r *Repository) ServicePermissionsSourcesForAccount(ctx context.Context, data *dto.UserIdService) ([]dto.NumberSource, error) {
+	sources, err := r.persistent.ServicePermissionsSourcesForAccount(ctx, data)
+	return sources, adaptErr(err)
+}
+
+// PermissionsGrantPathsForAccount возвращает разрешения аккаунта для сервиса и экземпляра вместе с названиями групп и
+// ролей, через которые они назначены. Данные не кешируются и всегда читаются из постоянного хранилища.
+func (r *Repository) PermissionsGrantPathsForAccount(ctx context.Context, data *dto.UserIdServiceInstance) ([]dto.NumberNameGrantPath, error) {
+	paths, err := r.persistent.PermissionsGrantPathsForAccount(ctx, data)
+	return paths, adaptErr(err)
+}
+
 // ServiceNumberedPermissions возвращает номера и названия разрешений сервиса.
 func (r *Repository) ServiceNumberedPermissions(ctx context.Context, serviceName string) (*[]dto.NameNumber, error) {
 	var err error
@@ -345,6 +587,227 @@ func (r *Repository) ServiceName(ctx context.Context, instanceName string) (stri
 	return name, nil
 }
 
+// ServicePermissionEncoding возвращает способ кодирования номеров разрешений в токенах сервиса. Сначала поиск
+// производится в памяти, затем в постоянном хранилище с последующим кешированием.
+func (r *Repository) ServicePermissionEncoding(ctx context.Context, serviceName string) (string, error) {
+	var encoding string
+	var err error
+
+	if encoding, err = r.memory.ServicePermissionEncoding(ctx, serviceName); err == nil {
+		return encoding, nil
+	}
+
+	if encoding, err = r.persistent.ServicePermissionEncoding(ctx, serviceName); err != nil {
+		return "", adaptErr(err)
+	}
+
+	defer func() {
+		_ = r.memory.SetServicePermissionEncoding(context.Background(), &dto.NameEncoding{Name: serviceName, Encoding: encoding})
+	}()
+
+	return encoding, nil
+}
+
+// SetServicePermissionEncoding сохраняет способ кодирования номеров разрешений в токенах сервиса в постоянное
+// хранилище и в память.
+func (r *Repository) SetServicePermissionEncoding(ctx context.Context, data *dto.NameEncoding) error {
+	if err := r.persistent.SetServicePermissionEncoding(ctx, data); err != nil {
+		return adaptErr(err)
+	}
+
+	if err := r.memory.SetServicePermissionEncoding(ctx, data); err != nil {
+		return adaptErr(joint.ErrCacheSavedData)
+	}
+
+	return nil
+}
+
+// Services возвращает страницу сервисов из постоянного хранилища.
+func (r *Repository) Services(ctx context.Context, data *dto.ListQuery) ([]dto.NameDescription, error) {
+	result, err := r.persistent.Services(ctx, data)
+	return result, adaptErr(err)
+}
+
+// Instances возвращает страницу экземпляров сервисов из постоянного хранилища.
+func (r *Repository) Instances(ctx context.Context, data *dto.ListQuery) ([]dto.NameService, error) {
+	result, err := r.persistent.Instances(ctx, data)
+	return result, adaptErr(err)
+}
+
+// Roles возвращает страницу ролей сервиса из постоянного хранилища.
+func (r *Repository) Roles(ctx context.Context, data *dto.ListQuery) ([]dto.NameDescription, error) {
+	result, err := r.persistent.Roles(ctx, data)
+	return result, adaptErr(err)
+}
+
+// Groups возвращает страницу групп сервиса из постоянного хранилища.
+func (r *Repository) Groups(ctx context.Context, data *dto.ListQuery) ([]dto.NameDescription, error) {
+	result, err := r.persistent.Groups(ctx, data)
+	return result, adaptErr(err)
+}
+
+// Accounts возвращает страницу учетных записей из постоянного хранилища.
+func (r *Repository) Accounts(ctx context.Context, data *dto.ListQuery) ([]dto.UserIdLoginState, error) {
+	result, err := r.persistent.Accounts(ctx, data)
+	return result, adaptErr(err)
+}
+
+// ServiceDetails возвращает подробные данные сервиса из постоянного хранилища.
+func (r *Repository) ServiceDetails(ctx context.Context, name string) (dto.ServiceDetails, error) {
+	result, err := r.persistent.ServiceDetails(ctx, name)
+	return result, adaptErr(err)
+}
+
+// RoleDetails возвращает подробные данные роли из постоянного хранилища.
+func (r *Repository) RoleDetails(ctx context.Context, data *dto.NameService) (dto.RoleDetails, error) {
+	result, err := r.persistent.RoleDetails(ctx, data)
+	return result, adaptErr(err)
+}
+
+// GroupDetails возвращает подробные данные группы из постоянного хранилища.
+func (r *Repository) GroupDetails(ctx context.Context, data *dto.NameService) (dto.GroupDetails, error) {
+	result, err := r.persistent.GroupDetails(ctx, data)
+	return result, adaptErr(err)
+}
+
+// AccountDetails возвращает подробные данные учетной записи из постоянного хранилища.
+func (r *Repository) AccountDetails(ctx context.Context, id uuid.UUID) (dto.AccountDetails, error) {
+	result, err := r.persistent.AccountDetails(ctx, id)
+	return result, adaptErr(err)
+}
+
+// GlobalGroups возвращает глобальные группы с их ролями из постоянного хранилища.
+func (r *Repository) GlobalGroups(ctx context.Context) ([]dto.RBACGlobalGroup, error) {
+	result, err := r.persistent.GlobalGroups(ctx)
+	return result, adaptErr(err)
+}
+
+// ImportRBAC удаляет перечисленные в removals данные (если removals не равно nil) и применяет документ с конфигурацией
+// управления доступом к постоянному хранилищу в одной транзакции, после чего удаляет из памяти устаревшие данные
+// затронутых удалением сервисов и экземпляров и сохраняет в памяти новые экземпляры сервисов. У учетных записей из
+// документа и участников затронутых глобальных групп удаляются из памяти все номера разрешений, а у учетных записей,
+// состояние которых изменилось, обновляются данные для входа и завершается сессия, если учетная запись больше не
+// активна.
+func (r *Repository) ImportRBAC(ctx context.Context, data *dto.RBACDocument, instanceSecrets map[string]string, removals *dto.RBACRemovals) error {
+	var stale staleCache
+	if removals != nil {
+		stale = r.staleCache(ctx, removals)
+	}
+
+	// Участники глобальных групп считываются до импорта, так как удаляемые группы после него не найти.
+	groups := make([]string, 0, len(data.GlobalGroups))
+	for _, group := range data.GlobalGroups {
+		groups = append(groups, group.Name)
+	}
+	if removals != nil {
+		groups = append(groups, removals.GlobalGroups...)
+	}
+	members := r.globalGroupsAccounts(ctx, groups)
+
+	states := make(map[uuid.UUID]account_state.State, len(data.Accounts))
+	for _, account := range data.Accounts {
+		if loginData, err := r.persistent.AccountLoginDataByUserId(ctx, account.UserId); err == nil {
+			states[account.UserId] = loginData.State
+		}
+	}
+
+	if err := r.persistent.ImportRBAC(ctx, data, instanceSecrets, removals); err != nil {
+		return adaptErr(err)
+	}
+
+	stale.invalidate(ctx, r.memory)
+
+	for _, service := range data.Services {
+		if err := r.memory.DeleteServicePermissionEncoding(ctx, service.Name); err != nil {
+			return adaptErr(joint.ErrCacheSavedData)
+		}
+
+		for _, instance := range service.Instances {
+			if secret, ok := instanceSecrets[instance]; ok {
+				if err := r.memory.SetInstanceServiceAndSecret(ctx, &dto.NameServiceSecret{
+					Name:    instance,
+					Service: service.Name,
+					Secret:  secret,
+				}); err != nil {
+					return adaptErr(joint.ErrCacheSavedData)
+				}
+			}
+		}
+	}
+
+	ids := members
+	for _, account := range data.Accounts {
+		ids = append(ids, account.UserId)
+
+		if state, ok := states[account.UserId]; !ok || state != account.State {
+			r.refreshAccountLoginData(ctx, account.UserId)
+		}
+	}
+
+	r.invalidateAccountsPermissionsNumbers(ctx, ids...)
+
+	return nil
+}
+
+// globalGroupsAccounts возвращает идентификаторы учетных записей, входящих в глобальные группы names. Группы, учетные
+// записи которых не удалось считать, пропускаются.
+func (r *Repository) globalGroupsAccounts(ctx context.Context, names []string) []uuid.UUID {
+	result := make([]uuid.UUID, 0)
+	for _, name := range names {
+		if accounts, err := r.persistent.GlobalGroupAccounts(ctx, name); err == nil {
+			result = append(result, accounts...)
+		}
+	}
+
+	return result
+}
+
+// refreshAccountLoginData заменяет в памяти данные для входа учетной записи прочитанными из постоянного хранилища и
+// завершает её сессию, если учетная запись не активна.
+func (r *Repository) refreshAccountLoginData(ctx context.Context, id uuid.UUID) {
+	loginData, err := r.persistent.AccountLoginDataByUserId(ctx, id)
+	if err != nil {
+		return
+	}
+
+	defer r.stateLocker.Unlock(loginData.Login)
+	r.stateLocker.Lock(loginData.Login)
+
+	if loginData.State != account_state.Enabled {
+		_ = r.memory.DeleteSession(ctx, id)
+	}
+
+	_ = r.saveToMemoryLoginData(ctx, &loginData)
+}
+
+// invalidateAccountsPermissionsNumbers удаляет из памяти номера разрешений учетных записей ids для всех сервисов и их
+// экземпляров. Номера будут заново прочитаны из постоянного хранилища при следующем обращении.
+func (r *Repository) invalidateAccountsPermissionsNumbers(ctx context.Context, ids ...uuid.UUID) {
+	if len(ids) == 0 {
+		return
+	}
+
+	services, err := r.persistent.ServicesNames(ctx)
+	if err != nil {
+		return
+	}
+
+	for _, service := range services {
+		var instances []string
+		if details, err := r.persistent.ServiceDetails(ctx, service); err == nil {
+			instances = details.Instances
+		}
+
+		for _, id := range ids {
+			_ = r.memory.DeleteServicePermissionsNumbersForAccount(ctx, &dto.UserIdService{UserId: id, Service: service})
+
+			for _, instance := range instances {
+				_ = r.memory.DeleteInstancePermissionsNumbersForAccount(ctx, &dto.UserIdInstance{UserId: id, Instance: instance})
+			}
+		}
+	}
+}
+
 // ServicesNames возвращает список названий всех сервисов.
 func (r *Repository) ServicesNames(ctx context.Context) ([]string, error) {
 	result, err := r.persistent.ServicesNames(ctx)
@@ -371,19 +834,15 @@ func (r *Repository) InstanceSecret(ctx context.Context, name string) (string, e
 	return secret, err
 }
 
-// DeleteRole удаляет роль из БД.
-func (r *Repository) DeleteRole(ctx context.Context, data *dto.NameService) error {
-	return adaptErr(r.persistent.DeleteRole(ctx, data))
+// AccountHasRole возвращает true, если учетной записи назначена роль напрямую или через группу.
+func (r *Repository) AccountHasRole(ctx context.Context, data *dto.UserIdRoleService) (bool, error) {
+	exist, err := r.persistent.AccountHasRole(ctx, data)
+	return exist, adaptErr(err)
 }
 
-// DeleteGroup удаляет группу из БД.
-func (r *Repository) DeleteGroup(ctx context.Context, data *dto.NameService) error {
-	return adaptErr(r.persistent.DeleteGroup(ctx, data))
-}
-
-// DeletePermission удаляет разрешение из БД.
-func (r *Repository) DeletePermission(ctx context.Context, data *dto.NameService) error {
-	return adaptErr(r.persistent.DeletePermission(ctx, data))
+// DeprecatePermission помечает разрешение в БД устаревшим.
+func (r *Repository) DeprecatePermission(ctx context.Context, data *dto.NameService) error {
+	return adaptErr(r.persistent.DeprecatePermission(ctx, data))
 }
 
 // instancePermissionsNumbersForAccountFromPersistentWithSaveToMemory возвращает номера разрешений аккаунта для
@@ -411,9 +870,71 @@ func (r *Repository) saveToMemoryLoginData(ctx context.Context, data *dto.UserId
 	return adaptErr(r.memory.SetAccountState(ctx, &dto.LoginState{Login: data.Login, State: data.State}))
 }
 
-// refreshAccountPermissions обновляет кеш разрешений
+// AccountGrantsExpiration возвращает наиболее раннее время окончания назначений учетной записи, от которых зависят
+// разрешения в её токене для экземпляра, или нулевое время, если все назначения бессрочны. Данные не кешируются.
+func (r *Repository) AccountGrantsExpiration(ctx context.Context, data *dto.UserIdInstance) (time.Time, error) {
+	until, err := r.persistent.AccountGrantsExpiration(ctx, data)
+	return until, adaptErr(err)
+}
+
+// DeleteExpiredGrants удаляет истекшие назначения учетным записям и обновляет в памяти закешированные номера
+// разрешений учетных записей, назначения которых истекли или вступили в силу после since.
+func (r *Repository) DeleteExpiredGrants(ctx context.Context, since time.Time) error {
+	changed, err := r.persistent.DeleteExpiredGrants(ctx, since)
+	if err != nil {
+		return adaptErr(err)
+	}
+
+	for _, item := range changed {
+		if len(item.Service) > 0 {
+			userIdService := dto.UserIdService{UserId: item.UserId, Service: item.Service}
+			if r.memory.ExistServicePermissionsNumbersForAccount(ctx, &userIdService) {
+				r.refreshAccountPermissions(ctx, &userIdService)
+			}
+			continue
+		}
+
+		userIdInstance := dto.UserIdInstance{UserId: item.UserId, Instance: item.Instance}
+		if r.memory.ExistInstancePermissionsNumbersForAccount(ctx, &userIdInstance) {
+			r.refreshAccountInstancePermissions(ctx, &userIdInstance)
+		}
+	}
+
+	return nil
+}
+
+// invalidateServicePermissionsNumbers удаляет из памяти номера разрешений сервиса для всех учетных записей. При
+// следующем запросе они будут считаны из постоянного хранилища.
+func (r *Repository) invalidateServicePermissionsNumbers(ctx context.Context, service string) error {
+	if err := r.memory.DeleteServicePermissionsNumbers(ctx, service); err != nil {
+		return adaptErr(joint.ErrCacheSavedData)
+	}
+
+	return r.invalidateServiceInstancesPermissionsNumbers(ctx, service)
+}
+
+// invalidateServiceInstancesPermissionsNumbers удаляет из памяти номера разрешений всех учетных записей для каждого
+// экземпляра сервиса. Вызывается при изменении ролей и групп сервиса, так как их получают и назначения экземплярам.
+func (r *Repository) invalidateServiceInstancesPermissionsNumbers(ctx context.Context, service string) error {
+	details, err := r.persistent.ServiceDetails(ctx, service)
+	if err != nil {
+		return adaptErr(joint.ErrCacheSavedData)
+	}
+
+	for _, instance := range details.Instances {
+		if err = r.memory.DeleteInstancePermissionsNumbers(ctx, instance); err != nil {
+			return adaptErr(joint.ErrCacheSavedData)
+		}
+	}
+
+	return nil
+}
+
+// refreshAccountPermissions обновляет кеш разрешений. Номера в памяти заменяются прочитанными из постоянного хранилища,
+// поэтому отозванные разрешения из кеша удаляются.
 func (r *Repository) refreshAccountPermissions(ctx context.Context, data *dto.UserIdService) {
 	if servicePerm, err := r.persistent.ServicePermissionsNumbersForAccount(ctx, data); err == nil {
+		_ = r.memory.DeleteServicePermissionsNumbersForAccount(ctx, data)
 		_ = r.memory.SetServicePermissionsNumbersForAccount(context.Background(), &dto.UserIdServicePermNumbers{
 			UserId:            data.UserId,
 			Service:           data.Service,
@@ -422,7 +943,142 @@ func (r *Repository) refreshAccountPermissions(ctx context.Context, data *dto.Us
 	}
 }
 
+// refreshAccountInstancePermissions обновляет кеш разрешений учетной записи для экземпляра сервиса. Номера в памяти
+// заменяются прочитанными из постоянного хранилища.
+func (r *Repository) refreshAccountInstancePermissions(ctx context.Context, data *dto.UserIdInstance) {
+	if numbers, err := r.persistent.InstancePermissionsNumbersForAccount(ctx, data); err == nil {
+		_ = r.memory.DeleteInstancePermissionsNumbersForAccount(ctx, data)
+		_ = r.memory.SetInstancePermissionsNumbersForAccount(ctx, &dto.UserIdInstancePermNumbers{
+			UserId:            data.UserId,
+			Instance:          data.Instance,
+			PermissionNumbers: numbers,
+		})
+	}
+}
+
+// refreshGroupAccountsPermissions обновляет кеш разрешений сервиса учетных записей, входящих в группу напрямую или через
+// вложенные группы. Обновляются только закешированные ранее разрешения. Номера разрешений для экземпляров сервиса
+// удаляются из кеша для всех учетных записей.
+func (r *Repository) refreshGroupAccountsPermissions(ctx context.Context, data *dto.NameService) {
+	_ = r.invalidateServiceInstancesPermissionsNumbers(ctx, data.Service)
+
+	accounts, err := r.persistent.GroupAccounts(ctx, data)
+	if err != nil {
+		return
+	}
+
+	for _, id := range accounts {
+		userIdService := dto.UserIdService{UserId: id, Service: data.Service}
+		if r.memory.ExistServicePermissionsNumbersForAccount(ctx, &userIdService) {
+			r.refreshAccountPermissions(ctx, &userIdService)
+		}
+	}
+}
+
+// staleCache названия сервисов и экземпляров, закешированные данные которых устаревают при удалении части конфигурации
+// управления доступом.
+type staleCache struct {
+	services         map[string]struct{} // Сервисы, разрешения и номера разрешений учетных записей которых устарели
+	instances        map[string]struct{} // Экземпляры, номера разрешений учетных записей для которых устарели
+	removedInstances []string            // Удаляемые экземпляры
+}
+
+// staleCache возвращает сервисы и экземпляры, закешированные данные которых устареют после удаления removals. Список
+// экземпляров сервисов, разрешения которых удаляются, считывается до удаления.
+func (r *Repository) staleCache(ctx context.Context, removals *dto.RBACRemovals) staleCache {
+	stale := staleCache{
+		services:         make(map[string]struct{}),
+		instances:        make(map[string]struct{}),
+		removedInstances: removals.Instances,
+	}
+
+	for _, service := range removals.Services {
+		stale.services[service] = struct{}{}
+	}
+	for _, items := range [][]dto.NameService{removals.Permissions, removals.Roles, removals.Groups} {
+		for _, item := range items {
+			stale.services[item.Service] = struct{}{}
+		}
+	}
+	for _, item := range removals.RolePermissions {
+		stale.services[item.Service] = struct{}{}
+	}
+	for _, item := range removals.RoleParents {
+		stale.services[item.Service] = struct{}{}
+	}
+	for _, item := range removals.GroupRoles {
+		stale.services[item.Service] = struct{}{}
+	}
+	for _, item := range removals.GroupPermissions {
+		stale.services[item.Service] = struct{}{}
+	}
+	for _, item := range removals.GroupSubgroups {
+		stale.services[item.Service] = struct{}{}
+	}
+	for _, item := range removals.AccountRoles {
+		stale.services[item.Service] = struct{}{}
+	}
+	for _, item := range removals.AccountGroups {
+		stale.services[item.Service] = struct{}{}
+	}
+	for _, item := range removals.GlobalGroupRoles {
+		stale.services[item.Service] = struct{}{}
+	}
+	for _, item := range removals.AccountSelectorPermissions {
+		stale.services[item.Service] = struct{}{}
+	}
+
+	for _, instance := range removals.Instances {
+		stale.instances[instance] = struct{}{}
+	}
+	for _, item := range removals.AccountInstancePermissions {
+		stale.instances[item.Instance] = struct{}{}
+	}
+	for _, item := range removals.AccountInstanceRoles {
+		stale.instances[item.Instance] = struct{}{}
+	}
+	for _, item := range removals.AccountInstanceGroups {
+		stale.instances[item.Instance] = struct{}{}
+	}
+
+	// Роли и группы сервиса назначаются и для экземпляров, поэтому устаревают номера разрешений всех его экземпляров.
+	for service := range stale.services {
+		if details, err := r.persistent.ServiceDetails(ctx, service); err == nil {
+			for _, instance := range details.Instances {
+				stale.instances[instance] = struct{}{}
+			}
+		}
+	}
+
+	return stale
+}
+
+// invalidate удаляет из памяти устаревшие данные. Ошибки удаления записываются в журнал: данные в памяти в любом
+// случае устареют по истечении времени их жизни.
+func (s *staleCache) invalidate(ctx context.Context, memory in_memory.Interface) {
+	var errs []error
+
+	for service := range s.services {
+		errs = append(errs,
+			memory.DeleteServiceNumberedPermissions(ctx, service),
+			memory.DeleteServicePermissionsNumbers(ctx, service),
+			memory.DeleteServicePermissionEncoding(ctx, service))
+	}
+	for instance := range s.instances {
+		errs = append(errs, memory.DeleteInstancePermissionsNumbers(ctx, instance))
+	}
+	for _, instance := range s.removedInstances {
+		errs = append(errs, memory.DeleteInstance(ctx, instance))
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		slog.Warn("unable to invalidate cached rbac data: " + err.Error())
+	}
+}
+
 // makeDataCache считывает все данные (которые возможно кешировать) из постоянного хранилища в хранилище в памяти.
+// Данные учетных записей считываются параллельно, не более чем в половину соединений с БД. Функция возвращается
+// после окончания кеширования.
 func (r *Repository) makeDataCache() {
 	slog.Info("data caching has started")
 	start := time.Now()
@@ -436,27 +1092,38 @@ func (r *Repository) makeDataCache() {
 	if err != nil {
 		slog.Error(err.Error())
 	} else {
-		c := make(chan struct{}, r.persistent.MaxConnections()/2)
-		defer close(c)
+		var wg sync.WaitGroup
+		c := make(chan struct{}, max(r.persistent.MaxConnections()/2, 1))
 
 		for _, login := range enabledAccountsLogins {
 			c <- struct{}{}
+			wg.Add(1)
 
 			go func(login loginVO.Login) {
-				if _, err = r.AccountLoginData(ctx, login); err != nil {
-					slog.Error(err.Error())
+				defer func() {
+					<-c
+					wg.Done()
+				}()
+				data, err := r.persistent.AccountLoginData(ctx, login)
+				if err == nil {
+					err = r.saveToMemoryLoginData(ctx, &data)
+				}
+				if err != nil {
+					slog.Error(adaptErr(err).Error())
 				}
 			}(login)
-
-			<-c
 		}
+
+		wg.Wait()
 	}
 
 	var services []string
 
 	if services, err = r.ServicesNames(ctx); err == nil {
 		for _, service := range services {
-			_, _ = r.ServiceNumberedPermissions(ctx, service)
+			if permissions, err := r.persistent.ServiceNumberedPermissions(ctx, service); err == nil {
+				_ = r.memory.SetServiceNumberedPermissions(ctx, service, permissions)
+			}
 		}
 	}
 }
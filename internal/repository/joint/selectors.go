@@ -0,0 +1,32 @@
+package joint
+
+import (
+	"context"
+	"github.com/lazylex/watch-store/secure/internal/dto"
+	"log/slog"
+)
+
+// AssignSelectorPermissionToAccount назначает учетной записи разрешение сервиса для экземпляров, атрибуты которых
+// соответствуют селектору. Закешированные номера разрешений учетной записи для экземпляров сервиса обновляются. Ошибка
+// чтения экземпляров сервиса после назначения записывается в журнал: закешированные номера в любом случае устареют по
+// истечении времени их жизни.
+func (r *Repository) AssignSelectorPermissionToAccount(ctx context.Context, data *dto.UserIdSelectorPermission) error {
+	if err := r.persistent.AssignSelectorPermissionToAccount(ctx, data); err != nil {
+		return adaptErr(err)
+	}
+
+	details, err := r.persistent.ServiceDetails(ctx, data.Service)
+	if err != nil {
+		slog.Warn("unable to refresh cached selector permissions: " + adaptErr(err).Error())
+		return nil
+	}
+
+	for _, instance := range details.Instances {
+		userIdInstance := dto.UserIdInstance{UserId: data.UserId, Instance: instance}
+		if r.memory.ExistInstancePermissionsNumbersForAccount(ctx, &userIdInstance) {
+			r.refreshAccountInstancePermissions(ctx, &userIdInstance)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,151 @@
+package joint
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/lazylex/watch-store/secure/internal/domain/value_objects/account_state"
+	"github.com/lazylex/watch-store/secure/internal/dto"
+	"time"
+)
+
+// DeleteRole помечает роль удаленной и удаляет из памяти устаревшие номера разрешений сервиса.
+func (r *Repository) DeleteRole(ctx context.Context, data *dto.NameService) error {
+	return r.changeWithStaleCache(ctx, &dto.RBACRemovals{Roles: []dto.NameService{*data}}, func() error {
+		return r.persistent.DeleteRole(ctx, data)
+	})
+}
+
+// DeleteGroup помечает группу удаленной и удаляет из памяти устаревшие номера разрешений сервиса.
+func (r *Repository) DeleteGroup(ctx context.Context, data *dto.NameService) error {
+	return r.changeWithStaleCache(ctx, &dto.RBACRemovals{Groups: []dto.NameService{*data}}, func() error {
+		return r.persistent.DeleteGroup(ctx, data)
+	})
+}
+
+// DeletePermission помечает разрешение удаленным и удаляет из памяти устаревшие номера разрешений сервиса.
+func (r *Repository) DeletePermission(ctx context.Context, data *dto.NameService) error {
+	return r.changeWithStaleCache(ctx, &dto.RBACRemovals{Permissions: []dto.NameService{*data}}, func() error {
+		return r.persistent.DeletePermission(ctx, data)
+	})
+}
+
+// DeleteInstance помечает экземпляр сервиса удаленным и удаляет из памяти его данные.
+func (r *Repository) DeleteInstance(ctx context.Context, name string) error {
+	return r.changeWithStaleCache(ctx, &dto.RBACRemovals{Instances: []string{name}}, func() error {
+		return r.persistent.DeleteInstance(ctx, name)
+	})
+}
+
+// DeleteService помечает удаленными сервис вместе с его экземплярами, разрешениями, ролями и группами и удаляет из
+// памяти их данные. Список экземпляров сервиса считывается до удаления.
+func (r *Repository) DeleteService(ctx context.Context, name string) error {
+	removals := dto.RBACRemovals{Services: []string{name}}
+	if details, err := r.persistent.ServiceDetails(ctx, name); err == nil {
+		removals.Instances = details.Instances
+	}
+
+	return r.changeWithStaleCache(ctx, &removals, func() error {
+		return r.persistent.DeleteService(ctx, name)
+	})
+}
+
+// DeleteAccount помечает учетную запись удаленной, завершает её сессию и удаляет из памяти её данные для входа и
+// закешированные номера разрешений. Логин учетной записи считывается до удаления.
+func (r *Repository) DeleteAccount(ctx context.Context, id uuid.UUID) error {
+	loginData, err := r.persistent.AccountLoginDataByUserId(ctx, id)
+	if err != nil {
+		return adaptErr(err)
+	}
+
+	defer r.stateLocker.Unlock(loginData.Login)
+	r.stateLocker.Lock(loginData.Login)
+
+	if err = r.persistent.DeleteAccount(ctx, id); err != nil {
+		return adaptErr(err)
+	}
+
+	_ = r.memory.DeleteSession(ctx, id)
+	_ = r.memory.DeleteUserIdAndPasswordHash(ctx, loginData.Login)
+	_ = r.memory.SetAccountState(ctx, &dto.LoginState{Login: loginData.Login, State: account_state.Disabled})
+	r.invalidateAccountsPermissionsNumbers(ctx, id)
+
+	return nil
+}
+
+// RestoreRole восстанавливает удаленную роль и удаляет из памяти устаревшие номера разрешений сервиса.
+func (r *Repository) RestoreRole(ctx context.Context, data *dto.NameService) error {
+	return r.changeWithStaleCache(ctx, &dto.RBACRemovals{Roles: []dto.NameService{*data}}, func() error {
+		return r.persistent.RestoreRole(ctx, data)
+	})
+}
+
+// RestoreGroup восстанавливает удаленную группу и удаляет из памяти устаревшие номера разрешений сервиса.
+func (r *Repository) RestoreGroup(ctx context.Context, data *dto.NameService) error {
+	return r.changeWithStaleCache(ctx, &dto.RBACRemovals{Groups: []dto.NameService{*data}}, func() error {
+		return r.persistent.RestoreGroup(ctx, data)
+	})
+}
+
+// RestorePermission восстанавливает удаленное разрешение и удаляет из памяти устаревшие номера разрешений сервиса.
+func (r *Repository) RestorePermission(ctx context.Context, data *dto.NameService) error {
+	return r.changeWithStaleCache(ctx, &dto.RBACRemovals{Permissions: []dto.NameService{*data}}, func() error {
+		return r.persistent.RestorePermission(ctx, data)
+	})
+}
+
+// RestoreInstance восстанавливает удаленный экземпляр сервиса и удаляет из памяти устаревшие данные экземпляра.
+func (r *Repository) RestoreInstance(ctx context.Context, name string) error {
+	return r.changeWithStaleCache(ctx, &dto.RBACRemovals{Instances: []string{name}}, func() error {
+		return r.persistent.RestoreInstance(ctx, name)
+	})
+}
+
+// RestoreService восстанавливает удаленный сервис вместе с удаленными с ним сущностями и удаляет из памяти
+// устаревшие данные сервиса и его экземпляров.
+func (r *Repository) RestoreService(ctx context.Context, name string) error {
+	if err := r.persistent.RestoreService(ctx, name); err != nil {
+		return adaptErr(err)
+	}
+
+	stale := r.staleCache(ctx, &dto.RBACRemovals{Services: []string{name}})
+	stale.invalidate(ctx, r.memory)
+
+	return nil
+}
+
+// RestoreAccount восстанавливает удаленную учетную запись и сохраняет в памяти её данные для входа.
+func (r *Repository) RestoreAccount(ctx context.Context, id uuid.UUID) error {
+	if err := r.persistent.RestoreAccount(ctx, id); err != nil {
+		return adaptErr(err)
+	}
+
+	loginData, err := r.persistent.AccountLoginDataByUserId(ctx, id)
+	if err != nil {
+		return adaptErr(err)
+	}
+
+	defer r.stateLocker.Unlock(loginData.Login)
+	r.stateLocker.Lock(loginData.Login)
+
+	return r.saveToMemoryLoginData(ctx, &loginData)
+}
+
+// PurgeDeleted безвозвратно удаляет из постоянного хранилища сущности, помеченные удаленными раньше before. Данные
+// удаленных сущностей из памяти уже удалены, поэтому кеш не изменяется.
+func (r *Repository) PurgeDeleted(ctx context.Context, before time.Time) error {
+	return adaptErr(r.persistent.PurgeDeleted(ctx, before))
+}
+
+// changeWithStaleCache выполняет изменение change в постоянном хранилище и при успехе удаляет из памяти данные,
+// устаревающие при удалении removals. Устаревшие данные определяются до изменения.
+func (r *Repository) changeWithStaleCache(ctx context.Context, removals *dto.RBACRemovals, change func() error) error {
+	stale := r.staleCache(ctx, removals)
+
+	if err := change(); err != nil {
+		return adaptErr(err)
+	}
+
+	stale.invalidate(ctx, r.memory)
+
+	return nil
+}
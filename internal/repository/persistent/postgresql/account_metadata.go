@@ -0,0 +1,26 @@
+package postgresql
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/lazylex/watch-store/secure/internal/dto"
+)
+
+// SetAccountMetadata сохраняет тип, отображаемое имя, контакт и команду-владельца учетной записи и обновляет время её
+// изменения.
+func (p *PostgreSQL) SetAccountMetadata(ctx context.Context, data *dto.UserIdAccountMetadata) error {
+	stmt := `	UPDATE accounts
+				SET account_type = $1, display_name = $2, contact = $3, owner_team = $4, updated_at = now()
+				WHERE uuid = $5`
+
+	return p.processExecResult(p.pool.ExecEx(ctx, stmt, nil, data.Type, data.DisplayName, data.Contact, data.OwnerTeam,
+		data.UserId))
+}
+
+// UpdateAccountLastLogin сохраняет текущее время как время последнего входа в учетную запись. Время изменения учетной
+// записи при этом не обновляется.
+func (p *PostgreSQL) UpdateAccountLastLogin(ctx context.Context, id uuid.UUID) error {
+	stmt := `UPDATE accounts SET last_login_at = now() WHERE uuid = $1`
+
+	return p.processExecResult(p.pool.ExecEx(ctx, stmt, nil, id))
+}
@@ -0,0 +1,83 @@
+package postgresql
+
+import (
+	"context"
+	"github.com/jackc/pgx"
+	"github.com/lazylex/watch-store/secure/internal/dto"
+)
+
+// SetInstanceAttribute устанавливает значение атрибута экземпляра сервиса, используемого в условиях назначений.
+func (p *PostgreSQL) SetInstanceAttribute(ctx context.Context, data *dto.InstanceNameValue) error {
+	stmt := `	INSERT INTO instance_attributes(instance_fk, name, value)
+				VALUES((SELECT instance_id FROM instances WHERE name = $1 AND deleted_at IS NULL), $2, $3)
+				ON CONFLICT (instance_fk, name) DO UPDATE SET value = EXCLUDED.value`
+
+	return p.processExecResult(p.pool.ExecEx(ctx, stmt, nil, data.Instance, data.Name, data.Value))
+}
+
+// DeleteInstanceAttribute удаляет атрибут экземпляра сервиса.
+func (p *PostgreSQL) DeleteInstanceAttribute(ctx context.Context, data *dto.NameInstance) error {
+	stmt := `	DELETE FROM instance_attributes
+				WHERE instance_fk = (SELECT instance_id FROM instances WHERE name = $1 AND deleted_at IS NULL)
+				  AND name = $2`
+
+	return p.processExecResult(p.pool.ExecEx(ctx, stmt, nil, data.Instance, data.Name))
+}
+
+// InstanceAttributes возвращает атрибуты экземпляра сервиса.
+func (p *PostgreSQL) InstanceAttributes(ctx context.Context, instance string) (map[string]string, error) {
+	stmt := `	SELECT a.name, a.value
+				FROM instance_attributes a
+					JOIN instances i ON i.instance_id = a.instance_fk AND i.deleted_at IS NULL
+				WHERE i.name = $1`
+
+	attributes, err := queryRows(ctx, p, stmt, func(rows *pgx.Rows) (dto.InstanceNameValue, error) {
+		var value dto.InstanceNameValue
+		err := rows.Scan(&value.Name, &value.Value)
+		return value, err
+	}, instance)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]string, len(attributes))
+	for _, attribute := range attributes {
+		result[attribute.Name] = attribute.Value
+	}
+
+	return result, nil
+}
+
+// ConditionalPermissionsForAccount возвращает номера разрешений учетной записи для экземпляра сервиса, полученные по
+// действующим назначениям с условием, вместе с текстом условия. Разрешение, назначенное по нескольким назначениям с
+// разными условиями, возвращается с каждым из них.
+func (p *PostgreSQL) ConditionalPermissionsForAccount(ctx context.Context, data *dto.UserIdInstance) ([]dto.NumberCondition, error) {
+	cte := `WITH RECURSIVE
+			account_cte AS
+			(SELECT account_id
+			FROM accounts
+			WHERE uuid = $1 AND deleted_at IS NULL),
+
+			instance_cte AS
+			(SELECT instance_id, name, service_fk
+			FROM instances
+			WHERE name = $2 AND deleted_at IS NULL),
+
+			service_cte AS
+			(SELECT service_fk AS service_id
+			FROM instance_cte),
+
+			` + roleClosureCTE + `,
+
+			` + conditionalGroupClosureCTE
+
+	stmt := cte + `	SELECT DISTINCT number, grant_condition
+					FROM (` + conditionalGrantPathsStmt + `) paths
+					ORDER BY 1, 2`
+
+	return queryRows(ctx, p, stmt, func(rows *pgx.Rows) (dto.NumberCondition, error) {
+		var value dto.NumberCondition
+		err := rows.Scan(&value.Number, &value.Condition)
+		return value, err
+	}, data.UserId, data.Instance)
+}
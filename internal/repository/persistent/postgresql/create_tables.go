@@ -2,6 +2,11 @@ package postgresql
 
 import "fmt"
 
+// Migrate создает схему и таблицы в БД, если они отсутствуют.
+func (p *PostgreSQL) Migrate() error {
+	return adaptErr(p.createNotExistedSchemaAndTables())
+}
+
 // createNotExistedSchemaAndTables создает схему и таблицы в БД, если они отсутствуют.
 func (p *PostgreSQL) createNotExistedSchemaAndTables() error {
 	var stmt string
@@ -24,6 +29,17 @@ func (p *PostgreSQL) createNotExistedSchemaAndTables() error {
 		return err
 	}
 
+	stmt = `ALTER TABLE accounts ADD COLUMN IF NOT EXISTS account_type VARCHAR(10) NOT NULL DEFAULT 'human',
+			ADD COLUMN IF NOT EXISTS display_name TEXT NOT NULL DEFAULT '',
+			ADD COLUMN IF NOT EXISTS contact TEXT NOT NULL DEFAULT '',
+			ADD COLUMN IF NOT EXISTS owner_team TEXT NOT NULL DEFAULT '',
+			ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+			ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+			ADD COLUMN IF NOT EXISTS last_login_at TIMESTAMPTZ`
+	if err := p.createTable(stmt); err != nil {
+		return err
+	}
+
 	stmt = `CREATE TABLE IF NOT EXISTS services 
 		(
 			service_id SERIAL PRIMARY KEY,
@@ -34,6 +50,11 @@ func (p *PostgreSQL) createNotExistedSchemaAndTables() error {
 		return err
 	}
 
+	stmt = `ALTER TABLE services ADD COLUMN IF NOT EXISTS permission_encoding VARCHAR(10) NOT NULL DEFAULT 'list'`
+	if err := p.createTable(stmt); err != nil {
+		return err
+	}
+
 	stmt = `CREATE TABLE IF NOT EXISTS instances 
 		(
 			instance_id SERIAL PRIMARY KEY,
@@ -58,6 +79,26 @@ func (p *PostgreSQL) createNotExistedSchemaAndTables() error {
 		return err
 	}
 
+	stmt = `ALTER TABLE permissions ADD COLUMN IF NOT EXISTS deprecated BOOLEAN NOT NULL DEFAULT FALSE`
+	if err := p.createTable(stmt); err != nil {
+		return err
+	}
+
+	stmt = `CREATE UNIQUE INDEX IF NOT EXISTS permissions_number_service_fk_key ON permissions (number, service_fk)`
+	if err := p.createTable(stmt); err != nil {
+		return err
+	}
+
+	stmt = `CREATE TABLE IF NOT EXISTS retired_permission_numbers
+		(
+			service_fk INTEGER NOT NULL REFERENCES services ON DELETE CASCADE,
+			number INTEGER NOT NULL,
+			PRIMARY KEY(service_fk, number)
+		)`
+	if err := p.createTable(stmt); err != nil {
+		return err
+	}
+
 	stmt = `CREATE TABLE IF NOT EXISTS accounts_instances_permissions
 		(
 			account_fk INTEGER NOT NULL REFERENCES accounts ON DELETE CASCADE,
@@ -91,6 +132,17 @@ func (p *PostgreSQL) createNotExistedSchemaAndTables() error {
 		return err
 	}
 
+	stmt = `CREATE TABLE IF NOT EXISTS role_parents
+		(
+			role_fk INTEGER NOT NULL REFERENCES roles ON DELETE CASCADE,
+			parent_fk INTEGER NOT NULL REFERENCES roles ON DELETE CASCADE,
+			PRIMARY KEY(role_fk, parent_fk),
+			CHECK (role_fk <> parent_fk)
+		)`
+	if err := p.createTable(stmt); err != nil {
+		return err
+	}
+
 	stmt = `CREATE TABLE IF NOT EXISTS account_roles
 		(
 			role_fk INTEGER NOT NULL REFERENCES roles ON DELETE CASCADE,
@@ -133,6 +185,17 @@ func (p *PostgreSQL) createNotExistedSchemaAndTables() error {
 		return err
 	}
 
+	stmt = `CREATE TABLE IF NOT EXISTS group_subgroups
+		(
+			group_fk INTEGER NOT NULL REFERENCES groups ON DELETE CASCADE,
+			subgroup_fk INTEGER NOT NULL REFERENCES groups ON DELETE CASCADE,
+			PRIMARY KEY(group_fk, subgroup_fk),
+			CHECK (group_fk <> subgroup_fk)
+		)`
+	if err := p.createTable(stmt); err != nil {
+		return err
+	}
+
 	stmt = `CREATE TABLE IF NOT EXISTS account_groups
 		(
 			account_fk INTEGER NOT NULL REFERENCES accounts ON DELETE CASCADE,
@@ -143,6 +206,140 @@ func (p *PostgreSQL) createNotExistedSchemaAndTables() error {
 		return err
 	}
 
+	stmt = `CREATE TABLE IF NOT EXISTS global_groups
+		(
+			global_group_id SERIAL PRIMARY KEY,
+			name VARCHAR(100) NOT NULL UNIQUE,
+			description TEXT
+		)`
+	if err := p.createTable(stmt); err != nil {
+		return err
+	}
+
+	stmt = `CREATE TABLE IF NOT EXISTS global_group_roles
+		(
+			global_group_fk INTEGER NOT NULL REFERENCES global_groups ON DELETE CASCADE,
+			role_fk INTEGER NOT NULL REFERENCES roles ON DELETE CASCADE,
+			PRIMARY KEY(global_group_fk, role_fk)
+		)`
+	if err := p.createTable(stmt); err != nil {
+		return err
+	}
+
+	stmt = `CREATE TABLE IF NOT EXISTS account_global_groups
+		(
+			account_fk INTEGER NOT NULL REFERENCES accounts ON DELETE CASCADE,
+			global_group_fk INTEGER NOT NULL REFERENCES global_groups ON DELETE CASCADE,
+			valid_from TIMESTAMPTZ,
+			valid_until TIMESTAMPTZ,
+			PRIMARY KEY(account_fk, global_group_fk)
+		)`
+	if err := p.createTable(stmt); err != nil {
+		return err
+	}
+
+	stmt = `CREATE TABLE IF NOT EXISTS account_instance_roles
+		(
+			account_fk INTEGER NOT NULL REFERENCES accounts ON DELETE CASCADE,
+			instance_fk INTEGER NOT NULL REFERENCES instances ON DELETE CASCADE,
+			role_fk INTEGER NOT NULL REFERENCES roles ON DELETE CASCADE,
+			valid_from TIMESTAMPTZ,
+			valid_until TIMESTAMPTZ,
+			PRIMARY KEY(account_fk, instance_fk, role_fk)
+		)`
+	if err := p.createTable(stmt); err != nil {
+		return err
+	}
+
+	stmt = `CREATE TABLE IF NOT EXISTS account_instance_groups
+		(
+			account_fk INTEGER NOT NULL REFERENCES accounts ON DELETE CASCADE,
+			instance_fk INTEGER NOT NULL REFERENCES instances ON DELETE CASCADE,
+			group_fk INTEGER NOT NULL REFERENCES groups ON DELETE CASCADE,
+			valid_from TIMESTAMPTZ,
+			valid_until TIMESTAMPTZ,
+			PRIMARY KEY(account_fk, instance_fk, group_fk)
+		)`
+	if err := p.createTable(stmt); err != nil {
+		return err
+	}
+
+	for _, table := range []string{"account_roles", "account_groups", "accounts_instances_permissions"} {
+		stmt = fmt.Sprintf(`ALTER TABLE %s ADD COLUMN IF NOT EXISTS valid_from TIMESTAMPTZ,
+			ADD COLUMN IF NOT EXISTS valid_until TIMESTAMPTZ`, table)
+		if err := p.createTable(stmt); err != nil {
+			return err
+		}
+	}
+
+	for _, table := range []string{"account_roles", "account_groups", "accounts_instances_permissions",
+		"account_global_groups", "account_instance_roles", "account_instance_groups"} {
+		stmt = fmt.Sprintf(`ALTER TABLE %s ADD COLUMN IF NOT EXISTS grant_condition TEXT NOT NULL DEFAULT ''`, table)
+		if err := p.createTable(stmt); err != nil {
+			return err
+		}
+	}
+
+	for _, table := range []string{"accounts", "services", "instances", "permissions", "roles", "groups"} {
+		stmt = fmt.Sprintf(`ALTER TABLE %s ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ`, table)
+		if err := p.createTable(stmt); err != nil {
+			return err
+		}
+	}
+
+	stmt = `CREATE TABLE IF NOT EXISTS instance_attributes
+		(
+			instance_fk INTEGER NOT NULL REFERENCES instances ON DELETE CASCADE,
+			name VARCHAR(100) NOT NULL,
+			value TEXT NOT NULL,
+			PRIMARY KEY(instance_fk, name)
+		)`
+	if err := p.createTable(stmt); err != nil {
+		return err
+	}
+
+	stmt = `CREATE TABLE IF NOT EXISTS account_selector_permissions
+		(
+			account_fk INTEGER NOT NULL REFERENCES accounts ON DELETE CASCADE,
+			permission_fk INTEGER NOT NULL REFERENCES permissions ON DELETE CASCADE,
+			selector TEXT NOT NULL,
+			PRIMARY KEY(account_fk, permission_fk, selector)
+		)`
+	if err := p.createTable(stmt); err != nil {
+		return err
+	}
+
+	stmt = `CREATE TABLE IF NOT EXISTS totp_secrets
+		(
+			account_fk INTEGER PRIMARY KEY REFERENCES accounts ON DELETE CASCADE,
+			secret VARCHAR(64) NOT NULL,
+			enabled BOOLEAN NOT NULL DEFAULT FALSE
+		)`
+	if err := p.createTable(stmt); err != nil {
+		return err
+	}
+
+	stmt = `CREATE TABLE IF NOT EXISTS recovery_codes
+		(
+			account_fk INTEGER NOT NULL REFERENCES accounts ON DELETE CASCADE,
+			code_hash CHAR(64) NOT NULL,
+			PRIMARY KEY(account_fk, code_hash)
+		)`
+	if err := p.createTable(stmt); err != nil {
+		return err
+	}
+
+	stmt = `CREATE TABLE IF NOT EXISTS oidc_clients
+		(
+			client_id VARCHAR(100) PRIMARY KEY,
+			name VARCHAR(100) NOT NULL,
+			secret_hash VARCHAR(60) NOT NULL DEFAULT '',
+			redirect_uris TEXT[] NOT NULL
+		)`
+	if err := p.createTable(stmt); err != nil {
+		return err
+	}
+
 	return nil
 }
 
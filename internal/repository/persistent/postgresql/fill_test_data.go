@@ -47,7 +47,8 @@ func (p *PostgreSQL) FillTestData() error {
 		   (3, 'Сервис планирования', '', 1),
 		   (4, 'Сервис заказа', '', 1),
 		   (5, 'Сервис заказа', '', 2),
-		   (6, 'Сервис планирования', '', 2);
+		   (6, 'Сервис планирования', '', 2),
+		   (7, 'Администратор', 'Управление учетными записями и паролями', 3);
 	
 	INSERT INTO groups (group_id, name, description, service_fk)
 	VALUES (1, 'Персонал магазина', 'Продавцы и менеджеры', 1);
@@ -84,7 +85,8 @@ func (p *PostgreSQL) FillTestData() error {
 	INSERT INTO account_roles (role_fk, account_fk)
 	VALUES (1, 1),
 		   (1, 2),
-		   (2, 3);
+		   (2, 3),
+		   (7, 1);
 	
 	INSERT INTO accounts_instances_permissions (account_fk, instance_fk, permission_fk)
 	VALUES (3, 1, 8);`
@@ -0,0 +1,91 @@
+package postgresql
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx"
+	"github.com/lazylex/watch-store/secure/internal/dto"
+)
+
+// CreateGlobalGroup добавляет в БД глобальную группу, не привязанную к сервису.
+func (p *PostgreSQL) CreateGlobalGroup(ctx context.Context, data *dto.NameDescription) error {
+	stmt := `INSERT INTO global_groups (name, description) VALUES ($1, $2);`
+	return p.processExecResult(p.pool.ExecEx(ctx, stmt, nil, data.Name, data.Description))
+}
+
+// AssignRoleToGlobalGroup присоединяет к глобальной группе роль сервиса. Глобальная группа может содержать роли разных
+// сервисов.
+func (p *PostgreSQL) AssignRoleToGlobalGroup(ctx context.Context, data *dto.GlobalGroupRoleService) error {
+	stmt := `	INSERT INTO global_group_roles(global_group_fk, role_fk)
+				VALUES(
+					(SELECT global_group_id
+					FROM global_groups
+					WHERE name =$1),
+
+					(SELECT role_id
+					FROM roles
+					WHERE service_fk = (SELECT service_id
+										FROM services
+										WHERE name =$2 AND deleted_at IS NULL)
+					  AND
+					name =$3
+					  AND deleted_at IS NULL)
+				)`
+
+	return p.processExecResult(p.pool.ExecEx(ctx, stmt, nil, data.GlobalGroup, data.Service, data.Role))
+}
+
+// AssignGlobalGroupToAccount назначает глобальную группу учетной записи. Если задан период действия назначения,
+// учетная запись входит в группу только в его пределах.
+func (p *PostgreSQL) AssignGlobalGroupToAccount(ctx context.Context, data *dto.UserIdGlobalGroup) error {
+	stmt := `	INSERT INTO account_global_groups(global_group_fk, account_fk, valid_from, valid_until, grant_condition)
+				VALUES(
+					(SELECT global_group_id
+					FROM global_groups
+					WHERE name =$1),
+
+					(SELECT account_id
+					FROM accounts
+					WHERE uuid = $2 AND deleted_at IS NULL),
+
+					$3, $4, $5
+				)`
+
+	return p.processExecResult(p.pool.ExecEx(ctx, stmt, nil, data.GlobalGroup, data.UserId, data.ValidFrom,
+		data.ValidUntil, data.Condition))
+}
+
+// DeleteGlobalGroup удаляет глобальную группу из БД.
+func (p *PostgreSQL) DeleteGlobalGroup(ctx context.Context, name string) error {
+	stmt := `DELETE FROM global_groups WHERE name = $1`
+	return p.processExecResult(p.pool.ExecEx(ctx, stmt, nil, name))
+}
+
+// GlobalGroupAccounts возвращает идентификаторы учетных записей, которым назначена глобальная группа.
+func (p *PostgreSQL) GlobalGroupAccounts(ctx context.Context, name string) ([]uuid.UUID, error) {
+	stmt := `	SELECT a.uuid
+				FROM account_global_groups agg
+					JOIN global_groups gg ON gg.global_group_id = agg.global_group_fk
+					JOIN accounts a ON a.account_id = agg.account_fk AND a.deleted_at IS NULL
+				WHERE gg.name = $1
+				ORDER BY a.uuid`
+
+	return queryRows(ctx, p, stmt, func(rows *pgx.Rows) (uuid.UUID, error) {
+		var value uuid.UUID
+		err := rows.Scan(&value)
+		return value, err
+	}, name)
+}
+
+// GlobalGroupServices возвращает названия сервисов, роли которых входят в глобальную группу.
+func (p *PostgreSQL) GlobalGroupServices(ctx context.Context, name string) ([]string, error) {
+	stmt := `	SELECT DISTINCT s.name
+				FROM global_group_roles ggr
+					JOIN global_groups gg ON gg.global_group_id = ggr.global_group_fk
+					JOIN roles r ON r.role_id = ggr.role_fk AND r.deleted_at IS NULL
+					JOIN services s ON s.service_id = r.service_fk AND s.deleted_at IS NULL
+				WHERE gg.name = $1
+				ORDER BY s.name`
+
+	return queryRows(ctx, p, stmt, scanString, name)
+}
@@ -0,0 +1,201 @@
+package postgresql
+
+import (
+	"context"
+	"github.com/jackc/pgx"
+	"github.com/lazylex/watch-store/secure/internal/dto"
+	"time"
+)
+
+// AccountGrantsExpiration возвращает наиболее раннее время окончания действующих назначений учетной записи (ролей и
+// групп сервиса экземпляра, глобальных групп с ролями этого сервиса, разрешений, ролей и групп для самого экземпляра),
+// от которых зависят разрешения в её токене для экземпляра. Назначения с условием учитываются независимо от выполнения
+// условия. Если все назначения бессрочны, возвращается нулевое время.
+func (p *PostgreSQL) AccountGrantsExpiration(ctx context.Context, data *dto.UserIdInstance) (time.Time, error) {
+	var until *time.Time
+
+	cte := `WITH
+			account_cte AS (SELECT account_id FROM accounts WHERE uuid = $1 AND deleted_at IS NULL),
+
+			instance_cte AS (SELECT instance_id, service_fk FROM instances WHERE name = $2 AND deleted_at IS NULL)`
+
+	stmt := cte + `	SELECT min(valid_until)
+					FROM
+						(
+						SELECT ar.valid_until
+						FROM account_roles ar
+							JOIN roles r ON r.role_id = ar.role_fk AND r.deleted_at IS NULL
+						WHERE ar.account_fk = (SELECT account_id FROM account_cte)
+						  AND r.service_fk = (SELECT service_fk FROM instance_cte)
+						  AND ` + grantPeriodCondition + `
+
+						UNION ALL
+
+						SELECT ag.valid_until
+						FROM account_groups ag
+							JOIN groups g ON g.group_id = ag.group_fk AND g.deleted_at IS NULL
+						WHERE ag.account_fk = (SELECT account_id FROM account_cte)
+						  AND g.service_fk = (SELECT service_fk FROM instance_cte)
+						  AND ` + grantPeriodCondition + `
+
+						UNION ALL
+
+						SELECT agg.valid_until
+						FROM account_global_groups agg
+						WHERE agg.account_fk = (SELECT account_id FROM account_cte)
+						  AND ` + grantPeriodCondition + `
+						  AND EXISTS (SELECT 1
+									  FROM global_group_roles ggr
+										  JOIN roles r ON r.role_id = ggr.role_fk AND r.deleted_at IS NULL
+									  WHERE ggr.global_group_fk = agg.global_group_fk
+										AND r.service_fk = (SELECT service_fk FROM instance_cte))
+
+						UNION ALL
+
+						SELECT valid_until
+						FROM accounts_instances_permissions
+						WHERE account_fk = (SELECT account_id FROM account_cte)
+						  AND instance_fk = (SELECT instance_id FROM instance_cte)
+						  AND ` + grantPeriodCondition + `
+
+						UNION ALL
+
+						SELECT valid_until
+						FROM account_instance_roles
+						WHERE account_fk = (SELECT account_id FROM account_cte)
+						  AND instance_fk = (SELECT instance_id FROM instance_cte)
+						  AND ` + grantPeriodCondition + `
+
+						UNION ALL
+
+						SELECT valid_until
+						FROM account_instance_groups
+						WHERE account_fk = (SELECT account_id FROM account_cte)
+						  AND instance_fk = (SELECT instance_id FROM instance_cte)
+						  AND ` + grantPeriodCondition + `
+						) grants`
+
+	if err := p.pool.QueryRowEx(ctx, stmt, nil, data.UserId, data.Instance).Scan(&until); err != nil {
+		return time.Time{}, adaptErr(err)
+	}
+
+	if until == nil {
+		return time.Time{}, nil
+	}
+
+	return *until, nil
+}
+
+// DeleteExpiredGrants удаляет назначения учетным записям ролей, групп, глобальных групп, а также разрешений, ролей и
+// групп для экземпляров, срок действия которых истёк. Возвращает учетные записи вместе с сервисами (для ролей и групп,
+// а для глобальных групп - с каждым сервисом их ролей) или экземплярами (для назначений экземпляров), разрешения для
+// которых изменились: из-за удаленных назначений или назначений, вступивших в силу после since.
+func (p *PostgreSQL) DeleteExpiredGrants(ctx context.Context, since time.Time) ([]dto.UserIdServiceInstance, error) {
+	cte := `WITH
+			expired_roles AS
+			(DELETE FROM account_roles
+			WHERE valid_until <= now()
+			RETURNING account_fk, role_fk),
+
+			expired_groups AS
+			(DELETE FROM account_groups
+			WHERE valid_until <= now()
+			RETURNING account_fk, group_fk),
+
+			expired_global_groups AS
+			(DELETE FROM account_global_groups
+			WHERE valid_until <= now()
+			RETURNING account_fk, global_group_fk),
+
+			expired_permissions AS
+			(DELETE FROM accounts_instances_permissions
+			WHERE valid_until <= now()
+			RETURNING account_fk, instance_fk),
+
+			expired_instance_roles AS
+			(DELETE FROM account_instance_roles
+			WHERE valid_until <= now()
+			RETURNING account_fk, instance_fk),
+
+			expired_instance_groups AS
+			(DELETE FROM account_instance_groups
+			WHERE valid_until <= now()
+			RETURNING account_fk, instance_fk),
+
+			started_roles AS
+			(SELECT account_fk, role_fk
+			FROM account_roles
+			WHERE valid_from > $1
+			  AND valid_from <= now()),
+
+			started_groups AS
+			(SELECT account_fk, group_fk
+			FROM account_groups
+			WHERE valid_from > $1
+			  AND valid_from <= now()),
+
+			started_global_groups AS
+			(SELECT account_fk, global_group_fk
+			FROM account_global_groups
+			WHERE valid_from > $1
+			  AND valid_from <= now()),
+
+			started_permissions AS
+			(SELECT account_fk, instance_fk
+			FROM accounts_instances_permissions
+			WHERE valid_from > $1
+			  AND valid_from <= now()),
+
+			started_instance_roles AS
+			(SELECT account_fk, instance_fk
+			FROM account_instance_roles
+			WHERE valid_from > $1
+			  AND valid_from <= now()),
+
+			started_instance_groups AS
+			(SELECT account_fk, instance_fk
+			FROM account_instance_groups
+			WHERE valid_from > $1
+			  AND valid_from <= now())`
+
+	stmt := cte + `	SELECT a.uuid, s.name, ''
+					FROM (SELECT * FROM expired_roles UNION SELECT * FROM started_roles) e
+						JOIN accounts a ON a.account_id = e.account_fk AND a.deleted_at IS NULL
+						JOIN roles r ON r.role_id = e.role_fk AND r.deleted_at IS NULL
+						JOIN services s ON s.service_id = r.service_fk AND s.deleted_at IS NULL
+
+					UNION
+
+					SELECT a.uuid, s.name, ''
+					FROM (SELECT * FROM expired_groups UNION SELECT * FROM started_groups) e
+						JOIN accounts a ON a.account_id = e.account_fk AND a.deleted_at IS NULL
+						JOIN groups g ON g.group_id = e.group_fk AND g.deleted_at IS NULL
+						JOIN services s ON s.service_id = g.service_fk AND s.deleted_at IS NULL
+
+					UNION
+
+					SELECT a.uuid, s.name, ''
+					FROM (SELECT * FROM expired_global_groups UNION SELECT * FROM started_global_groups) e
+						JOIN accounts a ON a.account_id = e.account_fk AND a.deleted_at IS NULL
+						JOIN global_group_roles ggr ON ggr.global_group_fk = e.global_group_fk
+						JOIN roles r ON r.role_id = ggr.role_fk AND r.deleted_at IS NULL
+						JOIN services s ON s.service_id = r.service_fk AND s.deleted_at IS NULL
+
+					UNION
+
+					SELECT a.uuid, '', i.name
+					FROM (SELECT * FROM expired_permissions
+						  UNION SELECT * FROM started_permissions
+						  UNION SELECT * FROM expired_instance_roles
+						  UNION SELECT * FROM started_instance_roles
+						  UNION SELECT * FROM expired_instance_groups
+						  UNION SELECT * FROM started_instance_groups) e
+						JOIN accounts a ON a.account_id = e.account_fk AND a.deleted_at IS NULL
+						JOIN instances i ON i.instance_id = e.instance_fk AND i.deleted_at IS NULL`
+
+	return queryRows(ctx, p, stmt, func(rows *pgx.Rows) (dto.UserIdServiceInstance, error) {
+		var value dto.UserIdServiceInstance
+		err := rows.Scan(&value.UserId, &value.Service, &value.Instance)
+		return value, err
+	}, since)
+}
@@ -0,0 +1,483 @@
+package postgresql
+
+import (
+	"context"
+	"github.com/jackc/pgx"
+	"github.com/lazylex/watch-store/secure/internal/dto"
+	"github.com/lazylex/watch-store/secure/internal/errors/persistent"
+)
+
+// unusablePasswordHash хеш пароля создаваемых при импорте учетных записей. Не совпадает ни с одним хешем bcrypt, поэтому
+// войти в такую учетную запись можно только после сброса пароля.
+const unusablePasswordHash = "*"
+
+// ImportRBAC в одной транзакции удаляет (сервисы, экземпляры, разрешения, роли и группы помечает удаленными)
+// перечисленные в removals данные (если removals не равно nil), затем добавляет отсутствующие сервисы, экземпляры (с
+// секретами из instanceSecrets), разрешения с заданными номерами, выведенные из употребления номера, роли, группы,
+// глобальные группы, учетные записи и назначения из документа и обновляет описания и состояния существующих. Удаленные
+// (мягко) сущности, перечисленные в документе, восстанавливаются вместе со своими назначениями. Признак устаревания
+// разрешения только устанавливается, но не снимается. Учетные записи создаются с непригодным для входа хешем пароля.
+func (p *PostgreSQL) ImportRBAC(ctx context.Context, data *dto.RBACDocument, instanceSecrets map[string]string, removals *dto.RBACRemovals) error {
+	tx, err := p.pool.BeginEx(ctx, nil)
+	if err != nil {
+		return adaptErr(err)
+	}
+	defer func() { _ = tx.RollbackEx(ctx) }()
+
+	if removals != nil {
+		if err = removeRBAC(ctx, tx, removals); err != nil {
+			return adaptErr(err)
+		}
+	}
+
+	for _, service := range data.Services {
+		if err = importService(ctx, tx, &service, instanceSecrets); err != nil {
+			return adaptErr(err)
+		}
+	}
+
+	for _, group := range data.GlobalGroups {
+		if err = importGlobalGroup(ctx, tx, &group); err != nil {
+			return adaptErr(err)
+		}
+	}
+
+	for _, account := range data.Accounts {
+		if err = importAccount(ctx, tx, &account); err != nil {
+			return adaptErr(err)
+		}
+	}
+
+	return adaptErr(tx.CommitEx(ctx))
+}
+
+// importService добавляет или обновляет сервис со всеми его экземплярами, разрешениями, ролями и группами. Если
+// экземпляр с названием из документа (в том числе удаленный) принадлежит другому сервису, возвращается ошибка
+// persistent.ErrDuplicateKeyValue.
+func importService(ctx context.Context, tx *pgx.Tx, data *dto.RBACService, instanceSecrets map[string]string) error {
+	const serviceId = `(SELECT service_id FROM services WHERE name = $1)`
+
+	stmt := `	INSERT INTO services (name, description, permission_encoding) VALUES ($1, $2, COALESCE(NULLIF($3, ''), 'list'))
+				ON CONFLICT (name) DO UPDATE SET description         = EXCLUDED.description,
+												 permission_encoding = COALESCE(NULLIF($3, ''), services.permission_encoding),
+												 deleted_at          = NULL`
+	if _, err := tx.ExecEx(ctx, stmt, nil, data.Name, data.Description, data.PermissionEncoding); err != nil {
+		return err
+	}
+
+	stmt = `	INSERT INTO instances (name, service_fk, secret) VALUES ($2, ` + serviceId + `, $3)
+				ON CONFLICT (name) DO UPDATE SET deleted_at = NULL WHERE instances.service_fk = EXCLUDED.service_fk`
+	for _, instance := range data.Instances {
+		tag, err := tx.ExecEx(ctx, stmt, nil, data.Name, instance, instanceSecrets[instance])
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return persistent.ErrDuplicateKeyValue
+		}
+	}
+
+	stmt = `	INSERT INTO permissions (name, number, description, deprecated, service_fk)
+				VALUES ($2, $3, $4, $5, ` + serviceId + `)
+				ON CONFLICT (name, service_fk) DO UPDATE SET description = EXCLUDED.description,
+															  deprecated  = permissions.deprecated OR EXCLUDED.deprecated,
+															  deleted_at  = NULL`
+	for _, permission := range data.Permissions {
+		if _, err := tx.ExecEx(ctx, stmt, nil, data.Name, permission.Name, permission.Number, permission.Description,
+			permission.Deprecated); err != nil {
+			return err
+		}
+	}
+
+	stmt = `	INSERT INTO retired_permission_numbers (service_fk, number) VALUES (` + serviceId + `, $2)
+				ON CONFLICT DO NOTHING`
+	for _, number := range data.RetiredNumbers {
+		if _, err := tx.ExecEx(ctx, stmt, nil, data.Name, number); err != nil {
+			return err
+		}
+	}
+
+	for _, role := range data.Roles {
+		stmt = `	INSERT INTO roles (name, description, service_fk) VALUES ($2, $3, ` + serviceId + `)
+					ON CONFLICT (name, service_fk) DO UPDATE SET description = EXCLUDED.description, deleted_at = NULL`
+		if _, err := tx.ExecEx(ctx, stmt, nil, data.Name, role.Name, role.Description); err != nil {
+			return err
+		}
+
+		stmt = `	INSERT INTO role_permissions (role_fk, permission_fk)
+					VALUES ((SELECT role_id FROM roles WHERE name = $2 AND service_fk = ` + serviceId + `),
+							(SELECT permission_id FROM permissions WHERE name = $3 AND service_fk = ` + serviceId + `))
+					ON CONFLICT DO NOTHING`
+		for _, permission := range role.Permissions {
+			if _, err := tx.ExecEx(ctx, stmt, nil, data.Name, role.Name, permission); err != nil {
+				return err
+			}
+		}
+	}
+
+	stmt = `	INSERT INTO role_parents (role_fk, parent_fk)
+				VALUES ((SELECT role_id FROM roles WHERE name = $2 AND service_fk = ` + serviceId + `),
+						(SELECT role_id FROM roles WHERE name = $3 AND service_fk = ` + serviceId + `))
+				ON CONFLICT DO NOTHING`
+	for _, role := range data.Roles {
+		for _, parent := range role.Parents {
+			if _, err := tx.ExecEx(ctx, stmt, nil, data.Name, role.Name, parent); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, group := range data.Groups {
+		stmt = `	INSERT INTO groups (name, description, service_fk) VALUES ($2, $3, ` + serviceId + `)
+					ON CONFLICT (name, service_fk) DO UPDATE SET description = EXCLUDED.description, deleted_at = NULL`
+		if _, err := tx.ExecEx(ctx, stmt, nil, data.Name, group.Name, group.Description); err != nil {
+			return err
+		}
+
+		stmt = `	INSERT INTO group_roles (group_fk, role_fk)
+					VALUES ((SELECT group_id FROM groups WHERE name = $2 AND service_fk = ` + serviceId + `),
+							(SELECT role_id FROM roles WHERE name = $3 AND service_fk = ` + serviceId + `))
+					ON CONFLICT DO NOTHING`
+		for _, role := range group.Roles {
+			if _, err := tx.ExecEx(ctx, stmt, nil, data.Name, group.Name, role); err != nil {
+				return err
+			}
+		}
+
+		stmt = `	INSERT INTO group_permissions (group_fk, permission_fk)
+					VALUES ((SELECT group_id FROM groups WHERE name = $2 AND service_fk = ` + serviceId + `),
+							(SELECT permission_id FROM permissions WHERE name = $3 AND service_fk = ` + serviceId + `))
+					ON CONFLICT DO NOTHING`
+		for _, permission := range group.Permissions {
+			if _, err := tx.ExecEx(ctx, stmt, nil, data.Name, group.Name, permission); err != nil {
+				return err
+			}
+		}
+	}
+
+	stmt = `	INSERT INTO group_subgroups (group_fk, subgroup_fk)
+				VALUES ((SELECT group_id FROM groups WHERE name = $2 AND service_fk = ` + serviceId + `),
+						(SELECT group_id FROM groups WHERE name = $3 AND service_fk = ` + serviceId + `))
+				ON CONFLICT DO NOTHING`
+	for _, group := range data.Groups {
+		for _, subgroup := range group.Subgroups {
+			if _, err := tx.ExecEx(ctx, stmt, nil, data.Name, group.Name, subgroup); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// importGlobalGroup добавляет или обновляет глобальную группу и присоединяет к ней роли сервисов.
+func importGlobalGroup(ctx context.Context, tx *pgx.Tx, data *dto.RBACGlobalGroup) error {
+	stmt := `	INSERT INTO global_groups (name, description) VALUES ($1, $2)
+				ON CONFLICT (name) DO UPDATE SET description = EXCLUDED.description`
+	if _, err := tx.ExecEx(ctx, stmt, nil, data.Name, data.Description); err != nil {
+		return err
+	}
+
+	stmt = `	INSERT INTO global_group_roles (global_group_fk, role_fk)
+				VALUES ((SELECT global_group_id FROM global_groups WHERE name = $1),
+						(SELECT role_id
+						FROM roles
+						WHERE name = $2
+						  AND service_fk = (SELECT service_id FROM services WHERE name = $3)))
+				ON CONFLICT DO NOTHING`
+	for _, role := range data.Roles {
+		if _, err := tx.ExecEx(ctx, stmt, nil, data.Name, role.Name, role.Service); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// importAccount добавляет учетную запись или обновляет её состояние и метаданные (пустой тип учетной записи сохраняет
+// текущий, новые учетные записи без типа создаются с типом human) и назначает ей роли, группы, разрешения, роли и
+// группы для экземпляров сервисов, разрешения по селектору атрибутов (в канонической записи) и глобальные группы.
+func importAccount(ctx context.Context, tx *pgx.Tx, data *dto.AccountDetails) error {
+	const accountId = `(SELECT account_id FROM accounts WHERE uuid = $1)`
+
+	stmt := `	INSERT INTO accounts (uuid, login, pwd_hash, state, account_type, display_name, contact, owner_team)
+				VALUES ($1, $2, $3, $4, COALESCE(NULLIF($5, ''), 'human'), $6, $7, $8)
+				ON CONFLICT (uuid) DO UPDATE SET state = EXCLUDED.state,
+					account_type = COALESCE(NULLIF($5, ''), accounts.account_type),
+					display_name = EXCLUDED.display_name, contact = EXCLUDED.contact, owner_team = EXCLUDED.owner_team,
+					updated_at = now(), deleted_at = NULL`
+	_, err := tx.ExecEx(ctx, stmt, nil, data.UserId, data.Login, unusablePasswordHash, data.State, data.Type,
+		data.DisplayName, data.Contact, data.OwnerTeam)
+	if err != nil {
+		return err
+	}
+
+	stmt = `	INSERT INTO account_roles (account_fk, role_fk)
+				VALUES (` + accountId + `,
+						(SELECT role_id
+						FROM roles
+						WHERE name = $2
+						  AND service_fk = (SELECT service_id FROM services WHERE name = $3)))
+				ON CONFLICT DO NOTHING`
+	for _, role := range data.Roles {
+		if _, err := tx.ExecEx(ctx, stmt, nil, data.UserId, role.Name, role.Service); err != nil {
+			return err
+		}
+	}
+
+	stmt = `	INSERT INTO account_groups (account_fk, group_fk)
+				VALUES (` + accountId + `,
+						(SELECT group_id
+						FROM groups
+						WHERE name = $2
+						  AND service_fk = (SELECT service_id FROM services WHERE name = $3)))
+				ON CONFLICT DO NOTHING`
+	for _, group := range data.Groups {
+		if _, err := tx.ExecEx(ctx, stmt, nil, data.UserId, group.Name, group.Service); err != nil {
+			return err
+		}
+	}
+
+	stmt = `	INSERT INTO accounts_instances_permissions (account_fk, instance_fk, permission_fk)
+				SELECT ` + accountId + `, i.instance_id, p.permission_id
+				FROM instances i
+					JOIN permissions p ON p.service_fk = i.service_fk
+				WHERE i.name = $2
+				  AND p.name = $3
+				ON CONFLICT DO NOTHING`
+	for _, permission := range data.InstancePermissions {
+		if _, err := tx.ExecEx(ctx, stmt, nil, data.UserId, permission.Instance, permission.Permission); err != nil {
+			return err
+		}
+	}
+
+	stmt = `	INSERT INTO account_instance_roles (account_fk, instance_fk, role_fk)
+				SELECT ` + accountId + `, i.instance_id, r.role_id
+				FROM instances i
+					JOIN roles r ON r.service_fk = i.service_fk
+				WHERE i.name = $2
+				  AND r.name = $3
+				ON CONFLICT DO NOTHING`
+	for _, role := range data.InstanceRoles {
+		if _, err := tx.ExecEx(ctx, stmt, nil, data.UserId, role.Instance, role.Name); err != nil {
+			return err
+		}
+	}
+
+	stmt = `	INSERT INTO account_instance_groups (account_fk, instance_fk, group_fk)
+				SELECT ` + accountId + `, i.instance_id, g.group_id
+				FROM instances i
+					JOIN groups g ON g.service_fk = i.service_fk
+				WHERE i.name = $2
+				  AND g.name = $3
+				ON CONFLICT DO NOTHING`
+	for _, group := range data.InstanceGroups {
+		if _, err := tx.ExecEx(ctx, stmt, nil, data.UserId, group.Instance, group.Name); err != nil {
+			return err
+		}
+	}
+
+	stmt = `	INSERT INTO account_selector_permissions (account_fk, permission_fk, selector)
+				VALUES (` + accountId + `,
+						(SELECT permission_id
+						FROM permissions
+						WHERE name = $2
+						  AND service_fk = (SELECT service_id FROM services WHERE name = $3)),
+						$4)
+				ON CONFLICT DO NOTHING`
+	for _, permission := range data.SelectorPermissions {
+		selector, err := canonicalSelector(permission.Selector)
+		if err != nil {
+			return err
+		}
+		if _, err = tx.ExecEx(ctx, stmt, nil, data.UserId, permission.Permission, permission.Service, selector); err != nil {
+			return err
+		}
+	}
+
+	stmt = `	INSERT INTO account_global_groups (account_fk, global_group_fk)
+				VALUES (` + accountId + `, (SELECT global_group_id FROM global_groups WHERE name = $2))
+				ON CONFLICT DO NOTHING`
+	for _, group := range data.GlobalGroups {
+		if _, err := tx.ExecEx(ctx, stmt, nil, data.UserId, group); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// removeRBAC удаляет перечисленные назначения и глобальные группы и помечает удаленными перечисленные группы, роли,
+// разрешения, экземпляры и сервисы так же, как DeleteGroup, DeleteRole, DeletePermission, DeleteInstance и
+// DeleteService. Назначения помеченных сущностей сохраняются до окончательного удаления.
+func removeRBAC(ctx context.Context, tx *pgx.Tx, data *dto.RBACRemovals) error {
+	const (
+		serviceId    = `(SELECT service_id FROM services WHERE name = $3)`
+		accountId    = `(SELECT account_id FROM accounts WHERE uuid = $1)`
+		permissionId = `(SELECT permission_id FROM permissions WHERE name = $2 AND service_fk = ` + serviceId + `)`
+		roleId       = `(SELECT role_id FROM roles WHERE name = $2 AND service_fk = ` + serviceId + `)`
+	)
+
+	stmt := `	DELETE FROM role_permissions
+				WHERE role_fk = (SELECT role_id FROM roles WHERE name = $1 AND service_fk = ` + serviceId + `)
+				  AND permission_fk = ` + permissionId
+	for _, item := range data.RolePermissions {
+		if _, err := tx.ExecEx(ctx, stmt, nil, item.Role, item.Permission, item.Service); err != nil {
+			return err
+		}
+	}
+
+	stmt = `	DELETE FROM role_parents
+				WHERE role_fk = (SELECT role_id FROM roles WHERE name = $1 AND service_fk = ` + serviceId + `)
+				  AND parent_fk = ` + roleId
+	for _, item := range data.RoleParents {
+		if _, err := tx.ExecEx(ctx, stmt, nil, item.Role, item.Parent, item.Service); err != nil {
+			return err
+		}
+	}
+
+	stmt = `	DELETE FROM group_roles
+				WHERE group_fk = (SELECT group_id FROM groups WHERE name = $1 AND service_fk = ` + serviceId + `)
+				  AND role_fk = ` + roleId
+	for _, item := range data.GroupRoles {
+		if _, err := tx.ExecEx(ctx, stmt, nil, item.Group, item.Role, item.Service); err != nil {
+			return err
+		}
+	}
+
+	stmt = `	DELETE FROM group_permissions
+				WHERE group_fk = (SELECT group_id FROM groups WHERE name = $1 AND service_fk = ` + serviceId + `)
+				  AND permission_fk = ` + permissionId
+	for _, item := range data.GroupPermissions {
+		if _, err := tx.ExecEx(ctx, stmt, nil, item.Group, item.Permission, item.Service); err != nil {
+			return err
+		}
+	}
+
+	stmt = `	DELETE FROM group_subgroups
+				WHERE group_fk = (SELECT group_id FROM groups WHERE name = $1 AND service_fk = ` + serviceId + `)
+				  AND subgroup_fk = (SELECT group_id FROM groups WHERE name = $2 AND service_fk = ` + serviceId + `)`
+	for _, item := range data.GroupSubgroups {
+		if _, err := tx.ExecEx(ctx, stmt, nil, item.Group, item.Subgroup, item.Service); err != nil {
+			return err
+		}
+	}
+
+	stmt = `DELETE FROM account_roles WHERE account_fk = ` + accountId + ` AND role_fk = ` + roleId
+	for _, item := range data.AccountRoles {
+		if _, err := tx.ExecEx(ctx, stmt, nil, item.UserId, item.Role, item.Service); err != nil {
+			return err
+		}
+	}
+
+	stmt = `	DELETE FROM account_groups
+				WHERE account_fk = ` + accountId + `
+				  AND group_fk = (SELECT group_id FROM groups WHERE name = $2 AND service_fk = ` + serviceId + `)`
+	for _, item := range data.AccountGroups {
+		if _, err := tx.ExecEx(ctx, stmt, nil, item.UserId, item.Group, item.Service); err != nil {
+			return err
+		}
+	}
+
+	stmt = `	DELETE FROM accounts_instances_permissions
+				WHERE account_fk = ` + accountId + `
+				  AND (instance_fk, permission_fk) IN (SELECT i.instance_id, p.permission_id
+													   FROM instances i
+														   JOIN permissions p ON p.service_fk = i.service_fk
+													   WHERE i.name = $2
+														 AND p.name = $3)`
+	for _, item := range data.AccountInstancePermissions {
+		if _, err := tx.ExecEx(ctx, stmt, nil, item.UserId, item.Instance, item.Permission); err != nil {
+			return err
+		}
+	}
+
+	stmt = `	DELETE FROM account_instance_roles
+				WHERE account_fk = ` + accountId + `
+				  AND (instance_fk, role_fk) IN (SELECT i.instance_id, r.role_id
+												 FROM instances i
+													 JOIN roles r ON r.service_fk = i.service_fk
+												 WHERE i.name = $2
+												   AND r.name = $3)`
+	for _, item := range data.AccountInstanceRoles {
+		if _, err := tx.ExecEx(ctx, stmt, nil, item.UserId, item.Instance, item.Role); err != nil {
+			return err
+		}
+	}
+
+	stmt = `	DELETE FROM account_instance_groups
+				WHERE account_fk = ` + accountId + `
+				  AND (instance_fk, group_fk) IN (SELECT i.instance_id, g.group_id
+												  FROM instances i
+													  JOIN groups g ON g.service_fk = i.service_fk
+												  WHERE i.name = $2
+													AND g.name = $3)`
+	for _, item := range data.AccountInstanceGroups {
+		if _, err := tx.ExecEx(ctx, stmt, nil, item.UserId, item.Instance, item.Group); err != nil {
+			return err
+		}
+	}
+
+	stmt = `	DELETE FROM account_selector_permissions
+				WHERE account_fk = ` + accountId + `
+				  AND permission_fk = ` + permissionId + `
+				  AND selector = $4`
+	for _, item := range data.AccountSelectorPermissions {
+		if _, err := tx.ExecEx(ctx, stmt, nil, item.UserId, item.Permission, item.Service, item.Selector); err != nil {
+			return err
+		}
+	}
+
+	stmt = `	DELETE FROM account_global_groups
+				WHERE account_fk = ` + accountId + `
+				  AND global_group_fk = (SELECT global_group_id FROM global_groups WHERE name = $2)`
+	for _, item := range data.AccountGlobalGroups {
+		if _, err := tx.ExecEx(ctx, stmt, nil, item.UserId, item.GlobalGroup); err != nil {
+			return err
+		}
+	}
+
+	stmt = `	DELETE FROM global_group_roles
+				WHERE global_group_fk = (SELECT global_group_id FROM global_groups WHERE name = $1)
+				  AND role_fk = ` + roleId
+	for _, item := range data.GlobalGroupRoles {
+		if _, err := tx.ExecEx(ctx, stmt, nil, item.GlobalGroup, item.Role, item.Service); err != nil {
+			return err
+		}
+	}
+
+	stmt = `DELETE FROM global_groups WHERE name = $1`
+	for _, group := range data.GlobalGroups {
+		if _, err := tx.ExecEx(ctx, stmt, nil, group); err != nil {
+			return err
+		}
+	}
+
+	for table, items := range map[string][]dto.NameService{
+		"groups":      data.Groups,
+		"roles":       data.Roles,
+		"permissions": data.Permissions,
+	} {
+		stmt = softDeleteServiceEntityStmt(table)
+		for _, item := range items {
+			if _, err := tx.ExecEx(ctx, stmt, nil, item.Name, item.Service); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, instance := range data.Instances {
+		if _, err := tx.ExecEx(ctx, softDeleteInstanceStmt, nil, instance); err != nil {
+			return err
+		}
+	}
+
+	stmt = softDeleteServiceStmt()
+	for _, service := range data.Services {
+		if _, err := tx.ExecEx(ctx, stmt, nil, service); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,26 @@
+package postgresql
+
+import (
+	"context"
+	"github.com/lazylex/watch-store/secure/internal/dto"
+)
+
+// CreateOIDCClient сохраняет клиента OpenID Connect. Для публичных клиентов хеш секрета пуст.
+func (p *PostgreSQL) CreateOIDCClient(ctx context.Context, data *dto.OIDCClient) error {
+	stmt := `INSERT INTO oidc_clients (client_id, name, secret_hash, redirect_uris) VALUES ($1, $2, $3, $4);`
+
+	return p.processExecResult(p.pool.ExecEx(ctx, stmt, nil, data.ClientId, data.Name, data.SecretHash, data.RedirectURIs))
+}
+
+// OIDCClient возвращает клиента OpenID Connect по его идентификатору.
+func (p *PostgreSQL) OIDCClient(ctx context.Context, clientId string) (dto.OIDCClient, error) {
+	result := dto.OIDCClient{ClientId: clientId}
+	stmt := `SELECT name, secret_hash, redirect_uris FROM oidc_clients WHERE client_id = $1;`
+
+	row := p.pool.QueryRowEx(ctx, stmt, nil, clientId)
+	if err := row.Scan(&result.Name, &result.SecretHash, &result.RedirectURIs); err != nil {
+		return dto.OIDCClient{}, adaptErr(err)
+	}
+
+	return result, nil
+}
@@ -3,9 +3,12 @@ package postgresql
 import (
 	"context"
 	"fmt"
+	"github.com/google/uuid"
 	"github.com/jackc/pgx"
 	"github.com/lazylex/watch-store/secure/internal/config"
 	"github.com/lazylex/watch-store/secure/internal/domain/value_objects/account_state"
+	"github.com/lazylex/watch-store/secure/internal/domain/value_objects/account_type"
+	"github.com/lazylex/watch-store/secure/internal/domain/value_objects/grant_source"
 	loginVO "github.com/lazylex/watch-store/secure/internal/domain/value_objects/login"
 	"github.com/lazylex/watch-store/secure/internal/dto"
 	"github.com/lazylex/watch-store/secure/internal/errors/persistent"
@@ -98,7 +101,7 @@ func (p *PostgreSQL) MaxConnections() int {
 // AccountLoginData возвращает необходимые для процесса входа в систему данные пользователя (сервиса).
 func (p *PostgreSQL) AccountLoginData(ctx context.Context, login loginVO.Login) (dto.UserIdLoginHashState, error) {
 	result := dto.UserIdLoginHashState{Login: login}
-	stmt := `SELECT uuid, pwd_hash, state FROM accounts WHERE login = $1;`
+	stmt := `SELECT uuid, pwd_hash, state FROM accounts WHERE login = $1 AND deleted_at IS NULL;`
 	row := p.pool.QueryRowEx(ctx, stmt, nil, login)
 	err := row.Scan(&result.UserId, &result.Hash, &result.State)
 	if err != nil {
@@ -109,65 +112,224 @@ func (p *PostgreSQL) AccountLoginData(ctx context.Context, login loginVO.Login)
 }
 
 // SetAccountLoginData сохраняет в БД идентификатор пользователя (сервиса), логин, хеш пароля и состояние учетной
-// записи.
+// записи. Если есть удаленная учетная запись с тем же логином, возвращается ошибка persistent.ErrDeletedEntity.
 func (p *PostgreSQL) SetAccountLoginData(ctx context.Context, data *dto.UserIdLoginHashState) error {
 	stmt := `INSERT INTO accounts (uuid, login, pwd_hash, state) values ($1, $2, $3, $4);`
-	return p.processExecResult(p.pool.ExecEx(ctx, stmt, nil, data.UserId, data.Login, data.Hash, data.State))
+	err := p.processExecResult(p.pool.ExecEx(ctx, stmt, nil, data.UserId, data.Login, data.Hash, data.State))
+	return p.deletedConflict(ctx, err, "accounts", "login = $1", data.Login)
 }
 
-// SetAccountState устанавливает состояние учетной записи.
+// CreateAccount одним запросом сохраняет в БД данные для входа и метаданные новой учетной записи. Если метаданные не
+// переданы, учетная запись сохраняется с типом human и пустыми отображаемым именем, контактом и командой-владельцем.
+// Если есть удаленная учетная запись с тем же логином, возвращается ошибка persistent.ErrDeletedEntity.
+func (p *PostgreSQL) CreateAccount(ctx context.Context, data *dto.UserIdLoginHashState,
+	metadata *dto.UserIdAccountMetadata) error {
+	if metadata == nil {
+		metadata = &dto.UserIdAccountMetadata{Type: account_type.Human}
+	}
+
+	stmt := `	INSERT INTO accounts (uuid, login, pwd_hash, state, account_type, display_name, contact, owner_team)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8);`
+
+	err := p.processExecResult(p.pool.ExecEx(ctx, stmt, nil, data.UserId, data.Login, data.Hash, data.State,
+		metadata.Type, metadata.DisplayName, metadata.Contact, metadata.OwnerTeam))
+	return p.deletedConflict(ctx, err, "accounts", "login = $1", data.Login)
+}
+
+// AccountLoginDataByUserId возвращает логин, хеш пароля и состояние учетной записи по идентификатору пользователя
+// (сервиса).
+func (p *PostgreSQL) AccountLoginDataByUserId(ctx context.Context, id uuid.UUID) (dto.UserIdLoginHashState, error) {
+	result := dto.UserIdLoginHashState{UserId: id}
+	stmt := `SELECT login, pwd_hash, state FROM accounts WHERE uuid = $1 AND deleted_at IS NULL;`
+	row := p.pool.QueryRowEx(ctx, stmt, nil, id)
+	err := row.Scan(&result.Login, &result.Hash, &result.State)
+	if err != nil {
+		return dto.UserIdLoginHashState{}, adaptErr(err)
+	}
+
+	return result, nil
+}
+
+// SetAccountPasswordHash сохраняет новый хеш пароля учетной записи и обновляет время её изменения.
+func (p *PostgreSQL) SetAccountPasswordHash(ctx context.Context, data *dto.UserIdHash) error {
+	stmt := `UPDATE accounts SET pwd_hash = $1, updated_at = now() WHERE uuid = $2 AND deleted_at IS NULL;`
+	return p.processExecResult(p.pool.ExecEx(ctx, stmt, nil, data.Hash, data.UserId))
+}
+
+// AccountHasRole возвращает true, если учетной записи назначена роль сервиса напрямую, через группу (в том числе
+// объемлющую группу одной из её групп) или через глобальную группу либо назначена роль, наследующая разрешения этой
+// роли.
+func (p *PostgreSQL) AccountHasRole(ctx context.Context, data *dto.UserIdRoleService) (bool, error) {
+	var exist bool
+	cte := `WITH RECURSIVE
+			account_cte AS
+			(SELECT account_id
+			FROM accounts
+			WHERE uuid = $1 AND deleted_at IS NULL),
+
+			service_cte AS
+			(SELECT service_id
+			FROM services
+			WHERE name = $3 AND deleted_at IS NULL),
+
+			` + roleClosureCTE + `,
+
+			` + groupClosureCTE + `,
+
+			` + globalRolesCTE
+
+	stmt := cte + `	SELECT EXISTS
+						(
+						SELECT 1
+						FROM role_closure c
+							JOIN roles r ON r.role_id = c.inherited_fk AND r.deleted_at IS NULL
+						WHERE r.name = $2
+						  AND
+						c.role_fk IN
+							(
+							SELECT role_fk
+							FROM account_roles
+							WHERE account_fk = (SELECT account_id FROM account_cte)
+							  AND ` + activeGrantCondition + `
+
+							UNION
+
+							SELECT role_fk
+							FROM group_roles
+							WHERE group_fk IN (SELECT group_fk FROM group_closure)
+
+							UNION
+
+							SELECT role_fk
+							FROM global_roles
+							)
+						)`
+
+	row := p.pool.QueryRowEx(ctx, stmt, nil, data.UserId, data.Role, data.Service)
+	if err := row.Scan(&exist); err != nil {
+		return false, adaptErr(err)
+	}
+
+	return exist, nil
+}
+
+// SetAccountState устанавливает состояние учетной записи и обновляет время её изменения.
 func (p *PostgreSQL) SetAccountState(ctx context.Context, data *dto.LoginState) error {
-	stmt := `UPDATE accounts SET state = $1 WHERE login = $2;`
+	stmt := `UPDATE accounts SET state = $1, updated_at = now() WHERE login = $2 AND deleted_at IS NULL;`
 	return p.processExecResult(p.pool.ExecEx(ctx, stmt, nil, data.State, data.Login))
 }
 
-// CreatePermission добавляет разрешение в таблицу permissions.
-func (p *PostgreSQL) CreatePermission(ctx context.Context, data *dto.NameServiceDescription) error {
-	stmt := `	INSERT INTO permissions (name, description, service_fk, number)
-				VALUES ($1,
-						$2,
-						(SELECT service_id FROM services WHERE name = $3),
-						(SELECT CASE
-							WHEN  max(number) IS NULL THEN
-								1
-							ELSE 
-								max(number) + 1 END
-						FROM permissions
-						WHERE service_fk = (SELECT service_id FROM services WHERE name = $3))
-						);`
+// CreatePermission в одной транзакции проверяет номер и добавляет разрешение в таблицу permissions. Если номер
+// разрешения не задан (равен нулю), разрешению присваивается номер, следующий за наибольшим из действующих, удаленных и
+// выведенных из употребления номеров сервиса. Выведенный из употребления номер или номер удаленного разрешения, которое
+// еще можно восстановить, назначить нельзя: в этом случае возвращается ошибка persistent.ErrRetiredNumber. Если у
+// сервиса есть удаленное разрешение с тем же названием, возвращается ошибка persistent.ErrDeletedEntity.
+func (p *PostgreSQL) CreatePermission(ctx context.Context, data *dto.NameNumberDescriptionService) error {
+	var retired bool
+
+	tx, err := p.pool.BeginEx(ctx, nil)
+	if err != nil {
+		return adaptErr(err)
+	}
+	defer func() { _ = tx.RollbackEx(ctx) }()
+
+	if data.Number > 0 {
+		stmt := `	SELECT EXISTS (SELECT 1
+								FROM retired_permission_numbers
+								WHERE number = $1
+								  AND service_fk = (SELECT service_id FROM services WHERE name = $2 AND deleted_at IS NULL)
+
+								UNION ALL
+
+								SELECT 1
+								FROM permissions
+								WHERE number = $1
+								  AND service_fk = (SELECT service_id FROM services WHERE name = $2 AND deleted_at IS NULL)
+								  AND deleted_at IS NOT NULL)`
+		if err = tx.QueryRowEx(ctx, stmt, nil, data.Number, data.Service).Scan(&retired); err != nil {
+			return adaptErr(err)
+		}
+		if retired {
+			return persistent.ErrRetiredNumber
+		}
+	}
+
+	cte := `WITH
+			service_cte AS (SELECT service_id FROM services WHERE name = $3 AND deleted_at IS NULL)`
+
+	stmt := cte + `	INSERT INTO permissions (name, description, service_fk, number)
+					VALUES ($1,
+							$2,
+							(SELECT service_id FROM service_cte),
+							CASE
+								WHEN $4 > 0 THEN
+									$4
+								ELSE
+									(SELECT COALESCE(max(number), 0) + 1
+									FROM (SELECT number
+										  FROM permissions
+										  WHERE service_fk = (SELECT service_id FROM service_cte)
+										  UNION ALL
+										  SELECT number
+										  FROM retired_permission_numbers
+										  WHERE service_fk = (SELECT service_id FROM service_cte)) AS numbers)
+							END
+							);`
+
+	if err = p.processExecResult(tx.ExecEx(ctx, stmt, nil, data.Name, data.Description, data.Service, data.Number)); err != nil {
+		return p.deletedConflict(ctx, err, "permissions", serviceEntityCondition, data.Name, data.Service)
+	}
 
-	return p.processExecResult(p.pool.ExecEx(ctx, stmt, nil, data.Name, data.Description, data.Service))
+	return adaptErr(tx.CommitEx(ctx))
 }
 
-// CreateRole добавляет роль в БД.
+// DeprecatePermission помечает разрешение устаревшим. Устаревшее разрешение сохраняет свой номер и назначения, но не
+// может быть назначено повторно.
+func (p *PostgreSQL) DeprecatePermission(ctx context.Context, data *dto.NameService) error {
+	stmt := `	UPDATE permissions
+				SET deprecated = TRUE
+				WHERE name = $1
+				  AND deleted_at IS NULL
+				  AND service_fk = (SELECT service_id FROM services WHERE name = $2 AND deleted_at IS NULL)`
+	return p.processExecResult(p.pool.ExecEx(ctx, stmt, nil, data.Name, data.Service))
+}
+
+// CreateRole добавляет роль в БД. Если у сервиса есть удаленная роль с тем же названием, возвращается ошибка
+// persistent.ErrDeletedEntity: такую роль нужно восстановить.
 func (p *PostgreSQL) CreateRole(ctx context.Context, data *dto.NameServiceDescription) error {
 	stmt := `INSERT INTO roles (name, description, service_fk)
-			VALUES ($1, $2, (SELECT service_id FROM services WHERE name=$3));`
-	return p.processExecResult(p.pool.ExecEx(ctx, stmt, nil, data.Name, data.Description, data.Service))
+			VALUES ($1, $2, (SELECT service_id FROM services WHERE name=$3 AND deleted_at IS NULL));`
+	err := p.processExecResult(p.pool.ExecEx(ctx, stmt, nil, data.Name, data.Description, data.Service))
+	return p.deletedConflict(ctx, err, "roles", serviceEntityCondition, data.Name, data.Service)
 }
 
-// CreateGroup добавляет группу в БД.
+// CreateGroup добавляет группу в БД. Если у сервиса есть удаленная группа с тем же названием, возвращается ошибка
+// persistent.ErrDeletedEntity: такую группу нужно восстановить.
 func (p *PostgreSQL) CreateGroup(ctx context.Context, data *dto.NameServiceDescription) error {
 	stmt := `INSERT INTO groups (name, description, service_fk)
-			VALUES ($1, $2, (SELECT service_id FROM services WHERE name=$3));`
-	return p.processExecResult(p.pool.ExecEx(ctx, stmt, nil, data.Name, data.Description, data.Service))
+			VALUES ($1, $2, (SELECT service_id FROM services WHERE name=$3 AND deleted_at IS NULL));`
+	err := p.processExecResult(p.pool.ExecEx(ctx, stmt, nil, data.Name, data.Description, data.Service))
+	return p.deletedConflict(ctx, err, "groups", serviceEntityCondition, data.Name, data.Service)
 }
 
-// CreateService добавляет сервис в БД.
+// CreateService добавляет сервис в БД. Если есть удаленный сервис с тем же названием, возвращается ошибка
+// persistent.ErrDeletedEntity: такой сервис нужно восстановить.
 func (p *PostgreSQL) CreateService(ctx context.Context, data *dto.NameDescription) error {
 	stmt := `INSERT INTO services (name, description) VALUES ($1, $2);`
-	return p.processExecResult(p.pool.ExecEx(ctx, stmt, nil, data.Name, data.Description))
+	err := p.processExecResult(p.pool.ExecEx(ctx, stmt, nil, data.Name, data.Description))
+	return p.deletedConflict(ctx, err, "services", "name = $1", data.Name)
 }
 
-// CreateOrUpdateInstance сохраняет/обновляет в БД название экземпляра сервиса и его секретный ключ.
+// CreateOrUpdateInstance сохраняет/обновляет в БД название экземпляра сервиса и его секретный ключ. Если есть удаленный
+// экземпляр с тем же названием, возвращается ошибка persistent.ErrDeletedEntity: такой экземпляр нужно восстановить.
 func (p *PostgreSQL) CreateOrUpdateInstance(ctx context.Context, data *dto.NameServiceSecret) error {
 	cte := `WITH
-			s AS (SELECT instance_id FROM instances WHERE name = $1),
+			s AS (SELECT instance_id FROM instances WHERE name = $1 AND deleted_at IS NULL),
 			i AS (
 				INSERT INTO instances (name, service_fk, secret)
 				SELECT
 					$1,
-					(SELECT service_id FROM services WHERE name = $2),
+					(SELECT service_id FROM services WHERE name = $2 AND deleted_at IS NULL),
 					$3 
 				WHERE NOT EXISTS (SELECT 1 FROM s)
 			)`
@@ -177,26 +339,32 @@ func (p *PostgreSQL) CreateOrUpdateInstance(ctx context.Context, data *dto.NameS
 					WHERE instance_id = (SELECT instance_id FROM s);`
 	_, err := p.pool.ExecEx(ctx, stmt, nil, data.Name, data.Service, data.Secret)
 
-	return adaptErr(err)
+	return p.deletedConflict(ctx, adaptErr(err), "instances", "name = $1", data.Name)
 }
 
-// AssignPermissionToRole назначает роли разрешение.
+// AssignPermissionToRole назначает роли разрешение. Устаревшее разрешение назначить нельзя.
 func (p *PostgreSQL) AssignPermissionToRole(ctx context.Context, data *dto.PermissionRoleService) error {
+	if err := p.checkNotDeprecated(ctx, servicePermissionDeprecatedStmt, data.Permission, data.Service); err != nil {
+		return err
+	}
+
 	stmt := `	INSERT INTO role_permissions(role_fk, permission_fk)
 				VALUES(
 					(SELECT role_id
 					FROM roles
 					WHERE service_fk = (SELECT service_id
 										FROM services
-										WHERE name =$1)
-					  AND name =$2),
+										WHERE name =$1 AND deleted_at IS NULL)
+					  AND name =$2
+					  AND deleted_at IS NULL),
 					   
 					(SELECT permission_id
 					FROM permissions
 					WHERE service_fk = (SELECT service_id
 										FROM services
-										WHERE name =$1)
-					  AND name =$3)
+										WHERE name =$1 AND deleted_at IS NULL)
+					  AND name =$3
+					  AND deleted_at IS NULL)
 				)`
 
 	return p.processExecResult(p.pool.ExecEx(ctx, stmt, nil, data.Service, data.Role, data.Permission))
@@ -210,75 +378,205 @@ func (p *PostgreSQL) AssignRoleToGroup(ctx context.Context, data *dto.GroupRoleS
 					FROM roles
 					WHERE service_fk = (SELECT service_id
 										FROM services
-										WHERE name =$1)
+										WHERE name =$1 AND deleted_at IS NULL)
 					  AND
-					name =$2),
+					name =$2
+					  AND deleted_at IS NULL),
 			
 					(SELECT group_id
 					FROM groups
 					WHERE service_fk = (SELECT service_id
 										FROM services
-										WHERE name =$1)
+										WHERE name =$1 AND deleted_at IS NULL)
 					  AND
-					name =$3)
+					name =$3
+					  AND deleted_at IS NULL)
 				)`
 
 	return p.processExecResult(p.pool.ExecEx(ctx, stmt, nil, data.Service, data.Role, data.Group))
 }
 
-// AssignRoleToAccount назначает роль учетной записи.
+// AssignParentToRole назначает роли родительскую роль того же сервиса, разрешения которой роль наследует. Если
+// родительская роль совпадает с ролью или сама (прямо или через другие роли) наследует её разрешения, возвращается
+// ошибка persistent.ErrRoleCycle.
+func (p *PostgreSQL) AssignParentToRole(ctx context.Context, data *dto.RoleParentService) error {
+	var cycle bool
+
+	cte := `WITH RECURSIVE
+			service_cte AS (SELECT service_id FROM services WHERE name = $1 AND deleted_at IS NULL),
+
+			ancestors (role_fk) AS
+			(SELECT role_id
+			FROM roles
+			WHERE name = $3
+			  AND service_fk = (SELECT service_id FROM service_cte)
+
+			UNION
+
+			SELECT rp.parent_fk
+			FROM role_parents rp
+				JOIN ancestors a ON a.role_fk = rp.role_fk)`
+
+	stmt := cte + `	SELECT EXISTS (SELECT 1
+									FROM ancestors a
+										JOIN roles r ON r.role_id = a.role_fk AND r.deleted_at IS NULL
+									WHERE r.name = $2)`
+	if err := p.pool.QueryRowEx(ctx, stmt, nil, data.Service, data.Role, data.Parent).Scan(&cycle); err != nil {
+		return adaptErr(err)
+	}
+	if cycle {
+		return persistent.ErrRoleCycle
+	}
+
+	stmt = `	INSERT INTO role_parents(role_fk, parent_fk)
+				VALUES(
+					(SELECT role_id
+					FROM roles
+					WHERE service_fk = (SELECT service_id
+										FROM services
+										WHERE name =$1 AND deleted_at IS NULL)
+					  AND
+					name =$2
+					  AND deleted_at IS NULL),
+
+					(SELECT role_id
+					FROM roles
+					WHERE service_fk = (SELECT service_id
+										FROM services
+										WHERE name =$1 AND deleted_at IS NULL)
+					  AND
+					name =$3
+					  AND deleted_at IS NULL)
+				)`
+
+	return p.processExecResult(p.pool.ExecEx(ctx, stmt, nil, data.Service, data.Role, data.Parent))
+}
+
+// AssignSubgroupToGroup вкладывает в группу другую группу того же сервиса. Учетные записи вложенной группы получают
+// роли и разрешения объемлющей группы. Если вложенная группа совпадает с группой или сама (прямо или через другие
+// группы) содержит её, возвращается ошибка persistent.ErrGroupCycle.
+func (p *PostgreSQL) AssignSubgroupToGroup(ctx context.Context, data *dto.GroupSubgroupService) error {
+	var cycle bool
+
+	cte := `WITH RECURSIVE
+			service_cte AS (SELECT service_id FROM services WHERE name = $1 AND deleted_at IS NULL),
+
+			descendants (group_fk) AS
+			(SELECT group_id
+			FROM groups
+			WHERE name = $3
+			  AND service_fk = (SELECT service_id FROM service_cte)
+
+			UNION
+
+			SELECT gs.subgroup_fk
+			FROM group_subgroups gs
+				JOIN descendants d ON d.group_fk = gs.group_fk)`
+
+	stmt := cte + `	SELECT EXISTS (SELECT 1
+									FROM descendants d
+										JOIN groups g ON g.group_id = d.group_fk AND g.deleted_at IS NULL
+									WHERE g.name = $2)`
+	if err := p.pool.QueryRowEx(ctx, stmt, nil, data.Service, data.Group, data.Subgroup).Scan(&cycle); err != nil {
+		return adaptErr(err)
+	}
+	if cycle {
+		return persistent.ErrGroupCycle
+	}
+
+	stmt = `	INSERT INTO group_subgroups(group_fk, subgroup_fk)
+				VALUES(
+					(SELECT group_id
+					FROM groups
+					WHERE service_fk = (SELECT service_id
+										FROM services
+										WHERE name =$1 AND deleted_at IS NULL)
+					  AND
+					name =$2
+					  AND deleted_at IS NULL),
+
+					(SELECT group_id
+					FROM groups
+					WHERE service_fk = (SELECT service_id
+										FROM services
+										WHERE name =$1 AND deleted_at IS NULL)
+					  AND
+					name =$3
+					  AND deleted_at IS NULL)
+				)`
+
+	return p.processExecResult(p.pool.ExecEx(ctx, stmt, nil, data.Service, data.Group, data.Subgroup))
+}
+
+// AssignRoleToAccount назначает роль учетной записи. Если задан период действия назначения, роль действует только в
+// его пределах.
 func (p *PostgreSQL) AssignRoleToAccount(ctx context.Context, data *dto.UserIdRoleService) error {
-	stmt := `	INSERT INTO account_roles(role_fk, account_fk)
+	stmt := `	INSERT INTO account_roles(role_fk, account_fk, valid_from, valid_until, grant_condition)
 				VALUES(
 					(SELECT role_id
 					FROM roles
 					WHERE service_fk = (SELECT service_id
 										FROM services
-										WHERE name =$1) 
+										WHERE name =$1 AND deleted_at IS NULL) 
 					  AND
-					name =$2),
+					name =$2
+					  AND deleted_at IS NULL),
 			
 					(SELECT account_id
 					FROM accounts
-					WHERE uuid = $3)
+					WHERE uuid = $3 AND deleted_at IS NULL),
+
+					$4, $5, $6
 				)`
 
-	return p.processExecResult(p.pool.ExecEx(ctx, stmt, nil, data.Service, data.Role, data.UserId))
+	return p.processExecResult(p.pool.ExecEx(ctx, stmt, nil, data.Service, data.Role, data.UserId, data.ValidFrom,
+		data.ValidUntil, data.Condition))
 }
 
-// AssignGroupToAccount назначает группу учетной записи.
+// AssignGroupToAccount назначает группу учетной записи. Если задан период действия назначения, учетная запись входит
+// в группу только в его пределах.
 func (p *PostgreSQL) AssignGroupToAccount(ctx context.Context, data *dto.UserIdGroupService) error {
-	stmt := `	INSERT INTO account_groups(group_fk, account_fk)
+	stmt := `	INSERT INTO account_groups(group_fk, account_fk, valid_from, valid_until, grant_condition)
 				VALUES(
 					(SELECT group_id
 					FROM groups
 					WHERE service_fk = (SELECT service_id
 										FROM services
-										WHERE name =$1) 
+										WHERE name =$1 AND deleted_at IS NULL) 
 					  AND
-					name =$2),
+					name =$2
+					  AND deleted_at IS NULL),
 			
 					(SELECT account_id
 					FROM accounts
-					WHERE uuid = $3)
+					WHERE uuid = $3 AND deleted_at IS NULL),
+
+					$4, $5, $6
 				)`
 
-	return p.processExecResult(p.pool.ExecEx(ctx, stmt, nil, data.Service, data.Group, data.UserId))
+	return p.processExecResult(p.pool.ExecEx(ctx, stmt, nil, data.Service, data.Group, data.UserId, data.ValidFrom,
+		data.ValidUntil, data.Condition))
 }
 
-// AssignInstancePermissionToAccount прикрепляет разрешение конкретного экземпляра сервиса к учетной записи.
+// AssignInstancePermissionToAccount прикрепляет разрешение конкретного экземпляра сервиса к учетной записи. Устаревшее
+// разрешение назначить нельзя. Если задан период действия назначения, разрешение действует только в его пределах.
 func (p *PostgreSQL) AssignInstancePermissionToAccount(ctx context.Context, data *dto.UserIdInstancePermission) error {
+	if err := p.checkNotDeprecated(ctx, instancePermissionDeprecatedStmt, data.Permission, data.Instance); err != nil {
+		return err
+	}
+
 	cte := `WITH
 			instance_cte AS 
 			(SELECT instance_id, service_fk
 			FROM instances
-			WHERE name = $1)`
+			WHERE name = $1 AND deleted_at IS NULL)`
 
-	stmt := cte + `	INSERT INTO accounts_instances_permissions(account_fk, instance_fk, permission_fk)
+	stmt := cte + `	INSERT INTO accounts_instances_permissions(account_fk, instance_fk, permission_fk, valid_from, valid_until,
+						grant_condition)
 					VALUES(
 						(SELECT account_id
 						FROM accounts
-						WHERE uuid = $2),
+						WHERE uuid = $2 AND deleted_at IS NULL),
 						   
 						(SELECT instance_id
 						FROM instance_cte),
@@ -289,58 +587,112 @@ func (p *PostgreSQL) AssignInstancePermissionToAccount(ctx context.Context, data
 							AND
 						service_fk IN (SELECT service_fk
 										FROM instance_cte)
-						)
+						  AND deleted_at IS NULL),
+
+						$4, $5, $6
 					)`
 
-	return p.processExecResult(p.pool.ExecEx(ctx, stmt, nil, data.Instance, data.UserId, data.Permission))
+	return p.processExecResult(p.pool.ExecEx(ctx, stmt, nil, data.Instance, data.UserId, data.Permission,
+		data.ValidFrom, data.ValidUntil, data.Condition))
 }
 
-// AssignPermissionToGroup назначает разрешения группе.
+// AssignInstanceRoleToAccount назначает учетной записи роль, которая действует только для одного экземпляра сервиса.
+// Роль должна принадлежать сервису экземпляра. Если задан период действия назначения, роль действует только в его
+// пределах.
+func (p *PostgreSQL) AssignInstanceRoleToAccount(ctx context.Context, data *dto.UserIdInstanceRole) error {
+	stmt := `	INSERT INTO account_instance_roles(account_fk, instance_fk, role_fk, valid_from, valid_until, grant_condition)
+				VALUES(
+					(SELECT account_id
+					FROM accounts
+					WHERE uuid = $1 AND deleted_at IS NULL),
+
+					(SELECT instance_id
+					FROM instances
+					WHERE name = $2 AND deleted_at IS NULL),
+
+					(SELECT role_id
+					FROM roles
+					WHERE service_fk = (SELECT service_fk
+										FROM instances
+										WHERE name = $2 AND deleted_at IS NULL)
+					  AND
+					name = $3
+					  AND deleted_at IS NULL),
+
+					$4, $5, $6
+				)`
+
+	return p.processExecResult(p.pool.ExecEx(ctx, stmt, nil, data.UserId, data.Instance, data.Role, data.ValidFrom,
+		data.ValidUntil, data.Condition))
+}
+
+// AssignInstanceGroupToAccount назначает учетной записи группу, роли и разрешения которой действуют только для одного
+// экземпляра сервиса. Группа должна принадлежать сервису экземпляра. Если задан период действия назначения, учетная
+// запись входит в группу только в его пределах.
+func (p *PostgreSQL) AssignInstanceGroupToAccount(ctx context.Context, data *dto.UserIdInstanceGroup) error {
+	stmt := `	INSERT INTO account_instance_groups(account_fk, instance_fk, group_fk, valid_from, valid_until, grant_condition)
+				VALUES(
+					(SELECT account_id
+					FROM accounts
+					WHERE uuid = $1 AND deleted_at IS NULL),
+
+					(SELECT instance_id
+					FROM instances
+					WHERE name = $2 AND deleted_at IS NULL),
+
+					(SELECT group_id
+					FROM groups
+					WHERE service_fk = (SELECT service_fk
+										FROM instances
+										WHERE name = $2 AND deleted_at IS NULL)
+					  AND
+					name = $3
+					  AND deleted_at IS NULL),
+
+					$4, $5, $6
+				)`
+
+	return p.processExecResult(p.pool.ExecEx(ctx, stmt, nil, data.UserId, data.Instance, data.Group, data.ValidFrom,
+		data.ValidUntil, data.Condition))
+}
+
+// AssignPermissionToGroup назначает разрешения группе. Устаревшее разрешение назначить нельзя.
 func (p *PostgreSQL) AssignPermissionToGroup(ctx context.Context, data *dto.GroupPermissionService) error {
+	if err := p.checkNotDeprecated(ctx, servicePermissionDeprecatedStmt, data.Permission, data.Service); err != nil {
+		return err
+	}
+
 	stmt := `	INSERT INTO group_permissions(group_fk, permission_fk)
 				VALUES(
 					(SELECT group_id
 					FROM groups
 					WHERE service_fk = (SELECT service_id
 										FROM services
-										WHERE name =$1)
+										WHERE name =$1 AND deleted_at IS NULL)
 					  AND
-					name =$2),
+					name =$2
+					  AND deleted_at IS NULL),
 			
 					(SELECT permission_id
 					FROM permissions
 					WHERE service_fk = (SELECT service_id
 										FROM services
-										WHERE name =$1)
+										WHERE name =$1 AND deleted_at IS NULL)
 					  AND
-					name =$3)
+					name =$3
+					  AND deleted_at IS NULL)
 				)`
 
 	return p.processExecResult(p.pool.ExecEx(ctx, stmt, nil, data.Service, data.Group, data.Permission))
 }
 
-// InstancePermissionsForAccount возвращает название, номер и описание разрешений аккаунта для экземпляра сервиса.
+// InstancePermissionsForAccount возвращает название, номер и описание разрешений аккаунта для экземпляра сервиса:
+// назначенных для экземпляра напрямую, через роли экземпляра и через группы экземпляра.
 func (p *PostgreSQL) InstancePermissionsForAccount(ctx context.Context, data *dto.UserIdInstance) ([]dto.NameNumberDescription, error) {
-	cte := `WITH account_cte AS
-			(SELECT account_id
-			FROM accounts
-			WHERE uuid = $1)`
-
-	stmt := cte + `	SELECT name, number, description
+	stmt := instancePermissionsStmt + `	SELECT name, number, description
 					FROM permissions
-					WHERE permission_id IN
-						(
-						SELECT permission_fk
-						FROM accounts_instances_permissions
-						WHERE account_fk = (SELECT account_id
-											FROM account_cte)
-				
-						 AND
-						instance_fk IN
-							(SELECT instance_id
-							FROM instances
-							WHERE name = $2)
-						)
+					WHERE permission_id IN (SELECT permission_fk FROM instance_permissions)
+					  AND deleted_at IS NULL
 				
 					ORDER BY number`
 
@@ -369,28 +721,13 @@ func (p *PostgreSQL) InstancePermissionsForAccount(ctx context.Context, data *dt
 	return result, nil
 }
 
-// InstancePermissionsNumbersForAccount возвращает номера разрешений аккаунта для экземпляра сервиса.
+// InstancePermissionsNumbersForAccount возвращает номера разрешений аккаунта для экземпляра сервиса: назначенных для
+// экземпляра напрямую, через роли экземпляра и через группы экземпляра.
 func (p *PostgreSQL) InstancePermissionsNumbersForAccount(ctx context.Context, data *dto.UserIdInstance) ([]int, error) {
-	cte := `WITH account_cte AS
-			(SELECT account_id
-			FROM accounts
-			WHERE uuid = $1)`
-
-	stmt := cte + `	SELECT number
+	stmt := instancePermissionsStmt + `	SELECT number
 					FROM permissions
-					WHERE permission_id IN
-						(
-						SELECT permission_fk
-						FROM accounts_instances_permissions
-						WHERE account_fk = (SELECT account_id
-											FROM account_cte)
-				
-						  AND
-						instance_fk IN
-							(SELECT instance_id
-							FROM instances
-							WHERE name = $2)
-						)
+					WHERE permission_id IN (SELECT permission_fk FROM instance_permissions)
+					  AND deleted_at IS NULL
 				
 					ORDER BY number`
 
@@ -420,30 +757,35 @@ func (p *PostgreSQL) InstancePermissionsNumbersForAccount(ctx context.Context, d
 // ServicePermissionsForAccount возвращает название, номер и описание разрешений аккаунта для сервиса (без разрешений
 // для экземпляра).
 func (p *PostgreSQL) ServicePermissionsForAccount(ctx context.Context, data *dto.UserIdService) ([]dto.NameNumberDescription, error) {
-	cte := `WITH
+	cte := `WITH RECURSIVE
 			account_cte AS
 			(SELECT account_id
 			FROM accounts
-			WHERE uuid = $1),
+			WHERE uuid = $1 AND deleted_at IS NULL),
 			
 			groups_cte AS
-			(SELECT group_fk
-			FROM account_groups
-			WHERE account_fk = (SELECT account_id
-								FROM account_cte)),
+			(SELECT DISTINCT group_fk
+			FROM group_closure),
 
 			service_cte AS
 			(SELECT service_id
 			FROM services
-			WHERE name = $2)`
+			WHERE name = $2 AND deleted_at IS NULL),
+
+			` + roleClosureCTE + `,
+
+			` + groupClosureCTE + `,
+
+			` + globalRolesCTE
 
 	stmt := cte + `	SELECT name, number, description
 					FROM permissions
 					WHERE permission_id IN
 						(
-						SELECT permission_fk
-						FROM role_permissions
-						WHERE role_fk IN
+						SELECT rp.permission_fk
+						FROM role_permissions rp
+							JOIN role_closure c ON c.inherited_fk = rp.role_fk
+						WHERE c.role_fk IN
 							(
 							SELECT role_fk
 							FROM group_roles
@@ -454,6 +796,12 @@ func (p *PostgreSQL) ServicePermissionsForAccount(ctx context.Context, data *dto
 							SELECT role_fk
 							FROM account_roles
 							WHERE account_fk = (SELECT account_id FROM account_cte)
+							  AND ` + activeGrantCondition + `
+
+							UNION
+
+							SELECT role_fk
+							FROM global_roles
 							)
 				
 						UNION
@@ -465,6 +813,7 @@ func (p *PostgreSQL) ServicePermissionsForAccount(ctx context.Context, data *dto
 				
 					  AND
 					service_fk = (SELECT service_id FROM service_cte)
+					  AND deleted_at IS NULL
 					
 					ORDER BY number`
 
@@ -496,30 +845,35 @@ func (p *PostgreSQL) ServicePermissionsForAccount(ctx context.Context, data *dto
 // ServicePermissionsNumbersForAccount возвращает номера разрешений аккаунта для сервиса (без разрешений для
 // экземпляра).
 func (p *PostgreSQL) ServicePermissionsNumbersForAccount(ctx context.Context, data *dto.UserIdService) ([]int, error) {
-	cte := `WITH
+	cte := `WITH RECURSIVE
 			account_cte AS
 			(SELECT account_id
 			FROM accounts
-			WHERE uuid = $1),
+			WHERE uuid = $1 AND deleted_at IS NULL),
 
 			groups_cte AS
-			(SELECT group_fk
-			FROM account_groups
-			WHERE account_fk = (SELECT account_id
-								FROM account_cte)),
+			(SELECT DISTINCT group_fk
+			FROM group_closure),
 
 			service_cte AS
 			(SELECT service_id
 			FROM services
-			WHERE name = $2)`
+			WHERE name = $2 AND deleted_at IS NULL),
+
+			` + roleClosureCTE + `,
+
+			` + groupClosureCTE + `,
+
+			` + globalRolesCTE
 
 	stmt := cte + `	SELECT number
 					FROM permissions
 					WHERE permission_id IN
 						(
-						SELECT permission_fk
-						FROM role_permissions
-						WHERE role_fk IN
+						SELECT rp.permission_fk
+						FROM role_permissions rp
+							JOIN role_closure c ON c.inherited_fk = rp.role_fk
+						WHERE c.role_fk IN
 							(
 							SELECT role_fk
 							FROM group_roles
@@ -535,6 +889,12 @@ func (p *PostgreSQL) ServicePermissionsNumbersForAccount(ctx context.Context, da
 							FROM account_roles
 							WHERE account_fk = (SELECT account_id
 												FROM account_cte)
+							  AND ` + activeGrantCondition + `
+
+							UNION
+
+							SELECT role_fk
+							FROM global_roles
 							)
 				
 						UNION
@@ -550,6 +910,7 @@ func (p *PostgreSQL) ServicePermissionsNumbersForAccount(ctx context.Context, da
 					  AND
 					service_fk = (SELECT service_id
 								FROM service_cte)
+					  AND deleted_at IS NULL
 					
 					ORDER BY number`
 
@@ -577,6 +938,255 @@ func (p *PostgreSQL) ServicePermissionsNumbersForAccount(ctx context.Context, da
 	return result, nil
 }
 
+// ServicePermissionsSourcesForAccount возвращает номера разрешений аккаунта для сервиса (без разрешений для
+// экземпляра) вместе с путём, по которому назначено каждое из них: роль аккаунта (grant_source.Role), группа аккаунта
+// (grant_source.Group), роль группы аккаунта (grant_source.GroupRole) или роль глобальной группы аккаунта
+// (grant_source.GlobalGroupRole). Разрешения, унаследованные ролью от родительских ролей, относятся к тому же пути, что
+// и сама роль, а разрешения объемлющих групп - к тому же пути, что и группа аккаунта. Разрешение, назначенное
+// несколькими путями, возвращается для каждого из них.
+func (p *PostgreSQL) ServicePermissionsSourcesForAccount(ctx context.Context, data *dto.UserIdService) ([]dto.NumberSource, error) {
+	cte := `WITH RECURSIVE
+			account_cte AS
+			(SELECT account_id
+			FROM accounts
+			WHERE uuid = $1 AND deleted_at IS NULL),
+
+			service_cte AS
+			(SELECT service_id
+			FROM services
+			WHERE name = $2 AND deleted_at IS NULL),
+
+			` + roleClosureCTE + `,
+
+			` + groupClosureCTE + `,
+
+			` + globalRolesCTE
+
+	stmt := cte + `	SELECT p.number, $3::TEXT
+					FROM account_roles ar
+						JOIN role_closure c ON c.role_fk = ar.role_fk
+						JOIN role_permissions rp ON rp.role_fk = c.inherited_fk
+						JOIN permissions p ON p.permission_id = rp.permission_fk AND p.deleted_at IS NULL
+					WHERE ar.account_fk = (SELECT account_id FROM account_cte)
+					  AND ` + activeGrantCondition + `
+					  AND p.service_fk = (SELECT service_id FROM service_cte)
+
+					UNION
+
+					SELECT p.number, $4::TEXT
+					FROM group_closure ag
+						JOIN group_permissions gp ON gp.group_fk = ag.group_fk
+						JOIN permissions p ON p.permission_id = gp.permission_fk AND p.deleted_at IS NULL
+					WHERE p.service_fk = (SELECT service_id FROM service_cte)
+
+					UNION
+
+					SELECT p.number, $5::TEXT
+					FROM group_closure ag
+						JOIN group_roles gr ON gr.group_fk = ag.group_fk
+						JOIN role_closure c ON c.role_fk = gr.role_fk
+						JOIN role_permissions rp ON rp.role_fk = c.inherited_fk
+						JOIN permissions p ON p.permission_id = rp.permission_fk AND p.deleted_at IS NULL
+					WHERE p.service_fk = (SELECT service_id FROM service_cte)
+
+					UNION
+
+					SELECT p.number, $6::TEXT
+					FROM global_roles gr
+						JOIN role_closure c ON c.role_fk = gr.role_fk
+						JOIN role_permissions rp ON rp.role_fk = c.inherited_fk
+						JOIN permissions p ON p.permission_id = rp.permission_fk AND p.deleted_at IS NULL
+					WHERE p.service_fk = (SELECT service_id FROM service_cte)
+
+					ORDER BY 1, 2`
+
+	rows, err := p.pool.QueryEx(ctx, stmt, nil, data.UserId, data.Service,
+		grant_source.Role, grant_source.Group, grant_source.GroupRole, grant_source.GlobalGroupRole)
+	defer rows.Close()
+
+	if err != nil {
+		return nil, adaptErr(err)
+	}
+
+	result := make([]dto.NumberSource, 0)
+	var source dto.NumberSource
+
+	for rows.Next() {
+		if err = rows.Scan(&source.Number, &source.Source); err != nil {
+			return result, adaptErr(err)
+		}
+		result = append(result, source)
+	}
+
+	if err = rows.Err(); err != nil {
+		return result, adaptErr(err)
+	}
+
+	return result, nil
+}
+
+// PermissionsGrantPathsForAccount возвращает разрешения аккаунта для сервиса вместе со всеми путями, по которым они
+// назначены: роль аккаунта, группа аккаунта, роль группы или глобальной группы аккаунта (с названиями групп и ролей).
+// Для разрешений, унаследованных от родительских ролей, путь содержит цепочку наследования, а для разрешений
+// объемлющих групп - цепочку групп, в которые вложена группа аккаунта. Если передано название экземпляра сервиса,
+// дополнительно возвращаются разрешения, назначенные аккаунту для экземпляра напрямую, через роли и группы экземпляра.
+// Для путей, начинающихся с назначения с условием, возвращается текст условия.
+func (p *PostgreSQL) PermissionsGrantPathsForAccount(ctx context.Context, data *dto.UserIdServiceInstance) ([]dto.NumberNameGrantPath, error) {
+	cte := `WITH RECURSIVE
+			account_cte AS
+			(SELECT account_id
+			FROM accounts
+			WHERE uuid = $1 AND deleted_at IS NULL),
+
+			service_cte AS
+			(SELECT service_id
+			FROM services
+			WHERE name = $2 AND deleted_at IS NULL),
+
+			instance_cte AS
+			(SELECT instance_id, name
+			FROM instances
+			WHERE name = $3
+			  AND service_fk = (SELECT service_id FROM service_cte)
+			  AND deleted_at IS NULL),
+
+			` + roleClosureCTE + `,
+
+			` + groupClosureCTE + `,
+
+			` + instanceGroupClosureCTE + `,
+
+			` + conditionalGroupClosureCTE + `,
+
+			` + globalRolesCTE
+
+	stmt := cte + `	SELECT p.number, p.name, $4::TEXT, i.name, '', ARRAY[]::TEXT[], '', '', ARRAY[]::TEXT[], '', ''
+					FROM accounts_instances_permissions aip
+						JOIN instances i ON i.instance_id = aip.instance_fk AND i.deleted_at IS NULL
+						JOIN permissions p ON p.permission_id = aip.permission_fk AND p.deleted_at IS NULL
+					WHERE aip.account_fk = (SELECT account_id FROM account_cte)
+					  AND ` + activeGrantCondition + `
+					  AND i.name = $3
+					  AND i.service_fk = (SELECT service_id FROM service_cte)
+
+					UNION
+
+					SELECT p.number, p.name, $5::TEXT, '', '', ARRAY[]::TEXT[], '', r.name, c.chain, '', ''
+					FROM account_roles ar
+						JOIN roles r ON r.role_id = ar.role_fk AND r.deleted_at IS NULL
+						JOIN role_closure c ON c.role_fk = ar.role_fk
+						JOIN role_permissions rp ON rp.role_fk = c.inherited_fk
+						JOIN permissions p ON p.permission_id = rp.permission_fk AND p.deleted_at IS NULL
+					WHERE ar.account_fk = (SELECT account_id FROM account_cte)
+					  AND ` + activeGrantCondition + `
+					  AND p.service_fk = (SELECT service_id FROM service_cte)
+
+					UNION
+
+					SELECT p.number, p.name, $6::TEXT, '', g.name, ag.chain, '', '', ARRAY[]::TEXT[], '', ''
+					FROM group_closure ag
+						JOIN groups g ON g.group_id = ag.member_fk AND g.deleted_at IS NULL
+						JOIN group_permissions gp ON gp.group_fk = ag.group_fk
+						JOIN permissions p ON p.permission_id = gp.permission_fk AND p.deleted_at IS NULL
+					WHERE p.service_fk = (SELECT service_id FROM service_cte)
+
+					UNION
+
+					SELECT p.number, p.name, $7::TEXT, '', g.name, ag.chain, '', r.name, c.chain, '', ''
+					FROM group_closure ag
+						JOIN groups g ON g.group_id = ag.member_fk AND g.deleted_at IS NULL
+						JOIN group_roles gr ON gr.group_fk = ag.group_fk
+						JOIN roles r ON r.role_id = gr.role_fk AND r.deleted_at IS NULL
+						JOIN role_closure c ON c.role_fk = gr.role_fk
+						JOIN role_permissions rp ON rp.role_fk = c.inherited_fk
+						JOIN permissions p ON p.permission_id = rp.permission_fk AND p.deleted_at IS NULL
+					WHERE p.service_fk = (SELECT service_id FROM service_cte)
+
+					UNION
+
+					SELECT p.number, p.name, $8::TEXT, '', '', ARRAY[]::TEXT[], gr.global_group, r.name, c.chain, '', ''
+					FROM global_roles gr
+						JOIN roles r ON r.role_id = gr.role_fk AND r.deleted_at IS NULL
+						JOIN role_closure c ON c.role_fk = gr.role_fk
+						JOIN role_permissions rp ON rp.role_fk = c.inherited_fk
+						JOIN permissions p ON p.permission_id = rp.permission_fk AND p.deleted_at IS NULL
+					WHERE p.service_fk = (SELECT service_id FROM service_cte)
+
+					UNION
+
+					SELECT p.number, p.name, $9::TEXT, $3::TEXT, '', ARRAY[]::TEXT[], '', r.name, c.chain, '', ''
+					FROM account_instance_roles air
+						JOIN roles r ON r.role_id = air.role_fk AND r.deleted_at IS NULL
+						JOIN role_closure c ON c.role_fk = air.role_fk
+						JOIN role_permissions rp ON rp.role_fk = c.inherited_fk
+						JOIN permissions p ON p.permission_id = rp.permission_fk AND p.deleted_at IS NULL
+					WHERE air.account_fk = (SELECT account_id FROM account_cte)
+					  AND air.instance_fk = (SELECT instance_id FROM instance_cte)
+					  AND ` + activeGrantCondition + `
+
+					UNION
+
+					SELECT p.number, p.name, $10::TEXT, $3::TEXT, g.name, ag.chain, '', '', ARRAY[]::TEXT[], '', ''
+					FROM instance_group_closure ag
+						JOIN groups g ON g.group_id = ag.member_fk AND g.deleted_at IS NULL
+						JOIN group_permissions gp ON gp.group_fk = ag.group_fk
+						JOIN permissions p ON p.permission_id = gp.permission_fk AND p.deleted_at IS NULL
+
+					UNION
+
+					SELECT p.number, p.name, $11::TEXT, $3::TEXT, g.name, ag.chain, '', r.name, c.chain, '', ''
+					FROM instance_group_closure ag
+						JOIN groups g ON g.group_id = ag.member_fk AND g.deleted_at IS NULL
+						JOIN group_roles gr ON gr.group_fk = ag.group_fk
+						JOIN roles r ON r.role_id = gr.role_fk AND r.deleted_at IS NULL
+						JOIN role_closure c ON c.role_fk = gr.role_fk
+						JOIN role_permissions rp ON rp.role_fk = c.inherited_fk
+						JOIN permissions p ON p.permission_id = rp.permission_fk AND p.deleted_at IS NULL
+
+					UNION
+
+					SELECT p.number, p.name, $12::TEXT, $3::TEXT, '', ARRAY[]::TEXT[], '', '', ARRAY[]::TEXT[], asp.selector, ''
+					FROM account_selector_permissions asp
+						JOIN permissions p ON p.permission_id = asp.permission_fk AND p.deleted_at IS NULL
+					WHERE asp.account_fk = (SELECT account_id FROM account_cte)
+					  AND p.service_fk = (SELECT service_id FROM service_cte)
+					  AND EXISTS (SELECT 1 FROM instance_cte)
+					  AND ` + selectorMatchCondition + `
+
+					UNION
+
+					` + conditionalGrantPathsStmt + `
+
+					ORDER BY 1, 3, 4, 5, 6, 7, 8, 9, 10, 11`
+
+	rows, err := p.pool.QueryEx(ctx, stmt, nil, data.UserId, data.Service, data.Instance,
+		grant_source.Instance, grant_source.Role, grant_source.Group, grant_source.GroupRole, grant_source.GlobalGroupRole,
+		grant_source.InstanceRole, grant_source.InstanceGroup, grant_source.InstanceGroupRole, grant_source.Selector)
+	defer rows.Close()
+
+	if err != nil {
+		return nil, adaptErr(err)
+	}
+
+	result := make([]dto.NumberNameGrantPath, 0)
+	var row dto.NumberNameGrantPath
+
+	for rows.Next() {
+		if err = rows.Scan(&row.Number, &row.Name, &row.GrantPath.Source, &row.GrantPath.Instance, &row.GrantPath.Group,
+			&row.GrantPath.ParentGroups, &row.GrantPath.GlobalGroup, &row.GrantPath.Role, &row.GrantPath.InheritedFrom,
+			&row.GrantPath.Selector, &row.GrantPath.Condition); err != nil {
+			return result, adaptErr(err)
+		}
+		result = append(result, row)
+	}
+
+	if err = rows.Err(); err != nil {
+		return result, adaptErr(err)
+	}
+
+	return result, nil
+}
+
 // PermissionNumber возвращает номер разрешения для заданного экземпляра сервиса.
 func (p *PostgreSQL) PermissionNumber(ctx context.Context, name, instance string) (int, error) {
 	var number int
@@ -586,7 +1196,8 @@ func (p *PostgreSQL) PermissionNumber(ctx context.Context, name, instance string
 				  AND
 				service_fk = (SELECT service_fk
 							FROM instances
-							WHERE name =$2)`
+							WHERE name =$2 AND deleted_at IS NULL)
+				  AND deleted_at IS NULL`
 
 	row := p.pool.QueryRowEx(ctx, stmt, nil, name, instance)
 	if err := row.Scan(&number); err != nil {
@@ -602,7 +1213,8 @@ func (p *PostgreSQL) ServiceNumberedPermissions(ctx context.Context, serviceName
 				FROM permissions
 				WHERE service_fk = (SELECT service_id
 							FROM services
-							WHERE name =$1)
+							WHERE name =$1 AND deleted_at IS NULL)
+				  AND deleted_at IS NULL
 				ORDER BY number`
 
 	rows, err := p.pool.QueryEx(ctx, stmt, nil, serviceName)
@@ -636,7 +1248,7 @@ func (p *PostgreSQL) ServiceNumberedPermissions(ctx context.Context, serviceName
 
 // AccountsLoginsByState возвращает список логинов пользователей с переданным функции состоянием.
 func (p *PostgreSQL) AccountsLoginsByState(ctx context.Context, state account_state.State) ([]loginVO.Login, error) {
-	stmt := `SELECT login FROM accounts WHERE state = $1`
+	stmt := `SELECT login FROM accounts WHERE state = $1 AND deleted_at IS NULL`
 
 	rows, err := p.pool.QueryEx(ctx, stmt, nil, state)
 	defer rows.Close()
@@ -667,7 +1279,7 @@ func (p *PostgreSQL) AccountsLoginsByState(ctx context.Context, state account_st
 // соответствующим экземпляром сервиса.
 func (p *PostgreSQL) InstanceSecret(ctx context.Context, name string) (string, error) {
 	var secret string
-	stmt := `SELECT secret FROM instances WHERE name = $1`
+	stmt := `SELECT secret FROM instances WHERE name = $1 AND deleted_at IS NULL`
 
 	row := p.pool.QueryRowEx(ctx, stmt, nil, name)
 	if err := row.Scan(&secret); err != nil {
@@ -684,7 +1296,7 @@ func (p *PostgreSQL) ServiceName(ctx context.Context, instanceName string) (stri
 				FROM services
 				WHERE service_id = (SELECT service_fk
 									FROM instances
-									WHERE name = $1)`
+									WHERE name = $1 AND deleted_at IS NULL)`
 
 	row := p.pool.QueryRowEx(ctx, stmt, nil, instanceName)
 	if err := row.Scan(&name); err != nil {
@@ -694,9 +1306,28 @@ func (p *PostgreSQL) ServiceName(ctx context.Context, instanceName string) (stri
 	return name, nil
 }
 
+// ServicePermissionEncoding возвращает способ кодирования номеров разрешений в токенах сервиса.
+func (p *PostgreSQL) ServicePermissionEncoding(ctx context.Context, serviceName string) (string, error) {
+	var encoding string
+	stmt := `SELECT permission_encoding FROM services WHERE name = $1 AND deleted_at IS NULL`
+
+	row := p.pool.QueryRowEx(ctx, stmt, nil, serviceName)
+	if err := row.Scan(&encoding); err != nil {
+		return "", adaptErr(err)
+	}
+
+	return encoding, nil
+}
+
+// SetServicePermissionEncoding устанавливает способ кодирования номеров разрешений в токенах сервиса.
+func (p *PostgreSQL) SetServicePermissionEncoding(ctx context.Context, data *dto.NameEncoding) error {
+	stmt := `UPDATE services SET permission_encoding = $1 WHERE name = $2 AND deleted_at IS NULL`
+	return p.processExecResult(p.pool.ExecEx(ctx, stmt, nil, data.Encoding, data.Name))
+}
+
 // ServicesNames возвращает названия всех сервисов в БД.
 func (p *PostgreSQL) ServicesNames(ctx context.Context) ([]string, error) {
-	stmt := `SELECT name FROM services`
+	stmt := `SELECT name FROM services WHERE deleted_at IS NULL`
 
 	rows, err := p.pool.QueryEx(ctx, stmt, nil)
 	defer rows.Close()
@@ -723,20 +1354,299 @@ func (p *PostgreSQL) ServicesNames(ctx context.Context) ([]string, error) {
 	return result, nil
 }
 
-// DeleteRole удаляет роль из БД.
-func (p *PostgreSQL) DeleteRole(ctx context.Context, data *dto.NameService) error {
-	stmt := `DELETE FROM roles WHERE name = $1 AND service_fk = (SELECT service_id FROM services WHERE name = $2)`
-	return p.processExecResult(p.pool.ExecEx(ctx, stmt, nil, data.Name, data.Service))
-}
+const (
+	// grantPeriodCondition условие действия назначения учетной записи (роли, группы или разрешения для экземпляра):
+	// текущее время входит в необязательный период valid_from - valid_until. Применимо в запросах, где столбцы периода
+	// есть только у одной таблицы назначений.
+	grantPeriodCondition = `(valid_from IS NULL OR valid_from <= now()) AND (valid_until IS NULL OR valid_until > now())`
+
+	// activeGrantCondition условие действия назначения учетной записи без условия grant_condition. Назначения с условием
+	// не кешируются и учитываются только при выдаче токена, когда известны атрибуты запроса.
+	activeGrantCondition = grantPeriodCondition + ` AND grant_condition = ''`
+
+	// conditionalGrantCondition условие действия назначения учетной записи с условием grant_condition.
+	conditionalGrantCondition = grantPeriodCondition + ` AND grant_condition <> ''`
+
+	// conditionalGroupClosureCTE рекурсивное общее табличное выражение conditional_group_closure (group_fk, member_fk,
+	// instance, grant_condition, chain, visited), аналогичное group_closure, для групп, в которые учетная запись
+	// account_cte входит по действующему назначению с условием: во всех экземплярах сервиса (instance пусто) или только
+	// в экземпляре instance_cte. Требует объявления account_cte, instance_cte и ключевого слова RECURSIVE в запросе.
+	conditionalGroupClosureCTE = `conditional_group_closure (group_fk, member_fk, instance, grant_condition, chain, visited) AS
+			(SELECT group_fk, group_fk, '', grant_condition, ARRAY[]::TEXT[], ARRAY[group_fk]
+			FROM account_groups
+			WHERE account_fk = (SELECT account_id FROM account_cte)
+			  AND ` + conditionalGrantCondition + `
+			  AND group_fk IN (SELECT group_id FROM groups WHERE deleted_at IS NULL)
+
+			UNION ALL
+
+			SELECT group_fk, group_fk, (SELECT name FROM instance_cte), grant_condition, ARRAY[]::TEXT[], ARRAY[group_fk]
+			FROM account_instance_groups
+			WHERE account_fk = (SELECT account_id FROM account_cte)
+			  AND instance_fk = (SELECT instance_id FROM instance_cte)
+			  AND ` + conditionalGrantCondition + `
+			  AND group_fk IN (SELECT group_id FROM groups WHERE deleted_at IS NULL)
+
+			UNION ALL
+
+			SELECT gs.group_fk, c.member_fk, c.instance, c.grant_condition, c.chain || g.name::TEXT, c.visited || gs.group_fk
+			FROM conditional_group_closure c
+				JOIN group_subgroups gs ON gs.subgroup_fk = c.group_fk
+				JOIN groups g ON g.group_id = gs.group_fk AND g.deleted_at IS NULL
+			WHERE NOT gs.group_fk = ANY(c.visited))`
+
+	// conditionalGrantPathsStmt запрос путей назначения разрешений сервиса service_cte учетной записи account_cte по
+	// действующим назначениям с условием. Столбцы совпадают со столбцами запроса PermissionsGrantPathsForAccount: номер и
+	// название разрешения, способ назначения, экземпляр, группа, цепочка объемлющих групп, глобальная группа, роль,
+	// цепочка наследования ролей, селектор (всегда пустой) и условие. Требует объявления account_cte, service_cte,
+	// instance_cte (instance_id, name), role_closure и conditional_group_closure.
+	conditionalGrantPathsStmt = `SELECT p.number, p.name, '` + grant_source.Instance + `', i.name, '', ARRAY[]::TEXT[], '', '',
+						ARRAY[]::TEXT[], '', aip.grant_condition
+					FROM accounts_instances_permissions aip
+						JOIN instances i ON i.instance_id = aip.instance_fk AND i.deleted_at IS NULL
+						JOIN permissions p ON p.permission_id = aip.permission_fk AND p.deleted_at IS NULL
+					WHERE aip.account_fk = (SELECT account_id FROM account_cte)
+					  AND aip.instance_fk = (SELECT instance_id FROM instance_cte)
+					  AND ` + conditionalGrantCondition + `
+
+					UNION
+
+					SELECT p.number, p.name, '` + grant_source.Role + `', '', '', ARRAY[]::TEXT[], '', r.name, c.chain,
+						'', ar.grant_condition
+					FROM account_roles ar
+						JOIN roles r ON r.role_id = ar.role_fk AND r.deleted_at IS NULL
+						JOIN role_closure c ON c.role_fk = ar.role_fk
+						JOIN role_permissions rp ON rp.role_fk = c.inherited_fk
+						JOIN permissions p ON p.permission_id = rp.permission_fk AND p.deleted_at IS NULL
+					WHERE ar.account_fk = (SELECT account_id FROM account_cte)
+					  AND ` + conditionalGrantCondition + `
+
+					UNION
+
+					SELECT p.number, p.name, '` + grant_source.InstanceRole + `', (SELECT name FROM instance_cte), '',
+						ARRAY[]::TEXT[], '', r.name, c.chain, '', air.grant_condition
+					FROM account_instance_roles air
+						JOIN roles r ON r.role_id = air.role_fk AND r.deleted_at IS NULL
+						JOIN role_closure c ON c.role_fk = air.role_fk
+						JOIN role_permissions rp ON rp.role_fk = c.inherited_fk
+						JOIN permissions p ON p.permission_id = rp.permission_fk AND p.deleted_at IS NULL
+					WHERE air.account_fk = (SELECT account_id FROM account_cte)
+					  AND air.instance_fk = (SELECT instance_id FROM instance_cte)
+					  AND ` + conditionalGrantCondition + `
+
+					UNION
+
+					SELECT p.number, p.name,
+						CASE WHEN ag.instance = '' THEN '` + grant_source.Group + `' ELSE '` + grant_source.InstanceGroup + `' END,
+						ag.instance, g.name, ag.chain, '', '', ARRAY[]::TEXT[], '', ag.grant_condition
+					FROM conditional_group_closure ag
+						JOIN groups g ON g.group_id = ag.member_fk AND g.deleted_at IS NULL
+						JOIN group_permissions gp ON gp.group_fk = ag.group_fk
+						JOIN permissions p ON p.permission_id = gp.permission_fk AND p.deleted_at IS NULL
+					WHERE p.service_fk = (SELECT service_id FROM service_cte)
+
+					UNION
+
+					SELECT p.number, p.name,
+						CASE WHEN ag.instance = '' THEN '` + grant_source.GroupRole + `' ELSE '` + grant_source.InstanceGroupRole + `' END,
+						ag.instance, g.name, ag.chain, '', r.name, c.chain, '', ag.grant_condition
+					FROM conditional_group_closure ag
+						JOIN groups g ON g.group_id = ag.member_fk AND g.deleted_at IS NULL
+						JOIN group_roles gr ON gr.group_fk = ag.group_fk
+						JOIN roles r ON r.role_id = gr.role_fk AND r.deleted_at IS NULL
+						JOIN role_closure c ON c.role_fk = gr.role_fk
+						JOIN role_permissions rp ON rp.role_fk = c.inherited_fk
+						JOIN permissions p ON p.permission_id = rp.permission_fk AND p.deleted_at IS NULL
+					WHERE p.service_fk = (SELECT service_id FROM service_cte)
+
+					UNION
+
+					SELECT p.number, p.name, '` + grant_source.GlobalGroupRole + `', '', '', ARRAY[]::TEXT[], gg.name, r.name,
+						c.chain, '', agg.grant_condition
+					FROM account_global_groups agg
+						JOIN global_groups gg ON gg.global_group_id = agg.global_group_fk
+						JOIN global_group_roles ggr ON ggr.global_group_fk = agg.global_group_fk
+						JOIN roles r ON r.role_id = ggr.role_fk AND r.deleted_at IS NULL
+						JOIN role_closure c ON c.role_fk = ggr.role_fk
+						JOIN role_permissions rp ON rp.role_fk = c.inherited_fk
+						JOIN permissions p ON p.permission_id = rp.permission_fk AND p.deleted_at IS NULL
+					WHERE agg.account_fk = (SELECT account_id FROM account_cte)
+					  AND ` + conditionalGrantCondition
+
+	// roleClosureCTE рекурсивное общее табличное выражение role_closure (role_fk, inherited_fk, chain, visited): каждая
+	// роль сервиса service_cte в паре с самой собой и с каждой ролью, разрешения которой она наследует через
+	// родительские роли. В chain перечисляются названия ролей на пути наследования без исходной роли, visited защищает от
+	// зацикливания. Требует объявления service_cte и ключевого слова RECURSIVE в запросе.
+	roleClosureCTE = `role_closure (role_fk, inherited_fk, chain, visited) AS
+			(SELECT role_id, role_id, ARRAY[]::TEXT[], ARRAY[role_id]
+			FROM roles
+			WHERE service_fk = (SELECT service_id FROM service_cte)
+			  AND deleted_at IS NULL
+
+			UNION ALL
+
+			SELECT c.role_fk, rp.parent_fk, c.chain || r.name::TEXT, c.visited || rp.parent_fk
+			FROM role_closure c
+				JOIN role_parents rp ON rp.role_fk = c.inherited_fk
+				JOIN roles r ON r.role_id = rp.parent_fk AND r.deleted_at IS NULL
+			WHERE NOT rp.parent_fk = ANY(c.visited))`
+
+	// groupClosureCTE рекурсивное общее табличное выражение group_closure (group_fk, member_fk, chain, visited): каждая
+	// группа, в которую учетная запись account_cte входит по действующему назначению напрямую или через вложенные
+	// группы, в паре с группой учетной записи (member_fk), через которую она получена. В chain перечисляются названия
+	// объемлющих групп на пути вложения без группы учетной записи, visited защищает от зацикливания. Требует объявления
+	// account_cte и ключевого слова RECURSIVE в запросе.
+	groupClosureCTE = `group_closure (group_fk, member_fk, chain, visited) AS
+			(SELECT group_fk, group_fk, ARRAY[]::TEXT[], ARRAY[group_fk]
+			FROM account_groups
+			WHERE account_fk = (SELECT account_id FROM account_cte)
+			  AND ` + activeGrantCondition + `
+			  AND group_fk IN (SELECT group_id FROM groups WHERE deleted_at IS NULL)
+
+			UNION ALL
+
+			SELECT gs.group_fk, c.member_fk, c.chain || g.name::TEXT, c.visited || gs.group_fk
+			FROM group_closure c
+				JOIN group_subgroups gs ON gs.subgroup_fk = c.group_fk
+				JOIN groups g ON g.group_id = gs.group_fk AND g.deleted_at IS NULL
+			WHERE NOT gs.group_fk = ANY(c.visited))`
+
+	// instanceGroupClosureCTE рекурсивное общее табличное выражение instance_group_closure (group_fk, member_fk, chain,
+	// visited), аналогичное group_closure, для групп, в которые учетная запись account_cte входит по действующему
+	// назначению только в экземпляре instance_cte. Требует объявления account_cte, instance_cte и ключевого слова
+	// RECURSIVE в запросе.
+	instanceGroupClosureCTE = `instance_group_closure (group_fk, member_fk, chain, visited) AS
+			(SELECT group_fk, group_fk, ARRAY[]::TEXT[], ARRAY[group_fk]
+			FROM account_instance_groups
+			WHERE account_fk = (SELECT account_id FROM account_cte)
+			  AND instance_fk = (SELECT instance_id FROM instance_cte)
+			  AND ` + activeGrantCondition + `
+			  AND group_fk IN (SELECT group_id FROM groups WHERE deleted_at IS NULL)
+
+			UNION ALL
+
+			SELECT gs.group_fk, c.member_fk, c.chain || g.name::TEXT, c.visited || gs.group_fk
+			FROM instance_group_closure c
+				JOIN group_subgroups gs ON gs.subgroup_fk = c.group_fk
+				JOIN groups g ON g.group_id = gs.group_fk AND g.deleted_at IS NULL
+			WHERE NOT gs.group_fk = ANY(c.visited))`
+
+	// selectorMatchCondition условие соответствия атрибутов экземпляра instance_cte селектору назначения asp: каждая пара
+	// название=значение селектора есть среди атрибутов экземпляра. Требует объявления instance_cte в запросе.
+	selectorMatchCondition = `NOT EXISTS (SELECT 1
+							  FROM regexp_split_to_table(asp.selector, ',') term
+							  WHERE term NOT IN (SELECT name || '=' || value
+												 FROM instance_attributes
+												 WHERE instance_fk = (SELECT instance_id FROM instance_cte)))`
+
+	// instancePermissionsStmt начало запроса с общим табличным выражением instance_permissions (permission_fk):
+	// разрешения, назначенные учетной записи $1 для экземпляра $2 напрямую, по селектору атрибутов экземпляра, через
+	// роли экземпляра и через группы экземпляра с учетом наследования ролей и вложенности групп.
+	instancePermissionsStmt = `WITH RECURSIVE
+			account_cte AS
+			(SELECT account_id
+			FROM accounts
+			WHERE uuid = $1 AND deleted_at IS NULL),
 
-// DeleteGroup удаляет группу из БД.
-func (p *PostgreSQL) DeleteGroup(ctx context.Context, data *dto.NameService) error {
-	stmt := `DELETE FROM groups WHERE name = $1 AND service_fk = (SELECT service_id FROM services WHERE name = $2)`
-	return p.processExecResult(p.pool.ExecEx(ctx, stmt, nil, data.Name, data.Service))
-}
+			instance_cte AS
+			(SELECT instance_id, service_fk
+			FROM instances
+			WHERE name = $2 AND deleted_at IS NULL),
 
-// DeletePermission удаляет разрешение из БД.
-func (p *PostgreSQL) DeletePermission(ctx context.Context, data *dto.NameService) error {
-	stmt := `DELETE FROM permissions WHERE name = $1 AND service_fk = (SELECT service_id FROM services WHERE name = $2)`
-	return p.processExecResult(p.pool.ExecEx(ctx, stmt, nil, data.Name, data.Service))
+			service_cte AS
+			(SELECT service_fk AS service_id
+			FROM instance_cte),
+
+			` + roleClosureCTE + `,
+
+			` + instanceGroupClosureCTE + `,
+
+			instance_permissions (permission_fk) AS
+			(SELECT permission_fk
+			FROM accounts_instances_permissions
+			WHERE account_fk = (SELECT account_id FROM account_cte)
+			  AND instance_fk = (SELECT instance_id FROM instance_cte)
+			  AND ` + activeGrantCondition + `
+
+			UNION
+
+			SELECT asp.permission_fk
+			FROM account_selector_permissions asp
+				JOIN permissions p ON p.permission_id = asp.permission_fk AND p.deleted_at IS NULL
+			WHERE asp.account_fk = (SELECT account_id FROM account_cte)
+			  AND p.service_fk = (SELECT service_fk FROM instance_cte)
+			  AND ` + selectorMatchCondition + `
+
+			UNION
+
+			SELECT rp.permission_fk
+			FROM role_permissions rp
+				JOIN role_closure c ON c.inherited_fk = rp.role_fk
+			WHERE c.role_fk IN
+				(
+				SELECT role_fk
+				FROM account_instance_roles
+				WHERE account_fk = (SELECT account_id FROM account_cte)
+				  AND instance_fk = (SELECT instance_id FROM instance_cte)
+				  AND ` + activeGrantCondition + `
+
+				UNION
+
+				SELECT role_fk
+				FROM group_roles
+				WHERE group_fk IN (SELECT group_fk FROM instance_group_closure)
+				)
+
+			UNION
+
+			SELECT permission_fk
+			FROM group_permissions
+			WHERE group_fk IN (SELECT group_fk FROM instance_group_closure))
+
+			`
+
+	// globalRolesCTE общее табличное выражение global_roles (role_fk, global_group): роли всех сервисов, входящие в
+	// глобальные группы, которые назначены учетной записи account_cte и действуют, вместе с названием глобальной группы.
+	// Требует объявления account_cte в запросе.
+	globalRolesCTE = `global_roles (role_fk, global_group) AS
+			(SELECT ggr.role_fk, gg.name
+			FROM account_global_groups agg
+				JOIN global_groups gg ON gg.global_group_id = agg.global_group_fk
+				JOIN global_group_roles ggr ON ggr.global_group_fk = agg.global_group_fk
+			WHERE agg.account_fk = (SELECT account_id FROM account_cte)
+			  AND ` + activeGrantCondition + `)`
+
+	// servicePermissionDeprecatedStmt возвращает признак устаревания разрешения $1 сервиса $2.
+	servicePermissionDeprecatedStmt = `SELECT deprecated
+			FROM permissions
+			WHERE name = $1
+			  AND service_fk = (SELECT service_id FROM services WHERE name = $2 AND deleted_at IS NULL)
+			  AND deleted_at IS NULL`
+
+	// instancePermissionDeprecatedStmt возвращает признак устаревания разрешения $1 сервиса, к которому относится
+	// экземпляр $2.
+	instancePermissionDeprecatedStmt = `SELECT deprecated
+			FROM permissions
+			WHERE name = $1
+			  AND service_fk = (SELECT service_fk FROM instances WHERE name = $2 AND deleted_at IS NULL)
+			  AND deleted_at IS NULL`
+)
+
+// checkNotDeprecated возвращает ошибку persistent.ErrDeprecatedPermission, если выбранное запросом stmt разрешение
+// устарело. Отсутствие разрешения ошибкой не считается: его обработает последующий запрос на назначение.
+func (p *PostgreSQL) checkNotDeprecated(ctx context.Context, stmt string, args ...interface{}) error {
+	var deprecated bool
+
+	if err := p.pool.QueryRowEx(ctx, stmt, nil, args...).Scan(&deprecated); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil
+		}
+		return adaptErrSkipFrames(err, 3)
+	}
+
+	if deprecated {
+		return persistent.ErrDeprecatedPermission
+	}
+
+	return nil
 }
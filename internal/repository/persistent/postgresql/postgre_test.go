@@ -6,6 +6,9 @@ import (
 	"github.com/google/uuid"
 	storageConfig "github.com/lazylex/watch-store/secure/internal/config"
 	"github.com/lazylex/watch-store/secure/internal/domain/value_objects/account_state"
+	"github.com/lazylex/watch-store/secure/internal/domain/value_objects/account_type"
+	"github.com/lazylex/watch-store/secure/internal/domain/value_objects/grant_source"
+	"github.com/lazylex/watch-store/secure/internal/domain/value_objects/permission_encoding"
 	"github.com/lazylex/watch-store/secure/internal/dto"
 	"github.com/lazylex/watch-store/secure/internal/errors/persistent"
 	"log/slog"
@@ -13,6 +16,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 const configFilename = "local.yaml"
@@ -77,6 +81,80 @@ func TestPostgreSQL_SetAndGetAccountLoginData(t *testing.T) {
 	}
 }
 
+func TestPostgreSQL_SetAccountPasswordHash(t *testing.T) {
+	p := postgreSQL(t)
+	ctx := context.Background()
+
+	data := dto.UserIdLoginHashState{
+		Login:  "test_user",
+		UserId: uuid.New(),
+		Hash:   "$2a$14$qXnQ8n9U0FItXkto3Sf8XuvZny48y4iZLTluWZtZszTrc7REdzUAy",
+		State:  account_state.Enabled,
+	}
+
+	if p.SetAccountLoginData(ctx, &data) != nil {
+		t.Fatal()
+	}
+
+	data.Hash = "$2a$14$Ne17rB21.iXHWug6wuB80ethQ.vWrViWXpPFpUotkA8pkxAGqyAj2"
+	if p.SetAccountPasswordHash(ctx, &dto.UserIdHash{UserId: data.UserId, Hash: data.Hash}) != nil {
+		t.Fatal()
+	}
+
+	dataFromDB, err := p.AccountLoginDataByUserId(ctx, data.UserId)
+	if err != nil || data != dataFromDB {
+		t.Fail()
+	}
+}
+
+func TestPostgreSQL_TOTP(t *testing.T) {
+	p := postgreSQL(t)
+	ctx := context.Background()
+	id := uuid.New()
+	code := "5d41402abc4b2a76b9719d911017c592ae5e2d0a1f6d3e1b1c9f2b7b8e6a4c3d"
+
+	if p.SetAccountLoginData(ctx, &dto.UserIdLoginHashState{
+		Login:  "test_user",
+		UserId: id,
+		Hash:   "$2a$14$qXnQ8n9U0FItXkto3Sf8XuvZny48y4iZLTluWZtZszTrc7REdzUAy",
+		State:  account_state.Enabled,
+	}) != nil {
+		t.Fatal()
+	}
+
+	if p.SetTOTPSecret(ctx, &dto.UserIdSecret{UserId: id, Secret: "JBSWY3DPEHPK3PXP"}) != nil {
+		t.Fatal()
+	}
+
+	if p.EnableTOTP(ctx, &dto.UserIdCodes{UserId: id, Codes: []string{code}}) != nil {
+		t.Fatal()
+	}
+
+	if state, err := p.TOTPSecret(ctx, id); err != nil || !state.Enabled || state.Secret != "JBSWY3DPEHPK3PXP" {
+		t.Fatal()
+	}
+
+	if p.SetTOTPSecret(ctx, &dto.UserIdSecret{UserId: id, Secret: "KRSXG5CTMVRXEZLU"}) == nil {
+		t.Fatal("enabled secret must not be overwritten")
+	}
+
+	if p.UseRecoveryCode(ctx, &dto.UserIdCode{UserId: id, Code: code}) != nil {
+		t.Fatal()
+	}
+
+	if !errors.Is(p.UseRecoveryCode(ctx, &dto.UserIdCode{UserId: id, Code: code}), persistent.ErrZeroRowsAffected) {
+		t.Fatal()
+	}
+
+	if p.DeleteTOTP(ctx, id) != nil {
+		t.Fatal()
+	}
+
+	if _, err := p.TOTPSecret(ctx, id); !errors.Is(err, persistent.ErrNoRowsInResultSet) {
+		t.Fail()
+	}
+}
+
 func TestPostgreSQL_ErrGetAccountLoginData(t *testing.T) {
 	p := postgreSQL(t)
 
@@ -142,15 +220,15 @@ func TestPostgreSQL_BigTest(t *testing.T) {
 		t.Fatal()
 	}
 
-	if p.CreatePermission(ctx, &dto.NameServiceDescription{Name: "perm1", Description: "p1", Service: "service1"}) != nil {
+	if p.CreatePermission(ctx, &dto.NameNumberDescriptionService{Name: "perm1", Description: "p1", Service: "service1"}) != nil {
 		t.Fatal()
 	}
 
-	if p.CreatePermission(ctx, &dto.NameServiceDescription{Name: "perm2", Description: "p2", Service: "service1"}) != nil {
+	if p.CreatePermission(ctx, &dto.NameNumberDescriptionService{Name: "perm2", Description: "p2", Service: "service1"}) != nil {
 		t.Fatal()
 	}
 
-	if p.CreatePermission(ctx, &dto.NameServiceDescription{Name: "perm3", Description: "p3", Service: "service1"}) != nil {
+	if p.CreatePermission(ctx, &dto.NameNumberDescriptionService{Name: "perm3", Description: "p3", Service: "service1"}) != nil {
 		t.Fatal()
 	}
 
@@ -251,6 +329,17 @@ func TestPostgreSQL_BigTest(t *testing.T) {
 		t.Fatal()
 	}
 
+	if paths, err := p.PermissionsGrantPathsForAccount(ctx, &dto.UserIdServiceInstance{
+		UserId:   userId,
+		Service:  "service1",
+		Instance: "instance1",
+	}); err != nil || len(paths) != 3 ||
+		paths[0].Number != 1 || paths[0].GrantPath.Source != grant_source.Group || paths[0].GrantPath.Group != "group1" ||
+		paths[1].Number != 2 || paths[1].GrantPath.Source != grant_source.GroupRole || paths[1].GrantPath.Role != "role1" ||
+		paths[2].Number != 3 || paths[2].GrantPath.Source != grant_source.Instance || paths[2].GrantPath.Instance != "instance1" {
+		t.Fatal()
+	}
+
 	if p.SetAccountState(ctx, &dto.LoginState{Login: "test_user", State: account_state.Disabled}) != nil {
 		t.Fatal()
 	}
@@ -284,3 +373,795 @@ func TestPostgreSQL_BigTest(t *testing.T) {
 		t.Fatal()
 	}
 }
+
+func TestPostgreSQL_OIDCClient(t *testing.T) {
+	p := postgreSQL(t)
+	ctx := context.Background()
+	client := dto.OIDCClient{
+		ClientId:     "test_client",
+		Name:         "Test client",
+		RedirectURIs: []string{"https://client.example/callback", "http://localhost:8080/callback"},
+	}
+
+	if p.CreateOIDCClient(ctx, &client) != nil {
+		t.Fatal()
+	}
+
+	result, err := p.OIDCClient(ctx, client.ClientId)
+	if err != nil || result.Name != client.Name || len(result.SecretHash) != 0 || len(result.RedirectURIs) != 2 ||
+		result.RedirectURIs[1] != client.RedirectURIs[1] {
+		t.Fatal()
+	}
+
+	if p.CreateOIDCClient(ctx, &client) == nil {
+		t.Fatal("client id must be unique")
+	}
+}
+
+func TestPostgreSQL_ReadModel(t *testing.T) {
+	p := postgreSQL(t)
+	ctx := context.Background()
+
+	for _, name := range []string{"alpha", "beta", "gamma"} {
+		if p.CreateService(ctx, &dto.NameDescription{Name: name, Description: name + " description"}) != nil {
+			t.Fatal()
+		}
+	}
+
+	if services, err := p.Services(ctx, &dto.ListQuery{Filter: "A", After: "alpha", Limit: 10}); err != nil ||
+		len(services) != 2 || services[0].Name != "beta" || services[1].Description != "gamma description" {
+		t.Fatal()
+	}
+
+	if services, err := p.Services(ctx, &dto.ListQuery{Limit: 2, Descending: true}); err != nil ||
+		len(services) != 2 || services[0].Name != "gamma" {
+		t.Fatal()
+	}
+
+	if p.CreateRole(ctx, &dto.NameServiceDescription{Name: "Продавец", Service: "alpha"}) != nil ||
+		p.CreateGroup(ctx, &dto.NameServiceDescription{Name: "Персонал магазина", Service: "alpha"}) != nil ||
+		p.CreatePermission(ctx, &dto.NameNumberDescriptionService{Name: "sell", Service: "alpha"}) != nil ||
+		p.AssignPermissionToRole(ctx, &dto.PermissionRoleService{Permission: "sell", Role: "Продавец", Service: "alpha"}) != nil ||
+		p.AssignRoleToGroup(ctx, &dto.GroupRoleService{Group: "Персонал магазина", Role: "Продавец", Service: "alpha"}) != nil {
+		t.Fatal()
+	}
+
+	if roles, err := p.Roles(ctx, &dto.ListQuery{Service: "alpha", Limit: 10}); err != nil || len(roles) != 1 {
+		t.Fatal()
+	}
+
+	if role, err := p.RoleDetails(ctx, &dto.NameService{Name: "Продавец", Service: "alpha"}); err != nil ||
+		len(role.Permissions) != 1 || role.Permissions[0].Name != "sell" {
+		t.Fatal()
+	}
+
+	if group, err := p.GroupDetails(ctx, &dto.NameService{Name: "Персонал магазина", Service: "alpha"}); err != nil ||
+		len(group.Roles) != 1 || group.Roles[0] != "Продавец" || len(group.Permissions) != 0 {
+		t.Fatal()
+	}
+
+	if _, err := p.RoleDetails(ctx, &dto.NameService{Name: "Продавец", Service: "beta"}); err == nil {
+		t.Fatal()
+	}
+
+	userId := uuid.New()
+	if p.SetAccountLoginData(ctx, &dto.UserIdLoginHashState{Login: "reader", UserId: userId, Hash: "hash", State: account_state.Enabled}) != nil ||
+		p.AssignGroupToAccount(ctx, &dto.UserIdGroupService{UserId: userId, Group: "Персонал магазина", Service: "alpha"}) != nil {
+		t.Fatal()
+	}
+
+	if accounts, err := p.Accounts(ctx, &dto.ListQuery{Filter: "read", Limit: 10}); err != nil || len(accounts) != 1 || accounts[0].UserId != userId {
+		t.Fatal()
+	}
+
+	if account, err := p.AccountDetails(ctx, userId); err != nil || account.Login != "reader" || len(account.Groups) != 1 ||
+		account.Groups[0].Service != "alpha" || len(account.Roles) != 0 {
+		t.Fatal()
+	}
+
+	if details, err := p.ServiceDetails(ctx, "alpha"); err != nil || len(details.Roles) != 1 || len(details.Groups) != 1 ||
+		len(details.Permissions) != 1 || len(details.Instances) != 0 {
+		t.Fatal()
+	}
+}
+
+func TestPostgreSQL_PermissionNumbers(t *testing.T) {
+	p := postgreSQL(t)
+	ctx := context.Background()
+
+	if p.CreateService(ctx, &dto.NameDescription{Name: "store"}) != nil ||
+		p.CreateRole(ctx, &dto.NameServiceDescription{Name: "clerk", Service: "store"}) != nil ||
+		p.CreatePermission(ctx, &dto.NameNumberDescriptionService{Name: "read", Service: "store"}) != nil ||
+		p.CreatePermission(ctx, &dto.NameNumberDescriptionService{Name: "sell", Number: 5, Service: "store"}) != nil ||
+		p.CreatePermission(ctx, &dto.NameNumberDescriptionService{Name: "refund", Service: "store"}) != nil {
+		t.Fatal()
+	}
+
+	if !errors.Is(p.CreatePermission(ctx, &dto.NameNumberDescriptionService{Name: "write", Number: 5, Service: "store"}),
+		persistent.ErrDuplicateKeyValue) {
+		t.Fatal()
+	}
+
+	if p.DeletePermission(ctx, &dto.NameService{Name: "refund", Service: "store"}) != nil {
+		t.Fatal()
+	}
+
+	if !errors.Is(p.CreatePermission(ctx, &dto.NameNumberDescriptionService{Name: "refund", Number: 6, Service: "store"}),
+		persistent.ErrRetiredNumber) {
+		t.Fatal()
+	}
+
+	if p.PurgeDeleted(ctx, time.Now().Add(time.Minute)) != nil {
+		t.Fatal()
+	}
+
+	if p.CreatePermission(ctx, &dto.NameNumberDescriptionService{Name: "refund", Service: "store"}) != nil ||
+		p.DeprecatePermission(ctx, &dto.NameService{Name: "read", Service: "store"}) != nil {
+		t.Fatal()
+	}
+
+	if !errors.Is(p.AssignPermissionToRole(ctx, &dto.PermissionRoleService{Permission: "read", Role: "clerk", Service: "store"}),
+		persistent.ErrDeprecatedPermission) {
+		t.Fatal()
+	}
+
+	details, err := p.ServiceDetails(ctx, "store")
+	if err != nil || len(details.Permissions) != 3 || len(details.RetiredNumbers) != 1 || details.RetiredNumbers[0] != 6 {
+		t.Fatal()
+	}
+
+	expected := []dto.NameNumberDescriptionDeprecated{
+		{Name: "read", Number: 1, Deprecated: true},
+		{Name: "sell", Number: 5},
+		{Name: "refund", Number: 7},
+	}
+	for i := range expected {
+		if details.Permissions[i] != expected[i] {
+			t.Fail()
+		}
+	}
+}
+
+func TestPostgreSQL_RoleHierarchy(t *testing.T) {
+	p := postgreSQL(t)
+	ctx := context.Background()
+	userId := uuid.New()
+
+	if p.CreateService(ctx, &dto.NameDescription{Name: "store"}) != nil ||
+		p.CreateRole(ctx, &dto.NameServiceDescription{Name: "seller", Service: "store"}) != nil ||
+		p.CreateRole(ctx, &dto.NameServiceDescription{Name: "manager", Service: "store"}) != nil ||
+		p.CreateRole(ctx, &dto.NameServiceDescription{Name: "director", Service: "store"}) != nil ||
+		p.CreatePermission(ctx, &dto.NameNumberDescriptionService{Name: "sell", Service: "store"}) != nil ||
+		p.CreatePermission(ctx, &dto.NameNumberDescriptionService{Name: "refund", Service: "store"}) != nil ||
+		p.AssignPermissionToRole(ctx, &dto.PermissionRoleService{Permission: "sell", Role: "seller", Service: "store"}) != nil ||
+		p.AssignPermissionToRole(ctx, &dto.PermissionRoleService{Permission: "refund", Role: "manager", Service: "store"}) != nil ||
+		p.AssignParentToRole(ctx, &dto.RoleParentService{Role: "manager", Parent: "seller", Service: "store"}) != nil ||
+		p.AssignParentToRole(ctx, &dto.RoleParentService{Role: "director", Parent: "manager", Service: "store"}) != nil {
+		t.Fatal()
+	}
+
+	for _, parent := range []string{"director", "seller"} {
+		if !errors.Is(p.AssignParentToRole(ctx, &dto.RoleParentService{Role: "seller", Parent: parent, Service: "store"}),
+			persistent.ErrRoleCycle) {
+			t.Fatal()
+		}
+	}
+
+	if p.SetAccountLoginData(ctx, &dto.UserIdLoginHashState{Login: "director", UserId: userId, State: account_state.Enabled,
+		Hash: "$2a$14$qXnQ8n9U0FItXkto3Sf8XuvZny48y4iZLTluWZtZszTrc7REdzUAy"}) != nil ||
+		p.AssignRoleToAccount(ctx, &dto.UserIdRoleService{UserId: userId, Role: "director", Service: "store"}) != nil {
+		t.Fatal()
+	}
+
+	numbers, err := p.ServicePermissionsNumbersForAccount(ctx, &dto.UserIdService{UserId: userId, Service: "store"})
+	if err != nil || len(numbers) != 2 || numbers[0] != 1 || numbers[1] != 2 {
+		t.Fatal()
+	}
+
+	if ok, err := p.AccountHasRole(ctx, &dto.UserIdRoleService{UserId: userId, Role: "seller", Service: "store"}); err != nil || !ok {
+		t.Fatal()
+	}
+
+	paths, err := p.PermissionsGrantPathsForAccount(ctx, &dto.UserIdServiceInstance{UserId: userId, Service: "store"})
+	if err != nil || len(paths) != 2 || paths[0].GrantPath.Role != "director" || len(paths[0].GrantPath.InheritedFrom) != 2 ||
+		paths[0].GrantPath.InheritedFrom[1] != "seller" {
+		t.Fatal()
+	}
+
+	if details, err := p.RoleDetails(ctx, &dto.NameService{Name: "director", Service: "store"}); err != nil ||
+		len(details.Parents) != 1 || details.Parents[0] != "manager" {
+		t.Fail()
+	}
+}
+
+func TestPostgreSQL_NestedGroups(t *testing.T) {
+	p := postgreSQL(t)
+	ctx := context.Background()
+	userId := uuid.New()
+
+	if p.CreateService(ctx, &dto.NameDescription{Name: "store"}) != nil ||
+		p.CreateGroup(ctx, &dto.NameServiceDescription{Name: "staff", Service: "store"}) != nil ||
+		p.CreateGroup(ctx, &dto.NameServiceDescription{Name: "cashiers", Service: "store"}) != nil ||
+		p.CreateGroup(ctx, &dto.NameServiceDescription{Name: "night", Service: "store"}) != nil ||
+		p.CreateRole(ctx, &dto.NameServiceDescription{Name: "seller", Service: "store"}) != nil ||
+		p.CreatePermission(ctx, &dto.NameNumberDescriptionService{Name: "sell", Service: "store"}) != nil ||
+		p.CreatePermission(ctx, &dto.NameNumberDescriptionService{Name: "open", Service: "store"}) != nil ||
+		p.AssignPermissionToRole(ctx, &dto.PermissionRoleService{Permission: "sell", Role: "seller", Service: "store"}) != nil ||
+		p.AssignRoleToGroup(ctx, &dto.GroupRoleService{Group: "staff", Role: "seller", Service: "store"}) != nil ||
+		p.AssignPermissionToGroup(ctx, &dto.GroupPermissionService{Group: "cashiers", Permission: "open", Service: "store"}) != nil ||
+		p.AssignSubgroupToGroup(ctx, &dto.GroupSubgroupService{Group: "staff", Subgroup: "cashiers", Service: "store"}) != nil ||
+		p.AssignSubgroupToGroup(ctx, &dto.GroupSubgroupService{Group: "cashiers", Subgroup: "night", Service: "store"}) != nil {
+		t.Fatal()
+	}
+
+	for _, subgroup := range []string{"staff", "night"} {
+		if !errors.Is(p.AssignSubgroupToGroup(ctx, &dto.GroupSubgroupService{Group: "night", Subgroup: subgroup, Service: "store"}),
+			persistent.ErrGroupCycle) {
+			t.Fatal()
+		}
+	}
+
+	if p.SetAccountLoginData(ctx, &dto.UserIdLoginHashState{Login: "night", UserId: userId, State: account_state.Enabled,
+		Hash: "$2a$14$qXnQ8n9U0FItXkto3Sf8XuvZny48y4iZLTluWZtZszTrc7REdzUAy"}) != nil ||
+		p.AssignGroupToAccount(ctx, &dto.UserIdGroupService{UserId: userId, Group: "night", Service: "store"}) != nil {
+		t.Fatal()
+	}
+
+	numbers, err := p.ServicePermissionsNumbersForAccount(ctx, &dto.UserIdService{UserId: userId, Service: "store"})
+	if err != nil || len(numbers) != 2 || numbers[0] != 1 || numbers[1] != 2 {
+		t.Fatal()
+	}
+
+	if ok, err := p.AccountHasRole(ctx, &dto.UserIdRoleService{UserId: userId, Role: "seller", Service: "store"}); err != nil || !ok {
+		t.Fatal()
+	}
+
+	accounts, err := p.GroupAccounts(ctx, &dto.NameService{Name: "staff", Service: "store"})
+	if err != nil || len(accounts) != 1 || accounts[0] != userId {
+		t.Fatal()
+	}
+
+	paths, err := p.PermissionsGrantPathsForAccount(ctx, &dto.UserIdServiceInstance{UserId: userId, Service: "store"})
+	if err != nil || len(paths) != 2 || paths[0].GrantPath.Group != "night" || len(paths[0].GrantPath.ParentGroups) != 2 ||
+		paths[0].GrantPath.ParentGroups[1] != "staff" || paths[0].GrantPath.Role != "seller" {
+		t.Fatal()
+	}
+
+	if details, err := p.GroupDetails(ctx, &dto.NameService{Name: "staff", Service: "store"}); err != nil ||
+		len(details.Subgroups) != 1 || details.Subgroups[0] != "cashiers" {
+		t.Fail()
+	}
+}
+
+func TestPostgreSQL_TimeBoundGrants(t *testing.T) {
+	p := postgreSQL(t)
+	ctx := context.Background()
+	userId := uuid.New()
+	expired, until, future := time.Now().Add(-time.Hour), time.Now().Add(time.Hour), time.Now().Add(2*time.Hour)
+
+	if p.CreateService(ctx, &dto.NameDescription{Name: "store"}) != nil ||
+		p.CreateOrUpdateInstance(ctx, &dto.NameServiceSecret{Name: "store-1", Service: "store", Secret: "secret"}) != nil ||
+		p.CreateRole(ctx, &dto.NameServiceDescription{Name: "seller", Service: "store"}) != nil ||
+		p.CreateRole(ctx, &dto.NameServiceDescription{Name: "contractor", Service: "store"}) != nil ||
+		p.CreateRole(ctx, &dto.NameServiceDescription{Name: "night", Service: "store"}) != nil ||
+		p.CreatePermission(ctx, &dto.NameNumberDescriptionService{Name: "sell", Service: "store"}) != nil ||
+		p.CreatePermission(ctx, &dto.NameNumberDescriptionService{Name: "repair", Service: "store"}) != nil ||
+		p.CreatePermission(ctx, &dto.NameNumberDescriptionService{Name: "open", Service: "store"}) != nil ||
+		p.AssignPermissionToRole(ctx, &dto.PermissionRoleService{Permission: "sell", Role: "seller", Service: "store"}) != nil ||
+		p.AssignPermissionToRole(ctx, &dto.PermissionRoleService{Permission: "repair", Role: "contractor", Service: "store"}) != nil ||
+		p.AssignPermissionToRole(ctx, &dto.PermissionRoleService{Permission: "open", Role: "night", Service: "store"}) != nil ||
+		p.SetAccountLoginData(ctx, &dto.UserIdLoginHashState{Login: "contractor", UserId: userId, State: account_state.Enabled,
+			Hash: "$2a$14$qXnQ8n9U0FItXkto3Sf8XuvZny48y4iZLTluWZtZszTrc7REdzUAy"}) != nil ||
+		p.AssignRoleToAccount(ctx, &dto.UserIdRoleService{UserId: userId, Role: "seller", Service: "store", ValidUntil: &expired}) != nil ||
+		p.AssignRoleToAccount(ctx, &dto.UserIdRoleService{UserId: userId, Role: "contractor", Service: "store", ValidUntil: &until}) != nil ||
+		p.AssignRoleToAccount(ctx, &dto.UserIdRoleService{UserId: userId, Role: "night", Service: "store", ValidFrom: &until,
+			ValidUntil: &future}) != nil {
+		t.Fatal()
+	}
+
+	numbers, err := p.ServicePermissionsNumbersForAccount(ctx, &dto.UserIdService{UserId: userId, Service: "store"})
+	if err != nil || len(numbers) != 1 || numbers[0] != 2 {
+		t.Fatal()
+	}
+
+	if ok, err := p.AccountHasRole(ctx, &dto.UserIdRoleService{UserId: userId, Role: "seller", Service: "store"}); err != nil || ok {
+		t.Fatal()
+	}
+
+	expiration, err := p.AccountGrantsExpiration(ctx, &dto.UserIdInstance{UserId: userId, Instance: "store-1"})
+	if err != nil || expiration.Sub(until).Abs() > time.Millisecond {
+		t.Fatal()
+	}
+
+	changed, err := p.DeleteExpiredGrants(ctx, time.Now())
+	if err != nil || len(changed) != 1 || changed[0].UserId != userId || changed[0].Service != "store" {
+		t.Fatal()
+	}
+
+	if changed, err = p.DeleteExpiredGrants(ctx, time.Now()); err != nil || len(changed) != 0 {
+		t.Fail()
+	}
+}
+
+func TestPostgreSQL_GlobalGroups(t *testing.T) {
+	p := postgreSQL(t)
+	ctx := context.Background()
+	userId := uuid.New()
+
+	for _, service := range []string{"store", "stock"} {
+		if p.CreateService(ctx, &dto.NameDescription{Name: service}) != nil ||
+			p.CreateRole(ctx, &dto.NameServiceDescription{Name: "orders", Service: service}) != nil ||
+			p.CreatePermission(ctx, &dto.NameNumberDescriptionService{Name: "read", Service: service}) != nil ||
+			p.AssignPermissionToRole(ctx, &dto.PermissionRoleService{Permission: "read", Role: "orders", Service: service}) != nil {
+			t.Fatal()
+		}
+	}
+
+	if p.CreateGlobalGroup(ctx, &dto.NameDescription{Name: "order service"}) != nil ||
+		p.AssignRoleToGlobalGroup(ctx, &dto.GlobalGroupRoleService{GlobalGroup: "order service", Role: "orders", Service: "store"}) != nil ||
+		p.AssignRoleToGlobalGroup(ctx, &dto.GlobalGroupRoleService{GlobalGroup: "order service", Role: "orders", Service: "stock"}) != nil ||
+		p.SetAccountLoginData(ctx, &dto.UserIdLoginHashState{Login: "orders", UserId: userId, State: account_state.Enabled,
+			Hash: "$2a$14$qXnQ8n9U0FItXkto3Sf8XuvZny48y4iZLTluWZtZszTrc7REdzUAy"}) != nil ||
+		p.AssignGlobalGroupToAccount(ctx, &dto.UserIdGlobalGroup{UserId: userId, GlobalGroup: "order service"}) != nil {
+		t.Fatal()
+	}
+
+	for _, service := range []string{"store", "stock"} {
+		numbers, err := p.ServicePermissionsNumbersForAccount(ctx, &dto.UserIdService{UserId: userId, Service: service})
+		if err != nil || len(numbers) != 1 || numbers[0] != 1 {
+			t.Fatal()
+		}
+
+		if ok, err := p.AccountHasRole(ctx, &dto.UserIdRoleService{UserId: userId, Role: "orders", Service: service}); err != nil || !ok {
+			t.Fatal()
+		}
+	}
+
+	sources, err := p.ServicePermissionsSourcesForAccount(ctx, &dto.UserIdService{UserId: userId, Service: "stock"})
+	if err != nil || len(sources) != 1 || sources[0].Source != grant_source.GlobalGroupRole {
+		t.Fatal()
+	}
+
+	paths, err := p.PermissionsGrantPathsForAccount(ctx, &dto.UserIdServiceInstance{UserId: userId, Service: "store"})
+	if err != nil || len(paths) != 1 || paths[0].GrantPath.GlobalGroup != "order service" || paths[0].GrantPath.Role != "orders" {
+		t.Fatal()
+	}
+
+	if services, err := p.GlobalGroupServices(ctx, "order service"); err != nil || len(services) != 2 || services[0] != "stock" {
+		t.Fatal()
+	}
+
+	if accounts, err := p.GlobalGroupAccounts(ctx, "order service"); err != nil || len(accounts) != 1 || accounts[0] != userId {
+		t.Fatal()
+	}
+
+	if p.DeleteGlobalGroup(ctx, "order service") != nil {
+		t.Fatal()
+	}
+
+	if numbers, err := p.ServicePermissionsNumbersForAccount(ctx, &dto.UserIdService{UserId: userId, Service: "store"}); err != nil ||
+		len(numbers) != 0 {
+		t.Fail()
+	}
+}
+
+func TestPostgreSQL_InstanceRolesAndGroups(t *testing.T) {
+	p := postgreSQL(t)
+	ctx := context.Background()
+	userId := uuid.New()
+	store1 := dto.UserIdInstance{UserId: userId, Instance: "store-1"}
+	store2 := dto.UserIdInstance{UserId: userId, Instance: "store-2"}
+
+	if p.CreateService(ctx, &dto.NameDescription{Name: "store"}) != nil ||
+		p.CreateOrUpdateInstance(ctx, &dto.NameServiceSecret{Name: "store-1", Service: "store", Secret: "secret"}) != nil ||
+		p.CreateOrUpdateInstance(ctx, &dto.NameServiceSecret{Name: "store-2", Service: "store", Secret: "secret"}) != nil ||
+		p.CreatePermission(ctx, &dto.NameNumberDescriptionService{Name: "sell", Service: "store"}) != nil ||
+		p.CreatePermission(ctx, &dto.NameNumberDescriptionService{Name: "refund", Service: "store"}) != nil ||
+		p.CreateRole(ctx, &dto.NameServiceDescription{Name: "seller", Service: "store"}) != nil ||
+		p.AssignPermissionToRole(ctx, &dto.PermissionRoleService{Permission: "sell", Role: "seller", Service: "store"}) != nil ||
+		p.CreateGroup(ctx, &dto.NameServiceDescription{Name: "cashiers", Service: "store"}) != nil ||
+		p.AssignPermissionToGroup(ctx, &dto.GroupPermissionService{Permission: "refund", Group: "cashiers", Service: "store"}) != nil ||
+		p.SetAccountLoginData(ctx, &dto.UserIdLoginHashState{Login: "seller", UserId: userId, State: account_state.Enabled,
+			Hash: "$2a$14$qXnQ8n9U0FItXkto3Sf8XuvZny48y4iZLTluWZtZszTrc7REdzUAy"}) != nil ||
+		p.AssignInstanceRoleToAccount(ctx, &dto.UserIdInstanceRole{UserId: userId, Instance: "store-1", Role: "seller"}) != nil ||
+		p.AssignInstanceGroupToAccount(ctx, &dto.UserIdInstanceGroup{UserId: userId, Instance: "store-2", Group: "cashiers"}) != nil {
+		t.Fatal()
+	}
+
+	if numbers, err := p.InstancePermissionsNumbersForAccount(ctx, &store1); err != nil || len(numbers) != 1 || numbers[0] != 1 {
+		t.Fatal()
+	}
+
+	if numbers, err := p.InstancePermissionsNumbersForAccount(ctx, &store2); err != nil || len(numbers) != 1 || numbers[0] != 2 {
+		t.Fatal()
+	}
+
+	if numbers, err := p.ServicePermissionsNumbersForAccount(ctx, &dto.UserIdService{UserId: userId, Service: "store"}); err != nil ||
+		len(numbers) != 0 {
+		t.Fatal()
+	}
+
+	paths, err := p.PermissionsGrantPathsForAccount(ctx, &dto.UserIdServiceInstance{UserId: userId, Service: "store", Instance: "store-2"})
+	if err != nil || len(paths) != 1 || paths[0].GrantPath.Source != grant_source.InstanceGroup || paths[0].GrantPath.Instance != "store-2" {
+		t.Fatal()
+	}
+
+	details, err := p.AccountDetails(ctx, userId)
+	if err != nil || len(details.InstanceRoles) != 1 || details.InstanceRoles[0].Instance != "store-1" ||
+		len(details.InstanceGroups) != 1 || details.InstanceGroups[0].Name != "cashiers" {
+		t.Fail()
+	}
+}
+
+func TestPostgreSQL_ConditionalGrants(t *testing.T) {
+	p := postgreSQL(t)
+	ctx := context.Background()
+	userId := uuid.New()
+	workingHours := `time_between("09:00", "21:00")`
+	south := `instance.region == "south"`
+
+	if p.CreateService(ctx, &dto.NameDescription{Name: "store"}) != nil ||
+		p.CreateOrUpdateInstance(ctx, &dto.NameServiceSecret{Name: "store-1", Service: "store", Secret: "secret"}) != nil ||
+		p.CreatePermission(ctx, &dto.NameNumberDescriptionService{Name: "sell", Service: "store"}) != nil ||
+		p.CreatePermission(ctx, &dto.NameNumberDescriptionService{Name: "refund", Service: "store"}) != nil ||
+		p.CreateRole(ctx, &dto.NameServiceDescription{Name: "seller", Service: "store"}) != nil ||
+		p.AssignPermissionToRole(ctx, &dto.PermissionRoleService{Permission: "sell", Role: "seller", Service: "store"}) != nil ||
+		p.SetAccountLoginData(ctx, &dto.UserIdLoginHashState{Login: "seller", UserId: userId, State: account_state.Enabled,
+			Hash: "$2a$14$qXnQ8n9U0FItXkto3Sf8XuvZny48y4iZLTluWZtZszTrc7REdzUAy"}) != nil ||
+		p.AssignRoleToAccount(ctx, &dto.UserIdRoleService{UserId: userId, Role: "seller", Service: "store", Condition: workingHours}) != nil ||
+		p.AssignInstancePermissionToAccount(ctx, &dto.UserIdInstancePermission{UserId: userId, Instance: "store-1",
+			Permission: "refund", Condition: south}) != nil ||
+		p.SetInstanceAttribute(ctx, &dto.InstanceNameValue{Instance: "store-1", Name: "region", Value: "north"}) != nil ||
+		p.SetInstanceAttribute(ctx, &dto.InstanceNameValue{Instance: "store-1", Name: "region", Value: "south"}) != nil {
+		t.Fatal()
+	}
+
+	store1 := dto.UserIdInstance{UserId: userId, Instance: "store-1"}
+	if numbers, err := p.InstancePermissionsNumbersForAccount(ctx, &store1); err != nil || len(numbers) != 0 {
+		t.Fatal()
+	}
+
+	if numbers, err := p.ServicePermissionsNumbersForAccount(ctx, &dto.UserIdService{UserId: userId, Service: "store"}); err != nil ||
+		len(numbers) != 0 {
+		t.Fatal()
+	}
+
+	conditional, err := p.ConditionalPermissionsForAccount(ctx, &store1)
+	if err != nil || len(conditional) != 2 || conditional[0] != (dto.NumberCondition{Number: 1, Condition: workingHours}) ||
+		conditional[1] != (dto.NumberCondition{Number: 2, Condition: south}) {
+		t.Fatal()
+	}
+
+	paths, err := p.PermissionsGrantPathsForAccount(ctx, &dto.UserIdServiceInstance{UserId: userId, Service: "store", Instance: "store-1"})
+	if err != nil || len(paths) != 2 || paths[0].GrantPath.Source != grant_source.Role || paths[0].GrantPath.Condition != workingHours ||
+		paths[1].GrantPath.Source != grant_source.Instance || paths[1].GrantPath.Condition != south {
+		t.Fatal()
+	}
+
+	if attributes, err := p.InstanceAttributes(ctx, "store-1"); err != nil || attributes["region"] != "south" {
+		t.Fatal()
+	}
+
+	if p.DeleteInstanceAttribute(ctx, &dto.NameInstance{Name: "region", Instance: "store-1"}) != nil {
+		t.Fatal()
+	}
+
+	if attributes, err := p.InstanceAttributes(ctx, "store-1"); err != nil || len(attributes) != 0 {
+		t.Fail()
+	}
+}
+
+func TestPostgreSQL_SelectorPermissions(t *testing.T) {
+	p := postgreSQL(t)
+	ctx := context.Background()
+	userId := uuid.New()
+	store1 := dto.UserIdInstance{UserId: userId, Instance: "store-1"}
+	store2 := dto.UserIdInstance{UserId: userId, Instance: "store-2"}
+
+	if p.CreateService(ctx, &dto.NameDescription{Name: "store"}) != nil ||
+		p.CreateOrUpdateInstance(ctx, &dto.NameServiceSecret{Name: "store-1", Service: "store", Secret: "secret"}) != nil ||
+		p.CreatePermission(ctx, &dto.NameNumberDescriptionService{Name: "sell", Service: "store"}) != nil ||
+		p.SetAccountLoginData(ctx, &dto.UserIdLoginHashState{Login: "seller", UserId: userId, State: account_state.Enabled,
+			Hash: "$2a$14$qXnQ8n9U0FItXkto3Sf8XuvZny48y4iZLTluWZtZszTrc7REdzUAy"}) != nil ||
+		p.SetInstanceAttribute(ctx, &dto.InstanceNameValue{Instance: "store-1", Name: "city", Value: "Донецк"}) != nil ||
+		p.AssignSelectorPermissionToAccount(ctx, &dto.UserIdSelectorPermission{UserId: userId, Permission: "sell",
+			Service: "store", Selector: "tier=gold, city=Донецк"}) != nil {
+		t.Fatal()
+	}
+
+	if !errors.Is(p.AssignSelectorPermissionToAccount(ctx, &dto.UserIdSelectorPermission{UserId: userId, Permission: "sell",
+		Service: "store", Selector: "city"}), persistent.ErrInvalidSelector) {
+		t.Fatal()
+	}
+
+	if numbers, err := p.InstancePermissionsNumbersForAccount(ctx, &store1); err != nil || len(numbers) != 0 {
+		t.Fatal()
+	}
+
+	if p.SetInstanceAttribute(ctx, &dto.InstanceNameValue{Instance: "store-1", Name: "tier", Value: "gold"}) != nil ||
+		p.CreateOrUpdateInstance(ctx, &dto.NameServiceSecret{Name: "store-2", Service: "store", Secret: "secret"}) != nil ||
+		p.SetInstanceAttribute(ctx, &dto.InstanceNameValue{Instance: "store-2", Name: "city", Value: "Донецк"}) != nil ||
+		p.SetInstanceAttribute(ctx, &dto.InstanceNameValue{Instance: "store-2", Name: "tier", Value: "gold"}) != nil {
+		t.Fatal()
+	}
+
+	for _, instance := range []dto.UserIdInstance{store1, store2} {
+		if numbers, err := p.InstancePermissionsNumbersForAccount(ctx, &instance); err != nil || len(numbers) != 1 || numbers[0] != 1 {
+			t.Fatal()
+		}
+	}
+
+	paths, err := p.PermissionsGrantPathsForAccount(ctx, &dto.UserIdServiceInstance{UserId: userId, Service: "store", Instance: "store-2"})
+	if err != nil || len(paths) != 1 || paths[0].GrantPath.Source != grant_source.Selector ||
+		paths[0].GrantPath.Instance != "store-2" || paths[0].GrantPath.Selector != "city=Донецк,tier=gold" {
+		t.Fatal()
+	}
+
+	details, err := p.AccountDetails(ctx, userId)
+	if err != nil || len(details.SelectorPermissions) != 1 || details.SelectorPermissions[0].Selector != "city=Донецк,tier=gold" {
+		t.Fail()
+	}
+}
+
+func TestPostgreSQL_CreateAccount(t *testing.T) {
+	p := postgreSQL(t)
+	ctx := context.Background()
+	userId := uuid.New()
+	metadata := dto.UserIdAccountMetadata{Type: account_type.Service, DisplayName: "Касса", OwnerTeam: "retail"}
+
+	if p.CreateAccount(ctx, &dto.UserIdLoginHashState{Login: "cashbox", UserId: userId, State: account_state.Enabled,
+		Hash: "$2a$14$qXnQ8n9U0FItXkto3Sf8XuvZny48y4iZLTluWZtZszTrc7REdzUAy"}, &metadata) != nil {
+		t.Fatal()
+	}
+
+	details, err := p.AccountDetails(ctx, userId)
+	if err != nil || details.Type != metadata.Type || details.DisplayName != metadata.DisplayName ||
+		details.OwnerTeam != metadata.OwnerTeam {
+		t.Fatal()
+	}
+
+	if !errors.Is(p.CreateAccount(ctx, &dto.UserIdLoginHashState{Login: "cashbox", UserId: uuid.New(),
+		State: account_state.Enabled}, nil), persistent.ErrDuplicateKeyValue) {
+		t.Fail()
+	}
+}
+
+func TestPostgreSQL_AccountMetadata(t *testing.T) {
+	p := postgreSQL(t)
+	ctx := context.Background()
+	userId := uuid.New()
+	metadata := dto.UserIdAccountMetadata{UserId: userId, Type: account_type.Service, DisplayName: "Склад",
+		Contact: "stock@example.com", OwnerTeam: "logistics"}
+
+	if p.SetAccountLoginData(ctx, &dto.UserIdLoginHashState{Login: "stock", UserId: userId, State: account_state.Enabled,
+		Hash: "$2a$14$qXnQ8n9U0FItXkto3Sf8XuvZny48y4iZLTluWZtZszTrc7REdzUAy"}) != nil {
+		t.Fatal()
+	}
+
+	details, err := p.AccountDetails(ctx, userId)
+	if err != nil || details.Type != account_type.Human || details.CreatedAt.IsZero() || details.LastLoginAt != nil {
+		t.Fatal()
+	}
+
+	if p.SetAccountMetadata(ctx, &metadata) != nil || p.UpdateAccountLastLogin(ctx, userId) != nil {
+		t.Fatal()
+	}
+
+	details, err = p.AccountDetails(ctx, userId)
+	if err != nil || details.Type != metadata.Type || details.DisplayName != metadata.DisplayName ||
+		details.Contact != metadata.Contact || details.OwnerTeam != metadata.OwnerTeam || details.LastLoginAt == nil ||
+		details.UpdatedAt.Before(details.CreatedAt) {
+		t.Fatal()
+	}
+
+	accounts, err := p.Accounts(ctx, &dto.ListQuery{Filter: "склад", Type: account_type.Service, OwnerTeam: "logistics", Limit: 10})
+	if err != nil || len(accounts) != 1 || accounts[0].UserId != userId || accounts[0].DisplayName != metadata.DisplayName {
+		t.Fatal()
+	}
+
+	if accounts, err = p.Accounts(ctx, &dto.ListQuery{Type: account_type.Human, Limit: 10}); err != nil || len(accounts) != 0 {
+		t.Fail()
+	}
+}
+
+func TestPostgreSQL_ServicePermissionEncoding(t *testing.T) {
+	p := postgreSQL(t)
+	ctx := context.Background()
+
+	if p.CreateService(ctx, &dto.NameDescription{Name: "store"}) != nil {
+		t.Fatal()
+	}
+
+	if encoding, err := p.ServicePermissionEncoding(ctx, "store"); err != nil || encoding != permission_encoding.List {
+		t.Fatal()
+	}
+
+	if p.SetServicePermissionEncoding(ctx, &dto.NameEncoding{Name: "store", Encoding: permission_encoding.Bitmap}) != nil {
+		t.Fatal()
+	}
+
+	if details, err := p.ServiceDetails(ctx, "store"); err != nil || details.PermissionEncoding != permission_encoding.Bitmap {
+		t.Fail()
+	}
+
+	if !errors.Is(p.SetServicePermissionEncoding(ctx, &dto.NameEncoding{Name: "shop", Encoding: permission_encoding.Bitmap}),
+		persistent.ErrZeroRowsAffected) {
+		t.Fail()
+	}
+}
+
+func TestPostgreSQL_ImportRBAC(t *testing.T) {
+	p := postgreSQL(t)
+	ctx := context.Background()
+
+	userId := uuid.New()
+	document := dto.RBACDocument{
+		Version: 1,
+		Services: []dto.RBACService{{
+			Name:        "imported",
+			Description: "Imported service",
+			Instances:   []string{"imported-1"},
+			Permissions: []dto.NameNumberDescriptionDeprecated{{Name: "sell", Number: 3, Description: "Sell watches"}},
+			Roles:       []dto.RBACRole{{Name: "Продавец", Permissions: []string{"sell"}}},
+			Groups:      []dto.RBACGroup{{Name: "Персонал магазина", Roles: []string{"Продавец"}}},
+		}},
+		GlobalGroups: []dto.RBACGlobalGroup{{
+			Name:  "Продажи",
+			Roles: []dto.NameService{{Name: "Продавец", Service: "imported"}},
+		}},
+		Accounts: []dto.AccountDetails{{
+			UserId:              userId,
+			Login:               "imported-clerk",
+			State:               account_state.Enabled,
+			Groups:              []dto.NameService{{Name: "Персонал магазина", Service: "imported"}},
+			InstancePermissions: []dto.InstancePermission{{Instance: "imported-1", Permission: "sell"}},
+			InstanceRoles:       []dto.NameInstance{{Name: "Продавец", Instance: "imported-1"}},
+			InstanceGroups:      []dto.NameInstance{{Name: "Персонал магазина", Instance: "imported-1"}},
+			SelectorPermissions: []dto.SelectorPermission{{Permission: "sell", Service: "imported", Selector: "tier=gold,city=Донецк"}},
+			GlobalGroups:        []string{"Продажи"},
+		}},
+	}
+
+	for i := 0; i < 2; i++ {
+		if p.ImportRBAC(ctx, &document, map[string]string{"imported-1": "secret"}, nil) != nil {
+			t.Fatal()
+		}
+	}
+
+	if service, err := p.ServiceDetails(ctx, "imported"); err != nil || len(service.Instances) != 1 ||
+		len(service.Permissions) != 1 || service.Permissions[0].Number != 3 || len(service.Groups) != 1 {
+		t.Fatal()
+	}
+
+	if account, err := p.AccountDetails(ctx, userId); err != nil || len(account.Groups) != 1 ||
+		len(account.InstancePermissions) != 1 || len(account.InstanceRoles) != 1 || len(account.InstanceGroups) != 1 ||
+		len(account.GlobalGroups) != 1 || len(account.SelectorPermissions) != 1 ||
+		account.SelectorPermissions[0].Selector != "city=Донецк,tier=gold" {
+		t.Fatal()
+	}
+
+	if groups, err := p.GlobalGroups(ctx); err != nil || len(groups) != 1 || len(groups[0].Roles) != 1 {
+		t.Fatal()
+	}
+
+	document.Services[0].Groups = nil
+	document.Accounts[0].Groups = nil
+	document.Accounts[0].InstancePermissions = nil
+	document.GlobalGroups = nil
+	document.Accounts[0].GlobalGroups = nil
+	document.Accounts[0].InstanceRoles = nil
+	document.Accounts[0].InstanceGroups = nil
+	document.Accounts[0].SelectorPermissions = nil
+	removals := dto.RBACRemovals{
+		Groups: []dto.NameService{{Name: "Персонал магазина", Service: "imported"}},
+		AccountInstancePermissions: []dto.UserIdInstancePermission{
+			{UserId: userId, Instance: "imported-1", Permission: "sell"},
+		},
+		AccountInstanceRoles: []dto.UserIdInstanceRole{{UserId: userId, Instance: "imported-1", Role: "Продавец"}},
+		AccountSelectorPermissions: []dto.UserIdSelectorPermission{
+			{UserId: userId, Permission: "sell", Service: "imported", Selector: "city=Донецк,tier=gold"},
+		},
+		GlobalGroups: []string{"Продажи"},
+	}
+	if p.ImportRBAC(ctx, &document, nil, &removals) != nil {
+		t.Fatal()
+	}
+
+	if account, err := p.AccountDetails(ctx, userId); err != nil || len(account.Groups) != 0 ||
+		len(account.InstancePermissions) != 0 || len(account.InstanceRoles) != 0 || len(account.InstanceGroups) != 0 ||
+		len(account.GlobalGroups) != 0 || len(account.SelectorPermissions) != 0 {
+		t.Fail()
+	}
+
+	if p.RestoreGroup(ctx, &dto.NameService{Name: "Персонал магазина", Service: "imported"}) != nil {
+		t.Fail()
+	}
+
+	foreign := dto.RBACDocument{Services: []dto.RBACService{{Name: "foreign", Instances: []string{"imported-1"}}}}
+	if !errors.Is(p.ImportRBAC(ctx, &foreign, nil, nil), persistent.ErrDuplicateKeyValue) {
+		t.Fail()
+	}
+}
+
+func TestPostgreSQL_SoftDelete(t *testing.T) {
+	p := postgreSQL(t)
+	ctx := context.Background()
+	userId := uuid.New()
+	store := dto.UserIdService{UserId: userId, Service: "store"}
+	seller := dto.NameService{Name: "seller", Service: "store"}
+
+	if p.CreateService(ctx, &dto.NameDescription{Name: "store"}) != nil ||
+		p.CreateRole(ctx, &dto.NameServiceDescription{Name: "seller", Service: "store"}) != nil ||
+		p.CreatePermission(ctx, &dto.NameNumberDescriptionService{Name: "sell", Service: "store"}) != nil ||
+		p.CreatePermission(ctx, &dto.NameNumberDescriptionService{Name: "refund", Service: "store"}) != nil ||
+		p.AssignPermissionToRole(ctx, &dto.PermissionRoleService{Permission: "sell", Role: "seller", Service: "store"}) != nil ||
+		p.SetAccountLoginData(ctx, &dto.UserIdLoginHashState{Login: "seller", UserId: userId, State: account_state.Enabled,
+			Hash: "$2a$14$qXnQ8n9U0FItXkto3Sf8XuvZny48y4iZLTluWZtZszTrc7REdzUAy"}) != nil ||
+		p.AssignRoleToAccount(ctx, &dto.UserIdRoleService{UserId: userId, Role: "seller", Service: "store"}) != nil {
+		t.Fatal()
+	}
+
+	if p.DeleteRole(ctx, &seller) != nil {
+		t.Fatal()
+	}
+
+	if !errors.Is(p.CreateRole(ctx, &dto.NameServiceDescription{Name: "seller", Service: "store"}),
+		persistent.ErrDeletedEntity) {
+		t.Fatal()
+	}
+
+	if numbers, err := p.ServicePermissionsNumbersForAccount(ctx, &store); err != nil || len(numbers) != 0 {
+		t.Fatal()
+	}
+
+	if !errors.Is(p.DeleteRole(ctx, &seller), persistent.ErrZeroRowsAffected) || p.RestoreRole(ctx, &seller) != nil {
+		t.Fatal()
+	}
+
+	if numbers, err := p.ServicePermissionsNumbersForAccount(ctx, &store); err != nil || len(numbers) != 1 || numbers[0] != 1 {
+		t.Fatal()
+	}
+
+	if p.DeletePermission(ctx, &dto.NameService{Name: "refund", Service: "store"}) != nil ||
+		p.DeleteService(ctx, "store") != nil {
+		t.Fatal()
+	}
+
+	if _, err := p.ServiceDetails(ctx, "store"); err == nil {
+		t.Fatal()
+	}
+
+	if p.RestoreService(ctx, "store") != nil {
+		t.Fatal()
+	}
+
+	details, err := p.ServiceDetails(ctx, "store")
+	if err != nil || len(details.Roles) != 1 || len(details.Permissions) != 1 || details.Permissions[0].Name != "sell" {
+		t.Fatal()
+	}
+
+	if p.DeleteAccount(ctx, userId) != nil {
+		t.Fatal()
+	}
+
+	if _, err = p.AccountLoginData(ctx, "seller"); err == nil {
+		t.Fatal()
+	}
+
+	if p.RestoreAccount(ctx, userId) != nil {
+		t.Fatal()
+	}
+
+	if numbers, err := p.ServicePermissionsNumbersForAccount(ctx, &store); err != nil || len(numbers) != 1 {
+		t.Fatal()
+	}
+
+	if p.PurgeDeleted(ctx, time.Now().Add(time.Minute)) != nil {
+		t.Fatal()
+	}
+
+	if details, err = p.ServiceDetails(ctx, "store"); err != nil || len(details.RetiredNumbers) != 1 ||
+		details.RetiredNumbers[0] != 2 {
+		t.Fail()
+	}
+}
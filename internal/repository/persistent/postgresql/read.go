@@ -0,0 +1,455 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx"
+	"github.com/lazylex/watch-store/secure/internal/dto"
+)
+
+// keyset возвращает оператор сравнения с курсором и направление сортировки для постраничной выборки.
+func keyset(descending bool) (string, string) {
+	if descending {
+		return "<", "DESC"
+	}
+	return ">", "ASC"
+}
+
+// queryRows выполняет запрос и возвращает все его строки, преобразованные функцией scan.
+func queryRows[T any](ctx context.Context, p *PostgreSQL, stmt string, scan func(*pgx.Rows) (T, error), args ...any) ([]T, error) {
+	rows, err := p.pool.QueryEx(ctx, stmt, nil, args...)
+	defer rows.Close()
+
+	if err != nil {
+		return nil, adaptErrSkipFrames(err, 2)
+	}
+
+	result := make([]T, 0)
+
+	for rows.Next() {
+		item, err := scan(rows)
+		if err != nil {
+			return result, adaptErrSkipFrames(err, 2)
+		}
+		result = append(result, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return result, adaptErrSkipFrames(err, 2)
+	}
+
+	return result, nil
+}
+
+// scanString считывает строку из единственного столбца выборки.
+func scanString(rows *pgx.Rows) (string, error) {
+	var value string
+	err := rows.Scan(&value)
+	return value, err
+}
+
+// scanInt считывает целое число из единственного столбца выборки.
+func scanInt(rows *pgx.Rows) (int, error) {
+	var value int
+	err := rows.Scan(&value)
+	return value, err
+}
+
+// scanNameDescription считывает название и описание.
+func scanNameDescription(rows *pgx.Rows) (dto.NameDescription, error) {
+	var value dto.NameDescription
+	err := rows.Scan(&value.Name, &value.Description)
+	return value, err
+}
+
+// scanNameNumber считывает название и номер разрешения.
+func scanNameNumber(rows *pgx.Rows) (dto.NameNumber, error) {
+	var value dto.NameNumber
+	err := rows.Scan(&value.Name, &value.Number)
+	return value, err
+}
+
+// scanNameService считывает название и название сервиса.
+func scanNameService(rows *pgx.Rows) (dto.NameService, error) {
+	var value dto.NameService
+	err := rows.Scan(&value.Name, &value.Service)
+	return value, err
+}
+
+// scanNameInstance считывает название и название экземпляра сервиса.
+func scanNameInstance(rows *pgx.Rows) (dto.NameInstance, error) {
+	var value dto.NameInstance
+	err := rows.Scan(&value.Name, &value.Instance)
+	return value, err
+}
+
+// Services возвращает страницу сервисов, отсортированных по названию. Выбираются сервисы, в названии которых
+// содержится data.Filter, и следующие за data.After в порядке сортировки.
+func (p *PostgreSQL) Services(ctx context.Context, data *dto.ListQuery) ([]dto.NameDescription, error) {
+	cmp, order := keyset(data.Descending)
+	stmt := fmt.Sprintf(`	SELECT name, COALESCE(description, '')
+							FROM services
+							WHERE deleted_at IS NULL
+							  AND strpos(lower(name), lower($1)) > 0
+							  AND ($2::TEXT = '' OR name %s $2)
+							ORDER BY name %s
+							LIMIT $3`, cmp, order)
+
+	return queryRows(ctx, p, stmt, scanNameDescription, data.Filter, data.After, data.Limit)
+}
+
+// Instances возвращает страницу экземпляров сервиса data.Service (или всех сервисов, если он не задан),
+// отсортированных по названию.
+func (p *PostgreSQL) Instances(ctx context.Context, data *dto.ListQuery) ([]dto.NameService, error) {
+	cmp, order := keyset(data.Descending)
+	stmt := fmt.Sprintf(`	SELECT i.name, s.name
+							FROM instances i
+								JOIN services s ON s.service_id = i.service_fk AND s.deleted_at IS NULL
+							WHERE i.deleted_at IS NULL
+							  AND strpos(lower(i.name), lower($1)) > 0
+							  AND ($2::TEXT = '' OR i.name %s $2)
+							  AND ($4::TEXT = '' OR s.name = $4)
+							ORDER BY i.name %s
+							LIMIT $3`, cmp, order)
+
+	return queryRows(ctx, p, stmt, scanNameService, data.Filter, data.After, data.Limit, data.Service)
+}
+
+// Roles возвращает страницу ролей сервиса data.Service, отсортированных по названию.
+func (p *PostgreSQL) Roles(ctx context.Context, data *dto.ListQuery) ([]dto.NameDescription, error) {
+	return p.serviceEntities(ctx, "roles", data)
+}
+
+// Groups возвращает страницу групп сервиса data.Service, отсортированных по названию.
+func (p *PostgreSQL) Groups(ctx context.Context, data *dto.ListQuery) ([]dto.NameDescription, error) {
+	return p.serviceEntities(ctx, "groups", data)
+}
+
+// serviceEntities возвращает страницу названий и описаний из таблицы table (ролей или групп) сервиса data.Service.
+func (p *PostgreSQL) serviceEntities(ctx context.Context, table string, data *dto.ListQuery) ([]dto.NameDescription, error) {
+	cmp, order := keyset(data.Descending)
+	stmt := fmt.Sprintf(`	SELECT name, COALESCE(description, '')
+							FROM %s
+							WHERE service_fk = (SELECT service_id FROM services WHERE name = $4 AND deleted_at IS NULL)
+							  AND deleted_at IS NULL
+							  AND strpos(lower(name), lower($1)) > 0
+							  AND ($2::TEXT = '' OR name %s $2)
+							ORDER BY name %s
+							LIMIT $3`, table, cmp, order)
+
+	return queryRows(ctx, p, stmt, scanNameDescription, data.Filter, data.After, data.Limit, data.Service)
+}
+
+// Accounts возвращает страницу учетных записей, отсортированных по логину. Фильтр применяется к логину и
+// отображаемому имени, тип учетной записи и команда-владелец, если переданы, должны совпадать.
+func (p *PostgreSQL) Accounts(ctx context.Context, data *dto.ListQuery) ([]dto.UserIdLoginState, error) {
+	cmp, order := keyset(data.Descending)
+	stmt := fmt.Sprintf(`	SELECT uuid, login, state, account_type, display_name
+							FROM accounts
+							WHERE deleted_at IS NULL
+							  AND (strpos(lower(login), lower($1)) > 0 OR strpos(lower(display_name), lower($1)) > 0)
+							  AND ($2::TEXT = '' OR login %s $2)
+							  AND ($4::TEXT = '' OR account_type = $4)
+							  AND ($5::TEXT = '' OR owner_team = $5)
+							ORDER BY login %s
+							LIMIT $3`, cmp, order)
+
+	return queryRows(ctx, p, stmt, func(rows *pgx.Rows) (dto.UserIdLoginState, error) {
+		var value dto.UserIdLoginState
+		err := rows.Scan(&value.UserId, &value.Login, &value.State, &value.Type, &value.DisplayName)
+		return value, err
+	}, data.Filter, data.After, data.Limit, data.Type, data.OwnerTeam)
+}
+
+// ServiceDetails возвращает описание сервиса с названиями его экземпляров, ролей и групп, списком разрешений,
+// выведенными из употребления номерами разрешений и способом кодирования номеров разрешений в токенах.
+func (p *PostgreSQL) ServiceDetails(ctx context.Context, name string) (dto.ServiceDetails, error) {
+	var err error
+	result := dto.ServiceDetails{Name: name}
+
+	stmt := `SELECT COALESCE(description, ''), permission_encoding FROM services WHERE name = $1 AND deleted_at IS NULL`
+	row := p.pool.QueryRowEx(ctx, stmt, nil, name)
+	if err = row.Scan(&result.Description, &result.PermissionEncoding); err != nil {
+		return dto.ServiceDetails{}, adaptErr(err)
+	}
+
+	serviceFilter := `service_fk = (SELECT service_id FROM services WHERE name = $1 AND deleted_at IS NULL)`
+
+	if result.Instances, err = queryRows(ctx, p, `SELECT name FROM instances WHERE `+serviceFilter+` AND deleted_at IS NULL ORDER BY name`, scanString, name); err != nil {
+		return dto.ServiceDetails{}, err
+	}
+
+	if result.Roles, err = queryRows(ctx, p, `SELECT name FROM roles WHERE `+serviceFilter+` AND deleted_at IS NULL ORDER BY name`, scanString, name); err != nil {
+		return dto.ServiceDetails{}, err
+	}
+
+	if result.Groups, err = queryRows(ctx, p, `SELECT name FROM groups WHERE `+serviceFilter+` AND deleted_at IS NULL ORDER BY name`, scanString, name); err != nil {
+		return dto.ServiceDetails{}, err
+	}
+
+	stmt = `SELECT name, number, COALESCE(description, ''), deprecated FROM permissions WHERE ` + serviceFilter + ` AND deleted_at IS NULL ORDER BY number`
+	result.Permissions, err = queryRows(ctx, p, stmt, func(rows *pgx.Rows) (dto.NameNumberDescriptionDeprecated, error) {
+		var value dto.NameNumberDescriptionDeprecated
+		err := rows.Scan(&value.Name, &value.Number, &value.Description, &value.Deprecated)
+		return value, err
+	}, name)
+	if err != nil {
+		return dto.ServiceDetails{}, err
+	}
+
+	stmt = `SELECT number FROM retired_permission_numbers WHERE ` + serviceFilter + ` ORDER BY number`
+	if result.RetiredNumbers, err = queryRows(ctx, p, stmt, scanInt, name); err != nil {
+		return dto.ServiceDetails{}, err
+	}
+
+	return result, nil
+}
+
+// RoleDetails возвращает описание роли, назначенные ей разрешения и названия её родительских ролей.
+func (p *PostgreSQL) RoleDetails(ctx context.Context, data *dto.NameService) (dto.RoleDetails, error) {
+	var err error
+	result := dto.RoleDetails{Name: data.Name, Service: data.Service}
+
+	stmt := `	SELECT COALESCE(description, '')
+				FROM roles
+				WHERE name = $1
+				  AND service_fk = (SELECT service_id FROM services WHERE name = $2 AND deleted_at IS NULL)
+				  AND deleted_at IS NULL`
+	if err = p.pool.QueryRowEx(ctx, stmt, nil, data.Name, data.Service).Scan(&result.Description); err != nil {
+		return dto.RoleDetails{}, adaptErr(err)
+	}
+
+	stmt = `	SELECT p.name, p.number
+				FROM role_permissions rp
+					JOIN roles r ON r.role_id = rp.role_fk AND r.deleted_at IS NULL
+					JOIN permissions p ON p.permission_id = rp.permission_fk AND p.deleted_at IS NULL
+				WHERE r.name = $1
+				  AND r.service_fk = (SELECT service_id FROM services WHERE name = $2 AND deleted_at IS NULL)
+				ORDER BY p.number`
+	if result.Permissions, err = queryRows(ctx, p, stmt, scanNameNumber, data.Name, data.Service); err != nil {
+		return dto.RoleDetails{}, err
+	}
+
+	stmt = `	SELECT parent.name
+				FROM role_parents rp
+					JOIN roles r ON r.role_id = rp.role_fk AND r.deleted_at IS NULL
+					JOIN roles parent ON parent.role_id = rp.parent_fk AND parent.deleted_at IS NULL
+				WHERE r.name = $1
+				  AND r.service_fk = (SELECT service_id FROM services WHERE name = $2 AND deleted_at IS NULL)
+				ORDER BY parent.name`
+	if result.Parents, err = queryRows(ctx, p, stmt, scanString, data.Name, data.Service); err != nil {
+		return dto.RoleDetails{}, err
+	}
+
+	return result, nil
+}
+
+// GroupDetails возвращает описание группы, назначенные ей роли и разрешения и названия вложенных в неё групп.
+func (p *PostgreSQL) GroupDetails(ctx context.Context, data *dto.NameService) (dto.GroupDetails, error) {
+	var err error
+	result := dto.GroupDetails{Name: data.Name, Service: data.Service}
+
+	stmt := `	SELECT COALESCE(description, '')
+				FROM groups
+				WHERE name = $1
+				  AND service_fk = (SELECT service_id FROM services WHERE name = $2 AND deleted_at IS NULL)
+				  AND deleted_at IS NULL`
+	if err = p.pool.QueryRowEx(ctx, stmt, nil, data.Name, data.Service).Scan(&result.Description); err != nil {
+		return dto.GroupDetails{}, adaptErr(err)
+	}
+
+	stmt = `	SELECT r.name
+				FROM group_roles gr
+					JOIN groups g ON g.group_id = gr.group_fk AND g.deleted_at IS NULL
+					JOIN roles r ON r.role_id = gr.role_fk AND r.deleted_at IS NULL
+				WHERE g.name = $1
+				  AND g.service_fk = (SELECT service_id FROM services WHERE name = $2 AND deleted_at IS NULL)
+				ORDER BY r.name`
+	if result.Roles, err = queryRows(ctx, p, stmt, scanString, data.Name, data.Service); err != nil {
+		return dto.GroupDetails{}, err
+	}
+
+	stmt = `	SELECT sg.name
+				FROM group_subgroups gs
+					JOIN groups g ON g.group_id = gs.group_fk AND g.deleted_at IS NULL
+					JOIN groups sg ON sg.group_id = gs.subgroup_fk AND sg.deleted_at IS NULL
+				WHERE g.name = $1
+				  AND g.service_fk = (SELECT service_id FROM services WHERE name = $2 AND deleted_at IS NULL)
+				ORDER BY sg.name`
+	if result.Subgroups, err = queryRows(ctx, p, stmt, scanString, data.Name, data.Service); err != nil {
+		return dto.GroupDetails{}, err
+	}
+
+	stmt = `	SELECT p.name, p.number
+				FROM group_permissions gp
+					JOIN groups g ON g.group_id = gp.group_fk AND g.deleted_at IS NULL
+					JOIN permissions p ON p.permission_id = gp.permission_fk AND p.deleted_at IS NULL
+				WHERE g.name = $1
+				  AND g.service_fk = (SELECT service_id FROM services WHERE name = $2 AND deleted_at IS NULL)
+				ORDER BY p.number`
+	if result.Permissions, err = queryRows(ctx, p, stmt, scanNameNumber, data.Name, data.Service); err != nil {
+		return dto.GroupDetails{}, err
+	}
+
+	return result, nil
+}
+
+// GroupAccounts возвращает идентификаторы учетных записей, входящих в группу сервиса напрямую или через вложенные
+// группы.
+func (p *PostgreSQL) GroupAccounts(ctx context.Context, data *dto.NameService) ([]uuid.UUID, error) {
+	stmt := `WITH RECURSIVE
+			members (group_fk) AS
+			(SELECT group_id
+			FROM groups
+			WHERE name = $1
+			  AND service_fk = (SELECT service_id FROM services WHERE name = $2 AND deleted_at IS NULL)
+			  AND deleted_at IS NULL
+
+			UNION
+
+			SELECT gs.subgroup_fk
+			FROM group_subgroups gs
+				JOIN members m ON m.group_fk = gs.group_fk
+				JOIN groups g ON g.group_id = gs.subgroup_fk AND g.deleted_at IS NULL)
+
+			SELECT DISTINCT a.uuid
+			FROM account_groups ag
+				JOIN members m ON m.group_fk = ag.group_fk
+				JOIN accounts a ON a.account_id = ag.account_fk AND a.deleted_at IS NULL
+			ORDER BY a.uuid`
+
+	return queryRows(ctx, p, stmt, func(rows *pgx.Rows) (uuid.UUID, error) {
+		var value uuid.UUID
+		err := rows.Scan(&value)
+		return value, err
+	}, data.Name, data.Service)
+}
+
+// AccountDetails возвращает данные учетной записи (без хеша пароля) с её метаданными, назначенные ей роли и группы всех
+// сервисов, разрешения, роли и группы, назначенные для экземпляров сервисов, разрешения, назначенные по селектору
+// атрибутов, и названия назначенных ей глобальных групп.
+func (p *PostgreSQL) AccountDetails(ctx context.Context, id uuid.UUID) (dto.AccountDetails, error) {
+	var err error
+	result := dto.AccountDetails{UserId: id}
+
+	stmt := `	SELECT login, state, account_type, display_name, contact, owner_team, created_at, updated_at, last_login_at
+				FROM accounts
+				WHERE uuid = $1
+				  AND deleted_at IS NULL`
+	row := p.pool.QueryRowEx(ctx, stmt, nil, id)
+	err = row.Scan(&result.Login, &result.State, &result.Type, &result.DisplayName, &result.Contact, &result.OwnerTeam,
+		&result.CreatedAt, &result.UpdatedAt, &result.LastLoginAt)
+	if err != nil {
+		return dto.AccountDetails{}, adaptErr(err)
+	}
+
+	stmt = `	SELECT r.name, s.name
+				FROM account_roles ar
+					JOIN roles r ON r.role_id = ar.role_fk AND r.deleted_at IS NULL
+					JOIN services s ON s.service_id = r.service_fk AND s.deleted_at IS NULL
+				WHERE ar.account_fk = (SELECT account_id FROM accounts WHERE uuid = $1 AND deleted_at IS NULL)
+				ORDER BY s.name, r.name`
+	if result.Roles, err = queryRows(ctx, p, stmt, scanNameService, id); err != nil {
+		return dto.AccountDetails{}, err
+	}
+
+	stmt = `	SELECT g.name, s.name
+				FROM account_groups ag
+					JOIN groups g ON g.group_id = ag.group_fk AND g.deleted_at IS NULL
+					JOIN services s ON s.service_id = g.service_fk AND s.deleted_at IS NULL
+				WHERE ag.account_fk = (SELECT account_id FROM accounts WHERE uuid = $1 AND deleted_at IS NULL)
+				ORDER BY s.name, g.name`
+	if result.Groups, err = queryRows(ctx, p, stmt, scanNameService, id); err != nil {
+		return dto.AccountDetails{}, err
+	}
+
+	stmt = `	SELECT i.name, p.name
+				FROM accounts_instances_permissions aip
+					JOIN instances i ON i.instance_id = aip.instance_fk AND i.deleted_at IS NULL
+					JOIN permissions p ON p.permission_id = aip.permission_fk AND p.deleted_at IS NULL
+				WHERE aip.account_fk = (SELECT account_id FROM accounts WHERE uuid = $1 AND deleted_at IS NULL)
+				ORDER BY i.name, p.number`
+	result.InstancePermissions, err = queryRows(ctx, p, stmt, func(rows *pgx.Rows) (dto.InstancePermission, error) {
+		var value dto.InstancePermission
+		err := rows.Scan(&value.Instance, &value.Permission)
+		return value, err
+	}, id)
+	if err != nil {
+		return dto.AccountDetails{}, err
+	}
+
+	stmt = `	SELECT r.name, i.name
+				FROM account_instance_roles air
+					JOIN instances i ON i.instance_id = air.instance_fk AND i.deleted_at IS NULL
+					JOIN roles r ON r.role_id = air.role_fk AND r.deleted_at IS NULL
+				WHERE air.account_fk = (SELECT account_id FROM accounts WHERE uuid = $1 AND deleted_at IS NULL)
+				ORDER BY i.name, r.name`
+	if result.InstanceRoles, err = queryRows(ctx, p, stmt, scanNameInstance, id); err != nil {
+		return dto.AccountDetails{}, err
+	}
+
+	stmt = `	SELECT g.name, i.name
+				FROM account_instance_groups aig
+					JOIN instances i ON i.instance_id = aig.instance_fk AND i.deleted_at IS NULL
+					JOIN groups g ON g.group_id = aig.group_fk AND g.deleted_at IS NULL
+				WHERE aig.account_fk = (SELECT account_id FROM accounts WHERE uuid = $1 AND deleted_at IS NULL)
+				ORDER BY i.name, g.name`
+	if result.InstanceGroups, err = queryRows(ctx, p, stmt, scanNameInstance, id); err != nil {
+		return dto.AccountDetails{}, err
+	}
+
+	stmt = `	SELECT p.name, s.name, asp.selector
+				FROM account_selector_permissions asp
+					JOIN permissions p ON p.permission_id = asp.permission_fk AND p.deleted_at IS NULL
+					JOIN services s ON s.service_id = p.service_fk AND s.deleted_at IS NULL
+				WHERE asp.account_fk = (SELECT account_id FROM accounts WHERE uuid = $1 AND deleted_at IS NULL)
+				ORDER BY s.name, p.number, asp.selector`
+	result.SelectorPermissions, err = queryRows(ctx, p, stmt, func(rows *pgx.Rows) (dto.SelectorPermission, error) {
+		var value dto.SelectorPermission
+		err := rows.Scan(&value.Permission, &value.Service, &value.Selector)
+		return value, err
+	}, id)
+	if err != nil {
+		return dto.AccountDetails{}, err
+	}
+
+	stmt = `	SELECT gg.name
+				FROM account_global_groups agg
+					JOIN global_groups gg ON gg.global_group_id = agg.global_group_fk
+				WHERE agg.account_fk = (SELECT account_id FROM accounts WHERE uuid = $1 AND deleted_at IS NULL)
+				ORDER BY gg.name`
+	if result.GlobalGroups, err = queryRows(ctx, p, stmt, scanString, id); err != nil {
+		return dto.AccountDetails{}, err
+	}
+
+	return result, nil
+}
+
+// GlobalGroups возвращает все глобальные группы, отсортированные по названию, с их описаниями и ролями сервисов.
+func (p *PostgreSQL) GlobalGroups(ctx context.Context) ([]dto.RBACGlobalGroup, error) {
+	stmt := `SELECT name, COALESCE(description, '') FROM global_groups ORDER BY name`
+	groups, err := queryRows(ctx, p, stmt, scanNameDescription)
+	if err != nil {
+		return nil, err
+	}
+
+	stmt = `	SELECT r.name, s.name
+				FROM global_group_roles ggr
+					JOIN global_groups gg ON gg.global_group_id = ggr.global_group_fk
+					JOIN roles r ON r.role_id = ggr.role_fk AND r.deleted_at IS NULL
+					JOIN services s ON s.service_id = r.service_fk AND s.deleted_at IS NULL
+				WHERE gg.name = $1
+				ORDER BY s.name, r.name`
+
+	result := make([]dto.RBACGlobalGroup, 0, len(groups))
+	for _, group := range groups {
+		var roles []dto.NameService
+		if roles, err = queryRows(ctx, p, stmt, scanNameService, group.Name); err != nil {
+			return nil, err
+		}
+		result = append(result, dto.RBACGlobalGroup{Name: group.Name, Description: group.Description, Roles: roles})
+	}
+
+	return result, nil
+}
@@ -0,0 +1,50 @@
+package postgresql
+
+import (
+	"context"
+	"github.com/lazylex/watch-store/secure/internal/domain/value_objects/label_selector"
+	"github.com/lazylex/watch-store/secure/internal/dto"
+	"github.com/lazylex/watch-store/secure/internal/errors/persistent"
+)
+
+// AssignSelectorPermissionToAccount назначает учетной записи разрешение сервиса для всех его экземпляров, атрибуты
+// которых соответствуют селектору, в том числе для экземпляров, зарегистрированных или получивших атрибуты позже.
+// Селектор сохраняется в канонической записи, для неверного селектора возвращается ошибка
+// persistent.ErrInvalidSelector.
+func (p *PostgreSQL) AssignSelectorPermissionToAccount(ctx context.Context, data *dto.UserIdSelectorPermission) error {
+	selector, err := canonicalSelector(data.Selector)
+	if err != nil {
+		return err
+	}
+
+	if err = p.checkNotDeprecated(ctx, servicePermissionDeprecatedStmt, data.Permission, data.Service); err != nil {
+		return err
+	}
+
+	stmt := `	INSERT INTO account_selector_permissions(account_fk, permission_fk, selector)
+				VALUES(
+					(SELECT account_id
+					FROM accounts
+					WHERE uuid = $1 AND deleted_at IS NULL),
+
+					(SELECT permission_id
+					FROM permissions
+					WHERE name = $2
+					  AND service_fk = (SELECT service_id FROM services WHERE name = $3 AND deleted_at IS NULL)
+					  AND deleted_at IS NULL),
+
+					$4
+				)`
+
+	return p.processExecResult(p.pool.ExecEx(ctx, stmt, nil, data.UserId, data.Permission, data.Service, selector))
+}
+
+// canonicalSelector возвращает каноническую запись селектора или ошибку persistent.ErrInvalidSelector.
+func canonicalSelector(text string) (string, error) {
+	selector, err := label_selector.Parse(text)
+	if err != nil {
+		return "", persistent.ErrInvalidSelector
+	}
+
+	return selector.String(), nil
+}
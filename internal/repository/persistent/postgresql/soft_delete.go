@@ -0,0 +1,189 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/lazylex/watch-store/secure/internal/dto"
+	"github.com/lazylex/watch-store/secure/internal/errors/persistent"
+	"time"
+)
+
+// softDeleteInstanceStmt запрос, помечающий удаленным действующий экземпляр с названием $1.
+const softDeleteInstanceStmt = `UPDATE instances SET deleted_at = now() WHERE name = $1 AND deleted_at IS NULL`
+
+// serviceEntityCondition условие отбора сущности с названием $1 сервиса $2 (в том числе удаленного).
+const serviceEntityCondition = `name = $1 AND service_fk = (SELECT service_id FROM services WHERE name = $2)`
+
+// DeleteRole помечает роль удаленной. Назначения роли сохраняются, но не учитываются до её восстановления.
+func (p *PostgreSQL) DeleteRole(ctx context.Context, data *dto.NameService) error {
+	return p.processExecResult(p.pool.ExecEx(ctx, softDeleteServiceEntityStmt("roles"), nil, data.Name, data.Service))
+}
+
+// DeleteGroup помечает группу удаленной. Назначения группы сохраняются, но не учитываются до её восстановления.
+func (p *PostgreSQL) DeleteGroup(ctx context.Context, data *dto.NameService) error {
+	return p.processExecResult(p.pool.ExecEx(ctx, softDeleteServiceEntityStmt("groups"), nil, data.Name, data.Service))
+}
+
+// DeletePermission помечает разрешение удаленным. Номер разрешения выводится из употребления при окончательном
+// удалении.
+func (p *PostgreSQL) DeletePermission(ctx context.Context, data *dto.NameService) error {
+	return p.processExecResult(p.pool.ExecEx(ctx, softDeleteServiceEntityStmt("permissions"), nil, data.Name, data.Service))
+}
+
+// DeleteInstance помечает экземпляр сервиса удаленным.
+func (p *PostgreSQL) DeleteInstance(ctx context.Context, name string) error {
+	return p.processExecResult(p.pool.ExecEx(ctx, softDeleteInstanceStmt, nil, name))
+}
+
+// DeleteService помечает удаленными сервис и все его действующие экземпляры, разрешения, роли и группы. Время удаления
+// у них совпадает, что позволяет при восстановлении сервиса вернуть только удаленные вместе с ним сущности.
+func (p *PostgreSQL) DeleteService(ctx context.Context, name string) error {
+	return p.processExecResult(p.pool.ExecEx(ctx, softDeleteServiceStmt(), nil, name))
+}
+
+// DeleteAccount помечает учетную запись удаленной. Войти в удаленную учетную запись нельзя, её назначения сохраняются,
+// но не учитываются до её восстановления.
+func (p *PostgreSQL) DeleteAccount(ctx context.Context, id uuid.UUID) error {
+	stmt := `UPDATE accounts SET deleted_at = now(), updated_at = now() WHERE uuid = $1 AND deleted_at IS NULL`
+	return p.processExecResult(p.pool.ExecEx(ctx, stmt, nil, id))
+}
+
+// RestoreRole восстанавливает удаленную роль действующего сервиса вместе с её назначениями.
+func (p *PostgreSQL) RestoreRole(ctx context.Context, data *dto.NameService) error {
+	return p.processExecResult(p.pool.ExecEx(ctx, restoreServiceEntityStmt("roles"), nil, data.Name, data.Service))
+}
+
+// RestoreGroup восстанавливает удаленную группу действующего сервиса вместе с её назначениями.
+func (p *PostgreSQL) RestoreGroup(ctx context.Context, data *dto.NameService) error {
+	return p.processExecResult(p.pool.ExecEx(ctx, restoreServiceEntityStmt("groups"), nil, data.Name, data.Service))
+}
+
+// RestorePermission восстанавливает удаленное разрешение действующего сервиса вместе с его номером и назначениями.
+func (p *PostgreSQL) RestorePermission(ctx context.Context, data *dto.NameService) error {
+	return p.processExecResult(p.pool.ExecEx(ctx, restoreServiceEntityStmt("permissions"), nil, data.Name, data.Service))
+}
+
+// RestoreInstance восстанавливает удаленный экземпляр действующего сервиса вместе с его назначениями.
+func (p *PostgreSQL) RestoreInstance(ctx context.Context, name string) error {
+	stmt := `	UPDATE instances
+				SET deleted_at = NULL
+				WHERE name = $1
+				  AND deleted_at IS NOT NULL
+				  AND service_fk IN (SELECT service_id FROM services WHERE deleted_at IS NULL)`
+	return p.processExecResult(p.pool.ExecEx(ctx, stmt, nil, name))
+}
+
+// RestoreService восстанавливает удаленный сервис и удаленные вместе с ним экземпляры, разрешения, роли и группы.
+// Сущности, удаленные до удаления сервиса, остаются удаленными.
+func (p *PostgreSQL) RestoreService(ctx context.Context, name string) error {
+	cte := `WITH
+			service_cte AS (SELECT service_id, deleted_at FROM services WHERE name = $1 AND deleted_at IS NOT NULL),
+			` + serviceChildrenCTE("restored_", "NULL", "deleted_at = (SELECT deleted_at FROM service_cte)")
+
+	stmt := cte + `	UPDATE services
+					SET deleted_at = NULL
+					WHERE service_id = (SELECT service_id FROM service_cte)`
+
+	return p.processExecResult(p.pool.ExecEx(ctx, stmt, nil, name))
+}
+
+// RestoreAccount восстанавливает удаленную учетную запись вместе с её назначениями.
+func (p *PostgreSQL) RestoreAccount(ctx context.Context, id uuid.UUID) error {
+	stmt := `UPDATE accounts SET deleted_at = NULL, updated_at = now() WHERE uuid = $1 AND deleted_at IS NOT NULL`
+	return p.processExecResult(p.pool.ExecEx(ctx, stmt, nil, id))
+}
+
+// PurgeDeleted в одной транзакции безвозвратно удаляет сущности, помеченные удаленными раньше before. Назначения
+// удаляемых сущностей удаляются каскадно, номера удаляемых разрешений выводятся из употребления.
+func (p *PostgreSQL) PurgeDeleted(ctx context.Context, before time.Time) error {
+	tx, err := p.pool.BeginEx(ctx, nil)
+	if err != nil {
+		return adaptErr(err)
+	}
+	defer func() { _ = tx.RollbackEx(ctx) }()
+
+	stmt := `WITH
+			deleted AS (DELETE FROM permissions WHERE deleted_at < $1 RETURNING service_fk, number)
+			INSERT INTO retired_permission_numbers (service_fk, number)
+			SELECT service_fk, number FROM deleted
+			ON CONFLICT DO NOTHING`
+	if _, err = tx.ExecEx(ctx, stmt, nil, before); err != nil {
+		return adaptErr(err)
+	}
+
+	for _, table := range []string{"roles", "groups", "instances", "services", "accounts"} {
+		if _, err = tx.ExecEx(ctx, `DELETE FROM `+table+` WHERE deleted_at < $1`, nil, before); err != nil {
+			return adaptErr(err)
+		}
+	}
+
+	return adaptErr(tx.CommitEx(ctx))
+}
+
+// softDeleteServiceEntityStmt возвращает запрос, помечающий удаленной действующую сущность с названием $1 сервиса $2 из
+// таблицы table (ролей, групп или разрешений).
+func softDeleteServiceEntityStmt(table string) string {
+	return `UPDATE ` + table + `
+			SET deleted_at = now()
+			WHERE name = $1
+			  AND service_fk = (SELECT service_id FROM services WHERE name = $2 AND deleted_at IS NULL)
+			  AND deleted_at IS NULL`
+}
+
+// softDeleteServiceStmt возвращает запрос, помечающий удаленными действующий сервис с названием $1 и все его
+// действующие экземпляры, разрешения, роли и группы с одинаковым временем удаления.
+func softDeleteServiceStmt() string {
+	cte := `WITH
+			service_cte AS (SELECT service_id FROM services WHERE name = $1 AND deleted_at IS NULL),
+			` + serviceChildrenCTE("deleted_", "now()", "deleted_at IS NULL")
+
+	return cte + `	UPDATE services
+					SET deleted_at = now()
+					WHERE service_id = (SELECT service_id FROM service_cte)`
+}
+
+// deletedConflict возвращает ошибку persistent.ErrDeletedEntity, если err означает нарушение уникальности, а в таблице
+// table есть удаленная сущность, удовлетворяющая условию condition с аргументами args. Такую сущность нужно
+// восстановить, а не создавать заново. В остальных случаях возвращается err.
+func (p *PostgreSQL) deletedConflict(ctx context.Context, err error, table, condition string, args ...interface{}) error {
+	if !errors.Is(err, persistent.ErrDuplicateKeyValue) {
+		return err
+	}
+
+	var deleted bool
+	stmt := `SELECT EXISTS (SELECT 1 FROM ` + table + ` WHERE deleted_at IS NOT NULL AND ` + condition + `)`
+	if p.pool.QueryRowEx(ctx, stmt, nil, args...).Scan(&deleted) == nil && deleted {
+		return persistent.ErrDeletedEntity
+	}
+
+	return err
+}
+
+// restoreServiceEntityStmt возвращает запрос, восстанавливающий удаленную сущность с названием $1 действующего сервиса
+// $2 из таблицы table (ролей, групп или разрешений).
+func restoreServiceEntityStmt(table string) string {
+	return `UPDATE ` + table + `
+			SET deleted_at = NULL
+			WHERE name = $1
+			  AND service_fk = (SELECT service_id FROM services WHERE name = $2 AND deleted_at IS NULL)
+			  AND deleted_at IS NOT NULL`
+}
+
+// serviceChildrenCTE возвращает общие табличные выражения с префиксом prefix, устанавливающие время удаления value
+// экземплярам, разрешениям, ролям и группам сервиса service_cte, отобранным условием condition.
+func serviceChildrenCTE(prefix, value, condition string) string {
+	var result string
+	for i, table := range []string{"instances", "permissions", "roles", "groups"} {
+		if i > 0 {
+			result += `,
+			`
+		}
+		result += prefix + table + ` AS (UPDATE ` + table + `
+							SET deleted_at = ` + value + `
+							WHERE service_fk = (SELECT service_id FROM service_cte)
+							  AND ` + condition + `)`
+	}
+
+	return result
+}
@@ -0,0 +1,87 @@
+package postgresql
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/lazylex/watch-store/secure/internal/dto"
+)
+
+// SetTOTPSecret сохраняет секрет для одноразовых паролей учетной записи. Секрет сохраняется неподтвержденным, а уже
+// подтвержденный секрет не перезаписывается.
+func (p *PostgreSQL) SetTOTPSecret(ctx context.Context, data *dto.UserIdSecret) error {
+	stmt := `	INSERT INTO totp_secrets (account_fk, secret, enabled)
+				VALUES ((SELECT account_id FROM accounts WHERE uuid = $1), $2, FALSE)
+				ON CONFLICT (account_fk) DO UPDATE
+				SET secret = EXCLUDED.secret
+				WHERE totp_secrets.enabled = FALSE;`
+
+	return p.processExecResult(p.pool.ExecEx(ctx, stmt, nil, data.UserId, data.Secret))
+}
+
+// TOTPSecret возвращает секрет для одноразовых паролей учетной записи и признак его подтверждения.
+func (p *PostgreSQL) TOTPSecret(ctx context.Context, id uuid.UUID) (dto.SecretEnabled, error) {
+	var result dto.SecretEnabled
+	stmt := `	SELECT secret, enabled
+				FROM totp_secrets
+				WHERE account_fk = (SELECT account_id FROM accounts WHERE uuid = $1);`
+
+	row := p.pool.QueryRowEx(ctx, stmt, nil, id)
+	if err := row.Scan(&result.Secret, &result.Enabled); err != nil {
+		return dto.SecretEnabled{}, adaptErr(err)
+	}
+
+	return result, nil
+}
+
+// EnableTOTP в одной транзакции подтверждает секрет одноразовых паролей учетной записи и заменяет её коды
+// восстановления переданными хешами.
+func (p *PostgreSQL) EnableTOTP(ctx context.Context, data *dto.UserIdCodes) error {
+	tx, err := p.pool.BeginEx(ctx, nil)
+	if err != nil {
+		return adaptErr(err)
+	}
+	defer func() { _ = tx.RollbackEx(ctx) }()
+
+	stmt := `	UPDATE totp_secrets
+				SET enabled = TRUE
+				WHERE account_fk = (SELECT account_id FROM accounts WHERE uuid = $1);`
+	if err = p.processExecResult(tx.ExecEx(ctx, stmt, nil, data.UserId)); err != nil {
+		return err
+	}
+
+	stmt = `DELETE FROM recovery_codes WHERE account_fk = (SELECT account_id FROM accounts WHERE uuid = $1);`
+	if _, err = tx.ExecEx(ctx, stmt, nil, data.UserId); err != nil {
+		return adaptErr(err)
+	}
+
+	stmt = `	INSERT INTO recovery_codes (account_fk, code_hash)
+				VALUES ((SELECT account_id FROM accounts WHERE uuid = $1), $2);`
+	for _, code := range data.Codes {
+		if _, err = tx.ExecEx(ctx, stmt, nil, data.UserId, code); err != nil {
+			return adaptErr(err)
+		}
+	}
+
+	return adaptErr(tx.CommitEx(ctx))
+}
+
+// DeleteTOTP удаляет секрет одноразовых паролей и коды восстановления учетной записи.
+func (p *PostgreSQL) DeleteTOTP(ctx context.Context, id uuid.UUID) error {
+	stmt := `	WITH
+				account_cte AS (SELECT account_id FROM accounts WHERE uuid = $1),
+				codes AS (DELETE FROM recovery_codes WHERE account_fk = (SELECT account_id FROM account_cte))
+				DELETE FROM totp_secrets WHERE account_fk = (SELECT account_id FROM account_cte);`
+
+	return p.processExecResult(p.pool.ExecEx(ctx, stmt, nil, id))
+}
+
+// UseRecoveryCode удаляет использованный код восстановления учетной записи. Если кода с переданным хешем нет,
+// возвращает ошибку persistent.ErrZeroRowsAffected.
+func (p *PostgreSQL) UseRecoveryCode(ctx context.Context, data *dto.UserIdCode) error {
+	stmt := `	DELETE FROM recovery_codes
+				WHERE account_fk = (SELECT account_id FROM accounts WHERE uuid = $1)
+				  AND
+				code_hash = $2;`
+
+	return p.processExecResult(p.pool.ExecEx(ctx, stmt, nil, data.UserId, data.Code))
+}
@@ -0,0 +1,261 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/lazylex/watch-store/secure/internal/domain/value_objects/account_state"
+	"github.com/lazylex/watch-store/secure/internal/domain/value_objects/account_type"
+	"github.com/lazylex/watch-store/secure/internal/domain/value_objects/grant_source"
+	"github.com/lazylex/watch-store/secure/internal/dto"
+	jointErr "github.com/lazylex/watch-store/secure/internal/errors/joint"
+	"slices"
+)
+
+// permissionResolver принимает решения о наличии разрешений, запоминая прочитанные из хранилища данные, чтобы при
+// пакетной проверке не запрашивать их повторно.
+type permissionResolver struct {
+	service            *Service
+	address            string
+	enabled            map[uuid.UUID]bool
+	services           map[string]string
+	instanceNumbers    map[dto.UserIdInstance][]int
+	conditionalNumbers map[dto.UserIdInstance][]int
+	serviceNumbers     map[dto.UserIdService][]int
+	sources            map[dto.UserIdService][]dto.NumberSource
+}
+
+// CheckPermission проверяет по запросу учетной записи caller с адреса address, имеет ли учетная запись разрешение с
+// переданным номером для экземпляра сервиса. Решение содержит пути, по которым разрешение назначено (grant_source).
+// Неизвестные учетная запись или экземпляр сервиса, как и неактивная учетная запись, приводят к отказу, а не к ошибке.
+func (s *Service) CheckPermission(ctx context.Context, caller uuid.UUID, address string, data *dto.UserIdInstanceNumber) (dto.PermissionDecision, error) {
+	decisions, err := s.CheckPermissions(ctx, caller, address, []dto.UserIdInstanceNumber{*data})
+	if err != nil {
+		return dto.PermissionDecision{}, err
+	}
+
+	return decisions[0], nil
+}
+
+// CheckPermissions выполняет проверку CheckPermission для каждого из переданных запросов и возвращает решения в том же
+// порядке. Разрешения других учетных записей могут проверять только администраторы и учетные записи сервисов,
+// остальным возвращается ошибка service.ErrForeignPermissionCheck. Решение принимается по тем же номерам разрешений,
+// что попадают в токен CreateToken: кешированным номерам разрешений сервиса и экземпляра и номерам назначений с
+// условием, выполненным для адреса address. Пути назначения читаются из постоянного хранилища только для разрешенных
+// проверок.
+func (s *Service) CheckPermissions(ctx context.Context, caller uuid.UUID, address string, data []dto.UserIdInstanceNumber) ([]dto.PermissionDecision, error) {
+	if err := s.authorizeChecks(ctx, caller, data); err != nil {
+		return nil, err
+	}
+
+	resolver := &permissionResolver{
+		service:            s,
+		address:            address,
+		enabled:            make(map[uuid.UUID]bool),
+		services:           make(map[string]string),
+		instanceNumbers:    make(map[dto.UserIdInstance][]int),
+		conditionalNumbers: make(map[dto.UserIdInstance][]int),
+		serviceNumbers:     make(map[dto.UserIdService][]int),
+		sources:            make(map[dto.UserIdService][]dto.NumberSource),
+	}
+
+	result := make([]dto.PermissionDecision, 0, len(data))
+	for i := range data {
+		decision, err := resolver.decide(ctx, &data[i])
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, decision)
+	}
+
+	return result, nil
+}
+
+// authorizeChecks возвращает ошибку service.ErrForeignPermissionCheck, если учетная запись caller запрашивает проверку
+// разрешений другой учетной записи, не являясь администратором или учетной записью сервиса.
+func (s *Service) authorizeChecks(ctx context.Context, caller uuid.UUID, data []dto.UserIdInstanceNumber) error {
+	if !slices.ContainsFunc(data, func(check dto.UserIdInstanceNumber) bool { return check.UserId != caller }) {
+		return nil
+	}
+
+	if isAdmin, err := s.IsAdmin(ctx, caller); err != nil || isAdmin {
+		return err
+	}
+
+	details, err := s.repository.AccountDetails(ctx, caller)
+	if err != nil {
+		return adaptErr(err)
+	}
+
+	if details.Type != account_type.Service {
+		return ErrForeignPermissionCheck()
+	}
+
+	return nil
+}
+
+// decide принимает решение по одному запросу проверки разрешения.
+func (r *permissionResolver) decide(ctx context.Context, data *dto.UserIdInstanceNumber) (dto.PermissionDecision, error) {
+	var (
+		enabled                                      bool
+		serviceName                                  string
+		instanceNumbers, conditional, serviceNumbers []int
+		sources                                      []dto.NumberSource
+		err                                          error
+	)
+
+	decision := dto.PermissionDecision{
+		UserId:     data.UserId,
+		Instance:   data.Instance,
+		Permission: data.Number,
+		Reasons:    []string{},
+	}
+
+	if enabled, err = r.accountEnabled(ctx, data.UserId); err != nil || !enabled {
+		return decision, err
+	}
+
+	if serviceName, err = r.serviceName(ctx, data.Instance); err != nil || len(serviceName) == 0 {
+		return decision, err
+	}
+
+	instance := dto.UserIdInstance{UserId: data.UserId, Instance: data.Instance}
+	if instanceNumbers, err = r.instancePermissionsNumbers(ctx, instance); err != nil {
+		return decision, err
+	}
+
+	if conditional, err = r.conditionalPermissions(ctx, instance); err != nil {
+		return decision, err
+	}
+
+	service := dto.UserIdService{UserId: data.UserId, Service: serviceName}
+	if serviceNumbers, err = r.servicePermissionsNumbers(ctx, service); err != nil {
+		return decision, err
+	}
+
+	byInstance := slices.Contains(instanceNumbers, data.Number)
+	byCondition := slices.Contains(conditional, data.Number)
+	byService := slices.Contains(serviceNumbers, data.Number)
+	decision.Allowed = byInstance || byCondition || byService
+
+	if byInstance {
+		decision.Reasons = append(decision.Reasons, grant_source.Instance)
+	}
+
+	if byCondition {
+		decision.Reasons = append(decision.Reasons, grant_source.Condition)
+	}
+
+	if byService {
+		if sources, err = r.servicePermissionsSources(ctx, service); err != nil {
+			return decision, err
+		}
+
+		for _, source := range sources {
+			if source.Number == data.Number {
+				decision.Reasons = append(decision.Reasons, source.Source)
+			}
+		}
+	}
+
+	return decision, nil
+}
+
+// accountEnabled возвращает true, если учетная запись существует и активна.
+func (r *permissionResolver) accountEnabled(ctx context.Context, id uuid.UUID) (bool, error) {
+	if enabled, ok := r.enabled[id]; ok {
+		return enabled, nil
+	}
+
+	loginData, err := r.service.repository.AccountLoginDataByUserId(ctx, id)
+	if err != nil && !errors.Is(err, jointErr.ErrEmptyResult) {
+		return false, adaptErr(err)
+	}
+
+	r.enabled[id] = err == nil && loginData.State == account_state.Enabled
+
+	return r.enabled[id], nil
+}
+
+// serviceName возвращает название сервиса экземпляра или пустую строку для неизвестного экземпляра.
+func (r *permissionResolver) serviceName(ctx context.Context, instance string) (string, error) {
+	if name, ok := r.services[instance]; ok {
+		return name, nil
+	}
+
+	name, err := r.service.repository.ServiceName(ctx, instance)
+	if err != nil && !errors.Is(err, jointErr.ErrEmptyResult) {
+		return "", adaptErr(err)
+	}
+
+	r.services[instance] = name
+
+	return name, nil
+}
+
+// instancePermissionsNumbers возвращает номера разрешений учетной записи, назначенных для экземпляра сервиса.
+func (r *permissionResolver) instancePermissionsNumbers(ctx context.Context, data dto.UserIdInstance) ([]int, error) {
+	if numbers, ok := r.instanceNumbers[data]; ok {
+		return numbers, nil
+	}
+
+	numbers, err := r.service.repository.InstancePermissionsNumbersForAccount(ctx, &data)
+	if err != nil && !errors.Is(err, jointErr.ErrEmptyResult) {
+		return nil, adaptErr(err)
+	}
+
+	r.instanceNumbers[data] = numbers
+
+	return numbers, nil
+}
+
+// conditionalPermissions возвращает номера разрешений учетной записи для экземпляра сервиса, назначенных с условием,
+// которое выполняется для адреса проверки.
+func (r *permissionResolver) conditionalPermissions(ctx context.Context, data dto.UserIdInstance) ([]int, error) {
+	if numbers, ok := r.conditionalNumbers[data]; ok {
+		return numbers, nil
+	}
+
+	numbers, err := r.service.conditionalPermissions(ctx,
+		&dto.UserIdInstanceAddress{UserId: data.UserId, Instance: data.Instance, Address: r.address})
+	if err != nil {
+		return nil, err
+	}
+
+	r.conditionalNumbers[data] = numbers
+
+	return numbers, nil
+}
+
+// servicePermissionsNumbers возвращает кешированные номера разрешений учетной записи для сервиса, по которым
+// выдаются токены.
+func (r *permissionResolver) servicePermissionsNumbers(ctx context.Context, data dto.UserIdService) ([]int, error) {
+	if numbers, ok := r.serviceNumbers[data]; ok {
+		return numbers, nil
+	}
+
+	numbers, err := r.service.repository.ServicePermissionsNumbersForAccount(ctx, &data)
+	if err != nil && !errors.Is(err, jointErr.ErrEmptyResult) {
+		return nil, adaptErr(err)
+	}
+
+	r.serviceNumbers[data] = numbers
+
+	return numbers, nil
+}
+
+// servicePermissionsSources возвращает номера разрешений учетной записи для сервиса с путями их назначения.
+func (r *permissionResolver) servicePermissionsSources(ctx context.Context, data dto.UserIdService) ([]dto.NumberSource, error) {
+	if sources, ok := r.sources[data]; ok {
+		return sources, nil
+	}
+
+	sources, err := r.service.repository.ServicePermissionsSourcesForAccount(ctx, &data)
+	if err != nil && !errors.Is(err, jointErr.ErrEmptyResult) {
+		return nil, adaptErr(err)
+	}
+
+	r.sources[data] = sources
+
+	return sources, nil
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"github.com/lazylex/watch-store/secure/internal/dto"
+	"github.com/lazylex/watch-store/secure/pkg/condition"
+	"time"
+)
+
+// SetInstanceAttribute устанавливает значение атрибута экземпляра сервиса, доступного в условиях назначений как
+// instance.<название>. Название атрибута должно быть допустимым идентификатором языка условий.
+func (s *Service) SetInstanceAttribute(ctx context.Context, data *dto.InstanceNameValue) error {
+	if len(data.Instance) == 0 || !condition.ValidAttributeName(data.Name) {
+		return ErrInvalidQueryParameters()
+	}
+
+	return adaptErr(s.repository.SetInstanceAttribute(ctx, data))
+}
+
+// DeleteInstanceAttribute удаляет атрибут экземпляра сервиса.
+func (s *Service) DeleteInstanceAttribute(ctx context.Context, data *dto.NameInstance) error {
+	return adaptErr(s.repository.DeleteInstanceAttribute(ctx, data))
+}
+
+// conditionalPermissions возвращает номера разрешений учетной записи для экземпляра сервиса, полученные по
+// назначениям, условия которых выполнены для адреса клиента и атрибутов экземпляра в текущий момент.
+func (s *Service) conditionalPermissions(ctx context.Context, data *dto.UserIdInstanceAddress) ([]int, error) {
+	permissions, err := s.repository.ConditionalPermissionsForAccount(ctx,
+		&dto.UserIdInstance{UserId: data.UserId, Instance: data.Instance})
+	if err != nil || len(permissions) == 0 {
+		return nil, adaptErr(err)
+	}
+
+	env, err := s.conditionEnvironment(ctx, data.Instance, data.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]int, 0, len(permissions))
+	for _, permission := range permissions {
+		if conditionMet(permission.Condition, env) {
+			result = append(result, permission.Number)
+		}
+	}
+
+	return result, nil
+}
+
+// conditionEnvironment возвращает атрибуты запроса к экземпляру сервиса instance с адреса address, по которым
+// вычисляются условия назначений. Если экземпляр не передан, атрибуты экземпляра не заполняются.
+func (s *Service) conditionEnvironment(ctx context.Context, instance, address string) (*condition.Environment, error) {
+	env := &condition.Environment{Time: time.Now(), Address: condition.ParseAddress(address)}
+
+	if len(instance) > 0 {
+		attributes, err := s.repository.InstanceAttributes(ctx, instance)
+		if err != nil {
+			return nil, adaptErr(err)
+		}
+		env.Instance = attributes
+	}
+
+	return env, nil
+}
+
+// conditionMet возвращает результат вычисления условия назначения. Условие, которое не удалось разобрать, считается
+// невыполненным.
+func conditionMet(text string, env *condition.Environment) bool {
+	parsed, err := condition.Parse(text)
+	return err == nil && parsed.Evaluate(env)
+}
+
+// validGrantCondition возвращает true, если условие назначения не задано либо является корректным выражением.
+func validGrantCondition(text string) bool {
+	return len(text) == 0 || condition.Valid(text)
+}
@@ -36,6 +36,18 @@ func adaptErrSkipFrames(err error, skip int) error {
 			return service.ErrNothingWasChanged.WithOrigin(be.Origin)
 		case message == joint.ErrEmptyResult.Message:
 			return service.ErrEmptyResult.WithOrigin(be.Origin)
+		case message == joint.ErrRetiredNumber.Message:
+			return service.ErrRetiredNumber.WithOrigin(be.Origin)
+		case message == joint.ErrDeprecatedPermission.Message:
+			return service.ErrDeprecatedPermission.WithOrigin(be.Origin)
+		case message == joint.ErrRoleCycle.Message:
+			return service.ErrRoleCycle.WithOrigin(be.Origin)
+		case message == joint.ErrGroupCycle.Message:
+			return service.ErrGroupCycle.WithOrigin(be.Origin)
+		case message == joint.ErrInvalidSelector.Message:
+			return service.ErrInvalidQueryParameters.WithOrigin(be.Origin)
+		case message == joint.ErrDeletedEntity.Message:
+			return service.ErrDeletedEntity.WithOrigin(be.Origin)
 		}
 
 		if be.Type == service.ErrServiceType {
@@ -65,3 +77,93 @@ func ErrNotEnabledAccount() error {
 func ErrLogout() error {
 	return withOrigin(service.ErrLogout)
 }
+
+// ErrInvalidResetToken возвращает ошибку service.ErrInvalidResetToken с местом генерации ошибки.
+func ErrInvalidResetToken() error {
+	return withOrigin(service.ErrInvalidResetToken)
+}
+
+// ErrSecondFactorRequired возвращает ошибку service.ErrSecondFactorRequired с местом генерации ошибки.
+func ErrSecondFactorRequired() error {
+	return withOrigin(service.ErrSecondFactorRequired)
+}
+
+// ErrInvalidSecondFactor возвращает ошибку service.ErrInvalidSecondFactor с местом генерации ошибки.
+func ErrInvalidSecondFactor() error {
+	return withOrigin(service.ErrInvalidSecondFactor)
+}
+
+// ErrInvalidLoginChallenge возвращает ошибку service.ErrInvalidLoginChallenge с местом генерации ошибки.
+func ErrInvalidLoginChallenge() error {
+	return withOrigin(service.ErrInvalidLoginChallenge)
+}
+
+// ErrTOTPAlreadyEnabled возвращает ошибку service.ErrTOTPAlreadyEnabled с местом генерации ошибки.
+func ErrTOTPAlreadyEnabled() error {
+	return withOrigin(service.ErrTOTPAlreadyEnabled)
+}
+
+// ErrTOTPNotEnrolled возвращает ошибку service.ErrTOTPNotEnrolled с местом генерации ошибки.
+func ErrTOTPNotEnrolled() error {
+	return withOrigin(service.ErrTOTPNotEnrolled)
+}
+
+// ErrNotTokenOwner возвращает ошибку service.ErrNotTokenOwner с местом генерации ошибки.
+func ErrNotTokenOwner() error {
+	return withOrigin(service.ErrNotTokenOwner)
+}
+
+// ErrForeignPermissionCheck возвращает ошибку service.ErrForeignPermissionCheck с местом генерации ошибки.
+func ErrForeignPermissionCheck() error {
+	return withOrigin(service.ErrForeignPermissionCheck)
+}
+
+// ErrOIDCDisabled возвращает ошибку service.ErrOIDCDisabled с местом генерации ошибки.
+func ErrOIDCDisabled() error {
+	return withOrigin(service.ErrOIDCDisabled)
+}
+
+// ErrInvalidOIDCClient возвращает ошибку service.ErrInvalidOIDCClient с местом генерации ошибки.
+func ErrInvalidOIDCClient() error {
+	return withOrigin(service.ErrInvalidOIDCClient)
+}
+
+// ErrInvalidAuthorizationRequest возвращает ошибку service.ErrInvalidAuthorizationRequest с местом генерации ошибки.
+func ErrInvalidAuthorizationRequest() error {
+	return withOrigin(service.ErrInvalidAuthorizationRequest)
+}
+
+// ErrInvalidGrant возвращает ошибку service.ErrInvalidGrant с местом генерации ошибки.
+func ErrInvalidGrant() error {
+	return withOrigin(service.ErrInvalidGrant)
+}
+
+// ErrInvalidAccessToken возвращает ошибку service.ErrInvalidAccessToken с местом генерации ошибки.
+func ErrInvalidAccessToken() error {
+	return withOrigin(service.ErrInvalidAccessToken)
+}
+
+// ErrInvalidQueryParameters возвращает ошибку service.ErrInvalidQueryParameters с местом генерации ошибки.
+func ErrInvalidQueryParameters() error {
+	return withOrigin(service.ErrInvalidQueryParameters)
+}
+
+// ErrInvalidRBACDocument возвращает ошибку service.ErrInvalidRBACDocument с местом генерации ошибки.
+func ErrInvalidRBACDocument() error {
+	return withOrigin(service.ErrInvalidRBACDocument)
+}
+
+// ErrRBACConflict возвращает ошибку service.ErrRBACConflict с местом генерации ошибки.
+func ErrRBACConflict() error {
+	return withOrigin(service.ErrRBACConflict)
+}
+
+// ErrAdministratorDeletion возвращает ошибку service.ErrAdministratorDeletion с местом генерации ошибки.
+func ErrAdministratorDeletion() error {
+	return withOrigin(service.ErrAdministratorDeletion)
+}
+
+// ErrOwnAccountDeletion возвращает ошибку service.ErrOwnAccountDeletion с местом генерации ошибки.
+func ErrOwnAccountDeletion() error {
+	return withOrigin(service.ErrOwnAccountDeletion)
+}
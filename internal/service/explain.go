@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/lazylex/watch-store/secure/internal/domain/value_objects/grant_source"
+	"github.com/lazylex/watch-store/secure/internal/dto"
+	"github.com/lazylex/watch-store/secure/pkg/condition"
+	"strings"
+)
+
+// ExplainPermissions возвращает разрешения учетной записи для сервиса с каждым из путей, по которым они назначены,
+// например: group "Персонал магазина" → role "Продавец" → permission 7. Для разрешений, унаследованных от
+// родительских ролей, путь продолжается цепочкой наследования: role "Менеджер" → role "Продавец" → permission 7, а
+// для разрешений групп, в которые вложена группа учетной записи, - цепочкой объемлющих групп: group "Кассиры" →
+// group "Персонал магазина" → permission 7. Разрешения ролей глобальной группы объясняются как global group "Сервис
+// заказа" → role "Заказ" → permission 7.
+// Если передано название экземпляра сервиса, сервис определяется по нему, а в результат попадают и разрешения,
+// назначенные для экземпляра напрямую, через роли и группы экземпляра: instance "store-1" → role "Продавец" →
+// permission 7 или instance "store-1" → group "Кассиры" → role "Продавец" → permission 7, а также разрешения,
+// назначенные по селектору атрибутов экземпляра: instance "store-1" → selector "city=Донецк" → permission 7.
+// Для путей, начинающихся с назначения с условием, условие вычисляется для текущего времени, переданного адреса
+// клиента и атрибутов экземпляра сервиса, а его текст и результат добавляются к пути: role "Продавец" → permission 7
+// when time_between("09:00", "21:00").
+func (s *Service) ExplainPermissions(ctx context.Context, data *dto.UserIdServiceInstance) ([]dto.NumberNamePaths, error) {
+	var (
+		err  error
+		rows []dto.NumberNameGrantPath
+	)
+
+	request := *data
+	if len(request.Instance) > 0 {
+		if request.Service, err = s.repository.ServiceName(ctx, request.Instance); err != nil {
+			return nil, adaptErr(err)
+		}
+	}
+
+	if rows, err = s.repository.PermissionsGrantPathsForAccount(ctx, &request); err != nil {
+		return nil, adaptErr(err)
+	}
+
+	var env *condition.Environment
+	result := make([]dto.NumberNamePaths, 0)
+	for _, row := range rows {
+		if len(row.GrantPath.Condition) > 0 {
+			if env == nil {
+				if env, err = s.conditionEnvironment(ctx, request.Instance, request.Address); err != nil {
+					return nil, err
+				}
+			}
+			met := conditionMet(row.GrantPath.Condition, env)
+			row.GrantPath.ConditionMet = &met
+		}
+
+		row.GrantPath.Path = grantPathText(row.GrantPath, row.Number)
+		if last := len(result) - 1; last >= 0 && result[last].Number == row.Number {
+			result[last].Paths = append(result[last].Paths, row.GrantPath)
+			continue
+		}
+		result = append(result, dto.NumberNamePaths{Number: row.Number, Name: row.Name, Paths: []dto.GrantPath{row.GrantPath}})
+	}
+
+	return result, nil
+}
+
+// grantPathText возвращает текстовое представление пути назначения разрешения с переданным номером.
+func grantPathText(path dto.GrantPath, number int) string {
+	var steps []string
+
+	switch path.Source {
+	case grant_source.Instance, grant_source.InstanceRole, grant_source.InstanceGroup, grant_source.InstanceGroupRole,
+		grant_source.Selector:
+		steps = append(steps, fmt.Sprintf("instance %q", path.Instance))
+	}
+
+	switch path.Source {
+	case grant_source.Selector:
+		steps = append(steps, fmt.Sprintf("selector %q", path.Selector))
+	case grant_source.Role, grant_source.InstanceRole:
+		steps = append(steps, fmt.Sprintf("role %q", path.Role))
+	case grant_source.Group, grant_source.GroupRole, grant_source.InstanceGroup, grant_source.InstanceGroupRole:
+		steps = append(steps, fmt.Sprintf("group %q", path.Group))
+		for _, group := range path.ParentGroups {
+			steps = append(steps, fmt.Sprintf("group %q", group))
+		}
+		if path.Source == grant_source.GroupRole || path.Source == grant_source.InstanceGroupRole {
+			steps = append(steps, fmt.Sprintf("role %q", path.Role))
+		}
+	case grant_source.GlobalGroupRole:
+		steps = append(steps, fmt.Sprintf("global group %q", path.GlobalGroup), fmt.Sprintf("role %q", path.Role))
+	}
+
+	for _, role := range path.InheritedFrom {
+		steps = append(steps, fmt.Sprintf("role %q", role))
+	}
+
+	text := strings.Join(append(steps, fmt.Sprintf("permission %d", number)), " → ")
+	if len(path.Condition) > 0 {
+		text += " when " + path.Condition
+	}
+
+	return text
+}
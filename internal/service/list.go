@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"github.com/google/uuid"
+	"github.com/lazylex/watch-store/secure/internal/domain/value_objects/account_type"
+	"github.com/lazylex/watch-store/secure/internal/dto"
+)
+
+const (
+	defaultPageSize = 50  // Размер страницы, если он не передан в запросе
+	maxPageSize     = 500 // Максимальный размер страницы
+)
+
+// listQuery преобразует запрос страницы в запрос к хранилищу: раскодирует курсор и ограничивает размер страницы.
+// Из хранилища запрашивается на одну запись больше, чтобы определить наличие следующей страницы.
+func listQuery(data *dto.PageRequest) (dto.ListQuery, error) {
+	after, err := base64.RawURLEncoding.DecodeString(data.Cursor)
+	if err != nil || data.Limit < 0 {
+		return dto.ListQuery{}, ErrInvalidQueryParameters()
+	}
+
+	limit := data.Limit
+	if limit == 0 {
+		limit = defaultPageSize
+	}
+	limit = min(limit, maxPageSize)
+
+	return dto.ListQuery{
+		Service:    data.Service,
+		Filter:     data.Filter,
+		After:      string(after),
+		Limit:      limit + 1,
+		Descending: data.Descending,
+		Type:       data.Type,
+		OwnerTeam:  data.OwnerTeam,
+	}, nil
+}
+
+// list выполняет постраничный запрос fetch к хранилищу и возвращает записи страницы и курсор следующей страницы. Если
+// записей больше запрошенного количества, лишняя отбрасывается, а ключ сортировки последней записи страницы становится
+// курсором следующей страницы.
+func list[T any](ctx context.Context, data *dto.PageRequest, fetch func(context.Context, *dto.ListQuery) ([]T, error),
+	key func(T) string) ([]T, string, error) {
+	query, err := listQuery(data)
+	if err != nil {
+		return nil, "", err
+	}
+
+	items, err := fetch(ctx, &query)
+	if err != nil {
+		return nil, "", adaptErr(err)
+	}
+
+	if len(items) < query.Limit {
+		return items, "", nil
+	}
+
+	items = items[:query.Limit-1]
+
+	return items, base64.RawURLEncoding.EncodeToString([]byte(key(items[len(items)-1]))), nil
+}
+
+// nameOfNameDescription возвращает ключ сортировки названия и описания.
+func nameOfNameDescription(item dto.NameDescription) string {
+	return item.Name
+}
+
+// Services возвращает страницу сервисов, отсортированных по названию, с фильтрацией по части названия.
+func (s *Service) Services(ctx context.Context, data *dto.PageRequest) (dto.NameDescriptionPage, error) {
+	items, next, err := list(ctx, data, s.repository.Services, nameOfNameDescription)
+	return dto.NameDescriptionPage{Items: items, NextCursor: next}, err
+}
+
+// Instances возвращает страницу экземпляров сервиса (или всех сервисов, если сервис не передан), отсортированных по
+// названию.
+func (s *Service) Instances(ctx context.Context, data *dto.PageRequest) (dto.NameServicePage, error) {
+	items, next, err := list(ctx, data, s.repository.Instances, func(item dto.NameService) string { return item.Name })
+	return dto.NameServicePage{Items: items, NextCursor: next}, err
+}
+
+// Roles возвращает страницу ролей сервиса, отсортированных по названию. Название сервиса обязательно.
+func (s *Service) Roles(ctx context.Context, data *dto.PageRequest) (dto.NameDescriptionPage, error) {
+	if len(data.Service) == 0 {
+		return dto.NameDescriptionPage{}, ErrInvalidQueryParameters()
+	}
+
+	items, next, err := list(ctx, data, s.repository.Roles, nameOfNameDescription)
+	return dto.NameDescriptionPage{Items: items, NextCursor: next}, err
+}
+
+// Groups возвращает страницу групп сервиса, отсортированных по названию. Название сервиса обязательно.
+func (s *Service) Groups(ctx context.Context, data *dto.PageRequest) (dto.NameDescriptionPage, error) {
+	if len(data.Service) == 0 {
+		return dto.NameDescriptionPage{}, ErrInvalidQueryParameters()
+	}
+
+	items, next, err := list(ctx, data, s.repository.Groups, nameOfNameDescription)
+	return dto.NameDescriptionPage{Items: items, NextCursor: next}, err
+}
+
+// Accounts возвращает страницу учетных записей, отсортированных по логину, с фильтрацией по части логина или
+// отображаемого имени, типу учетной записи и команде-владельцу.
+func (s *Service) Accounts(ctx context.Context, data *dto.PageRequest) (dto.UserIdLoginStatePage, error) {
+	if len(data.Type) > 0 && !account_type.Valid(data.Type) {
+		return dto.UserIdLoginStatePage{}, ErrInvalidQueryParameters()
+	}
+
+	items, next, err := list(ctx, data, s.repository.Accounts, func(item dto.UserIdLoginState) string { return string(item.Login) })
+	return dto.UserIdLoginStatePage{Items: items, NextCursor: next}, err
+}
+
+// ServiceDetails возвращает описание сервиса, его экземпляры, разрешения, роли и группы.
+func (s *Service) ServiceDetails(ctx context.Context, name string) (dto.ServiceDetails, error) {
+	result, err := s.repository.ServiceDetails(ctx, name)
+	return result, adaptErr(err)
+}
+
+// InstanceDetails возвращает название экземпляра, сервиса, к которому он относится, и атрибуты экземпляра.
+func (s *Service) InstanceDetails(ctx context.Context, name string) (dto.InstanceDetails, error) {
+	service, err := s.repository.ServiceName(ctx, name)
+	if err != nil {
+		return dto.InstanceDetails{}, adaptErr(err)
+	}
+
+	attributes, err := s.repository.InstanceAttributes(ctx, name)
+	if err != nil {
+		return dto.InstanceDetails{}, adaptErr(err)
+	}
+
+	return dto.InstanceDetails{Name: name, Service: service, Attributes: attributes}, nil
+}
+
+// RoleDetails возвращает описание роли и назначенные ей разрешения.
+func (s *Service) RoleDetails(ctx context.Context, data *dto.NameService) (dto.RoleDetails, error) {
+	result, err := s.repository.RoleDetails(ctx, data)
+	return result, adaptErr(err)
+}
+
+// GroupDetails возвращает описание группы и назначенные ей роли и разрешения.
+func (s *Service) GroupDetails(ctx context.Context, data *dto.NameService) (dto.GroupDetails, error) {
+	result, err := s.repository.GroupDetails(ctx, data)
+	return result, adaptErr(err)
+}
+
+// AccountDetails возвращает данные учетной записи, назначенные ей роли и группы и разрешения для экземпляров.
+func (s *Service) AccountDetails(ctx context.Context, id uuid.UUID) (dto.AccountDetails, error) {
+	result, err := s.repository.AccountDetails(ctx, id)
+	return result, adaptErr(err)
+}
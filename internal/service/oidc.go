@@ -0,0 +1,370 @@
+package service
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/lazylex/watch-store/secure/internal/domain/value_objects/account_state"
+	"github.com/lazylex/watch-store/secure/internal/domain/value_objects/login"
+	"github.com/lazylex/watch-store/secure/internal/domain/value_objects/password"
+	"github.com/lazylex/watch-store/secure/internal/dto"
+	jointErr "github.com/lazylex/watch-store/secure/internal/errors/joint"
+	se "github.com/lazylex/watch-store/secure/internal/errors/service"
+	"golang.org/x/crypto/bcrypt"
+	"math/big"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	oidcScope               = "openid"
+	codeChallengeMethodS256 = "S256"
+	minCodeVerifierLength   = 43 // RFC 7636, раздел 4.1
+	maxCodeVerifierLength   = 128
+	clientSecretLength      = 32
+	accessTokenType         = "at+jwt" // RFC 9068, раздел 2.1
+)
+
+// OIDCEnabled возвращает true, если заданы издатель и ключ подписи ID-токенов OpenID Connect.
+func (s *Service) OIDCEnabled() bool {
+	return s.oidcKey != nil && len(s.secure.OIDCIssuer) > 0
+}
+
+// OpenIDConfiguration возвращает метаданные провайдера OpenID Connect для /.well-known/openid-configuration.
+func (s *Service) OpenIDConfiguration() dto.OpenIDConfiguration {
+	issuer := strings.TrimSuffix(s.secure.OIDCIssuer, "/")
+
+	return dto.OpenIDConfiguration{
+		Issuer:                            issuer,
+		AuthorizationEndpoint:             issuer + "/authorize",
+		TokenEndpoint:                     issuer + "/token",
+		UserinfoEndpoint:                  issuer + "/userinfo",
+		JwksURI:                           issuer + "/jwks",
+		ResponseTypesSupported:            []string{"code"},
+		GrantTypesSupported:               []string{"authorization_code"},
+		SubjectTypesSupported:             []string{"public"},
+		IDTokenSigningAlgValuesSupported:  []string{jwt.SigningMethodRS256.Alg()},
+		ScopesSupported:                   []string{oidcScope, "profile"},
+		ClaimsSupported:                   []string{"iss", "sub", "aud", "exp", "iat", "nonce", "preferred_username"},
+		CodeChallengeMethodsSupported:     []string{codeChallengeMethodS256},
+		TokenEndpointAuthMethodsSupported: []string{"none", "client_secret_basic", "client_secret_post"},
+	}
+}
+
+// JSONWebKeySet возвращает открытый ключ подписи ID-токенов в формате JWK Set (RFC 7517).
+func (s *Service) JSONWebKeySet() dto.JSONWebKeySet {
+	if s.oidcKey == nil {
+		return dto.JSONWebKeySet{Keys: []dto.JSONWebKey{}}
+	}
+
+	return dto.JSONWebKeySet{Keys: []dto.JSONWebKey{{
+		Kty: "RSA",
+		Use: "sig",
+		Alg: jwt.SigningMethodRS256.Alg(),
+		Kid: s.oidcKeyId,
+		N:   base64.RawURLEncoding.EncodeToString(s.oidcKey.PublicKey.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(s.oidcKey.PublicKey.E)).Bytes()),
+	}}}
+}
+
+// RegisterOIDCClient регистрирует клиента OpenID Connect. Для конфиденциального клиента создаётся секрет, который
+// возвращается только один раз, а в хранилище сохраняется его хеш. Для публичного клиента возвращается пустая строка.
+func (s *Service) RegisterOIDCClient(ctx context.Context, data *dto.OIDCClientRegistration) (string, error) {
+	var secret string
+	var hash []byte
+	var err error
+
+	if len(data.ClientId) == 0 || len(data.Name) == 0 || len(data.RedirectURIs) == 0 {
+		return "", ErrInvalidOIDCClient()
+	}
+
+	for _, redirectURI := range data.RedirectURIs {
+		if !validRedirectURI(redirectURI) {
+			return "", ErrInvalidOIDCClient()
+		}
+	}
+
+	client := dto.OIDCClient{ClientId: data.ClientId, Name: data.Name, RedirectURIs: data.RedirectURIs}
+
+	if data.Confidential {
+		if secret, err = randomHex(clientSecretLength); err != nil {
+			return "", adaptErr(err)
+		}
+		if hash, err = bcrypt.GenerateFromPassword([]byte(secret), s.secure.PasswordCreationCost); err != nil {
+			return "", se.ErrCreatePwdHash
+		}
+		client.SecretHash = string(hash)
+	}
+
+	if err = s.repository.CreateOIDCClient(ctx, &client); err != nil {
+		return "", adaptErr(err)
+	}
+
+	return secret, nil
+}
+
+// Authorize обрабатывает запрос авторизации OpenID Connect (authorization code flow) от аутентифицированного
+// пользователя и возвращает одноразовый код авторизации. Использование PKCE с методом S256 обязательно. Неизвестный
+// клиент или незарегистрированный адрес перенаправления приводят к ошибке service.ErrInvalidOIDCClient, при которой
+// перенаправлять пользователя нельзя. Прочие ошибки запроса - service.ErrInvalidAuthorizationRequest.
+func (s *Service) Authorize(ctx context.Context, data *dto.AuthorizationRequest) (string, error) {
+	var client dto.OIDCClient
+	var code string
+	var err error
+
+	if !s.OIDCEnabled() {
+		return "", ErrOIDCDisabled()
+	}
+
+	if client, err = s.repository.OIDCClient(ctx, data.ClientId); err != nil {
+		return "", inactiveClientOrErr(err)
+	}
+
+	if !slices.Contains(client.RedirectURIs, data.RedirectURI) {
+		return "", ErrInvalidOIDCClient()
+	}
+
+	if data.ResponseType != "code" || !slices.Contains(strings.Fields(data.Scope), oidcScope) ||
+		data.CodeChallengeMethod != codeChallengeMethodS256 || len(data.CodeChallenge) == 0 {
+		return "", ErrInvalidAuthorizationRequest()
+	}
+
+	if code, err = s.createToken(); err != nil {
+		return "", adaptErr(err)
+	}
+
+	if err = s.repository.SaveAuthorizationCode(ctx, &dto.AuthorizationCode{
+		Code:          code,
+		UserId:        data.UserId,
+		ClientId:      data.ClientId,
+		RedirectURI:   data.RedirectURI,
+		CodeChallenge: data.CodeChallenge,
+		Nonce:         data.Nonce,
+		Scope:         data.Scope,
+	}); err != nil {
+		return "", adaptErr(err)
+	}
+
+	return code, nil
+}
+
+// ExchangeAuthorizationCode обменивает код авторизации на access token и ID-токен, содержащий UUID учетной записи (sub)
+// и её логин (preferred_username). Проверяются принадлежность кода клиенту, совпадение адреса перенаправления,
+// code_verifier (PKCE) и секрет конфиденциального клиента. Access token выдаётся для клиента и запрошенных им scope и
+// не является токеном сессии, поэтому не даёт клиенту прав пользователя в остальных точках доступа приложения.
+func (s *Service) ExchangeAuthorizationCode(ctx context.Context, data *dto.AuthorizationCodeExchange) (dto.OIDCTokens, error) {
+	var (
+		request     dto.AuthorizationCode
+		client      dto.OIDCClient
+		loginData   dto.UserIdLoginHashState
+		correct     bool
+		accessToken string
+		idToken     string
+		err         error
+	)
+
+	if !s.OIDCEnabled() {
+		return dto.OIDCTokens{}, ErrOIDCDisabled()
+	}
+
+	if client, err = s.repository.OIDCClient(ctx, data.ClientId); err != nil {
+		return dto.OIDCTokens{}, inactiveClientOrErr(err)
+	}
+
+	if len(client.SecretHash) > 0 {
+		if correct, err = s.comparePassword(ctx, client.SecretHash, password.Password(data.ClientSecret)); err != nil {
+			return dto.OIDCTokens{}, err
+		}
+		if !correct {
+			s.metrics.AuthenticationErrorInc()
+			return dto.OIDCTokens{}, ErrInvalidOIDCClient()
+		}
+	}
+
+	if request, err = s.repository.AuthorizationCode(ctx, data.Code); err != nil {
+		return dto.OIDCTokens{}, ErrInvalidGrant()
+	}
+
+	if request.ClientId != data.ClientId || request.RedirectURI != data.RedirectURI ||
+		!verifyCodeChallenge(request.CodeChallenge, data.CodeVerifier) {
+		return dto.OIDCTokens{}, ErrInvalidGrant()
+	}
+
+	if loginData, err = s.repository.AccountLoginDataByUserId(ctx, request.UserId); err != nil {
+		return dto.OIDCTokens{}, adaptErr(err)
+	}
+
+	if loginData.State != account_state.Enabled {
+		return dto.OIDCTokens{}, ErrNotEnabledAccount()
+	}
+
+	if idToken, err = s.createIDToken(&request, loginData.Login); err != nil {
+		return dto.OIDCTokens{}, adaptErr(err)
+	}
+
+	if accessToken, err = s.createAccessToken(&request); err != nil {
+		return dto.OIDCTokens{}, adaptErr(err)
+	}
+
+	go s.updateLastLogin(request.UserId)
+
+	return dto.OIDCTokens{AccessToken: accessToken, IDToken: idToken, TTL: s.secure.TokenTTL}, nil
+}
+
+// UserIdFromAccessToken проверяет access token, выданный функцией ExchangeAuthorizationCode, и возвращает UUID
+// учетной записи, для которой он выдан. Токен должен быть подписан ключом OpenID Connect, не просрочен и содержать
+// scope openid. ID-токены и токены сессий приводят к ошибке service.ErrInvalidAccessToken.
+func (s *Service) UserIdFromAccessToken(token string) (uuid.UUID, error) {
+	if !s.OIDCEnabled() {
+		return uuid.Nil, ErrOIDCDisabled()
+	}
+
+	parsed, err := jwt.Parse(token, func(*jwt.Token) (any, error) {
+		return &s.oidcKey.PublicKey, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg()}), jwt.WithExpirationRequired(),
+		jwt.WithIssuer(strings.TrimSuffix(s.secure.OIDCIssuer, "/")))
+	if err != nil || parsed.Header["typ"] != accessTokenType {
+		return uuid.Nil, ErrInvalidAccessToken()
+	}
+
+	claims := parsed.Claims.(jwt.MapClaims)
+	if scope, _ := claims["scope"].(string); !slices.Contains(strings.Fields(scope), oidcScope) {
+		return uuid.Nil, ErrInvalidAccessToken()
+	}
+
+	subject, _ := claims.GetSubject()
+	id, err := uuid.Parse(subject)
+	if err != nil {
+		return uuid.Nil, ErrInvalidAccessToken()
+	}
+
+	return id, nil
+}
+
+// UserInfo возвращает логин учетной записи для ответа /userinfo.
+func (s *Service) UserInfo(ctx context.Context, id uuid.UUID) (login.Login, error) {
+	loginData, err := s.repository.AccountLoginDataByUserId(ctx, id)
+	if err != nil {
+		return "", adaptErr(err)
+	}
+
+	return loginData.Login, nil
+}
+
+// createIDToken создаёт подписанный ключом OpenID Connect ID-токен.
+func (s *Service) createIDToken(request *dto.AuthorizationCode, accountLogin login.Login) (string, error) {
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":                strings.TrimSuffix(s.secure.OIDCIssuer, "/"),
+		"sub":                request.UserId.String(),
+		"aud":                request.ClientId,
+		"exp":                now.Add(s.secure.TokenTTL).Unix(),
+		"iat":                now.Unix(),
+		"preferred_username": string(accountLogin),
+	}
+	if len(request.Nonce) > 0 {
+		claims["nonce"] = request.Nonce
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = s.oidcKeyId
+
+	return token.SignedString(s.oidcKey)
+}
+
+// createAccessToken создаёт подписанный ключом OpenID Connect access token в формате RFC 9068 с клиентом в качестве
+// получателя (aud) и запрошенными им scope.
+func (s *Service) createAccessToken(request *dto.AuthorizationCode) (string, error) {
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":       strings.TrimSuffix(s.secure.OIDCIssuer, "/"),
+		"sub":       request.UserId.String(),
+		"aud":       request.ClientId,
+		"client_id": request.ClientId,
+		"scope":     request.Scope,
+		"exp":       now.Add(s.secure.TokenTTL).Unix(),
+		"iat":       now.Unix(),
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = s.oidcKeyId
+	token.Header["typ"] = accessTokenType
+
+	return token.SignedString(s.oidcKey)
+}
+
+// verifyCodeChallenge проверяет соответствие code_verifier сохраненному code_challenge по методу S256 (RFC 7636).
+func verifyCodeChallenge(challenge, verifier string) bool {
+	if len(verifier) < minCodeVerifierLength || len(verifier) > maxCodeVerifierLength {
+		return false
+	}
+
+	sum := sha256.Sum256([]byte(verifier))
+	computed := base64.RawURLEncoding.EncodeToString(sum[:])
+
+	return subtle.ConstantTimeCompare([]byte(computed), []byte(challenge)) == 1
+}
+
+// validRedirectURI возвращает true, если адрес перенаправления является абсолютным URL без фрагмента (RFC 6749,
+// раздел 3.1.2).
+func validRedirectURI(redirectURI string) bool {
+	u, err := url.Parse(redirectURI)
+
+	return err == nil && u.IsAbs() && len(u.Host) > 0 && len(u.Fragment) == 0
+}
+
+// inactiveClientOrErr переводит отсутствие клиента в хранилище в ошибку service.ErrInvalidOIDCClient.
+func inactiveClientOrErr(err error) error {
+	if errors.Is(err, jointErr.ErrEmptyResult) {
+		return withOrigin(se.ErrInvalidOIDCClient)
+	}
+
+	return adaptErrSkipFrames(err, 2)
+}
+
+// loadSigningKey загружает закрытый ключ RSA в формате PEM (PKCS #1 или PKCS #8).
+func loadSigningKey(file string) (*rsa.PrivateKey, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no pem data in %s", file)
+	}
+
+	if key, errPKCS1 := x509.ParsePKCS1PrivateKey(block.Bytes); errPKCS1 == nil {
+		return key, nil
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("key in %s is not an rsa key", file)
+	}
+
+	return key, nil
+}
+
+// signingKeyId возвращает идентификатор ключа (kid) - хеш SHA-256 открытого ключа в кодировке base64url.
+func signingKeyId(key *rsa.PublicKey) string {
+	sum := sha256.Sum256(x509.MarshalPKCS1PublicKey(key))
+
+	return base64.RawURLEncoding.EncodeToString(sum[:16])
+}
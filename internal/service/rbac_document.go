@@ -0,0 +1,936 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/lazylex/watch-store/secure/internal/domain/value_objects/account_type"
+	"github.com/lazylex/watch-store/secure/internal/domain/value_objects/label_selector"
+	"github.com/lazylex/watch-store/secure/internal/domain/value_objects/login"
+	"github.com/lazylex/watch-store/secure/internal/domain/value_objects/permission_encoding"
+	"github.com/lazylex/watch-store/secure/internal/dto"
+	"slices"
+	"sort"
+	"strings"
+)
+
+const (
+	RBACDocumentVersion  = 1  // Версия формата документа с конфигурацией управления доступом
+	importedSecretLength = 64 // Длина секрета экземпляров сервисов, создаваемых при импорте
+)
+
+// Действия, перечисляемые в отчете об импорте.
+const (
+	actionCreate    = "create"
+	actionUpdate    = "update"
+	actionAssign    = "assign"
+	actionDelete    = "delete"
+	actionUnassign  = "unassign"
+	actionDeprecate = "deprecate"
+	actionRetire    = "retire"
+)
+
+// ExportRBAC возвращает документ с полной конфигурацией управления доступом: сервисы с экземплярами (без секретов),
+// разрешениями и их номерами, выведенными из употребления номерами, ролями и группами, глобальные группы с их ролями,
+// а также учетные записи (без хешей паролей) с их ролями, группами, глобальными группами, разрешениями, ролями и
+// группами для экземпляров и разрешениями по селектору атрибутов.
+func (s *Service) ExportRBAC(ctx context.Context) (dto.RBACDocument, error) {
+	document := dto.RBACDocument{Version: RBACDocumentVersion, Services: []dto.RBACService{}, Accounts: []dto.AccountDetails{}}
+
+	services, err := all(ctx, s.repository.Services, nameOfNameDescription)
+	if err != nil {
+		return dto.RBACDocument{}, err
+	}
+
+	for _, service := range services {
+		var exported dto.RBACService
+		if exported, err = s.exportService(ctx, service.Name); err != nil {
+			return dto.RBACDocument{}, err
+		}
+		document.Services = append(document.Services, exported)
+	}
+
+	if document.GlobalGroups, err = s.repository.GlobalGroups(ctx); err != nil {
+		return dto.RBACDocument{}, adaptErr(err)
+	}
+
+	accounts, err := all(ctx, s.repository.Accounts, func(item dto.UserIdLoginState) string { return string(item.Login) })
+	if err != nil {
+		return dto.RBACDocument{}, err
+	}
+
+	for _, account := range accounts {
+		var details dto.AccountDetails
+		if details, err = s.repository.AccountDetails(ctx, account.UserId); err != nil {
+			return dto.RBACDocument{}, adaptErr(err)
+		}
+		document.Accounts = append(document.Accounts, details)
+	}
+
+	return document, nil
+}
+
+// exportService возвращает конфигурацию сервиса для документа.
+func (s *Service) exportService(ctx context.Context, name string) (dto.RBACService, error) {
+	details, err := s.repository.ServiceDetails(ctx, name)
+	if err != nil {
+		return dto.RBACService{}, adaptErr(err)
+	}
+
+	result := dto.RBACService{
+		Name:               details.Name,
+		Description:        details.Description,
+		PermissionEncoding: details.PermissionEncoding,
+		Instances:          details.Instances,
+		Permissions:        details.Permissions,
+		RetiredNumbers:     details.RetiredNumbers,
+		Roles:              make([]dto.RBACRole, 0, len(details.Roles)),
+		Groups:             make([]dto.RBACGroup, 0, len(details.Groups)),
+	}
+
+	for _, roleName := range details.Roles {
+		var role dto.RoleDetails
+		if role, err = s.repository.RoleDetails(ctx, &dto.NameService{Name: roleName, Service: name}); err != nil {
+			return dto.RBACService{}, adaptErr(err)
+		}
+		result.Roles = append(result.Roles, dto.RBACRole{
+			Name:        role.Name,
+			Description: role.Description,
+			Parents:     role.Parents,
+			Permissions: permissionsNames(role.Permissions),
+		})
+	}
+
+	for _, groupName := range details.Groups {
+		var group dto.GroupDetails
+		if group, err = s.repository.GroupDetails(ctx, &dto.NameService{Name: groupName, Service: name}); err != nil {
+			return dto.RBACService{}, adaptErr(err)
+		}
+		result.Groups = append(result.Groups, dto.RBACGroup{
+			Name:        group.Name,
+			Description: group.Description,
+			Subgroups:   group.Subgroups,
+			Roles:       group.Roles,
+			Permissions: permissionsNames(group.Permissions),
+		})
+	}
+
+	return result, nil
+}
+
+// permissionsNames возвращает названия переданных разрешений.
+func permissionsNames(permissions []dto.NameNumber) []string {
+	result := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		result = append(result, permission.Name)
+	}
+
+	return result
+}
+
+// all постранично считывает из хранилища все записи, возвращаемые функцией fetch.
+func all[T any](ctx context.Context, fetch func(context.Context, *dto.ListQuery) ([]T, error), key func(T) string) ([]T, error) {
+	result := make([]T, 0)
+	query := dto.ListQuery{Limit: maxPageSize}
+
+	for {
+		items, err := fetch(ctx, &query)
+		if err != nil {
+			return nil, adaptErr(err)
+		}
+		result = append(result, items...)
+
+		if len(items) < query.Limit {
+			return result, nil
+		}
+		query.After = key(items[len(items)-1])
+	}
+}
+
+// ImportRBAC сравнивает документ с текущей конфигурацией управления доступом и, если dryRun ложно, применяет его в
+// одной транзакции. Импорт только добавляет и обновляет данные, ничего не удаляя. Номера существующих разрешений и
+// логины существующих учетных записей изменить нельзя, выведенные из употребления номера нельзя назначить, а устаревшие
+// разрешения - восстановить или назначить заново: такие расхождения, как и ссылки на неизвестные разрешения, роли,
+// группы, глобальные группы и экземпляры, возвращаются в Conflicts вместе с ошибкой ErrRBACConflict, и документ не
+// применяется. Создаваемые учетные записи не имеют пароля и требуют его сброса, создаваемые экземпляры получают
+// случайный секрет.
+func (s *Service) ImportRBAC(ctx context.Context, document *dto.RBACDocument, dryRun bool) (dto.RBACImportResult, error) {
+	return s.applyRBAC(ctx, document, dryRun, false)
+}
+
+// ReconcileRBAC приводит конфигурацию управления доступом к документу с желаемым состоянием так же, как ImportRBAC.
+// Если prune истинно, в той же транзакции отсутствующие в документе сервисы, экземпляры, разрешения, роли и группы
+// помечаются удаленными так же, как DeleteService, DeleteInstance, DeletePermission, DeleteRole и DeleteGroup, а
+// отсутствующие глобальные группы и назначения удаляются. Учетные записи не удаляются, а их назначения удаляются,
+// только если учетная запись есть в документе. Удаление сервиса или роли администратора, как и снятие роли
+// администратора с учетной записи или глобальной группы, считается конфликтом.
+func (s *Service) ReconcileRBAC(ctx context.Context, document *dto.RBACDocument, prune bool) (dto.RBACImportResult, error) {
+	return s.applyRBAC(ctx, document, false, prune)
+}
+
+// applyRBAC сравнивает документ с текущей конфигурацией и, если dryRun ложно и нет конфликтов, применяет его, при prune
+// удаляя отсутствующие в документе данные.
+func (s *Service) applyRBAC(ctx context.Context, document *dto.RBACDocument, dryRun, prune bool) (dto.RBACImportResult, error) {
+	if document.Version != RBACDocumentVersion {
+		return dto.RBACImportResult{}, ErrInvalidRBACDocument()
+	}
+
+	current, err := s.ExportRBAC(ctx)
+	if err != nil {
+		return dto.RBACImportResult{}, err
+	}
+
+	index := newRBACIndex(&current)
+	result := dto.RBACImportResult{Changes: []dto.RBACChange{}, Conflicts: []string{}}
+
+	var removals *dto.RBACRemovals
+	if prune {
+		removals = index.prune(&current, newRBACIndex(document), &result)
+		result.Conflicts = append(result.Conflicts, s.administratorsConflicts(removals)...)
+	}
+
+	newInstances := index.compare(document, &result)
+
+	if len(result.Conflicts) > 0 {
+		return result, ErrRBACConflict()
+	}
+
+	if dryRun || len(result.Changes) == 0 {
+		return result, nil
+	}
+
+	secrets := make(map[string]string, len(newInstances))
+	for _, instance := range newInstances {
+		if secrets[instance], err = randomHex(importedSecretLength); err != nil {
+			return dto.RBACImportResult{}, err
+		}
+	}
+
+	if err = s.repository.ImportRBAC(ctx, document, secrets, removals); err != nil {
+		return dto.RBACImportResult{}, adaptErr(err)
+	}
+
+	result.Applied = true
+
+	return result, nil
+}
+
+// removesAdministrators возвращает true, если среди удаляемых данных есть сервис или роль администратора.
+func (s *Service) removesAdministrators(removals *dto.RBACRemovals) bool {
+	for _, service := range removals.Services {
+		if service == s.secure.AdminService {
+			return true
+		}
+	}
+
+	for _, role := range removals.Roles {
+		if role.Name == s.secure.AdminRole && role.Service == s.secure.AdminService {
+			return true
+		}
+	}
+
+	return false
+}
+
+// administratorsConflicts возвращает конфликты удаления, лишающего учетные записи прав администратора: удаление сервиса
+// или роли администратора и снятие роли администратора с учетной записи или глобальной группы.
+func (s *Service) administratorsConflicts(removals *dto.RBACRemovals) []string {
+	var conflicts []string
+
+	if s.removesAdministrators(removals) {
+		conflicts = append(conflicts, fmt.Sprintf("document removes administrator role %q of service %q",
+			s.secure.AdminRole, s.secure.AdminService))
+	}
+
+	for _, role := range removals.AccountRoles {
+		if role.Role == s.secure.AdminRole && role.Service == s.secure.AdminService {
+			conflicts = append(conflicts, fmt.Sprintf("document unassigns administrator role %q of service %q from account %s",
+				s.secure.AdminRole, s.secure.AdminService, role.UserId))
+		}
+	}
+
+	for _, role := range removals.GlobalGroupRoles {
+		if role.Role == s.secure.AdminRole && role.Service == s.secure.AdminService {
+			conflicts = append(conflicts, fmt.Sprintf("document unassigns administrator role %q of service %q from global group %q",
+				s.secure.AdminRole, s.secure.AdminService, role.GlobalGroup))
+		}
+	}
+
+	return conflicts
+}
+
+// rbacIndex индекс конфигурации управления доступом для сравнения с импортируемым документом. Ключи сущностей имеют
+// вид "вид:сервис/название", ключи сервисов и глобальных групп - "вид:название".
+type rbacIndex struct {
+	descriptions map[string]string                // Описания сервисов, разрешений, ролей, групп и глобальных групп по ключу
+	numbers      map[string]int                   // Номера разрешений по ключу разрешения
+	deprecated   map[string]bool                  // Признаки устаревания разрешений по ключу разрешения
+	numberOwners map[string]string                // Названия разрешений по сервису и номеру
+	retired      map[string]bool                  // Выведенные из употребления номера по сервису и номеру
+	encodings    map[string]string                // Способы кодирования номеров разрешений по названию сервиса
+	parents      rbacGraph                        // Ключи родительских ролей по ключу роли
+	subgroups    rbacGraph                        // Ключи вложенных групп по ключу группы
+	instances    map[string]string                // Сервисы экземпляров по названию экземпляра
+	assignments  map[string]bool                  // Назначения по ключу назначения
+	accounts     map[uuid.UUID]dto.AccountDetails // Учетные записи по идентификатору
+	logins       map[login.Login]uuid.UUID        // Идентификаторы учетных записей по логину
+}
+
+// newRBACIndex возвращает индекс переданного документа.
+func newRBACIndex(document *dto.RBACDocument) *rbacIndex {
+	index := &rbacIndex{
+		descriptions: make(map[string]string),
+		numbers:      make(map[string]int),
+		deprecated:   make(map[string]bool),
+		numberOwners: make(map[string]string),
+		retired:      make(map[string]bool),
+		encodings:    make(map[string]string),
+		parents:      make(rbacGraph),
+		subgroups:    make(rbacGraph),
+		instances:    make(map[string]string),
+		assignments:  make(map[string]bool),
+		accounts:     make(map[uuid.UUID]dto.AccountDetails),
+		logins:       make(map[login.Login]uuid.UUID),
+	}
+
+	for _, service := range document.Services {
+		index.descriptions[entityKey("service", service.Name, "")] = service.Description
+		index.encodings[service.Name] = service.PermissionEncoding
+		for _, instance := range service.Instances {
+			index.instances[instance] = service.Name
+		}
+		for _, permission := range service.Permissions {
+			index.addPermission(service.Name, &permission)
+		}
+		for _, number := range service.RetiredNumbers {
+			index.retired[numberKey(service.Name, number)] = true
+		}
+		for _, role := range service.Roles {
+			index.descriptions[entityKey("role", service.Name, role.Name)] = role.Description
+			for _, permission := range role.Permissions {
+				index.assignments[assignmentKey("role_permission", service.Name+"/"+role.Name, permission)] = true
+			}
+			for _, parent := range role.Parents {
+				index.assignments[assignmentKey("role_parent", service.Name+"/"+role.Name, parent)] = true
+				index.parents.add(entityKey("role", service.Name, role.Name), entityKey("role", service.Name, parent))
+			}
+		}
+		for _, group := range service.Groups {
+			index.descriptions[entityKey("group", service.Name, group.Name)] = group.Description
+			for _, role := range group.Roles {
+				index.assignments[assignmentKey("group_role", service.Name+"/"+group.Name, role)] = true
+			}
+			for _, permission := range group.Permissions {
+				index.assignments[assignmentKey("group_permission", service.Name+"/"+group.Name, permission)] = true
+			}
+			for _, subgroup := range group.Subgroups {
+				index.assignments[assignmentKey("group_subgroup", service.Name+"/"+group.Name, subgroup)] = true
+				index.subgroups.add(entityKey("group", service.Name, group.Name), entityKey("group", service.Name, subgroup))
+			}
+		}
+	}
+
+	for _, group := range document.GlobalGroups {
+		index.descriptions[entityKey("global_group", group.Name, "")] = group.Description
+		for _, role := range group.Roles {
+			index.assignments[assignmentKey("global_group_role", group.Name, role.Service+"/"+role.Name)] = true
+		}
+	}
+
+	for _, account := range document.Accounts {
+		index.addAccount(&account)
+	}
+
+	return index
+}
+
+// entityKey возвращает ключ сущности. Для сервиса и глобальной группы, не принадлежащей сервису, service содержит
+// название сущности, а name пусто.
+func entityKey(kind, service, name string) string {
+	if len(name) == 0 {
+		return kind + ":" + service
+	}
+	return kind + ":" + service + "/" + name
+}
+
+// numberKey возвращает ключ номера разрешения сервиса.
+func numberKey(service string, number int) string {
+	return fmt.Sprintf("%s#%d", service, number)
+}
+
+// assignmentKey возвращает ключ назначения объекта target субъекту subject.
+func assignmentKey(kind, subject, target string) string {
+	return kind + ":" + subject + " → " + target
+}
+
+// addPermission добавляет разрешение в индекс.
+func (i *rbacIndex) addPermission(service string, permission *dto.NameNumberDescriptionDeprecated) {
+	key := entityKey("permission", service, permission.Name)
+	i.descriptions[key] = permission.Description
+	i.numbers[key] = permission.Number
+	i.deprecated[key] = permission.Deprecated
+	i.numberOwners[numberKey(service, permission.Number)] = permission.Name
+}
+
+// retirePermission удаляет разрешение из индекса, выводя его номер из употребления.
+func (i *rbacIndex) retirePermission(service string, permission *dto.NameNumberDescriptionDeprecated) {
+	key := entityKey("permission", service, permission.Name)
+	delete(i.descriptions, key)
+	delete(i.numbers, key)
+	delete(i.deprecated, key)
+	delete(i.numberOwners, numberKey(service, permission.Number))
+	i.retired[numberKey(service, permission.Number)] = true
+}
+
+// rbacGraph ориентированный граф связей между сущностями одного вида (наследования ролей, вложения групп): ключи
+// сущностей, с которыми связана сущность, по её ключу.
+type rbacGraph map[string]map[string]bool
+
+// add добавляет в граф связь сущности from с сущностью to.
+func (g rbacGraph) add(from, to string) {
+	if g[from] == nil {
+		g[from] = make(map[string]bool)
+	}
+	g[from][to] = true
+}
+
+// remove удаляет из графа все связи сущности key.
+func (g rbacGraph) remove(key string) {
+	delete(g, key)
+	for _, edges := range g {
+		delete(edges, key)
+	}
+}
+
+// cycles возвращает циклы графа в виде цепочек ключей сущностей, в которых первая сущность совпадает с последней.
+// Каждый цикл возвращается один раз.
+func (g rbacGraph) cycles() [][]string {
+	const (
+		visiting = 1
+		done     = 2
+	)
+
+	keys := make([]string, 0, len(g))
+	for key := range g {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var (
+		cycles [][]string
+		stack  []string
+		visit  func(key string)
+	)
+	state := make(map[string]int)
+
+	visit = func(key string) {
+		state[key] = visiting
+		stack = append(stack, key)
+
+		edges := make([]string, 0, len(g[key]))
+		for edge := range g[key] {
+			edges = append(edges, edge)
+		}
+		sort.Strings(edges)
+
+		for _, edge := range edges {
+			switch state[edge] {
+			case visiting:
+				start := slices.Index(stack, edge)
+				cycles = append(cycles, append(slices.Clone(stack[start:]), edge))
+			case 0:
+				visit(edge)
+			}
+		}
+
+		stack = stack[:len(stack)-1]
+		state[key] = done
+	}
+
+	for _, key := range keys {
+		if state[key] == 0 {
+			visit(key)
+		}
+	}
+
+	return cycles
+}
+
+// addAccount добавляет учетную запись и её назначения в индекс.
+func (i *rbacIndex) addAccount(account *dto.AccountDetails) {
+	i.accounts[account.UserId] = *account
+	i.logins[account.Login] = account.UserId
+	for _, role := range account.Roles {
+		i.assignments[assignmentKey("account_role", account.UserId.String(), role.Service+"/"+role.Name)] = true
+	}
+	for _, group := range account.Groups {
+		i.assignments[assignmentKey("account_group", account.UserId.String(), group.Service+"/"+group.Name)] = true
+	}
+	for _, permission := range account.InstancePermissions {
+		i.assignments[assignmentKey("account_instance_permission", account.UserId.String(),
+			permission.Instance+"/"+permission.Permission)] = true
+	}
+	for _, role := range account.InstanceRoles {
+		i.assignments[assignmentKey("account_instance_role", account.UserId.String(), role.Instance+"/"+role.Name)] = true
+	}
+	for _, group := range account.InstanceGroups {
+		i.assignments[assignmentKey("account_instance_group", account.UserId.String(), group.Instance+"/"+group.Name)] = true
+	}
+	for _, permission := range account.SelectorPermissions {
+		i.assignments[assignmentKey("account_selector_permission", account.UserId.String(), selectorTarget(&permission))] = true
+	}
+	for _, group := range account.GlobalGroups {
+		i.assignments[assignmentKey("account_global_group", account.UserId.String(), group)] = true
+	}
+}
+
+// selectorTarget возвращает цель назначения разрешения по селектору в виде "сервис/разрешение [селектор]". Допустимый
+// селектор приводится к канонической записи, чтобы одинаковые селекторы давали одинаковые цели.
+func selectorTarget(permission *dto.SelectorPermission) string {
+	text := permission.Selector
+	if selector, err := label_selector.Parse(text); err == nil {
+		text = selector.String()
+	}
+
+	return permission.Service + "/" + permission.Permission + " [" + text + "]"
+}
+
+// compare записывает в result изменения, необходимые для приведения индексированной конфигурации к документу, и
+// конфликты, препятствующие этому. По мере сравнения сущности документа добавляются в индекс. Возвращает названия
+// экземпляров сервисов, которые будут созданы.
+func (i *rbacIndex) compare(document *dto.RBACDocument, result *dto.RBACImportResult) []string {
+	newInstances := make([]string, 0)
+
+	change := func(action, kind, target string) {
+		result.Changes = append(result.Changes, dto.RBACChange{Action: action, Kind: kind, Target: target})
+	}
+	conflict := func(format string, args ...any) {
+		result.Conflicts = append(result.Conflicts, fmt.Sprintf(format, args...))
+	}
+	entity := func(kind, service, name, description string) {
+		key := entityKey(kind, service, name)
+		target := key[len(kind)+1:]
+		if len(service) == 0 || (kind != "service" && kind != "global_group" && len(name) == 0) {
+			conflict("%s with empty name", kind)
+			return
+		}
+		if current, ok := i.descriptions[key]; !ok {
+			change(actionCreate, kind, target)
+		} else if current != description {
+			change(actionUpdate, kind, target)
+		}
+		i.descriptions[key] = description
+	}
+	assign := func(kind, subject, target, requiredKey string) {
+		if _, ok := i.descriptions[requiredKey]; !ok {
+			conflict("%s %q references unknown %s", kind, subject, requiredKey)
+			return
+		}
+		if key := assignmentKey(kind, subject, target); !i.assignments[key] {
+			if i.deprecated[requiredKey] {
+				conflict("%s %q assigns deprecated %s", kind, subject, requiredKey)
+				return
+			}
+			i.assignments[key] = true
+			change(actionAssign, kind, subject+" → "+target)
+		}
+	}
+
+	for _, service := range document.Services {
+		currentDescription, serviceExists := i.descriptions[entityKey("service", service.Name, "")]
+		entity("service", service.Name, "", service.Description)
+
+		// Пустой способ кодирования номеров разрешений в документе сохраняет текущий.
+		if encoding := service.PermissionEncoding; len(encoding) > 0 {
+			if !permission_encoding.Valid(encoding) {
+				conflict("service %q has unknown permission encoding %q", service.Name, encoding)
+			} else if serviceExists && currentDescription == service.Description && i.encodings[service.Name] != encoding {
+				change(actionUpdate, "service", service.Name)
+			}
+			i.encodings[service.Name] = encoding
+		}
+
+		for _, instance := range service.Instances {
+			if owner, ok := i.instances[instance]; !ok {
+				i.instances[instance] = service.Name
+				newInstances = append(newInstances, instance)
+				change(actionCreate, "instance", service.Name+"/"+instance)
+			} else if owner != service.Name {
+				conflict("instance %q belongs to service %q", instance, owner)
+			}
+		}
+
+		for _, permission := range service.Permissions {
+			key := entityKey("permission", service.Name, permission.Name)
+			owner, numberUsed := i.numberOwners[numberKey(service.Name, permission.Number)]
+			switch number, exist := i.numbers[key]; {
+			case permission.Number < 1:
+				conflict("permission %q of service %q has invalid number %d", permission.Name, service.Name, permission.Number)
+				continue
+			case exist && number != permission.Number:
+				conflict("permission %q of service %q has number %d, document sets %d", permission.Name, service.Name,
+					number, permission.Number)
+				continue
+			case !exist && numberUsed:
+				conflict("number %d of service %q is already used by permission %q", permission.Number, service.Name, owner)
+				continue
+			case !exist && i.retired[numberKey(service.Name, permission.Number)]:
+				conflict("number %d of service %q is retired", permission.Number, service.Name)
+				continue
+			case exist && i.deprecated[key] && !permission.Deprecated:
+				conflict("permission %q of service %q is deprecated, document restores it", permission.Name, service.Name)
+				continue
+			case exist && !i.deprecated[key] && permission.Deprecated:
+				change(actionDeprecate, "permission", service.Name+"/"+permission.Name)
+			}
+			entity("permission", service.Name, permission.Name, permission.Description)
+			i.addPermission(service.Name, &permission)
+		}
+
+		for _, number := range service.RetiredNumbers {
+			key := numberKey(service.Name, number)
+			switch owner, numberUsed := i.numberOwners[key]; {
+			case number < 1:
+				conflict("service %q has invalid retired number %d", service.Name, number)
+			case numberUsed:
+				conflict("retired number %d of service %q is used by permission %q", number, service.Name, owner)
+			case !i.retired[key]:
+				i.retired[key] = true
+				change(actionRetire, "permission_number", key)
+			}
+		}
+
+		for _, role := range service.Roles {
+			entity("role", service.Name, role.Name, role.Description)
+			for _, permission := range role.Permissions {
+				assign("role_permission", service.Name+"/"+role.Name, permission,
+					entityKey("permission", service.Name, permission))
+			}
+		}
+
+		// Родительские роли назначаются после создания всех ролей сервиса, чтобы роль могла ссылаться на роль,
+		// объявленную в документе позже неё.
+		for _, role := range service.Roles {
+			for _, parent := range role.Parents {
+				key, parentKey := entityKey("role", service.Name, role.Name), entityKey("role", service.Name, parent)
+				assign("role_parent", service.Name+"/"+role.Name, parent, parentKey)
+				if _, ok := i.descriptions[parentKey]; ok {
+					i.parents.add(key, parentKey)
+				}
+			}
+		}
+
+		for _, group := range service.Groups {
+			entity("group", service.Name, group.Name, group.Description)
+			for _, role := range group.Roles {
+				assign("group_role", service.Name+"/"+group.Name, role, entityKey("role", service.Name, role))
+			}
+			for _, permission := range group.Permissions {
+				assign("group_permission", service.Name+"/"+group.Name, permission,
+					entityKey("permission", service.Name, permission))
+			}
+		}
+
+		// Вложенные группы назначаются после создания всех групп сервиса по той же причине, что и родительские роли.
+		for _, group := range service.Groups {
+			for _, subgroup := range group.Subgroups {
+				key, subgroupKey := entityKey("group", service.Name, group.Name), entityKey("group", service.Name, subgroup)
+				assign("group_subgroup", service.Name+"/"+group.Name, subgroup, subgroupKey)
+				if _, ok := i.descriptions[subgroupKey]; ok {
+					i.subgroups.add(key, subgroupKey)
+				}
+			}
+		}
+	}
+
+	for _, cycle := range i.parents.cycles() {
+		conflict("roles form an inheritance cycle: %s", strings.Join(cycle, " → "))
+	}
+	for _, cycle := range i.subgroups.cycles() {
+		conflict("groups form a nesting cycle: %s", strings.Join(cycle, " → "))
+	}
+
+	for _, group := range document.GlobalGroups {
+		entity("global_group", group.Name, "", group.Description)
+		for _, role := range group.Roles {
+			assign("global_group_role", group.Name, role.Service+"/"+role.Name, entityKey("role", role.Service, role.Name))
+		}
+	}
+
+	for _, account := range document.Accounts {
+		if account.UserId == uuid.Nil || len(account.Login) == 0 {
+			conflict("account %q without user id or login", account.Login)
+			continue
+		}
+
+		current, exist := i.accounts[account.UserId]
+		// Пустой тип учетной записи в документе сохраняет текущий.
+		if len(account.Type) == 0 {
+			account.Type = current.Type
+		} else if !account_type.Valid(account.Type) {
+			conflict("account %q has unknown type %q", account.Login, account.Type)
+		}
+
+		owner, loginUsed := i.logins[account.Login]
+		switch {
+		case exist && current.Login != account.Login:
+			conflict("account %s has login %q, document sets %q", account.UserId, current.Login, account.Login)
+			continue
+		case !exist && loginUsed:
+			conflict("login %q is already used by account %s", account.Login, owner)
+			continue
+		case !exist:
+			change(actionCreate, "account", string(account.Login))
+		case current.State != account.State || current.Type != account.Type || current.DisplayName != account.DisplayName ||
+			current.Contact != account.Contact || current.OwnerTeam != account.OwnerTeam:
+			change(actionUpdate, "account", string(account.Login))
+		}
+		i.accounts[account.UserId] = account
+		i.logins[account.Login] = account.UserId
+
+		subject := account.UserId.String()
+		for _, role := range account.Roles {
+			assign("account_role", subject, role.Service+"/"+role.Name, entityKey("role", role.Service, role.Name))
+		}
+		for _, group := range account.Groups {
+			assign("account_group", subject, group.Service+"/"+group.Name, entityKey("group", group.Service, group.Name))
+		}
+		for _, permission := range account.InstancePermissions {
+			service, ok := i.instances[permission.Instance]
+			if !ok {
+				conflict("account %s references unknown instance %q", subject, permission.Instance)
+				continue
+			}
+			assign("account_instance_permission", subject, permission.Instance+"/"+permission.Permission,
+				entityKey("permission", service, permission.Permission))
+		}
+		for _, role := range account.InstanceRoles {
+			service, ok := i.instances[role.Instance]
+			if !ok {
+				conflict("account %s references unknown instance %q", subject, role.Instance)
+				continue
+			}
+			assign("account_instance_role", subject, role.Instance+"/"+role.Name, entityKey("role", service, role.Name))
+		}
+		for _, group := range account.InstanceGroups {
+			service, ok := i.instances[group.Instance]
+			if !ok {
+				conflict("account %s references unknown instance %q", subject, group.Instance)
+				continue
+			}
+			assign("account_instance_group", subject, group.Instance+"/"+group.Name, entityKey("group", service, group.Name))
+		}
+		for _, permission := range account.SelectorPermissions {
+			if _, err := label_selector.Parse(permission.Selector); err != nil {
+				conflict("account %s has invalid selector %q", subject, permission.Selector)
+				continue
+			}
+			assign("account_selector_permission", subject, selectorTarget(&permission),
+				entityKey("permission", permission.Service, permission.Permission))
+		}
+		for _, group := range account.GlobalGroups {
+			assign("account_global_group", subject, group, entityKey("global_group", group, ""))
+		}
+	}
+
+	return newInstances
+}
+
+// prune возвращает данные индексированной конфигурации current, отсутствующие в индексе желаемой конфигурации desired,
+// и записывает их удаление в result. Назначения, удаляемые каскадно вместе с сущностями, не перечисляются. Номера
+// удаляемых разрешений выводятся в индексе из употребления, чтобы документ не мог назначить их новым разрешениям.
+func (i *rbacIndex) prune(current *dto.RBACDocument, desired *rbacIndex, result *dto.RBACImportResult) *dto.RBACRemovals {
+	removals := &dto.RBACRemovals{}
+
+	change := func(action, kind, target string) {
+		result.Changes = append(result.Changes, dto.RBACChange{Action: action, Kind: kind, Target: target})
+	}
+	exist := func(kind, service, name string) bool {
+		_, ok := desired.descriptions[entityKey(kind, service, name)]
+		return ok
+	}
+
+	for _, service := range current.Services {
+		if !exist("service", service.Name, "") {
+			removals.Services = append(removals.Services, service.Name)
+			change(actionDelete, "service", service.Name)
+			for _, permission := range service.Permissions {
+				i.retirePermission(service.Name, &permission)
+			}
+			continue
+		}
+
+		for _, instance := range service.Instances {
+			if _, ok := desired.instances[instance]; !ok {
+				removals.Instances = append(removals.Instances, instance)
+				change(actionDelete, "instance", service.Name+"/"+instance)
+			}
+		}
+
+		for _, permission := range service.Permissions {
+			if !exist("permission", service.Name, permission.Name) {
+				removals.Permissions = append(removals.Permissions, dto.NameService{Name: permission.Name, Service: service.Name})
+				change(actionDelete, "permission", service.Name+"/"+permission.Name)
+				i.retirePermission(service.Name, &permission)
+			}
+		}
+
+		for _, role := range service.Roles {
+			subject := service.Name + "/" + role.Name
+			if !exist("role", service.Name, role.Name) {
+				removals.Roles = append(removals.Roles, dto.NameService{Name: role.Name, Service: service.Name})
+				change(actionDelete, "role", subject)
+				i.parents.remove(entityKey("role", service.Name, role.Name))
+				continue
+			}
+			for _, parent := range role.Parents {
+				if exist("role", service.Name, parent) &&
+					!desired.assignments[assignmentKey("role_parent", subject, parent)] {
+					removals.RoleParents = append(removals.RoleParents,
+						dto.RoleParentService{Role: role.Name, Parent: parent, Service: service.Name})
+					change(actionUnassign, "role_parent", subject+" → "+parent)
+					delete(i.parents[entityKey("role", service.Name, role.Name)], entityKey("role", service.Name, parent))
+				}
+			}
+			for _, permission := range role.Permissions {
+				if exist("permission", service.Name, permission) &&
+					!desired.assignments[assignmentKey("role_permission", subject, permission)] {
+					removals.RolePermissions = append(removals.RolePermissions,
+						dto.PermissionRoleService{Permission: permission, Role: role.Name, Service: service.Name})
+					change(actionUnassign, "role_permission", subject+" → "+permission)
+				}
+			}
+		}
+
+		for _, group := range service.Groups {
+			subject := service.Name + "/" + group.Name
+			if !exist("group", service.Name, group.Name) {
+				removals.Groups = append(removals.Groups, dto.NameService{Name: group.Name, Service: service.Name})
+				change(actionDelete, "group", subject)
+				i.subgroups.remove(entityKey("group", service.Name, group.Name))
+				continue
+			}
+			for _, subgroup := range group.Subgroups {
+				if exist("group", service.Name, subgroup) &&
+					!desired.assignments[assignmentKey("group_subgroup", subject, subgroup)] {
+					removals.GroupSubgroups = append(removals.GroupSubgroups,
+						dto.GroupSubgroupService{Group: group.Name, Subgroup: subgroup, Service: service.Name})
+					change(actionUnassign, "group_subgroup", subject+" → "+subgroup)
+					delete(i.subgroups[entityKey("group", service.Name, group.Name)], entityKey("group", service.Name, subgroup))
+				}
+			}
+			for _, role := range group.Roles {
+				if exist("role", service.Name, role) && !desired.assignments[assignmentKey("group_role", subject, role)] {
+					removals.GroupRoles = append(removals.GroupRoles,
+						dto.GroupRoleService{Group: group.Name, Role: role, Service: service.Name})
+					change(actionUnassign, "group_role", subject+" → "+role)
+				}
+			}
+			for _, permission := range group.Permissions {
+				if exist("permission", service.Name, permission) &&
+					!desired.assignments[assignmentKey("group_permission", subject, permission)] {
+					removals.GroupPermissions = append(removals.GroupPermissions,
+						dto.GroupPermissionService{Group: group.Name, Permission: permission, Service: service.Name})
+					change(actionUnassign, "group_permission", subject+" → "+permission)
+				}
+			}
+		}
+	}
+
+	for _, group := range current.GlobalGroups {
+		if !exist("global_group", group.Name, "") {
+			removals.GlobalGroups = append(removals.GlobalGroups, group.Name)
+			change(actionDelete, "global_group", group.Name)
+			continue
+		}
+		for _, role := range group.Roles {
+			target := role.Service + "/" + role.Name
+			if exist("role", role.Service, role.Name) &&
+				!desired.assignments[assignmentKey("global_group_role", group.Name, target)] {
+				removals.GlobalGroupRoles = append(removals.GlobalGroupRoles,
+					dto.GlobalGroupRoleService{GlobalGroup: group.Name, Role: role.Name, Service: role.Service})
+				change(actionUnassign, "global_group_role", group.Name+" → "+target)
+			}
+		}
+	}
+
+	for _, account := range current.Accounts {
+		if _, ok := desired.accounts[account.UserId]; !ok {
+			continue
+		}
+
+		subject := account.UserId.String()
+		for _, role := range account.Roles {
+			target := role.Service + "/" + role.Name
+			if exist("role", role.Service, role.Name) && !desired.assignments[assignmentKey("account_role", subject, target)] {
+				removals.AccountRoles = append(removals.AccountRoles,
+					dto.UserIdRoleService{UserId: account.UserId, Role: role.Name, Service: role.Service})
+				change(actionUnassign, "account_role", subject+" → "+target)
+			}
+		}
+		for _, group := range account.Groups {
+			target := group.Service + "/" + group.Name
+			if exist("group", group.Service, group.Name) && !desired.assignments[assignmentKey("account_group", subject, target)] {
+				removals.AccountGroups = append(removals.AccountGroups,
+					dto.UserIdGroupService{UserId: account.UserId, Group: group.Name, Service: group.Service})
+				change(actionUnassign, "account_group", subject+" → "+target)
+			}
+		}
+		for _, permission := range account.InstancePermissions {
+			target := permission.Instance + "/" + permission.Permission
+			service, ok := desired.instances[permission.Instance]
+			if ok && exist("permission", service, permission.Permission) &&
+				!desired.assignments[assignmentKey("account_instance_permission", subject, target)] {
+				removals.AccountInstancePermissions = append(removals.AccountInstancePermissions, dto.UserIdInstancePermission{
+					UserId:     account.UserId,
+					Instance:   permission.Instance,
+					Permission: permission.Permission,
+				})
+				change(actionUnassign, "account_instance_permission", subject+" → "+target)
+			}
+		}
+		for _, role := range account.InstanceRoles {
+			target := role.Instance + "/" + role.Name
+			service, ok := desired.instances[role.Instance]
+			if ok && exist("role", service, role.Name) &&
+				!desired.assignments[assignmentKey("account_instance_role", subject, target)] {
+				removals.AccountInstanceRoles = append(removals.AccountInstanceRoles,
+					dto.UserIdInstanceRole{UserId: account.UserId, Instance: role.Instance, Role: role.Name})
+				change(actionUnassign, "account_instance_role", subject+" → "+target)
+			}
+		}
+		for _, group := range account.InstanceGroups {
+			target := group.Instance + "/" + group.Name
+			service, ok := desired.instances[group.Instance]
+			if ok && exist("group", service, group.Name) &&
+				!desired.assignments[assignmentKey("account_instance_group", subject, target)] {
+				removals.AccountInstanceGroups = append(removals.AccountInstanceGroups,
+					dto.UserIdInstanceGroup{UserId: account.UserId, Instance: group.Instance, Group: group.Name})
+				change(actionUnassign, "account_instance_group", subject+" → "+target)
+			}
+		}
+		for _, permission := range account.SelectorPermissions {
+			target := selectorTarget(&permission)
+			if exist("permission", permission.Service, permission.Permission) &&
+				!desired.assignments[assignmentKey("account_selector_permission", subject, target)] {
+				removals.AccountSelectorPermissions = append(removals.AccountSelectorPermissions, dto.UserIdSelectorPermission{
+					UserId:     account.UserId,
+					Permission: permission.Permission,
+					Service:    permission.Service,
+					Selector:   permission.Selector,
+				})
+				change(actionUnassign, "account_selector_permission", subject+" → "+target)
+			}
+		}
+		for _, group := range account.GlobalGroups {
+			if exist("global_group", group, "") &&
+				!desired.assignments[assignmentKey("account_global_group", subject, group)] {
+				removals.AccountGlobalGroups = append(removals.AccountGlobalGroups,
+					dto.UserIdGlobalGroup{GlobalGroup: group, UserId: account.UserId})
+				change(actionUnassign, "account_global_group", subject+" → "+group)
+			}
+		}
+	}
+
+	return removals
+}
@@ -6,40 +6,59 @@ package service
 import (
 	"context"
 	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/lazylex/watch-store/secure/internal/config"
 	"github.com/lazylex/watch-store/secure/internal/domain/value_objects/account_state"
+	"github.com/lazylex/watch-store/secure/internal/domain/value_objects/account_type"
+	"github.com/lazylex/watch-store/secure/internal/domain/value_objects/label_selector"
+	"github.com/lazylex/watch-store/secure/internal/domain/value_objects/login"
 	"github.com/lazylex/watch-store/secure/internal/domain/value_objects/password"
+	"github.com/lazylex/watch-store/secure/internal/domain/value_objects/permission_encoding"
 	"github.com/lazylex/watch-store/secure/internal/dto"
+	jointErr "github.com/lazylex/watch-store/secure/internal/errors/joint"
 	se "github.com/lazylex/watch-store/secure/internal/errors/service"
 	"github.com/lazylex/watch-store/secure/internal/ports/metrics/service"
 	"github.com/lazylex/watch-store/secure/internal/ports/repository/joint"
+	"github.com/lazylex/watch-store/secure/pkg/tokenperm"
+	"github.com/lazylex/watch-store/secure/pkg/totp"
 	"golang.org/x/crypto/bcrypt"
 	"log/slog"
 	"os"
+	"strings"
 	"time"
 )
 
+const (
+	totpSkew           = 1  // Допустимое отклонение шага времени одноразового пароля
+	recoveryCodeLength = 10 // Длина кода восстановления
+)
+
 // Service структура для взаимодействия с хранилищем данных, настройками безопасности и подсчетом метрик. Логика пакета
 // реализуется на базе этой структуры.
 type Service struct {
 	metrics    service.MetricsInterface // Метрики
 	repository joint.Interface          // Хранилище данных
 	secure     config.Secure            // Настройки безопасности
+	oidcKey    *rsa.PrivateKey          // Ключ подписи ID-токенов OpenID Connect. Равен nil, если OIDC не настроен
+	oidcKeyId  string                   // Идентификатор ключа подписи (kid)
 }
 
 // AccountOptions опции для создаваемых учетных записей.
 type AccountOptions struct {
-	Groups              []dto.NameService        // Срез групп, в которых состоит пользователь
-	Roles               []dto.NameService        // Срез ролей, в которых состоит пользователь
-	InstancePermissions []dto.InstancePermission // Срез разрешений для конкретных экземпляров сервисов
+	Groups              []dto.NameService          // Срез групп, в которых состоит пользователь
+	Roles               []dto.NameService          // Срез ролей, в которых состоит пользователь
+	InstancePermissions []dto.InstancePermission   // Срез разрешений для конкретных экземпляров сервисов
+	Metadata            *dto.UserIdAccountMetadata // Метаданные учетной записи (идентификатор пользователя не учитывается)
 }
 
-// MustCreate конструктор для сервиса. Если метрики или хранилище равны nil или настройки безопасности пусты, работа
-// приложения завершается.
+// MustCreate конструктор для сервиса. Если метрики или хранилище равны nil, настройки безопасности пусты или не удалось
+// загрузить ключ подписи ID-токенов OpenID Connect, работа приложения завершается.
 func MustCreate(metrics service.MetricsInterface, repository joint.Interface, cfg config.Secure) *Service {
 	var err error
 	switch {
@@ -55,13 +74,46 @@ func MustCreate(metrics service.MetricsInterface, repository joint.Interface, cf
 		slog.Error(err.Error())
 		os.Exit(1)
 	}
-	return &Service{metrics: metrics, repository: repository, secure: cfg}
+
+	s := &Service{metrics: metrics, repository: repository, secure: cfg}
+
+	if len(cfg.OIDCSigningKeyFile) > 0 {
+		if s.oidcKey, err = loadSigningKey(cfg.OIDCSigningKeyFile); err != nil {
+			slog.Error("unable to load oidc signing key: " + err.Error())
+			os.Exit(1)
+		}
+		s.oidcKeyId = signingKeyId(&s.oidcKey.PublicKey)
+	}
+
+	return s
 }
 
 // Login совершает логин пользователя (сервиса) по переданным в dto логину и паролю. Возвращает токен сессии и ошибку.
+// Если для учетной записи включена двухфакторная аутентификация, сессия не создаётся: возвращается токен
+// незавершенного входа и ошибка service.ErrSecondFactorRequired, а вход завершается функцией CompleteLogin.
 func (s *Service) Login(ctx context.Context, data *dto.LoginPassword) (string, error) {
+	var totpState dto.SecretEnabled
+
+	id, err := s.authenticate(ctx, data)
+	if err != nil || id == uuid.Nil {
+		return "", err
+	}
+
+	if totpState, err = s.repository.TOTPSecret(ctx, id); err != nil && !errors.Is(err, jointErr.ErrEmptyResult) {
+		return "", adaptErr(err)
+	}
+
+	if totpState.Enabled {
+		return s.createLoginChallenge(ctx, id)
+	}
+
+	return s.startSession(ctx, id)
+}
+
+// authenticate проверяет, что учетная запись активна и переданный пароль верен. Возвращает идентификатор учетной
+// записи. Если хранилище не вернуло идентификатор, возвращается uuid.Nil без ошибки.
+func (s *Service) authenticate(ctx context.Context, data *dto.LoginPassword) (uuid.UUID, error) {
 	var (
-		token           string
 		passwordCorrect bool
 		userIdAndHash   dto.UserIdHash
 	)
@@ -69,39 +121,139 @@ func (s *Service) Login(ctx context.Context, data *dto.LoginPassword) (string, e
 	state, err := s.repository.AccountState(ctx, data.Login)
 
 	if err != nil {
-		return "", adaptErr(err)
+		return uuid.Nil, adaptErr(err)
 	}
 
 	if state != account_state.Enabled {
-		return "", ErrNotEnabledAccount()
+		return uuid.Nil, ErrNotEnabledAccount()
 	}
 
 	userIdAndHash, err = s.repository.UserIdAndPasswordHash(ctx, data.Login)
 	if userIdAndHash.UserId == uuid.Nil || err != nil {
 		s.metrics.AuthenticationErrorInc()
-		return "", adaptErr(err)
+		return uuid.Nil, adaptErr(err)
 	}
 
-	compare := make(chan struct{})
+	if passwordCorrect, err = s.comparePassword(ctx, userIdAndHash.Hash, data.Password); err != nil {
+		return uuid.Nil, err
+	}
 
-	go func(correct *bool) {
-		*correct = bcrypt.CompareHashAndPassword([]byte(userIdAndHash.Hash), []byte(data.Password)) == nil
-		compare <- struct{}{}
-	}(&passwordCorrect)
+	if !passwordCorrect {
+		s.metrics.AuthenticationErrorInc()
+		return uuid.Nil, se.ErrAuthenticationData
+	}
 
-	select {
-	case <-ctx.Done():
-		return "", ctx.Err()
-	case <-compare:
-		close(compare)
+	return userIdAndHash.UserId, nil
+}
+
+// AuthenticatePassword проверяет логин и пароль учетной записи без открытия сессии и возвращает её идентификатор.
+// Несуществующая учетная запись приводит к ошибке service.ErrAuthenticationData. Учетные записи с включенной
+// двухфакторной аутентификацией так пройти проверку не могут: возвращается ошибка service.ErrSecondFactorRequired.
+func (s *Service) AuthenticatePassword(ctx context.Context, data *dto.LoginPassword) (uuid.UUID, error) {
+	var totpState dto.SecretEnabled
+
+	id, err := s.authenticate(ctx, data)
+	if errors.Is(err, se.ErrEmptyResult) || (err == nil && id == uuid.Nil) {
+		return uuid.Nil, se.ErrAuthenticationData
+	}
+	if err != nil {
+		return uuid.Nil, err
 	}
 
-	if !passwordCorrect {
+	if totpState, err = s.repository.TOTPSecret(ctx, id); err != nil && !errors.Is(err, jointErr.ErrEmptyResult) {
+		return uuid.Nil, adaptErr(err)
+	}
+
+	if totpState.Enabled {
+		return uuid.Nil, ErrSecondFactorRequired()
+	}
+
+	return id, nil
+}
+
+// ClientCredentialsToken выдаёт JWT-токен с разрешениями для экземпляра сервиса по логину и паролю учетной записи без
+// открытия сессии (OAuth 2.0, grant_type=client_credentials). Проверка учетных данных производится функцией
+// AuthenticatePassword. Ошибка service.ErrEmptyResult означает отсутствие экземпляра сервиса. Возвращает токен и время
+// его жизни.
+func (s *Service) ClientCredentialsToken(ctx context.Context, data *dto.LoginPasswordInstance) (dto.TokenTTL, error) {
+	var token string
+
+	id, err := s.AuthenticatePassword(ctx, &dto.LoginPassword{Login: data.Login, Password: data.Password})
+	if err != nil {
+		return dto.TokenTTL{}, err
+	}
+
+	var expires time.Time
+	if token, expires, err = s.instanceToken(ctx, &dto.UserIdInstanceAddress{UserId: id, Instance: data.Instance,
+		Address: data.Address}); err != nil {
+		return dto.TokenTTL{}, err
+	}
+
+	go s.updateLastLogin(id)
+
+	return dto.TokenTTL{Token: token, TTL: time.Until(expires).Round(time.Second)}, nil
+}
+
+// CompleteLogin завершает вход, начатый функцией Login для учетной записи с включенной двухфакторной аутентификацией.
+// Принимает токен незавершенного входа и одноразовый пароль или код восстановления. Возвращает токен сессии и ошибку.
+func (s *Service) CompleteLogin(ctx context.Context, data *dto.TokenCode) (string, error) {
+	var id uuid.UUID
+	var correct bool
+	var err error
+
+	if id, err = s.repository.UserIdFromLoginChallenge(ctx, data.Token); err != nil || id == uuid.Nil {
+		return "", ErrInvalidLoginChallenge()
+	}
+
+	if correct, err = s.verifySecondFactor(ctx, id, data.Code); err != nil {
+		return "", err
+	}
+
+	if !correct {
 		s.metrics.AuthenticationErrorInc()
-		return "", se.ErrAuthenticationData
+		return "", ErrInvalidSecondFactor()
 	}
 
-	if token, err = s.repository.SessionToken(ctx, userIdAndHash.UserId); err == nil {
+	return s.startSession(ctx, id)
+}
+
+// LoginWithCertificate производит вход в учетную запись по логину, извлеченному из клиентского сертификата, который
+// был проверен при установке TLS-соединения. Пароль при этом не требуется, так как владение закрытым ключом
+// сертификата, подписанного доверенным центром сертификации, само является подтверждением подлинности. Если для
+// учетной записи включена двухфакторная аутентификация, сертификат заменяет только пароль: как и функция Login,
+// возвращается токен незавершенного входа вместе с ошибкой service.ErrSecondFactorRequired.
+func (s *Service) LoginWithCertificate(ctx context.Context, accountLogin login.Login) (string, error) {
+	var totpState dto.SecretEnabled
+
+	loginData, err := s.repository.AccountLoginData(ctx, accountLogin)
+	if err != nil {
+		s.metrics.AuthenticationErrorInc()
+		return "", adaptErr(err)
+	}
+
+	if loginData.State != account_state.Enabled {
+		return "", ErrNotEnabledAccount()
+	}
+
+	if totpState, err = s.repository.TOTPSecret(ctx, loginData.UserId); err != nil && !errors.Is(err, jointErr.ErrEmptyResult) {
+		return "", adaptErr(err)
+	}
+
+	if totpState.Enabled {
+		return s.createLoginChallenge(ctx, loginData.UserId)
+	}
+
+	return s.startSession(ctx, loginData.UserId)
+}
+
+// startSession возвращает токен открытой сессии пользователя (сервиса) или открывает новую сессию. В обоих случаях
+// асинхронно обновляется время последнего входа.
+func (s *Service) startSession(ctx context.Context, id uuid.UUID) (string, error) {
+	var token string
+	var err error
+
+	if token, err = s.repository.SessionToken(ctx, id); err == nil {
+		go s.updateLastLogin(id)
 		return token, err
 	}
 
@@ -109,15 +261,155 @@ func (s *Service) Login(ctx context.Context, data *dto.LoginPassword) (string, e
 		return "", adaptErr(err)
 	}
 
-	if err = s.repository.SaveSession(ctx, &dto.UserIdToken{Token: token, UserId: userIdAndHash.UserId}); err != nil {
+	if err = s.repository.SaveSession(ctx, &dto.UserIdToken{Token: token, UserId: id}); err != nil {
 		return "", adaptErr(err)
 	}
 
 	go s.metrics.LoginInc()
+	go s.updateLastLogin(id)
 
 	return token, nil
 }
 
+// updateLastLogin сохраняет время последнего входа в учетную запись. Предназначена для асинхронного вызова, поэтому не
+// зависит от контекста запроса, а ошибка сохранения только записывается в журнал.
+func (s *Service) updateLastLogin(id uuid.UUID) {
+	if err := s.repository.UpdateAccountLastLogin(context.Background(), id); err != nil {
+		slog.Error("unable to update last login time: " + err.Error())
+	}
+}
+
+// createLoginChallenge сохраняет токен незавершенного входа и возвращает его вместе с ошибкой
+// service.ErrSecondFactorRequired.
+func (s *Service) createLoginChallenge(ctx context.Context, id uuid.UUID) (string, error) {
+	token, err := s.createToken()
+	if err != nil {
+		return "", adaptErr(err)
+	}
+
+	if err = s.repository.SaveLoginChallenge(ctx, &dto.UserIdToken{Token: token, UserId: id}); err != nil {
+		return "", adaptErr(err)
+	}
+
+	return token, ErrSecondFactorRequired()
+}
+
+// EnrollTOTP создаёт неподтвержденный секрет одноразовых паролей для учетной записи. Возвращает секрет и ссылку
+// otpauth:// для приложения-аутентификатора. Двухфакторная аутентификация включается после вызова ConfirmTOTP.
+func (s *Service) EnrollTOTP(ctx context.Context, id uuid.UUID) (dto.SecretURI, error) {
+	var loginData dto.UserIdLoginHashState
+	var state dto.SecretEnabled
+	var secret string
+	var err error
+
+	if state, err = s.repository.TOTPSecret(ctx, id); err == nil && state.Enabled {
+		return dto.SecretURI{}, ErrTOTPAlreadyEnabled()
+	}
+
+	if loginData, err = s.repository.AccountLoginDataByUserId(ctx, id); err != nil {
+		return dto.SecretURI{}, adaptErr(err)
+	}
+
+	if secret, err = totp.GenerateSecret(); err != nil {
+		return dto.SecretURI{}, adaptErr(err)
+	}
+
+	if err = s.repository.SetTOTPSecret(ctx, &dto.UserIdSecret{UserId: id, Secret: secret}); err != nil {
+		return dto.SecretURI{}, adaptErr(err)
+	}
+
+	return dto.SecretURI{Secret: secret, URI: totp.URI(s.secure.TOTPIssuer, string(loginData.Login), secret)}, nil
+}
+
+// ConfirmTOTP включает двухфакторную аутентификацию, если переданный одноразовый пароль соответствует секрету,
+// созданному функцией EnrollTOTP. Возвращает коды восстановления, которые показываются пользователю только один раз.
+func (s *Service) ConfirmTOTP(ctx context.Context, data *dto.UserIdCode) ([]string, error) {
+	var state dto.SecretEnabled
+	var codes, hashes []string
+	var err error
+
+	if state, err = s.repository.TOTPSecret(ctx, data.UserId); err != nil {
+		return nil, ErrTOTPNotEnrolled()
+	}
+
+	if state.Enabled {
+		return nil, ErrTOTPAlreadyEnabled()
+	}
+
+	step, ok := totp.Validate(state.Secret, data.Code, time.Now(), totpSkew)
+	if !ok {
+		return nil, ErrInvalidSecondFactor()
+	}
+
+	_, _ = s.repository.MarkTOTPStepUsed(ctx, data.UserId, step)
+
+	if codes, hashes, err = s.createRecoveryCodes(); err != nil {
+		return nil, adaptErr(err)
+	}
+
+	if err = s.repository.EnableTOTP(ctx, &dto.UserIdCodes{UserId: data.UserId, Codes: hashes}); err != nil {
+		return nil, adaptErr(err)
+	}
+
+	return codes, nil
+}
+
+// DisableTOTP отключает двухфакторную аутентификацию. Требует действующий одноразовый пароль или код восстановления.
+func (s *Service) DisableTOTP(ctx context.Context, data *dto.UserIdCode) error {
+	correct, err := s.verifySecondFactor(ctx, data.UserId, data.Code)
+	if err != nil {
+		return err
+	}
+
+	if !correct {
+		s.metrics.AuthenticationErrorInc()
+		return ErrInvalidSecondFactor()
+	}
+
+	return adaptErr(s.repository.DeleteTOTP(ctx, data.UserId))
+}
+
+// verifySecondFactor возвращает true, если переданный код является действующим одноразовым паролем, не
+// использованным ранее, или неиспользованным кодом восстановления (который при этом становится недействительным).
+func (s *Service) verifySecondFactor(ctx context.Context, id uuid.UUID, code string) (bool, error) {
+	state, err := s.repository.TOTPSecret(ctx, id)
+	if err != nil || !state.Enabled {
+		return false, ErrTOTPNotEnrolled()
+	}
+
+	if step, ok := totp.Validate(state.Secret, code, time.Now(), totpSkew); ok {
+		fresh, errMark := s.repository.MarkTOTPStepUsed(ctx, id, step)
+		return fresh, adaptErr(errMark)
+	}
+
+	return s.repository.UseRecoveryCode(ctx, &dto.UserIdCode{UserId: id, Code: hashRecoveryCode(code)}) == nil, nil
+}
+
+// createRecoveryCodes создаёт коды восстановления в количестве, заданном в настройках безопасности. Возвращает сами
+// коды и их хеши для сохранения.
+func (s *Service) createRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, s.secure.RecoveryCodesCount)
+	hashes := make([]string, s.secure.RecoveryCodesCount)
+	b := make([]byte, recoveryCodeLength/2)
+
+	for i := range codes {
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, se.ErrCreateToken
+		}
+		codes[i] = hex.EncodeToString(b)
+		hashes[i] = hashRecoveryCode(codes[i])
+	}
+
+	return codes, hashes, nil
+}
+
+// hashRecoveryCode возвращает хеш кода восстановления. Коды генерируются случайным образом и имеют достаточную
+// энтропию, поэтому медленное хеширование, как для паролей, не требуется.
+func hashRecoveryCode(code string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(code))))
+	return hex.EncodeToString(sum[:])
+}
+
 // Logout производит выход из сеанса путём удаления данных о сессии пользователя (сервиса).
 func (s *Service) Logout(ctx context.Context, id uuid.UUID) error {
 	if s.repository.DeleteSession(ctx, id) == nil {
@@ -128,6 +420,110 @@ func (s *Service) Logout(ctx context.Context, id uuid.UUID) error {
 	return ErrLogout()
 }
 
+// ChangePassword меняет пароль учетной записи при условии, что передан верный текущий пароль. Все открытые сессии
+// пользователя (сервиса) при этом закрываются.
+func (s *Service) ChangePassword(ctx context.Context, data *dto.UserIdOldNewPassword) error {
+	var loginData dto.UserIdLoginHashState
+	var passwordCorrect bool
+	var err error
+
+	if err = data.NewPassword.Validate(); err != nil {
+		return adaptErr(err)
+	}
+
+	if loginData, err = s.repository.AccountLoginDataByUserId(ctx, data.UserId); err != nil {
+		return adaptErr(err)
+	}
+
+	if passwordCorrect, err = s.comparePassword(ctx, loginData.Hash, data.OldPassword); err != nil {
+		return err
+	}
+
+	if !passwordCorrect {
+		s.metrics.AuthenticationErrorInc()
+		return se.ErrAuthenticationData
+	}
+
+	return s.replacePassword(ctx, data.UserId, data.NewPassword)
+}
+
+// ResetPassword создаёт одноразовый токен сброса пароля для учетной записи с переданным логином и закрывает все её
+// открытые сессии. Новый пароль устанавливается функцией CompletePasswordReset.
+func (s *Service) ResetPassword(ctx context.Context, accountLogin login.Login) (string, error) {
+	var loginData dto.UserIdLoginHashState
+	var token string
+	var err error
+
+	if loginData, err = s.repository.AccountLoginData(ctx, accountLogin); err != nil {
+		return "", adaptErr(err)
+	}
+
+	if token, err = s.createToken(); err != nil {
+		return "", adaptErr(err)
+	}
+
+	if err = s.repository.SaveResetToken(ctx, &dto.UserIdToken{UserId: loginData.UserId, Token: token}); err != nil {
+		return "", adaptErr(err)
+	}
+
+	_ = s.repository.DeleteSession(ctx, loginData.UserId)
+
+	return token, nil
+}
+
+// CompletePasswordReset устанавливает новый пароль для учетной записи, которой принадлежит токен сброса пароля.
+func (s *Service) CompletePasswordReset(ctx context.Context, data *dto.TokenPassword) error {
+	var id uuid.UUID
+	var err error
+
+	if err = data.Password.Validate(); err != nil {
+		return adaptErr(err)
+	}
+
+	if id, err = s.repository.UserIdFromResetToken(ctx, data.Token); err != nil || id == uuid.Nil {
+		return ErrInvalidResetToken()
+	}
+
+	return s.replacePassword(ctx, id, data.Password)
+}
+
+// replacePassword сохраняет хеш нового пароля и закрывает сессию пользователя (сервиса).
+func (s *Service) replacePassword(ctx context.Context, id uuid.UUID, pwd password.Password) error {
+	var hash string
+	var err error
+
+	if hash, err = s.createPasswordHash(pwd); err != nil {
+		return adaptErr(err)
+	}
+
+	if err = s.repository.SetAccountPasswordHash(ctx, &dto.UserIdHash{UserId: id, Hash: hash}); err != nil {
+		return adaptErr(err)
+	}
+
+	_ = s.repository.DeleteSession(ctx, id)
+
+	return nil
+}
+
+// comparePassword возвращает true, если пароль соответствует хешу. Сравнение прерывается при отмене контекста.
+func (s *Service) comparePassword(ctx context.Context, hash string, pwd password.Password) (bool, error) {
+	var correct bool
+	compare := make(chan struct{})
+
+	go func() {
+		correct = bcrypt.CompareHashAndPassword([]byte(hash), []byte(pwd)) == nil
+		close(compare)
+	}()
+
+	select {
+	case <-ctx.Done():
+		return false, ctx.Err()
+	case <-compare:
+	}
+
+	return correct, nil
+}
+
 // CreateAccount создаёт активную учетную запись.
 func (s *Service) CreateAccount(ctx context.Context, data *dto.LoginPassword, options AccountOptions) (uuid.UUID, error) {
 	var hash string
@@ -141,6 +537,10 @@ func (s *Service) CreateAccount(ctx context.Context, data *dto.LoginPassword, op
 		return uuid.Nil, adaptErr(err)
 	}
 
+	if options.Metadata != nil && !account_type.Valid(options.Metadata.Type) {
+		return uuid.Nil, ErrInvalidQueryParameters()
+	}
+
 	if hash, err = s.createPasswordHash(data.Password); err != nil {
 		return uuid.Nil, adaptErr(err)
 	}
@@ -149,7 +549,14 @@ func (s *Service) CreateAccount(ctx context.Context, data *dto.LoginPassword, op
 
 	loginData := dto.UserIdLoginHashState{Login: data.Login, UserId: userId, Hash: hash, State: account_state.Enabled}
 
-	if err = s.repository.SetAccountLoginData(ctx, &loginData); err != nil {
+	var metadata *dto.UserIdAccountMetadata
+	if options.Metadata != nil {
+		metadata = &dto.UserIdAccountMetadata{}
+		*metadata = *options.Metadata
+		metadata.UserId = userId
+	}
+
+	if err = s.repository.CreateAccount(ctx, &loginData, metadata); err != nil {
 		return uuid.Nil, adaptErr(err)
 	}
 
@@ -258,9 +665,21 @@ func (s *Service) UserUUIDFromSession(ctx context.Context, token string) (uuid.U
 	return id, adaptErr(err)
 }
 
+// IsAdmin возвращает true, если учетной записи назначена роль администратора, указанная в настройках безопасности.
+func (s *Service) IsAdmin(ctx context.Context, id uuid.UUID) (bool, error) {
+	isAdmin, err := s.repository.AccountHasRole(ctx,
+		&dto.UserIdRoleService{UserId: id, Role: s.secure.AdminRole, Service: s.secure.AdminService})
+	return isAdmin, adaptErr(err)
+}
+
 // createToken создает токен сессии для идентификации аутентифицированного пользователя (сервиса).
 func (s *Service) createToken() (string, error) {
-	b := make([]byte, s.secure.LoginTokenLength/2)
+	return randomHex(s.secure.LoginTokenLength)
+}
+
+// randomHex возвращает случайную строку в шестнадцатеричном представлении переданной длины.
+func randomHex(length int) (string, error) {
+	b := make([]byte, length/2)
 	if _, err := rand.Read(b); err != nil {
 		return "", se.ErrCreateToken
 	}
@@ -268,8 +687,14 @@ func (s *Service) createToken() (string, error) {
 	return hex.EncodeToString(b), nil
 }
 
-// CreatePermission создает разрешение.
-func (s *Service) CreatePermission(ctx context.Context, data *dto.NameServiceDescription) error {
+// CreatePermission создает разрешение с заданным номером, а если номер равен нулю - со следующим свободным номером
+// сервиса. Номера удаленных разрешений выведены из употребления и повторно не назначаются, чтобы выданные ранее токены
+// не получили новый смысл.
+func (s *Service) CreatePermission(ctx context.Context, data *dto.NameNumberDescriptionService) error {
+	if data.Number < 0 {
+		return ErrInvalidQueryParameters()
+	}
+
 	return adaptErr(s.repository.CreatePermission(ctx, data))
 }
 
@@ -283,6 +708,11 @@ func (s *Service) CreateGroup(ctx context.Context, data *dto.NameServiceDescript
 	return adaptErr(s.repository.CreateGroup(ctx, data))
 }
 
+// CreateGlobalGroup создает глобальную группу, которая не привязана к сервису и может объединять роли разных сервисов.
+func (s *Service) CreateGlobalGroup(ctx context.Context, data *dto.NameDescription) error {
+	return adaptErr(s.repository.CreateGlobalGroup(ctx, data))
+}
+
 // RegisterInstance регистрирует название экземпляра сервиса и его секретный ключ. При существующем экземпляре -
 // обновляет о нём данные.
 func (s *Service) RegisterInstance(ctx context.Context, data *dto.NameServiceSecret) error {
@@ -294,21 +724,135 @@ func (s *Service) RegisterService(ctx context.Context, data *dto.NameDescription
 	return adaptErr(s.repository.CreateService(ctx, data))
 }
 
-// AssignRoleToAccount прикрепляет роль к учетной записи.
+// SetAccountMetadata сохраняет тип (human или service), отображаемое имя, контакт и команду-владельца учетной записи.
+// Для неизвестного типа возвращается ошибка ErrInvalidQueryParameters.
+func (s *Service) SetAccountMetadata(ctx context.Context, data *dto.UserIdAccountMetadata) error {
+	if !account_type.Valid(data.Type) {
+		return ErrInvalidQueryParameters()
+	}
+
+	return adaptErr(s.repository.SetAccountMetadata(ctx, data))
+}
+
+// SetServicePermissionEncoding устанавливает способ кодирования номеров разрешений в токенах, выдаваемых для
+// экземпляров сервиса.
+func (s *Service) SetServicePermissionEncoding(ctx context.Context, data *dto.NameEncoding) error {
+	if !permission_encoding.Valid(data.Encoding) {
+		return ErrInvalidQueryParameters()
+	}
+
+	return adaptErr(s.repository.SetServicePermissionEncoding(ctx, data))
+}
+
+// AssignRoleToAccount прикрепляет роль к учетной записи. Если задан период действия назначения, его окончание должно
+// быть позже начала и текущего времени, а условие назначения, если задано, должно быть корректным выражением (см. пакет
+// condition), иначе возвращается ошибка ErrInvalidQueryParameters.
 func (s *Service) AssignRoleToAccount(ctx context.Context, data *dto.UserIdRoleService) error {
+	if !validGrantPeriod(data.ValidFrom, data.ValidUntil) || !validGrantCondition(data.Condition) {
+		return ErrInvalidQueryParameters()
+	}
+
 	return adaptErr(s.repository.AssignRoleToAccount(ctx, data))
 }
 
-// AssignGroupToAccount прикрепляет учетную запись к группе.
+// AssignGroupToAccount прикрепляет учетную запись к группе. Период действия и условие назначения проверяются так же,
+// как в AssignRoleToAccount.
 func (s *Service) AssignGroupToAccount(ctx context.Context, data *dto.UserIdGroupService) error {
+	if !validGrantPeriod(data.ValidFrom, data.ValidUntil) || !validGrantCondition(data.Condition) {
+		return ErrInvalidQueryParameters()
+	}
+
 	return adaptErr(s.repository.AssignGroupToAccount(ctx, data))
 }
 
-// AssignInstancePermissionToAccount прикрепляет к учетной записи разрешения для конкретного экземпляра сервиса.
+// AssignGlobalGroupToAccount прикрепляет учетную запись к глобальной группе: учетная запись получает роли группы во всех
+// сервисах. Период действия и условие назначения проверяются так же, как в AssignRoleToAccount.
+func (s *Service) AssignGlobalGroupToAccount(ctx context.Context, data *dto.UserIdGlobalGroup) error {
+	if !validGrantPeriod(data.ValidFrom, data.ValidUntil) || !validGrantCondition(data.Condition) {
+		return ErrInvalidQueryParameters()
+	}
+
+	return adaptErr(s.repository.AssignGlobalGroupToAccount(ctx, data))
+}
+
+// AssignRoleToGlobalGroup прикрепляет роль сервиса к глобальной группе.
+func (s *Service) AssignRoleToGlobalGroup(ctx context.Context, data *dto.GlobalGroupRoleService) error {
+	return adaptErr(s.repository.AssignRoleToGlobalGroup(ctx, data))
+}
+
+// AssignInstancePermissionToAccount прикрепляет к учетной записи разрешения для конкретного экземпляра сервиса. Период
+// действия и условие назначения проверяются так же, как в AssignRoleToAccount.
 func (s *Service) AssignInstancePermissionToAccount(ctx context.Context, data *dto.UserIdInstancePermission) error {
+	if !validGrantPeriod(data.ValidFrom, data.ValidUntil) || !validGrantCondition(data.Condition) {
+		return ErrInvalidQueryParameters()
+	}
+
 	return adaptErr(s.repository.AssignInstancePermissionToAccount(ctx, data))
 }
 
+// AssignInstanceRoleToAccount прикрепляет к учетной записи роль, действующую только в конкретном экземпляре сервиса.
+// Период действия и условие назначения проверяются так же, как в AssignRoleToAccount.
+func (s *Service) AssignInstanceRoleToAccount(ctx context.Context, data *dto.UserIdInstanceRole) error {
+	if !validGrantPeriod(data.ValidFrom, data.ValidUntil) || !validGrantCondition(data.Condition) {
+		return ErrInvalidQueryParameters()
+	}
+
+	return adaptErr(s.repository.AssignInstanceRoleToAccount(ctx, data))
+}
+
+// AssignInstanceGroupToAccount прикрепляет учетную запись к группе только в конкретном экземпляре сервиса. Период
+// действия и условие назначения проверяются так же, как в AssignRoleToAccount.
+func (s *Service) AssignInstanceGroupToAccount(ctx context.Context, data *dto.UserIdInstanceGroup) error {
+	if !validGrantPeriod(data.ValidFrom, data.ValidUntil) || !validGrantCondition(data.Condition) {
+		return ErrInvalidQueryParameters()
+	}
+
+	return adaptErr(s.repository.AssignInstanceGroupToAccount(ctx, data))
+}
+
+// AssignSelectorPermissionToAccount прикрепляет к учетной записи разрешение сервиса для всех его экземпляров, атрибуты
+// которых соответствуют селектору вида city=Донецк,tier=gold, в том числе для экземпляров, добавленных позже.
+// Селектор сохраняется в канонической записи.
+func (s *Service) AssignSelectorPermissionToAccount(ctx context.Context, data *dto.UserIdSelectorPermission) error {
+	selector, err := label_selector.Parse(data.Selector)
+	if err != nil {
+		return ErrInvalidQueryParameters()
+	}
+
+	request := *data
+	request.Selector = selector.String()
+
+	return adaptErr(s.repository.AssignSelectorPermissionToAccount(ctx, &request))
+}
+
+// validGrantPeriod возвращает true, если окончание периода действия назначения не задано либо наступит позже текущего
+// времени и начала периода.
+func validGrantPeriod(from, until *time.Time) bool {
+	if until == nil {
+		return true
+	}
+
+	return until.After(time.Now()) && (from == nil || until.After(*from))
+}
+
+// DeleteExpiredGrants удаляет истекшие назначения учетным записям ролей, групп и разрешений для экземпляров и
+// обновляет закешированные разрешения учетных записей, назначения которых истекли или вступили в силу после since.
+func (s *Service) DeleteExpiredGrants(ctx context.Context, since time.Time) error {
+	return adaptErr(s.repository.DeleteExpiredGrants(ctx, since))
+}
+
+// AssignParentToRole назначает роли родительскую роль, разрешения которой роль наследует. Назначение, создающее цикл
+// наследования, отклоняется с ошибкой ErrRoleCycle.
+func (s *Service) AssignParentToRole(ctx context.Context, data *dto.RoleParentService) error {
+	return adaptErr(s.repository.AssignParentToRole(ctx, data))
+}
+
+// AssignSubgroupToGroup вкладывает в группу другую группу того же сервиса, учетные записи которой получают роли и
+// разрешения объемлющей группы. Вложение, создающее цикл, отклоняется с ошибкой ErrGroupCycle.
+func (s *Service) AssignSubgroupToGroup(ctx context.Context, data *dto.GroupSubgroupService) error {
+	return adaptErr(s.repository.AssignSubgroupToGroup(ctx, data))
+}
+
 // AssignRoleToGroup прикрепляет роль к группе.
 func (s *Service) AssignRoleToGroup(ctx context.Context, data *dto.GroupRoleService) error {
 	return adaptErr(s.repository.AssignRoleToGroup(ctx, data))
@@ -324,46 +868,187 @@ func (s *Service) AssignPermissionToGroup(ctx context.Context, data *dto.GroupPe
 	return adaptErr(s.repository.AssignPermissionToGroup(ctx, data))
 }
 
-// DeleteRole удаляет роль.
+// DeleteRole помечает роль удаленной. Назначения роли перестают учитываться, но сохраняются до окончательного
+// удаления и возвращаются при восстановлении роли.
 func (s *Service) DeleteRole(ctx context.Context, data *dto.NameService) error {
 	return adaptErr(s.repository.DeleteRole(ctx, data))
 }
 
-// DeleteGroup удаляет группу.
+// DeleteGroup помечает группу удаленной. Назначения группы перестают учитываться, но сохраняются до окончательного
+// удаления и возвращаются при восстановлении группы.
 func (s *Service) DeleteGroup(ctx context.Context, data *dto.NameService) error {
 	return adaptErr(s.repository.DeleteGroup(ctx, data))
 }
 
-// DeletePermission удаляет разрешение.
+// DeleteGlobalGroup удаляет глобальную группу.
+func (s *Service) DeleteGlobalGroup(ctx context.Context, name string) error {
+	return adaptErr(s.repository.DeleteGlobalGroup(ctx, name))
+}
+
+// DeletePermission помечает разрешение удаленным. Номер разрешения занят до окончательного удаления, после которого он
+// выводится из употребления.
 func (s *Service) DeletePermission(ctx context.Context, data *dto.NameService) error {
 	return adaptErr(s.repository.DeletePermission(ctx, data))
 }
 
+// DeprecatePermission помечает разрешение устаревшим. Устаревшее разрешение сохраняет номер и существующие назначения,
+// поэтому выданные токены не меняют смысла, но новые назначения этого разрешения запрещены.
+func (s *Service) DeprecatePermission(ctx context.Context, data *dto.NameService) error {
+	return adaptErr(s.repository.DeprecatePermission(ctx, data))
+}
+
+// DeleteInstance помечает экземпляр сервиса удаленным. Токены для удаленного экземпляра не выдаются.
+func (s *Service) DeleteInstance(ctx context.Context, name string) error {
+	return adaptErr(s.repository.DeleteInstance(ctx, name))
+}
+
+// DeleteService помечает удаленными сервис и все его экземпляры, разрешения, роли и группы. Сервис, роль которого
+// дает права администратора, удалить нельзя.
+func (s *Service) DeleteService(ctx context.Context, name string) error {
+	if name == s.secure.AdminService {
+		return ErrAdministratorDeletion()
+	}
+
+	return adaptErr(s.repository.DeleteService(ctx, name))
+}
+
+// DeleteAccount помечает учетную запись id удаленной по запросу учетной записи callerId и завершает её сессию. Войти в
+// удаленную учетную запись нельзя. Удалить собственную учетную запись и последнюю учетную запись администратора
+// нельзя. Если запрос выполняется не от имени учетной записи (например, утилитой securectl), callerId равен uuid.Nil.
+func (s *Service) DeleteAccount(ctx context.Context, callerId, id uuid.UUID) error {
+	if id == callerId {
+		return ErrOwnAccountDeletion()
+	}
+
+	isAdmin, err := s.IsAdmin(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if isAdmin {
+		var exist bool
+		if exist, err = s.otherAdministratorExists(ctx, id); err != nil {
+			return err
+		}
+		if !exist {
+			return ErrAdministratorDeletion()
+		}
+	}
+
+	return adaptErr(s.repository.DeleteAccount(ctx, id))
+}
+
+// otherAdministratorExists возвращает true, если права администратора есть у действующей учетной записи, отличной от
+// id.
+func (s *Service) otherAdministratorExists(ctx context.Context, id uuid.UUID) (bool, error) {
+	accounts, err := all(ctx, s.repository.Accounts, func(item dto.UserIdLoginState) string { return string(item.Login) })
+	if err != nil {
+		return false, err
+	}
+
+	for _, account := range accounts {
+		if account.UserId == id {
+			continue
+		}
+
+		isAdmin, err := s.IsAdmin(ctx, account.UserId)
+		if err != nil {
+			return false, err
+		}
+		if isAdmin {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// RestoreRole восстанавливает удаленную роль вместе с её назначениями.
+func (s *Service) RestoreRole(ctx context.Context, data *dto.NameService) error {
+	return adaptErr(s.repository.RestoreRole(ctx, data))
+}
+
+// RestoreGroup восстанавливает удаленную группу вместе с её назначениями.
+func (s *Service) RestoreGroup(ctx context.Context, data *dto.NameService) error {
+	return adaptErr(s.repository.RestoreGroup(ctx, data))
+}
+
+// RestorePermission восстанавливает удаленное разрешение вместе с его номером и назначениями.
+func (s *Service) RestorePermission(ctx context.Context, data *dto.NameService) error {
+	return adaptErr(s.repository.RestorePermission(ctx, data))
+}
+
+// RestoreInstance восстанавливает удаленный экземпляр действующего сервиса вместе с его назначениями.
+func (s *Service) RestoreInstance(ctx context.Context, name string) error {
+	return adaptErr(s.repository.RestoreInstance(ctx, name))
+}
+
+// RestoreService восстанавливает удаленный сервис и удаленные вместе с ним экземпляры, разрешения, роли и группы.
+func (s *Service) RestoreService(ctx context.Context, name string) error {
+	return adaptErr(s.repository.RestoreService(ctx, name))
+}
+
+// RestoreAccount восстанавливает удаленную учетную запись вместе с её назначениями.
+func (s *Service) RestoreAccount(ctx context.Context, id uuid.UUID) error {
+	return adaptErr(s.repository.RestoreAccount(ctx, id))
+}
+
+// PurgeDeleted безвозвратно удаляет сущности, помеченные удаленными раньше before. Номера удаляемых разрешений
+// выводятся из употребления.
+func (s *Service) PurgeDeleted(ctx context.Context, before time.Time) error {
+	return adaptErr(s.repository.PurgeDeleted(ctx, before))
+}
+
 // CreateToken создает JWT-токен, содержащий номера разрешений пользователя (сервиса) для переданного экземпляра
-// сервиса.
-func (s *Service) CreateToken(ctx context.Context, data *dto.UserIdInstance) (string, error) {
+// сервиса. Помимо разрешений (perm) и срока действия (exp) токен содержит UUID учетной записи (sub), название
+// экземпляра (aud), время выдачи (iat) и идентификатор (jti), используемые при интроспекции и отзыве токена.
+func (s *Service) CreateToken(ctx context.Context, data *dto.UserIdInstanceAddress) (string, error) {
+	token, _, err := s.instanceToken(ctx, data)
+	return token, err
+}
+
+// instanceToken возвращает токен учетной записи для экземпляра сервиса и время окончания его действия. Токен действует
+// не дольше, чем наиболее рано истекающее из назначений, от которых зависят разрешения в нём. Разрешения сервиса
+// объединяются с разрешениями экземпляра, в которые входят и разрешения ролей и групп, назначенных для экземпляра, и
+// разрешениями назначений с условием, выполненным для адреса клиента data.Address и атрибутов экземпляра.
+func (s *Service) instanceToken(ctx context.Context, data *dto.UserIdInstanceAddress) (string, time.Time, error) {
 	var err error
-	var permissions1, permissions2 []int
-	var serviceName, secret string
+	var permissions1, permissions2, conditional []int
+	var serviceName, secret, encoding string
+	var grantsExpiration time.Time
+
+	instance := &dto.UserIdInstance{UserId: data.UserId, Instance: data.Instance}
 
 	if secret, err = s.repository.InstanceSecret(ctx, data.Instance); err != nil {
-		return "", adaptErr(err)
+		return "", time.Time{}, adaptErr(err)
 	}
 
-	if permissions1, err = s.repository.InstancePermissionsNumbersForAccount(ctx, data); err != nil {
-		return "", adaptErr(err)
+	if permissions1, err = s.repository.InstancePermissionsNumbersForAccount(ctx, instance); err != nil {
+		return "", time.Time{}, adaptErr(err)
+	}
+
+	if conditional, err = s.conditionalPermissions(ctx, data); err != nil {
+		return "", time.Time{}, err
 	}
 
 	if serviceName, err = s.repository.ServiceName(ctx, data.Instance); err != nil {
-		return "", adaptErr(err)
+		return "", time.Time{}, adaptErr(err)
 	}
 
 	if permissions2, err = s.repository.ServicePermissionsNumbersForAccount(ctx,
 		&dto.UserIdService{UserId: data.UserId, Service: serviceName}); err != nil {
-		return "", adaptErr(err)
+		return "", time.Time{}, adaptErr(err)
 	}
 
-	permissions1 = append(permissions1, permissions2...)
+	if encoding, err = s.repository.ServicePermissionEncoding(ctx, serviceName); err != nil {
+		return "", time.Time{}, adaptErr(err)
+	}
+
+	if grantsExpiration, err = s.repository.AccountGrantsExpiration(ctx, instance); err != nil {
+		return "", time.Time{}, adaptErr(err)
+	}
+
+	permissions1 = append(append(permissions1, permissions2...), conditional...)
 	permissions2 = permissions2[:0]
 
 	unique := make(map[int]struct{}, len(permissions1)/2)
@@ -375,12 +1060,38 @@ func (s *Service) CreateToken(ctx context.Context, data *dto.UserIdInstance) (st
 		}
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"perm": permissions2,
-		"exp":  time.Now().Add(s.secure.TokenTTL).Unix(),
-	})
+	tokenId, err := s.createToken()
+	if err != nil {
+		return "", time.Time{}, adaptErr(err)
+	}
+
+	now := time.Now()
+	expires := now.Add(s.secure.TokenTTL)
+	if !grantsExpiration.IsZero() && grantsExpiration.Before(expires) {
+		expires = grantsExpiration
+	}
+
+	claims := jwt.MapClaims{
+		tokenperm.ClaimPermissions: permissions2,
+		tokenperm.ClaimEncoding:    tokenperm.EncodingList,
+		"exp":                      expires.Unix(),
+		"iat":                      now.Unix(),
+		"sub":                      data.UserId.String(),
+		"aud":                      data.Instance,
+		"jti":                      tokenId,
+	}
+
+	if encoding == permission_encoding.Bitmap && tokenperm.FitsBitmap(permissions2) {
+		claims[tokenperm.ClaimPermissions] = tokenperm.EncodeBitmap(permissions2)
+		claims[tokenperm.ClaimEncoding] = tokenperm.EncodingBitmap
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	if err != nil {
+		return "", time.Time{}, err
+	}
 
-	return token.SignedString([]byte(secret))
+	return token, expires, nil
 }
 
 // ServiceNumberedPermissions возвращает пары номер разрешения/название разрешения для сервиса.
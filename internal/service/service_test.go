@@ -2,16 +2,29 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
 	"errors"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	"github.com/lazylex/watch-store/secure/internal/config"
 	"github.com/lazylex/watch-store/secure/internal/domain/value_objects/account_state"
+	"github.com/lazylex/watch-store/secure/internal/domain/value_objects/account_type"
+	"github.com/lazylex/watch-store/secure/internal/domain/value_objects/grant_source"
+	"github.com/lazylex/watch-store/secure/internal/domain/value_objects/permission_encoding"
 	"github.com/lazylex/watch-store/secure/internal/dto"
 	"github.com/lazylex/watch-store/secure/internal/errors/joint"
 	"github.com/lazylex/watch-store/secure/internal/errors/service"
 	mockservice "github.com/lazylex/watch-store/secure/internal/ports/metrics/service/mocks"
 	mockjoint "github.com/lazylex/watch-store/secure/internal/ports/repository/joint/mocks"
+	"github.com/lazylex/watch-store/secure/pkg/tokenperm"
+	"github.com/lazylex/watch-store/secure/pkg/totp"
+	"golang.org/x/crypto/bcrypt"
+	"reflect"
+	"strings"
 	"time"
 
 	"testing"
@@ -32,6 +45,7 @@ func TestService_Login(t *testing.T) {
 	repo.EXPECT().UserIdAndPasswordHash(ctx, loginData.Login).Times(1).Return(idHash, nil)
 	repo.EXPECT().SaveSession(ctx, gomock.Any()).Times(1).Return(nil)
 	metrics.EXPECT().LoginInc().AnyTimes()
+	repo.EXPECT().UpdateAccountLastLogin(gomock.Any(), gomock.Any()).AnyTimes()
 	token, err := s.Login(ctx, &loginData)
 	if len(token) != 24 || err != nil {
 		t.Fail()
@@ -178,7 +192,7 @@ func TestService_CreateAccount(t *testing.T) {
 	metrics := mockservice.NewMockMetricsInterface(controller)
 	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24, PasswordCreationCost: 14})
 
-	repo.EXPECT().SetAccountLoginData(ctx, gomock.Any()).Times(1).Return(nil)
+	repo.EXPECT().CreateAccount(ctx, gomock.Any(), gomock.Any()).Times(1).Return(nil)
 	repo.EXPECT().AssignGroupToAccount(ctx, gomock.Any()).Times(1).Return(nil)
 	repo.EXPECT().AssignRoleToAccount(ctx, gomock.Any()).Times(1).Return(nil)
 	repo.EXPECT().AssignInstancePermissionToAccount(ctx, gomock.Any()).Times(1).Return(nil)
@@ -213,7 +227,7 @@ func TestService_CreateAccountErrAssign(t *testing.T) {
 	metrics := mockservice.NewMockMetricsInterface(controller)
 	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24, PasswordCreationCost: 14})
 
-	repo.EXPECT().SetAccountLoginData(ctx, gomock.Any()).Times(1).Return(nil)
+	repo.EXPECT().CreateAccount(ctx, gomock.Any(), gomock.Any()).Times(1).Return(nil)
 	repo.EXPECT().AssignGroupToAccount(ctx, gomock.Any()).Times(1).Return(joint.ErrDataNotSaved)
 	repo.EXPECT().AssignRoleToAccount(ctx, gomock.Any()).Times(1).Return(joint.ErrDataNotSaved)
 	repo.EXPECT().AssignInstancePermissionToAccount(ctx, gomock.Any()).Times(1).Return(joint.ErrDataNotSaved)
@@ -235,7 +249,7 @@ func TestService_CreateAccountErrSetAccountData(t *testing.T) {
 	metrics := mockservice.NewMockMetricsInterface(controller)
 	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24, PasswordCreationCost: 14})
 
-	repo.EXPECT().SetAccountLoginData(ctx, gomock.Any()).Times(1).Return(joint.ErrDataNotSaved)
+	repo.EXPECT().CreateAccount(ctx, gomock.Any(), gomock.Any()).Times(1).Return(joint.ErrDataNotSaved)
 
 	accountId, err := s.CreateAccount(ctx, &dto.LoginPassword{Login: "Homer Jay Simpson", Password: "donut"}, AccountOptions{
 		Groups:              []dto.NameService{{"users", "tron"}},
@@ -247,6 +261,48 @@ func TestService_CreateAccountErrSetAccountData(t *testing.T) {
 	}
 }
 
+func TestService_CreateAccountMetadata(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24, PasswordCreationCost: bcrypt.MinCost})
+	var created uuid.UUID
+
+	repo.EXPECT().CreateAccount(ctx, gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
+		func(_ context.Context, data *dto.UserIdLoginHashState, metadata *dto.UserIdAccountMetadata) error {
+			created = data.UserId
+			if metadata.UserId != created || metadata.Type != account_type.Service || metadata.OwnerTeam != "logistics" {
+				t.Error("unexpected metadata")
+			}
+			return nil
+		})
+
+	accountId, err := s.CreateAccount(ctx, &dto.LoginPassword{Login: "stock-service", Password: "Donut-Password"}, AccountOptions{
+		Metadata: &dto.UserIdAccountMetadata{Type: account_type.Service, OwnerTeam: "logistics"},
+	})
+	if err != nil || accountId != created {
+		t.Fail()
+	}
+}
+
+func TestService_CreateAccountErrInvalidType(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24, PasswordCreationCost: bcrypt.MinCost})
+
+	repo.EXPECT().CreateAccount(ctx, gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := s.CreateAccount(ctx, &dto.LoginPassword{Login: "stock-service", Password: "Donut-Password"}, AccountOptions{
+		Metadata: &dto.UserIdAccountMetadata{Type: "robot"},
+	})
+	if !errors.Is(err, service.ErrInvalidQueryParameters) {
+		t.Fail()
+	}
+}
+
 func TestService_RegisterInstance(t *testing.T) {
 	ctx := context.Background()
 	controller := gomock.NewController(t)
@@ -313,7 +369,7 @@ func TestService_CreatePermission(t *testing.T) {
 	repo := mockjoint.NewMockInterface(controller)
 	metrics := mockservice.NewMockMetricsInterface(controller)
 	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24, PasswordCreationCost: 14})
-	data := dto.NameServiceDescription{Name: "Flynn", Description: "", Service: "tron"}
+	data := dto.NameNumberDescriptionService{Name: "Flynn", Description: "", Service: "tron"}
 
 	repo.EXPECT().CreatePermission(ctx, &data).Times(1).Return(nil)
 
@@ -328,7 +384,7 @@ func TestService_CreatePermissionErr(t *testing.T) {
 	repo := mockjoint.NewMockInterface(controller)
 	metrics := mockservice.NewMockMetricsInterface(controller)
 	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24, PasswordCreationCost: 14})
-	data := dto.NameServiceDescription{Name: "Flynn", Description: "", Service: "tron"}
+	data := dto.NameNumberDescriptionService{Name: "Flynn", Description: "", Service: "tron"}
 
 	repo.EXPECT().CreatePermission(ctx, &data).Times(1).Return(joint.ErrDuplicateData)
 
@@ -337,6 +393,160 @@ func TestService_CreatePermissionErr(t *testing.T) {
 	}
 }
 
+func TestService_CreatePermissionErrRetiredNumber(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24, PasswordCreationCost: 14})
+	data := dto.NameNumberDescriptionService{Name: "Flynn", Number: 7, Service: "tron"}
+
+	repo.EXPECT().CreatePermission(ctx, &data).Times(1).Return(joint.ErrRetiredNumber)
+
+	if !errors.Is(s.CreatePermission(ctx, &data), service.ErrRetiredNumber) {
+		t.Fail()
+	}
+}
+
+func TestService_CreatePermissionErrInvalidNumber(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24, PasswordCreationCost: 14})
+
+	if !errors.Is(s.CreatePermission(ctx, &dto.NameNumberDescriptionService{Name: "Flynn", Number: -1, Service: "tron"}),
+		service.ErrInvalidQueryParameters) {
+		t.Fail()
+	}
+}
+
+func TestService_DeprecatePermission(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24, PasswordCreationCost: 14})
+	data := dto.NameService{Name: "Flynn", Service: "tron"}
+
+	repo.EXPECT().DeprecatePermission(ctx, &data).Times(1).Return(nil)
+
+	if s.DeprecatePermission(ctx, &data) != nil {
+		t.Fail()
+	}
+}
+
+func TestService_DeleteService(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24, PasswordCreationCost: 14})
+
+	repo.EXPECT().DeleteService(ctx, "tron").Times(1).Return(nil)
+
+	if s.DeleteService(ctx, "tron") != nil {
+		t.Fail()
+	}
+}
+
+func TestService_DeleteAccount(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24, PasswordCreationCost: 14})
+	id := uuid.New()
+
+	repo.EXPECT().AccountHasRole(ctx, gomock.Any()).Times(1).Return(false, nil)
+	repo.EXPECT().DeleteAccount(ctx, id).Times(1).Return(nil)
+
+	if s.DeleteAccount(ctx, uuid.New(), id) != nil {
+		t.Fail()
+	}
+}
+
+func TestService_DeleteAccountErrOwnAccountDeletion(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24, PasswordCreationCost: 14})
+	id := uuid.New()
+
+	repo.EXPECT().DeleteAccount(ctx, gomock.Any()).Times(0)
+
+	if !errors.Is(s.DeleteAccount(ctx, id, id), service.ErrOwnAccountDeletion) {
+		t.Fail()
+	}
+}
+
+func TestService_DeleteAccountErrAdministratorDeletion(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24, AdminRole: "admin", AdminService: "secure"})
+	id, other := uuid.New(), uuid.New()
+
+	repo.EXPECT().AccountHasRole(ctx, &dto.UserIdRoleService{UserId: id, Role: "admin", Service: "secure"}).
+		Times(1).Return(true, nil)
+	repo.EXPECT().AccountHasRole(ctx, &dto.UserIdRoleService{UserId: other, Role: "admin", Service: "secure"}).
+		Times(1).Return(false, nil)
+	repo.EXPECT().Accounts(ctx, gomock.Any()).Times(1).Return([]dto.UserIdLoginState{
+		{UserId: id, Login: "admin"}, {UserId: other, Login: "seller"},
+	}, nil)
+	repo.EXPECT().DeleteAccount(ctx, gomock.Any()).Times(0)
+
+	if !errors.Is(s.DeleteAccount(ctx, uuid.Nil, id), service.ErrAdministratorDeletion) {
+		t.Fail()
+	}
+}
+
+func TestService_DeleteServiceErrAdministratorDeletion(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24, AdminRole: "admin", AdminService: "secure"})
+
+	repo.EXPECT().DeleteService(ctx, gomock.Any()).Times(0)
+
+	if !errors.Is(s.DeleteService(ctx, "secure"), service.ErrAdministratorDeletion) {
+		t.Fail()
+	}
+}
+
+func TestService_RestoreRoleErrNothingWasChanged(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24, PasswordCreationCost: 14})
+	data := dto.NameService{Name: "creator", Service: "tron"}
+
+	repo.EXPECT().RestoreRole(ctx, &data).Times(1).Return(joint.ErrDataNotSaved)
+
+	if !errors.Is(s.RestoreRole(ctx, &data), service.ErrNothingWasChanged) {
+		t.Fail()
+	}
+}
+
+func TestService_PurgeDeleted(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24, PasswordCreationCost: 14})
+	before := time.Now().Add(-720 * time.Hour)
+
+	repo.EXPECT().PurgeDeleted(ctx, before).Times(1).Return(nil)
+
+	if s.PurgeDeleted(ctx, before) != nil {
+		t.Fail()
+	}
+}
+
 func TestService_CreateRole(t *testing.T) {
 	ctx := context.Background()
 	controller := gomock.NewController(t)
@@ -586,10 +796,1945 @@ func TestService_CreateToken(t *testing.T) {
 
 	repo.EXPECT().InstanceSecret(ctx, gomock.Any()).Times(1).Return("secret", nil)
 	repo.EXPECT().InstancePermissionsNumbersForAccount(ctx, gomock.Any()).Times(1).Return([]int{1}, nil)
+	repo.EXPECT().ConditionalPermissionsForAccount(ctx, gomock.Any()).Times(1).Return(nil, nil)
 	repo.EXPECT().ServiceName(ctx, gomock.Any()).Times(1).Return("", nil)
 	repo.EXPECT().ServicePermissionsNumbersForAccount(ctx, gomock.Any()).Times(1).Return([]int{4, 6}, nil)
-	token, err := s.CreateToken(ctx, &dto.UserIdInstance{UserId: uuid.Nil, Instance: ""})
+	repo.EXPECT().ServicePermissionEncoding(ctx, gomock.Any()).Times(1).Return(permission_encoding.List, nil)
+	repo.EXPECT().AccountGrantsExpiration(ctx, gomock.Any()).Times(1).Return(time.Time{}, nil)
+	token, err := s.CreateToken(ctx, &dto.UserIdInstanceAddress{UserId: uuid.Nil, Instance: ""})
 	if len(token) == 0 || err != nil {
 		t.Fail()
 	}
 }
+
+func TestService_CreateTokenBitmap(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24, TokenTTL: time.Hour})
+
+	repo.EXPECT().InstanceSecret(ctx, "instance").Times(1).Return("secret", nil)
+	repo.EXPECT().InstancePermissionsNumbersForAccount(ctx, gomock.Any()).Times(1).Return([]int{1, 9}, nil)
+	repo.EXPECT().ConditionalPermissionsForAccount(ctx, gomock.Any()).Times(1).Return(nil, nil)
+	repo.EXPECT().ServiceName(ctx, "instance").Times(1).Return("service", nil)
+	repo.EXPECT().ServicePermissionsNumbersForAccount(ctx, gomock.Any()).Times(1).Return([]int{9, 4}, nil)
+	repo.EXPECT().ServicePermissionEncoding(ctx, "service").Times(1).Return(permission_encoding.Bitmap, nil)
+	repo.EXPECT().AccountGrantsExpiration(ctx, gomock.Any()).Times(1).Return(time.Time{}, nil)
+
+	token, err := s.CreateToken(ctx, &dto.UserIdInstanceAddress{UserId: uuid.New(), Instance: "instance"})
+	if err != nil {
+		t.Fatal()
+	}
+
+	claims := jwt.MapClaims{}
+	if _, err = jwt.ParseWithClaims(token, claims, func(*jwt.Token) (any, error) { return []byte("secret"), nil }); err != nil {
+		t.Fatal()
+	}
+
+	if _, isString := claims[tokenperm.ClaimPermissions].(string); !isString {
+		t.Fail()
+	}
+
+	if numbers, err := tokenperm.FromClaims(claims); err != nil || !reflect.DeepEqual(numbers, []int{1, 4, 9}) {
+		t.Fail()
+	}
+}
+
+func TestService_CreateTokenGrantExpiration(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24, TokenTTL: time.Hour})
+	until := time.Now().Add(10 * time.Minute).Truncate(time.Second)
+
+	repo.EXPECT().InstanceSecret(ctx, "instance").Times(1).Return("secret", nil)
+	repo.EXPECT().InstancePermissionsNumbersForAccount(ctx, gomock.Any()).Times(1).Return([]int{1}, nil)
+	repo.EXPECT().ConditionalPermissionsForAccount(ctx, gomock.Any()).Times(1).Return(nil, nil)
+	repo.EXPECT().ServiceName(ctx, "instance").Times(1).Return("service", nil)
+	repo.EXPECT().ServicePermissionsNumbersForAccount(ctx, gomock.Any()).Times(1).Return([]int{2}, nil)
+	repo.EXPECT().ServicePermissionEncoding(ctx, "service").Times(1).Return(permission_encoding.List, nil)
+	repo.EXPECT().AccountGrantsExpiration(ctx, gomock.Any()).Times(1).Return(until, nil)
+
+	token, err := s.CreateToken(ctx, &dto.UserIdInstanceAddress{UserId: uuid.New(), Instance: "instance"})
+	if err != nil {
+		t.Fatal()
+	}
+
+	claims := jwt.MapClaims{}
+	if _, err = jwt.ParseWithClaims(token, claims, func(*jwt.Token) (any, error) { return []byte("secret"), nil }); err != nil {
+		t.Fatal()
+	}
+
+	if exp, err := claims.GetExpirationTime(); err != nil || !exp.Time.Equal(until) {
+		t.Fail()
+	}
+}
+
+func TestService_CreateTokenConditionalPermissions(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24, TokenTTL: time.Hour})
+
+	repo.EXPECT().InstanceSecret(ctx, "instance").Times(1).Return("secret", nil)
+	repo.EXPECT().InstancePermissionsNumbersForAccount(ctx, gomock.Any()).Times(1).Return([]int{1}, nil)
+	repo.EXPECT().ConditionalPermissionsForAccount(ctx, &dto.UserIdInstance{UserId: uuid.Nil, Instance: "instance"}).
+		Times(1).Return([]dto.NumberCondition{
+		{Number: 3, Condition: `instance.region == "south" && ip_in("10.0.0.0/8")`},
+		{Number: 4, Condition: `instance.region == "north"`},
+		{Number: 5, Condition: `ip_in("192.168.0.0/16")`},
+	}, nil)
+	repo.EXPECT().InstanceAttributes(ctx, "instance").Times(1).Return(map[string]string{"region": "south"}, nil)
+	repo.EXPECT().ServiceName(ctx, "instance").Times(1).Return("service", nil)
+	repo.EXPECT().ServicePermissionsNumbersForAccount(ctx, gomock.Any()).Times(1).Return([]int{2}, nil)
+	repo.EXPECT().ServicePermissionEncoding(ctx, "service").Times(1).Return(permission_encoding.List, nil)
+	repo.EXPECT().AccountGrantsExpiration(ctx, gomock.Any()).Times(1).Return(time.Time{}, nil)
+
+	token, err := s.CreateToken(ctx, &dto.UserIdInstanceAddress{Instance: "instance", Address: "10.1.2.3:54321"})
+	if err != nil {
+		t.Fatal()
+	}
+
+	claims := jwt.MapClaims{}
+	if _, err = jwt.ParseWithClaims(token, claims, func(*jwt.Token) (any, error) { return []byte("secret"), nil }); err != nil {
+		t.Fatal()
+	}
+
+	if numbers, err := tokenperm.FromClaims(claims); err != nil || !reflect.DeepEqual(numbers, []int{1, 2, 3}) {
+		t.Fail()
+	}
+}
+
+func TestService_AssignRoleToAccountErrInvalidCondition(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24})
+
+	repo.EXPECT().AssignRoleToAccount(ctx, gomock.Any()).Times(0)
+
+	if !errors.Is(s.AssignRoleToAccount(ctx, &dto.UserIdRoleService{UserId: uuid.New(), Role: "role", Service: "service",
+		Condition: `ip_in("10.0.0.0/8"`}), service.ErrInvalidQueryParameters) {
+		t.Fail()
+	}
+}
+
+func TestService_SetInstanceAttributeErrInvalidName(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24})
+
+	repo.EXPECT().SetInstanceAttribute(ctx, gomock.Any()).Times(0)
+
+	if !errors.Is(s.SetInstanceAttribute(ctx, &dto.InstanceNameValue{Instance: "store-1", Name: "1region", Value: "south"}),
+		service.ErrInvalidQueryParameters) {
+		t.Fail()
+	}
+}
+
+func TestService_AssignSelectorPermissionToAccount(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24})
+	userId := uuid.New()
+
+	repo.EXPECT().AssignSelectorPermissionToAccount(ctx, &dto.UserIdSelectorPermission{UserId: userId, Permission: "sell",
+		Service: "store", Selector: "city=Донецк,tier=gold"}).Times(1).Return(nil)
+
+	if s.AssignSelectorPermissionToAccount(ctx, &dto.UserIdSelectorPermission{UserId: userId, Permission: "sell",
+		Service: "store", Selector: " tier=gold, city = Донецк"}) != nil {
+		t.Fail()
+	}
+}
+
+func TestService_AssignSelectorPermissionToAccountErrInvalidSelector(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24})
+
+	repo.EXPECT().AssignSelectorPermissionToAccount(ctx, gomock.Any()).Times(0)
+
+	for _, selector := range []string{"", "city", "city=", "city=Донецк,city=Макеевка", "1city=Донецк", "city=Донецк,"} {
+		if !errors.Is(s.AssignSelectorPermissionToAccount(ctx, &dto.UserIdSelectorPermission{UserId: uuid.New(),
+			Permission: "sell", Service: "store", Selector: selector}), service.ErrInvalidQueryParameters) {
+			t.Errorf("selector %q accepted", selector)
+		}
+	}
+}
+
+func TestService_InstanceDetails(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24})
+
+	repo.EXPECT().ServiceName(ctx, "store-1").Times(1).Return("store", nil)
+	repo.EXPECT().InstanceAttributes(ctx, "store-1").Times(1).Return(map[string]string{"city": "Донецк"}, nil)
+
+	details, err := s.InstanceDetails(ctx, "store-1")
+	if err != nil || details.Service != "store" || details.Attributes["city"] != "Донецк" {
+		t.Fail()
+	}
+}
+
+func TestService_AssignRoleToAccountErrInvalidPeriod(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24})
+	from, until := time.Now().Add(time.Hour), time.Now().Add(time.Minute)
+
+	repo.EXPECT().AssignRoleToAccount(ctx, gomock.Any()).Times(0)
+
+	if !errors.Is(s.AssignRoleToAccount(ctx, &dto.UserIdRoleService{UserId: uuid.New(), Role: "role", Service: "service",
+		ValidFrom: &from, ValidUntil: &until}), service.ErrInvalidQueryParameters) {
+		t.Fail()
+	}
+}
+
+func TestService_AssignGlobalGroupToAccountErrInvalidPeriod(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24})
+	until := time.Now().Add(-time.Minute)
+
+	repo.EXPECT().AssignGlobalGroupToAccount(ctx, gomock.Any()).Times(0)
+
+	if !errors.Is(s.AssignGlobalGroupToAccount(ctx, &dto.UserIdGlobalGroup{UserId: uuid.New(), GlobalGroup: "Сервис заказа",
+		ValidUntil: &until}), service.ErrInvalidQueryParameters) {
+		t.Fail()
+	}
+}
+
+func TestService_AssignInstanceRoleToAccountErrInvalidPeriod(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24})
+	from := time.Now().Add(time.Hour)
+	until := from.Add(-time.Minute)
+
+	repo.EXPECT().AssignInstanceRoleToAccount(ctx, gomock.Any()).Times(0)
+
+	if !errors.Is(s.AssignInstanceRoleToAccount(ctx, &dto.UserIdInstanceRole{UserId: uuid.New(), Instance: "store-1",
+		Role: "Продавец", ValidFrom: &from, ValidUntil: &until}), service.ErrInvalidQueryParameters) {
+		t.Fail()
+	}
+}
+
+func TestService_SetServicePermissionEncodingErrInvalid(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24})
+
+	repo.EXPECT().SetServicePermissionEncoding(ctx, gomock.Any()).Times(0)
+
+	if !errors.Is(s.SetServicePermissionEncoding(ctx, &dto.NameEncoding{Name: "service", Encoding: "gzip"}),
+		service.ErrInvalidQueryParameters) {
+		t.Fail()
+	}
+}
+
+func TestService_SetAccountMetadata(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24})
+	data := dto.UserIdAccountMetadata{UserId: uuid.New(), Type: account_type.Service, DisplayName: "Склад",
+		Contact: "stock@example.com", OwnerTeam: "logistics"}
+
+	repo.EXPECT().SetAccountMetadata(ctx, &data).Times(1).Return(nil)
+
+	if err := s.SetAccountMetadata(ctx, &data); err != nil {
+		t.Fail()
+	}
+}
+
+func TestService_SetAccountMetadataErrInvalidType(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24})
+
+	repo.EXPECT().SetAccountMetadata(ctx, gomock.Any()).Times(0)
+
+	if !errors.Is(s.SetAccountMetadata(ctx, &dto.UserIdAccountMetadata{UserId: uuid.New(), Type: "robot"}),
+		service.ErrInvalidQueryParameters) {
+		t.Fail()
+	}
+}
+
+func TestService_ChangePassword(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24, PasswordCreationCost: bcrypt.MinCost})
+	id := uuid.New()
+	hash, _ := bcrypt.GenerateFromPassword([]byte("Old_password"), bcrypt.MinCost)
+
+	repo.EXPECT().AccountLoginDataByUserId(ctx, id).Times(1).Return(dto.UserIdLoginHashState{UserId: id, Hash: string(hash)}, nil)
+	repo.EXPECT().SetAccountPasswordHash(ctx, gomock.Any()).Times(1).Return(nil)
+	repo.EXPECT().DeleteSession(ctx, id).Times(1).Return(nil)
+
+	if s.ChangePassword(ctx, &dto.UserIdOldNewPassword{UserId: id, OldPassword: "Old_password", NewPassword: "New_password"}) != nil {
+		t.Fail()
+	}
+}
+
+func TestService_ChangePasswordErrIncorrectOldPassword(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24, PasswordCreationCost: bcrypt.MinCost})
+	id := uuid.New()
+	hash, _ := bcrypt.GenerateFromPassword([]byte("Old_password"), bcrypt.MinCost)
+
+	repo.EXPECT().AccountLoginDataByUserId(ctx, id).Times(1).Return(dto.UserIdLoginHashState{UserId: id, Hash: string(hash)}, nil)
+	metrics.EXPECT().AuthenticationErrorInc().Times(1)
+
+	if s.ChangePassword(ctx, &dto.UserIdOldNewPassword{UserId: id, OldPassword: "Wrong_password", NewPassword: "New_password"}) != service.ErrAuthenticationData {
+		t.Fail()
+	}
+}
+
+func TestService_ChangePasswordErrWeakNewPassword(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24, PasswordCreationCost: bcrypt.MinCost})
+
+	if s.ChangePassword(ctx, &dto.UserIdOldNewPassword{UserId: uuid.New(), OldPassword: "Old_password", NewPassword: "new"}) == nil {
+		t.Fail()
+	}
+}
+
+func TestService_ResetPassword(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24, PasswordCreationCost: bcrypt.MinCost})
+	id := uuid.New()
+
+	repo.EXPECT().AccountLoginData(ctx, loginData.Login).Times(1).Return(dto.UserIdLoginHashState{UserId: id}, nil)
+	repo.EXPECT().SaveResetToken(ctx, gomock.Any()).Times(1).Return(nil)
+	repo.EXPECT().DeleteSession(ctx, id).Times(1).Return(nil)
+
+	token, err := s.ResetPassword(ctx, loginData.Login)
+	if len(token) != 24 || err != nil {
+		t.Fail()
+	}
+}
+
+func TestService_ResetPasswordErrUnknownLogin(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24, PasswordCreationCost: bcrypt.MinCost})
+
+	repo.EXPECT().AccountLoginData(ctx, loginData.Login).Times(1).Return(dto.UserIdLoginHashState{}, joint.ErrEmptyResult)
+
+	token, err := s.ResetPassword(ctx, loginData.Login)
+	if len(token) != 0 || err != service.ErrEmptyResult {
+		t.Fail()
+	}
+}
+
+func TestService_CompletePasswordReset(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24, PasswordCreationCost: bcrypt.MinCost})
+	id := uuid.New()
+
+	repo.EXPECT().UserIdFromResetToken(ctx, "reset").Times(1).Return(id, nil)
+	repo.EXPECT().SetAccountPasswordHash(ctx, gomock.Any()).Times(1).Return(nil)
+	repo.EXPECT().DeleteSession(ctx, id).Times(1).Return(nil)
+
+	if s.CompletePasswordReset(ctx, &dto.TokenPassword{Token: "reset", Password: "New_password"}) != nil {
+		t.Fail()
+	}
+}
+
+func TestService_CompletePasswordResetErrInvalidToken(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24, PasswordCreationCost: bcrypt.MinCost})
+
+	repo.EXPECT().UserIdFromResetToken(ctx, "reset").Times(1).Return(uuid.Nil, errors.New(""))
+
+	if s.CompletePasswordReset(ctx, &dto.TokenPassword{Token: "reset", Password: "New_password"}) != service.ErrInvalidResetToken {
+		t.Fail()
+	}
+}
+
+func TestService_LoginSecondFactorRequired(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24, PasswordCreationCost: bcrypt.MinCost})
+	id := uuid.New()
+	hash, _ := bcrypt.GenerateFromPassword([]byte(loginData.Password), bcrypt.MinCost)
+
+	repo.EXPECT().AccountState(ctx, loginData.Login).Times(1).Return(account_state.State(account_state.Enabled), nil)
+	repo.EXPECT().UserIdAndPasswordHash(ctx, loginData.Login).Times(1).Return(dto.UserIdHash{UserId: id, Hash: string(hash)}, nil)
+	repo.EXPECT().TOTPSecret(ctx, id).Times(1).Return(dto.SecretEnabled{Secret: "JBSWY3DPEHPK3PXP", Enabled: true}, nil)
+	repo.EXPECT().SaveLoginChallenge(ctx, gomock.Any()).Times(1).Return(nil)
+
+	challenge, err := s.Login(ctx, &loginData)
+	if len(challenge) != 24 || err != service.ErrSecondFactorRequired {
+		t.Fail()
+	}
+}
+
+func TestService_CompleteLogin(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24, PasswordCreationCost: bcrypt.MinCost})
+	id := uuid.New()
+	secret, _ := totp.GenerateSecret()
+
+	repo.EXPECT().UserIdFromLoginChallenge(ctx, "challenge").Times(1).Return(id, nil)
+	repo.EXPECT().TOTPSecret(ctx, id).Times(1).Return(dto.SecretEnabled{Secret: secret, Enabled: true}, nil)
+	repo.EXPECT().MarkTOTPStepUsed(ctx, id, gomock.Any()).Times(1).Return(true, nil)
+	repo.EXPECT().SessionToken(ctx, id).Times(1).Return("", joint.ErrEmptyResult)
+	repo.EXPECT().SaveSession(ctx, gomock.Any()).Times(1).Return(nil)
+	metrics.EXPECT().LoginInc().AnyTimes()
+	repo.EXPECT().UpdateAccountLastLogin(gomock.Any(), gomock.Any()).AnyTimes()
+
+	token, err := s.CompleteLogin(ctx, &dto.TokenCode{Token: "challenge", Code: currentCode(t, secret)})
+	if len(token) != 24 || err != nil {
+		t.Fail()
+	}
+}
+
+func TestService_CompleteLoginErrReusedCode(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24, PasswordCreationCost: bcrypt.MinCost})
+	id := uuid.New()
+	secret, _ := totp.GenerateSecret()
+
+	repo.EXPECT().UserIdFromLoginChallenge(ctx, "challenge").Times(1).Return(id, nil)
+	repo.EXPECT().TOTPSecret(ctx, id).Times(1).Return(dto.SecretEnabled{Secret: secret, Enabled: true}, nil)
+	repo.EXPECT().MarkTOTPStepUsed(ctx, id, gomock.Any()).Times(1).Return(false, nil)
+	metrics.EXPECT().AuthenticationErrorInc().Times(1)
+
+	if _, err := s.CompleteLogin(ctx, &dto.TokenCode{Token: "challenge", Code: currentCode(t, secret)}); err != service.ErrInvalidSecondFactor {
+		t.Fail()
+	}
+}
+
+func TestService_CompleteLoginErrInvalidChallenge(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24, PasswordCreationCost: bcrypt.MinCost})
+
+	repo.EXPECT().UserIdFromLoginChallenge(ctx, "challenge").Times(1).Return(uuid.Nil, joint.ErrEmptyResult)
+
+	if _, err := s.CompleteLogin(ctx, &dto.TokenCode{Token: "challenge", Code: "000000"}); err != service.ErrInvalidLoginChallenge {
+		t.Fail()
+	}
+}
+
+func TestService_EnrollTOTP(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24, TOTPIssuer: "secure"})
+	id := uuid.New()
+
+	repo.EXPECT().TOTPSecret(ctx, id).Times(1).Return(dto.SecretEnabled{}, joint.ErrEmptyResult)
+	repo.EXPECT().AccountLoginDataByUserId(ctx, id).Times(1).Return(dto.UserIdLoginHashState{UserId: id, Login: "good"}, nil)
+	repo.EXPECT().SetTOTPSecret(ctx, gomock.Any()).Times(1).Return(nil)
+
+	secret, err := s.EnrollTOTP(ctx, id)
+	if err != nil || len(secret.Secret) == 0 || len(secret.URI) == 0 {
+		t.Fail()
+	}
+}
+
+func TestService_EnrollTOTPErrAlreadyEnabled(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24})
+	id := uuid.New()
+
+	repo.EXPECT().TOTPSecret(ctx, id).Times(1).Return(dto.SecretEnabled{Secret: "JBSWY3DPEHPK3PXP", Enabled: true}, nil)
+
+	if _, err := s.EnrollTOTP(ctx, id); err != service.ErrTOTPAlreadyEnabled {
+		t.Fail()
+	}
+}
+
+func TestService_ConfirmTOTP(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24, RecoveryCodesCount: 5})
+	id := uuid.New()
+	secret, _ := totp.GenerateSecret()
+
+	repo.EXPECT().TOTPSecret(ctx, id).Times(1).Return(dto.SecretEnabled{Secret: secret}, nil)
+	repo.EXPECT().MarkTOTPStepUsed(ctx, id, gomock.Any()).Times(1).Return(true, nil)
+	repo.EXPECT().EnableTOTP(ctx, gomock.Any()).Times(1).Return(nil)
+
+	codes, err := s.ConfirmTOTP(ctx, &dto.UserIdCode{UserId: id, Code: currentCode(t, secret)})
+	if err != nil || len(codes) != 5 {
+		t.Fail()
+	}
+}
+
+func TestService_ConfirmTOTPErrInvalidCode(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24, RecoveryCodesCount: 5})
+	id := uuid.New()
+	secret, _ := totp.GenerateSecret()
+
+	repo.EXPECT().TOTPSecret(ctx, id).Times(1).Return(dto.SecretEnabled{Secret: secret}, nil)
+
+	if _, err := s.ConfirmTOTP(ctx, &dto.UserIdCode{UserId: id, Code: "not a code"}); err != service.ErrInvalidSecondFactor {
+		t.Fail()
+	}
+}
+
+func TestService_DisableTOTPWithRecoveryCode(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24})
+	id := uuid.New()
+	secret, _ := totp.GenerateSecret()
+
+	repo.EXPECT().TOTPSecret(ctx, id).Times(1).Return(dto.SecretEnabled{Secret: secret, Enabled: true}, nil)
+	repo.EXPECT().UseRecoveryCode(ctx, &dto.UserIdCode{UserId: id, Code: hashRecoveryCode("3f9a1c0b7e")}).Times(1).Return(nil)
+	repo.EXPECT().DeleteTOTP(ctx, id).Times(1).Return(nil)
+
+	if s.DisableTOTP(ctx, &dto.UserIdCode{UserId: id, Code: " 3F9A1C0B7E "}) != nil {
+		t.Fail()
+	}
+}
+
+// currentCode возвращает одноразовый пароль для секрета на текущий момент времени.
+func currentCode(t *testing.T, secret string) string {
+	code, err := totp.Code(secret, time.Now())
+	if err != nil {
+		t.Fatal()
+	}
+
+	return code
+}
+
+func TestService_LoginWithCertificate(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24})
+	id := uuid.New()
+
+	repo.EXPECT().AccountLoginData(ctx, loginData.Login).Times(1).Return(dto.UserIdLoginHashState{UserId: id, State: account_state.Enabled}, nil)
+	repo.EXPECT().TOTPSecret(ctx, id).Times(1).Return(dto.SecretEnabled{}, joint.ErrEmptyResult)
+	repo.EXPECT().SessionToken(ctx, id).Times(1).Return("", joint.ErrEmptyResult)
+	repo.EXPECT().SaveSession(ctx, gomock.Any()).Times(1).Return(nil)
+	metrics.EXPECT().LoginInc().AnyTimes()
+	repo.EXPECT().UpdateAccountLastLogin(gomock.Any(), gomock.Any()).AnyTimes()
+
+	token, err := s.LoginWithCertificate(ctx, loginData.Login)
+	if len(token) != 24 || err != nil {
+		t.Fail()
+	}
+}
+
+func TestService_LoginWithCertificateUpdatesLastLogin(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24})
+	id := uuid.New()
+	updated := make(chan struct{})
+
+	repo.EXPECT().AccountLoginData(ctx, loginData.Login).Times(1).Return(dto.UserIdLoginHashState{UserId: id, State: account_state.Enabled}, nil)
+	repo.EXPECT().TOTPSecret(ctx, id).Times(1).Return(dto.SecretEnabled{}, joint.ErrEmptyResult)
+	repo.EXPECT().SessionToken(ctx, id).Times(1).Return("", joint.ErrEmptyResult)
+	repo.EXPECT().SaveSession(ctx, gomock.Any()).Times(1).Return(nil)
+	metrics.EXPECT().LoginInc().AnyTimes()
+	repo.EXPECT().UpdateAccountLastLogin(gomock.Any(), id).Times(1).DoAndReturn(func(context.Context, uuid.UUID) error {
+		close(updated)
+		return nil
+	})
+
+	if _, err := s.LoginWithCertificate(ctx, loginData.Login); err != nil {
+		t.Fatal()
+	}
+
+	select {
+	case <-updated:
+	case <-time.After(time.Second):
+		t.Fail()
+	}
+}
+
+func TestService_LoginWithCertificateSecondFactorRequired(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24})
+	id := uuid.New()
+
+	repo.EXPECT().AccountLoginData(ctx, loginData.Login).Times(1).Return(dto.UserIdLoginHashState{UserId: id, State: account_state.Enabled}, nil)
+	repo.EXPECT().TOTPSecret(ctx, id).Times(1).Return(dto.SecretEnabled{Secret: "JBSWY3DPEHPK3PXP", Enabled: true}, nil)
+	repo.EXPECT().SaveLoginChallenge(ctx, gomock.Any()).Times(1).Return(nil)
+	repo.EXPECT().SessionToken(gomock.Any(), gomock.Any()).Times(0)
+	repo.EXPECT().SaveSession(gomock.Any(), gomock.Any()).Times(0)
+
+	challenge, err := s.LoginWithCertificate(ctx, loginData.Login)
+	if len(challenge) != 24 || !errors.Is(err, service.ErrSecondFactorRequired) {
+		t.Fail()
+	}
+}
+
+func TestService_LoginWithCertificateErrDisabledAccount(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24})
+
+	repo.EXPECT().AccountLoginData(ctx, loginData.Login).Times(1).Return(dto.UserIdLoginHashState{UserId: uuid.New(), State: account_state.Disabled}, nil)
+
+	if _, err := s.LoginWithCertificate(ctx, loginData.Login); err != service.ErrNotEnabledAccount {
+		t.Fail()
+	}
+}
+
+func TestService_ClientCredentialsToken(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24, TokenTTL: time.Hour})
+	id := uuid.New()
+	hash, _ := bcrypt.GenerateFromPassword([]byte(loginData.Password), bcrypt.MinCost)
+
+	repo.EXPECT().AccountState(ctx, loginData.Login).Times(1).Return(account_state.State(account_state.Enabled), nil)
+	repo.EXPECT().UserIdAndPasswordHash(ctx, loginData.Login).Times(1).Return(dto.UserIdHash{UserId: id, Hash: string(hash)}, nil)
+	repo.EXPECT().TOTPSecret(ctx, id).Times(1).Return(dto.SecretEnabled{}, joint.ErrEmptyResult)
+	repo.EXPECT().InstanceSecret(ctx, "instance").Times(1).Return("secret", nil)
+	repo.EXPECT().InstancePermissionsNumbersForAccount(ctx, gomock.Any()).Times(1).Return([]int{1}, nil)
+	repo.EXPECT().ConditionalPermissionsForAccount(ctx, gomock.Any()).Times(1).Return(nil, nil)
+	repo.EXPECT().ServiceName(ctx, "instance").Times(1).Return("service", nil)
+	repo.EXPECT().ServicePermissionsNumbersForAccount(ctx, gomock.Any()).Times(1).Return([]int{2}, nil)
+	repo.EXPECT().ServicePermissionEncoding(ctx, "service").Times(1).Return(permission_encoding.List, nil)
+	repo.EXPECT().AccountGrantsExpiration(ctx, gomock.Any()).Times(1).Return(time.Time{}, nil)
+	repo.EXPECT().UpdateAccountLastLogin(gomock.Any(), id).AnyTimes()
+
+	token, err := s.ClientCredentialsToken(ctx, &dto.LoginPasswordInstance{Login: loginData.Login, Password: loginData.Password, Instance: "instance"})
+	if err != nil || len(token.Token) == 0 || token.TTL != time.Hour {
+		t.Fail()
+	}
+}
+
+func TestService_ClientCredentialsTokenErrSecondFactor(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24, TokenTTL: time.Hour})
+	id := uuid.New()
+	hash, _ := bcrypt.GenerateFromPassword([]byte(loginData.Password), bcrypt.MinCost)
+
+	repo.EXPECT().AccountState(ctx, loginData.Login).Times(1).Return(account_state.State(account_state.Enabled), nil)
+	repo.EXPECT().UserIdAndPasswordHash(ctx, loginData.Login).Times(1).Return(dto.UserIdHash{UserId: id, Hash: string(hash)}, nil)
+	repo.EXPECT().TOTPSecret(ctx, id).Times(1).Return(dto.SecretEnabled{Secret: "JBSWY3DPEHPK3PXP", Enabled: true}, nil)
+
+	_, err := s.ClientCredentialsToken(ctx, &dto.LoginPasswordInstance{Login: loginData.Login, Password: loginData.Password, Instance: "instance"})
+	if err != service.ErrSecondFactorRequired {
+		t.Fail()
+	}
+}
+
+func TestService_ClientCredentialsTokenErrUnknownClient(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24, TokenTTL: time.Hour})
+
+	repo.EXPECT().AccountState(ctx, loginData.Login).Times(1).Return(account_state.State(0), joint.ErrEmptyResult)
+
+	_, err := s.ClientCredentialsToken(ctx, &dto.LoginPasswordInstance{Login: loginData.Login, Password: loginData.Password, Instance: "instance"})
+	if err != service.ErrAuthenticationData {
+		t.Fail()
+	}
+}
+
+// createTestToken создаёт JWT-токен с разрешениями 1 и 2 экземпляра "instance" для учетной записи id.
+func createTestToken(t *testing.T, s *Service, repo *mockjoint.MockInterface, id uuid.UUID) string {
+	ctx := context.Background()
+
+	repo.EXPECT().InstanceSecret(ctx, "instance").Times(1).Return("secret", nil)
+	repo.EXPECT().InstancePermissionsNumbersForAccount(ctx, gomock.Any()).Times(1).Return([]int{1}, nil)
+	repo.EXPECT().ConditionalPermissionsForAccount(ctx, gomock.Any()).Times(1).Return(nil, nil)
+	repo.EXPECT().ServiceName(ctx, "instance").Times(1).Return("service", nil)
+	repo.EXPECT().ServicePermissionsNumbersForAccount(ctx, gomock.Any()).Times(1).Return([]int{2}, nil)
+	repo.EXPECT().ServicePermissionEncoding(ctx, "service").Times(1).Return(permission_encoding.List, nil)
+	repo.EXPECT().AccountGrantsExpiration(ctx, gomock.Any()).Times(1).Return(time.Time{}, nil)
+
+	token, err := s.CreateToken(ctx, &dto.UserIdInstanceAddress{UserId: id, Instance: "instance"})
+	if err != nil {
+		t.Fatal()
+	}
+
+	return token
+}
+
+func TestService_IntrospectToken(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24, TokenTTL: time.Hour})
+	id := uuid.New()
+	token := createTestToken(t, s, repo, id)
+
+	repo.EXPECT().InstanceSecret(ctx, "instance").Times(1).Return("secret", nil)
+	repo.EXPECT().IsTokenRevoked(ctx, gomock.Any()).Times(1).Return(false, nil)
+	repo.EXPECT().AccountLoginDataByUserId(ctx, id).Times(1).Return(dto.UserIdLoginHashState{UserId: id, State: account_state.Enabled}, nil)
+	repo.EXPECT().ServiceName(ctx, "instance").Times(1).Return("service", nil)
+	repo.EXPECT().ServiceNumberedPermissions(ctx, "service").Times(1).Return(&[]dto.NameNumber{{Name: "first", Number: 1}, {Name: "second", Number: 2}}, nil)
+
+	result, err := s.IntrospectToken(ctx, token)
+	if err != nil || !result.Active || result.UserId != id || result.Instance != "instance" || len(result.Permissions) != 2 {
+		t.Fail()
+	}
+}
+
+func TestService_IntrospectTokenRevoked(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24, TokenTTL: time.Hour})
+	token := createTestToken(t, s, repo, uuid.New())
+
+	repo.EXPECT().InstanceSecret(ctx, "instance").Times(1).Return("secret", nil)
+	repo.EXPECT().IsTokenRevoked(ctx, gomock.Any()).Times(1).Return(true, nil)
+
+	if result, err := s.IntrospectToken(ctx, token); err != nil || result.Active {
+		t.Fail()
+	}
+}
+
+func TestService_IntrospectTokenWrongSecret(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24, TokenTTL: time.Hour})
+	token := createTestToken(t, s, repo, uuid.New())
+
+	repo.EXPECT().InstanceSecret(ctx, "instance").Times(1).Return("another secret", nil)
+
+	if result, err := s.IntrospectToken(ctx, token); err != nil || result.Active {
+		t.Fail()
+	}
+}
+
+func TestService_RevokeTokenErrNotOwner(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24, TokenTTL: time.Hour})
+	token := createTestToken(t, s, repo, uuid.New())
+	caller := uuid.New()
+
+	repo.EXPECT().InstanceSecret(ctx, "instance").Times(1).Return("secret", nil)
+	repo.EXPECT().AccountHasRole(ctx, gomock.Any()).Times(1).Return(false, nil)
+
+	if s.RevokeToken(ctx, caller, token) != service.ErrNotTokenOwner {
+		t.Fail()
+	}
+}
+
+func TestService_RevokeToken(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24, TokenTTL: time.Hour})
+	id := uuid.New()
+	token := createTestToken(t, s, repo, id)
+
+	repo.EXPECT().InstanceSecret(ctx, "instance").Times(1).Return("secret", nil)
+	repo.EXPECT().RevokeToken(ctx, gomock.Any()).Times(1).Return(nil)
+
+	if s.RevokeToken(ctx, id, token) != nil {
+		t.Fail()
+	}
+}
+
+// createOIDCService создаёт сервис с включенным OpenID Connect и сгенерированным ключом подписи.
+func createOIDCService(t *testing.T, metrics *mockservice.MockMetricsInterface, repo *mockjoint.MockInterface) *Service {
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24, TokenTTL: time.Hour, OIDCIssuer: "https://secure.example"})
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.oidcKey, s.oidcKeyId = key, signingKeyId(&key.PublicKey)
+
+	return s
+}
+
+// codeChallenge возвращает code_challenge по методу S256 для переданного code_verifier.
+func codeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+var (
+	oidcClient   = dto.OIDCClient{ClientId: "client", Name: "Client", RedirectURIs: []string{"https://client.example/callback"}}
+	codeVerifier = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+)
+
+func TestService_Authorize(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := createOIDCService(t, metrics, repo)
+	id := uuid.New()
+
+	repo.EXPECT().OIDCClient(ctx, oidcClient.ClientId).Times(1).Return(oidcClient, nil)
+	repo.EXPECT().SaveAuthorizationCode(ctx, gomock.Any()).Times(1).Return(nil)
+
+	code, err := s.Authorize(ctx, &dto.AuthorizationRequest{
+		UserId:              id,
+		ClientId:            oidcClient.ClientId,
+		RedirectURI:         oidcClient.RedirectURIs[0],
+		ResponseType:        "code",
+		Scope:               "openid",
+		CodeChallenge:       codeChallenge(codeVerifier),
+		CodeChallengeMethod: "S256",
+	})
+	if err != nil || len(code) == 0 {
+		t.Fail()
+	}
+}
+
+func TestService_AuthorizeErrUnregisteredRedirectURI(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := createOIDCService(t, metrics, repo)
+
+	repo.EXPECT().OIDCClient(ctx, oidcClient.ClientId).Times(1).Return(oidcClient, nil)
+
+	_, err := s.Authorize(ctx, &dto.AuthorizationRequest{
+		ClientId:            oidcClient.ClientId,
+		RedirectURI:         "https://evil.example/callback",
+		ResponseType:        "code",
+		Scope:               "openid",
+		CodeChallenge:       codeChallenge(codeVerifier),
+		CodeChallengeMethod: "S256",
+	})
+	if err != service.ErrInvalidOIDCClient {
+		t.Fail()
+	}
+}
+
+func TestService_AuthorizeErrWithoutPKCE(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := createOIDCService(t, metrics, repo)
+
+	repo.EXPECT().OIDCClient(ctx, oidcClient.ClientId).Times(1).Return(oidcClient, nil)
+
+	_, err := s.Authorize(ctx, &dto.AuthorizationRequest{
+		ClientId:     oidcClient.ClientId,
+		RedirectURI:  oidcClient.RedirectURIs[0],
+		ResponseType: "code",
+		Scope:        "openid",
+	})
+	if err != service.ErrInvalidAuthorizationRequest {
+		t.Fail()
+	}
+}
+
+func TestService_ExchangeAuthorizationCode(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := createOIDCService(t, metrics, repo)
+	id := uuid.New()
+
+	repo.EXPECT().OIDCClient(ctx, oidcClient.ClientId).Times(1).Return(oidcClient, nil)
+	repo.EXPECT().AuthorizationCode(ctx, "code").Times(1).Return(dto.AuthorizationCode{
+		Code:          "code",
+		UserId:        id,
+		ClientId:      oidcClient.ClientId,
+		RedirectURI:   oidcClient.RedirectURIs[0],
+		CodeChallenge: codeChallenge(codeVerifier),
+		Nonce:         "nonce",
+		Scope:         "openid",
+	}, nil)
+	repo.EXPECT().AccountLoginDataByUserId(ctx, id).Times(1).Return(dto.UserIdLoginHashState{UserId: id, Login: loginData.Login, State: account_state.Enabled}, nil)
+	repo.EXPECT().SessionToken(gomock.Any(), gomock.Any()).Times(0)
+	repo.EXPECT().SaveSession(gomock.Any(), gomock.Any()).Times(0)
+	repo.EXPECT().UpdateAccountLastLogin(gomock.Any(), gomock.Any()).AnyTimes()
+
+	tokens, err := s.ExchangeAuthorizationCode(ctx, &dto.AuthorizationCodeExchange{
+		Code:         "code",
+		ClientId:     oidcClient.ClientId,
+		RedirectURI:  oidcClient.RedirectURIs[0],
+		CodeVerifier: codeVerifier,
+	})
+	if err != nil {
+		t.Fatal()
+	}
+
+	if userId, err := s.UserIdFromAccessToken(tokens.AccessToken); err != nil || userId != id {
+		t.Fatal()
+	}
+
+	if _, err = s.UserIdFromAccessToken(tokens.IDToken); !errors.Is(err, service.ErrInvalidAccessToken) {
+		t.Fatal()
+	}
+
+	parsed, err := jwt.Parse(tokens.IDToken, func(*jwt.Token) (any, error) {
+		return &s.oidcKey.PublicKey, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg()}), jwt.WithAudience(oidcClient.ClientId),
+		jwt.WithIssuer("https://secure.example"))
+	if err != nil || parsed.Header["kid"] != s.oidcKeyId {
+		t.Fatal(err)
+	}
+
+	claims := parsed.Claims.(jwt.MapClaims)
+	if claims["sub"] != id.String() || claims["preferred_username"] != string(loginData.Login) || claims["nonce"] != "nonce" {
+		t.Fail()
+	}
+}
+
+func TestService_ExchangeAuthorizationCodeErrWrongVerifier(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := createOIDCService(t, metrics, repo)
+
+	repo.EXPECT().OIDCClient(ctx, oidcClient.ClientId).Times(1).Return(oidcClient, nil)
+	repo.EXPECT().AuthorizationCode(ctx, "code").Times(1).Return(dto.AuthorizationCode{
+		Code:          "code",
+		UserId:        uuid.New(),
+		ClientId:      oidcClient.ClientId,
+		RedirectURI:   oidcClient.RedirectURIs[0],
+		CodeChallenge: codeChallenge(codeVerifier),
+	}, nil)
+
+	_, err := s.ExchangeAuthorizationCode(ctx, &dto.AuthorizationCodeExchange{
+		Code:         "code",
+		ClientId:     oidcClient.ClientId,
+		RedirectURI:  oidcClient.RedirectURIs[0],
+		CodeVerifier: strings.Repeat("a", 43),
+	})
+	if err != service.ErrInvalidGrant {
+		t.Fail()
+	}
+}
+
+var permissionCheck = dto.UserIdInstanceNumber{UserId: uuid.New(), Instance: "store-1", Number: 7}
+
+func TestService_CheckPermissionAllowedByInstance(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24})
+
+	repo.EXPECT().AccountLoginDataByUserId(ctx, permissionCheck.UserId).Times(1).Return(dto.UserIdLoginHashState{State: account_state.Enabled}, nil)
+	repo.EXPECT().ServiceName(ctx, permissionCheck.Instance).Times(1).Return("store", nil)
+	repo.EXPECT().InstancePermissionsNumbersForAccount(ctx, gomock.Any()).Times(1).Return([]int{7}, nil)
+	repo.EXPECT().ConditionalPermissionsForAccount(ctx, gomock.Any()).Times(1).Return(nil, nil)
+	repo.EXPECT().ServicePermissionsNumbersForAccount(ctx, gomock.Any()).Times(1).Return(nil, joint.ErrEmptyResult)
+	repo.EXPECT().ServicePermissionsSourcesForAccount(ctx, gomock.Any()).Times(0)
+
+	decision, err := s.CheckPermission(ctx, permissionCheck.UserId, "", &permissionCheck)
+	if err != nil || !decision.Allowed || len(decision.Reasons) != 1 || decision.Reasons[0] != grant_source.Instance {
+		t.Fail()
+	}
+}
+
+func TestService_CheckPermissionAllowedByRoles(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24})
+	store := dto.UserIdService{UserId: permissionCheck.UserId, Service: "store"}
+
+	repo.EXPECT().AccountLoginDataByUserId(ctx, permissionCheck.UserId).Times(1).Return(dto.UserIdLoginHashState{State: account_state.Enabled}, nil)
+	repo.EXPECT().ServiceName(ctx, permissionCheck.Instance).Times(1).Return("store", nil)
+	repo.EXPECT().InstancePermissionsNumbersForAccount(ctx, gomock.Any()).Times(1).Return(nil, joint.ErrEmptyResult)
+	repo.EXPECT().ConditionalPermissionsForAccount(ctx, gomock.Any()).Times(1).Return(nil, nil)
+	repo.EXPECT().ServicePermissionsNumbersForAccount(ctx, &store).Times(1).Return([]int{3, 7}, nil)
+	repo.EXPECT().ServicePermissionsSourcesForAccount(ctx, &store).Times(1).Return([]dto.NumberSource{
+		{Number: 3, Source: grant_source.Group},
+		{Number: 7, Source: grant_source.Role},
+		{Number: 7, Source: grant_source.GroupRole},
+	}, nil)
+
+	decision, err := s.CheckPermission(ctx, permissionCheck.UserId, "", &permissionCheck)
+	if err != nil || !decision.Allowed || len(decision.Reasons) != 2 ||
+		decision.Reasons[0] != grant_source.Role || decision.Reasons[1] != grant_source.GroupRole {
+		t.Fail()
+	}
+}
+
+func TestService_CheckPermissionAllowedByCondition(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24})
+
+	repo.EXPECT().AccountLoginDataByUserId(ctx, permissionCheck.UserId).Times(1).Return(dto.UserIdLoginHashState{State: account_state.Enabled}, nil)
+	repo.EXPECT().ServiceName(ctx, permissionCheck.Instance).Times(1).Return("store", nil)
+	repo.EXPECT().InstancePermissionsNumbersForAccount(ctx, gomock.Any()).Times(1).Return(nil, joint.ErrEmptyResult)
+	repo.EXPECT().ConditionalPermissionsForAccount(ctx, gomock.Any()).Times(1).Return([]dto.NumberCondition{
+		{Number: 7, Condition: `ip_in("10.0.0.0/8")`},
+	}, nil)
+	repo.EXPECT().InstanceAttributes(ctx, permissionCheck.Instance).Times(1).Return(map[string]string{}, nil)
+	repo.EXPECT().ServicePermissionsNumbersForAccount(ctx, gomock.Any()).Times(1).Return(nil, joint.ErrEmptyResult)
+
+	decision, err := s.CheckPermission(ctx, permissionCheck.UserId, "10.1.2.3:5000", &permissionCheck)
+	if err != nil || !decision.Allowed || len(decision.Reasons) != 1 || decision.Reasons[0] != grant_source.Condition {
+		t.Fail()
+	}
+}
+
+func TestService_CheckPermissionDeniedByCondition(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24})
+
+	repo.EXPECT().AccountLoginDataByUserId(ctx, permissionCheck.UserId).Times(1).Return(dto.UserIdLoginHashState{State: account_state.Enabled}, nil)
+	repo.EXPECT().ServiceName(ctx, permissionCheck.Instance).Times(1).Return("store", nil)
+	repo.EXPECT().InstancePermissionsNumbersForAccount(ctx, gomock.Any()).Times(1).Return(nil, joint.ErrEmptyResult)
+	repo.EXPECT().ConditionalPermissionsForAccount(ctx, gomock.Any()).Times(1).Return([]dto.NumberCondition{
+		{Number: 7, Condition: `ip_in("10.0.0.0/8")`},
+	}, nil)
+	repo.EXPECT().InstanceAttributes(ctx, permissionCheck.Instance).Times(1).Return(map[string]string{}, nil)
+	repo.EXPECT().ServicePermissionsNumbersForAccount(ctx, gomock.Any()).Times(1).Return(nil, joint.ErrEmptyResult)
+
+	decision, err := s.CheckPermission(ctx, permissionCheck.UserId, "192.168.1.2:5000", &permissionCheck)
+	if err != nil || decision.Allowed || len(decision.Reasons) != 0 {
+		t.Fail()
+	}
+}
+
+func TestService_CheckPermissionDenied(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24})
+
+	repo.EXPECT().AccountLoginDataByUserId(ctx, permissionCheck.UserId).Times(1).Return(dto.UserIdLoginHashState{State: account_state.Enabled}, nil)
+	repo.EXPECT().ServiceName(ctx, permissionCheck.Instance).Times(1).Return("store", nil)
+	repo.EXPECT().InstancePermissionsNumbersForAccount(ctx, gomock.Any()).Times(1).Return([]int{1}, nil)
+	repo.EXPECT().ConditionalPermissionsForAccount(ctx, gomock.Any()).Times(1).Return(nil, nil)
+	repo.EXPECT().ServicePermissionsNumbersForAccount(ctx, gomock.Any()).Times(1).Return([]int{2}, nil)
+	repo.EXPECT().ServicePermissionsSourcesForAccount(ctx, gomock.Any()).Times(0)
+
+	decision, err := s.CheckPermission(ctx, permissionCheck.UserId, "", &permissionCheck)
+	if err != nil || decision.Allowed || len(decision.Reasons) != 0 {
+		t.Fail()
+	}
+}
+
+func TestService_CheckPermissionDisabledAccount(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24})
+
+	repo.EXPECT().AccountLoginDataByUserId(ctx, permissionCheck.UserId).Times(1).Return(dto.UserIdLoginHashState{State: account_state.Disabled}, nil)
+	repo.EXPECT().ServiceName(ctx, gomock.Any()).Times(0)
+
+	decision, err := s.CheckPermission(ctx, permissionCheck.UserId, "", &permissionCheck)
+	if err != nil || decision.Allowed {
+		t.Fail()
+	}
+}
+
+func TestService_CheckPermissions(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24})
+
+	other := permissionCheck
+	other.Number = 8
+
+	repo.EXPECT().AccountLoginDataByUserId(ctx, permissionCheck.UserId).Times(1).Return(dto.UserIdLoginHashState{State: account_state.Enabled}, nil)
+	repo.EXPECT().ServiceName(ctx, permissionCheck.Instance).Times(1).Return("store", nil)
+	repo.EXPECT().InstancePermissionsNumbersForAccount(ctx, gomock.Any()).Times(1).Return([]int{8}, nil)
+	repo.EXPECT().ConditionalPermissionsForAccount(ctx, gomock.Any()).Times(1).Return(nil, nil)
+	repo.EXPECT().ServicePermissionsNumbersForAccount(ctx, gomock.Any()).Times(1).Return([]int{7}, nil)
+	repo.EXPECT().ServicePermissionsSourcesForAccount(ctx, gomock.Any()).Times(1).Return([]dto.NumberSource{{Number: 7, Source: grant_source.Group}}, nil)
+
+	decisions, err := s.CheckPermissions(ctx, permissionCheck.UserId, "", []dto.UserIdInstanceNumber{permissionCheck, other, permissionCheck})
+	if err != nil || len(decisions) != 3 || !decisions[0].Allowed || !decisions[1].Allowed || !decisions[2].Allowed ||
+		decisions[1].Reasons[0] != grant_source.Instance || decisions[2].Reasons[0] != grant_source.Group {
+		t.Fail()
+	}
+}
+
+func TestService_CheckPermissionErrForeignAccount(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24})
+	caller := uuid.New()
+
+	repo.EXPECT().AccountHasRole(ctx, gomock.Any()).Times(1).Return(false, nil)
+	repo.EXPECT().AccountDetails(ctx, caller).Times(1).Return(dto.AccountDetails{UserId: caller, Type: account_type.Human}, nil)
+	repo.EXPECT().AccountLoginDataByUserId(gomock.Any(), gomock.Any()).Times(0)
+
+	if _, err := s.CheckPermission(ctx, caller, "", &permissionCheck); !errors.Is(err, service.ErrForeignPermissionCheck) {
+		t.Fail()
+	}
+}
+
+func TestService_CheckPermissionByServiceAccount(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24})
+	caller := uuid.New()
+
+	repo.EXPECT().AccountHasRole(ctx, gomock.Any()).Times(1).Return(false, nil)
+	repo.EXPECT().AccountDetails(ctx, caller).Times(1).Return(dto.AccountDetails{UserId: caller, Type: account_type.Service}, nil)
+	repo.EXPECT().AccountLoginDataByUserId(ctx, permissionCheck.UserId).Times(1).Return(dto.UserIdLoginHashState{State: account_state.Disabled}, nil)
+
+	if decision, err := s.CheckPermission(ctx, caller, "", &permissionCheck); err != nil || decision.Allowed {
+		t.Fail()
+	}
+}
+
+func TestService_ExplainPermissions(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24})
+
+	userId := uuid.New()
+	repo.EXPECT().ServiceName(ctx, "store-1").Times(1).Return("store", nil)
+	repo.EXPECT().PermissionsGrantPathsForAccount(ctx, &dto.UserIdServiceInstance{UserId: userId, Service: "store", Instance: "store-1"}).
+		Times(1).Return([]dto.NumberNameGrantPath{
+		{Number: 3, Name: "продавать", GrantPath: dto.GrantPath{Source: grant_source.Instance, Instance: "store-1"}},
+		{Number: 7, Name: "возвращать", GrantPath: dto.GrantPath{Source: grant_source.Role, Role: "Продавец"}},
+		{Number: 7, Name: "возвращать", GrantPath: dto.GrantPath{Source: grant_source.GroupRole, Group: "Персонал магазина", Role: "Продавец"}},
+		{Number: 7, Name: "возвращать", GrantPath: dto.GrantPath{Source: grant_source.Role, Role: "Менеджер", InheritedFrom: []string{"Продавец"}}},
+		{Number: 7, Name: "возвращать", GrantPath: dto.GrantPath{Source: grant_source.Group, Group: "Кассиры", ParentGroups: []string{"Персонал магазина"}}},
+		{Number: 7, Name: "возвращать", GrantPath: dto.GrantPath{Source: grant_source.GlobalGroupRole, GlobalGroup: "Сервис заказа", Role: "Продавец"}},
+		{Number: 7, Name: "возвращать", GrantPath: dto.GrantPath{Source: grant_source.InstanceRole, Instance: "store-1", Role: "Продавец"}},
+		{Number: 7, Name: "возвращать", GrantPath: dto.GrantPath{Source: grant_source.InstanceGroupRole, Instance: "store-1", Group: "Кассиры", Role: "Продавец"}},
+		{Number: 7, Name: "возвращать", GrantPath: dto.GrantPath{Source: grant_source.Selector, Instance: "store-1", Selector: "city=Донецк"}},
+	}, nil)
+
+	permissions, err := s.ExplainPermissions(ctx, &dto.UserIdServiceInstance{UserId: userId, Instance: "store-1"})
+	if err != nil || len(permissions) != 2 || len(permissions[0].Paths) != 1 || len(permissions[1].Paths) != 8 {
+		t.Fatal()
+	}
+
+	if permissions[0].Paths[0].Path != `instance "store-1" → permission 3` ||
+		permissions[1].Paths[0].Path != `role "Продавец" → permission 7` ||
+		permissions[1].Paths[1].Path != `group "Персонал магазина" → role "Продавец" → permission 7` ||
+		permissions[1].Paths[2].Path != `role "Менеджер" → role "Продавец" → permission 7` ||
+		permissions[1].Paths[3].Path != `group "Кассиры" → group "Персонал магазина" → permission 7` ||
+		permissions[1].Paths[4].Path != `global group "Сервис заказа" → role "Продавец" → permission 7` ||
+		permissions[1].Paths[5].Path != `instance "store-1" → role "Продавец" → permission 7` ||
+		permissions[1].Paths[6].Path != `instance "store-1" → group "Кассиры" → role "Продавец" → permission 7` ||
+		permissions[1].Paths[7].Path != `instance "store-1" → selector "city=Донецк" → permission 7` {
+		t.Fail()
+	}
+}
+
+func TestService_ExplainPermissionsCondition(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24})
+
+	userId := uuid.New()
+	repo.EXPECT().PermissionsGrantPathsForAccount(ctx, gomock.Any()).Times(1).Return([]dto.NumberNameGrantPath{
+		{Number: 7, Name: "возвращать", GrantPath: dto.GrantPath{Source: grant_source.Role, Role: "Продавец",
+			Condition: `ip_in("10.0.0.0/8")`}},
+		{Number: 7, Name: "возвращать", GrantPath: dto.GrantPath{Source: grant_source.Group, Group: "Кассиры",
+			Condition: `ip_in("192.168.0.0/16")`}},
+	}, nil)
+	repo.EXPECT().InstanceAttributes(ctx, gomock.Any()).Times(0)
+
+	permissions, err := s.ExplainPermissions(ctx, &dto.UserIdServiceInstance{UserId: userId, Service: "store", Address: "10.0.0.1"})
+	if err != nil || len(permissions) != 1 || len(permissions[0].Paths) != 2 {
+		t.Fatal()
+	}
+
+	first, second := permissions[0].Paths[0], permissions[0].Paths[1]
+	if first.ConditionMet == nil || !*first.ConditionMet || second.ConditionMet == nil || *second.ConditionMet ||
+		first.Path != `role "Продавец" → permission 7 when ip_in("10.0.0.0/8")` {
+		t.Fail()
+	}
+}
+
+func TestService_ExplainPermissionsErrUnknownInstance(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24})
+
+	repo.EXPECT().ServiceName(ctx, "unknown").Times(1).Return("", joint.ErrEmptyResult)
+
+	if _, err := s.ExplainPermissions(ctx, &dto.UserIdServiceInstance{UserId: uuid.New(), Instance: "unknown"}); !errors.Is(err, service.ErrEmptyResult) {
+		t.Fail()
+	}
+}
+
+func TestService_ServicesPagination(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24})
+
+	repo.EXPECT().Services(ctx, &dto.ListQuery{Filter: "st", Limit: 3}).Times(1).Return([]dto.NameDescription{
+		{Name: "stock"}, {Name: "store"}, {Name: "studio"},
+	}, nil)
+
+	first, err := s.Services(ctx, &dto.PageRequest{Filter: "st", Limit: 2})
+	if err != nil || len(first.Items) != 2 || first.Items[1].Name != "store" || len(first.NextCursor) == 0 {
+		t.Fatal()
+	}
+
+	repo.EXPECT().Services(ctx, &dto.ListQuery{Filter: "st", After: "store", Limit: 3}).Times(1).Return([]dto.NameDescription{
+		{Name: "studio"},
+	}, nil)
+
+	second, err := s.Services(ctx, &dto.PageRequest{Filter: "st", Limit: 2, Cursor: first.NextCursor})
+	if err != nil || len(second.Items) != 1 || len(second.NextCursor) != 0 {
+		t.Fail()
+	}
+}
+
+func TestService_AccountsPageSizeLimits(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24})
+
+	repo.EXPECT().Accounts(ctx, &dto.ListQuery{Limit: defaultPageSize + 1, Descending: true}).Times(1).Return(nil, nil)
+	repo.EXPECT().Accounts(ctx, &dto.ListQuery{Limit: maxPageSize + 1}).Times(1).Return(nil, nil)
+
+	if _, err := s.Accounts(ctx, &dto.PageRequest{Descending: true}); err != nil {
+		t.Fatal()
+	}
+
+	if _, err := s.Accounts(ctx, &dto.PageRequest{Limit: maxPageSize * 2}); err != nil {
+		t.Fail()
+	}
+}
+
+func TestService_AccountsFilterByTypeAndOwnerTeam(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24})
+
+	repo.EXPECT().Accounts(ctx, &dto.ListQuery{Limit: defaultPageSize + 1, Type: account_type.Service,
+		OwnerTeam: "logistics"}).Times(1).Return(nil, nil)
+
+	if _, err := s.Accounts(ctx, &dto.PageRequest{Type: account_type.Service, OwnerTeam: "logistics"}); err != nil {
+		t.Fatal()
+	}
+
+	if _, err := s.Accounts(ctx, &dto.PageRequest{Type: "robot"}); !errors.Is(err, service.ErrInvalidQueryParameters) {
+		t.Fail()
+	}
+}
+
+func TestService_ListErrInvalidQueryParameters(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24})
+
+	if _, err := s.Services(ctx, &dto.PageRequest{Cursor: "not base64!"}); !errors.Is(err, service.ErrInvalidQueryParameters) {
+		t.Fatal()
+	}
+
+	if _, err := s.Roles(ctx, &dto.PageRequest{}); !errors.Is(err, service.ErrInvalidQueryParameters) {
+		t.Fatal()
+	}
+
+	if _, err := s.Groups(ctx, &dto.PageRequest{Service: "store", Limit: -1}); !errors.Is(err, service.ErrInvalidQueryParameters) {
+		t.Fail()
+	}
+}
+
+var rbacAccount = dto.AccountDetails{
+	UserId: uuid.New(),
+	Login:  "keeper",
+	State:  account_state.Enabled,
+	Roles:  []dto.NameService{{Name: "reader", Service: "store"}},
+}
+
+// expectRBACExport ожидает считывание конфигурации из одного сервиса store с экземпляром store-1, разрешением read,
+// ролью reader, глобальной группы staff с этой ролью и одной учетной записью.
+func expectRBACExport(ctx context.Context, repo *mockjoint.MockInterface) {
+	repo.EXPECT().Services(ctx, &dto.ListQuery{Limit: maxPageSize}).Times(1).Return([]dto.NameDescription{
+		{Name: "store", Description: "Store"},
+	}, nil)
+	repo.EXPECT().ServiceDetails(ctx, "store").Times(1).Return(dto.ServiceDetails{
+		Name:               "store",
+		Description:        "Store",
+		PermissionEncoding: permission_encoding.List,
+		Instances:          []string{"store-1"},
+		Permissions:        []dto.NameNumberDescriptionDeprecated{{Name: "read", Number: 1, Description: "Read"}},
+		RetiredNumbers:     []int{2},
+		Roles:              []string{"reader"},
+		Groups:             []string{},
+	}, nil)
+	repo.EXPECT().RoleDetails(ctx, &dto.NameService{Name: "reader", Service: "store"}).Times(1).Return(dto.RoleDetails{
+		Name: "reader", Service: "store", Permissions: []dto.NameNumber{{Name: "read", Number: 1}},
+	}, nil)
+	repo.EXPECT().GlobalGroups(ctx).Times(1).Return([]dto.RBACGlobalGroup{
+		{Name: "staff", Roles: []dto.NameService{{Name: "reader", Service: "store"}}},
+	}, nil)
+	repo.EXPECT().Accounts(ctx, &dto.ListQuery{Limit: maxPageSize}).Times(1).Return([]dto.UserIdLoginState{
+		{UserId: rbacAccount.UserId, Login: rbacAccount.Login, State: rbacAccount.State},
+	}, nil)
+	repo.EXPECT().AccountDetails(ctx, rbacAccount.UserId).Times(1).Return(rbacAccount, nil)
+}
+
+func TestService_ExportRBAC(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24})
+
+	expectRBACExport(ctx, repo)
+
+	document, err := s.ExportRBAC(ctx)
+	if err != nil || document.Version != RBACDocumentVersion || len(document.Services) != 1 || len(document.Accounts) != 1 {
+		t.Fatal()
+	}
+
+	if roles := document.Services[0].Roles; len(roles) != 1 || len(roles[0].Permissions) != 1 || roles[0].Permissions[0] != "read" {
+		t.Fail()
+	}
+}
+
+func TestService_ImportRBACDryRun(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24})
+
+	expectRBACExport(ctx, repo)
+
+	document := dto.RBACDocument{Version: RBACDocumentVersion, Services: []dto.RBACService{{
+		Name:        "store",
+		Description: "Watch store",
+		Instances:   []string{"store-1", "store-2"},
+		Permissions: []dto.NameNumberDescriptionDeprecated{{Name: "read", Number: 1, Description: "Read"}},
+		Roles:       []dto.RBACRole{{Name: "reader", Permissions: []string{"read"}}},
+	}}}
+
+	result, err := s.ImportRBAC(ctx, &document, true)
+	if err != nil || result.Applied || len(result.Conflicts) != 0 {
+		t.Fatal()
+	}
+
+	expected := []dto.RBACChange{
+		{Action: actionUpdate, Kind: "service", Target: "store"},
+		{Action: actionCreate, Kind: "instance", Target: "store/store-2"},
+	}
+	if len(result.Changes) != len(expected) || result.Changes[0] != expected[0] || result.Changes[1] != expected[1] {
+		t.Fail()
+	}
+}
+
+func TestService_ImportRBAC(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24})
+
+	expectRBACExport(ctx, repo)
+
+	document := dto.RBACDocument{
+		Version:  RBACDocumentVersion,
+		Services: []dto.RBACService{{Name: "store", Description: "Store", Instances: []string{"store-2"}}},
+		Accounts: []dto.AccountDetails{{
+			UserId:              uuid.New(),
+			Login:               "clerk",
+			State:               account_state.Enabled,
+			InstancePermissions: []dto.InstancePermission{{Instance: "store-2", Permission: "read"}},
+		}},
+	}
+
+	repo.EXPECT().ImportRBAC(ctx, &document, gomock.Any(), gomock.Nil()).Times(1).DoAndReturn(
+		func(_ context.Context, _ *dto.RBACDocument, secrets map[string]string, _ *dto.RBACRemovals) error {
+			if len(secrets) != 1 || len(secrets["store-2"]) != importedSecretLength {
+				t.Fail()
+			}
+			return nil
+		})
+
+	result, err := s.ImportRBAC(ctx, &document, false)
+	if err != nil || !result.Applied || len(result.Changes) != 3 {
+		t.Fail()
+	}
+}
+
+func TestService_ImportRBACErrConflict(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24})
+
+	expectRBACExport(ctx, repo)
+
+	document := dto.RBACDocument{
+		Version: RBACDocumentVersion,
+		Services: []dto.RBACService{{
+			Name:        "store",
+			Description: "Store",
+			Permissions: []dto.NameNumberDescriptionDeprecated{{Name: "read", Number: 2}, {Name: "write", Number: 1}},
+		}},
+		Accounts: []dto.AccountDetails{{UserId: rbacAccount.UserId, Login: "renamed", State: account_state.Enabled}},
+	}
+
+	result, err := s.ImportRBAC(ctx, &document, false)
+	if !errors.Is(err, service.ErrRBACConflict) || result.Applied || len(result.Conflicts) != 3 {
+		t.Fail()
+	}
+}
+
+func TestService_ImportRBACGlobalGroups(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24})
+
+	expectRBACExport(ctx, repo)
+
+	document := dto.RBACDocument{
+		Version: RBACDocumentVersion,
+		GlobalGroups: []dto.RBACGlobalGroup{
+			{Name: "staff", Roles: []dto.NameService{{Name: "reader", Service: "store"}}},
+			{Name: "sellers", Description: "Sellers", Roles: []dto.NameService{{Name: "reader", Service: "store"}}},
+		},
+		Accounts: []dto.AccountDetails{{
+			UserId:       rbacAccount.UserId,
+			Login:        rbacAccount.Login,
+			State:        rbacAccount.State,
+			GlobalGroups: []string{"sellers", "cashiers"},
+		}},
+	}
+
+	result, err := s.ImportRBAC(ctx, &document, true)
+	if !errors.Is(err, service.ErrRBACConflict) || len(result.Conflicts) != 1 {
+		t.Fatal()
+	}
+
+	expected := []dto.RBACChange{
+		{Action: actionCreate, Kind: "global_group", Target: "sellers"},
+		{Action: actionAssign, Kind: "global_group_role", Target: "sellers → store/reader"},
+		{Action: actionAssign, Kind: "account_global_group", Target: rbacAccount.UserId.String() + " → sellers"},
+	}
+	if len(result.Changes) != len(expected) {
+		t.Fatal()
+	}
+	for i := range expected {
+		if result.Changes[i] != expected[i] {
+			t.Fail()
+		}
+	}
+}
+
+func TestService_ImportRBACInstanceRolesAndGroups(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24})
+
+	expectRBACExport(ctx, repo)
+
+	document := dto.RBACDocument{
+		Version: RBACDocumentVersion,
+		Accounts: []dto.AccountDetails{{
+			UserId:         rbacAccount.UserId,
+			Login:          rbacAccount.Login,
+			State:          rbacAccount.State,
+			InstanceRoles:  []dto.NameInstance{{Name: "reader", Instance: "store-1"}},
+			InstanceGroups: []dto.NameInstance{{Name: "readers", Instance: "store-1"}, {Name: "readers", Instance: "store-9"}},
+		}},
+	}
+
+	result, err := s.ImportRBAC(ctx, &document, true)
+	if !errors.Is(err, service.ErrRBACConflict) || len(result.Conflicts) != 2 {
+		t.Fatal()
+	}
+
+	expected := dto.RBACChange{
+		Action: actionAssign,
+		Kind:   "account_instance_role",
+		Target: rbacAccount.UserId.String() + " → store-1/reader",
+	}
+	if len(result.Changes) != 1 || result.Changes[0] != expected {
+		t.Fail()
+	}
+}
+
+func TestService_ImportRBACSelectorPermissions(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24})
+
+	expectRBACExport(ctx, repo)
+
+	document := dto.RBACDocument{
+		Version: RBACDocumentVersion,
+		Accounts: []dto.AccountDetails{{
+			UserId: rbacAccount.UserId,
+			Login:  rbacAccount.Login,
+			State:  rbacAccount.State,
+			SelectorPermissions: []dto.SelectorPermission{
+				{Permission: "read", Service: "store", Selector: "tier=gold, city=Донецк"},
+				{Permission: "read", Service: "store", Selector: "city"},
+			},
+		}},
+	}
+
+	result, err := s.ImportRBAC(ctx, &document, true)
+	if !errors.Is(err, service.ErrRBACConflict) || len(result.Conflicts) != 1 {
+		t.Fatal()
+	}
+
+	expected := dto.RBACChange{
+		Action: actionAssign,
+		Kind:   "account_selector_permission",
+		Target: rbacAccount.UserId.String() + " → store/read [city=Донецк,tier=gold]",
+	}
+	if len(result.Changes) != 1 || result.Changes[0] != expected {
+		t.Fail()
+	}
+}
+
+func TestService_ImportRBACErrInvalidDocument(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24})
+
+	if _, err := s.ImportRBAC(ctx, &dto.RBACDocument{Version: RBACDocumentVersion + 1}, true); !errors.Is(err, service.ErrInvalidRBACDocument) {
+		t.Fail()
+	}
+}
+
+func TestService_ReconcileRBACPrune(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24})
+
+	expectRBACExport(ctx, repo)
+
+	document := dto.RBACDocument{
+		Version: RBACDocumentVersion,
+		Services: []dto.RBACService{{
+			Name:        "store",
+			Description: "Store",
+			Instances:   []string{"store-1"},
+			Permissions: []dto.NameNumberDescriptionDeprecated{{Name: "write", Number: 3}},
+			Roles:       []dto.RBACRole{{Name: "reader"}},
+		}},
+		Accounts: []dto.AccountDetails{{UserId: rbacAccount.UserId, Login: rbacAccount.Login, State: rbacAccount.State}},
+	}
+
+	removals := dto.RBACRemovals{
+		Permissions:  []dto.NameService{{Name: "read", Service: "store"}},
+		AccountRoles: []dto.UserIdRoleService{{UserId: rbacAccount.UserId, Role: "reader", Service: "store"}},
+		GlobalGroups: []string{"staff"},
+	}
+	repo.EXPECT().ImportRBAC(ctx, &document, map[string]string{}, &removals).Times(1).Return(nil)
+
+	result, err := s.ReconcileRBAC(ctx, &document, true)
+	if err != nil || !result.Applied {
+		t.Fatal()
+	}
+
+	expected := []dto.RBACChange{
+		{Action: actionDelete, Kind: "permission", Target: "store/read"},
+		{Action: actionDelete, Kind: "global_group", Target: "staff"},
+		{Action: actionUnassign, Kind: "account_role", Target: rbacAccount.UserId.String() + " → store/reader"},
+		{Action: actionCreate, Kind: "permission", Target: "store/write"},
+	}
+	if len(result.Changes) != len(expected) {
+		t.Fatal()
+	}
+	for i := range expected {
+		if result.Changes[i] != expected[i] {
+			t.Fail()
+		}
+	}
+}
+
+func TestService_ReconcileRBACErrReusesRetiredNumber(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24})
+
+	expectRBACExport(ctx, repo)
+
+	document := dto.RBACDocument{Version: RBACDocumentVersion, Services: []dto.RBACService{{
+		Name:        "store",
+		Description: "Store",
+		Instances:   []string{"store-1"},
+		Permissions: []dto.NameNumberDescriptionDeprecated{{Name: "write", Number: 1}, {Name: "delete", Number: 2}},
+		Roles:       []dto.RBACRole{{Name: "reader"}},
+	}}}
+
+	result, err := s.ReconcileRBAC(ctx, &document, true)
+	if !errors.Is(err, service.ErrRBACConflict) || result.Applied || len(result.Conflicts) != 2 {
+		t.Fail()
+	}
+}
+
+func TestService_ImportRBACDeprecatePermission(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24})
+
+	expectRBACExport(ctx, repo)
+
+	document := dto.RBACDocument{Version: RBACDocumentVersion, Services: []dto.RBACService{{
+		Name:           "store",
+		Description:    "Store",
+		Permissions:    []dto.NameNumberDescriptionDeprecated{{Name: "read", Number: 1, Description: "Read", Deprecated: true}},
+		RetiredNumbers: []int{2, 4},
+		Roles:          []dto.RBACRole{{Name: "reader", Permissions: []string{"read"}}},
+	}}}
+
+	result, err := s.ImportRBAC(ctx, &document, true)
+	if err != nil || len(result.Conflicts) != 0 {
+		t.Fatal()
+	}
+
+	expected := []dto.RBACChange{
+		{Action: actionDeprecate, Kind: "permission", Target: "store/read"},
+		{Action: actionRetire, Kind: "permission_number", Target: "store#4"},
+	}
+	if len(result.Changes) != len(expected) || result.Changes[0] != expected[0] || result.Changes[1] != expected[1] {
+		t.Fatal()
+	}
+
+	expectRBACExport(ctx, repo)
+
+	document.Services[0].Groups = []dto.RBACGroup{{Name: "readers", Permissions: []string{"read"}}}
+	if _, err = s.ImportRBAC(ctx, &document, true); !errors.Is(err, service.ErrRBACConflict) {
+		t.Fail()
+	}
+}
+
+func TestService_AssignParentToRoleErrRoleCycle(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24})
+	data := dto.RoleParentService{Role: "Продавец", Parent: "Менеджер", Service: "store"}
+
+	repo.EXPECT().AssignParentToRole(ctx, &data).Times(1).Return(joint.ErrRoleCycle)
+
+	if !errors.Is(s.AssignParentToRole(ctx, &data), service.ErrRoleCycle) {
+		t.Fail()
+	}
+}
+
+func TestService_ImportRBACRoleParents(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24})
+
+	expectRBACExport(ctx, repo)
+
+	document := dto.RBACDocument{Version: RBACDocumentVersion, Services: []dto.RBACService{{
+		Name:        "store",
+		Description: "Store",
+		Roles: []dto.RBACRole{
+			{Name: "manager", Parents: []string{"reader"}},
+			{Name: "reader", Permissions: []string{"read"}},
+		},
+	}}}
+
+	result, err := s.ImportRBAC(ctx, &document, true)
+	if err != nil || len(result.Conflicts) != 0 {
+		t.Fatal()
+	}
+
+	expected := []dto.RBACChange{
+		{Action: actionCreate, Kind: "role", Target: "store/manager"},
+		{Action: actionAssign, Kind: "role_parent", Target: "store/manager → reader"},
+	}
+	if len(result.Changes) != len(expected) || result.Changes[0] != expected[0] || result.Changes[1] != expected[1] {
+		t.Fatal()
+	}
+
+	expectRBACExport(ctx, repo)
+
+	document.Services[0].Roles[1].Parents = []string{"manager"}
+	result, err = s.ImportRBAC(ctx, &document, true)
+	if !errors.Is(err, service.ErrRBACConflict) || len(result.Conflicts) != 1 ||
+		result.Conflicts[0] != "roles form an inheritance cycle: role:store/manager → role:store/reader → role:store/manager" {
+		t.Fail()
+	}
+}
+
+func TestService_AssignSubgroupToGroupErrGroupCycle(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24})
+	data := dto.GroupSubgroupService{Group: "Кассиры", Subgroup: "Персонал магазина", Service: "store"}
+
+	repo.EXPECT().AssignSubgroupToGroup(ctx, &data).Times(1).Return(joint.ErrGroupCycle)
+
+	if !errors.Is(s.AssignSubgroupToGroup(ctx, &data), service.ErrGroupCycle) {
+		t.Fail()
+	}
+}
+
+func TestService_ImportRBACSubgroups(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24})
+
+	expectRBACExport(ctx, repo)
+
+	document := dto.RBACDocument{Version: RBACDocumentVersion, Services: []dto.RBACService{{
+		Name:        "store",
+		Description: "Store",
+		Groups: []dto.RBACGroup{
+			{Name: "staff", Subgroups: []string{"cashiers"}},
+			{Name: "cashiers"},
+		},
+	}}}
+
+	result, err := s.ImportRBAC(ctx, &document, true)
+	if err != nil || len(result.Conflicts) != 0 {
+		t.Fatal()
+	}
+
+	expected := []dto.RBACChange{
+		{Action: actionCreate, Kind: "group", Target: "store/staff"},
+		{Action: actionCreate, Kind: "group", Target: "store/cashiers"},
+		{Action: actionAssign, Kind: "group_subgroup", Target: "store/staff → cashiers"},
+	}
+	if len(result.Changes) != len(expected) || result.Changes[0] != expected[0] || result.Changes[1] != expected[1] ||
+		result.Changes[2] != expected[2] {
+		t.Fatal()
+	}
+
+	expectRBACExport(ctx, repo)
+
+	document.Services[0].Groups[1].Subgroups = []string{"staff"}
+	result, err = s.ImportRBAC(ctx, &document, true)
+	if !errors.Is(err, service.ErrRBACConflict) || len(result.Conflicts) != 1 ||
+		result.Conflicts[0] != "groups form a nesting cycle: group:store/cashiers → group:store/staff → group:store/cashiers" {
+		t.Fail()
+	}
+}
+
+func TestService_ImportRBACPermissionEncoding(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24})
+
+	expectRBACExport(ctx, repo)
+
+	document := dto.RBACDocument{Version: RBACDocumentVersion, Services: []dto.RBACService{{
+		Name:               "store",
+		Description:        "Store",
+		PermissionEncoding: permission_encoding.Bitmap,
+	}}}
+
+	result, err := s.ImportRBAC(ctx, &document, true)
+	if err != nil || len(result.Changes) != 1 || result.Changes[0] != (dto.RBACChange{Action: actionUpdate, Kind: "service", Target: "store"}) {
+		t.Fatal()
+	}
+
+	expectRBACExport(ctx, repo)
+
+	document.Services[0].PermissionEncoding = "gzip"
+	if _, err = s.ImportRBAC(ctx, &document, true); !errors.Is(err, service.ErrRBACConflict) {
+		t.Fail()
+	}
+}
+
+func TestService_ImportRBACAccountMetadata(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24})
+
+	expectRBACExport(ctx, repo)
+
+	account := rbacAccount
+	account.Type = account_type.Service
+	account.OwnerTeam = "logistics"
+	document := dto.RBACDocument{Version: RBACDocumentVersion, Accounts: []dto.AccountDetails{account}}
+
+	result, err := s.ImportRBAC(ctx, &document, true)
+	if err != nil || len(result.Changes) != 1 || result.Changes[0] != (dto.RBACChange{Action: actionUpdate, Kind: "account", Target: "keeper"}) {
+		t.Fatal()
+	}
+
+	expectRBACExport(ctx, repo)
+
+	document.Accounts[0].Type = "robot"
+	if _, err = s.ImportRBAC(ctx, &document, true); !errors.Is(err, service.ErrRBACConflict) {
+		t.Fail()
+	}
+}
+
+func TestService_ReconcileRBACErrRemovesAdministrators(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24, AdminService: "store", AdminRole: "reader"})
+
+	expectRBACExport(ctx, repo)
+
+	document := dto.RBACDocument{Version: RBACDocumentVersion, Services: []dto.RBACService{{Name: "store", Description: "Store"}}}
+
+	if result, err := s.ReconcileRBAC(ctx, &document, true); !errors.Is(err, service.ErrRBACConflict) || len(result.Conflicts) != 1 {
+		t.Fail()
+	}
+}
+
+func TestService_ReconcileRBACErrUnassignsAdministrator(t *testing.T) {
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+	repo := mockjoint.NewMockInterface(controller)
+	metrics := mockservice.NewMockMetricsInterface(controller)
+	s := MustCreate(metrics, repo, config.Secure{LoginTokenLength: 24, AdminService: "store", AdminRole: "reader"})
+
+	expectRBACExport(ctx, repo)
+	repo.EXPECT().ImportRBAC(ctx, gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	document := dto.RBACDocument{
+		Version: RBACDocumentVersion,
+		Services: []dto.RBACService{{
+			Name:           "store",
+			Description:    "Store",
+			Instances:      []string{"store-1"},
+			Permissions:    []dto.NameNumberDescriptionDeprecated{{Name: "read", Number: 1, Description: "Read"}},
+			RetiredNumbers: []int{2},
+			Roles:          []dto.RBACRole{{Name: "reader", Permissions: []string{"read"}}},
+		}},
+		Accounts: []dto.AccountDetails{{UserId: rbacAccount.UserId, Login: rbacAccount.Login, State: rbacAccount.State}},
+	}
+
+	result, err := s.ReconcileRBAC(ctx, &document, true)
+	if !errors.Is(err, service.ErrRBACConflict) || len(result.Conflicts) != 1 ||
+		!strings.Contains(result.Conflicts[0], rbacAccount.UserId.String()) {
+		t.Fail()
+	}
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/lazylex/watch-store/secure/internal/domain/value_objects/account_state"
+	"github.com/lazylex/watch-store/secure/internal/dto"
+	jointErr "github.com/lazylex/watch-store/secure/internal/errors/joint"
+	"github.com/lazylex/watch-store/secure/pkg/tokenperm"
+	"time"
+)
+
+// permissionClaims содержит проверенные данные JWT-токена, выданного функцией CreateToken.
+type permissionClaims struct {
+	userId      uuid.UUID
+	instance    string
+	tokenId     string
+	permissions []int
+	expiresAt   time.Time
+	issuedAt    time.Time
+}
+
+// IntrospectToken проверяет JWT-токен, выданный функцией CreateToken (RFC 7662): подпись секретом экземпляра сервиса,
+// срок действия, отсутствие отзыва и активность учетной записи. Для действующего токена возвращает UUID учетной
+// записи, экземпляр и сервис, а также названия разрешений. Для недействительного токена возвращает структуру с
+// Active равным false без ошибки. Ошибка возвращается только при невозможности выполнить проверку.
+func (s *Service) IntrospectToken(ctx context.Context, token string) (dto.TokenIntrospection, error) {
+	var (
+		claims      permissionClaims
+		revoked     bool
+		loginData   dto.UserIdLoginHashState
+		serviceName string
+		numbered    *[]dto.NameNumber
+		err         error
+	)
+
+	if claims, err = s.parsePermissionToken(ctx, token); err != nil {
+		return dto.TokenIntrospection{}, inactiveOrErr(err)
+	}
+
+	if revoked, err = s.repository.IsTokenRevoked(ctx, claims.tokenId); err != nil || revoked {
+		return dto.TokenIntrospection{}, adaptErr(err)
+	}
+
+	if loginData, err = s.repository.AccountLoginDataByUserId(ctx, claims.userId); err != nil {
+		return dto.TokenIntrospection{}, inactiveOrErr(err)
+	}
+
+	if loginData.State != account_state.Enabled {
+		return dto.TokenIntrospection{}, nil
+	}
+
+	if serviceName, err = s.repository.ServiceName(ctx, claims.instance); err != nil {
+		return dto.TokenIntrospection{}, inactiveOrErr(err)
+	}
+
+	if numbered, err = s.repository.ServiceNumberedPermissions(ctx, serviceName); err != nil && !errors.Is(err, jointErr.ErrEmptyResult) {
+		return dto.TokenIntrospection{}, adaptErr(err)
+	}
+
+	names := make(map[int]string)
+	if numbered != nil {
+		for _, permission := range *numbered {
+			names[permission.Number] = permission.Name
+		}
+	}
+
+	permissions := make([]string, 0, len(claims.permissions))
+	for _, number := range claims.permissions {
+		if name, ok := names[number]; ok {
+			permissions = append(permissions, name)
+		}
+	}
+
+	return dto.TokenIntrospection{
+		Active:      true,
+		UserId:      claims.userId,
+		Instance:    claims.instance,
+		Service:     serviceName,
+		Permissions: permissions,
+		ExpiresAt:   claims.expiresAt.Unix(),
+		IssuedAt:    claims.issuedAt.Unix(),
+		TokenId:     claims.tokenId,
+	}, nil
+}
+
+// RevokeToken отзывает JWT-токен, выданный функцией CreateToken (RFC 7009). Отозвать токен может учетная запись,
+// которой он выдан, или администратор. Недействительный или просроченный токен отзыва не требует, поэтому ошибка в этом
+// случае не возвращается.
+func (s *Service) RevokeToken(ctx context.Context, callerId uuid.UUID, token string) error {
+	var claims permissionClaims
+	var isAdmin bool
+	var err error
+
+	if claims, err = s.parsePermissionToken(ctx, token); err != nil {
+		return inactiveOrErr(err)
+	}
+
+	if claims.userId != callerId {
+		if isAdmin, err = s.IsAdmin(ctx, callerId); err != nil {
+			return err
+		}
+		if !isAdmin {
+			return ErrNotTokenOwner()
+		}
+	}
+
+	return adaptErr(s.repository.RevokeToken(ctx, &dto.TokenTTL{Token: claims.tokenId, TTL: time.Until(claims.expiresAt)}))
+}
+
+// parsePermissionToken проверяет подпись и срок действия JWT-токена и возвращает его данные. Секрет для проверки подписи
+// берётся у экземпляра сервиса, указанного в поле aud. Ошибка хранилища возвращается без изменений, остальные ошибки
+// означают, что токен недействителен.
+func (s *Service) parsePermissionToken(ctx context.Context, token string) (permissionClaims, error) {
+	var result permissionClaims
+	var repositoryErr error
+
+	parsed, err := jwt.Parse(token, func(t *jwt.Token) (any, error) {
+		audience, errAud := t.Claims.GetAudience()
+		if errAud != nil || len(audience) != 1 {
+			return nil, jwt.ErrTokenInvalidAudience
+		}
+
+		result.instance = audience[0]
+		secret, errSecret := s.repository.InstanceSecret(ctx, result.instance)
+		if errSecret != nil {
+			repositoryErr = errSecret
+			return nil, errSecret
+		}
+
+		return []byte(secret), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired(), jwt.WithIssuedAt())
+
+	if repositoryErr != nil {
+		return permissionClaims{}, repositoryErr
+	}
+
+	if err != nil || !parsed.Valid {
+		return permissionClaims{}, jwt.ErrTokenInvalidClaims
+	}
+
+	claims := parsed.Claims.(jwt.MapClaims)
+
+	subject, _ := claims.GetSubject()
+	if result.userId, err = uuid.Parse(subject); err != nil {
+		return permissionClaims{}, jwt.ErrTokenInvalidSubject
+	}
+
+	if result.tokenId, _ = claims["jti"].(string); len(result.tokenId) == 0 {
+		return permissionClaims{}, jwt.ErrTokenInvalidId
+	}
+
+	expiresAt, _ := claims.GetExpirationTime()
+	result.expiresAt = expiresAt.Time
+
+	if issuedAt, _ := claims.GetIssuedAt(); issuedAt != nil {
+		result.issuedAt = issuedAt.Time
+	}
+
+	if result.permissions, err = tokenperm.FromClaims(claims); err != nil {
+		return permissionClaims{}, jwt.ErrTokenInvalidClaims
+	}
+
+	return result, nil
+}
+
+// inactiveOrErr возвращает nil для ошибок, означающих недействительность токена (в том числе отсутствие экземпляра
+// сервиса или учетной записи в хранилище), и адаптированную ошибку для прочих ошибок хранилища.
+func inactiveOrErr(err error) error {
+	if errors.Is(err, jointErr.ErrEmptyResult) || errors.Is(err, jwt.ErrTokenInvalidClaims) ||
+		errors.Is(err, jwt.ErrTokenInvalidSubject) || errors.Is(err, jwt.ErrTokenInvalidId) {
+		return nil
+	}
+
+	return adaptErrSkipFrames(err, 2)
+}
@@ -0,0 +1,194 @@
+/*
+Package tls_config: пакет предназначен для создания конфигурации TLS-серверов приложения. Сертификат сервера
+перечитывается с диска при изменении файлов сертификата или ключа, поэтому для его замены перезапуск приложения не
+требуется.
+*/
+package tls_config
+
+import (
+	"crypto/tls"
+	"fmt"
+	"github.com/lazylex/watch-store/secure/internal/config"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+const defaultReloadInterval = time.Minute
+
+// Reloader хранит сертификат TLS-сервера и обновляет его при изменении файлов сертификата или ключа.
+type Reloader struct {
+	certFile string           // Путь к файлу сертификата
+	keyFile  string           // Путь к файлу закрытого ключа
+	mu       sync.RWMutex     // Защищает cert и modTime
+	cert     *tls.Certificate // Текущий сертификат
+	modTime  time.Time        // Время последнего изменения файлов, из которых загружен текущий сертификат
+}
+
+// MustCreate возвращает конфигурацию TLS-сервера с минимальной версией протокола, набором шифров и автоматически
+// обновляемым сертификатом. При некорректной конфигурации или ошибке загрузки сертификата работа приложения
+// завершается.
+func MustCreate(cfg *config.TLS) *tls.Config {
+	version, err := minVersion(cfg.MinVersion)
+	if err != nil {
+		slog.Error(err.Error())
+		os.Exit(1)
+	}
+
+	suites, err := cipherSuites(cfg.CipherSuites)
+	if err != nil {
+		slog.Error(err.Error())
+		os.Exit(1)
+	}
+
+	reloader, err := NewReloader(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		slog.Error("unable to load tls certificate: " + err.Error())
+		os.Exit(1)
+	}
+
+	interval := cfg.ReloadInterval
+	if interval <= 0 {
+		interval = defaultReloadInterval
+	}
+	reloader.Watch(interval)
+
+	return &tls.Config{
+		MinVersion:     version,
+		CipherSuites:   suites,
+		GetCertificate: reloader.GetCertificate,
+	}
+}
+
+// NewReloader загружает сертификат из переданных файлов и возвращает структуру для его обновления.
+func NewReloader(certFile, keyFile string) (*Reloader, error) {
+	r := &Reloader{certFile: certFile, keyFile: keyFile}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// GetCertificate возвращает текущий сертификат. Предназначена для использования в качестве tls.Config.GetCertificate.
+func (r *Reloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.cert, nil
+}
+
+// Watch запускает в отдельной go-рутине периодическую проверку файлов сертификата и ключа. При их изменении сертификат
+// перечитывается. Если новый сертификат загрузить не удалось, продолжает использоваться прежний.
+func (r *Reloader) Watch(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if reloaded, err := r.reloadIfModified(); err != nil {
+				slog.Error("unable to reload tls certificate: " + err.Error())
+			} else if reloaded {
+				slog.Info("tls certificate reloaded from " + r.certFile)
+			}
+		}
+	}()
+}
+
+// reloadIfModified перечитывает сертификат, если файлы сертификата или ключа изменились после его загрузки. Возвращает
+// true, если сертификат был обновлен.
+func (r *Reloader) reloadIfModified() (bool, error) {
+	modTime, err := lastModified(r.certFile, r.keyFile)
+	if err != nil {
+		return false, err
+	}
+
+	r.mu.RLock()
+	unchanged := !modTime.After(r.modTime)
+	r.mu.RUnlock()
+
+	if unchanged {
+		return false, nil
+	}
+
+	return true, r.reload()
+}
+
+// reload загружает сертификат и ключ из файлов.
+func (r *Reloader) reload() error {
+	modTime, err := lastModified(r.certFile, r.keyFile)
+	if err != nil {
+		return err
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	r.cert = &cert
+	r.modTime = modTime
+	r.mu.Unlock()
+
+	return nil
+}
+
+// lastModified возвращает наиболее позднее время изменения из переданных файлов.
+func lastModified(files ...string) (time.Time, error) {
+	var latest time.Time
+
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+
+	return latest, nil
+}
+
+// minVersion возвращает константу версии TLS по её строковому представлению. По умолчанию используется TLS 1.2.
+func minVersion(version string) (uint16, error) {
+	switch version {
+	case "", "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	case "1.0":
+		return tls.VersionTLS10, nil
+	case "1.1":
+		return tls.VersionTLS11, nil
+	}
+
+	return 0, fmt.Errorf("unknown tls version %s", version)
+}
+
+// cipherSuites возвращает идентификаторы наборов шифров по их названиям. Допускаются только наборы, которые стандартная
+// библиотека не считает небезопасными. Пустой список означает использование наборов по умолчанию. Наборы шифров для
+// TLS 1.3 не настраиваются.
+func cipherSuites(names []string) ([]uint16, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	known := make(map[string]uint16)
+	for _, suite := range tls.CipherSuites() {
+		known[suite.Name] = suite.ID
+	}
+
+	suites := make([]uint16, 0, len(names))
+	for _, name := range names {
+		id, ok := known[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown or insecure cipher suite %s", name)
+		}
+		suites = append(suites, id)
+	}
+
+	return suites, nil
+}
@@ -0,0 +1,137 @@
+package tls_config
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeCertificate создаёт самоподписанный сертификат с переданным CommonName и записывает его и ключ в файлы,
+// устанавливая им время изменения modTime.
+func writeCertificate(t *testing.T, certFile, keyFile, commonName string, modTime time.Time) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, file := range []string{certFile, keyFile} {
+		if err = os.Chtimes(file, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// commonName возвращает CommonName текущего сертификата.
+func commonName(t *testing.T, r *Reloader) string {
+	cert, _ := r.GetCertificate(nil)
+	parsed, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return parsed.Subject.CommonName
+}
+
+func TestReloader_ReloadIfModified(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "server.crt"), filepath.Join(dir, "server.key")
+	start := time.Now().Add(-time.Minute)
+
+	writeCertificate(t, certFile, keyFile, "first", start)
+	r, err := NewReloader(certFile, keyFile)
+	if err != nil || commonName(t, r) != "first" {
+		t.Fatal()
+	}
+
+	if reloaded, errReload := r.reloadIfModified(); reloaded || errReload != nil {
+		t.Fatal("unchanged certificate must not be reloaded")
+	}
+
+	writeCertificate(t, certFile, keyFile, "second", start.Add(time.Second))
+	if reloaded, errReload := r.reloadIfModified(); !reloaded || errReload != nil || commonName(t, r) != "second" {
+		t.Fatal()
+	}
+}
+
+func TestReloader_KeepsCertificateOnError(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "server.crt"), filepath.Join(dir, "server.key")
+	start := time.Now().Add(-time.Minute)
+
+	writeCertificate(t, certFile, keyFile, "first", start)
+	r, err := NewReloader(certFile, keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = os.WriteFile(certFile, []byte("broken"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = r.reloadIfModified(); err == nil || commonName(t, r) != "first" {
+		t.Fail()
+	}
+}
+
+func TestMinVersion(t *testing.T) {
+	if v, err := minVersion(""); err != nil || v != tls.VersionTLS12 {
+		t.Fail()
+	}
+
+	if v, err := minVersion("1.3"); err != nil || v != tls.VersionTLS13 {
+		t.Fail()
+	}
+
+	if _, err := minVersion("2.0"); err == nil {
+		t.Fail()
+	}
+}
+
+func TestCipherSuites(t *testing.T) {
+	if suites, err := cipherSuites(nil); err != nil || suites != nil {
+		t.Fail()
+	}
+
+	suites, err := cipherSuites([]string{"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256"})
+	if err != nil || len(suites) != 1 || suites[0] != tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256 {
+		t.Fail()
+	}
+
+	if _, err = cipherSuites([]string{"TLS_RSA_WITH_RC4_128_SHA"}); err == nil {
+		t.Fail()
+	}
+}
@@ -0,0 +1,437 @@
+/*
+Package condition: пакет для разбора и вычисления условий, которыми ограничиваются назначения учетным записям ролей,
+групп и разрешений. Условие вычисляется при выдаче токена по атрибутам запроса (Environment) и записывается
+выражением небольшого языка:
+
+  - time_between("09:00", "21:00") - время запроса входит в интервал (правая граница не включается). Если начало
+    интервала позже окончания, интервал переходит через полночь;
+  - ip_in("10.0.0.0/8", "192.168.1.0/24") - адрес клиента входит в одну из подсетей;
+  - weekday - день недели запроса: "mon", "tue", "wed", "thu", "fri", "sat" или "sun";
+  - instance.<атрибут> - значение атрибута экземпляра сервиса (пустая строка, если атрибут не задан);
+  - строки в двойных кавычках, сравнения == и !=, логические операции !, && и || и скобки.
+
+Пример: time_between("09:00", "21:00") && instance.region == "south" && !(weekday == "sun").
+*/
+package condition
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"strings"
+	"time"
+)
+
+// InstanceAttributePrefix префикс идентификатора атрибута экземпляра сервиса.
+const InstanceAttributePrefix = "instance."
+
+var ErrSyntax = errors.New("condition syntax error")
+
+var weekdays = [...]string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// Environment атрибуты запроса, по которым вычисляется условие.
+type Environment struct {
+	Time     time.Time         // Время запроса
+	Address  netip.Addr        // Адрес клиента. Нулевое значение означает, что адрес неизвестен
+	Instance map[string]string // Атрибуты экземпляра сервиса
+}
+
+// Condition разобранное условие.
+type Condition struct {
+	text string
+	root node
+}
+
+// Parse разбирает текст условия. Ошибки разбора оборачивают ErrSyntax.
+func Parse(text string) (*Condition, error) {
+	tokens, err := tokenize(text)
+	if err != nil {
+		return nil, err
+	}
+
+	p := parser{tokens: tokens}
+	root, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.end() {
+		return nil, p.errorf("unexpected %q", p.peek().text)
+	}
+
+	return &Condition{text: text, root: root}, nil
+}
+
+// Valid возвращает true, если текст является корректным условием.
+func Valid(text string) bool {
+	_, err := Parse(text)
+	return err == nil
+}
+
+// ValidAttributeName возвращает true, если name можно использовать в условиях как название атрибута экземпляра
+// (instance.<name>): оно начинается с латинской буквы или подчеркивания и содержит также цифры, точки и дефисы.
+func ValidAttributeName(name string) bool {
+	for i := 0; i < len(name); i++ {
+		if !isIdentifierByte(name[i], i == 0) {
+			return false
+		}
+	}
+
+	return len(name) > 0
+}
+
+// Evaluate возвращает результат вычисления условия для атрибутов запроса env.
+func (c *Condition) Evaluate(env *Environment) bool {
+	return c.root.bool(env)
+}
+
+// String возвращает исходный текст условия.
+func (c *Condition) String() string {
+	return c.text
+}
+
+// ParseAddress возвращает адрес из строки вида "адрес" или "адрес:порт" (например, http.Request.RemoteAddr). Если
+// строку разобрать не удалось, возвращается нулевой адрес.
+func ParseAddress(address string) netip.Addr {
+	if addrPort, err := netip.ParseAddrPort(address); err == nil {
+		return addrPort.Addr().Unmap()
+	}
+
+	if addr, err := netip.ParseAddr(address); err == nil {
+		return addr.Unmap()
+	}
+
+	return netip.Addr{}
+}
+
+// node узел дерева выражения.
+type node interface {
+	bool(env *Environment) bool
+	string(env *Environment) string
+}
+
+type (
+	orNode    struct{ left, right node }
+	andNode   struct{ left, right node }
+	notNode   struct{ operand node }
+	equalNode struct {
+		left, right node
+		negate      bool
+	}
+	literalNode   struct{ value string }
+	attributeNode struct{ name string }
+	weekdayNode   struct{}
+	timeNode      struct{ from, until int }
+	ipNode        struct{ prefixes []netip.Prefix }
+)
+
+func (n orNode) bool(env *Environment) bool  { return n.left.bool(env) || n.right.bool(env) }
+func (n andNode) bool(env *Environment) bool { return n.left.bool(env) && n.right.bool(env) }
+func (n notNode) bool(env *Environment) bool { return !n.operand.bool(env) }
+func (n equalNode) bool(env *Environment) bool {
+	return (n.left.string(env) == n.right.string(env)) != n.negate
+}
+
+func (n literalNode) bool(*Environment) bool       { return len(n.value) > 0 }
+func (n attributeNode) bool(env *Environment) bool { return len(n.string(env)) > 0 }
+func (n weekdayNode) bool(*Environment) bool       { return true }
+
+func (n timeNode) bool(env *Environment) bool {
+	minutes := env.Time.Hour()*60 + env.Time.Minute()
+	if n.from <= n.until {
+		return n.from <= minutes && minutes < n.until
+	}
+
+	return minutes >= n.from || minutes < n.until
+}
+
+func (n ipNode) bool(env *Environment) bool {
+	if !env.Address.IsValid() {
+		return false
+	}
+
+	for _, prefix := range n.prefixes {
+		if prefix.Contains(env.Address) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (n orNode) string(env *Environment) string    { return boolString(n.bool(env)) }
+func (n andNode) string(env *Environment) string   { return boolString(n.bool(env)) }
+func (n notNode) string(env *Environment) string   { return boolString(n.bool(env)) }
+func (n equalNode) string(env *Environment) string { return boolString(n.bool(env)) }
+func (n timeNode) string(env *Environment) string  { return boolString(n.bool(env)) }
+func (n ipNode) string(env *Environment) string    { return boolString(n.bool(env)) }
+
+func (n literalNode) string(*Environment) string { return n.value }
+func (n attributeNode) string(env *Environment) string {
+	return env.Instance[n.name]
+}
+func (n weekdayNode) string(env *Environment) string { return weekdays[env.Time.Weekday()] }
+
+// boolString возвращает строковое представление логического значения для сравнения с литералами "true" и "false".
+func boolString(value bool) string {
+	if value {
+		return "true"
+	}
+
+	return "false"
+}
+
+// Виды лексем.
+const (
+	tokenIdentifier = iota
+	tokenString
+	tokenOperator
+)
+
+// token лексема условия.
+type token struct {
+	kind     int
+	text     string
+	position int
+}
+
+// tokenize разбивает текст условия на лексемы.
+func tokenize(text string) ([]token, error) {
+	var tokens []token
+
+	for i := 0; i < len(text); {
+		c := text[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '"':
+			end := strings.IndexByte(text[i+1:], '"')
+			if end < 0 {
+				return nil, fmt.Errorf("%w: unterminated string at %d", ErrSyntax, i)
+			}
+			tokens = append(tokens, token{kind: tokenString, text: text[i+1 : i+1+end], position: i})
+			i += end + 2
+		case isIdentifierByte(c, true):
+			start := i
+			for i < len(text) && isIdentifierByte(text[i], false) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdentifier, text: text[start:i], position: start})
+		default:
+			operator := ""
+			for _, candidate := range []string{"&&", "||", "==", "!=", "!", "(", ")", ","} {
+				if strings.HasPrefix(text[i:], candidate) {
+					operator = candidate
+					break
+				}
+			}
+			if len(operator) == 0 {
+				return nil, fmt.Errorf("%w: unexpected %q at %d", ErrSyntax, c, i)
+			}
+			tokens = append(tokens, token{kind: tokenOperator, text: operator, position: i})
+			i += len(operator)
+		}
+	}
+
+	return tokens, nil
+}
+
+// isIdentifierByte возвращает true, если байт может входить в идентификатор (first - в его начало).
+func isIdentifierByte(c byte, first bool) bool {
+	letter := c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+	if first {
+		return letter
+	}
+
+	return letter || c == '.' || c == '-' || (c >= '0' && c <= '9')
+}
+
+// parser разбор условия методом рекурсивного спуска.
+type parser struct {
+	tokens   []token
+	position int
+}
+
+func (p *parser) end() bool {
+	return p.position >= len(p.tokens)
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.position]
+}
+
+// accept пропускает оператор text и возвращает true, если он следует далее.
+func (p *parser) accept(text string) bool {
+	if !p.end() && p.peek().kind == tokenOperator && p.peek().text == text {
+		p.position++
+		return true
+	}
+
+	return false
+}
+
+// expect пропускает оператор text или возвращает ошибку, если далее следует другая лексема.
+func (p *parser) expect(text string) error {
+	if !p.accept(text) {
+		return p.errorf("%q expected", text)
+	}
+
+	return nil
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	position := -1
+	if !p.end() {
+		position = p.peek().position
+	}
+
+	return fmt.Errorf("%w: %s at %d", ErrSyntax, fmt.Sprintf(format, args...), position)
+}
+
+// or разбирает выражение вида and { "||" and }.
+func (p *parser) or() (node, error) {
+	left, err := p.and()
+	for err == nil && p.accept("||") {
+		var right node
+		if right, err = p.and(); err == nil {
+			left = orNode{left: left, right: right}
+		}
+	}
+
+	return left, err
+}
+
+// and разбирает выражение вида not { "&&" not }.
+func (p *parser) and() (node, error) {
+	left, err := p.not()
+	for err == nil && p.accept("&&") {
+		var right node
+		if right, err = p.not(); err == nil {
+			left = andNode{left: left, right: right}
+		}
+	}
+
+	return left, err
+}
+
+// not разбирает выражение вида "!" not или сравнение.
+func (p *parser) not() (node, error) {
+	if p.accept("!") {
+		operand, err := p.not()
+		return notNode{operand: operand}, err
+	}
+
+	return p.comparison()
+}
+
+// comparison разбирает выражение вида operand [ ("==" | "!=") operand ].
+func (p *parser) comparison() (node, error) {
+	left, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, operator := range []string{"==", "!="} {
+		if p.accept(operator) {
+			right, err := p.operand()
+			return equalNode{left: left, right: right, negate: operator == "!="}, err
+		}
+	}
+
+	return left, nil
+}
+
+// operand разбирает строку, идентификатор, вызов функции или выражение в скобках.
+func (p *parser) operand() (node, error) {
+	if p.end() {
+		return nil, p.errorf("operand expected")
+	}
+
+	if p.accept("(") {
+		inner, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		return inner, p.expect(")")
+	}
+
+	current := p.peek()
+	switch current.kind {
+	case tokenString:
+		p.position++
+		return literalNode{value: current.text}, nil
+	case tokenIdentifier:
+		p.position++
+		if p.accept("(") {
+			return p.call(current)
+		}
+		if current.text == "weekday" {
+			return weekdayNode{}, nil
+		}
+		if name, found := strings.CutPrefix(current.text, InstanceAttributePrefix); found && len(name) > 0 {
+			return attributeNode{name: name}, nil
+		}
+		p.position--
+		return nil, p.errorf("unknown identifier %q", current.text)
+	}
+
+	return nil, p.errorf("unexpected %q", current.text)
+}
+
+// call разбирает аргументы функции function (открывающая скобка уже пропущена) и возвращает узел функции.
+func (p *parser) call(function token) (node, error) {
+	var args []string
+	for !p.accept(")") {
+		if len(args) > 0 {
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+		if p.end() || p.peek().kind != tokenString {
+			return nil, p.errorf("string argument expected")
+		}
+		args = append(args, p.peek().text)
+		p.position++
+	}
+
+	switch function.text {
+	case "time_between":
+		if len(args) != 2 {
+			return nil, fmt.Errorf("%w: time_between requires 2 arguments at %d", ErrSyntax, function.position)
+		}
+		from, err := minutes(args[0])
+		if err != nil {
+			return nil, err
+		}
+		until, err := minutes(args[1])
+		if err != nil {
+			return nil, err
+		}
+		return timeNode{from: from, until: until}, nil
+	case "ip_in":
+		if len(args) == 0 {
+			return nil, fmt.Errorf("%w: ip_in requires at least 1 argument at %d", ErrSyntax, function.position)
+		}
+		prefixes := make([]netip.Prefix, 0, len(args))
+		for _, arg := range args {
+			prefix, err := netip.ParsePrefix(arg)
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid subnet %q", ErrSyntax, arg)
+			}
+			prefixes = append(prefixes, prefix.Masked())
+		}
+		return ipNode{prefixes: prefixes}, nil
+	}
+
+	return nil, fmt.Errorf("%w: unknown function %q at %d", ErrSyntax, function.text, function.position)
+}
+
+// minutes возвращает количество минут с начала суток для времени в формате "ЧЧ:ММ".
+func minutes(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("%w: invalid time %q", ErrSyntax, value)
+	}
+
+	return t.Hour()*60 + t.Minute(), nil
+}
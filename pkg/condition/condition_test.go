@@ -0,0 +1,94 @@
+package condition
+
+import (
+	"errors"
+	"net/netip"
+	"testing"
+	"time"
+)
+
+// environment возвращает атрибуты запроса в среду 2024-05-15 в переданное время из магазина южного региона.
+func environment(clock, address string) *Environment {
+	t, _ := time.Parse("2006-01-02 15:04", "2024-05-15 "+clock)
+	return &Environment{Time: t, Address: ParseAddress(address), Instance: map[string]string{"region": "south"}}
+}
+
+func TestEvaluate(t *testing.T) {
+	cases := []struct {
+		condition string
+		env       *Environment
+		expected  bool
+	}{
+		{`time_between("09:00", "21:00")`, environment("12:30", ""), true},
+		{`time_between("09:00", "21:00")`, environment("21:00", ""), false},
+		{`time_between("22:00", "06:00")`, environment("23:15", ""), true},
+		{`time_between("22:00", "06:00")`, environment("05:59", ""), true},
+		{`time_between("22:00", "06:00")`, environment("12:00", ""), false},
+		{`ip_in("10.0.0.0/8", "192.168.1.0/24")`, environment("12:00", "192.168.1.15:51234"), true},
+		{`ip_in("10.0.0.0/8")`, environment("12:00", "[::ffff:10.1.2.3]:443"), true},
+		{`ip_in("10.0.0.0/8")`, environment("12:00", "172.16.0.1"), false},
+		{`ip_in("10.0.0.0/8")`, environment("12:00", ""), false},
+		{`instance.region == "south"`, environment("12:00", ""), true},
+		{`instance.region != "south"`, environment("12:00", ""), false},
+		{`instance.city == ""`, environment("12:00", ""), true},
+		{`weekday == "wed"`, environment("12:00", ""), true},
+		{`!(weekday == "sat" || weekday == "sun")`, environment("12:00", ""), true},
+		{`time_between("09:00", "21:00") && instance.region == "north"`, environment("12:00", ""), false},
+		{`instance.region == "north" || time_between("09:00", "21:00") && ip_in("10.0.0.0/8")`,
+			environment("12:00", "10.0.0.1"), true},
+	}
+
+	for _, c := range cases {
+		parsed, err := Parse(c.condition)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", c.condition, err)
+			continue
+		}
+
+		if result := parsed.Evaluate(c.env); result != c.expected {
+			t.Errorf("%s at %s from %s: expected %t", c.condition, c.env.Time.Format("15:04"), c.env.Address, c.expected)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, text := range []string{
+		``,
+		`time_between("09:00")`,
+		`time_between("9", "21:00")`,
+		`ip_in("10.0.0.0/33")`,
+		`ip_in()`,
+		`unknown("a")`,
+		`region == "south"`,
+		`instance.region == "south`,
+		`instance.region == "south" &&`,
+		`(weekday == "mon"`,
+		`weekday == "mon")`,
+		`weekday = "mon"`,
+	} {
+		if _, err := Parse(text); !errors.Is(err, ErrSyntax) {
+			t.Errorf("%q: expected syntax error, got %v", text, err)
+		}
+	}
+}
+
+func TestStringAndValid(t *testing.T) {
+	text := `instance.region == "south"`
+	if parsed, err := Parse(text); err != nil || parsed.String() != text || !Valid(text) || Valid(`ip_in(`) {
+		t.Fail()
+	}
+}
+
+func TestParseAddress(t *testing.T) {
+	if ParseAddress("10.0.0.1:8080") != netip.MustParseAddr("10.0.0.1") || ParseAddress("::1") != netip.IPv6Loopback() ||
+		ParseAddress("unknown").IsValid() {
+		t.Fail()
+	}
+}
+
+func TestValidAttributeName(t *testing.T) {
+	if !ValidAttributeName("region") || !ValidAttributeName("time-zone.2") || ValidAttributeName("") ||
+		ValidAttributeName("2region") || ValidAttributeName(`region == ""`) {
+		t.Fail()
+	}
+}
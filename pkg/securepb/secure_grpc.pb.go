@@ -0,0 +1,1605 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v4.25.3
+// source: secure.proto
+
+package securepb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	Secure_Login_FullMethodName                  = "/secure.v1.Secure/Login"
+	Secure_CompleteLogin_FullMethodName          = "/secure.v1.Secure/CompleteLogin"
+	Secure_Logout_FullMethodName                 = "/secure.v1.Secure/Logout"
+	Secure_GetToken_FullMethodName               = "/secure.v1.Secure/GetToken"
+	Secure_GetNumberedPermissions_FullMethodName = "/secure.v1.Secure/GetNumberedPermissions"
+)
+
+// SecureClient is the client API for Secure service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type SecureClient interface {
+	Login(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginResponse, error)
+	CompleteLogin(ctx context.Context, in *CompleteLoginRequest, opts ...grpc.CallOption) (*CompleteLoginResponse, error)
+	Logout(ctx context.Context, in *LogoutRequest, opts ...grpc.CallOption) (*LogoutResponse, error)
+	GetToken(ctx context.Context, in *GetTokenRequest, opts ...grpc.CallOption) (*GetTokenResponse, error)
+	GetNumberedPermissions(ctx context.Context, in *GetNumberedPermissionsRequest, opts ...grpc.CallOption) (*GetNumberedPermissionsResponse, error)
+}
+
+type secureClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSecureClient(cc grpc.ClientConnInterface) SecureClient {
+	return &secureClient{cc}
+}
+
+func (c *secureClient) Login(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginResponse, error) {
+	out := new(LoginResponse)
+	err := c.cc.Invoke(ctx, Secure_Login_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *secureClient) CompleteLogin(ctx context.Context, in *CompleteLoginRequest, opts ...grpc.CallOption) (*CompleteLoginResponse, error) {
+	out := new(CompleteLoginResponse)
+	err := c.cc.Invoke(ctx, Secure_CompleteLogin_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *secureClient) Logout(ctx context.Context, in *LogoutRequest, opts ...grpc.CallOption) (*LogoutResponse, error) {
+	out := new(LogoutResponse)
+	err := c.cc.Invoke(ctx, Secure_Logout_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *secureClient) GetToken(ctx context.Context, in *GetTokenRequest, opts ...grpc.CallOption) (*GetTokenResponse, error) {
+	out := new(GetTokenResponse)
+	err := c.cc.Invoke(ctx, Secure_GetToken_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *secureClient) GetNumberedPermissions(ctx context.Context, in *GetNumberedPermissionsRequest, opts ...grpc.CallOption) (*GetNumberedPermissionsResponse, error) {
+	out := new(GetNumberedPermissionsResponse)
+	err := c.cc.Invoke(ctx, Secure_GetNumberedPermissions_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SecureServer is the server API for Secure service.
+// All implementations must embed UnimplementedSecureServer
+// for forward compatibility
+type SecureServer interface {
+	Login(context.Context, *LoginRequest) (*LoginResponse, error)
+	CompleteLogin(context.Context, *CompleteLoginRequest) (*CompleteLoginResponse, error)
+	Logout(context.Context, *LogoutRequest) (*LogoutResponse, error)
+	GetToken(context.Context, *GetTokenRequest) (*GetTokenResponse, error)
+	GetNumberedPermissions(context.Context, *GetNumberedPermissionsRequest) (*GetNumberedPermissionsResponse, error)
+	mustEmbedUnimplementedSecureServer()
+}
+
+// UnimplementedSecureServer must be embedded to have forward compatible implementations.
+type UnimplementedSecureServer struct {
+}
+
+func (UnimplementedSecureServer) Login(context.Context, *LoginRequest) (*LoginResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Login not implemented")
+}
+func (UnimplementedSecureServer) CompleteLogin(context.Context, *CompleteLoginRequest) (*CompleteLoginResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CompleteLogin not implemented")
+}
+func (UnimplementedSecureServer) Logout(context.Context, *LogoutRequest) (*LogoutResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Logout not implemented")
+}
+func (UnimplementedSecureServer) GetToken(context.Context, *GetTokenRequest) (*GetTokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetToken not implemented")
+}
+func (UnimplementedSecureServer) GetNumberedPermissions(context.Context, *GetNumberedPermissionsRequest) (*GetNumberedPermissionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNumberedPermissions not implemented")
+}
+func (UnimplementedSecureServer) mustEmbedUnimplementedSecureServer() {}
+
+// UnsafeSecureServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SecureServer will
+// result in compilation errors.
+type UnsafeSecureServer interface {
+	mustEmbedUnimplementedSecureServer()
+}
+
+func RegisterSecureServer(s grpc.ServiceRegistrar, srv SecureServer) {
+	s.RegisterService(&Secure_ServiceDesc, srv)
+}
+
+func _Secure_Login_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoginRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SecureServer).Login(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Secure_Login_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SecureServer).Login(ctx, req.(*LoginRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Secure_CompleteLogin_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CompleteLoginRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SecureServer).CompleteLogin(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Secure_CompleteLogin_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SecureServer).CompleteLogin(ctx, req.(*CompleteLoginRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Secure_Logout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LogoutRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SecureServer).Logout(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Secure_Logout_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SecureServer).Logout(ctx, req.(*LogoutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Secure_GetToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SecureServer).GetToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Secure_GetToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SecureServer).GetToken(ctx, req.(*GetTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Secure_GetNumberedPermissions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNumberedPermissionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SecureServer).GetNumberedPermissions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Secure_GetNumberedPermissions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SecureServer).GetNumberedPermissions(ctx, req.(*GetNumberedPermissionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Secure_ServiceDesc is the grpc.ServiceDesc for Secure service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Secure_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "secure.v1.Secure",
+	HandlerType: (*SecureServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Login",
+			Handler:    _Secure_Login_Handler,
+		},
+		{
+			MethodName: "CompleteLogin",
+			Handler:    _Secure_CompleteLogin_Handler,
+		},
+		{
+			MethodName: "Logout",
+			Handler:    _Secure_Logout_Handler,
+		},
+		{
+			MethodName: "GetToken",
+			Handler:    _Secure_GetToken_Handler,
+		},
+		{
+			MethodName: "GetNumberedPermissions",
+			Handler:    _Secure_GetNumberedPermissions_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "secure.proto",
+}
+
+const (
+	Admin_CreatePermission_FullMethodName                  = "/secure.v1.Admin/CreatePermission"
+	Admin_CreateRole_FullMethodName                        = "/secure.v1.Admin/CreateRole"
+	Admin_CreateGroup_FullMethodName                       = "/secure.v1.Admin/CreateGroup"
+	Admin_SetAccountMetadata_FullMethodName                = "/secure.v1.Admin/SetAccountMetadata"
+	Admin_AssignRoleToAccount_FullMethodName               = "/secure.v1.Admin/AssignRoleToAccount"
+	Admin_AssignGroupToAccount_FullMethodName              = "/secure.v1.Admin/AssignGroupToAccount"
+	Admin_AssignInstancePermissionToAccount_FullMethodName = "/secure.v1.Admin/AssignInstancePermissionToAccount"
+	Admin_AssignInstanceRoleToAccount_FullMethodName       = "/secure.v1.Admin/AssignInstanceRoleToAccount"
+	Admin_AssignInstanceGroupToAccount_FullMethodName      = "/secure.v1.Admin/AssignInstanceGroupToAccount"
+	Admin_AssignSelectorPermissionToAccount_FullMethodName = "/secure.v1.Admin/AssignSelectorPermissionToAccount"
+	Admin_AssignRoleToGroup_FullMethodName                 = "/secure.v1.Admin/AssignRoleToGroup"
+	Admin_AssignPermissionToRole_FullMethodName            = "/secure.v1.Admin/AssignPermissionToRole"
+	Admin_AssignParentToRole_FullMethodName                = "/secure.v1.Admin/AssignParentToRole"
+	Admin_AssignPermissionToGroup_FullMethodName           = "/secure.v1.Admin/AssignPermissionToGroup"
+	Admin_AssignSubgroupToGroup_FullMethodName             = "/secure.v1.Admin/AssignSubgroupToGroup"
+	Admin_DeleteRole_FullMethodName                        = "/secure.v1.Admin/DeleteRole"
+	Admin_DeleteGroup_FullMethodName                       = "/secure.v1.Admin/DeleteGroup"
+	Admin_DeletePermission_FullMethodName                  = "/secure.v1.Admin/DeletePermission"
+	Admin_DeprecatePermission_FullMethodName               = "/secure.v1.Admin/DeprecatePermission"
+	Admin_DeleteInstance_FullMethodName                    = "/secure.v1.Admin/DeleteInstance"
+	Admin_DeleteService_FullMethodName                     = "/secure.v1.Admin/DeleteService"
+	Admin_DeleteAccount_FullMethodName                     = "/secure.v1.Admin/DeleteAccount"
+	Admin_RestoreRole_FullMethodName                       = "/secure.v1.Admin/RestoreRole"
+	Admin_RestoreGroup_FullMethodName                      = "/secure.v1.Admin/RestoreGroup"
+	Admin_RestorePermission_FullMethodName                 = "/secure.v1.Admin/RestorePermission"
+	Admin_RestoreInstance_FullMethodName                   = "/secure.v1.Admin/RestoreInstance"
+	Admin_RestoreService_FullMethodName                    = "/secure.v1.Admin/RestoreService"
+	Admin_RestoreAccount_FullMethodName                    = "/secure.v1.Admin/RestoreAccount"
+	Admin_CreateGlobalGroup_FullMethodName                 = "/secure.v1.Admin/CreateGlobalGroup"
+	Admin_AssignRoleToGlobalGroup_FullMethodName           = "/secure.v1.Admin/AssignRoleToGlobalGroup"
+	Admin_AssignGlobalGroupToAccount_FullMethodName        = "/secure.v1.Admin/AssignGlobalGroupToAccount"
+	Admin_DeleteGlobalGroup_FullMethodName                 = "/secure.v1.Admin/DeleteGlobalGroup"
+	Admin_SetInstanceAttribute_FullMethodName              = "/secure.v1.Admin/SetInstanceAttribute"
+	Admin_DeleteInstanceAttribute_FullMethodName           = "/secure.v1.Admin/DeleteInstanceAttribute"
+	Admin_SetPermissionEncoding_FullMethodName             = "/secure.v1.Admin/SetPermissionEncoding"
+)
+
+// AdminClient is the client API for Admin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AdminClient interface {
+	CreatePermission(ctx context.Context, in *CreatePermissionRequest, opts ...grpc.CallOption) (*Empty, error)
+	CreateRole(ctx context.Context, in *NameServiceDescription, opts ...grpc.CallOption) (*Empty, error)
+	CreateGroup(ctx context.Context, in *NameServiceDescription, opts ...grpc.CallOption) (*Empty, error)
+	SetAccountMetadata(ctx context.Context, in *AccountMetadata, opts ...grpc.CallOption) (*Empty, error)
+	AssignRoleToAccount(ctx context.Context, in *AccountRole, opts ...grpc.CallOption) (*Empty, error)
+	AssignGroupToAccount(ctx context.Context, in *AccountGroup, opts ...grpc.CallOption) (*Empty, error)
+	AssignInstancePermissionToAccount(ctx context.Context, in *AccountInstancePermission, opts ...grpc.CallOption) (*Empty, error)
+	AssignInstanceRoleToAccount(ctx context.Context, in *AccountInstanceRole, opts ...grpc.CallOption) (*Empty, error)
+	AssignInstanceGroupToAccount(ctx context.Context, in *AccountInstanceGroup, opts ...grpc.CallOption) (*Empty, error)
+	AssignSelectorPermissionToAccount(ctx context.Context, in *AccountSelectorPermission, opts ...grpc.CallOption) (*Empty, error)
+	AssignRoleToGroup(ctx context.Context, in *GroupRole, opts ...grpc.CallOption) (*Empty, error)
+	AssignPermissionToRole(ctx context.Context, in *RolePermission, opts ...grpc.CallOption) (*Empty, error)
+	AssignParentToRole(ctx context.Context, in *RoleParent, opts ...grpc.CallOption) (*Empty, error)
+	AssignPermissionToGroup(ctx context.Context, in *GroupPermission, opts ...grpc.CallOption) (*Empty, error)
+	AssignSubgroupToGroup(ctx context.Context, in *GroupSubgroup, opts ...grpc.CallOption) (*Empty, error)
+	DeleteRole(ctx context.Context, in *NameService, opts ...grpc.CallOption) (*Empty, error)
+	DeleteGroup(ctx context.Context, in *NameService, opts ...grpc.CallOption) (*Empty, error)
+	DeletePermission(ctx context.Context, in *NameService, opts ...grpc.CallOption) (*Empty, error)
+	DeprecatePermission(ctx context.Context, in *NameService, opts ...grpc.CallOption) (*Empty, error)
+	DeleteInstance(ctx context.Context, in *Name, opts ...grpc.CallOption) (*Empty, error)
+	DeleteService(ctx context.Context, in *Name, opts ...grpc.CallOption) (*Empty, error)
+	DeleteAccount(ctx context.Context, in *AccountId, opts ...grpc.CallOption) (*Empty, error)
+	RestoreRole(ctx context.Context, in *NameService, opts ...grpc.CallOption) (*Empty, error)
+	RestoreGroup(ctx context.Context, in *NameService, opts ...grpc.CallOption) (*Empty, error)
+	RestorePermission(ctx context.Context, in *NameService, opts ...grpc.CallOption) (*Empty, error)
+	RestoreInstance(ctx context.Context, in *Name, opts ...grpc.CallOption) (*Empty, error)
+	RestoreService(ctx context.Context, in *Name, opts ...grpc.CallOption) (*Empty, error)
+	RestoreAccount(ctx context.Context, in *AccountId, opts ...grpc.CallOption) (*Empty, error)
+	CreateGlobalGroup(ctx context.Context, in *NameDescription, opts ...grpc.CallOption) (*Empty, error)
+	AssignRoleToGlobalGroup(ctx context.Context, in *GlobalGroupRole, opts ...grpc.CallOption) (*Empty, error)
+	AssignGlobalGroupToAccount(ctx context.Context, in *AccountGlobalGroup, opts ...grpc.CallOption) (*Empty, error)
+	DeleteGlobalGroup(ctx context.Context, in *Name, opts ...grpc.CallOption) (*Empty, error)
+	SetInstanceAttribute(ctx context.Context, in *InstanceAttribute, opts ...grpc.CallOption) (*Empty, error)
+	DeleteInstanceAttribute(ctx context.Context, in *InstanceAttribute, opts ...grpc.CallOption) (*Empty, error)
+	SetPermissionEncoding(ctx context.Context, in *ServicePermissionEncoding, opts ...grpc.CallOption) (*Empty, error)
+}
+
+type adminClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAdminClient(cc grpc.ClientConnInterface) AdminClient {
+	return &adminClient{cc}
+}
+
+func (c *adminClient) CreatePermission(ctx context.Context, in *CreatePermissionRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, Admin_CreatePermission_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) CreateRole(ctx context.Context, in *NameServiceDescription, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, Admin_CreateRole_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) CreateGroup(ctx context.Context, in *NameServiceDescription, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, Admin_CreateGroup_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) SetAccountMetadata(ctx context.Context, in *AccountMetadata, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, Admin_SetAccountMetadata_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) AssignRoleToAccount(ctx context.Context, in *AccountRole, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, Admin_AssignRoleToAccount_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) AssignGroupToAccount(ctx context.Context, in *AccountGroup, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, Admin_AssignGroupToAccount_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) AssignInstancePermissionToAccount(ctx context.Context, in *AccountInstancePermission, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, Admin_AssignInstancePermissionToAccount_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) AssignInstanceRoleToAccount(ctx context.Context, in *AccountInstanceRole, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, Admin_AssignInstanceRoleToAccount_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) AssignInstanceGroupToAccount(ctx context.Context, in *AccountInstanceGroup, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, Admin_AssignInstanceGroupToAccount_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) AssignSelectorPermissionToAccount(ctx context.Context, in *AccountSelectorPermission, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, Admin_AssignSelectorPermissionToAccount_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) AssignRoleToGroup(ctx context.Context, in *GroupRole, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, Admin_AssignRoleToGroup_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) AssignPermissionToRole(ctx context.Context, in *RolePermission, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, Admin_AssignPermissionToRole_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) AssignParentToRole(ctx context.Context, in *RoleParent, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, Admin_AssignParentToRole_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) AssignPermissionToGroup(ctx context.Context, in *GroupPermission, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, Admin_AssignPermissionToGroup_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) AssignSubgroupToGroup(ctx context.Context, in *GroupSubgroup, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, Admin_AssignSubgroupToGroup_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) DeleteRole(ctx context.Context, in *NameService, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, Admin_DeleteRole_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) DeleteGroup(ctx context.Context, in *NameService, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, Admin_DeleteGroup_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) DeletePermission(ctx context.Context, in *NameService, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, Admin_DeletePermission_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) DeprecatePermission(ctx context.Context, in *NameService, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, Admin_DeprecatePermission_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) DeleteInstance(ctx context.Context, in *Name, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, Admin_DeleteInstance_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) DeleteService(ctx context.Context, in *Name, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, Admin_DeleteService_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) DeleteAccount(ctx context.Context, in *AccountId, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, Admin_DeleteAccount_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) RestoreRole(ctx context.Context, in *NameService, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, Admin_RestoreRole_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) RestoreGroup(ctx context.Context, in *NameService, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, Admin_RestoreGroup_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) RestorePermission(ctx context.Context, in *NameService, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, Admin_RestorePermission_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) RestoreInstance(ctx context.Context, in *Name, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, Admin_RestoreInstance_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) RestoreService(ctx context.Context, in *Name, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, Admin_RestoreService_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) RestoreAccount(ctx context.Context, in *AccountId, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, Admin_RestoreAccount_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) CreateGlobalGroup(ctx context.Context, in *NameDescription, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, Admin_CreateGlobalGroup_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) AssignRoleToGlobalGroup(ctx context.Context, in *GlobalGroupRole, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, Admin_AssignRoleToGlobalGroup_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) AssignGlobalGroupToAccount(ctx context.Context, in *AccountGlobalGroup, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, Admin_AssignGlobalGroupToAccount_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) DeleteGlobalGroup(ctx context.Context, in *Name, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, Admin_DeleteGlobalGroup_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) SetInstanceAttribute(ctx context.Context, in *InstanceAttribute, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, Admin_SetInstanceAttribute_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) DeleteInstanceAttribute(ctx context.Context, in *InstanceAttribute, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, Admin_DeleteInstanceAttribute_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) SetPermissionEncoding(ctx context.Context, in *ServicePermissionEncoding, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, Admin_SetPermissionEncoding_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminServer is the server API for Admin service.
+// All implementations must embed UnimplementedAdminServer
+// for forward compatibility
+type AdminServer interface {
+	CreatePermission(context.Context, *CreatePermissionRequest) (*Empty, error)
+	CreateRole(context.Context, *NameServiceDescription) (*Empty, error)
+	CreateGroup(context.Context, *NameServiceDescription) (*Empty, error)
+	SetAccountMetadata(context.Context, *AccountMetadata) (*Empty, error)
+	AssignRoleToAccount(context.Context, *AccountRole) (*Empty, error)
+	AssignGroupToAccount(context.Context, *AccountGroup) (*Empty, error)
+	AssignInstancePermissionToAccount(context.Context, *AccountInstancePermission) (*Empty, error)
+	AssignInstanceRoleToAccount(context.Context, *AccountInstanceRole) (*Empty, error)
+	AssignInstanceGroupToAccount(context.Context, *AccountInstanceGroup) (*Empty, error)
+	AssignSelectorPermissionToAccount(context.Context, *AccountSelectorPermission) (*Empty, error)
+	AssignRoleToGroup(context.Context, *GroupRole) (*Empty, error)
+	AssignPermissionToRole(context.Context, *RolePermission) (*Empty, error)
+	AssignParentToRole(context.Context, *RoleParent) (*Empty, error)
+	AssignPermissionToGroup(context.Context, *GroupPermission) (*Empty, error)
+	AssignSubgroupToGroup(context.Context, *GroupSubgroup) (*Empty, error)
+	DeleteRole(context.Context, *NameService) (*Empty, error)
+	DeleteGroup(context.Context, *NameService) (*Empty, error)
+	DeletePermission(context.Context, *NameService) (*Empty, error)
+	DeprecatePermission(context.Context, *NameService) (*Empty, error)
+	DeleteInstance(context.Context, *Name) (*Empty, error)
+	DeleteService(context.Context, *Name) (*Empty, error)
+	DeleteAccount(context.Context, *AccountId) (*Empty, error)
+	RestoreRole(context.Context, *NameService) (*Empty, error)
+	RestoreGroup(context.Context, *NameService) (*Empty, error)
+	RestorePermission(context.Context, *NameService) (*Empty, error)
+	RestoreInstance(context.Context, *Name) (*Empty, error)
+	RestoreService(context.Context, *Name) (*Empty, error)
+	RestoreAccount(context.Context, *AccountId) (*Empty, error)
+	CreateGlobalGroup(context.Context, *NameDescription) (*Empty, error)
+	AssignRoleToGlobalGroup(context.Context, *GlobalGroupRole) (*Empty, error)
+	AssignGlobalGroupToAccount(context.Context, *AccountGlobalGroup) (*Empty, error)
+	DeleteGlobalGroup(context.Context, *Name) (*Empty, error)
+	SetInstanceAttribute(context.Context, *InstanceAttribute) (*Empty, error)
+	DeleteInstanceAttribute(context.Context, *InstanceAttribute) (*Empty, error)
+	SetPermissionEncoding(context.Context, *ServicePermissionEncoding) (*Empty, error)
+	mustEmbedUnimplementedAdminServer()
+}
+
+// UnimplementedAdminServer must be embedded to have forward compatible implementations.
+type UnimplementedAdminServer struct {
+}
+
+func (UnimplementedAdminServer) CreatePermission(context.Context, *CreatePermissionRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreatePermission not implemented")
+}
+func (UnimplementedAdminServer) CreateRole(context.Context, *NameServiceDescription) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateRole not implemented")
+}
+func (UnimplementedAdminServer) CreateGroup(context.Context, *NameServiceDescription) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateGroup not implemented")
+}
+func (UnimplementedAdminServer) SetAccountMetadata(context.Context, *AccountMetadata) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetAccountMetadata not implemented")
+}
+func (UnimplementedAdminServer) AssignRoleToAccount(context.Context, *AccountRole) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AssignRoleToAccount not implemented")
+}
+func (UnimplementedAdminServer) AssignGroupToAccount(context.Context, *AccountGroup) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AssignGroupToAccount not implemented")
+}
+func (UnimplementedAdminServer) AssignInstancePermissionToAccount(context.Context, *AccountInstancePermission) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AssignInstancePermissionToAccount not implemented")
+}
+func (UnimplementedAdminServer) AssignInstanceRoleToAccount(context.Context, *AccountInstanceRole) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AssignInstanceRoleToAccount not implemented")
+}
+func (UnimplementedAdminServer) AssignInstanceGroupToAccount(context.Context, *AccountInstanceGroup) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AssignInstanceGroupToAccount not implemented")
+}
+func (UnimplementedAdminServer) AssignSelectorPermissionToAccount(context.Context, *AccountSelectorPermission) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AssignSelectorPermissionToAccount not implemented")
+}
+func (UnimplementedAdminServer) AssignRoleToGroup(context.Context, *GroupRole) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AssignRoleToGroup not implemented")
+}
+func (UnimplementedAdminServer) AssignPermissionToRole(context.Context, *RolePermission) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AssignPermissionToRole not implemented")
+}
+func (UnimplementedAdminServer) AssignParentToRole(context.Context, *RoleParent) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AssignParentToRole not implemented")
+}
+func (UnimplementedAdminServer) AssignPermissionToGroup(context.Context, *GroupPermission) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AssignPermissionToGroup not implemented")
+}
+func (UnimplementedAdminServer) AssignSubgroupToGroup(context.Context, *GroupSubgroup) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AssignSubgroupToGroup not implemented")
+}
+func (UnimplementedAdminServer) DeleteRole(context.Context, *NameService) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteRole not implemented")
+}
+func (UnimplementedAdminServer) DeleteGroup(context.Context, *NameService) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteGroup not implemented")
+}
+func (UnimplementedAdminServer) DeletePermission(context.Context, *NameService) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeletePermission not implemented")
+}
+func (UnimplementedAdminServer) DeprecatePermission(context.Context, *NameService) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeprecatePermission not implemented")
+}
+func (UnimplementedAdminServer) DeleteInstance(context.Context, *Name) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteInstance not implemented")
+}
+func (UnimplementedAdminServer) DeleteService(context.Context, *Name) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteService not implemented")
+}
+func (UnimplementedAdminServer) DeleteAccount(context.Context, *AccountId) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteAccount not implemented")
+}
+func (UnimplementedAdminServer) RestoreRole(context.Context, *NameService) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreRole not implemented")
+}
+func (UnimplementedAdminServer) RestoreGroup(context.Context, *NameService) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreGroup not implemented")
+}
+func (UnimplementedAdminServer) RestorePermission(context.Context, *NameService) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestorePermission not implemented")
+}
+func (UnimplementedAdminServer) RestoreInstance(context.Context, *Name) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreInstance not implemented")
+}
+func (UnimplementedAdminServer) RestoreService(context.Context, *Name) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreService not implemented")
+}
+func (UnimplementedAdminServer) RestoreAccount(context.Context, *AccountId) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreAccount not implemented")
+}
+func (UnimplementedAdminServer) CreateGlobalGroup(context.Context, *NameDescription) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateGlobalGroup not implemented")
+}
+func (UnimplementedAdminServer) AssignRoleToGlobalGroup(context.Context, *GlobalGroupRole) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AssignRoleToGlobalGroup not implemented")
+}
+func (UnimplementedAdminServer) AssignGlobalGroupToAccount(context.Context, *AccountGlobalGroup) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AssignGlobalGroupToAccount not implemented")
+}
+func (UnimplementedAdminServer) DeleteGlobalGroup(context.Context, *Name) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteGlobalGroup not implemented")
+}
+func (UnimplementedAdminServer) SetInstanceAttribute(context.Context, *InstanceAttribute) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetInstanceAttribute not implemented")
+}
+func (UnimplementedAdminServer) DeleteInstanceAttribute(context.Context, *InstanceAttribute) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteInstanceAttribute not implemented")
+}
+func (UnimplementedAdminServer) SetPermissionEncoding(context.Context, *ServicePermissionEncoding) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetPermissionEncoding not implemented")
+}
+func (UnimplementedAdminServer) mustEmbedUnimplementedAdminServer() {}
+
+// UnsafeAdminServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AdminServer will
+// result in compilation errors.
+type UnsafeAdminServer interface {
+	mustEmbedUnimplementedAdminServer()
+}
+
+func RegisterAdminServer(s grpc.ServiceRegistrar, srv AdminServer) {
+	s.RegisterService(&Admin_ServiceDesc, srv)
+}
+
+func _Admin_CreatePermission_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreatePermissionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).CreatePermission(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_CreatePermission_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).CreatePermission(ctx, req.(*CreatePermissionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_CreateRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NameServiceDescription)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).CreateRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_CreateRole_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).CreateRole(ctx, req.(*NameServiceDescription))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_CreateGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NameServiceDescription)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).CreateGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_CreateGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).CreateGroup(ctx, req.(*NameServiceDescription))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_SetAccountMetadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AccountMetadata)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).SetAccountMetadata(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_SetAccountMetadata_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).SetAccountMetadata(ctx, req.(*AccountMetadata))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_AssignRoleToAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AccountRole)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).AssignRoleToAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_AssignRoleToAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).AssignRoleToAccount(ctx, req.(*AccountRole))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_AssignGroupToAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AccountGroup)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).AssignGroupToAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_AssignGroupToAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).AssignGroupToAccount(ctx, req.(*AccountGroup))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_AssignInstancePermissionToAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AccountInstancePermission)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).AssignInstancePermissionToAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_AssignInstancePermissionToAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).AssignInstancePermissionToAccount(ctx, req.(*AccountInstancePermission))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_AssignInstanceRoleToAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AccountInstanceRole)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).AssignInstanceRoleToAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_AssignInstanceRoleToAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).AssignInstanceRoleToAccount(ctx, req.(*AccountInstanceRole))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_AssignInstanceGroupToAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AccountInstanceGroup)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).AssignInstanceGroupToAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_AssignInstanceGroupToAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).AssignInstanceGroupToAccount(ctx, req.(*AccountInstanceGroup))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_AssignSelectorPermissionToAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AccountSelectorPermission)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).AssignSelectorPermissionToAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_AssignSelectorPermissionToAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).AssignSelectorPermissionToAccount(ctx, req.(*AccountSelectorPermission))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_AssignRoleToGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GroupRole)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).AssignRoleToGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_AssignRoleToGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).AssignRoleToGroup(ctx, req.(*GroupRole))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_AssignPermissionToRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RolePermission)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).AssignPermissionToRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_AssignPermissionToRole_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).AssignPermissionToRole(ctx, req.(*RolePermission))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_AssignParentToRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RoleParent)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).AssignParentToRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_AssignParentToRole_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).AssignParentToRole(ctx, req.(*RoleParent))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_AssignPermissionToGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GroupPermission)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).AssignPermissionToGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_AssignPermissionToGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).AssignPermissionToGroup(ctx, req.(*GroupPermission))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_AssignSubgroupToGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GroupSubgroup)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).AssignSubgroupToGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_AssignSubgroupToGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).AssignSubgroupToGroup(ctx, req.(*GroupSubgroup))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_DeleteRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NameService)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).DeleteRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_DeleteRole_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).DeleteRole(ctx, req.(*NameService))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_DeleteGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NameService)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).DeleteGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_DeleteGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).DeleteGroup(ctx, req.(*NameService))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_DeletePermission_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NameService)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).DeletePermission(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_DeletePermission_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).DeletePermission(ctx, req.(*NameService))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_DeprecatePermission_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NameService)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).DeprecatePermission(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_DeprecatePermission_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).DeprecatePermission(ctx, req.(*NameService))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_DeleteInstance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Name)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).DeleteInstance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_DeleteInstance_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).DeleteInstance(ctx, req.(*Name))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_DeleteService_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Name)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).DeleteService(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_DeleteService_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).DeleteService(ctx, req.(*Name))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_DeleteAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AccountId)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).DeleteAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_DeleteAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).DeleteAccount(ctx, req.(*AccountId))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_RestoreRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NameService)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).RestoreRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_RestoreRole_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).RestoreRole(ctx, req.(*NameService))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_RestoreGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NameService)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).RestoreGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_RestoreGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).RestoreGroup(ctx, req.(*NameService))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_RestorePermission_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NameService)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).RestorePermission(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_RestorePermission_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).RestorePermission(ctx, req.(*NameService))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_RestoreInstance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Name)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).RestoreInstance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_RestoreInstance_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).RestoreInstance(ctx, req.(*Name))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_RestoreService_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Name)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).RestoreService(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_RestoreService_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).RestoreService(ctx, req.(*Name))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_RestoreAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AccountId)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).RestoreAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_RestoreAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).RestoreAccount(ctx, req.(*AccountId))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_CreateGlobalGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NameDescription)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).CreateGlobalGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_CreateGlobalGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).CreateGlobalGroup(ctx, req.(*NameDescription))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_AssignRoleToGlobalGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GlobalGroupRole)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).AssignRoleToGlobalGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_AssignRoleToGlobalGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).AssignRoleToGlobalGroup(ctx, req.(*GlobalGroupRole))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_AssignGlobalGroupToAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AccountGlobalGroup)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).AssignGlobalGroupToAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_AssignGlobalGroupToAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).AssignGlobalGroupToAccount(ctx, req.(*AccountGlobalGroup))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_DeleteGlobalGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Name)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).DeleteGlobalGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_DeleteGlobalGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).DeleteGlobalGroup(ctx, req.(*Name))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_SetInstanceAttribute_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InstanceAttribute)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).SetInstanceAttribute(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_SetInstanceAttribute_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).SetInstanceAttribute(ctx, req.(*InstanceAttribute))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_DeleteInstanceAttribute_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InstanceAttribute)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).DeleteInstanceAttribute(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_DeleteInstanceAttribute_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).DeleteInstanceAttribute(ctx, req.(*InstanceAttribute))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_SetPermissionEncoding_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ServicePermissionEncoding)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).SetPermissionEncoding(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_SetPermissionEncoding_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).SetPermissionEncoding(ctx, req.(*ServicePermissionEncoding))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Admin_ServiceDesc is the grpc.ServiceDesc for Admin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Admin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "secure.v1.Admin",
+	HandlerType: (*AdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreatePermission",
+			Handler:    _Admin_CreatePermission_Handler,
+		},
+		{
+			MethodName: "CreateRole",
+			Handler:    _Admin_CreateRole_Handler,
+		},
+		{
+			MethodName: "CreateGroup",
+			Handler:    _Admin_CreateGroup_Handler,
+		},
+		{
+			MethodName: "SetAccountMetadata",
+			Handler:    _Admin_SetAccountMetadata_Handler,
+		},
+		{
+			MethodName: "AssignRoleToAccount",
+			Handler:    _Admin_AssignRoleToAccount_Handler,
+		},
+		{
+			MethodName: "AssignGroupToAccount",
+			Handler:    _Admin_AssignGroupToAccount_Handler,
+		},
+		{
+			MethodName: "AssignInstancePermissionToAccount",
+			Handler:    _Admin_AssignInstancePermissionToAccount_Handler,
+		},
+		{
+			MethodName: "AssignInstanceRoleToAccount",
+			Handler:    _Admin_AssignInstanceRoleToAccount_Handler,
+		},
+		{
+			MethodName: "AssignInstanceGroupToAccount",
+			Handler:    _Admin_AssignInstanceGroupToAccount_Handler,
+		},
+		{
+			MethodName: "AssignSelectorPermissionToAccount",
+			Handler:    _Admin_AssignSelectorPermissionToAccount_Handler,
+		},
+		{
+			MethodName: "AssignRoleToGroup",
+			Handler:    _Admin_AssignRoleToGroup_Handler,
+		},
+		{
+			MethodName: "AssignPermissionToRole",
+			Handler:    _Admin_AssignPermissionToRole_Handler,
+		},
+		{
+			MethodName: "AssignParentToRole",
+			Handler:    _Admin_AssignParentToRole_Handler,
+		},
+		{
+			MethodName: "AssignPermissionToGroup",
+			Handler:    _Admin_AssignPermissionToGroup_Handler,
+		},
+		{
+			MethodName: "AssignSubgroupToGroup",
+			Handler:    _Admin_AssignSubgroupToGroup_Handler,
+		},
+		{
+			MethodName: "DeleteRole",
+			Handler:    _Admin_DeleteRole_Handler,
+		},
+		{
+			MethodName: "DeleteGroup",
+			Handler:    _Admin_DeleteGroup_Handler,
+		},
+		{
+			MethodName: "DeletePermission",
+			Handler:    _Admin_DeletePermission_Handler,
+		},
+		{
+			MethodName: "DeprecatePermission",
+			Handler:    _Admin_DeprecatePermission_Handler,
+		},
+		{
+			MethodName: "DeleteInstance",
+			Handler:    _Admin_DeleteInstance_Handler,
+		},
+		{
+			MethodName: "DeleteService",
+			Handler:    _Admin_DeleteService_Handler,
+		},
+		{
+			MethodName: "DeleteAccount",
+			Handler:    _Admin_DeleteAccount_Handler,
+		},
+		{
+			MethodName: "RestoreRole",
+			Handler:    _Admin_RestoreRole_Handler,
+		},
+		{
+			MethodName: "RestoreGroup",
+			Handler:    _Admin_RestoreGroup_Handler,
+		},
+		{
+			MethodName: "RestorePermission",
+			Handler:    _Admin_RestorePermission_Handler,
+		},
+		{
+			MethodName: "RestoreInstance",
+			Handler:    _Admin_RestoreInstance_Handler,
+		},
+		{
+			MethodName: "RestoreService",
+			Handler:    _Admin_RestoreService_Handler,
+		},
+		{
+			MethodName: "RestoreAccount",
+			Handler:    _Admin_RestoreAccount_Handler,
+		},
+		{
+			MethodName: "CreateGlobalGroup",
+			Handler:    _Admin_CreateGlobalGroup_Handler,
+		},
+		{
+			MethodName: "AssignRoleToGlobalGroup",
+			Handler:    _Admin_AssignRoleToGlobalGroup_Handler,
+		},
+		{
+			MethodName: "AssignGlobalGroupToAccount",
+			Handler:    _Admin_AssignGlobalGroupToAccount_Handler,
+		},
+		{
+			MethodName: "DeleteGlobalGroup",
+			Handler:    _Admin_DeleteGlobalGroup_Handler,
+		},
+		{
+			MethodName: "SetInstanceAttribute",
+			Handler:    _Admin_SetInstanceAttribute_Handler,
+		},
+		{
+			MethodName: "DeleteInstanceAttribute",
+			Handler:    _Admin_DeleteInstanceAttribute_Handler,
+		},
+		{
+			MethodName: "SetPermissionEncoding",
+			Handler:    _Admin_SetPermissionEncoding_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "secure.proto",
+}
@@ -0,0 +1,192 @@
+/*
+Package tokenperm: пакет для кодирования и разбора номеров разрешений в JWT-токенах, выдаваемых приложением secure.
+Номера разрешений передаются в утверждении perm, а способ их кодирования - в утверждении perm_enc:
+
+  - EncodingList (1): perm содержит JSON-массив номеров. Токены без perm_enc кодируются так же;
+  - EncodingBitmap (2): perm содержит битовую карту в кодировке base64url без выравнивания, в которой установленный бит
+    N означает наличие разрешения с номером N. Бит N находится в байте N/8, младший бит байта соответствует наименьшему
+    номеру.
+
+Сервисы-потребители могут разбирать утверждения токена функцией FromClaims, не заботясь о способе кодирования.
+*/
+package tokenperm
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/bits"
+	"sort"
+)
+
+const (
+	ClaimPermissions = "perm"     // Утверждение с номерами разрешений
+	ClaimEncoding    = "perm_enc" // Утверждение с версией кодирования номеров разрешений
+)
+
+// Версии кодирования номеров разрешений.
+const (
+	EncodingList   = 1 // JSON-массив номеров
+	EncodingBitmap = 2 // Битовая карта в кодировке base64url
+)
+
+// MaxBitmapNumber наибольший номер разрешения, который можно закодировать битовой картой (карта не превышает 1 КиБ).
+const MaxBitmapNumber = 8191
+
+var (
+	ErrUnknownEncoding    = errors.New("unknown permissions encoding")
+	ErrInvalidPermissions = errors.New("invalid permissions claim")
+)
+
+var encoding = base64.RawURLEncoding
+
+// FitsBitmap возвращает true, если все переданные номера разрешений можно закодировать битовой картой.
+func FitsBitmap(numbers []int) bool {
+	for _, number := range numbers {
+		if number > MaxBitmapNumber {
+			return false
+		}
+	}
+
+	return true
+}
+
+// EncodeBitmap возвращает битовую карту переданных номеров разрешений в кодировке base64url без выравнивания.
+// Отрицательные номера и номера больше MaxBitmapNumber пропускаются, поэтому перед кодированием следует проверить
+// номера функцией FitsBitmap.
+func EncodeBitmap(numbers []int) string {
+	maxNumber := -1
+	for _, number := range numbers {
+		if number <= MaxBitmapNumber {
+			maxNumber = max(maxNumber, number)
+		}
+	}
+
+	bitmap := make([]byte, maxNumber/8+1)
+	if maxNumber < 0 {
+		bitmap = bitmap[:0]
+	}
+
+	for _, number := range numbers {
+		if number >= 0 && number <= MaxBitmapNumber {
+			bitmap[number/8] |= 1 << (number % 8)
+		}
+	}
+
+	return encoding.EncodeToString(bitmap)
+}
+
+// DecodeBitmap возвращает отсортированные по возрастанию номера разрешений из битовой карты в кодировке base64url без
+// выравнивания.
+func DecodeBitmap(encoded string) ([]int, error) {
+	bitmap, err := encoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidPermissions
+	}
+
+	result := make([]int, 0)
+	for i, b := range bitmap {
+		for b != 0 {
+			bit := bits.TrailingZeros8(b)
+			result = append(result, i*8+bit)
+			b &^= 1 << bit
+		}
+	}
+
+	return result, nil
+}
+
+// HasInBitmap возвращает true, если в битовой карте в кодировке base64url без выравнивания есть разрешение с номером
+// number.
+func HasInBitmap(encoded string, number int) (bool, error) {
+	bitmap, err := encoding.DecodeString(encoded)
+	if err != nil {
+		return false, ErrInvalidPermissions
+	}
+
+	if number < 0 || number/8 >= len(bitmap) {
+		return false, nil
+	}
+
+	return bitmap[number/8]&(1<<(number%8)) != 0, nil
+}
+
+// FromClaims возвращает отсортированные по возрастанию номера разрешений из утверждений токена, разобранных в
+// map[string]any (например, jwt.MapClaims). Поддерживаются обе версии кодирования; отсутствие утверждения perm
+// означает отсутствие разрешений.
+func FromClaims(claims map[string]any) ([]int, error) {
+	version, err := encodingVersion(claims[ClaimEncoding])
+	if err != nil {
+		return nil, err
+	}
+
+	value, ok := claims[ClaimPermissions]
+	if !ok || value == nil {
+		return []int{}, nil
+	}
+
+	switch version {
+	case EncodingList:
+		return listNumbers(value)
+	case EncodingBitmap:
+		encoded, isString := value.(string)
+		if !isString {
+			return nil, ErrInvalidPermissions
+		}
+		return DecodeBitmap(encoded)
+	}
+
+	return nil, ErrUnknownEncoding
+}
+
+// encodingVersion возвращает версию кодирования из значения утверждения perm_enc.
+func encodingVersion(value any) (int, error) {
+	if value == nil {
+		return EncodingList, nil
+	}
+
+	version, ok := number(value)
+	if !ok {
+		return 0, ErrUnknownEncoding
+	}
+
+	return version, nil
+}
+
+// listNumbers возвращает отсортированные номера разрешений из JSON-массива.
+func listNumbers(value any) ([]int, error) {
+	items, ok := value.([]any)
+	if !ok {
+		return nil, ErrInvalidPermissions
+	}
+
+	result := make([]int, 0, len(items))
+	for _, item := range items {
+		n, isNumber := number(item)
+		if !isNumber {
+			return nil, ErrInvalidPermissions
+		}
+		result = append(result, n)
+	}
+
+	sort.Ints(result)
+
+	return result, nil
+}
+
+// number приводит разобранное из JSON число к int.
+func number(value any) (int, bool) {
+	switch v := value.(type) {
+	case float64:
+		return int(v), v == float64(int(v))
+	case int:
+		return v, true
+	case int64:
+		return int(v), true
+	case json.Number:
+		n, err := v.Int64()
+		return int(n), err == nil
+	}
+
+	return 0, false
+}
@@ -0,0 +1,92 @@
+package tokenperm
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestBitmapRoundTrip(t *testing.T) {
+	numbers := []int{0, 1, 7, 8, 15, 64, 129}
+	encoded := EncodeBitmap([]int{129, 7, 0, 64, 1, 15, 8, -3, 7})
+
+	decoded, err := DecodeBitmap(encoded)
+	if err != nil || !reflect.DeepEqual(decoded, numbers) {
+		t.Errorf("expected %v, got %v (%v)", numbers, decoded, err)
+	}
+
+	for _, number := range []int{0, 64, 129} {
+		if ok, _ := HasInBitmap(encoded, number); !ok {
+			t.Errorf("bitmap must contain %d", number)
+		}
+	}
+
+	for _, number := range []int{2, 130, 1000, -1} {
+		if ok, _ := HasInBitmap(encoded, number); ok {
+			t.Errorf("bitmap must not contain %d", number)
+		}
+	}
+}
+
+func TestEncodeBitmap(t *testing.T) {
+	if encoded := EncodeBitmap(nil); encoded != "" {
+		t.Errorf("expected empty bitmap, got %q", encoded)
+	}
+
+	// Биты 0 и 2 первого байта, бит 1 второго: 0b00000101, 0b00000010.
+	if encoded := EncodeBitmap([]int{0, 2, 9}); encoded != "BQI" {
+		t.Errorf("expected BQI, got %q", encoded)
+	}
+
+	if encoded := EncodeBitmap([]int{0, 2, 9, MaxBitmapNumber + 1, 1 << 40}); encoded != "BQI" {
+		t.Errorf("expected numbers above MaxBitmapNumber to be skipped, got %q", encoded)
+	}
+}
+
+func TestFitsBitmap(t *testing.T) {
+	if !FitsBitmap([]int{0, 7, MaxBitmapNumber}) {
+		t.Error("expected numbers up to MaxBitmapNumber to fit")
+	}
+
+	if FitsBitmap([]int{1, MaxBitmapNumber + 1}) {
+		t.Error("expected number above MaxBitmapNumber not to fit")
+	}
+}
+
+func TestFromClaims(t *testing.T) {
+	var list, bitmap, legacy map[string]any
+	_ = json.Unmarshal([]byte(`{"perm":[5,1,3],"perm_enc":1}`), &list)
+	_ = json.Unmarshal([]byte(`{"perm":"`+EncodeBitmap([]int{1, 3, 5})+`","perm_enc":2}`), &bitmap)
+	_ = json.Unmarshal([]byte(`{"perm":[3,5,1]}`), &legacy)
+
+	expected := []int{1, 3, 5}
+	for name, claims := range map[string]map[string]any{"list": list, "bitmap": bitmap, "legacy": legacy} {
+		if numbers, err := FromClaims(claims); err != nil || !reflect.DeepEqual(numbers, expected) {
+			t.Errorf("%s: expected %v, got %v (%v)", name, expected, numbers, err)
+		}
+	}
+
+	if numbers, err := FromClaims(map[string]any{}); err != nil || len(numbers) != 0 {
+		t.Errorf("expected no permissions, got %v (%v)", numbers, err)
+	}
+}
+
+func TestFromClaimsErrors(t *testing.T) {
+	cases := map[string]struct {
+		claims map[string]any
+		err    error
+	}{
+		"unknown version": {map[string]any{"perm": []any{}, "perm_enc": float64(3)}, ErrUnknownEncoding},
+		"bad version":     {map[string]any{"perm": []any{}, "perm_enc": "2"}, ErrUnknownEncoding},
+		"list as string":  {map[string]any{"perm": "BQI", "perm_enc": float64(1)}, ErrInvalidPermissions},
+		"bitmap as list":  {map[string]any{"perm": []any{float64(1)}, "perm_enc": float64(2)}, ErrInvalidPermissions},
+		"bad bitmap":      {map[string]any{"perm": "@@", "perm_enc": float64(2)}, ErrInvalidPermissions},
+		"bad number":      {map[string]any{"perm": []any{1.5}}, ErrInvalidPermissions},
+	}
+
+	for name, c := range cases {
+		if _, err := FromClaims(c.claims); err != c.err {
+			t.Errorf("%s: expected %v, got %v", name, c.err, err)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+/*
+Package totp: пакет реализует генерацию и проверку одноразовых паролей, основанных на времени (RFC 6238). Используется
+алгоритм HMAC-SHA1, шаг времени 30 секунд и пароли из шести цифр, что совместимо с распространёнными приложениями-
+аутентификаторами.
+*/
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	Period     = 30 // Шаг времени в секундах
+	Digits     = 6  // Количество цифр в одноразовом пароле
+	secretSize = 20 // Длина секрета в байтах (рекомендуемая RFC 4226 для HMAC-SHA1)
+)
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret возвращает случайный секрет в кодировке base32 без выравнивания.
+func GenerateSecret() (string, error) {
+	b := make([]byte, secretSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return encoding.EncodeToString(b), nil
+}
+
+// Code возвращает одноразовый пароль для секрета в кодировке base32 на момент времени t.
+func Code(secret string, t time.Time) (string, error) {
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return "", err
+	}
+
+	return code(key, counter(t)), nil
+}
+
+// Validate возвращает номер шага времени, если переданный пароль соответствует секрету на момент времени t с
+// допустимым отклонением в skew шагов в обе стороны. Если пароль неверный, возвращает false.
+func Validate(secret, passcode string, t time.Time, skew int) (uint64, bool) {
+	if len(passcode) != Digits {
+		return 0, false
+	}
+
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return 0, false
+	}
+
+	current := counter(t)
+	for i := -skew; i <= skew; i++ {
+		step := current + uint64(i)
+		if subtle.ConstantTimeCompare([]byte(code(key, step)), []byte(passcode)) == 1 {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+// URI возвращает ссылку формата otpauth://, используемую приложениями-аутентификаторами (обычно в виде QR-кода).
+func URI(issuer, account, secret string) string {
+	label := url.PathEscape(issuer + ":" + account)
+	params := url.Values{}
+	params.Set("secret", secret)
+	params.Set("issuer", issuer)
+	params.Set("algorithm", "SHA1")
+	params.Set("digits", fmt.Sprint(Digits))
+	params.Set("period", fmt.Sprint(Period))
+
+	return fmt.Sprintf("otpauth://totp/%s?%s", label, params.Encode())
+}
+
+// counter возвращает номер шага времени для момента t.
+func counter(t time.Time) uint64 {
+	return uint64(t.Unix()) / Period
+}
+
+// code вычисляет одноразовый пароль по алгоритму HOTP (RFC 4226) для ключа и счетчика.
+func code(key []byte, counter uint64) string {
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, counter)
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < Digits; i++ {
+		mod *= 10
+	}
+
+	return fmt.Sprintf("%0*d", Digits, value%mod)
+}
+
+// decodeSecret декодирует секрет из base32, игнорируя регистр, пробелы и выравнивание.
+func decodeSecret(secret string) ([]byte, error) {
+	secret = strings.ToUpper(strings.ReplaceAll(secret, " ", ""))
+	return encoding.DecodeString(strings.TrimRight(secret, "="))
+}
@@ -0,0 +1,64 @@
+package totp
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret секрет из тестовых векторов RFC 6238 (приложение B) для HMAC-SHA1.
+var rfcSecret = base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+func TestCode(t *testing.T) {
+	// Восьмизначные значения из RFC 6238, обрезанные до шести последних цифр.
+	vectors := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+
+	for unix, expected := range vectors {
+		if code, err := Code(rfcSecret, time.Unix(unix, 0)); err != nil || code != expected {
+			t.Errorf("time %d: expected %s, got %s (%v)", unix, expected, code, err)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	code, _ := Code(rfcSecret, now.Add(-Period*time.Second))
+
+	if _, ok := Validate(rfcSecret, code, now, 1); !ok {
+		t.Error("code from previous step must be accepted with skew 1")
+	}
+
+	if _, ok := Validate(rfcSecret, code, now, 0); ok {
+		t.Error("code from previous step must be rejected with skew 0")
+	}
+
+	if _, ok := Validate(rfcSecret, "12345", now, 1); ok {
+		t.Error("code of wrong length must be rejected")
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil || len(secret) != 32 {
+		t.Fatal()
+	}
+
+	if _, err = Code(strings.ToLower(secret), time.Now()); err != nil {
+		t.Fail()
+	}
+}
+
+func TestURI(t *testing.T) {
+	uri := URI("secure", "store1", "JBSWY3DPEHPK3PXP")
+	if !strings.HasPrefix(uri, "otpauth://totp/secure:store1?") || !strings.Contains(uri, "secret=JBSWY3DPEHPK3PXP") {
+		t.Fail()
+	}
+}